@@ -0,0 +1,192 @@
+package ipp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// failoverTarget is one of the uris of a FailoverAdapter
+type failoverTarget struct {
+	// uri is the configured printer uri, base its scheme, host and port
+	uri     string
+	base    string
+	httpURL string
+	adapter *HttpAdapter
+}
+
+// FailoverAdapter sends requests to the first reachable of an ordered list of uris of the same printer, e.g. the ipps
+// uri followed by the ipp uri, or the uri with the hostname followed by the uri with the ip address. if the
+// connection to the current uri fails, the request is sent to the next uri, other errors are returned. the adapter
+// stays with a working uri, Reset returns to the first uri
+type FailoverAdapter struct {
+	targets []failoverTarget
+
+	mu      sync.Mutex
+	current int
+}
+
+// NewFailoverAdapter creates an adapter for the ipp, ipps, http or https uris, the port defaults to 631. the username
+// and password are sent with basic authentication to all uris
+func NewFailoverAdapter(username, password string, uris ...string) (*FailoverAdapter, error) {
+	if len(uris) == 0 {
+		return nil, errors.New("failover adapter requires an uri")
+	}
+
+	a := &FailoverAdapter{}
+	for _, uri := range uris {
+		adapter, u, err := uriAdapter(uri, username, password)
+		if err != nil {
+			return nil, err
+		}
+
+		a.targets = append(a.targets, failoverTarget{
+			uri:     uri,
+			base:    u.Scheme + "://" + u.Host,
+			httpURL: adapter.GetHttpUri("", nil),
+			adapter: adapter,
+		})
+	}
+
+	return a, nil
+}
+
+// uriAdapter creates a http adapter for the host and port of an ipp, ipps, http or https uri, the port defaults to 631
+func uriAdapter(uri, username, password string) (*HttpAdapter, *url.URL, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var useTLS bool
+	switch u.Scheme {
+	case "ipp", "http":
+	case "ipps", "https":
+		useTLS = true
+	default:
+		return nil, nil, fmt.Errorf("unsupported uri scheme %s", u.Scheme)
+	}
+
+	port := 631
+	if u.Port() != "" {
+		if port, err = strconv.Atoi(u.Port()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return NewHttpAdapter(u.Hostname(), port, username, password, useTLS), u, nil
+}
+
+// NewIPPClientWithFailover creates a client which fails over between the uris of a printer, see FailoverAdapter
+func NewIPPClientWithFailover(username, password string, uris ...string) (*IPPClient, error) {
+	adapter, err := NewFailoverAdapter(username, password, uris...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewIPPClientWithAdapter(username, adapter), nil
+}
+
+// Current returns the uri requests are sent to
+func (a *FailoverAdapter) Current() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.targets[a.current].uri
+}
+
+// Reset returns to the first uri, e.g. after the preferred ipps uri is reachable again
+func (a *FailoverAdapter) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.current = 0
+}
+
+// SendRequest sends the request to the current uri and fails over to the next uris on connection errors. the url
+// and the printer-uri and job-uri attributes are rewritten for the other uris if they start with the scheme, host
+// and port of the current uri
+func (a *FailoverAdapter) SendRequest(url string, req *Request, additionalResponseData io.Writer) (*Response, error) {
+	a.mu.Lock()
+	start := a.current
+	a.mu.Unlock()
+
+	from := a.targets[start]
+	if !strings.HasPrefix(url, from.httpURL) {
+		return from.adapter.SendRequest(url, req, additionalResponseData)
+	}
+
+	var err error
+	for i := range a.targets {
+		index := (start + i) % len(a.targets)
+		to := a.targets[index]
+
+		var resp *Response
+		resp, err = to.adapter.SendRequest(to.httpURL+strings.TrimPrefix(url, from.httpURL), failoverRequest(req, from, to),
+			additionalResponseData)
+		if !isConnectError(err) {
+			if index != start {
+				a.mu.Lock()
+				a.current = index
+				a.mu.Unlock()
+			}
+			return resp, err
+		}
+	}
+
+	return nil, err
+}
+
+// failoverRequest returns a copy of the request with the printer-uri and job-uri of the target
+func failoverRequest(req *Request, from, to failoverTarget) *Request {
+	if from.base == to.base {
+		return req
+	}
+
+	failover := *req
+	failover.OperationAttributes = make(map[string]interface{}, len(req.OperationAttributes))
+	for name, value := range req.OperationAttributes {
+		if uri, ok := value.(string); ok && (name == AttributePrinterURI || name == AttributeJobURI) &&
+			strings.HasPrefix(uri, from.base) {
+			value = to.base + strings.TrimPrefix(uri, from.base)
+		}
+		failover.OperationAttributes[name] = value
+	}
+
+	return &failover
+}
+
+// isConnectError reports whether the connection to the server could not be established, in this case no data of
+// the request was sent
+func isConnectError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// GetHttpUri returns the http url of the current uri
+func (a *FailoverAdapter) GetHttpUri(namespace string, object interface{}) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.targets[a.current].adapter.GetHttpUri(namespace, object)
+}
+
+// TestConnection connects to the uris in order and makes the first reachable uri the current uri
+func (a *FailoverAdapter) TestConnection() error {
+	var err error
+	for i, target := range a.targets {
+		if err = target.adapter.TestConnection(); err == nil {
+			a.mu.Lock()
+			a.current = i
+			a.mu.Unlock()
+			return nil
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,82 @@
+package ipp
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// closedAddress returns an address nothing listens on
+func closedAddress(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	l.Close()
+
+	return l.Addr().String()
+}
+
+func TestFailoverAdapter(t *testing.T) {
+	var printerURIs, documents []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data bytes.Buffer
+		req, err := NewRequestDecoder(r.Body).Decode(&data)
+		assert.Nil(t, err)
+		printerURIs = append(printerURIs, req.OperationAttributes[AttributePrinterURI].(string))
+		documents = append(documents, data.String())
+
+		resp := NewResponse(StatusOk, req.RequestId)
+		resp.JobAttributes = append(resp.JobAttributes, Attributes{
+			AttributeJobID: []Attribute{{Tag: TagInteger, Name: AttributeJobID, Value: 7}},
+		})
+		payload, _ := resp.Encode()
+		w.Header().Set("Content-Type", ContentTypeIPP)
+		w.Write(payload)
+	}))
+	defer ts.Close()
+
+	down := "ipp://" + closedAddress(t) + "/ipp/print"
+	up := "ipp://" + strings.TrimPrefix(ts.URL, "http://") + "/ipp/print"
+
+	adapter, err := NewFailoverAdapter("", "", down, up)
+	assert.Nil(t, err)
+	client := NewIPPClientWithAdapter("user", adapter)
+
+	req := NewRequest(OperationPrintJob, 1, WithPrinterURI(down),
+		WithDocument(strings.NewReader("document"), 8, MimeTypePostscript))
+	resp, err := client.SendRequest(adapter.GetHttpUri("ipp", "print"), req, nil)
+	assert.Nil(t, err)
+	assert.Len(t, resp.JobAttributes, 1)
+	assert.Equal(t, []string{up}, printerURIs)
+	assert.Equal(t, []string{"document"}, documents)
+	assert.Equal(t, up, adapter.Current())
+	assert.Equal(t, strings.TrimSuffix(ts.URL, "/")+"/printers/office", adapter.GetHttpUri("printers", "office"))
+
+	// the working uri is kept until Reset
+	_, err = client.GetPrinterAttributes("office", []string{AttributePrinterState})
+	assert.NotNil(t, err)
+	assert.Len(t, printerURIs, 2)
+	adapter.Reset()
+	assert.Equal(t, down, adapter.Current())
+
+	assert.Nil(t, adapter.TestConnection())
+	assert.Equal(t, up, adapter.Current())
+
+	// connection errors of all uris are returned
+	adapter, err = NewFailoverAdapter("", "", down)
+	assert.Nil(t, err)
+	_, err = NewIPPClientWithAdapter("user", adapter).GetPrinterAttributes("office", nil)
+	var opErr *net.OpError
+	assert.True(t, errors.As(err, &opErr))
+	assert.NotNil(t, adapter.TestConnection())
+
+	_, err = NewFailoverAdapter("", "", "lpd://printer/queue")
+	assert.NotNil(t, err)
+	_, err = NewIPPClientWithFailover("", "")
+	assert.NotNil(t, err)
+}
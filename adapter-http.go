@@ -1,13 +1,14 @@
 package ipp
 
 import (
-	"bytes"
+	"bufio"
 	"crypto/tls"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 type HttpAdapter struct {
@@ -17,6 +18,7 @@ type HttpAdapter struct {
 	password string
 	useTLS   bool
 	client   *http.Client
+	warn     func(Warning)
 }
 
 func NewHttpAdapter(host string, port int, username, password string, useTLS bool) *HttpAdapter {
@@ -38,27 +40,56 @@ func NewHttpAdapter(host string, port int, username, password string, useTLS boo
 	}
 }
 
+// TransportOptions tune the http connections of a HttpAdapter, zero values keep the defaults of net/http
+type TransportOptions struct {
+	// HTTP2 enables http/2 for tls connections, http/1.1 is used if the printer doesn't negotiate http/2 with alpn.
+	// connections without tls always use http/1.1
+	HTTP2 bool
+	// MaxIdleConns limits the idle connections to all hosts, zero means no limit
+	MaxIdleConns int
+	// MaxIdleConnsPerHost limits the idle connections per host, http.DefaultMaxIdleConnsPerHost if zero
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits the connections per host including the active ones, zero means no limit
+	MaxConnsPerHost int
+	// IdleConnTimeout closes connections which are idle for longer, zero keeps idle connections open
+	IdleConnTimeout time.Duration
+}
+
+// SetTransportOptions applies the options to the connections of the adapter, it should be called before the first
+// request
+func (h *HttpAdapter) SetTransportOptions(opts TransportOptions) {
+	transport := h.client.Transport.(*http.Transport)
+	transport.ForceAttemptHTTP2 = opts.HTTP2
+	transport.MaxIdleConns = opts.MaxIdleConns
+	transport.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	transport.MaxConnsPerHost = opts.MaxConnsPerHost
+	transport.IdleConnTimeout = opts.IdleConnTimeout
+}
+
+// CloseIdleConnections closes the idle connections of the adapter, e.g. after a printer of a large fleet was queried
+func (h *HttpAdapter) CloseIdleConnections() {
+	h.client.CloseIdleConnections()
+}
+
+// OnWarning sets a function which receives the deviations from the specification tolerated while decoding the
+// responses, e.g. to monitor the interop issues of printers
+func (h *HttpAdapter) OnWarning(fn func(Warning)) {
+	h.warn = fn
+}
+
 func (h *HttpAdapter) SendRequest(url string, req *Request, additionalResponseData io.Writer) (*Response, error) {
-	payload, err := req.Encode()
+	body, size, err := requestBody(req)
 	if err != nil {
 		return nil, err
 	}
 
-	size := len(payload)
-	var body io.Reader
-	if req.File != nil && req.FileSize != -1 {
-		size += req.FileSize
-		body = io.MultiReader(bytes.NewBuffer(payload), req.File)
-	} else {
-		body = bytes.NewBuffer(payload)
-	}
-
 	httpReq, err := http.NewRequest("POST", url, body)
 	if err != nil {
 		return nil, err
 	}
 
-	httpReq.Header.Set("Content-Length", strconv.Itoa(size))
+	// documents of unknown size are sent with chunked transfer encoding
+	httpReq.ContentLength = size
 	httpReq.Header.Set("Content-Type", ContentTypeIPP)
 
 	if h.username != "" && h.password != "" {
@@ -67,7 +98,7 @@ func (h *HttpAdapter) SendRequest(url string, req *Request, additionalResponseDa
 
 	httpResp, err := h.client.Do(httpReq)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("unable to perform HTTP request: %w", err)
 	}
 	defer httpResp.Body.Close()
 
@@ -77,22 +108,17 @@ func (h *HttpAdapter) SendRequest(url string, req *Request, additionalResponseDa
 		}
 	}
 
-	// buffer response to avoid read issues
-	buf := new(bytes.Buffer)
-	if httpResp.ContentLength > 0 {
-		buf.Grow(int(httpResp.ContentLength))
-	}
-	if _, err := io.Copy(buf, httpResp.Body); err != nil {
-		return nil, fmt.Errorf("unable to buffer response: %w", err)
-	}
-
-	ippResp, err := NewResponseDecoder(buf).Decode(additionalResponseData)
+	// the response is decoded while it is received, additional data like the document of Get-Document is streamed
+	decoder := NewResponseDecoder(bufio.NewReader(httpResp.Body))
+	decoder.InternValues = true
+	decoder.OnWarning = h.warn
+	ippResp, err := decoder.Decode(additionalResponseData)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("unable to decode IPP response: %w", err)
 	}
 
 	if err = ippResp.CheckForErrors(); err != nil {
-		return nil, fmt.Errorf("received error IPP response: %w", err)
+		return ippResp, fmt.Errorf("received error IPP response: %w", err)
 	}
 
 	return ippResp, nil
@@ -118,7 +144,7 @@ func (h *HttpAdapter) GetHttpUri(namespace string, object interface{}) string {
 }
 
 func (h *HttpAdapter) TestConnection() error {
-	conn, err := net.Dial("tcp", fmt.Sprintf("%s:%d", h.host, h.port))
+	conn, err := net.Dial("tcp", net.JoinHostPort(h.host, strconv.Itoa(h.port)))
 	if err != nil {
 		return err
 	}
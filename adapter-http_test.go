@@ -0,0 +1,130 @@
+package ipp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// zeroReader returns an endless stream of zeros
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestHttpAdapter_SendRequest(t *testing.T) {
+	var (
+		contentLength int64
+		received      int64
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentLength = r.ContentLength
+		req, err := NewRequestDecoder(r.Body).Decode(nil)
+		assert.Nil(t, err)
+		received, err = io.Copy(io.Discard, r.Body)
+		assert.Nil(t, err)
+
+		payload, _ := NewResponse(StatusOk, req.RequestId).Encode()
+		w.Header().Set("Content-Type", ContentTypeIPP)
+		w.Write(payload)
+		// additional data like the document of Get-Document
+		w.Write([]byte("document data"))
+	}))
+	defer ts.Close()
+
+	host, port, _ := strings.Cut(strings.TrimPrefix(ts.URL, "http://"), ":")
+	portNumber, _ := strconv.Atoi(port)
+	adapter := NewHttpAdapter(host, portNumber, "", "", false)
+
+	const size = 64 << 20
+
+	testCases := []struct {
+		Name string
+		Size int
+	}{
+		{"known size", size},
+		// sent with chunked transfer encoding
+		{"unknown size", -1},
+	}
+
+	for _, tc := range testCases {
+		req := NewRequest(OperationPrintJob, 1, WithPrinterURI("ipp://printer/ipp/print"),
+			WithDocument(io.LimitReader(zeroReader{}, size), tc.Size, MimeTypeOctetStream))
+		payload, _ := req.Encode()
+
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		allocated := stats.TotalAlloc
+
+		var data bytes.Buffer
+		_, err := adapter.SendRequest(adapter.GetHttpUri("ipp", "print"), req, &data)
+		assert.Nil(t, err, tc.Name)
+
+		// the document is streamed and never held in memory
+		runtime.ReadMemStats(&stats)
+		assert.Less(t, stats.TotalAlloc-allocated, uint64(size/4), tc.Name)
+
+		assert.Equal(t, int64(size), received, tc.Name)
+		if tc.Size >= 0 {
+			assert.Equal(t, int64(len(payload)+size), contentLength, tc.Name)
+		} else {
+			assert.Equal(t, int64(-1), contentLength, tc.Name)
+		}
+		assert.Equal(t, "document data", data.String(), tc.Name)
+	}
+}
+
+func TestHttpAdapter_SetTransportOptions(t *testing.T) {
+	var proto string
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proto = r.Proto
+		req, err := NewRequestDecoder(r.Body).Decode(nil)
+		assert.Nil(t, err)
+
+		payload, _ := NewResponse(StatusOk, req.RequestId).Encode()
+		w.Header().Set("Content-Type", ContentTypeIPP)
+		w.Write(payload)
+	}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	host, port, _ := strings.Cut(strings.TrimPrefix(ts.URL, "https://"), ":")
+	portNumber, _ := strconv.Atoi(port)
+
+	for _, http2 := range []bool{false, true} {
+		adapter := NewHttpAdapter(host, portNumber, "", "", true)
+		adapter.SetTransportOptions(TransportOptions{
+			HTTP2:               http2,
+			MaxIdleConnsPerHost: 4,
+			MaxConnsPerHost:     8,
+			IdleConnTimeout:     time.Minute,
+		})
+
+		transport := adapter.client.Transport.(*http.Transport)
+		assert.Equal(t, 4, transport.MaxIdleConnsPerHost)
+		assert.Equal(t, 8, transport.MaxConnsPerHost)
+		assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+
+		req := NewRequest(OperationGetPrinterAttributes, 1, WithPrinterURI("ipps://printer/ipp/print"))
+		_, err := adapter.SendRequest(adapter.GetHttpUri("ipp", "print"), req, nil)
+		assert.Nil(t, err)
+		if http2 {
+			assert.Equal(t, "HTTP/2.0", proto)
+		} else {
+			assert.Equal(t, "HTTP/1.1", proto)
+		}
+
+		adapter.CloseIdleConnections()
+	}
+}
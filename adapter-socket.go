@@ -1,6 +1,7 @@
 package ipp
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"errors"
@@ -9,7 +10,6 @@ import (
 	"net"
 	"net/http"
 	"os"
-	"strconv"
 )
 
 var SocketNotFoundError = errors.New("unable to locate CUPS socket")
@@ -29,6 +29,8 @@ type SocketAdapter struct {
 	CertSearchPaths   []string
 	//RequestRetryLimit is the number of times a request will be retried when receiving an authorized status. This usually happens when a CUPs cert is expired, and a retry will use the newly generated cert. Default 3.
 	RequestRetryLimit int
+	// OnWarning receives the deviations from the specification tolerated while decoding the responses
+	OnWarning func(Warning)
 }
 
 func NewSocketAdapter(host string, useTLS bool) *SocketAdapter {
@@ -46,26 +48,16 @@ func NewSocketAdapter(host string, useTLS bool) *SocketAdapter {
 func (h *SocketAdapter) SendRequest(url string, r *Request, additionalData io.Writer) (*Response, error) {
 	for i := 0; i < h.RequestRetryLimit; i++ {
 		// encode request
-		payload, err := r.Encode()
+		body, size, err := requestBody(r)
 		if err != nil {
 			return nil, fmt.Errorf("unable to encode IPP request: %w", err)
 		}
 
-		var body io.Reader
-		size := len(payload)
-
-		if r.File != nil && r.FileSize != -1 {
-			size += r.FileSize
-
-			body = io.MultiReader(bytes.NewBuffer(payload), r.File)
-		} else {
-			body = bytes.NewBuffer(payload)
-		}
-
 		req, err := http.NewRequest("POST", url, body)
 		if err != nil {
 			return nil, fmt.Errorf("unable to create HTTP request: %w", err)
 		}
+		req.ContentLength = size
 
 		sock, err := h.GetSocket()
 		if err != nil {
@@ -78,7 +70,6 @@ func (h *SocketAdapter) SendRequest(url string, r *Request, additionalData io.Wr
 			return nil, err
 		}
 
-		req.Header.Set("Content-Length", strconv.Itoa(size))
 		req.Header.Set("Content-Type", ContentTypeIPP)
 		req.Header.Set("Authorization", fmt.Sprintf("Local %s", cert))
 
@@ -104,29 +95,21 @@ func (h *SocketAdapter) SendRequest(url string, r *Request, additionalData io.Wr
 
 		if httpResp.StatusCode != http.StatusOK {
 			httpResp.Body.Close()
-			return nil, fmt.Errorf("server did not return Status OK: %d", httpResp.StatusCode)
-		}
-
-		// buffer response to avoid read issues
-		buf := new(bytes.Buffer)
-		if httpResp.ContentLength > 0 {
-			buf.Grow(int(httpResp.ContentLength))
-		}
-		if _, err := io.Copy(buf, httpResp.Body); err != nil {
-			httpResp.Body.Close()
-			return nil, fmt.Errorf("unable to buffer response: %w", err)
+			return nil, HTTPError{Code: httpResp.StatusCode}
 		}
 
+		// decode reply while it is received, additional data is streamed
+		decoder := NewResponseDecoder(bufio.NewReader(httpResp.Body))
+		decoder.InternValues = true
+		decoder.OnWarning = h.OnWarning
+		ippResp, err := decoder.Decode(additionalData)
 		httpResp.Body.Close()
-
-		// decode reply
-		ippResp, err := NewResponseDecoder(buf).Decode(additionalData)
 		if err != nil {
 			return nil, fmt.Errorf("unable to decode IPP response: %w", err)
 		}
 
 		if err = ippResp.CheckForErrors(); err != nil {
-			return nil, fmt.Errorf("received error IPP response: %w", err)
+			return ippResp, fmt.Errorf("received error IPP response: %w", err)
 		}
 
 		return ippResp, nil
@@ -0,0 +1,104 @@
+package ipp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// CertificatePinError is returned if a printer presents a certificate which doesn't match the pinned fingerprints
+var CertificatePinError = errors.New("certificate doesn't match the pinned fingerprints")
+
+// TLSOptions configure the tls connections of a HttpAdapter
+type TLSOptions struct {
+	// SessionCache resumes tls sessions to save the full handshake on new connections. one cache can be shared by
+	// the adapters of many printers, e.g. tls.NewLRUClientSessionCache(0)
+	SessionCache tls.ClientSessionCache
+	// Pins are the sha-256 fingerprints of the accepted certificates or public keys of the printer as hex strings,
+	// colons are allowed. the printer must present a certificate matching one of them, this is the trust model for
+	// self-signed printer certificates. without pins any certificate is accepted
+	Pins []string
+	// TOFU trusts the certificate of the printer on first contact and rejects a changed certificate later, it can
+	// be used instead of pins if the fingerprints are not known in advance
+	TOFU *TOFUStore
+}
+
+// CertificateFingerprint returns the sha-256 fingerprint of the certificate as pinned with TLSOptions
+func CertificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// PublicKeyFingerprint returns the sha-256 fingerprint of the public key of the certificate as pinned with
+// TLSOptions, it stays the same if the printer renews the certificate with the same key
+func PublicKeyFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(sum[:])
+}
+
+// SetTLSOptions applies the options to the tls connections of the adapter, it should be called before the first
+// request. an error is returned for invalid pins
+func (h *HttpAdapter) SetTLSOptions(opts TLSOptions) error {
+	pins, err := parsePins(opts.Pins)
+	if err != nil {
+		return err
+	}
+
+	transport := h.client.Transport.(*http.Transport)
+	config := transport.TLSClientConfig.Clone()
+	config.ClientSessionCache = opts.SessionCache
+	config.VerifyConnection = nil
+	if len(pins) > 0 || opts.TOFU != nil {
+		// VerifyConnection is called for resumed sessions as well
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return fmt.Errorf("%w: no certificate presented", CertificatePinError)
+			}
+			if len(pins) > 0 {
+				if err := verifyPins(state.PeerCertificates[0], pins); err != nil {
+					return err
+				}
+			}
+			if opts.TOFU != nil {
+				return opts.TOFU.Verify(h.host, state.PeerCertificates[0])
+			}
+			return nil
+		}
+	}
+	transport.TLSClientConfig = config
+
+	return nil
+}
+
+// parsePins decodes hex fingerprints with or without colons
+func parsePins(pins []string) ([][]byte, error) {
+	parsed := make([][]byte, 0, len(pins))
+	for _, pin := range pins {
+		fingerprint, err := hex.DecodeString(strings.ReplaceAll(pin, ":", ""))
+		if err != nil || len(fingerprint) != sha256.Size {
+			return nil, fmt.Errorf("invalid sha-256 fingerprint %q", pin)
+		}
+		parsed = append(parsed, fingerprint)
+	}
+
+	return parsed, nil
+}
+
+// verifyPins checks the certificate against the pinned certificate and public key fingerprints
+func verifyPins(cert *x509.Certificate, pins [][]byte) error {
+	certSum := sha256.Sum256(cert.Raw)
+	keySum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	for _, pin := range pins {
+		if bytes.Equal(pin, certSum[:]) || bytes.Equal(pin, keySum[:]) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", CertificatePinError, CertificateFingerprint(cert))
+}
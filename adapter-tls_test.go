@@ -0,0 +1,65 @@
+package ipp
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHttpAdapter_SetTLSOptions(t *testing.T) {
+	var resumed bool
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resumed = r.TLS.DidResume
+		req, err := NewRequestDecoder(r.Body).Decode(nil)
+		assert.Nil(t, err)
+
+		payload, _ := NewResponse(StatusOk, req.RequestId).Encode()
+		w.Header().Set("Content-Type", ContentTypeIPP)
+		w.Write(payload)
+	}))
+	defer ts.Close()
+
+	host, port, _ := strings.Cut(strings.TrimPrefix(ts.URL, "https://"), ":")
+	portNumber, _ := strconv.Atoi(port)
+	cert := ts.Certificate()
+
+	send := func(opts TLSOptions) error {
+		adapter := NewHttpAdapter(host, portNumber, "", "", true)
+		if err := adapter.SetTLSOptions(opts); err != nil {
+			return err
+		}
+		defer adapter.CloseIdleConnections()
+
+		req := NewRequest(OperationGetPrinterAttributes, 1, WithPrinterURI("ipps://printer/ipp/print"))
+		_, err := adapter.SendRequest(adapter.GetHttpUri("ipp", "print"), req, nil)
+		return err
+	}
+
+	// the session of the first adapter is resumed by the second one
+	cache := tls.NewLRUClientSessionCache(0)
+	assert.Nil(t, send(TLSOptions{SessionCache: cache}))
+	assert.False(t, resumed)
+	assert.Nil(t, send(TLSOptions{SessionCache: cache}))
+	assert.True(t, resumed)
+
+	fingerprint := CertificateFingerprint(cert)
+	colons := strings.ToUpper(fingerprint[:2] + ":" + fingerprint[2:])
+	assert.Nil(t, send(TLSOptions{Pins: []string{colons}}))
+	assert.Nil(t, send(TLSOptions{Pins: []string{strings.Repeat("00", 32), PublicKeyFingerprint(cert)}}))
+	// pins are checked for resumed sessions as well
+	assert.Nil(t, send(TLSOptions{SessionCache: cache, Pins: []string{fingerprint}}))
+	assert.True(t, resumed)
+
+	err := send(TLSOptions{SessionCache: cache, Pins: []string{strings.Repeat("00", 32)}})
+	assert.ErrorIs(t, err, CertificatePinError)
+	err = send(TLSOptions{Pins: []string{strings.Repeat("ab", 32)}})
+	assert.ErrorIs(t, err, CertificatePinError)
+
+	assert.NotNil(t, send(TLSOptions{Pins: []string{"not a fingerprint"}}))
+	assert.NotNil(t, send(TLSOptions{Pins: []string{"abcd"}}))
+}
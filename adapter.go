@@ -1,9 +1,34 @@
 package ipp
 
-import "io"
+import (
+	"bytes"
+	"io"
+)
 
+// Adapter sends ipp requests to a server. SendRequest returns the decoded response together with a StatusError if
+// the response has a non successful status
 type Adapter interface {
 	SendRequest(url string, req *Request, additionalResponseData io.Writer) (*Response, error)
 	GetHttpUri(namespace string, object interface{}) string
 	TestConnection() error
 }
+
+// requestBody encodes the request and returns it followed by the document together with the content length, -1 if
+// the size of the document is unknown. the document is streamed, it is never read into memory
+func requestBody(req *Request) (io.Reader, int64, error) {
+	payload, err := req.Encode()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if req.File == nil {
+		return bytes.NewReader(payload), int64(len(payload)), nil
+	}
+
+	size := int64(-1)
+	if req.FileSize >= 0 {
+		size = int64(len(payload) + req.FileSize)
+	}
+
+	return io.MultiReader(bytes.NewReader(payload), req.File), size, nil
+}
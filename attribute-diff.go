@@ -0,0 +1,119 @@
+package ipp
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// AttributeChange is an attribute whose values differ between two attribute sets
+type AttributeChange struct {
+	Name string
+	Old  []Attribute
+	New  []Attribute
+}
+
+// AttributeDiff is the difference between two attribute sets
+type AttributeDiff struct {
+	// Added are the attributes only present in the new set
+	Added Attributes
+	// Removed are the attributes only present in the old set
+	Removed Attributes
+	// Changed are the attributes present in both sets with different values, sorted by name
+	Changed []AttributeChange
+}
+
+// Diff compares two attribute sets, e.g. the printer attributes before and after a firmware update or the expected
+// and actual attributes of a job. two attributes are equal if they have the same values with the same tags in the
+// same order, collections are compared member by member
+func Diff(old, new Attributes) AttributeDiff {
+	diff := AttributeDiff{Added: make(Attributes), Removed: make(Attributes)}
+
+	for name, values := range old {
+		newValues, ok := new[name]
+		if !ok {
+			diff.Removed[name] = values
+			continue
+		}
+		if !equalAttributeValues(values, newValues) {
+			diff.Changed = append(diff.Changed, AttributeChange{Name: name, Old: values, New: newValues})
+		}
+	}
+
+	for name, values := range new {
+		if _, ok := old[name]; !ok {
+			diff.Added[name] = values
+		}
+	}
+
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].Name < diff.Changed[j].Name
+	})
+
+	return diff
+}
+
+// Empty reports whether the attribute sets of the diff are equal
+func (d AttributeDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String returns one line per difference sorted by attribute name, added attributes are prefixed with +, removed
+// attributes with - and changed attributes with ~, e.g.
+//
+//	~ printer-state: idle -> stopped
+func (d AttributeDiff) String() string {
+	type line struct {
+		name, text string
+	}
+
+	var lines []line
+	for name, values := range d.Added {
+		lines = append(lines, line{name, fmt.Sprintf("+ %s: %s", name, formatValues(name, values))})
+	}
+	for name, values := range d.Removed {
+		lines = append(lines, line{name, fmt.Sprintf("- %s: %s", name, formatValues(name, values))})
+	}
+	for _, change := range d.Changed {
+		lines = append(lines, line{change.Name, fmt.Sprintf("~ %s: %s -> %s", change.Name,
+			formatValues(change.Name, change.Old), formatValues(change.Name, change.New))})
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].name < lines[j].name
+	})
+
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(l.text)
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+// equalAttributeValues compares the tags and values of two attribute values, the names are ignored
+func equalAttributeValues(a, b []Attribute) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Tag != b[i].Tag || !equalValue(a[i].Value, b[i].Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// equalValue compares two decoded attribute values
+func equalValue(a, b interface{}) bool {
+	if av, ok := a.(Attributes); ok {
+		bv, ok := b.(Attributes)
+		return ok && Diff(av, bv).Empty()
+	}
+
+	return reflect.DeepEqual(a, b)
+}
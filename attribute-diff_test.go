@@ -0,0 +1,75 @@
+package ipp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	old := make(Attributes)
+	old.Set(AttributePrinterState, TagEnum, int(PrinterStateIdle))
+	old.Set(AttributePrinterName, TagName, "office")
+	old.Set(AttributeDocumentFormatSupported, TagMimeType, "application/pdf", "image/urf")
+	old.Set(AttributeMediaColDefault, TagBeginCollection, mediaColCollection(t, "iso_a4_210x297mm"))
+	old.Set(AttributeCopiesSupported, TagRange, []int32{1, 99})
+
+	new := make(Attributes)
+	new.Set(AttributePrinterState, TagEnum, int(PrinterStateStopped))
+	new.Set(AttributeDocumentFormatSupported, TagMimeType, "application/pdf", "image/urf")
+	new.Set(AttributeMediaColDefault, TagBeginCollection, mediaColCollection(t, "iso_a4_210x297mm"))
+	new.Set(AttributeCopiesSupported, TagRange, []int32{1, 999})
+	new.Set(AttributePrinterLocation, TagText, "2nd floor")
+
+	diff := Diff(old, new)
+	assert.False(t, diff.Empty())
+	assert.Equal(t, []string{AttributePrinterName}, attributeNamesOf(diff.Removed))
+	assert.Equal(t, []string{AttributePrinterLocation}, attributeNamesOf(diff.Added))
+	assert.Len(t, diff.Changed, 2)
+	assert.Equal(t, AttributeCopiesSupported, diff.Changed[0].Name)
+	assert.Equal(t, AttributePrinterState, diff.Changed[1].Name)
+
+	assert.Equal(t, "~ copies-supported: 1-99 -> 1-999\n"+
+		"+ printer-location: 2nd floor\n"+
+		"- printer-name: office\n"+
+		"~ printer-state: idle -> stopped\n", diff.String())
+
+	assert.True(t, Diff(old, old).Empty())
+	assert.Equal(t, "", Diff(old, old).String())
+}
+
+func TestDiff_Collections(t *testing.T) {
+	a4 := make(Attributes)
+	a4.Set(AttributeMediaCol, TagBeginCollection, mediaColCollection(t, "iso_a4_210x297mm"))
+	letter := make(Attributes)
+	letter.Set(AttributeMediaCol, TagBeginCollection, mediaColCollection(t, "na_letter_8.5x11in"))
+
+	assert.Len(t, Diff(a4, letter).Changed, 1)
+
+	// the order of the values is significant
+	a := make(Attributes)
+	a.Set(AttributeSidesSupported, TagKeyword, SidesOneSided, SidesTwoSidedLongEdge)
+	b := make(Attributes)
+	b.Set(AttributeSidesSupported, TagKeyword, SidesTwoSidedLongEdge, SidesOneSided)
+	assert.Len(t, Diff(a, b).Changed, 1)
+
+	// the tag is significant
+	b.Set(AttributeSidesSupported, TagName, SidesOneSided, SidesTwoSidedLongEdge)
+	assert.Len(t, Diff(a, b).Changed, 1)
+}
+
+func attributeNamesOf(attributes Attributes) []string {
+	var names []string
+	for name := range attributes {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func mediaColCollection(t *testing.T, sizeName string) Attributes {
+	m, err := NewMediaCol(sizeName)
+	assert.Nil(t, err)
+
+	return m.Collection()
+}
@@ -0,0 +1,108 @@
+package ipp
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var AttributeNotFoundError = errors.New("attribute not found")
+
+// attributeValueUnmarshaler is implemented by typed collections like MediaCol
+type attributeValueUnmarshaler interface {
+	Unmarshal(Attributes) error
+}
+
+// GetOne returns the first value of the attribute converted to T. integers and enums convert to all integer kinds,
+// including the enum types like JobState, all string syntaxes convert to string kinds. dateTime values convert to
+// time.Time and collections to types with an Unmarshal(Attributes) error method like MediaCol, other values must
+// have the type T. out-of-band values like no-value are treated as missing. an error wrapping AttributeNotFoundError
+// or AttributeTypeError is returned if the value is missing or can not be converted
+func GetOne[T any](attributes Attributes, name string) (T, error) {
+	var value T
+
+	values := (&attributeUnmarshaler{attributes: attributes}).values(name)
+	if len(values) == 0 {
+		return value, fmt.Errorf("%w: %s", AttributeNotFoundError, name)
+	}
+
+	err := convertAttributeValue(name, values[0].Value, &value)
+	return value, err
+}
+
+// GetSet returns all values of the attribute converted to T, the conversions are the same as for GetOne. an error
+// wrapping AttributeNotFoundError is returned if the attribute has no values
+func GetSet[T any](attributes Attributes, name string) ([]T, error) {
+	values := (&attributeUnmarshaler{attributes: attributes}).values(name)
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w: %s", AttributeNotFoundError, name)
+	}
+
+	set := make([]T, len(values))
+	for i, attr := range values {
+		if err := convertAttributeValue(name, attr.Value, &set[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return set, nil
+}
+
+// convertAttributeValue stores the decoded value in the value target points to
+func convertAttributeValue(name string, value interface{}, target interface{}) error {
+	typeError := func() error {
+		return fmt.Errorf("%w: %s has value %v of type %T, expected %T", AttributeTypeError, name, value, value,
+			reflect.ValueOf(target).Elem().Interface())
+	}
+
+	switch t := target.(type) {
+	case *time.Time:
+		d, ok := value.([]int)
+		if !ok {
+			return typeError()
+		}
+		if *t, ok = DecodeDateTime(d); !ok {
+			return typeError()
+		}
+		return nil
+	case attributeValueUnmarshaler:
+		c, ok := value.(Attributes)
+		if !ok {
+			return typeError()
+		}
+		return t.Unmarshal(c)
+	}
+
+	v := reflect.ValueOf(value)
+	elem := reflect.ValueOf(target).Elem()
+
+	switch elem.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if elem.OverflowInt(v.Int()) {
+				return typeError()
+			}
+			elem.SetInt(v.Int())
+			return nil
+		}
+	case reflect.String:
+		if v.Kind() == reflect.String {
+			elem.SetString(v.String())
+			return nil
+		}
+	case reflect.Bool:
+		if v.Kind() == reflect.Bool {
+			elem.SetBool(v.Bool())
+			return nil
+		}
+	}
+
+	if !v.IsValid() || !v.Type().AssignableTo(elem.Type()) {
+		return typeError()
+	}
+	elem.Set(v)
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package ipp
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOne(t *testing.T) {
+	a4 := make(Attributes)
+	a4.Set(AttributeXDimension, TagInteger, 21000)
+	a4.Set(AttributeYDimension, TagInteger, 29700)
+	size := make(Attributes)
+	size.Set(AttributeMediaSize, TagBeginCollection, a4)
+
+	attributes := make(Attributes)
+	attributes.Set(AttributeCopies, TagInteger, 2)
+	attributes.Set(AttributeJobState, TagEnum, int(JobStateProcessing))
+	attributes.Set(AttributeJobName, TagName, "report")
+	attributes.Set(AttributeColorSupported, TagBoolean, true)
+	attributes.Set(AttributeCopiesSupported, TagRange, []int32{1, 99})
+	attributes.Set(AttributeDateTimeAtCreation, TagDate, []int{7, -28, 5, 1, 12, 30, 0, 0, '+', 2, 0})
+	attributes.Set(AttributeMediaColDefault, TagBeginCollection, size)
+	attributes.Set(AttributeJobPrinterURI, TagNoValue, "")
+
+	copies, err := GetOne[int](attributes, AttributeCopies)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, copies)
+
+	copies32, err := GetOne[int32](attributes, AttributeCopies)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), copies32)
+
+	state, err := GetOne[JobState](attributes, AttributeJobState)
+	assert.Nil(t, err)
+	assert.Equal(t, "processing", state.String())
+
+	name, err := GetOne[string](attributes, AttributeJobName)
+	assert.Nil(t, err)
+	assert.Equal(t, "report", name)
+
+	color, err := GetOne[bool](attributes, AttributeColorSupported)
+	assert.Nil(t, err)
+	assert.True(t, color)
+
+	copiesSupported, err := GetOne[[]int32](attributes, AttributeCopiesSupported)
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{1, 99}, copiesSupported)
+
+	created, err := GetOne[time.Time](attributes, AttributeDateTimeAtCreation)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2020, 5, 1, 10, 30, 0, 0, time.UTC), created.UTC())
+
+	media, err := GetOne[MediaCol](attributes, AttributeMediaColDefault)
+	assert.Nil(t, err)
+	assert.Equal(t, 21000, media.Width)
+
+	_, err = GetOne[int](attributes, AttributeJobName)
+	assert.True(t, errors.Is(err, AttributeTypeError))
+
+	_, err = GetOne[int8](attributes, AttributeDateTimeAtCreation)
+	assert.True(t, errors.Is(err, AttributeTypeError))
+
+	_, err = GetOne[string](attributes, AttributeJobPrinterURI)
+	assert.True(t, errors.Is(err, AttributeNotFoundError))
+
+	_, err = GetOne[string](attributes, AttributeJobURI)
+	assert.True(t, errors.Is(err, AttributeNotFoundError))
+}
+
+func TestGetSet(t *testing.T) {
+	attributes := make(Attributes)
+	attributes.Set(AttributeOperationsSupported, TagEnum, int(OperationPrintJob), int(OperationGetJobs))
+	attributes.Set(AttributeSidesSupported, TagKeyword, "one-sided", "two-sided-long-edge")
+	attributes.Set(AttributePrinterStateReasons, TagKeyword, "none", 5)
+
+	operations, err := GetSet[Operation](attributes, AttributeOperationsSupported)
+	assert.Nil(t, err)
+	assert.Equal(t, []Operation{Operation(OperationPrintJob), Operation(OperationGetJobs)}, operations)
+
+	sides, err := GetSet[string](attributes, AttributeSidesSupported)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"one-sided", "two-sided-long-edge"}, sides)
+
+	_, err = GetSet[string](attributes, AttributePrinterStateReasons)
+	assert.True(t, errors.Is(err, AttributeTypeError))
+
+	_, err = GetSet[string](attributes, AttributeMediaSupported)
+	assert.True(t, errors.Is(err, AttributeNotFoundError))
+}
@@ -0,0 +1,368 @@
+package ipp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// attribute groups used as hints in struct tags
+const (
+	GroupOperation    = "operation"
+	GroupJob          = "job"
+	GroupPrinter      = "printer"
+	GroupSubscription = "subscription"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	resolutionType = reflect.TypeOf(Resolution{})
+	attributesType = reflect.TypeOf(Attributes{})
+)
+
+// collectionMarshaler is implemented by typed collections like MediaCol
+type collectionMarshaler interface {
+	Collection() Attributes
+}
+
+// fieldInfo is the parsed struct tag of a field
+type fieldInfo struct {
+	index     int
+	name      string
+	tag       int8
+	group     string
+	omitEmpty bool
+}
+
+// structFields parses the ipp struct tags of a struct type. the tag has the form `ipp:"name,hints..."`, the hints are
+// a syntax like keyword or enum, a group like operation or job and omitempty. fields without tag or with tag "-" are
+// skipped
+func structFields(t reflect.Type) ([]fieldInfo, error) {
+	var fields []fieldInfo
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("ipp")
+		if !ok || tag == "-" || field.PkgPath != "" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		info := fieldInfo{index: i, name: parts[0]}
+		if info.name == "" {
+			return nil, fmt.Errorf("field %s has no attribute name", field.Name)
+		}
+
+		for _, hint := range parts[1:] {
+			switch hint {
+			case "omitempty":
+				info.omitEmpty = true
+			case GroupOperation, GroupJob, GroupPrinter, GroupSubscription:
+				info.group = hint
+			default:
+				tag, ok := ParseTagSyntax(hint)
+				if !ok || isOutOfBandTag(tag) {
+					return nil, fmt.Errorf("field %s has unknown hint %s", field.Name, hint)
+				}
+				info.tag = tag
+			}
+		}
+
+		fields = append(fields, info)
+	}
+
+	return fields, nil
+}
+
+// structValue dereferences v and checks that it is a struct
+func structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("cannot marshal attributes of type %T, a struct is required", v)
+	}
+
+	return rv, nil
+}
+
+// MarshalAttributes converts the fields of a struct with ipp struct tags into attributes, similar to encoding/json:
+//
+//	type Options struct {
+//		Copies int          `ipp:"copies,omitempty"`
+//		Sides  string       `ipp:"sides"`
+//		Media  *MediaCol    `ipp:"media-col,omitempty"`
+//		Finish []Finishings `ipp:"finishings,enum"`
+//	}
+//
+// the value tag is taken from the syntax hint, then from the AttributeTagMapping and is derived from the field type
+// otherwise. slices are encoded as sets, nested structs with ipp struct tags and types with a Collection method like
+// MediaCol as collections. nil pointers, nil slices and empty values with omitempty are skipped. group hints are
+// ignored
+func MarshalAttributes(v interface{}) (Attributes, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := make(Attributes)
+	err = marshalFields(rv, func(info fieldInfo, values []Attribute) {
+		attributes[info.name] = values
+	})
+
+	return attributes, err
+}
+
+// MarshalRequest sets the attributes of a struct with ipp struct tags in the groups of the request given by the group
+// hints, fields without group hint are job attributes. the marshalling is the same as for MarshalAttributes, values
+// with a tag which differs from the AttributeTagMapping are stored as []Attribute to keep the tag
+func MarshalRequest(req *Request, v interface{}) error {
+	rv, err := structValue(v)
+	if err != nil {
+		return err
+	}
+
+	return marshalFields(rv, func(info fieldInfo, values []Attribute) {
+		group := req.JobAttributes
+		switch info.group {
+		case GroupOperation:
+			group = req.OperationAttributes
+		case GroupPrinter:
+			group = req.PrinterAttributes
+		case GroupSubscription:
+			group = req.SubscriptionAttributes
+		}
+
+		group[info.name] = requestValue(info.name, values)
+	})
+}
+
+func marshalFields(rv reflect.Value, set func(info fieldInfo, values []Attribute)) error {
+	fields, err := structFields(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, info := range fields {
+		field := rv.Field(info.index)
+		if info.omitEmpty && field.IsZero() {
+			continue
+		}
+
+		if info.tag == 0 {
+			info.tag = AttributeTagMapping[info.name]
+		}
+
+		values, err := marshalField(info, field)
+		if err != nil {
+			return err
+		}
+		if len(values) > 0 {
+			set(info, values)
+		}
+	}
+
+	return nil
+}
+
+// marshalField converts a field into the values of the attribute
+func marshalField(info fieldInfo, field reflect.Value) ([]Attribute, error) {
+	switch field.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if field.IsNil() {
+			return nil, nil
+		}
+		return marshalField(info, field.Elem())
+	case reflect.Slice:
+		// ranges and raw dates are slices themselves
+		if info.tag == TagRange || info.tag == TagDate || field.Type() == reflect.TypeOf([]byte(nil)) {
+			break
+		}
+		if field.IsNil() {
+			return nil, nil
+		}
+
+		values := make([]Attribute, 0, field.Len())
+		for i := 0; i < field.Len(); i++ {
+			value, err := marshalField(info, field.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value...)
+		}
+		return values, nil
+	}
+
+	tag, value, err := marshalValue(info, field)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Attribute{{Tag: tag, Name: info.name, Value: value}}, nil
+}
+
+// marshalValue converts a single value and determines its tag
+func marshalValue(info fieldInfo, field reflect.Value) (int8, interface{}, error) {
+	tag := info.tag
+	if c, ok := field.Interface().(collectionMarshaler); ok {
+		return TagBeginCollection, c.Collection(), nil
+	}
+
+	switch field.Type() {
+	case timeType:
+		return TagDate, EncodeDateTime(field.Interface().(time.Time)), nil
+	case resolutionType:
+		return TagResolution, field.Interface(), nil
+	case attributesType:
+		return TagBeginCollection, field.Interface(), nil
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if tag != TagEnum {
+			tag = TagInteger
+		}
+		return tag, int(field.Int()), nil
+	case reflect.Bool:
+		return TagBoolean, field.Bool(), nil
+	case reflect.String:
+		if tag == 0 {
+			tag = TagKeyword
+		}
+		return tag, field.String(), nil
+	case reflect.Slice:
+		switch tag {
+		case TagRange:
+			if r, ok := field.Interface().([]int32); ok && len(r) == 2 {
+				return tag, r, nil
+			}
+		case TagDate:
+			if d, ok := field.Interface().([]int); ok {
+				return tag, d, nil
+			}
+		default:
+			if b, ok := field.Interface().([]byte); ok {
+				return TagString, string(b), nil
+			}
+		}
+	case reflect.Struct:
+		c := make(Attributes)
+		err := marshalFields(field, func(info fieldInfo, values []Attribute) {
+			c[info.name] = values
+		})
+		return TagBeginCollection, c, err
+	}
+
+	return 0, nil, fmt.Errorf("%w: cannot marshal %s of type %s", AttributeTypeError, info.name, field.Type())
+}
+
+// UnmarshalAttributes populates the fields of the struct v points to from the attributes, the fields are matched by
+// their ipp struct tags. the conversions are the same as for GetOne, slices are populated with all values and nested
+// structs with ipp struct tags from collections. missing attributes keep the field unchanged
+func UnmarshalAttributes(attributes Attributes, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot unmarshal attributes into %T, a pointer to a struct is required", v)
+	}
+
+	return unmarshalFields(rv.Elem(), func(info fieldInfo) Attributes {
+		return attributes
+	})
+}
+
+// UnmarshalResponse populates the fields of the struct v points to from the response. fields with a group hint are
+// read from the operation attributes or the first job, printer or subscription attributes, fields without group hint
+// from the first printer attributes, the first job attributes or the operation attributes, whichever contains the
+// attribute
+func UnmarshalResponse(resp *Response, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot unmarshal response into %T, a pointer to a struct is required", v)
+	}
+
+	first := func(groups []Attributes) Attributes {
+		if len(groups) == 0 {
+			return nil
+		}
+		return groups[0]
+	}
+
+	return unmarshalFields(rv.Elem(), func(info fieldInfo) Attributes {
+		switch info.group {
+		case GroupOperation:
+			return resp.OperationAttributes
+		case GroupJob:
+			return first(resp.JobAttributes)
+		case GroupPrinter:
+			return first(resp.PrinterAttributes)
+		case GroupSubscription:
+			return first(resp.SubscriptionAttributes)
+		}
+
+		for _, group := range []Attributes{first(resp.PrinterAttributes), first(resp.JobAttributes)} {
+			if _, ok := group[info.name]; ok {
+				return group
+			}
+		}
+		return resp.OperationAttributes
+	})
+}
+
+func unmarshalFields(rv reflect.Value, group func(info fieldInfo) Attributes) error {
+	fields, err := structFields(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, info := range fields {
+		values := (&attributeUnmarshaler{attributes: group(info)}).values(info.name)
+		if len(values) == 0 {
+			continue
+		}
+
+		if err := unmarshalField(info.name, values, rv.Field(info.index)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unmarshalField stores the values in the field
+func unmarshalField(name string, values []Attribute, field reflect.Value) error {
+	switch field.Kind() {
+	case reflect.Ptr:
+		value := reflect.New(field.Type().Elem())
+		if err := unmarshalField(name, values, value.Elem()); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	case reflect.Slice:
+		// ranges and dates are decoded as slices
+		if reflect.TypeOf(values[0].Value) == field.Type() {
+			break
+		}
+
+		set := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := unmarshalField(name, []Attribute{value}, set.Index(i)); err != nil {
+				return err
+			}
+		}
+		field.Set(set)
+		return nil
+	case reflect.Struct:
+		c, ok := values[0].Value.(Attributes)
+		_, unmarshaler := field.Addr().Interface().(attributeValueUnmarshaler)
+		if ok && !unmarshaler && field.Type() != timeType && field.Type() != resolutionType {
+			return unmarshalFields(field, func(info fieldInfo) Attributes {
+				return c
+			})
+		}
+	}
+
+	return convertAttributeValue(name, values[0].Value, field.Addr().Interface())
+}
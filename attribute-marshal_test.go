@@ -0,0 +1,120 @@
+package ipp
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testJobOptions struct {
+	User       string       `ipp:"requesting-user-name,name,operation"`
+	Copies     int          `ipp:"copies,omitempty"`
+	Sides      string       `ipp:"sides"`
+	Finishings []Finishings `ipp:"finishings"`
+	Media      *MediaCol    `ipp:"media-col,omitempty"`
+	Hold       string       `ipp:"job-hold-until,omitempty"`
+	Custom     string       `ipp:"x-custom-option,text,omitempty"`
+	Ignored    string       `ipp:"-"`
+	internal   string
+}
+
+type testPrinterStatus struct {
+	Name         string         `ipp:"printer-name"`
+	State        PrinterState   `ipp:"printer-state"`
+	StateReasons []string       `ipp:"printer-state-reasons"`
+	Copies       []int32        `ipp:"copies-supported"`
+	Resolutions  []Resolution   `ipp:"printer-resolution-supported"`
+	Media        MediaCol       `ipp:"media-col-default"`
+	Size         testMediaSize  `ipp:"media-size,collection"`
+	ChangedAt    time.Time      `ipp:"printer-config-change-date-time"`
+	Charset      string         `ipp:"attributes-charset,operation"`
+	Missing      *string        `ipp:"printer-location"`
+	Impressions  map[string]int `ipp:"-"`
+}
+
+type testMediaSize struct {
+	Width  int `ipp:"x-dimension"`
+	Height int `ipp:"y-dimension"`
+}
+
+func TestMarshalRequest(t *testing.T) {
+	media, _ := NewMediaCol("iso_a4_210x297mm")
+	options := testJobOptions{
+		User:       "alice",
+		Sides:      "two-sided-long-edge",
+		Finishings: []Finishings{4, 20},
+		Media:      &media,
+		Custom:     "value",
+		Ignored:    "ignored",
+		internal:   "internal",
+	}
+
+	req := NewRequest(OperationPrintJob, 1)
+	assert.Nil(t, MarshalRequest(req, &options))
+
+	assert.Equal(t, "alice", req.OperationAttributes[AttributeRequestingUserName])
+	assert.Equal(t, "two-sided-long-edge", req.JobAttributes[AttributeSides])
+	assert.Equal(t, []interface{}{4, 20}, req.JobAttributes[AttributeFinishings])
+	assert.Equal(t, []Attribute{{Tag: TagText, Name: "x-custom-option", Value: "value"}}, req.JobAttributes["x-custom-option"])
+	assert.NotContains(t, req.JobAttributes, AttributeCopies)
+	assert.NotContains(t, req.JobAttributes, AttributeJobHoldUntil)
+	assert.Len(t, req.JobAttributes, 4)
+
+	data, err := req.Encode()
+	assert.Nil(t, err)
+
+	decoded, err := NewRequestDecoder(bytes.NewReader(data)).Decode(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "value", decoded.JobAttributes["x-custom-option"])
+}
+
+func TestMarshalAttributes(t *testing.T) {
+	changed := time.Date(2020, 5, 1, 12, 30, 0, 0, time.FixedZone("", 2*3600))
+	status := testPrinterStatus{
+		Name:         "office",
+		State:        PrinterState(PrinterStateIdle),
+		StateReasons: []string{"none"},
+		Copies:       []int32{1, 99},
+		Resolutions:  []Resolution{{Width: 300, Height: 300, Depth: 3}},
+		Size:         testMediaSize{Width: 21000, Height: 29700},
+		ChangedAt:    changed,
+	}
+
+	attributes, err := MarshalAttributes(status)
+	assert.Nil(t, err)
+	assert.Equal(t, TagEnum, attributes[AttributePrinterState][0].Tag)
+	assert.Equal(t, TagRange, attributes[AttributeCopiesSupported][0].Tag)
+	assert.Equal(t, TagBeginCollection, attributes[AttributeMediaSize][0].Tag)
+	assert.NotContains(t, attributes, AttributePrinterLocation)
+
+	resp := NewResponse(StatusOk, 1)
+	resp.PrinterAttributes = append(resp.PrinterAttributes, attributes)
+	data, err := resp.Encode()
+	assert.Nil(t, err)
+
+	decoded, err := NewResponseDecoder(bytes.NewReader(data)).Decode(nil)
+	assert.Nil(t, err)
+
+	var unmarshaled testPrinterStatus
+	assert.Nil(t, UnmarshalResponse(decoded, &unmarshaled))
+	assert.Equal(t, "office", unmarshaled.Name)
+	assert.Equal(t, "idle", unmarshaled.State.String())
+	assert.Equal(t, []string{"none"}, unmarshaled.StateReasons)
+	assert.Equal(t, []int32{1, 99}, unmarshaled.Copies)
+	assert.Equal(t, status.Resolutions, unmarshaled.Resolutions)
+	assert.Equal(t, status.Size, unmarshaled.Size)
+	assert.True(t, changed.Equal(unmarshaled.ChangedAt))
+	assert.Equal(t, Charset, unmarshaled.Charset)
+	assert.Nil(t, unmarshaled.Missing)
+
+	attributes[AttributePrinterState] = []Attribute{{Tag: TagKeyword, Value: "idle"}}
+	err = UnmarshalAttributes(attributes, &unmarshaled)
+	assert.True(t, errors.Is(err, AttributeTypeError))
+
+	assert.NotNil(t, UnmarshalAttributes(attributes, unmarshaled))
+	_, err = MarshalAttributes("office")
+	assert.NotNil(t, err)
+}
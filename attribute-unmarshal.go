@@ -0,0 +1,226 @@
+package ipp
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var AttributeTypeError = errors.New("unexpected attribute value type")
+
+// attributeUnmarshaler reads typed values from decoded attributes. out-of-band values like no-value or unknown are
+// treated as missing attributes, the first value of an unexpected type is recorded as error
+type attributeUnmarshaler struct {
+	attributes Attributes
+	err        error
+}
+
+// values returns the in-band values of the attribute
+func (u *attributeUnmarshaler) values(name string) []Attribute {
+	values := make([]Attribute, 0, len(u.attributes[name]))
+	for _, attr := range u.attributes[name] {
+		switch attr.Tag {
+		case TagUnsupportedValue, TagDefault, TagUnknown, TagNoValue, TagNotSettable, TagDeleteAttr, TagAdminDefine:
+			continue
+		}
+		values = append(values, attr)
+	}
+
+	return values
+}
+
+func (u *attributeUnmarshaler) typeError(name string, value interface{}) {
+	if u.err == nil {
+		u.err = fmt.Errorf("%w: %s has value %v of type %T", AttributeTypeError, name, value, value)
+	}
+}
+
+func (u *attributeUnmarshaler) string(name string) string {
+	values := u.strings(name)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func (u *attributeUnmarshaler) strings(name string) []string {
+	var values []string
+	for _, attr := range u.values(name) {
+		value, ok := attr.Value.(string)
+		if !ok {
+			u.typeError(name, attr.Value)
+			continue
+		}
+		values = append(values, value)
+	}
+
+	return values
+}
+
+func (u *attributeUnmarshaler) int(name string) int {
+	values := u.ints(name)
+	if len(values) == 0 {
+		return 0
+	}
+
+	return values[0]
+}
+
+func (u *attributeUnmarshaler) ints(name string) []int {
+	var values []int
+	for _, attr := range u.values(name) {
+		value, ok := attr.Value.(int)
+		if !ok {
+			u.typeError(name, attr.Value)
+			continue
+		}
+		values = append(values, value)
+	}
+
+	return values
+}
+
+func (u *attributeUnmarshaler) bool(name string) bool {
+	for _, attr := range u.values(name) {
+		value, ok := attr.Value.(bool)
+		if !ok {
+			u.typeError(name, attr.Value)
+			continue
+		}
+		return value
+	}
+
+	return false
+}
+
+func (u *attributeUnmarshaler) resolutions(name string) []Resolution {
+	var values []Resolution
+	for _, attr := range u.values(name) {
+		value, ok := attr.Value.(Resolution)
+		if !ok {
+			u.typeError(name, attr.Value)
+			continue
+		}
+		values = append(values, value)
+	}
+
+	return values
+}
+
+func (u *attributeUnmarshaler) collections(name string) []Attributes {
+	var values []Attributes
+	for _, attr := range u.values(name) {
+		value, ok := attr.Value.(Attributes)
+		if !ok {
+			u.typeError(name, attr.Value)
+			continue
+		}
+		values = append(values, value)
+	}
+
+	return values
+}
+
+func (u *attributeUnmarshaler) collection(name string) (Attributes, bool) {
+	values := u.collections(name)
+	if len(values) == 0 {
+		return nil, false
+	}
+
+	return values[0], true
+}
+
+// mediaCols returns the media-col values of the attribute
+func (u *attributeUnmarshaler) mediaCols(name string) []MediaCol {
+	var values []MediaCol
+	for _, c := range u.collections(name) {
+		var m MediaCol
+		if err := m.Unmarshal(c); err != nil && u.err == nil {
+			u.err = err
+		}
+		values = append(values, m)
+	}
+
+	return values
+}
+
+// rangeUpper returns the upper bound of a rangeOfInteger attribute, integer values are accepted as well
+func (u *attributeUnmarshaler) rangeUpper(name string) int {
+	for _, attr := range u.values(name) {
+		switch value := attr.Value.(type) {
+		case []int32:
+			if len(value) == 2 {
+				return int(value[1])
+			}
+		case int:
+			return value
+		}
+		u.typeError(name, attr.Value)
+	}
+
+	return 0
+}
+
+// dateTime returns the first dateTime value of the attribute
+func (u *attributeUnmarshaler) dateTime(name string) (time.Time, bool) {
+	for _, attr := range u.values(name) {
+		value, ok := attr.Value.([]int)
+		if !ok {
+			u.typeError(name, attr.Value)
+			continue
+		}
+
+		t, ok := DecodeDateTime(value)
+		if !ok {
+			u.typeError(name, attr.Value)
+			continue
+		}
+		return t, true
+	}
+
+	return time.Time{}, false
+}
+
+// DecodeDateTime converts the octets of a rfc 2579 DateAndTime value, as returned by the decoder for dateTime
+// attributes, into a time
+func DecodeDateTime(d []int) (time.Time, bool) {
+	if len(d) != 11 {
+		return time.Time{}, false
+	}
+
+	b := make([]int, len(d))
+	for i, v := range d {
+		// the decoder returns the octets as signed bytes
+		b[i] = int(uint8(int8(v)))
+	}
+
+	offset := (b[9]*60 + b[10]) * 60
+	if b[8] == '-' {
+		offset = -offset
+	}
+
+	year := b[0]<<8 | b[1]
+	location := time.FixedZone("", offset)
+
+	return time.Date(year, time.Month(b[2]), b[3], b[4], b[5], b[6], b[7]*100000000, location), true
+}
+
+// EncodeDateTime converts a time into the octets of a rfc 2579 DateAndTime value, as signed bytes like the decoder
+// returns them. the result can be used as value of a dateTime attribute
+func EncodeDateTime(t time.Time) []int {
+	_, offset := t.Zone()
+	direction := '+'
+	if offset < 0 {
+		direction = '-'
+		offset = -offset
+	}
+
+	d := []int{t.Year() >> 8, t.Year() & 0xff, int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second(),
+		t.Nanosecond() / 100000000, int(direction), offset / 3600, offset / 60 % 60}
+	for i, v := range d {
+		d[i] = int(int8(uint8(v)))
+	}
+
+	return d
+}
@@ -0,0 +1,55 @@
+package ipp
+
+import "strings"
+
+// builtinAttributes are the names of the AttributeTagMapping defined by the package
+var builtinAttributes = func() map[string]bool {
+	names := make(map[string]bool, len(AttributeTagMapping))
+	for name := range AttributeTagMapping {
+		names[name] = true
+	}
+
+	return names
+}()
+
+// attributeNames are the names of the AttributeTagMapping, the decoder uses them instead of allocating known names
+var attributeNames = func() map[string]string {
+	names := make(map[string]string, len(AttributeTagMapping))
+	for name := range AttributeTagMapping {
+		names[name] = name
+	}
+
+	return names
+}()
+
+// RegisterAttribute adds the syntax of a vendor or extension attribute to the AttributeTagMapping, so request values
+// of the attribute can be given without tag, e.g. RegisterAttribute("hp-job-pin", TagText). it must not be called
+// concurrently with encoding or decoding, e.g. call it in an init function
+func RegisterAttribute(name string, tag int8) {
+	AttributeTagMapping[name] = tag
+	attributeNames[name] = name
+}
+
+// Vendor returns the attributes which are not defined by the package, e.g. vendor attributes or attributes
+// registered with RegisterAttribute, grouped by the prefix up to the first dash, e.g. hp for hp-pclm-supported or
+// smi2699 for smi2699-device-command
+func (a Attributes) Vendor() map[string]Attributes {
+	vendor := make(map[string]Attributes)
+	for name, values := range a {
+		if builtinAttributes[name] {
+			continue
+		}
+
+		prefix := name
+		if i := strings.IndexByte(name, '-'); i > 0 {
+			prefix = name[:i]
+		}
+
+		if vendor[prefix] == nil {
+			vendor[prefix] = make(Attributes)
+		}
+		vendor[prefix][name] = values
+	}
+
+	return vendor
+}
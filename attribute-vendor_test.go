@@ -0,0 +1,78 @@
+package ipp
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterAttribute(t *testing.T) {
+	RegisterAttribute("hp-job-pin", TagText)
+	defer delete(AttributeTagMapping, "hp-job-pin")
+
+	req := NewRequest(OperationPrintJob, 1)
+	req.JobAttributes["hp-job-pin"] = "1234"
+	data, err := req.Encode()
+	assert.Nil(t, err)
+
+	decoded, err := NewRequestDecoder(bytes.NewReader(data)).Decode(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "1234", decoded.JobAttributes["hp-job-pin"])
+}
+
+func TestRequest_VendorAttributesRoundTrip(t *testing.T) {
+	req := NewRequest(OperationPrintJob, 1)
+	req.JobAttributes["smi2699-job-color"] = []Attribute{
+		{Tag: TagKeyword, Name: "smi2699-job-color", Value: "red"},
+		{Tag: TagKeyword, Name: "smi2699-job-color", Value: "blue"},
+	}
+	req.JobAttributes["epson-media-thickness"] = Attribute{Tag: TagInteger, Value: 3}
+
+	data, err := req.Encode()
+	assert.Nil(t, err)
+	decoded, err := NewRequestDecoder(bytes.NewReader(data)).Decode(nil)
+	assert.Nil(t, err)
+
+	// the values are decoded like the values of known attributes, the tags are kept by the request
+	assert.Equal(t, []string{"red", "blue"}, decoded.JobAttributes["smi2699-job-color"])
+	assert.Equal(t, 3, decoded.JobAttributes["epson-media-thickness"])
+	tag, ok := decoded.AttributeTag("smi2699-job-color")
+	assert.True(t, ok)
+	assert.Equal(t, TagKeyword, tag)
+	tag, ok = decoded.AttributeTag("epson-media-thickness")
+	assert.True(t, ok)
+	assert.Equal(t, TagInteger, tag)
+	_, ok = decoded.AttributeTag("x-unknown")
+	assert.False(t, ok)
+
+	// the decoded request encodes without losing the vendor attributes
+	again, err := decoded.Encode()
+	assert.Nil(t, err)
+	redecoded, err := NewRequestDecoder(bytes.NewReader(again)).Decode(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, decoded.JobAttributes, redecoded.JobAttributes)
+	tag, _ = redecoded.AttributeTag("smi2699-job-color")
+	assert.Equal(t, TagKeyword, tag)
+
+	// the json encoding uses the decoded tags too
+	encoded, err := json.Marshal(decoded)
+	assert.Nil(t, err)
+	assert.Contains(t, string(encoded), `{"name":"epson-media-thickness","tag":"integer","values":[3]}`)
+}
+
+func TestAttributes_Vendor(t *testing.T) {
+	attributes := make(Attributes)
+	attributes.Set(AttributePrinterName, TagName, "office")
+	attributes.Set("hp-pclm-supported", TagBoolean, true)
+	attributes.Set("hp-device-id", TagText, "MFG:HP;")
+	attributes.Set("smi2699-device-command", TagName, "ippeveps")
+	attributes.Set("marker", TagKeyword, "toner")
+
+	vendor := attributes.Vendor()
+	assert.Len(t, vendor, 3)
+	assert.Len(t, vendor["hp"], 2)
+	assert.Equal(t, "ippeveps", vendor["smi2699"]["smi2699-device-command"][0].Value)
+	assert.Contains(t, vendor["marker"], "marker")
+}
@@ -2,37 +2,65 @@ package ipp
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
+	"sort"
 )
 
 const (
-	sizeInteger = int16(4)
-	sizeBoolean = int16(1)
+	sizeInteger    = int16(4)
+	sizeBoolean    = int16(1)
+	sizeResolution = int16(9)
+	sizeRange      = int16(8)
 )
 
 // AttributeEncoder encodes attribute to a io.Writer
 type AttributeEncoder struct {
 	writer io.Writer
+	// scratch holds the length and bytes of a fixed size value, it is reused for all values
+	scratch [2 + sizeResolution]byte
 }
 
 // NewAttributeEncoder returns a new encoder that writes to w
 func NewAttributeEncoder(w io.Writer) *AttributeEncoder {
-	return &AttributeEncoder{w}
+	return &AttributeEncoder{writer: w}
 }
 
 // Encode encodes a attribute and its value to a io.Writer
-// the tag is determined by the AttributeTagMapping map
+// the tag is determined by the AttributeTagMapping map, values of type Attribute or []Attribute are encoded with their own tag
 func (e *AttributeEncoder) Encode(attribute string, value interface{}) error {
+	switch v := value.(type) {
+	case Attribute:
+		return e.EncodeWithTag(attribute, v.Tag, v.Value)
+	case []Attribute:
+		for index, val := range v {
+			name := attribute
+			if index > 0 {
+				name = ""
+			}
+
+			if err := e.EncodeWithTag(name, val.Tag, val.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	tag, ok := AttributeTagMapping[attribute]
 	if !ok {
-		return fmt.Errorf("cannot get tag of attribute %s", attribute)
+		return fmt.Errorf("%w: cannot get tag of attribute %s", InvalidTagError, attribute)
 	}
 
+	return e.EncodeWithTag(attribute, tag, value)
+}
+
+// EncodeWithTag encodes a attribute and its value to a io.Writer using the given tag
+func (e *AttributeEncoder) EncodeWithTag(attribute string, tag int8, value interface{}) error {
 	switch v := value.(type) {
 	case int:
 		if tag != TagInteger && tag != TagEnum {
-			return fmt.Errorf("tag for attribute %s does not match with value type", attribute)
+			return fmt.Errorf("%w: tag for attribute %s does not match with value type", InvalidTagError, attribute)
 		}
 
 		if err := e.encodeTag(tag); err != nil {
@@ -48,7 +76,7 @@ func (e *AttributeEncoder) Encode(attribute string, value interface{}) error {
 		}
 	case int16:
 		if tag != TagInteger && tag != TagEnum {
-			return fmt.Errorf("tag for attribute %s does not match with value type", attribute)
+			return fmt.Errorf("%w: tag for attribute %s does not match with value type", InvalidTagError, attribute)
 		}
 
 		if err := e.encodeTag(tag); err != nil {
@@ -64,7 +92,7 @@ func (e *AttributeEncoder) Encode(attribute string, value interface{}) error {
 		}
 	case int8:
 		if tag != TagInteger && tag != TagEnum {
-			return fmt.Errorf("tag for attribute %s does not match with value type", attribute)
+			return fmt.Errorf("%w: tag for attribute %s does not match with value type", InvalidTagError, attribute)
 		}
 
 		if err := e.encodeTag(tag); err != nil {
@@ -80,7 +108,7 @@ func (e *AttributeEncoder) Encode(attribute string, value interface{}) error {
 		}
 	case int32:
 		if tag != TagInteger && tag != TagEnum {
-			return fmt.Errorf("tag for attribute %s does not match with value type", attribute)
+			return fmt.Errorf("%w: tag for attribute %s does not match with value type", InvalidTagError, attribute)
 		}
 
 		if err := e.encodeTag(tag); err != nil {
@@ -96,7 +124,7 @@ func (e *AttributeEncoder) Encode(attribute string, value interface{}) error {
 		}
 	case int64:
 		if tag != TagInteger && tag != TagEnum {
-			return fmt.Errorf("tag for attribute %s does not match with value type", attribute)
+			return fmt.Errorf("%w: tag for attribute %s does not match with value type", InvalidTagError, attribute)
 		}
 
 		if err := e.encodeTag(tag); err != nil {
@@ -111,8 +139,23 @@ func (e *AttributeEncoder) Encode(attribute string, value interface{}) error {
 			return err
 		}
 	case []int:
+		if tag == TagDate {
+			if err := e.encodeTag(tag); err != nil {
+				return err
+			}
+
+			if err := e.encodeString(attribute); err != nil {
+				return err
+			}
+
+			if err := e.encodeDate(v); err != nil {
+				return err
+			}
+			break
+		}
+
 		if tag != TagInteger && tag != TagEnum {
-			return fmt.Errorf("tag for attribute %s does not match with value type", attribute)
+			return fmt.Errorf("%w: tag for attribute %s does not match with value type", InvalidTagError, attribute)
 		}
 
 		for index, val := range v {
@@ -136,7 +179,7 @@ func (e *AttributeEncoder) Encode(attribute string, value interface{}) error {
 		}
 	case []int16:
 		if tag != TagInteger && tag != TagEnum {
-			return fmt.Errorf("tag for attribute %s does not match with value type", attribute)
+			return fmt.Errorf("%w: tag for attribute %s does not match with value type", InvalidTagError, attribute)
 		}
 
 		for index, val := range v {
@@ -160,7 +203,7 @@ func (e *AttributeEncoder) Encode(attribute string, value interface{}) error {
 		}
 	case []int8:
 		if tag != TagInteger && tag != TagEnum {
-			return fmt.Errorf("tag for attribute %s does not match with value type", attribute)
+			return fmt.Errorf("%w: tag for attribute %s does not match with value type", InvalidTagError, attribute)
 		}
 
 		for index, val := range v {
@@ -183,8 +226,23 @@ func (e *AttributeEncoder) Encode(attribute string, value interface{}) error {
 			}
 		}
 	case []int32:
+		if tag == TagRange && len(v) == 2 {
+			if err := e.encodeTag(tag); err != nil {
+				return err
+			}
+
+			if err := e.encodeString(attribute); err != nil {
+				return err
+			}
+
+			if err := e.encodeRange(v[0], v[1]); err != nil {
+				return err
+			}
+			break
+		}
+
 		if tag != TagInteger && tag != TagEnum {
-			return fmt.Errorf("tag for attribute %s does not match with value type", attribute)
+			return fmt.Errorf("%w: tag for attribute %s does not match with value type", InvalidTagError, attribute)
 		}
 
 		for index, val := range v {
@@ -208,7 +266,7 @@ func (e *AttributeEncoder) Encode(attribute string, value interface{}) error {
 		}
 	case []int64:
 		if tag != TagInteger && tag != TagEnum {
-			return fmt.Errorf("tag for attribute %s does not match with value type", attribute)
+			return fmt.Errorf("%w: tag for attribute %s does not match with value type", InvalidTagError, attribute)
 		}
 
 		for index, val := range v {
@@ -232,7 +290,7 @@ func (e *AttributeEncoder) Encode(attribute string, value interface{}) error {
 		}
 	case bool:
 		if tag != TagBoolean {
-			return fmt.Errorf("tag for attribute %s does not match with value type", attribute)
+			return fmt.Errorf("%w: tag for attribute %s does not match with value type", InvalidTagError, attribute)
 		}
 
 		if err := e.encodeTag(tag); err != nil {
@@ -248,7 +306,7 @@ func (e *AttributeEncoder) Encode(attribute string, value interface{}) error {
 		}
 	case []bool:
 		if tag != TagBoolean {
-			return fmt.Errorf("tag for attribute %s does not match with value type", attribute)
+			return fmt.Errorf("%w: tag for attribute %s does not match with value type", InvalidTagError, attribute)
 		}
 
 		for index, val := range v {
@@ -270,6 +328,22 @@ func (e *AttributeEncoder) Encode(attribute string, value interface{}) error {
 				return err
 			}
 		}
+	case Resolution:
+		if tag != TagResolution {
+			return fmt.Errorf("%w: tag for attribute %s does not match with value type", InvalidTagError, attribute)
+		}
+
+		if err := e.encodeTag(tag); err != nil {
+			return err
+		}
+
+		if err := e.encodeString(attribute); err != nil {
+			return err
+		}
+
+		if err := e.encodeResolution(v); err != nil {
+			return err
+		}
 	case string:
 		if err := e.encodeTag(tag); err != nil {
 			return err
@@ -302,6 +376,44 @@ func (e *AttributeEncoder) Encode(attribute string, value interface{}) error {
 				return err
 			}
 		}
+	case Attributes:
+		if tag != TagBeginCollection {
+			return fmt.Errorf("%w: tag for attribute %s does not match with value type", InvalidTagError, attribute)
+		}
+
+		if err := e.encodeTag(tag); err != nil {
+			return err
+		}
+
+		if err := e.encodeString(attribute); err != nil {
+			return err
+		}
+
+		if err := e.encodeCollection(v); err != nil {
+			return err
+		}
+	case []Attributes:
+		for index, val := range v {
+			name := attribute
+			if index > 0 {
+				name = ""
+			}
+
+			if err := e.EncodeWithTag(name, tag, val); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for index, val := range v {
+			name := attribute
+			if index > 0 {
+				name = ""
+			}
+
+			if err := e.EncodeWithTag(name, tag, val); err != nil {
+				return err
+			}
+		}
 	default:
 		return fmt.Errorf("type %T is not supported", value)
 	}
@@ -310,36 +422,140 @@ func (e *AttributeEncoder) Encode(attribute string, value interface{}) error {
 }
 
 func (e *AttributeEncoder) encodeString(s string) error {
-	if err := binary.Write(e.writer, binary.BigEndian, int16(len(s))); err != nil {
+	if err := e.writeInt16(int16(len(s))); err != nil {
 		return err
 	}
 
-	_, err := e.writer.Write([]byte(s))
+	_, err := io.WriteString(e.writer, s)
 	return err
 }
 
 func (e *AttributeEncoder) encodeInteger(i int32) error {
-	if err := binary.Write(e.writer, binary.BigEndian, sizeInteger); err != nil {
+	binary.BigEndian.PutUint16(e.scratch[0:], uint16(sizeInteger))
+	binary.BigEndian.PutUint32(e.scratch[2:], uint32(i))
+
+	return e.write(e.scratch[:2+sizeInteger])
+}
+
+func (e *AttributeEncoder) encodeBoolean(b bool) error {
+	binary.BigEndian.PutUint16(e.scratch[0:], uint16(sizeBoolean))
+	e.scratch[2] = 0
+	if b {
+		e.scratch[2] = 1
+	}
+
+	return e.write(e.scratch[:2+sizeBoolean])
+}
+
+func (e *AttributeEncoder) encodeDate(d []int) error {
+	if err := e.writeInt16(int16(len(d))); err != nil {
 		return err
 	}
 
-	return binary.Write(e.writer, binary.BigEndian, i)
+	for _, i := range d {
+		e.scratch[0] = byte(i)
+		if err := e.write(e.scratch[:1]); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (e *AttributeEncoder) encodeBoolean(b bool) error {
-	if err := binary.Write(e.writer, binary.BigEndian, sizeBoolean); err != nil {
+func (e *AttributeEncoder) encodeRange(lower, upper int32) error {
+	binary.BigEndian.PutUint16(e.scratch[0:], uint16(sizeRange))
+	binary.BigEndian.PutUint32(e.scratch[2:], uint32(lower))
+	binary.BigEndian.PutUint32(e.scratch[6:], uint32(upper))
+
+	return e.write(e.scratch[:2+sizeRange])
+}
+
+func (e *AttributeEncoder) encodeResolution(r Resolution) error {
+	binary.BigEndian.PutUint16(e.scratch[0:], uint16(sizeResolution))
+	binary.BigEndian.PutUint32(e.scratch[2:], uint32(r.Height))
+	binary.BigEndian.PutUint32(e.scratch[6:], uint32(r.Width))
+	e.scratch[10] = byte(r.Depth)
+
+	return e.write(e.scratch[:2+sizeResolution])
+}
+
+// encodeCollection encodes the members of a collection after the begCollection value. the members are ordered by
+// name and each member value is encoded with its tag, or the tag of the AttributeTagMapping map if not set
+func (e *AttributeEncoder) encodeCollection(c Attributes) error {
+	// the begCollection value is empty
+	if err := e.writeNullByte(); err != nil {
 		return err
 	}
 
-	return binary.Write(e.writer, binary.BigEndian, b)
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := e.encodeTag(TagMemberName); err != nil {
+			return err
+		}
+		if err := e.writeNullByte(); err != nil {
+			return err
+		}
+		if err := e.encodeString(name); err != nil {
+			return err
+		}
+
+		for _, v := range c[name] {
+			tag := v.Tag
+			if tag == TagZero {
+				var ok bool
+				if tag, ok = AttributeTagMapping[name]; !ok {
+					return fmt.Errorf("%w: cannot get tag of collection member %s", InvalidTagError, name)
+				}
+			}
+
+			if err := e.EncodeWithTag("", tag, v.Value); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := e.encodeTag(TagEndCollection); err != nil {
+		return err
+	}
+	if err := e.writeNullByte(); err != nil {
+		return err
+	}
+
+	return e.writeNullByte()
+}
+
+// encodeHeader encodes the version, the operation or status code and the request id of a message
+func (e *AttributeEncoder) encodeHeader(major, minor int8, code int16, requestID int32) error {
+	e.scratch[0] = byte(major)
+	e.scratch[1] = byte(minor)
+	binary.BigEndian.PutUint16(e.scratch[2:], uint16(code))
+	binary.BigEndian.PutUint32(e.scratch[4:], uint32(requestID))
+
+	return e.write(e.scratch[:8])
 }
 
 func (e *AttributeEncoder) encodeTag(t int8) error {
-	return binary.Write(e.writer, binary.BigEndian, t)
+	e.scratch[0] = byte(t)
+	return e.write(e.scratch[:1])
 }
 
 func (e *AttributeEncoder) writeNullByte() error {
-	return binary.Write(e.writer, binary.BigEndian, int16(0))
+	return e.writeInt16(0)
+}
+
+func (e *AttributeEncoder) writeInt16(i int16) error {
+	binary.BigEndian.PutUint16(e.scratch[:], uint16(i))
+	return e.write(e.scratch[:2])
+}
+
+func (e *AttributeEncoder) write(b []byte) error {
+	_, err := e.writer.Write(b)
+	return err
 }
 
 // Attribute defines an ipp attribute
@@ -359,119 +575,343 @@ type Resolution struct {
 // AttributeDecoder reads and decodes ipp from an input stream
 type AttributeDecoder struct {
 	reader io.Reader
+	// scratch holds the bytes of a fixed size value and buf the bytes of a string, both are reused for all values
+	scratch [4]byte
+	buf     []byte
+	// values interns keyword like values if set, they are kept as interface values to save the allocation of
+	// converting a string into an interface as well
+	values map[string]interface{}
+	// warn receives the tolerated deviations, attribute is the name of the attribute being decoded
+	warn      func(Warning)
+	attribute string
+	profile   DecodeProfile
 }
 
 // NewAttributeDecoder returns a new decoder that reads from r
 func NewAttributeDecoder(r io.Reader) *AttributeDecoder {
-	return &AttributeDecoder{r}
+	return &AttributeDecoder{reader: r}
+}
+
+// InternValues makes the decoder return repeated keyword, charset, natural language, mime type and uri scheme values
+// as the same string instead of allocating each value, e.g. the job-state-reasons of the jobs of a Get-Jobs
+// response. the strings are kept until the decoder is released, so a decoder should not be used for an unbounded
+// stream of messages with this option
+func (d *AttributeDecoder) InternValues() {
+	if d.values == nil {
+		d.values = make(map[string]interface{})
+	}
+}
+
+// OnWarning sets a function which receives the deviations from the specification the decoder tolerates, e.g. an
+// integer value with a length other than 4 or a value tag not matching the syntax of a known attribute
+func (d *AttributeDecoder) OnWarning(fn func(Warning)) {
+	d.warn = fn
+}
+
+// SetProfile selects how deviations from rfc 8011 are handled, ProfileLenient reports them to the warning function
+// and ProfileStrict fails with a ConformanceError
+func (d *AttributeDecoder) SetProfile(profile DecodeProfile) {
+	d.profile = profile
+}
+
+// checking reports whether deviations are of interest, they are not checked otherwise to save the time
+func (d *AttributeDecoder) checking() bool {
+	return d.warn != nil || d.profile == ProfileStrict
+}
+
+// deviation reports a deviation of the current attribute, it is an error with the strict profile
+func (d *AttributeDecoder) deviation(format string, args ...interface{}) error {
+	return d.report(Warning{Attribute: d.attribute, Message: fmt.Sprintf(format, args...)})
+}
+
+// messageDeviation reports a deviation of the message, it is an error with the strict profile
+func (d *AttributeDecoder) messageDeviation(format string, args ...interface{}) error {
+	return d.report(Warning{Message: fmt.Sprintf(format, args...)})
+}
+
+// checkOperationAttribute reports operation attributes out of the order of rfc 8011, attributes-charset and
+// attributes-natural-language must come first
+func (d *AttributeDecoder) checkOperationAttribute(index int, name string) error {
+	switch {
+	case index == 0 && name != AttributeCharset:
+		return d.messageDeviation("the first operation attribute is %s instead of %s", name, AttributeCharset)
+	case index == 1 && name != AttributeNaturalLanguage:
+		return d.messageDeviation("the second operation attribute is %s instead of %s", name, AttributeNaturalLanguage)
+	}
+
+	return nil
+}
+
+// checkMessage reports the deviations found at the end of the attributes of a message
+func (d *AttributeDecoder) checkMessage(operationAttributes int, endTag bool) error {
+	if !endTag {
+		if err := d.messageDeviation("message without end-of-attributes tag"); err != nil {
+			return err
+		}
+	}
+	if operationAttributes < 2 {
+		return d.messageDeviation("the operation attributes lack %s or %s", AttributeCharset, AttributeNaturalLanguage)
+	}
+
+	return nil
+}
+
+func (d *AttributeDecoder) report(warning Warning) error {
+	if d.profile == ProfileStrict {
+		return fmt.Errorf("%w: %s", ConformanceError, warning)
+	}
+	if d.warn != nil {
+		d.warn(warning)
+	}
+
+	return nil
 }
 
 // Decode reads the next ipp attribute into a attribute struct. the type is identified by a tag passed as an argument
 func (d *AttributeDecoder) Decode(tag int8) (*Attribute, error) {
+	attr, err := d.decode(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &attr, nil
+}
+
+// decode reads the next attribute like Decode, the attribute is returned by value to save an allocation
+func (d *AttributeDecoder) decode(tag int8) (Attribute, error) {
+	if tag < TagUnsupportedValue {
+		return Attribute{}, fmt.Errorf("%w: delimiter tag 0x%02x in place of a value", InvalidTagError, uint8(tag))
+	}
+
 	attr := Attribute{Tag: tag}
 
-	name, err := d.decodeString()
+	name, err := d.decodeName()
 	if err != nil {
-		return nil, err
+		return Attribute{}, err
 	}
 	attr.Name = name
+	if name != "" && d.checking() {
+		d.attribute = name
+		if expected, ok := AttributeTagMapping[name]; ok && !isOutOfBand(tag) && tagClass(tag) != tagClass(expected) {
+			if err := d.deviation("value tag 0x%02x instead of 0x%02x", uint8(tag), uint8(expected)); err != nil {
+				return Attribute{}, err
+			}
+		}
+	}
 
 	switch attr.Tag {
 	case TagEnum, TagInteger:
 		val, err := d.decodeInteger()
 		if err != nil {
-			return nil, err
+			return Attribute{}, err
 		}
 		attr.Value = val
 	case TagBoolean:
 		val, err := d.decodeBool()
 		if err != nil {
-			return nil, err
+			return Attribute{}, err
 		}
 		attr.Value = val
 	case TagDate:
 		val, err := d.decodeDate()
 		if err != nil {
-			return nil, err
+			return Attribute{}, err
 		}
 		attr.Value = val
 	case TagRange:
 		val, err := d.decodeRange()
 		if err != nil {
-			return nil, err
+			return Attribute{}, err
 		}
 		attr.Value = val
 	case TagResolution:
 		val, err := d.decodeResolution()
 		if err != nil {
-			return nil, err
+			return Attribute{}, err
+		}
+		attr.Value = val
+	case TagBeginCollection:
+		val, err := d.decodeCollection()
+		if err != nil {
+			return Attribute{}, err
+		}
+		attr.Value = val
+	case TagMemberName:
+		val, err := d.decodeName()
+		if err != nil {
+			return Attribute{}, err
+		}
+		attr.Value = val
+	case TagKeyword, TagCharset, TagLanguage, TagMimeType, TagUriScheme:
+		val, err := d.decodeKeyword()
+		if err != nil {
+			return Attribute{}, err
 		}
 		attr.Value = val
 	default:
 		val, err := d.decodeString()
 		if err != nil {
-			return nil, err
+			return Attribute{}, err
 		}
 		attr.Value = val
 	}
 
-	return &attr, nil
+	if limit := valueLimits[attr.Tag]; limit > 0 && d.checking() {
+		if value, ok := attr.Value.(string); ok && len(value) > limit {
+			if err := d.deviation("value of %d octets exceeds the limit of %d", len(value), limit); err != nil {
+				return Attribute{}, err
+			}
+		}
+	}
+
+	return attr, nil
 }
 
-func (d *AttributeDecoder) decodeBool() (b bool, err error) {
-	if _, err = d.readValueLength(); err != nil {
-		return
+func (d *AttributeDecoder) decodeBool() (bool, error) {
+	length, err := d.readValueLength()
+	if err != nil {
+		return false, err
+	}
+	if length != 1 {
+		// a boolean of another length is true if any byte is set
+		i, err := d.readVariableInteger("boolean", length)
+		return i != 0, err
 	}
 
-	if err = binary.Read(d.reader, binary.BigEndian, &b); err != nil {
-		return
+	b, err := d.readInt8()
+	return b != 0, err
+}
+
+func (d *AttributeDecoder) decodeInteger() (int, error) {
+	length, err := d.readValueLength()
+	if err != nil {
+		return 0, err
+	}
+	if length != 4 {
+		i, err := d.readVariableInteger("integer", length)
+		return int(i), err
 	}
 
-	return
+	i, err := d.readInt32()
+	return int(i), err
 }
 
-func (d *AttributeDecoder) decodeInteger() (i int, err error) {
-	if _, err = d.readValueLength(); err != nil {
-		return
+// readVariableInteger reads a signed big endian integer of up to 4 bytes, the value of a boolean, integer or enum
+// with an unexpected length
+func (d *AttributeDecoder) readVariableInteger(syntax string, length int16) (int32, error) {
+	if length < 0 || length > 4 {
+		return 0, fmt.Errorf("invalid %s value length %d", syntax, length)
+	}
+	if err := d.deviation("%s value with length %d", syntax, length); err != nil {
+		return 0, err
 	}
 
-	var reti int32
-	if err = binary.Read(d.reader, binary.BigEndian, &reti); err != nil {
-		return
+	b, err := d.read(int(length))
+	if err != nil {
+		return 0, err
+	}
+
+	var i int32
+	for _, c := range b {
+		i = i<<8 | int32(c)
+	}
+	if length > 0 {
+		// extend the sign of the shorter value
+		shift := 32 - 8*int(length)
+		i = i << shift >> shift
 	}
 
-	return int(reti), nil
+	return i, nil
 }
 
 func (d *AttributeDecoder) decodeString() (string, error) {
-	length, err := d.readValueLength()
+	bs, err := d.readString()
 	if err != nil {
 		return "", err
 	}
 
-	if length == 0 {
-		return "", nil
+	return string(bs), nil
+}
+
+// decodeName decodes an attribute or member name, the names of the AttributeTagMapping are returned without
+// allocation
+func (d *AttributeDecoder) decodeName() (string, error) {
+	bs, err := d.readString()
+	if err != nil {
+		return "", err
 	}
 
-	bs := make([]byte, length)
-	if _, err := d.reader.Read(bs); err != nil {
-		return "", nil
+	if name, ok := attributeNames[string(bs)]; ok {
+		return name, nil
 	}
 
 	return string(bs), nil
 }
 
+// decodeKeyword decodes a keyword like value, repeated values are returned as the same string if the decoder interns
+// values
+func (d *AttributeDecoder) decodeKeyword() (interface{}, error) {
+	bs, err := d.readString()
+	if err != nil {
+		return nil, err
+	}
+
+	if d.values == nil {
+		return string(bs), nil
+	}
+
+	if value, ok := d.values[string(bs)]; ok {
+		return value, nil
+	}
+
+	var value interface{} = string(bs)
+	d.values[value.(string)] = value
+
+	return value, nil
+}
+
+// readString reads a length prefixed string into the buffer of the decoder, the bytes are only valid until the next
+// read
+func (d *AttributeDecoder) readString() ([]byte, error) {
+	length, err := d.readValueLength()
+	if err != nil {
+		return nil, err
+	}
+
+	if length == 0 {
+		return nil, nil
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("invalid value length %d", length)
+	}
+
+	if cap(d.buf) < int(length) {
+		d.buf = make([]byte, length)
+	}
+	bs := d.buf[:length]
+	if _, err := io.ReadFull(d.reader, bs); err != nil {
+		return nil, readError(err)
+	}
+
+	return bs, nil
+}
+
 func (d *AttributeDecoder) decodeDate() ([]int, error) {
 	length, err := d.readValueLength()
 	if err != nil {
 		return nil, err
 	}
+	if length != 11 {
+		if err := d.deviation("dateTime value with length %d", length); err != nil {
+			return nil, err
+		}
+	}
 
-	is := make([]int, length)
-	var ti int8
+	is := make([]int, max(length, 0))
 
-	for i := int16(0); i < length; i++ {
-		if err = binary.Read(d.reader, binary.BigEndian, &ti); err != nil {
+	for i := range is {
+		ti, err := d.readInt8()
+		if err != nil {
 			return nil, err
 		}
 		is[i] = int(ti)
@@ -485,44 +925,133 @@ func (d *AttributeDecoder) decodeRange() ([]int32, error) {
 	if err != nil {
 		return nil, err
 	}
+	if length != 8 {
+		if err := d.deviation("rangeOfInteger value with length %d", length); err != nil {
+			return nil, err
+		}
+	}
 
 	// initialize range element count (c) and range slice (r)
-	c := length / 4
+	c := max(length/4, 0)
 	r := make([]int32, c)
 
-	for i := int16(0); i < c; i++ {
-		var ti int32
-		if err = binary.Read(d.reader, binary.BigEndian, &ti); err != nil {
+	for i := range r {
+		if r[i], err = d.readInt32(); err != nil {
 			return nil, err
 		}
-		r[i] = ti
 	}
 
 	return r, nil
 }
 
 func (d *AttributeDecoder) decodeResolution() (res Resolution, err error) {
-	_, err = d.readValueLength()
+	length, err := d.readValueLength()
 	if err != nil {
 		return
 	}
+	if length < 9 {
+		return res, fmt.Errorf("invalid resolution value length %d", length)
+	}
 
-	if err = binary.Read(d.reader, binary.BigEndian, &res.Height); err != nil {
+	if res.Height, err = d.readInt32(); err != nil {
 		return
 	}
 
-	if err = binary.Read(d.reader, binary.BigEndian, &res.Width); err != nil {
+	if res.Width, err = d.readInt32(); err != nil {
 		return
 	}
 
-	if err = binary.Read(d.reader, binary.BigEndian, &res.Depth); err != nil {
+	if res.Depth, err = d.readInt8(); err != nil {
 		return
 	}
 
+	if length > 9 {
+		if err = d.deviation("resolution value with length %d", length); err != nil {
+			return
+		}
+		if _, err = io.CopyN(io.Discard, d.reader, int64(length-9)); err != nil {
+			err = readError(err)
+		}
+	}
+
 	return
 }
 
-func (d *AttributeDecoder) readValueLength() (length int16, err error) {
-	err = binary.Read(d.reader, binary.BigEndian, &length)
-	return
+// decodeCollection decodes the members of a collection up to the endCollection value
+func (d *AttributeDecoder) decodeCollection() (Attributes, error) {
+	// skip the empty begCollection value
+	if _, err := d.readString(); err != nil {
+		return nil, err
+	}
+
+	c := make(Attributes)
+	member := ""
+
+	for {
+		tag, err := d.readInt8()
+		if err != nil {
+			return nil, err
+		}
+
+		attr, err := d.decode(tag)
+		if err != nil {
+			return nil, err
+		}
+
+		switch tag {
+		case TagEndCollection:
+			return c, nil
+		case TagMemberName:
+			member, _ = attr.Value.(string)
+			if member == "" {
+				return nil, errors.New("collection member without name")
+			}
+		default:
+			if member == "" {
+				return nil, errors.New("collection value without member name")
+			}
+			attr.Name = member
+			c[member] = append(c[member], attr)
+		}
+	}
+}
+
+func (d *AttributeDecoder) readValueLength() (int16, error) {
+	return d.readInt16()
+}
+
+func (d *AttributeDecoder) readInt16() (int16, error) {
+	b, err := d.read(2)
+	if err != nil {
+		return 0, err
+	}
+
+	return int16(binary.BigEndian.Uint16(b)), nil
+}
+
+func (d *AttributeDecoder) readInt8() (int8, error) {
+	b, err := d.read(1)
+	if err != nil {
+		return 0, err
+	}
+
+	return int8(b[0]), nil
+}
+
+func (d *AttributeDecoder) readInt32() (int32, error) {
+	b, err := d.read(4)
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(binary.BigEndian.Uint32(b)), nil
+}
+
+// read reads n bytes into the scratch space, a truncated value is reported as ShortReadError
+func (d *AttributeDecoder) read(n int) ([]byte, error) {
+	if _, err := io.ReadFull(d.reader, d.scratch[:n]); err != nil {
+		return nil, readError(err)
+	}
+
+	return d.scratch[:n], nil
 }
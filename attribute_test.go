@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"unsafe"
 )
 
 var attributeTestCases = []struct {
@@ -41,6 +42,23 @@ var attributeTestCases = []struct {
 		Value:     3,
 		Bytes:     []byte("\x23\x00\x0dprinter-state\x00\x04\x00\x00\x00\x03"),
 	},
+	{
+		Attribute: "media-col",
+		Value: Attributes{
+			"media-size": {{Tag: TagBeginCollection, Name: "media-size", Value: Attributes{
+				"x-dimension": {{Tag: TagInteger, Name: "x-dimension", Value: 21000}},
+				"y-dimension": {{Tag: TagInteger, Name: "y-dimension", Value: 29700}},
+			}}},
+			"media-source": {{Tag: TagKeyword, Name: "media-source", Value: "tray-1"}},
+		},
+		Bytes: []byte("\x34\x00\x09media-col\x00\x00" +
+			"\x4a\x00\x00\x00\x0amedia-size\x34\x00\x00\x00\x00" +
+			"\x4a\x00\x00\x00\x0bx-dimension\x21\x00\x00\x00\x04\x00\x00\x52\x08" +
+			"\x4a\x00\x00\x00\x0by-dimension\x21\x00\x00\x00\x04\x00\x00\x74\x04" +
+			"\x37\x00\x00\x00\x00" +
+			"\x4a\x00\x00\x00\x0cmedia-source\x44\x00\x00\x00\x06tray-1" +
+			"\x37\x00\x00\x00\x00"),
+	},
 }
 
 func TestAttributeDecoder_Decode(t *testing.T) {
@@ -71,3 +89,39 @@ func TestAttributeEncoder_Encode(t *testing.T) {
 		buf.Reset()
 	}
 }
+
+func TestAttributeDecoder_InternValues(t *testing.T) {
+	buf := new(bytes.Buffer)
+	enc := NewAttributeEncoder(buf)
+	for i := 0; i < 2; i++ {
+		assert.Nil(t, enc.EncodeWithTag(AttributeJobStateReasons, TagKeyword, "job-completed-successfully"))
+	}
+	data := buf.Bytes()
+
+	decode := func(intern bool) (*Attribute, *Attribute) {
+		dec := NewAttributeDecoder(bytes.NewReader(data[1:]))
+		if intern {
+			dec.InternValues()
+		}
+
+		first, err := dec.Decode(int8(data[0]))
+		assert.Nil(t, err)
+		var tag [1]byte
+		_, err = dec.reader.Read(tag[:])
+		assert.Nil(t, err)
+		second, err := dec.Decode(int8(tag[0]))
+		assert.Nil(t, err)
+
+		return first, second
+	}
+
+	first, second := decode(true)
+	assert.Equal(t, "job-completed-successfully", second.Value)
+	assert.Same(t, unsafe.StringData(first.Value.(string)), unsafe.StringData(second.Value.(string)))
+	// known attribute names are never allocated
+	assert.Same(t, unsafe.StringData(AttributeJobStateReasons), unsafe.StringData(first.Name))
+
+	first, second = decode(false)
+	assert.Equal(t, first.Value, second.Value)
+	assert.NotSame(t, unsafe.StringData(first.Value.(string)), unsafe.StringData(second.Value.(string)))
+}
@@ -0,0 +1,38 @@
+package ipp
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBufferSize limits the buffers kept for reuse, the buffers of messages with large attributes are released
+const maxPooledBufferSize = 64 << 10
+
+// encodeBuffer is the buffer and attribute encoder of a message, they are reused for the next message
+type encodeBuffer struct {
+	bytes.Buffer
+	encoder AttributeEncoder
+}
+
+var encodeBufferPool = sync.Pool{
+	New: func() interface{} {
+		buf := new(encodeBuffer)
+		buf.encoder.writer = &buf.Buffer
+		return buf
+	},
+}
+
+// getEncodeBuffer returns an empty buffer from the pool
+func getEncodeBuffer() *encodeBuffer {
+	return encodeBufferPool.Get().(*encodeBuffer)
+}
+
+// putEncodeBuffer returns the buffer to the pool, the encoded bytes must not be used afterwards
+func putEncodeBuffer(buf *encodeBuffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+
+	buf.Reset()
+	encodeBufferPool.Put(buf)
+}
@@ -0,0 +1,132 @@
+package ipp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultBatchConcurrency is the default number of jobs submitted at the same time by PrintAll
+const DefaultBatchConcurrency = 4
+
+// PrintRequest is a job submitted by PrintAll. a single document is printed with Print-Job, multiple documents with
+// Create-Job and Send-Document
+type PrintRequest struct {
+	Printer       string
+	Documents     []Document
+	JobAttributes map[string]interface{}
+}
+
+// BatchOptions configure PrintAll
+type BatchOptions struct {
+	// Concurrency limits the number of jobs submitted at the same time, defaults to DefaultBatchConcurrency
+	Concurrency int
+	// StopOnError stops submitting further jobs after the first failed job
+	StopOnError bool
+}
+
+// BatchResult is the result of a job submitted by PrintAll. Job is nil if Err is set
+type BatchResult struct {
+	Job *Job
+	Err error
+}
+
+// BatchError is returned by PrintAll if at least one job failed
+type BatchError struct {
+	// Failed is the number of failed jobs
+	Failed int
+	// Total is the number of jobs of the batch
+	Total int
+	// Errors are the errors of the failed jobs in the order of the jobs
+	Errors []error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%d of %d jobs failed, first error: %v", e.Failed, e.Total, e.Errors[0])
+}
+
+// Unwrap returns the errors of the failed jobs to match them with errors.Is and errors.As
+func (e *BatchError) Unwrap() []error {
+	return e.Errors
+}
+
+// PrintAll submits the jobs concurrently with at most Concurrency jobs at the same time. the results are returned in
+// the order of the jobs, the error is a *BatchError if any job failed. jobs which are not submitted before the context
+// is done or after a failed job with StopOnError get the error of the context or context.Canceled, jobs which are
+// already submitted are not interrupted. the client is shared by all jobs, the documents of the jobs must not be shared
+func (c *IPPClient) PrintAll(ctx context.Context, jobs []PrintRequest, opts BatchOptions) ([]BatchResult, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultBatchConcurrency
+	}
+
+	results := make([]BatchResult, len(jobs))
+	slots := make(chan struct{}, opts.Concurrency)
+
+	// stop cancels the submission of further jobs after a failed job with StopOnError
+	stop := func() {}
+	if opts.StopOnError {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		stop = cancel
+	}
+
+	var wg sync.WaitGroup
+	for i := range jobs {
+		// a free slot must not win over a done context
+		err := ctx.Err()
+		if err == nil {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+		}
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			results[i] = c.printRequest(jobs[i])
+			if results[i].Err != nil {
+				stop()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	batchErr := &BatchError{Total: len(jobs)}
+	for _, result := range results {
+		if result.Err != nil {
+			batchErr.Failed++
+			batchErr.Errors = append(batchErr.Errors, result.Err)
+		}
+	}
+	if batchErr.Failed > 0 {
+		return results, batchErr
+	}
+
+	return results, nil
+}
+
+// printRequest submits a job of PrintAll
+func (c *IPPClient) printRequest(job PrintRequest) BatchResult {
+	var result BatchResult
+
+	switch len(job.Documents) {
+	case 0:
+		result.Err = fmt.Errorf("job for printer %s has no documents", job.Printer)
+	case 1:
+		result.Job, result.Err = c.SubmitJob(job.Documents[0], job.Printer, job.JobAttributes)
+	default:
+		result.Job, result.Err = c.SubmitDocuments(job.Documents, job.Printer, job.JobAttributes)
+	}
+
+	return result
+}
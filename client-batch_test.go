@@ -0,0 +1,107 @@
+package ipp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// batchAdapter answers job requests concurrently and fails all requests for the printer broken
+type batchAdapter struct {
+	active, maxActive int32
+	jobID             int32
+
+	mu         sync.Mutex
+	operations []int16
+}
+
+func (a *batchAdapter) SendRequest(url string, req *Request, additionalResponseData io.Writer) (*Response, error) {
+	n := atomic.AddInt32(&a.active, 1)
+	defer atomic.AddInt32(&a.active, -1)
+	for {
+		m := atomic.LoadInt32(&a.maxActive)
+		if n <= m || atomic.CompareAndSwapInt32(&a.maxActive, m, n) {
+			break
+		}
+	}
+
+	a.mu.Lock()
+	a.operations = append(a.operations, req.Operation)
+	a.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	if req.OperationAttributes[AttributePrinterURI] == "ipp://localhost/printers/broken" {
+		return nil, HTTPError{Code: 503}
+	}
+
+	resp := NewResponse(StatusOk, req.RequestId)
+	resp.JobAttributes = append(resp.JobAttributes, Attributes{
+		AttributeJobID: []Attribute{{Tag: TagInteger, Value: int(atomic.AddInt32(&a.jobID, 1))}},
+	})
+	return resp, nil
+}
+
+func (a *batchAdapter) GetHttpUri(namespace string, object interface{}) string {
+	return "http://localhost:631/" + namespace
+}
+
+func (a *batchAdapter) TestConnection() error {
+	return nil
+}
+
+func TestIPPClient_PrintAll(t *testing.T) {
+	adapter := &batchAdapter{}
+	client := NewIPPClientWithAdapter("user", adapter)
+
+	doc := Document{Name: "letter", Size: -1, MimeType: MimeTypeOctetStream}
+	var jobs []PrintRequest
+	for i := 0; i < 10; i++ {
+		jobs = append(jobs, PrintRequest{Printer: "office", Documents: []Document{doc}})
+	}
+	jobs[3].Printer = "broken"
+	jobs[5].Documents = []Document{doc, doc}
+	jobs[7].Documents = nil
+
+	results, err := client.PrintAll(context.Background(), jobs, BatchOptions{Concurrency: 3})
+	assert.Len(t, results, len(jobs))
+	assert.LessOrEqual(t, atomic.LoadInt32(&adapter.maxActive), int32(3))
+	assert.Greater(t, atomic.LoadInt32(&adapter.maxActive), int32(1))
+
+	var batchErr *BatchError
+	assert.True(t, errors.As(err, &batchErr))
+	assert.Equal(t, 2, batchErr.Failed)
+	assert.Equal(t, 10, batchErr.Total)
+	assert.True(t, errors.Is(err, HTTPError{Code: 503}))
+
+	for i, result := range results {
+		if i == 3 || i == 7 {
+			assert.NotNil(t, result.Err, i)
+			assert.Nil(t, result.Job, i)
+			continue
+		}
+		assert.Nil(t, result.Err, i)
+		assert.NotZero(t, result.Job.ID, i)
+	}
+	// one Create-Job and two Send-Document requests for the job with two documents
+	assert.Contains(t, adapter.operations, OperationCreateJob)
+
+	// no further jobs are submitted after the first failed job
+	adapter.operations = nil
+	results, err = client.PrintAll(context.Background(), jobs[3:], BatchOptions{Concurrency: 1, StopOnError: true})
+	assert.NotNil(t, err)
+	assert.Len(t, adapter.operations, 1)
+	for _, result := range results[1:] {
+		assert.ErrorIs(t, result.Err, context.Canceled)
+	}
+
+	results, err = client.PrintAll(context.Background(), nil, BatchOptions{})
+	assert.Nil(t, err)
+	assert.Empty(t, results)
+}
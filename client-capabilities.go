@@ -0,0 +1,126 @@
+package ipp
+
+import (
+	"errors"
+	"maps"
+)
+
+// capabilityKey identifies the cached capabilities of a printer for a document format
+type capabilityKey struct {
+	printer string
+	format  string
+}
+
+// capabilities are the cached printer attributes of a response to Get-Printer-Attributes
+type capabilities struct {
+	attributes  Attributes
+	unsupported Attributes
+}
+
+// PrinterCapabilities returns all attributes of the printer in typed form as they apply to the document format, e.g.
+// the resolutions supported for pwg raster differ from the ones for pdf. an empty format returns the capabilities
+// for all formats. the capabilities are requested once per printer and format and cached until
+// ResetPrinterCapabilities is called, the operations-supported of the response replace the cached operations of the
+// printer. the attributes the printer returned to GetUserPrinterAttributes replace the ones of the printer for all
+// formats, so the capabilities contain the defaults and restrictions of the user
+func (c *IPPClient) PrinterCapabilities(printer, format string) (*PrinterDescription, error) {
+	key := capabilityKey{printer: printer, format: format}
+
+	c.capabilitiesMu.Lock()
+	cached, ok := c.capabilities[key]
+	user := c.userAttributes[printer]
+	c.capabilitiesMu.Unlock()
+	if ok {
+		return cached.description(user)
+	}
+
+	req := NewRequest(OperationGetPrinterAttributes, 1, WithPrinterURI(c.getPrinterUri(printer)),
+		WithRequestedAttributes("all"))
+	if format != "" {
+		WithDocumentFormat(format)(req)
+	}
+
+	resp, err := c.SendRequest(c.adapter.GetHttpUri("printers", printer), req, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.PrinterAttributes) == 0 {
+		return nil, errors.New("server doesn't return any printer attributes")
+	}
+
+	cached = &capabilities{
+		attributes:  resp.PrinterAttributes[0],
+		unsupported: mergeUnsupported(nil, resp.UnsupportedAttributes),
+	}
+	d, err := cached.description(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if values := resp.PrinterAttributes[0][AttributeOperationsSupported]; len(values) > 0 {
+		c.cacheOperations(printer, values)
+	}
+
+	c.capabilitiesMu.Lock()
+	if c.capabilities == nil {
+		c.capabilities = make(map[capabilityKey]*capabilities)
+	}
+	c.capabilities[key] = cached
+	c.capabilitiesMu.Unlock()
+
+	return d, nil
+}
+
+// description unmarshals the cached attributes with the attributes of the user in place of the ones of the printer
+func (c *capabilities) description(user Attributes) (*PrinterDescription, error) {
+	attributes := c.attributes
+	if len(user) > 0 {
+		attributes = maps.Clone(c.attributes)
+		maps.Copy(attributes, user)
+	}
+
+	d := new(PrinterDescription)
+	if err := d.Unmarshal(attributes); err != nil {
+		return nil, err
+	}
+	d.Unsupported = maps.Clone(c.unsupported)
+
+	return d, nil
+}
+
+// cacheUserAttributes merges the attributes of a response to Get-User-Printer-Attributes into the attributes which
+// replace the ones of the printer in its capabilities
+func (c *IPPClient) cacheUserAttributes(printer string, attributes Attributes) {
+	c.capabilitiesMu.Lock()
+	defer c.capabilitiesMu.Unlock()
+
+	if c.userAttributes == nil {
+		c.userAttributes = make(map[string]Attributes)
+	}
+	merged := maps.Clone(c.userAttributes[printer])
+	if merged == nil {
+		merged = make(Attributes, len(attributes))
+	}
+	maps.Copy(merged, attributes)
+	c.userAttributes[printer] = merged
+}
+
+// ResetPrinterCapabilities removes the cached capabilities of the printer for all formats and the attributes of the
+// user, e.g. after the installed options of the printer changed. an empty printer name removes the capabilities of
+// all printers
+func (c *IPPClient) ResetPrinterCapabilities(printer string) {
+	c.capabilitiesMu.Lock()
+	defer c.capabilitiesMu.Unlock()
+
+	if printer == "" {
+		c.capabilities = nil
+		c.userAttributes = nil
+		return
+	}
+	delete(c.userAttributes, printer)
+	for key := range c.capabilities {
+		if key.printer == printer {
+			delete(c.capabilities, key)
+		}
+	}
+}
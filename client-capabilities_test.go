@@ -0,0 +1,97 @@
+package ipp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPPClient_PrinterCapabilities(t *testing.T) {
+	adapter := &testAdapter{}
+	adapter.respond = func(req *Request) *Response {
+		resp := NewResponse(StatusOk, req.RequestId)
+		printer := make(Attributes)
+		printer.Set(AttributeOperationsSupported, TagEnum, int(OperationPrintJob), int(OperationGetPrinterAttributes))
+		switch req.OperationAttributes[AttributeDocumentFormat] {
+		case MimeTypePwgRaster:
+			printer.Set(AttributePrinterResolutionSupported, TagResolution, Resolution{Height: 300, Width: 300, Depth: 3})
+		default:
+			printer.Set(AttributePrinterResolutionSupported, TagResolution, Resolution{Height: 600, Width: 600, Depth: 3},
+				Resolution{Height: 1200, Width: 1200, Depth: 3})
+		}
+		resp.PrinterAttributes = append(resp.PrinterAttributes, printer)
+		return resp
+	}
+	client := NewIPPClientWithAdapter("user", adapter)
+
+	raster, err := client.PrinterCapabilities("office", MimeTypePwgRaster)
+	assert.Nil(t, err)
+	assert.Equal(t, []Resolution{{Height: 300, Width: 300, Depth: 3}}, raster.ResolutionsSupported)
+	assert.Equal(t, MimeTypePwgRaster, adapter.requests[0].OperationAttributes[AttributeDocumentFormat])
+	assert.Equal(t, []string{"all"}, adapter.requests[0].OperationAttributes[AttributeRequestedAttributes])
+
+	all, err := client.PrinterCapabilities("office", "")
+	assert.Nil(t, err)
+	assert.Len(t, all.ResolutionsSupported, 2)
+	assert.NotContains(t, adapter.requests[1].OperationAttributes, AttributeDocumentFormat)
+
+	// the capabilities are cached per format and fill the operations cache
+	raster.ResolutionsSupported = nil
+	raster, err = client.PrinterCapabilities("office", MimeTypePwgRaster)
+	assert.Nil(t, err)
+	assert.Len(t, raster.ResolutionsSupported, 1)
+	supported, err := client.SupportsOperation("office", OperationPrintJob)
+	assert.Nil(t, err)
+	assert.True(t, supported)
+	assert.Len(t, adapter.requests, 2)
+
+	client.ResetPrinterCapabilities("office")
+	_, err = client.PrinterCapabilities("office", MimeTypePwgRaster)
+	assert.Nil(t, err)
+	assert.Len(t, adapter.requests, 3)
+
+	attributes, err := client.QueryPrinterAttributes("office", WithDocumentFormat(MimeTypePwgRaster),
+		WithRequestedAttributes(AttributePrinterResolutionSupported))
+	assert.Nil(t, err)
+	assert.Len(t, attributes[AttributePrinterResolutionSupported], 1)
+}
+
+func TestIPPClient_PrinterCapabilities_User(t *testing.T) {
+	adapter := &testAdapter{}
+	adapter.respond = func(req *Request) *Response {
+		resp := NewResponse(StatusOk, req.RequestId)
+		printer := make(Attributes)
+		printer.Set(AttributeOperationsSupported, TagEnum, int(OperationGetPrinterAttributes),
+			int(OperationGetUserPrinterAttributes))
+		if req.Operation == OperationGetUserPrinterAttributes {
+			printer.Set(AttributeSidesDefault, TagKeyword, SidesTwoSidedLongEdge)
+		} else {
+			printer.Set(AttributeSidesDefault, TagKeyword, SidesOneSided)
+			printer.Set(AttributePrinterMakeAndModel, TagText, "Office Printer")
+		}
+		resp.PrinterAttributes = append(resp.PrinterAttributes, printer)
+		return resp
+	}
+	client := NewIPPClientWithAdapter("user", adapter)
+
+	d, err := client.PrinterCapabilities("office", "")
+	assert.Nil(t, err)
+	assert.Equal(t, SidesOneSided, d.SidesDefault)
+
+	_, err = client.GetUserPrinterAttributes("office", nil)
+	assert.Nil(t, err)
+
+	// the defaults of the user replace the ones of the printer in the cached and in newly requested capabilities
+	for _, format := range []string{"", MimeTypePDF} {
+		d, err = client.PrinterCapabilities("office", format)
+		assert.Nil(t, err)
+		assert.Equal(t, SidesTwoSidedLongEdge, d.SidesDefault)
+		assert.Equal(t, "Office Printer", d.MakeAndModel)
+	}
+	assert.Len(t, adapter.requests, 3)
+
+	client.ResetPrinterCapabilities("office")
+	d, err = client.PrinterCapabilities("office", "")
+	assert.Nil(t, err)
+	assert.Equal(t, SidesOneSided, d.SidesDefault)
+}
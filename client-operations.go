@@ -0,0 +1,140 @@
+package ipp
+
+import (
+	"errors"
+	"slices"
+)
+
+// OperationsSupported returns the operations-supported of the printer. the operations are requested once with
+// Get-Printer-Attributes and cached until ResetOperationsSupported is called
+func (c *IPPClient) OperationsSupported(printer string) ([]int16, error) {
+	c.operationsMu.Lock()
+	operations, ok := c.operations[printer]
+	c.operationsMu.Unlock()
+	if ok {
+		return operations, nil
+	}
+
+	attributes, err := c.GetPrinterAttributes(printer, []string{AttributeOperationsSupported})
+	if err != nil {
+		return nil, err
+	}
+
+	values := attributes[AttributeOperationsSupported]
+	if len(values) == 0 {
+		return nil, errors.New("server doesn't return operations-supported")
+	}
+
+	return c.cacheOperations(printer, values), nil
+}
+
+// cacheOperations stores the operations-supported values as the operations of the printer
+func (c *IPPClient) cacheOperations(printer string, values []Attribute) []int16 {
+	operations := make([]int16, 0, len(values))
+	for _, value := range values {
+		if op, ok := value.Value.(int); ok {
+			operations = append(operations, int16(op))
+		}
+	}
+
+	c.operationsMu.Lock()
+	if c.operations == nil {
+		c.operations = make(map[string][]int16)
+	}
+	c.operations[printer] = operations
+	c.operationsMu.Unlock()
+
+	return operations
+}
+
+// removeOperation removes the operation from the cached operations of the printer, e.g. if the printer rejects an
+// operation it lists
+func (c *IPPClient) removeOperation(printer string, operation int16) {
+	c.operationsMu.Lock()
+	defer c.operationsMu.Unlock()
+
+	if operations, ok := c.operations[printer]; ok {
+		c.operations[printer] = slices.DeleteFunc(slices.Clone(operations), func(op int16) bool {
+			return op == operation
+		})
+	}
+}
+
+// SupportsOperation reports whether the printer lists the operation in operations-supported
+func (c *IPPClient) SupportsOperation(printer string, operation int16) (bool, error) {
+	operations, err := c.OperationsSupported(printer)
+	if err != nil {
+		return false, err
+	}
+
+	for _, op := range operations {
+		if op == operation {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ResetOperationsSupported removes the cached operations of the printer, e.g. after a firmware update. an empty
+// printer name removes the operations of all printers
+func (c *IPPClient) ResetOperationsSupported(printer string) {
+	c.operationsMu.Lock()
+	defer c.operationsMu.Unlock()
+
+	if printer == "" {
+		c.operations = nil
+	} else {
+		delete(c.operations, printer)
+	}
+}
+
+// supportsOperation checks the operation for the helpers falling back to other operations. known is false if the
+// operations of the printer can not be requested, the helpers should try the operation in this case
+func (c *IPPClient) supportsOperation(printer string, operation int16) (supported, known bool) {
+	supported, err := c.SupportsOperation(printer, operation)
+	return supported, err == nil
+}
+
+// GetUserPrinterAttributes returns the printer attributes as they apply to the user of the client, e.g. the defaults
+// and restrictions of the user, with Get-User-Printer-Attributes. printers and cups servers without the operation are
+// asked with Get-Printer-Attributes instead. the returned operations-supported replace the cached operations of the
+// printer, see OperationsSupported, and the attributes returned by Get-User-Printer-Attributes replace the ones of the
+// printer in its cached capabilities, see PrinterCapabilities
+func (c *IPPClient) GetUserPrinterAttributes(printer string, attributes []string) (Attributes, error) {
+	c.operationsMu.Lock()
+	operations, known := c.operations[printer]
+	c.operationsMu.Unlock()
+	if known && !slices.Contains(operations, OperationGetUserPrinterAttributes) {
+		return c.GetPrinterAttributes(printer, attributes)
+	}
+
+	req := NewRequest(OperationGetUserPrinterAttributes, 1)
+	req.OperationAttributes[AttributePrinterURI] = c.getPrinterUri(printer)
+	if attributes == nil {
+		req.OperationAttributes[AttributeRequestedAttributes] = DefaultPrinterAttributes
+	} else {
+		req.OperationAttributes[AttributeRequestedAttributes] = attributes
+	}
+
+	resp, err := c.SendRequest(c.adapter.GetHttpUri("printers", printer), req, nil)
+	if errors.Is(err, OperationNotSupportedError) {
+		c.removeOperation(printer, OperationGetUserPrinterAttributes)
+		return c.GetPrinterAttributes(printer, attributes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.PrinterAttributes) == 0 {
+		return nil, errors.New("server doesn't return any printer attributes")
+	}
+
+	user := resp.PrinterAttributes[0]
+	if values := user[AttributeOperationsSupported]; len(values) > 0 {
+		c.cacheOperations(printer, values)
+	}
+	c.cacheUserAttributes(printer, user)
+
+	return user, nil
+}
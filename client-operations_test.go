@@ -0,0 +1,112 @@
+package ipp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPPClient_OperationsSupported(t *testing.T) {
+	operations := []interface{}{int(OperationPrintJob), int(OperationGetPrinterAttributes)}
+
+	adapter := &testAdapter{}
+	adapter.respond = func(req *Request) *Response {
+		resp := NewResponse(StatusOk, req.RequestId)
+		switch req.Operation {
+		case OperationGetPrinterAttributes:
+			printer := make(Attributes)
+			printer.Set(AttributeOperationsSupported, TagEnum, operations...)
+			resp.PrinterAttributes = append(resp.PrinterAttributes, printer)
+		case OperationPrintJob:
+			job := make(Attributes)
+			job.Set(AttributeJobID, TagInteger, 5)
+			resp.JobAttributes = append(resp.JobAttributes, job)
+		default:
+			resp.StatusCode = StatusErrorOperationNotSupported
+		}
+		return resp
+	}
+	client := NewIPPClientWithAdapter("user", adapter)
+
+	supported, err := client.SupportsOperation("office", OperationPrintJob)
+	assert.Nil(t, err)
+	assert.True(t, supported)
+	supported, err = client.SupportsOperation("office", OperationCreateJob)
+	assert.Nil(t, err)
+	assert.False(t, supported)
+	assert.Len(t, adapter.requests, 1)
+
+	// a single document is printed with Print-Job if Create-Job is not supported
+	jobID, err := client.PrintDocuments([]Document{{Name: "doc", Size: -1, MimeType: MimeTypePostscript}}, "office",
+		map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, jobID)
+	assert.Equal(t, OperationPrintJob, adapter.requests[len(adapter.requests)-1].Operation)
+
+	_, err = client.PrintDocuments([]Document{{Name: "a", Size: -1}, {Name: "b", Size: -1}}, "office",
+		map[string]interface{}{})
+	assert.True(t, errors.Is(err, OperationNotSupportedError))
+
+	assert.True(t, errors.Is(client.CancelAllJob("office", false), OperationNotSupportedError))
+
+	// the operations are requested again after a reset
+	operations = append(operations, int(OperationCancelJobs))
+	client.ResetOperationsSupported("office")
+	supported, err = client.SupportsOperation("office", OperationCancelJobs)
+	assert.Nil(t, err)
+	assert.True(t, supported)
+
+	assert.True(t, errors.Is(StatusError{Status: StatusErrorOperationNotSupported}, OperationNotSupportedError))
+}
+
+func TestIPPClient_GetUserPrinterAttributes(t *testing.T) {
+	userSupported := true
+
+	adapter := &testAdapter{}
+	adapter.respond = func(req *Request) *Response {
+		resp := NewResponse(StatusOk, req.RequestId)
+		printer := make(Attributes)
+		switch {
+		case req.Operation == OperationGetUserPrinterAttributes && userSupported:
+			printer.Set(AttributeCopiesDefault, TagInteger, 2)
+			printer.Set(AttributeOperationsSupported, TagEnum, int(OperationPrintJob),
+				int(OperationGetUserPrinterAttributes))
+		case req.Operation == OperationGetPrinterAttributes:
+			printer.Set(AttributeCopiesDefault, TagInteger, 1)
+			printer.Set(AttributeOperationsSupported, TagEnum, int(OperationPrintJob))
+		default:
+			resp.StatusCode = StatusErrorOperationNotSupported
+			return resp
+		}
+		resp.PrinterAttributes = append(resp.PrinterAttributes, printer)
+		return resp
+	}
+	client := NewIPPClientWithAdapter("user", adapter)
+
+	attributes, err := client.GetUserPrinterAttributes("office", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attributes[AttributeCopiesDefault][0].Value)
+	assert.Equal(t, OperationGetUserPrinterAttributes, adapter.requests[0].Operation)
+	assert.Equal(t, "user", adapter.requests[0].OperationAttributes[AttributeRequestingUserName])
+
+	// the operations of the response are merged into the cache
+	supported, err := client.SupportsOperation("office", OperationGetUserPrinterAttributes)
+	assert.Nil(t, err)
+	assert.True(t, supported)
+	assert.Len(t, adapter.requests, 1)
+
+	// printers without the operation are asked with Get-Printer-Attributes
+	userSupported = false
+	attributes, err = client.GetUserPrinterAttributes("office", []string{AttributeCopiesDefault})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, attributes[AttributeCopiesDefault][0].Value)
+	assert.Len(t, adapter.requests, 3)
+	assert.Equal(t, OperationGetPrinterAttributes, adapter.requests[2].Operation)
+
+	// the operation is not tried again once the cached operations don't list it
+	_, err = client.GetUserPrinterAttributes("office", nil)
+	assert.Nil(t, err)
+	assert.Len(t, adapter.requests, 4)
+	assert.Equal(t, OperationGetPrinterAttributes, adapter.requests[3].Operation)
+}
@@ -0,0 +1,304 @@
+// Command ippfind lists the ipp printers found on the local network with dns-sd, like ippfind of cups. the printers
+// can be filtered by name and txt record and completed with a Get-Printer-Attributes probe. by default the printer
+// uris are printed, -exec runs a command for each printer instead
+//
+//	ippfind -T 3 -l
+//	ippfind -name '^Office' -txt Color=T -exec ipptool -tv {} get-printer-attributes.test ;
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/phin1x/go-ipp/discovery"
+)
+
+// options are the parsed command line arguments
+type options struct {
+	timeout time.Duration
+	name    *regexp.Regexp
+	txt     []txtFilter
+	probe   bool
+	long    bool
+	json    bool
+	// exec is the command and its arguments run for each printer, the arguments are templates, see expand
+	exec []string
+}
+
+// txtFilter matches printers whose txt record has the key, and a value matching the pattern if set
+type txtFilter struct {
+	key     string
+	pattern *regexp.Regexp
+}
+
+// txtFilters collects the -txt flags
+type txtFilters []txtFilter
+
+func (f *txtFilters) String() string {
+	return ""
+}
+
+func (f *txtFilters) Set(value string) error {
+	key, pattern, found := strings.Cut(value, "=")
+	filter := txtFilter{key: strings.ToLower(key)}
+
+	if found {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return err
+		}
+		filter.pattern = re
+	}
+
+	*f = append(*f, filter)
+
+	return nil
+}
+
+func main() {
+	opts, err := parseArgs(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+
+	printers, err := discovery.Discover(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	found, err := run(opts, printers, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if !found || err != nil {
+		os.Exit(1)
+	}
+}
+
+// parseArgs parses the flags, the arguments after -exec up to a ; are the command
+func parseArgs(flags *flag.FlagSet, args []string) (*options, error) {
+	opts := &options{}
+
+	for i, arg := range args {
+		if arg != "-exec" && arg != "--exec" {
+			continue
+		}
+
+		end := i + 1
+		for end < len(args) && args[end] != ";" {
+			end++
+		}
+		if end == len(args) {
+			return nil, errors.New("-exec requires a command terminated by ;")
+		}
+		if end == i+1 {
+			return nil, errors.New("-exec requires a command")
+		}
+
+		opts.exec = args[i+1 : end]
+		args = append(append([]string(nil), args[:i]...), args[end+1:]...)
+		break
+	}
+
+	var (
+		txt  txtFilters
+		name string
+	)
+	flags.DurationVar(&opts.timeout, "T", 3*time.Second, "browse `duration`")
+	flags.StringVar(&name, "name", "", "only list printers whose name matches the `regexp`")
+	flags.Var(&txt, "txt", "only list printers with the txt `key`, or key=regexp to match its value")
+	flags.BoolVar(&opts.probe, "probe", false, "complete the capabilities with a Get-Printer-Attributes request")
+	flags.BoolVar(&opts.long, "l", false, "list the name, txt record and capabilities of the printers")
+	flags.BoolVar(&opts.json, "json", false, "print the printers as json")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %s", flags.Arg(0))
+	}
+
+	if name != "" {
+		re, err := regexp.Compile(name)
+		if err != nil {
+			return nil, err
+		}
+		opts.name = re
+	}
+	opts.txt = txt
+
+	return opts, nil
+}
+
+// run filters and probes the printers and outputs them, it reports whether a printer matched. printers which can not
+// be probed are skipped
+func run(opts *options, printers []discovery.Printer, w io.Writer) (bool, error) {
+	var matched []discovery.Printer
+	for _, p := range printers {
+		if !opts.match(p) {
+			continue
+		}
+
+		if opts.probe {
+			if err := p.Probe(nil); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", p.URI, err)
+				continue
+			}
+		}
+
+		matched = append(matched, p)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].Name < matched[j].Name
+	})
+
+	switch {
+	case opts.json:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(matched); err != nil {
+			return false, err
+		}
+	case len(opts.exec) > 0:
+		for _, p := range matched {
+			if err := execute(opts.exec, p, w); err != nil {
+				return len(matched) > 0, err
+			}
+		}
+	default:
+		for _, p := range matched {
+			if opts.long {
+				list(w, p)
+			} else {
+				fmt.Fprintln(w, p.URI)
+			}
+		}
+	}
+
+	return len(matched) > 0, nil
+}
+
+// match reports whether the printer matches the name and txt filters
+func (o *options) match(p discovery.Printer) bool {
+	if o.name != nil && !o.name.MatchString(p.Name) {
+		return false
+	}
+
+	for _, f := range o.txt {
+		value, ok := txtValue(p, f.key)
+		if !ok || f.pattern != nil && !f.pattern.MatchString(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// txtValue returns the value of a txt key, the key is case-insensitive
+func txtValue(p discovery.Printer, key string) (string, bool) {
+	for k, v := range p.Text {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// execute runs the command with the expanded arguments for a printer
+func execute(command []string, p discovery.Printer, w io.Writer) error {
+	args := make([]string, len(command))
+	for i, arg := range command {
+		args[i] = expand(arg, p)
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	return nil
+}
+
+var templatePattern = regexp.MustCompile(`\{([a-z_A-Z0-9-]*)\}`)
+
+// expand replaces the templates of ippfind in an argument: {} and {service_uri} with the printer uri,
+// {service_name}, {service_hostname}, {service_port}, {service_scheme} and {service_path} with the parts of the
+// service and {txt_key} with the value of a txt key. unknown templates are kept
+func expand(arg string, p discovery.Printer) string {
+	return templatePattern.ReplaceAllStringFunc(arg, func(template string) string {
+		name := template[1 : len(template)-1]
+
+		switch name {
+		case "", "service_uri":
+			return p.URI
+		case "service_name":
+			return p.Name
+		case "service_hostname":
+			return p.Host
+		case "service_port":
+			return fmt.Sprint(p.Port)
+		case "service_scheme":
+			if p.Secure {
+				return "ipps"
+			}
+			return "ipp"
+		case "service_path":
+			return "/" + p.Capabilities.ResourcePath
+		}
+
+		if key, ok := strings.CutPrefix(name, "txt_"); ok {
+			value, _ := txtValue(p, key)
+			return value
+		}
+
+		return template
+	})
+}
+
+// list writes the name, uri, txt record and capabilities of a printer
+func list(w io.Writer, p discovery.Printer) {
+	fmt.Fprintf(w, "%s\n", p.Name)
+	fmt.Fprintf(w, "  uri: %s\n", p.URI)
+
+	keys := make([]string, 0, len(p.Text))
+	for key := range p.Text {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(w, "  txt %s=%s\n", key, p.Text[key])
+	}
+
+	c := p.Capabilities
+	if c.MakeAndModel != "" {
+		fmt.Fprintf(w, "  make and model: %s\n", c.MakeAndModel)
+	}
+	if c.Location != "" {
+		fmt.Fprintf(w, "  location: %s\n", c.Location)
+	}
+	if len(c.DocumentFormats) > 0 {
+		fmt.Fprintf(w, "  document formats: %s\n", strings.Join(c.DocumentFormats, ", "))
+	}
+	fmt.Fprintf(w, "  color: %t, duplex: %t\n", c.Color, c.Duplex)
+}
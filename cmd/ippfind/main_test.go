@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"testing"
+
+	"github.com/phin1x/go-ipp/discovery"
+	"github.com/phin1x/go-ipp/dnssd"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPrinters() []discovery.Printer {
+	return []discovery.Printer{
+		discovery.NewPrinter(&dnssd.Service{Instance: "Office Printer", Type: dnssd.ServiceTypeIPPS,
+			Host: "office.local.", Port: 631, Text: dnssd.ParseTXT([]string{"rp=ipp/print", "Color=T",
+				"ty=ACME Laser", "pdl=application/pdf,image/pwg-raster"})}),
+		discovery.NewPrinter(&dnssd.Service{Instance: "Label Printer", Type: dnssd.ServiceTypeIPP,
+			Host: "label.local.", Port: 8631, Text: dnssd.ParseTXT([]string{"rp=printers/label", "Color=F"})}),
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	opts, err := parseArgs(flag.NewFlagSet("ippfind", flag.ContinueOnError), []string{"-T", "5s", "-name", "^Office",
+		"-exec", "ipptool", "-tv", "{}", "get-printer-attributes.test", ";", "-txt", "Color=T", "-txt", "pdl"})
+	assert.Nil(t, err)
+	assert.Equal(t, "5s", opts.timeout.String())
+	assert.Equal(t, []string{"ipptool", "-tv", "{}", "get-printer-attributes.test"}, opts.exec)
+	assert.Len(t, opts.txt, 2)
+	assert.Equal(t, "color", opts.txt[0].key)
+	assert.Nil(t, opts.txt[1].pattern)
+
+	for _, args := range [][]string{
+		{"-exec", "echo", "{}"},
+		{"-exec", ";"},
+		{"-name", "("},
+		{"_ipp._tcp"},
+	} {
+		_, err := parseArgs(flag.NewFlagSet("ippfind", flag.ContinueOnError), args)
+		assert.NotNil(t, err, args)
+	}
+}
+
+func TestRun(t *testing.T) {
+	opts, err := parseArgs(flag.NewFlagSet("ippfind", flag.ContinueOnError), nil)
+	assert.Nil(t, err)
+
+	var out bytes.Buffer
+	found, err := run(opts, testPrinters(), &out)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "ipp://label.local:8631/printers/label\nipps://office.local:631/ipp/print\n", out.String())
+
+	opts, _ = parseArgs(flag.NewFlagSet("ippfind", flag.ContinueOnError), []string{"-txt", "color=T", "-l"})
+	out.Reset()
+	found, err = run(opts, testPrinters(), &out)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, `Office Printer
+  uri: ipps://office.local:631/ipp/print
+  txt color=T
+  txt pdl=application/pdf,image/pwg-raster
+  txt rp=ipp/print
+  txt ty=ACME Laser
+  make and model: ACME Laser
+  document formats: application/pdf, image/pwg-raster
+  color: true, duplex: false
+`, out.String())
+
+	opts, _ = parseArgs(flag.NewFlagSet("ippfind", flag.ContinueOnError), []string{"-name", "Label", "-json"})
+	out.Reset()
+	_, err = run(opts, testPrinters(), &out)
+	assert.Nil(t, err)
+	var printers []discovery.Printer
+	assert.Nil(t, json.Unmarshal(out.Bytes(), &printers))
+	assert.Len(t, printers, 1)
+	assert.Equal(t, 8631, printers[0].Port)
+
+	opts, _ = parseArgs(flag.NewFlagSet("ippfind", flag.ContinueOnError), []string{"-name", "Fax"})
+	found, err = run(opts, testPrinters(), &out)
+	assert.Nil(t, err)
+	assert.False(t, found)
+}
+
+func TestRun_Exec(t *testing.T) {
+	opts, _ := parseArgs(flag.NewFlagSet("ippfind", flag.ContinueOnError), []string{"-name", "Office",
+		"-exec", "echo", "{service_name}", "{service_port}", ";"})
+
+	var out bytes.Buffer
+	found, err := run(opts, testPrinters(), &out)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "Office Printer 631\n", out.String())
+}
+
+func TestExpand(t *testing.T) {
+	p := testPrinters()[0]
+
+	tests := []struct {
+		template string
+		expanded string
+	}{
+		{"{}", "ipps://office.local:631/ipp/print"},
+		{"{service_uri}", "ipps://office.local:631/ipp/print"},
+		{"{service_hostname}:{service_port}", "office.local:631"},
+		{"{service_scheme}://x{service_path}", "ipps://x/ipp/print"},
+		{"{txt_ty}", "ACME Laser"},
+		{"{txt_Color}", "T"},
+		{"{txt_missing}", ""},
+		{"{unknown}", "{unknown}"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expanded, expand(test.template, p), test.template)
+	}
+}
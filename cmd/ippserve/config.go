@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/server"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the configuration of the daemon, it is read from a yaml file like
+//
+//	listen: ":8631"
+//	directory: /var/spool/ippserve
+//	tls:
+//	  directory: /etc/ippserve/certs
+//	queues:
+//	  - name: office
+//	    make-and-model: Office Printer
+//	    document-formats: [application/pdf, image/jpeg]
+//	    color: true
+//	    duplex: true
+//	  - name: label
+//	    backend: 10.0.0.20:9100
+type Config struct {
+	// Listen is the address the daemon listens on, defaults to :8631
+	Listen string `yaml:"listen"`
+	// Directory is the directory the documents of the queues without directory and backend are saved in, each queue
+	// saves its documents in a sub directory named after the queue. defaults to jobs
+	Directory string `yaml:"directory"`
+	// DisableDNSSD turns off the dns-sd advertisement of the queues
+	DisableDNSSD bool `yaml:"disable-dnssd"`
+	// Verbose logs every request, by default only failed requests are logged
+	Verbose bool      `yaml:"verbose"`
+	TLS     TLSConfig `yaml:"tls"`
+	Queues  []Queue   `yaml:"queues"`
+}
+
+// TLSConfig enables ipps, either with a certificate and key file or with a self-signed certificate which is saved in
+// a directory
+type TLSConfig struct {
+	Cert      string `yaml:"cert"`
+	Key       string `yaml:"key"`
+	Directory string `yaml:"directory"`
+}
+
+// Enabled reports whether tls is configured
+func (c TLSConfig) Enabled() bool {
+	return c.Cert != "" || c.Directory != ""
+}
+
+// Queue is the configuration of a printer queue
+type Queue struct {
+	Name string `yaml:"name"`
+	// Path is the http path of the queue, defaults to /printers/<name>
+	Path string `yaml:"path"`
+	// UUID should be set, so clients recognize the queue after a restart
+	UUID         string `yaml:"uuid"`
+	MakeAndModel string `yaml:"make-and-model"`
+	Location     string `yaml:"location"`
+	Info         string `yaml:"info"`
+	// DocumentFormats are the accepted document formats in addition to image/pwg-raster, defaults to
+	// DefaultDocumentFormats
+	DocumentFormats []string `yaml:"document-formats"`
+	Media           []string `yaml:"media"`
+	MediaSources    []string `yaml:"media-sources"`
+	MediaTypes      []string `yaml:"media-types"`
+	OutputBins      []string `yaml:"output-bins"`
+	MaxCopies       int      `yaml:"max-copies"`
+	Color           bool     `yaml:"color"`
+	Duplex          bool     `yaml:"duplex"`
+	Borderless      bool     `yaml:"borderless"`
+	// Directory is the directory the documents are saved in
+	Directory string `yaml:"directory"`
+	// Backend is the address of a printer with a raw socket interface the documents are forwarded to instead of being
+	// saved, e.g. 10.0.0.20:9100
+	Backend string `yaml:"backend"`
+}
+
+// DefaultDocumentFormats are the document formats of queues without configured formats
+var DefaultDocumentFormats = []string{ipp.MimeTypePDF, "image/jpeg"}
+
+// LoadConfig reads the configuration from a yaml file, unknown keys are rejected
+func LoadConfig(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var c Config
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&c); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return &c, nil
+}
+
+// setDefaults fills the empty fields with the defaults
+func (c *Config) setDefaults() {
+	if c.Listen == "" {
+		c.Listen = ":8631"
+	}
+	if c.Directory == "" {
+		c.Directory = "jobs"
+	}
+}
+
+// queueConfigs converts the queues into the queue configurations of the server, the document sinks are created
+func (c *Config) queueConfigs() ([]server.QueueConfig, error) {
+	if len(c.Queues) == 0 {
+		return nil, errors.New("no queues configured")
+	}
+
+	configs := make([]server.QueueConfig, 0, len(c.Queues))
+	for _, q := range c.Queues {
+		if q.Name == "" {
+			return nil, errors.New("queue requires a name")
+		}
+
+		sink, err := c.sink(q)
+		if err != nil {
+			return nil, fmt.Errorf("queue %s: %w", q.Name, err)
+		}
+
+		formats := q.DocumentFormats
+		if len(formats) == 0 {
+			formats = DefaultDocumentFormats
+		}
+
+		configs = append(configs, server.QueueConfig{
+			Name: q.Name,
+			Path: q.Path,
+			UUID: q.UUID,
+			Capabilities: &server.Capabilities{
+				MakeAndModel:    q.MakeAndModel,
+				Location:        q.Location,
+				Info:            q.Info,
+				DocumentFormats: formats,
+				Media:           q.Media,
+				MediaSources:    q.MediaSources,
+				MediaTypes:      q.MediaTypes,
+				OutputBins:      q.OutputBins,
+				MaxCopies:       q.MaxCopies,
+				Color:           q.Color,
+				Duplex:          q.Duplex,
+				Borderless:      q.Borderless,
+			},
+			Sink: sink,
+		})
+	}
+
+	return configs, nil
+}
+
+// sink returns the socket sink of a queue with backend, otherwise a directory sink
+func (c *Config) sink(q Queue) (server.DocumentSink, error) {
+	if q.Backend != "" {
+		return server.NewSocketSink(q.Backend), nil
+	}
+
+	directory := q.Directory
+	if directory == "" {
+		directory = filepath.Join(c.Directory, q.Name)
+	}
+
+	return server.NewDirectorySink(directory)
+}
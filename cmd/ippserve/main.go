@@ -0,0 +1,258 @@
+// Command ippserve serves virtual ipp everywhere printers, like ippeveprinter of cups. the queues are advertised with
+// dns-sd and the received documents are saved as files or forwarded to printers with a raw socket interface. a
+// single queue is configured with flags, multiple queues with a yaml file, see Config
+//
+//	ippserve -name office -directory /tmp/jobs
+//	ippserve -config /etc/ippserve.yaml
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/phin1x/go-ipp/dnssd"
+	"github.com/phin1x/go-ipp/server"
+)
+
+// shutdownTimeout limits the time to finish the running jobs on shutdown
+const shutdownTimeout = 30 * time.Second
+
+func main() {
+	config, err := parseFlags(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	d, err := newDaemon(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	l, err := net.Listen("tcp", config.Listen)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := d.advertise(l.Addr()); err != nil {
+		log.Fatal(err)
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- d.serve(l)
+	}()
+
+	for _, q := range d.server.Queues() {
+		log.Printf("serving %s at %s", q.Name(), q.Endpoint.Path())
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case err := <-errs:
+		log.Fatal(err)
+	case <-signals:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := d.shutdown(ctx); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseFlags parses the command line into a configuration. the queue flags define a single queue if no
+// configuration file is given, the other flags override the values of the file
+func parseFlags(flags *flag.FlagSet, args []string) (*Config, error) {
+	var (
+		path    = flags.String("config", "", "yaml configuration `file`")
+		listen  = flags.String("listen", "", "listen `address`, defaults to :8631")
+		dir     = flags.String("directory", "", "`directory` the documents are saved in, defaults to jobs")
+		noDNSSD = flags.Bool("no-dnssd", false, "don't advertise the queues with dns-sd")
+		tlsDir  = flags.String("tls-directory", "", "enable ipps with a self-signed certificate saved in `directory`")
+		verbose = flags.Bool("v", false, "log every request")
+
+		queue   Queue
+		formats string
+	)
+	flags.StringVar(&queue.Name, "name", "", "printer `name` of the queue")
+	flags.StringVar(&queue.UUID, "uuid", "", "printer `uuid` of the queue")
+	flags.StringVar(&queue.MakeAndModel, "make-and-model", "", "make and `model` of the queue")
+	flags.StringVar(&queue.Location, "location", "", "`location` of the queue")
+	flags.StringVar(&formats, "formats", "", "comma separated document `formats` of the queue")
+	flags.BoolVar(&queue.Color, "color", false, "the queue supports color")
+	flags.BoolVar(&queue.Duplex, "duplex", false, "the queue supports duplex")
+	flags.StringVar(&queue.Backend, "backend", "", "forward the documents to the raw socket printer at `address`")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	if *path != "" {
+		var err error
+		if config, err = LoadConfig(*path); err != nil {
+			return nil, err
+		}
+	}
+
+	if *listen != "" {
+		config.Listen = *listen
+	}
+	if *dir != "" {
+		config.Directory = *dir
+	}
+	if *tlsDir != "" {
+		config.TLS.Directory = *tlsDir
+	}
+	config.DisableDNSSD = config.DisableDNSSD || *noDNSSD
+	config.Verbose = config.Verbose || *verbose
+
+	if queue.Name != "" {
+		if formats != "" {
+			queue.DocumentFormats = strings.Split(formats, ",")
+		}
+		config.Queues = append(config.Queues, queue)
+	}
+	if len(config.Queues) == 0 {
+		return nil, errors.New("either -name or -config is required")
+	}
+
+	config.setDefaults()
+
+	return config, nil
+}
+
+// daemon serves the queues of a configuration
+type daemon struct {
+	config    *Config
+	server    *server.Server
+	http      *http.Server
+	tls       *tls.Config
+	responder *dnssd.Responder
+}
+
+// newDaemon creates the queues of the configuration and loads the tls certificate
+func newDaemon(config *Config) (*daemon, error) {
+	config.setDefaults()
+
+	queues, err := config.queueConfigs()
+	if err != nil {
+		return nil, err
+	}
+
+	d := &daemon{config: config, server: server.NewServer()}
+	// client errors and failed requests are logged, successful requests only in verbose mode
+	level := slog.LevelInfo
+	if config.Verbose {
+		level = slog.LevelDebug
+	}
+	d.server.Logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	for _, q := range queues {
+		if _, err := d.server.AddQueue(q); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.TLS.Enabled() {
+		if d.tls, err = d.tlsConfig(); err != nil {
+			return nil, err
+		}
+	}
+
+	d.http = &http.Server{Handler: d.server}
+
+	return d, nil
+}
+
+// tlsConfig loads the configured certificate or the self-signed certificate of the first queue
+func (d *daemon) tlsConfig() (*tls.Config, error) {
+	c := d.config.TLS
+	if c.Cert != "" {
+		cert, err := tls.LoadX509KeyPair(c.Cert, c.Key)
+		if err != nil {
+			return nil, err
+		}
+		return server.TLSConfig(cert), nil
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := d.server.Queues()[0].Printer.Certificate(c.Directory, hostname, hostname+".local")
+	if err != nil {
+		return nil, err
+	}
+
+	return server.TLSConfig(cert), nil
+}
+
+// advertise registers the dns-sd services of the queues on the port of the listen address, unless dns-sd is disabled
+func (d *daemon) advertise(addr net.Addr) error {
+	if d.config.DisableDNSSD {
+		return nil
+	}
+
+	_, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return err
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		return err
+	}
+
+	if d.responder, err = dnssd.NewResponder(); err != nil {
+		return fmt.Errorf("failed to start the dns-sd responder: %w", err)
+	}
+
+	return d.server.AdvertiseQueues(d.responder, portNumber, d.tls != nil)
+}
+
+// serve serves ipp requests on the listener until the daemon is shut down
+func (d *daemon) serve(l net.Listener) error {
+	if d.tls != nil {
+		l = tls.NewListener(l, d.tls)
+	}
+
+	if err := d.http.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// shutdown stops advertising the queues, waits for the running jobs and stops the http server
+func (d *daemon) shutdown(ctx context.Context) error {
+	err := d.server.Shutdown(ctx)
+
+	if httpErr := d.http.Shutdown(ctx); err == nil {
+		err = httpErr
+	}
+
+	if d.responder != nil {
+		if closeErr := d.responder.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+const testConfig = `
+listen: "127.0.0.1:0"
+directory: jobs
+disable-dnssd: true
+queues:
+  - name: office
+    uuid: 3e6f0a2c-1d4b-4f8e-9c1a-5b7d2e8f6a01
+    make-and-model: Office Printer
+    document-formats: [application/pdf]
+    color: true
+  - name: label
+    path: /ipp/label
+    backend: 10.0.0.20:9100
+`
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "ippserve.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0o644))
+
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	config, err := LoadConfig(writeConfig(t, testConfig))
+	assert.Nil(t, err)
+
+	assert.Equal(t, "127.0.0.1:0", config.Listen)
+	assert.True(t, config.DisableDNSSD)
+	assert.False(t, config.TLS.Enabled())
+	assert.Len(t, config.Queues, 2)
+	assert.Equal(t, Queue{
+		Name:            "office",
+		UUID:            "3e6f0a2c-1d4b-4f8e-9c1a-5b7d2e8f6a01",
+		MakeAndModel:    "Office Printer",
+		DocumentFormats: []string{"application/pdf"},
+		Color:           true,
+	}, config.Queues[0])
+	assert.Equal(t, "10.0.0.20:9100", config.Queues[1].Backend)
+
+	_, err = LoadConfig(writeConfig(t, "queues:\n  - name: office\n    colour: true\n"))
+	assert.NotNil(t, err)
+}
+
+func TestParseFlags(t *testing.T) {
+	config, err := parseFlags(flag.NewFlagSet("ippserve", flag.ContinueOnError), []string{"-name", "office",
+		"-formats", "application/pdf,image/jpeg", "-duplex", "-no-dnssd", "-v"})
+	assert.Nil(t, err)
+	assert.Equal(t, ":8631", config.Listen)
+	assert.Equal(t, "jobs", config.Directory)
+	assert.True(t, config.DisableDNSSD)
+	assert.True(t, config.Verbose)
+	assert.Equal(t, []Queue{{Name: "office", DocumentFormats: []string{"application/pdf", "image/jpeg"}, Duplex: true}},
+		config.Queues)
+
+	config, err = parseFlags(flag.NewFlagSet("ippserve", flag.ContinueOnError), []string{"-config",
+		writeConfig(t, testConfig), "-listen", ":631"})
+	assert.Nil(t, err)
+	assert.Equal(t, ":631", config.Listen)
+	assert.Len(t, config.Queues, 2)
+
+	_, err = parseFlags(flag.NewFlagSet("ippserve", flag.ContinueOnError), nil)
+	assert.NotNil(t, err)
+}
+
+func TestDaemon(t *testing.T) {
+	directory := t.TempDir()
+	d, err := newDaemon(&Config{Directory: directory, DisableDNSSD: true, Queues: []Queue{{Name: "office"}}})
+	assert.Nil(t, err)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- d.serve(l)
+	}()
+	assert.Nil(t, d.advertise(l.Addr()))
+
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+	portNumber, _ := net.LookupPort("tcp", port)
+	client := ipp.NewIPPClient(host, portNumber, "", "", false)
+
+	uri := "ipp://" + l.Addr().String() + "/printers/office"
+	req := ipp.NewRequest(ipp.OperationPrintJob, 1, ipp.WithPrinterURI(uri),
+		ipp.WithDocument(bytes.NewBufferString("%PDF-1.7"), 8, ipp.MimeTypePDF))
+	resp, err := client.SendRequest("http://"+l.Addr().String()+"/printers/office", req, nil)
+	assert.Nil(t, err)
+	assert.Len(t, resp.JobAttributes, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Nil(t, d.shutdown(ctx))
+	assert.Nil(t, <-errs)
+
+	files, err := os.ReadDir(filepath.Join(directory, "office"))
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+
+	f, err := os.Open(filepath.Join(directory, "office", files[0].Name()))
+	assert.Nil(t, err)
+	defer f.Close()
+	data, _ := io.ReadAll(f)
+	assert.Equal(t, "%PDF-1.7", string(data))
+}
@@ -1,236 +1,34 @@
 package ipp
 
-// ipp status codes
+//go:generate go run ./internal/ianagen
+
+// the operation, status code and tag constants of the iana ipp registry and the cups extensions are generated into
+// constants-generated.go, these are the values which are not registered. the hand written status codes lacked
+// client-error-compression-not-supported (0x040f), so StatusErrorCompressionError up to StatusErrorNotFetchable were
+// one less than their registered values before they were generated
+
+// status codes which are not registered
 const (
-	StatusCupsInvalid                         int16 = -1
-	StatusOk                                  int16 = 0x0000
-	StatusOkIgnoredOrSubstituted              int16 = 0x0001
-	StatusOkConflicting                       int16 = 0x0002
-	StatusOkIgnoredSubscriptions              int16 = 0x0003
-	StatusOkIgnoredNotifications              int16 = 0x0004
-	StatusOkTooManyEvents                     int16 = 0x0005
-	StatusOkButCancelSubscription             int16 = 0x0006
-	StatusOkEventsComplete                    int16 = 0x0007
-	StatusRedirectionOtherSite                int16 = 0x0200
-	StatusCupsSeeOther                        int16 = 0x0280
-	StatusErrorBadRequest                     int16 = 0x0400
-	StatusErrorForbidden                      int16 = 0x0401
-	StatusErrorNotAuthenticated               int16 = 0x0402
-	StatusErrorNotAuthorized                  int16 = 0x0403
-	StatusErrorNotPossible                    int16 = 0x0404
-	StatusErrorTimeout                        int16 = 0x0405
-	StatusErrorNotFound                       int16 = 0x0406
-	StatusErrorGone                           int16 = 0x0407
-	StatusErrorRequestEntity                  int16 = 0x0408
-	StatusErrorRequestValue                   int16 = 0x0409
-	StatusErrorDocumentFormatNotSupported     int16 = 0x040a
-	StatusErrorAttributesOrValues             int16 = 0x040b
-	StatusErrorUriScheme                      int16 = 0x040c
-	StatusErrorCharset                        int16 = 0x040d
-	StatusErrorConflicting                    int16 = 0x040e
-	StatusErrorCompressionError               int16 = 0x040f
-	StatusErrorDocumentFormatError            int16 = 0x0410
-	StatusErrorDocumentAccess                 int16 = 0x0411
-	StatusErrorAttributesNotSettable          int16 = 0x0412
-	StatusErrorIgnoredAllSubscriptions        int16 = 0x0413
-	StatusErrorTooManySubscriptions           int16 = 0x0414
-	StatusErrorIgnoredAllNotifications        int16 = 0x0415
-	StatusErrorPrintSupportFileNotFound       int16 = 0x0416
-	StatusErrorDocumentPassword               int16 = 0x0417
-	StatusErrorDocumentPermission             int16 = 0x0418
-	StatusErrorDocumentSecurity               int16 = 0x0419
-	StatusErrorDocumentUnprintable            int16 = 0x041a
-	StatusErrorAccountInfoNeeded              int16 = 0x041b
-	StatusErrorAccountClosed                  int16 = 0x041c
-	StatusErrorAccountLimitReached            int16 = 0x041d
-	StatusErrorAccountAuthorizationFailed     int16 = 0x041e
-	StatusErrorNotFetchable                   int16 = 0x041f
-	StatusErrorCupsAccountInfoNeeded          int16 = 0x049C
-	StatusErrorCupsAccountClosed              int16 = 0x049d
-	StatusErrorCupsAccountLimitReached        int16 = 0x049e
-	StatusErrorCupsAccountAuthorizationFailed int16 = 0x049f
-	StatusErrorInternal                       int16 = 0x0500
-	StatusErrorOperationNotSupported          int16 = 0x0501
-	StatusErrorServiceUnavailable             int16 = 0x0502
-	StatusErrorVersionNotSupported            int16 = 0x0503
-	StatusErrorDevice                         int16 = 0x0504
-	StatusErrorTemporary                      int16 = 0x0505
-	StatusErrorNotAcceptingJobs               int16 = 0x0506
-	StatusErrorBusy                           int16 = 0x0507
-	StatusErrorJobCanceled                    int16 = 0x0508
-	StatusErrorMultipleJobsNotSupported       int16 = 0x0509
-	StatusErrorPrinterIsDeactivated           int16 = 0x050a
-	StatusErrorTooManyJobs                    int16 = 0x050b
-	StatusErrorTooManyDocuments               int16 = 0x050c
-	StatusErrorCupsAuthenticationCanceled     int16 = 0x1000
-	StatusErrorCupsPki                        int16 = 0x1001
-	StatusErrorCupsUpgradeRequired            int16 = 0x1002
-)
-
-// ipp operations
+	StatusCupsInvalid int16 = -1
+)
+
+// operations which are not registered
 const (
-	OperationCupsInvalid                     int16 = -0x0001
-	OperationCupsNone                        int16 = 0x0000
-	OperationPrintJob                        int16 = 0x0002
-	OperationPrintUri                        int16 = 0x0003
-	OperationValidateJob                     int16 = 0x0004
-	OperationCreateJob                       int16 = 0x0005
-	OperationSendDocument                    int16 = 0x0006
-	OperationSendUri                         int16 = 0x0007
-	OperationCancelJob                       int16 = 0x0008
-	OperationGetJobAttributes                int16 = 0x0009
-	OperationGetJobs                         int16 = 0x000a
-	OperationGetPrinterAttributes            int16 = 0x000b
-	OperationHoldJob                         int16 = 0x000c
-	OperationReleaseJob                      int16 = 0x000d
-	OperationRestartJob                      int16 = 0x000e
-	OperationPausePrinter                    int16 = 0x0010
-	OperationResumePrinter                   int16 = 0x0011
-	OperationPurgeJobs                       int16 = 0x0012
-	OperationSetPrinterAttributes            int16 = 0x0013
-	OperationSetJobAttributes                int16 = 0x0014
-	OperationGetPrinterSupportedValues       int16 = 0x0015
-	OperationCreatePrinterSubscriptions      int16 = 0x0016
-	OperationCreateJobSubscriptions          int16 = 0x0017
-	OperationGetSubscriptionAttributes       int16 = 0x0018
-	OperationGetSubscriptions                int16 = 0x0019
-	OperationRenewSubscription               int16 = 0x001a
-	OperationCancelSubscription              int16 = 0x001b
-	OperationGetNotifications                int16 = 0x001c
-	OperationSendNotifications               int16 = 0x001d
-	OperationGetResourceAttributes           int16 = 0x001e
-	OperationGetResourceData                 int16 = 0x001f
-	OperationGetResources                    int16 = 0x0020
-	OperationGetPrintSupportFiles            int16 = 0x0021
-	OperationEnablePrinter                   int16 = 0x0022
-	OperationDisablePrinter                  int16 = 0x0023
-	OperationPausePrinterAfterCurrentJob     int16 = 0x0024
-	OperationHoldNewJobs                     int16 = 0x0025
-	OperationReleaseHeldNewJobs              int16 = 0x0026
-	OperationDeactivatePrinter               int16 = 0x0027
-	OperationActivatePrinter                 int16 = 0x0028
-	OperationRestartPrinter                  int16 = 0x0029
-	OperationShutdownPrinter                 int16 = 0x002a
-	OperationStartupPrinter                  int16 = 0x002b
-	OperationReprocessJob                    int16 = 0x002c
-	OperationCancelCurrentJob                int16 = 0x002d
-	OperationSuspendCurrentJob               int16 = 0x002e
-	OperationResumeJob                       int16 = 0x002f
-	OperationOperationPromoteJob             int16 = 0x0030
-	OperationScheduleJobAfter                int16 = 0x0031
-	OperationCancelDocument                  int16 = 0x0033
-	OperationGetDocumentAttributes           int16 = 0x0034
-	OperationGetDocuments                    int16 = 0x0035
-	OperationDeleteDocument                  int16 = 0x0036
-	OperationSetDocumentAttributes           int16 = 0x0037
-	OperationCancelJobs                      int16 = 0x0038
-	OperationCancelMyJobs                    int16 = 0x0039
-	OperationResubmitJob                     int16 = 0x003a
-	OperationCloseJob                        int16 = 0x003b
-	OperationIdentifyPrinter                 int16 = 0x003c
-	OperationValidateDocument                int16 = 0x003d
-	OperationAddDocumentImages               int16 = 0x003e
-	OperationAcknowledgeDocument             int16 = 0x003f
-	OperationAcknowledgeIdentifyPrinter      int16 = 0x0040
-	OperationAcknowledgeJob                  int16 = 0x0041
-	OperationFetchDocument                   int16 = 0x0042
-	OperationFetchJob                        int16 = 0x0043
-	OperationGetOutputDeviceAttributes       int16 = 0x0044
-	OperationUpdateActiveJobs                int16 = 0x0045
-	OperationDeregisterOutputDevice          int16 = 0x0046
-	OperationUpdateDocumentStatus            int16 = 0x0047
-	OperationUpdateJobStatus                 int16 = 0x0048
-	OperationUpdateOutputDeviceAttributes    int16 = 0x0049
-	OperationGetNextDocumentData             int16 = 0x004a
-	OperationAllocatePrinterResources        int16 = 0x004b
-	OperationCreatePrinter                   int16 = 0x004c
-	OperationDeallocatePrinterResources      int16 = 0x004d
-	OperationDeletePrinter                   int16 = 0x004e
-	OperationGetPrinters                     int16 = 0x004f
-	OperationShutdownOnePrinter              int16 = 0x0050
-	OperationStartupOnePrinter               int16 = 0x0051
-	OperationCancelResource                  int16 = 0x0052
-	OperationCreateResource                  int16 = 0x0053
-	OperationInstallResource                 int16 = 0x0054
-	OperationSendResourceData                int16 = 0x0055
-	OperationSetResourceAttributes           int16 = 0x0056
-	OperationCreateResourceSubscriptions     int16 = 0x0057
-	OperationCreateSystemSubscriptions       int16 = 0x0058
-	OperationDisableAllPrinters              int16 = 0x0059
-	OperationEnableAllPrinters               int16 = 0x005a
-	OperationGetSystemAttributes             int16 = 0x005b
-	OperationGetSystemSupportedValues        int16 = 0x005c
-	OperationPauseAllPrinters                int16 = 0x005d
-	OperationPauseAllPrintersAfterCurrentJob int16 = 0x005e
-	OperationRegisterOutputDevice            int16 = 0x005f
-	OperationRestartSystem                   int16 = 0x0060
-	OperationResumeAllPrinters               int16 = 0x0061
-	OperationSetSystemAttributes             int16 = 0x0062
-	OperationShutdownAllPrinter              int16 = 0x0063
-	OperationStartupAllPrinters              int16 = 0x0064
-	OperationPrivate                         int16 = 0x4000
-	OperationCupsGetDefault                  int16 = 0x4001
-	OperationCupsGetPrinters                 int16 = 0x4002
-	OperationCupsAddModifyPrinter            int16 = 0x4003
-	OperationCupsDeletePrinter               int16 = 0x4004
-	OperationCupsGetClasses                  int16 = 0x4005
-	OperationCupsAddModifyClass              int16 = 0x4006
-	OperationCupsDeleteClass                 int16 = 0x4007
-	OperationCupsAcceptJobs                  int16 = 0x4008
-	OperationCupsRejectJobs                  int16 = 0x4009
-	OperationCupsSetDefault                  int16 = 0x400a
-	OperationCupsGetDevices                  int16 = 0x400b
-	OperationCupsGetPPDs                     int16 = 0x400c
-	OperationCupsMoveJob                     int16 = 0x400d
-	OperationCupsAuthenticateJob             int16 = 0x400e
-	OperationCupsGetPpd                      int16 = 0x400f
-	OperationCupsGetDocument                 int16 = 0x4027
-	OperationCupsCreateLocalPrinter          int16 = 0x4028
-)
-
-// ipp tags
+	OperationCupsInvalid int16 = -0x0001
+	OperationCupsNone    int16 = 0x0000
+	OperationPrivate     int16 = 0x4000
+
+	// Deprecated: use OperationPromoteJob
+	OperationOperationPromoteJob = OperationPromoteJob
+	// Deprecated: use OperationShutdownAllPrinters
+	OperationShutdownAllPrinter = OperationShutdownAllPrinters
+)
+
+// tags which are not registered
 const (
-	TagCupsInvalid       int8 = -1
-	TagZero              int8 = 0x00
-	TagOperation         int8 = 0x01
-	TagJob               int8 = 0x02
-	TagEnd               int8 = 0x03
-	TagPrinter           int8 = 0x04
-	TagUnsupportedGroup  int8 = 0x05
-	TagSubscription      int8 = 0x06
-	TagEventNotification int8 = 0x07
-	TagResource          int8 = 0x08
-	TagDocument          int8 = 0x09
-	TagSystem            int8 = 0x0a
-	TagUnsupportedValue  int8 = 0x10
-	TagDefault           int8 = 0x11
-	TagUnknown           int8 = 0x12
-	TagNoValue           int8 = 0x13
-	TagNotSettable       int8 = 0x15
-	TagDeleteAttr        int8 = 0x16
-	TagAdminDefine       int8 = 0x17
-	TagInteger           int8 = 0x21
-	TagBoolean           int8 = 0x22
-	TagEnum              int8 = 0x23
-	TagString            int8 = 0x30
-	TagDate              int8 = 0x31
-	TagResolution        int8 = 0x32
-	TagRange             int8 = 0x33
-	TagBeginCollection   int8 = 0x34
-	TagTextLang          int8 = 0x35
-	TagNameLang          int8 = 0x36
-	TagEndCollection     int8 = 0x37
-	TagText              int8 = 0x41
-	TagName              int8 = 0x42
-	TagReservedString    int8 = 0x43
-	TagKeyword           int8 = 0x44
-	TagUri               int8 = 0x45
-	TagUriScheme         int8 = 0x46
-	TagCharset           int8 = 0x47
-	TagLanguage          int8 = 0x48
-	TagMimeType          int8 = 0x49
-	TagMemberName        int8 = 0x4a
-	TagExtension         int8 = 0x7f
+	TagCupsInvalid    int8 = -1
+	TagZero           int8 = 0x00
+	TagReservedString int8 = 0x43
 )
 
 // job states
@@ -260,11 +58,288 @@ const (
 	PrinterStateStopped    int8 = 0x0005
 )
 
+// orientations
+const (
+	OrientationPortrait         int8 = 0x03
+	OrientationLandscape        int8 = 0x04
+	OrientationReverseLandscape int8 = 0x05
+	OrientationReversePortrait  int8 = 0x06
+	OrientationNone             int8 = 0x07
+)
+
+// print qualities
+const (
+	PrintQualityDraft  int8 = 0x03
+	PrintQualityNormal int8 = 0x04
+	PrintQualityHigh   int8 = 0x05
+)
+
+// finishings
+const (
+	FinishingsNone                int8 = 0x03
+	FinishingsStaple              int8 = 4
+	FinishingsPunch               int8 = 5
+	FinishingsCover               int8 = 6
+	FinishingsBind                int8 = 7
+	FinishingsSaddleStitch        int8 = 8
+	FinishingsEdgeStitch          int8 = 9
+	FinishingsFold                int8 = 10
+	FinishingsTrim                int8 = 11
+	FinishingsBale                int8 = 12
+	FinishingsBookletMaker        int8 = 13
+	FinishingsJogOffset           int8 = 14
+	FinishingsCoat                int8 = 15
+	FinishingsLaminate            int8 = 16
+	FinishingsStapleTopLeft       int8 = 20
+	FinishingsStapleBottomLeft    int8 = 21
+	FinishingsStapleTopRight      int8 = 22
+	FinishingsStapleBottomRight   int8 = 23
+	FinishingsEdgeStitchLeft      int8 = 24
+	FinishingsEdgeStitchTop       int8 = 25
+	FinishingsEdgeStitchRight     int8 = 26
+	FinishingsEdgeStitchBottom    int8 = 27
+	FinishingsStapleDualLeft      int8 = 28
+	FinishingsStapleDualTop       int8 = 29
+	FinishingsStapleDualRight     int8 = 30
+	FinishingsStapleDualBottom    int8 = 31
+	FinishingsStapleTripleLeft    int8 = 32
+	FinishingsStapleTripleTop     int8 = 33
+	FinishingsStapleTripleRight   int8 = 34
+	FinishingsStapleTripleBottom  int8 = 35
+	FinishingsBindLeft            int8 = 50
+	FinishingsBindTop             int8 = 51
+	FinishingsBindRight           int8 = 52
+	FinishingsBindBottom          int8 = 53
+	FinishingsTrimAfterPages      int8 = 60
+	FinishingsTrimAfterDocuments  int8 = 61
+	FinishingsTrimAfterCopies     int8 = 62
+	FinishingsTrimAfterJob        int8 = 63
+	FinishingsPunchTopLeft        int8 = 70
+	FinishingsPunchBottomLeft     int8 = 71
+	FinishingsPunchTopRight       int8 = 72
+	FinishingsPunchBottomRight    int8 = 73
+	FinishingsPunchDualLeft       int8 = 74
+	FinishingsPunchDualTop        int8 = 75
+	FinishingsPunchDualRight      int8 = 76
+	FinishingsPunchDualBottom     int8 = 77
+	FinishingsPunchTripleLeft     int8 = 78
+	FinishingsPunchTripleTop      int8 = 79
+	FinishingsPunchTripleRight    int8 = 80
+	FinishingsPunchTripleBottom   int8 = 81
+	FinishingsPunchQuadLeft       int8 = 82
+	FinishingsPunchQuadTop        int8 = 83
+	FinishingsPunchQuadRight      int8 = 84
+	FinishingsPunchQuadBottom     int8 = 85
+	FinishingsPunchMultipleLeft   int8 = 86
+	FinishingsPunchMultipleTop    int8 = 87
+	FinishingsPunchMultipleRight  int8 = 88
+	FinishingsPunchMultipleBottom int8 = 89
+	FinishingsFoldAccordion       int8 = 90
+	FinishingsFoldDoubleGate      int8 = 91
+	FinishingsFoldGate            int8 = 92
+	FinishingsFoldHalf            int8 = 93
+	FinishingsFoldHalfZ           int8 = 94
+	FinishingsFoldLeftGate        int8 = 95
+	FinishingsFoldLetter          int8 = 96
+	FinishingsFoldParallel        int8 = 97
+	FinishingsFoldPoster          int8 = 98
+	FinishingsFoldRightGate       int8 = 99
+	FinishingsFoldZ               int8 = 100
+	FinishingsFoldEngineeringZ    int8 = 101
+)
+
 // job state filter
 const (
-	JobStateFilterNotCompleted = "not-completed"
-	JobStateFilterCompleted    = "completed"
-	JobStateFilterAll          = "all"
+	JobStateFilterNotCompleted      = "not-completed"
+	JobStateFilterCompleted         = "completed"
+	JobStateFilterAll               = "all"
+	JobStateFilterAborted           = "aborted"
+	JobStateFilterCanceled          = "canceled"
+	JobStateFilterFetchable         = "fetchable"
+	JobStateFilterPending           = "pending"
+	JobStateFilterPendingHeld       = "pending-held"
+	JobStateFilterProcessing        = "processing"
+	JobStateFilterProcessingStopped = "processing-stopped"
+	JobStateFilterProofPrint        = "proof-print"
+	JobStateFilterSaved             = "saved"
+)
+
+// group keywords of the requested-attributes operation attribute
+const (
+	RequestedAll                = "all"
+	RequestedPrinterDescription = "printer-description"
+	RequestedJobTemplate        = "job-template"
+	RequestedJobDescription     = "job-description"
+	// RequestedMediaColDatabase requests the media-col-database, printers don't return it for all due to its size
+	RequestedMediaColDatabase = "media-col-database"
+)
+
+// sides
+const (
+	SidesOneSided          = "one-sided"
+	SidesTwoSidedLongEdge  = "two-sided-long-edge"
+	SidesTwoSidedShortEdge = "two-sided-short-edge"
+)
+
+// print color modes
+const (
+	PrintColorModeAuto              = "auto"
+	PrintColorModeAutoMonochrome    = "auto-monochrome"
+	PrintColorModeBiLevel           = "bi-level"
+	PrintColorModeColor             = "color"
+	PrintColorModeHighlight         = "highlight"
+	PrintColorModeMonochrome        = "monochrome"
+	PrintColorModeProcessBiLevel    = "process-bi-level"
+	PrintColorModeProcessMonochrome = "process-monochrome"
+)
+
+// print content optimizations
+const (
+	PrintContentOptimizeAuto            = "auto"
+	PrintContentOptimizeGraphics        = "graphics"
+	PrintContentOptimizePhoto           = "photo"
+	PrintContentOptimizeText            = "text"
+	PrintContentOptimizeTextAndGraphics = "text-and-graphics"
+)
+
+// print rendering intents
+const (
+	PrintRenderingIntentAuto        = "auto"
+	PrintRenderingIntentAbsolute    = "absolute"
+	PrintRenderingIntentPerceptual  = "perceptual"
+	PrintRenderingIntentRelative    = "relative"
+	PrintRenderingIntentRelativeBpc = "relative-bpc"
+	PrintRenderingIntentSaturation  = "saturation"
+)
+
+// print scalings
+const (
+	PrintScalingAuto    = "auto"
+	PrintScalingAutoFit = "auto-fit"
+	PrintScalingFill    = "fill"
+	PrintScalingFit     = "fit"
+	PrintScalingNone    = "none"
+)
+
+// multiple document handlings
+const (
+	MultipleDocumentHandlingSeparateDocumentsCollatedCopies   = "separate-documents-collated-copies"
+	MultipleDocumentHandlingSeparateDocumentsUncollatedCopies = "separate-documents-uncollated-copies"
+	MultipleDocumentHandlingSingleDocument                    = "single-document"
+	MultipleDocumentHandlingSingleDocumentNewSheet            = "single-document-new-sheet"
+)
+
+// job hold until values
+const (
+	JobHoldUntilNoHold      = "no-hold"
+	JobHoldUntilIndefinite  = "indefinite"
+	JobHoldUntilDayTime     = "day-time"
+	JobHoldUntilEvening     = "evening"
+	JobHoldUntilNight       = "night"
+	JobHoldUntilSecondShift = "second-shift"
+	JobHoldUntilThirdShift  = "third-shift"
+	JobHoldUntilWeekend     = "weekend"
+)
+
+// job account types
+const (
+	JobAccountTypeGeneral = "general"
+	JobAccountTypeGroup   = "group"
+	JobAccountTypeNone    = "none"
+)
+
+// media sources
+const (
+	MediaSourceAuto          = "auto"
+	MediaSourceAlternate     = "alternate"
+	MediaSourceAlternateRoll = "alternate-roll"
+	MediaSourceBottom        = "bottom"
+	MediaSourceByPassTray    = "by-pass-tray"
+	MediaSourceCenter        = "center"
+	MediaSourceDisc          = "disc"
+	MediaSourceEnvelope      = "envelope"
+	MediaSourceHagaki        = "hagaki"
+	MediaSourceLargeCapacity = "large-capacity"
+	MediaSourceLeft          = "left"
+	MediaSourceMain          = "main"
+	MediaSourceMainRoll      = "main-roll"
+	MediaSourceManual        = "manual"
+	MediaSourceMiddle        = "middle"
+	MediaSourcePhoto         = "photo"
+	MediaSourceRear          = "rear"
+	MediaSourceRight         = "right"
+	MediaSourceSide          = "side"
+	MediaSourceTop           = "top"
+	MediaSourceTray1         = "tray-1"
+	MediaSourceTray2         = "tray-2"
+	MediaSourceTray3         = "tray-3"
+	MediaSourceTray4         = "tray-4"
+	MediaSourceTray5         = "tray-5"
+	MediaSourceTray6         = "tray-6"
+	MediaSourceTray7         = "tray-7"
+	MediaSourceTray8         = "tray-8"
+	MediaSourceTray9         = "tray-9"
+	MediaSourceTray10        = "tray-10"
+	MediaSourceRoll1         = "roll-1"
+	MediaSourceRoll2         = "roll-2"
+	MediaSourceRoll3         = "roll-3"
+	MediaSourceRoll4         = "roll-4"
+)
+
+// media types
+const (
+	MediaTypeAuto                  = "auto"
+	MediaTypeCardstock             = "cardstock"
+	MediaTypeContinuous            = "continuous"
+	MediaTypeDisc                  = "disc"
+	MediaTypeEnvelope              = "envelope"
+	MediaTypeEnvelopeWindow        = "envelope-window"
+	MediaTypeLabels                = "labels"
+	MediaTypeOther                 = "other"
+	MediaTypePhotographic          = "photographic"
+	MediaTypePhotographicFilm      = "photographic-film"
+	MediaTypePhotographicGlossy    = "photographic-glossy"
+	MediaTypePhotographicHighGloss = "photographic-high-gloss"
+	MediaTypePhotographicMatte     = "photographic-matte"
+	MediaTypePhotographicSatin     = "photographic-satin"
+	MediaTypePhotographicSemiGloss = "photographic-semi-gloss"
+	MediaTypeStationery            = "stationery"
+	MediaTypeStationeryCoated      = "stationery-coated"
+	MediaTypeStationeryFine        = "stationery-fine"
+	MediaTypeStationeryHeavyweight = "stationery-heavyweight"
+	MediaTypeStationeryInkjet      = "stationery-inkjet"
+	MediaTypeStationeryLetterhead  = "stationery-letterhead"
+	MediaTypeStationeryLightweight = "stationery-lightweight"
+	MediaTypeStationeryPreprinted  = "stationery-preprinted"
+	MediaTypeStationeryPrepunched  = "stationery-prepunched"
+	MediaTypeStationeryRecycled    = "stationery-recycled"
+	MediaTypeTransparency          = "transparency"
+)
+
+// output bins
+const (
+	OutputBinAuto          = "auto"
+	OutputBinBottom        = "bottom"
+	OutputBinCenter        = "center"
+	OutputBinFaceDown      = "face-down"
+	OutputBinFaceUp        = "face-up"
+	OutputBinLargeCapacity = "large-capacity"
+	OutputBinLeft          = "left"
+	OutputBinMiddle        = "middle"
+	OutputBinMyMailbox     = "my-mailbox"
+	OutputBinRear          = "rear"
+	OutputBinRight         = "right"
+	OutputBinSide          = "side"
+	OutputBinTop           = "top"
+)
+
+// page deliveries
+const (
+	PageDeliveryReverseOrderFaceDown = "reverse-order-face-down"
+	PageDeliveryReverseOrderFaceUp   = "reverse-order-face-up"
+	PageDeliverySameOrderFaceDown    = "same-order-face-down"
+	PageDeliverySameOrderFaceUp      = "same-order-face-up"
+	PageDeliverySystemSpecified      = "system-specified"
 )
 
 // error policies
@@ -275,6 +350,29 @@ const (
 	ErrorPolicyStopPrinter     = "stop-printer"
 )
 
+// notification events
+const (
+	EventNone                     = "none"
+	EventPrinterStateChanged      = "printer-state-changed"
+	EventPrinterStopped           = "printer-stopped"
+	EventPrinterConfigChanged     = "printer-config-changed"
+	EventPrinterQueueChanged      = "printer-queue-order-changed"
+	EventJobCreated               = "job-created"
+	EventJobCompleted             = "job-completed"
+	EventJobStateChanged          = "job-state-changed"
+	EventJobProgress              = "job-progress"
+	EventJobConfigChanged         = "job-config-changed"
+	EventPrinterRestarted         = "printer-restarted"
+	EventPrinterShutdown          = "printer-shutdown"
+	EventPrinterMediaChanged      = "printer-media-changed"
+	EventPrinterFinishingsChanged = "printer-finishings-changed"
+)
+
+// notification pull methods
+const (
+	PullMethodIppGet = "ippget"
+)
+
 // ipp defaults
 const (
 	CharsetLanguage      = "en-US"
@@ -289,6 +387,9 @@ const (
 const (
 	MimeTypePostscript  = "application/postscript"
 	MimeTypeOctetStream = "application/octet-stream"
+	MimeTypePDF         = "application/pdf"
+	MimeTypePwgRaster   = "image/pwg-raster"
+	MimeTypeUrf         = "image/urf"
 )
 
 // ipp content types
@@ -298,71 +399,397 @@ const (
 
 // known ipp attributes
 const (
-	AttributeCopies                  = "copies"
-	AttributeDocumentFormat          = "document-format"
-	AttributeDocumentName            = "document-name"
-	AttributeJobID                   = "job-id"
-	AttributeJobName                 = "job-name"
-	AttributeJobPriority             = "job-priority"
-	AttributeJobURI                  = "job-uri"
-	AttributeLastDocument            = "last-document"
-	AttributeMyJobs                  = "my-jobs"
-	AttributePPDName                 = "ppd-name"
-	AttributePPDMakeAndModel         = "ppd-make-and-model"
-	AttributePrinterIsShared         = "printer-is-shared"
-	AttributePrinterIsTemporary      = "printer-is-temporary"
-	AttributePrinterURI              = "printer-uri"
-	AttributePurgeJobs               = "purge-jobs"
-	AttributeRequestedAttributes     = "requested-attributes"
-	AttributeRequestingUserName      = "requesting-user-name"
-	AttributeWhichJobs               = "which-jobs"
-	AttributeFirstJobID              = "first-job-id"
-	AttributeLimit                   = "limit"
-	AttributeStatusMessage           = "status-message"
-	AttributeCharset                 = "attributes-charset"
-	AttributeNaturalLanguage         = "attributes-natural-language"
-	AttributeDeviceURI               = "device-uri"
-	AttributeHoldJobUntil            = "job-hold-until"
-	AttributePrinterErrorPolicy      = "printer-error-policy"
-	AttributePrinterInfo             = "printer-info"
-	AttributePrinterLocation         = "printer-location"
-	AttributePrinterName             = "printer-name"
-	AttributePrinterStateReasons     = "printer-state-reasons"
-	AttributeJobPrinterURI           = "job-printer-uri"
-	AttributeMemberURIs              = "member-uris"
-	AttributeDocumentNumber          = "document-number"
-	AttributeDocumentState           = "document-state"
-	AttributeFinishings              = "finishings"
-	AttributeJobHoldUntil            = "hold-job-until"
-	AttributeJobSheets               = "job-sheets"
-	AttributeJobState                = "job-state"
-	AttributeJobStateReason          = "job-state-reason"
-	AttributeMedia                   = "media"
-	AttributeSides                   = "sides"
-	AttributeNumberUp                = "number-up"
-	AttributeOrientationRequested    = "orientation-requested"
-	AttributePrintQuality            = "print-quality"
-	AttributePrinterIsAcceptingJobs  = "printer-is-accepting-jobs"
-	AttributePrinterResolution       = "printer-resolution"
-	AttributePrinterState            = "printer-state"
-	AttributeMemberNames             = "member-names"
-	AttributePrinterType             = "printer-type"
-	AttributePrinterMakeAndModel     = "printer-make-and-model"
-	AttributePrinterStateMessage     = "printer-state-message"
-	AttributePrinterUriSupported     = "printer-uri-supported"
-	AttributeJobMediaProgress        = "job-media-progress"
-	AttributeJobKilobyteOctets       = "job-k-octets"
-	AttributeNumberOfDocuments       = "number-of-documents"
-	AttributeJobOriginatingUserName  = "job-originating-user-name"
-	AttributeOutputOrder             = "outputorder"
-	AttributeJobStateReasons         = "job-state-reasons"
-	AttributeJobStateMessage         = "job-state-message"
-	AttributeJobPrinterStateReasons  = "job-printer-state-reasons"
-	AttributeJobPrinterStateMessage  = "job-printer-state-message"
-	AttributeJobImpressionsCompleted = "job-impressions-completed"
-	AttributePrintScaling            = "print-scaling"
-	AttributePrintColorMode          = "print-color-mode"
-	AttributePageRanges              = "page-ranges"
+	AttributeCopies                                 = "copies"
+	AttributeDocumentFormat                         = "document-format"
+	AttributeDocumentName                           = "document-name"
+	AttributeJobID                                  = "job-id"
+	AttributeJobName                                = "job-name"
+	AttributeJobPriority                            = "job-priority"
+	AttributeJobURI                                 = "job-uri"
+	AttributeLastDocument                           = "last-document"
+	AttributeMyJobs                                 = "my-jobs"
+	AttributePPDName                                = "ppd-name"
+	AttributePPDMakeAndModel                        = "ppd-make-and-model"
+	AttributePrinterIsShared                        = "printer-is-shared"
+	AttributePrinterIsTemporary                     = "printer-is-temporary"
+	AttributePrinterURI                             = "printer-uri"
+	AttributePurgeJobs                              = "purge-jobs"
+	AttributeRequestedAttributes                    = "requested-attributes"
+	AttributeRequestingUserName                     = "requesting-user-name"
+	AttributeWhichJobs                              = "which-jobs"
+	AttributeFirstJobID                             = "first-job-id"
+	AttributeLimit                                  = "limit"
+	AttributeStatusMessage                          = "status-message"
+	AttributeCharset                                = "attributes-charset"
+	AttributeNaturalLanguage                        = "attributes-natural-language"
+	AttributeDeviceURI                              = "device-uri"
+	AttributeHoldJobUntil                           = "job-hold-until"
+	AttributePrinterErrorPolicy                     = "printer-error-policy"
+	AttributePrinterInfo                            = "printer-info"
+	AttributePrinterLocation                        = "printer-location"
+	AttributePrinterName                            = "printer-name"
+	AttributePrinterStateReasons                    = "printer-state-reasons"
+	AttributeJobPrinterURI                          = "job-printer-uri"
+	AttributeMemberURIs                             = "member-uris"
+	AttributeDocumentNumber                         = "document-number"
+	AttributeDocumentState                          = "document-state"
+	AttributeFinishings                             = "finishings"
+	AttributeJobHoldUntil                           = "hold-job-until"
+	AttributeJobSheets                              = "job-sheets"
+	AttributeJobState                               = "job-state"
+	AttributeJobStateReason                         = "job-state-reason"
+	AttributeMedia                                  = "media"
+	AttributeSides                                  = "sides"
+	AttributeNumberUp                               = "number-up"
+	AttributeOrientationRequested                   = "orientation-requested"
+	AttributePrintQuality                           = "print-quality"
+	AttributePrinterIsAcceptingJobs                 = "printer-is-accepting-jobs"
+	AttributePrinterResolution                      = "printer-resolution"
+	AttributePrinterState                           = "printer-state"
+	AttributeMemberNames                            = "member-names"
+	AttributePrinterType                            = "printer-type"
+	AttributePrinterMakeAndModel                    = "printer-make-and-model"
+	AttributePrinterStateMessage                    = "printer-state-message"
+	AttributePrinterUriSupported                    = "printer-uri-supported"
+	AttributeJobMediaProgress                       = "job-media-progress"
+	AttributeJobKilobyteOctets                      = "job-k-octets"
+	AttributeNumberOfDocuments                      = "number-of-documents"
+	AttributeJobOriginatingUserName                 = "job-originating-user-name"
+	AttributeOutputOrder                            = "outputorder"
+	AttributeJobStateReasons                        = "job-state-reasons"
+	AttributeJobStateMessage                        = "job-state-message"
+	AttributeJobPrinterStateReasons                 = "job-printer-state-reasons"
+	AttributeJobPrinterStateMessage                 = "job-printer-state-message"
+	AttributeJobImpressionsCompleted                = "job-impressions-completed"
+	AttributePrintScaling                           = "print-scaling"
+	AttributePrintColorMode                         = "print-color-mode"
+	AttributePageRanges                             = "page-ranges"
+	AttributeMediaSource                            = "media-source"
+	AttributeMediaType                              = "media-type"
+	AttributeOutputBin                              = "output-bin"
+	AttributeDocumentURI                            = "document-uri"
+	AttributeNotifySubscriptionID                   = "notify-subscription-id"
+	AttributeDetailedStatusMessage                  = "detailed-status-message"
+	AttributeTimeAtCreation                         = "time-at-creation"
+	AttributeTimeAtProcessing                       = "time-at-processing"
+	AttributeTimeAtCompleted                        = "time-at-completed"
+	AttributeJobPrinterUpTime                       = "job-printer-up-time"
+	AttributePrinterUpTime                          = "printer-up-time"
+	AttributePrinterUUID                            = "printer-uuid"
+	AttributeQueuedJobCount                         = "queued-job-count"
+	AttributeOperationsSupported                    = "operations-supported"
+	AttributeDocumentFormatSupported                = "document-format-supported"
+	AttributeDocumentFormatDefault                  = "document-format-default"
+	AttributeCharsetConfigured                      = "charset-configured"
+	AttributeCharsetSupported                       = "charset-supported"
+	AttributeNaturalLanguageConfigured              = "natural-language-configured"
+	AttributeGeneratedNaturalLanguageSupported      = "generated-natural-language-supported"
+	AttributeIppVersionsSupported                   = "ipp-versions-supported"
+	AttributeCompression                            = "compression"
+	AttributeCompressionSupported                   = "compression-supported"
+	AttributePdlOverrideSupported                   = "pdl-override-supported"
+	AttributeUriSecuritySupported                   = "uri-security-supported"
+	AttributeUriAuthenticationSupported             = "uri-authentication-supported"
+	AttributeIdentifyActions                        = "identify-actions"
+	AttributeIdentifyActionsSupported               = "identify-actions-supported"
+	AttributeMessage                                = "message"
+	AttributeJobUUID                                = "job-uuid"
+	AttributeColorSupported                         = "color-supported"
+	AttributeSidesSupported                         = "sides-supported"
+	AttributeUrfSupported                           = "urf-supported"
+	AttributePrinterMoreInfo                        = "printer-more-info"
+	AttributeCopiesDefault                          = "copies-default"
+	AttributeCopiesSupported                        = "copies-supported"
+	AttributeFinishingsDefault                      = "finishings-default"
+	AttributeFinishingsSupported                    = "finishings-supported"
+	AttributeIdentifyActionsDefault                 = "identify-actions-default"
+	AttributeIppFeaturesSupported                   = "ipp-features-supported"
+	AttributeJobCreationAttributesSupported         = "job-creation-attributes-supported"
+	AttributeMediaDefault                           = "media-default"
+	AttributeMediaSupported                         = "media-supported"
+	AttributeMediaReady                             = "media-ready"
+	AttributeMediaSourceSupported                   = "media-source-supported"
+	AttributeMediaTypeSupported                     = "media-type-supported"
+	AttributeMediaBottomMarginSupported             = "media-bottom-margin-supported"
+	AttributeMediaLeftMarginSupported               = "media-left-margin-supported"
+	AttributeMediaRightMarginSupported              = "media-right-margin-supported"
+	AttributeMediaTopMarginSupported                = "media-top-margin-supported"
+	AttributeMultipleDocumentJobsSupported          = "multiple-document-jobs-supported"
+	AttributeMultipleOperationTimeOut               = "multiple-operation-time-out"
+	AttributeOrientationRequestedDefault            = "orientation-requested-default"
+	AttributeOrientationRequestedSupported          = "orientation-requested-supported"
+	AttributeOutputBinDefault                       = "output-bin-default"
+	AttributeOutputBinSupported                     = "output-bin-supported"
+	AttributePageRangesSupported                    = "page-ranges-supported"
+	AttributePrintColorModeDefault                  = "print-color-mode-default"
+	AttributePrintColorModeSupported                = "print-color-mode-supported"
+	AttributePrintQualityDefault                    = "print-quality-default"
+	AttributePrintQualitySupported                  = "print-quality-supported"
+	AttributePrinterGeoLocation                     = "printer-geo-location"
+	AttributePrinterOrganization                    = "printer-organization"
+	AttributePrinterOrganizationalUnit              = "printer-organizational-unit"
+	AttributePrinterResolutionDefault               = "printer-resolution-default"
+	AttributePrinterResolutionSupported             = "printer-resolution-supported"
+	AttributePrinterKind                            = "printer-kind"
+	AttributePrinterDeviceID                        = "printer-device-id"
+	AttributePwgRasterDocumentResolutionSupported   = "pwg-raster-document-resolution-supported"
+	AttributePwgRasterDocumentSheetBack             = "pwg-raster-document-sheet-back"
+	AttributePwgRasterDocumentTypeSupported         = "pwg-raster-document-type-supported"
+	AttributeSidesDefault                           = "sides-default"
+	AttributeWhichJobsSupported                     = "which-jobs-supported"
+	AttributeJobIdsSupported                        = "job-ids-supported"
+	AttributePrinterGetAttributesSupported          = "printer-get-attributes-supported"
+	AttributeNotifySubscriptionIDs                  = "notify-subscription-ids"
+	AttributeNotifySequenceNumbers                  = "notify-sequence-numbers"
+	AttributeNotifyWait                             = "notify-wait"
+	AttributeNotifyGetInterval                      = "notify-get-interval"
+	AttributeNotifyEvents                           = "notify-events"
+	AttributeNotifyEventsDefault                    = "notify-events-default"
+	AttributeNotifyEventsSupported                  = "notify-events-supported"
+	AttributeNotifyPullMethod                       = "notify-pull-method"
+	AttributeNotifyPullMethodSupported              = "notify-pull-method-supported"
+	AttributeNotifyRecipientURI                     = "notify-recipient-uri"
+	AttributeNotifySchemesSupported                 = "notify-schemes-supported"
+	AttributeNotifyLeaseDuration                    = "notify-lease-duration"
+	AttributeNotifyLeaseDurationDefault             = "notify-lease-duration-default"
+	AttributeNotifyLeaseDurationSupported           = "notify-lease-duration-supported"
+	AttributeNotifyJobID                            = "notify-job-id"
+	AttributeNotifyTimeInterval                     = "notify-time-interval"
+	AttributeNotifyUserData                         = "notify-user-data"
+	AttributeNotifyCharset                          = "notify-charset"
+	AttributeNotifyNaturalLanguage                  = "notify-natural-language"
+	AttributeNotifySubscriberUserName               = "notify-subscriber-user-name"
+	AttributeNotifyPrinterURI                       = "notify-printer-uri"
+	AttributeNotifySequenceNumber                   = "notify-sequence-number"
+	AttributeNotifySubscribedEvent                  = "notify-subscribed-event"
+	AttributeNotifyText                             = "notify-text"
+	AttributeNotifyStatusCode                       = "notify-status-code"
+	AttributeNotifyMaxEventsSupported               = "notify-max-events-supported"
+	AttributeMySubscriptions                        = "my-subscriptions"
+	AttributeMediaCol                               = "media-col"
+	AttributeFinishingsCol                          = "finishings-col"
+	AttributeMultipleDocumentHandling               = "multiple-document-handling"
+	AttributeJobHoldUntilSupported                  = "job-hold-until-supported"
+	AttributeJobHoldUntilDefault                    = "job-hold-until-default"
+	AttributeJobPrioritySupported                   = "job-priority-supported"
+	AttributeJobPriorityDefault                     = "job-priority-default"
+	AttributeJobSheetsDefault                       = "job-sheets-default"
+	AttributeJobSheetsSupported                     = "job-sheets-supported"
+	AttributeJobQuotaPeriod                         = "job-quota-period"
+	AttributeJobPageLimit                           = "job-page-limit"
+	AttributeJobKLimit                              = "job-k-limit"
+	AttributeJobPagesUsed                           = "job-pages-used"
+	AttributeJobKOctetsUsed                         = "job-k-octets-used"
+	AttributeJobImpressions                         = "job-impressions"
+	AttributePrinterStringsLanguagesSupported       = "printer-strings-languages-supported"
+	AttributePrinterStringsURI                      = "printer-strings-uri"
+	AttributePrinterIcons                           = "printer-icons"
+	AttributeDateTimeAtCreation                     = "date-time-at-creation"
+	AttributeDateTimeAtProcessing                   = "date-time-at-processing"
+	AttributeDateTimeAtCompleted                    = "date-time-at-completed"
+	AttributeMediaColDefault                        = "media-col-default"
+	AttributeMediaColReady                          = "media-col-ready"
+	AttributeMediaColSupported                      = "media-col-supported"
+	AttributeMediaSize                              = "media-size"
+	AttributeMediaSizeName                          = "media-size-name"
+	AttributeXDimension                             = "x-dimension"
+	AttributeYDimension                             = "y-dimension"
+	AttributeMediaBottomMargin                      = "media-bottom-margin"
+	AttributeMediaLeftMargin                        = "media-left-margin"
+	AttributeMediaRightMargin                       = "media-right-margin"
+	AttributeMediaTopMargin                         = "media-top-margin"
+	AttributeMediaColor                             = "media-color"
+	AttributeMediaColDatabase                       = "media-col-database"
+	AttributeMediaSizeSupported                     = "media-size-supported"
+	AttributeDocumentNaturalLanguage                = "document-natural-language"
+	AttributeDocumentFormatDetails                  = "document-format-details"
+	AttributeDocumentMessage                        = "document-message"
+	AttributeFirstIndex                             = "first-index"
+	AttributeIppAttributeFidelity                   = "ipp-attribute-fidelity"
+	AttributeJobIDs                                 = "job-ids"
+	AttributeJobMandatoryAttributes                 = "job-mandatory-attributes"
+	AttributeJobMediaSheets                         = "job-media-sheets"
+	AttributeJobPassword                            = "job-password"
+	AttributeJobPasswordEncryption                  = "job-password-encryption"
+	AttributeOriginalRequestingUserName             = "original-requesting-user-name"
+	AttributePreferredAttributes                    = "preferred-attributes"
+	AttributePrinterMessageFromOperator             = "printer-message-from-operator"
+	AttributeRequestingUserURI                      = "requesting-user-uri"
+	AttributeJobMessageFromOperator                 = "job-message-from-operator"
+	AttributeJobMessageToOperator                   = "job-message-to-operator"
+	AttributeDocumentFormatSupplied                 = "document-format-supplied"
+	AttributeDocumentNameSupplied                   = "document-name-supplied"
+	AttributeJobAccountID                           = "job-account-id"
+	AttributeJobAccountingUserID                    = "job-accounting-user-id"
+	AttributeJobAccountType                         = "job-account-type"
+	AttributeJobCopies                              = "job-copies"
+	AttributeProofPrint                             = "proof-print"
+	AttributeJobCancelAfter                         = "job-cancel-after"
+	AttributeJobDelayOutputUntil                    = "job-delay-output-until"
+	AttributeJobErrorAction                         = "job-error-action"
+	AttributeJobPhoneNumber                         = "job-phone-number"
+	AttributeJobRecipientName                       = "job-recipient-name"
+	AttributeJobRetainUntil                         = "job-retain-until"
+	AttributeJobSheetsCol                           = "job-sheets-col"
+	AttributeCoverBack                              = "cover-back"
+	AttributeCoverFront                             = "cover-front"
+	AttributeFeedOrientation                        = "feed-orientation"
+	AttributeFontNameRequested                      = "font-name-requested"
+	AttributeFontSizeRequested                      = "font-size-requested"
+	AttributeImpositionTemplate                     = "imposition-template"
+	AttributeInsertSheet                            = "insert-sheet"
+	AttributeOverrides                              = "overrides"
+	AttributePageDelivery                           = "page-delivery"
+	AttributePresentationDirectionNumberUp          = "presentation-direction-number-up"
+	AttributePrintContentOptimize                   = "print-content-optimize"
+	AttributePrintDarkness                          = "print-darkness"
+	AttributePrintRenderingIntent                   = "print-rendering-intent"
+	AttributeSeparatorSheets                        = "separator-sheets"
+	AttributeXImagePosition                         = "x-image-position"
+	AttributeXImageShift                            = "x-image-shift"
+	AttributeYImagePosition                         = "y-image-position"
+	AttributeYImageShift                            = "y-image-shift"
+	AttributeJobDetailedStatusMessages              = "job-detailed-status-messages"
+	AttributeJobDocumentAccessErrors                = "job-document-access-errors"
+	AttributeJobKOctetsProcessed                    = "job-k-octets-processed"
+	AttributeJobMediaSheetsCompleted                = "job-media-sheets-completed"
+	AttributeJobMoreInfo                            = "job-more-info"
+	AttributeJobPages                               = "job-pages"
+	AttributeJobPagesCompleted                      = "job-pages-completed"
+	AttributeNumberOfInterveningJobs                = "number-of-intervening-jobs"
+	AttributeOutputDeviceAssigned                   = "output-device-assigned"
+	AttributeCoverBackSupported                     = "cover-back-supported"
+	AttributeCoverFrontSupported                    = "cover-front-supported"
+	AttributeDocumentPasswordSupported              = "document-password-supported"
+	AttributeFeedOrientationSupported               = "feed-orientation-supported"
+	AttributeFinishingsColDatabase                  = "finishings-col-database"
+	AttributeFinishingsColDefault                   = "finishings-col-default"
+	AttributeFinishingsColReady                     = "finishings-col-ready"
+	AttributeFinishingsColSupported                 = "finishings-col-supported"
+	AttributeFinishingsReady                        = "finishings-ready"
+	AttributeJobAccountIDDefault                    = "job-account-id-default"
+	AttributeJobAccountIDSupported                  = "job-account-id-supported"
+	AttributeJobAccountingUserIDDefault             = "job-accounting-user-id-default"
+	AttributeJobAccountingUserIDSupported           = "job-accounting-user-id-supported"
+	AttributeJobAccountTypeDefault                  = "job-account-type-default"
+	AttributeJobAccountTypeSupported                = "job-account-type-supported"
+	AttributeJobCopiesDefault                       = "job-copies-default"
+	AttributeJobCopiesSupported                     = "job-copies-supported"
+	AttributeProofPrintDefault                      = "proof-print-default"
+	AttributeProofPrintSupported                    = "proof-print-supported"
+	AttributeDocumentFormatDetailsSupported         = "document-format-details-supported"
+	AttributeJobCancelAfterDefault                  = "job-cancel-after-default"
+	AttributeJobCancelAfterSupported                = "job-cancel-after-supported"
+	AttributeJobConstraintsSupported                = "job-constraints-supported"
+	AttributeJobDelayOutputUntilDefault             = "job-delay-output-until-default"
+	AttributeJobDelayOutputUntilSupported           = "job-delay-output-until-supported"
+	AttributeJobErrorActionDefault                  = "job-error-action-default"
+	AttributeJobErrorActionSupported                = "job-error-action-supported"
+	AttributeJobImpressionsSupported                = "job-impressions-supported"
+	AttributeJobKOctetsSupported                    = "job-k-octets-supported"
+	AttributeJobMediaSheetsSupported                = "job-media-sheets-supported"
+	AttributeJobPasswordEncryptionSupported         = "job-password-encryption-supported"
+	AttributeJobPasswordSupported                   = "job-password-supported"
+	AttributeJobPreferredAttributesSupported        = "job-preferred-attributes-supported"
+	AttributeJobResolversSupported                  = "job-resolvers-supported"
+	AttributeJobRetainUntilDefault                  = "job-retain-until-default"
+	AttributeJobRetainUntilSupported                = "job-retain-until-supported"
+	AttributeJpegKOctetsSupported                   = "jpeg-k-octets-supported"
+	AttributeJpegXDimensionSupported                = "jpeg-x-dimension-supported"
+	AttributeJpegYDimensionSupported                = "jpeg-y-dimension-supported"
+	AttributeLandscapeOrientationRequestedPreferred = "landscape-orientation-requested-preferred"
+	AttributeMediaColorSupported                    = "media-color-supported"
+	AttributeMultipleDocumentHandlingDefault        = "multiple-document-handling-default"
+	AttributeMultipleDocumentHandlingSupported      = "multiple-document-handling-supported"
+	AttributeNumberUpDefault                        = "number-up-default"
+	AttributeNumberUpSupported                      = "number-up-supported"
+	AttributeOverridesSupported                     = "overrides-supported"
+	AttributePageDeliveryDefault                    = "page-delivery-default"
+	AttributePageDeliverySupported                  = "page-delivery-supported"
+	AttributePagesPerMinute                         = "pages-per-minute"
+	AttributePagesPerMinuteColor                    = "pages-per-minute-color"
+	AttributePdfKOctetsSupported                    = "pdf-k-octets-supported"
+	AttributePdfVersionsSupported                   = "pdf-versions-supported"
+	AttributePreferredAttributesSupported           = "preferred-attributes-supported"
+	AttributePresentationDirectionNumberUpDefault   = "presentation-direction-number-up-default"
+	AttributePresentationDirectionNumberUpSupported = "presentation-direction-number-up-supported"
+	AttributePrintContentOptimizeDefault            = "print-content-optimize-default"
+	AttributePrintContentOptimizeSupported          = "print-content-optimize-supported"
+	AttributePrintDarknessDefault                   = "print-darkness-default"
+	AttributePrintDarknessSupported                 = "print-darkness-supported"
+	AttributePrintRenderingIntentDefault            = "print-rendering-intent-default"
+	AttributePrintRenderingIntentSupported          = "print-rendering-intent-supported"
+	AttributePrintScalingDefault                    = "print-scaling-default"
+	AttributePrintScalingSupported                  = "print-scaling-supported"
+	AttributePrinterAlert                           = "printer-alert"
+	AttributePrinterAlertDescription                = "printer-alert-description"
+	AttributePrinterChargeInfo                      = "printer-charge-info"
+	AttributePrinterChargeInfoURI                   = "printer-charge-info-uri"
+	AttributePrinterConfigChangeDateTime            = "printer-config-change-date-time"
+	AttributePrinterConfigChangeTime                = "printer-config-change-time"
+	AttributePrinterCurrentTime                     = "printer-current-time"
+	AttributePrinterDarknessConfigured              = "printer-darkness-configured"
+	AttributePrinterDarknessSupported               = "printer-darkness-supported"
+	AttributePrinterDnsSdName                       = "printer-dns-sd-name"
+	AttributePrinterDriverInstaller                 = "printer-driver-installer"
+	AttributePrinterFinisher                        = "printer-finisher"
+	AttributePrinterFinisherDescription             = "printer-finisher-description"
+	AttributePrinterFirmwareName                    = "printer-firmware-name"
+	AttributePrinterFirmwareStringVersion           = "printer-firmware-string-version"
+	AttributePrinterFirmwareVersion                 = "printer-firmware-version"
+	AttributePrinterIccProfiles                     = "printer-icc-profiles"
+	AttributePrinterImpressionsCompleted            = "printer-impressions-completed"
+	AttributePrinterInputTray                       = "printer-input-tray"
+	AttributePrinterMandatoryJobAttributes          = "printer-mandatory-job-attributes"
+	AttributePrinterMediaSheetsCompleted            = "printer-media-sheets-completed"
+	AttributePrinterMoreInfoManufacturer            = "printer-more-info-manufacturer"
+	AttributePrinterOutputTray                      = "printer-output-tray"
+	AttributePrinterPagesCompleted                  = "printer-pages-completed"
+	AttributePrinterStateChangeDateTime             = "printer-state-change-date-time"
+	AttributePrinterStateChangeTime                 = "printer-state-change-time"
+	AttributePrinterSupply                          = "printer-supply"
+	AttributePrinterSupplyDescription               = "printer-supply-description"
+	AttributePrinterSupplyInfoURI                   = "printer-supply-info-uri"
+	AttributePrinterXriSupported                    = "printer-xri-supported"
+	AttributeReferenceURISchemesSupported           = "reference-uri-schemes-supported"
+	AttributeJobPagesPerSetSupported                = "job-pages-per-set-supported"
+	AttributeInsertSheetSupported                   = "insert-sheet-supported"
+	AttributeJobSheetsColSupported                  = "job-sheets-col-supported"
+	AttributeSeparatorSheetsSupported               = "separator-sheets-supported"
+	AttributeXImagePositionSupported                = "x-image-position-supported"
+	AttributeXImageShiftSupported                   = "x-image-shift-supported"
+	AttributeYImagePositionSupported                = "y-image-position-supported"
+	AttributeYImageShiftSupported                   = "y-image-shift-supported"
+	AttributeFinishingTemplate                      = "finishing-template"
+	AttributeStitching                              = "stitching"
+	AttributeStitchingLocations                     = "stitching-locations"
+	AttributeStitchingOffset                        = "stitching-offset"
+	AttributeStitchingReferenceEdge                 = "stitching-reference-edge"
+	AttributeStitchingMethod                        = "stitching-method"
+	AttributePunching                               = "punching"
+	AttributePunchingLocations                      = "punching-locations"
+	AttributePunchingOffset                         = "punching-offset"
+	AttributePunchingReferenceEdge                  = "punching-reference-edge"
+	AttributeFolding                                = "folding"
+	AttributeFoldingDirection                       = "folding-direction"
+	AttributeFoldingOffset                          = "folding-offset"
+	AttributeFoldingReferenceEdge                   = "folding-reference-edge"
+	AttributeProofPrintCopies                       = "proof-print-copies"
+	AttributeDocumentFormatDeviceID                 = "document-format-device-id"
+	AttributeDocumentFormatVersion                  = "document-format-version"
+	AttributeDocumentSourceApplicationName          = "document-source-application-name"
+	AttributeDocumentSourceApplicationVersion       = "document-source-application-version"
+	AttributeDocumentSourceOsName                   = "document-source-os-name"
+	AttributeDocumentSourceOsVersion                = "document-source-os-version"
+	AttributeResolverName                           = "resolver-name"
+	AttributeMarkerNames                            = "marker-names"
+	AttributeMarkerTypes                            = "marker-types"
+	AttributeMarkerColors                           = "marker-colors"
+	AttributeMarkerLevels                           = "marker-levels"
+	AttributeMarkerLowLevels                        = "marker-low-levels"
+	AttributeMarkerHighLevels                       = "marker-high-levels"
+	AttributeMarkerMessage                          = "marker-message"
+	AttributeMarkerChangeTime                       = "marker-change-time"
 )
 
 // Default attributes
@@ -383,62 +810,388 @@ var (
 // Attribute to tag mapping
 var (
 	AttributeTagMapping = map[string]int8{
-		AttributeCharset:                 TagCharset,
-		AttributeNaturalLanguage:         TagLanguage,
-		AttributeCopies:                  TagInteger,
-		AttributeDeviceURI:               TagUri,
-		AttributeDocumentFormat:          TagMimeType,
-		AttributeDocumentName:            TagName,
-		AttributeDocumentNumber:          TagInteger,
-		AttributeDocumentState:           TagEnum,
-		AttributeFinishings:              TagEnum,
-		AttributeJobHoldUntil:            TagKeyword,
-		AttributeHoldJobUntil:            TagKeyword,
-		AttributeJobID:                   TagInteger,
-		AttributeJobName:                 TagName,
-		AttributeJobPrinterURI:           TagUri,
-		AttributeJobPriority:             TagInteger,
-		AttributeJobSheets:               TagName,
-		AttributeJobState:                TagEnum,
-		AttributeJobStateReason:          TagKeyword,
-		AttributeJobURI:                  TagUri,
-		AttributeLastDocument:            TagBoolean,
-		AttributeMedia:                   TagKeyword,
-		AttributeSides:                   TagKeyword,
-		AttributeMemberURIs:              TagUri,
-		AttributeMyJobs:                  TagBoolean,
-		AttributeNumberUp:                TagInteger,
-		AttributeOrientationRequested:    TagEnum,
-		AttributePPDName:                 TagName,
-		AttributePPDMakeAndModel:         TagText,
-		AttributeNumberOfDocuments:       TagInteger,
-		AttributePrintQuality:            TagEnum,
-		AttributePrinterErrorPolicy:      TagName,
-		AttributePrinterInfo:             TagText,
-		AttributePrinterIsAcceptingJobs:  TagBoolean,
-		AttributePrinterIsShared:         TagBoolean,
-		AttributePrinterIsTemporary:      TagBoolean,
-		AttributePrinterName:             TagName,
-		AttributePrinterLocation:         TagText,
-		AttributePrinterResolution:       TagResolution,
-		AttributePrinterState:            TagEnum,
-		AttributePrinterStateReasons:     TagKeyword,
-		AttributePrinterURI:              TagUri,
-		AttributePurgeJobs:               TagBoolean,
-		AttributeRequestedAttributes:     TagKeyword,
-		AttributeRequestingUserName:      TagName,
-		AttributeWhichJobs:               TagKeyword,
-		AttributeFirstJobID:              TagInteger,
-		AttributeStatusMessage:           TagText,
-		AttributeLimit:                   TagInteger,
-		AttributeOutputOrder:             TagName,
-		AttributeJobStateReasons:         TagString,
-		AttributeJobStateMessage:         TagString,
-		AttributeJobPrinterStateReasons:  TagString,
-		AttributeJobPrinterStateMessage:  TagString,
-		AttributeJobImpressionsCompleted: TagInteger,
-		AttributePrintScaling:            TagKeyword,
-		AttributePrintColorMode:          TagKeyword,
-		AttributePageRanges:              TagKeyword,
+		AttributeCharset:                                TagCharset,
+		AttributeNaturalLanguage:                        TagLanguage,
+		AttributeCopies:                                 TagInteger,
+		AttributeDeviceURI:                              TagUri,
+		AttributeDocumentFormat:                         TagMimeType,
+		AttributeDocumentName:                           TagName,
+		AttributeDocumentNumber:                         TagInteger,
+		AttributeDocumentState:                          TagEnum,
+		AttributeFinishings:                             TagEnum,
+		AttributeJobHoldUntil:                           TagKeyword,
+		AttributeHoldJobUntil:                           TagKeyword,
+		AttributeJobID:                                  TagInteger,
+		AttributeJobName:                                TagName,
+		AttributeJobPrinterURI:                          TagUri,
+		AttributeJobPriority:                            TagInteger,
+		AttributeJobSheets:                              TagName,
+		AttributeJobState:                               TagEnum,
+		AttributeJobStateReason:                         TagKeyword,
+		AttributeJobURI:                                 TagUri,
+		AttributeLastDocument:                           TagBoolean,
+		AttributeMedia:                                  TagKeyword,
+		AttributeSides:                                  TagKeyword,
+		AttributeMemberURIs:                             TagUri,
+		AttributeMyJobs:                                 TagBoolean,
+		AttributeNumberUp:                               TagInteger,
+		AttributeOrientationRequested:                   TagEnum,
+		AttributePPDName:                                TagName,
+		AttributePPDMakeAndModel:                        TagText,
+		AttributeNumberOfDocuments:                      TagInteger,
+		AttributePrintQuality:                           TagEnum,
+		AttributePrinterErrorPolicy:                     TagName,
+		AttributePrinterInfo:                            TagText,
+		AttributePrinterIsAcceptingJobs:                 TagBoolean,
+		AttributePrinterIsShared:                        TagBoolean,
+		AttributePrinterIsTemporary:                     TagBoolean,
+		AttributePrinterName:                            TagName,
+		AttributePrinterLocation:                        TagText,
+		AttributePrinterResolution:                      TagResolution,
+		AttributePrinterState:                           TagEnum,
+		AttributePrinterStateReasons:                    TagKeyword,
+		AttributePrinterURI:                             TagUri,
+		AttributePurgeJobs:                              TagBoolean,
+		AttributeRequestedAttributes:                    TagKeyword,
+		AttributeRequestingUserName:                     TagName,
+		AttributeWhichJobs:                              TagKeyword,
+		AttributeFirstJobID:                             TagInteger,
+		AttributeStatusMessage:                          TagText,
+		AttributeLimit:                                  TagInteger,
+		AttributeOutputOrder:                            TagName,
+		AttributeJobStateReasons:                        TagString,
+		AttributeJobStateMessage:                        TagString,
+		AttributeJobPrinterStateReasons:                 TagString,
+		AttributeJobPrinterStateMessage:                 TagString,
+		AttributeJobImpressionsCompleted:                TagInteger,
+		AttributePrintScaling:                           TagKeyword,
+		AttributePrintColorMode:                         TagKeyword,
+		AttributePageRanges:                             TagKeyword,
+		AttributeMediaSource:                            TagKeyword,
+		AttributeMediaType:                              TagKeyword,
+		AttributeOutputBin:                              TagKeyword,
+		AttributeDocumentURI:                            TagUri,
+		AttributeNotifySubscriptionID:                   TagInteger,
+		AttributeDetailedStatusMessage:                  TagText,
+		AttributeTimeAtCreation:                         TagInteger,
+		AttributeTimeAtProcessing:                       TagInteger,
+		AttributeTimeAtCompleted:                        TagInteger,
+		AttributeJobPrinterUpTime:                       TagInteger,
+		AttributePrinterUpTime:                          TagInteger,
+		AttributePrinterUUID:                            TagUri,
+		AttributeQueuedJobCount:                         TagInteger,
+		AttributeOperationsSupported:                    TagEnum,
+		AttributeDocumentFormatSupported:                TagMimeType,
+		AttributeDocumentFormatDefault:                  TagMimeType,
+		AttributeCharsetConfigured:                      TagCharset,
+		AttributeCharsetSupported:                       TagCharset,
+		AttributeNaturalLanguageConfigured:              TagLanguage,
+		AttributeGeneratedNaturalLanguageSupported:      TagLanguage,
+		AttributeIppVersionsSupported:                   TagKeyword,
+		AttributeCompression:                            TagKeyword,
+		AttributeCompressionSupported:                   TagKeyword,
+		AttributePdlOverrideSupported:                   TagKeyword,
+		AttributeUriSecuritySupported:                   TagKeyword,
+		AttributeUriAuthenticationSupported:             TagKeyword,
+		AttributeIdentifyActions:                        TagKeyword,
+		AttributeIdentifyActionsSupported:               TagKeyword,
+		AttributeMessage:                                TagText,
+		AttributeJobUUID:                                TagUri,
+		AttributeColorSupported:                         TagBoolean,
+		AttributeSidesSupported:                         TagKeyword,
+		AttributeUrfSupported:                           TagKeyword,
+		AttributePrinterMoreInfo:                        TagUri,
+		AttributeCopiesDefault:                          TagInteger,
+		AttributeCopiesSupported:                        TagRange,
+		AttributeFinishingsDefault:                      TagEnum,
+		AttributeFinishingsSupported:                    TagEnum,
+		AttributeIdentifyActionsDefault:                 TagKeyword,
+		AttributeIppFeaturesSupported:                   TagKeyword,
+		AttributeJobCreationAttributesSupported:         TagKeyword,
+		AttributeMediaDefault:                           TagKeyword,
+		AttributeMediaSupported:                         TagKeyword,
+		AttributeMediaReady:                             TagKeyword,
+		AttributeMediaSourceSupported:                   TagKeyword,
+		AttributeMediaTypeSupported:                     TagKeyword,
+		AttributeMediaBottomMarginSupported:             TagInteger,
+		AttributeMediaLeftMarginSupported:               TagInteger,
+		AttributeMediaRightMarginSupported:              TagInteger,
+		AttributeMediaTopMarginSupported:                TagInteger,
+		AttributeMultipleDocumentJobsSupported:          TagBoolean,
+		AttributeMultipleOperationTimeOut:               TagInteger,
+		AttributeOrientationRequestedDefault:            TagEnum,
+		AttributeOrientationRequestedSupported:          TagEnum,
+		AttributeOutputBinDefault:                       TagKeyword,
+		AttributeOutputBinSupported:                     TagKeyword,
+		AttributePageRangesSupported:                    TagBoolean,
+		AttributePrintColorModeDefault:                  TagKeyword,
+		AttributePrintColorModeSupported:                TagKeyword,
+		AttributePrintQualityDefault:                    TagEnum,
+		AttributePrintQualitySupported:                  TagEnum,
+		AttributePrinterGeoLocation:                     TagUri,
+		AttributePrinterOrganization:                    TagText,
+		AttributePrinterOrganizationalUnit:              TagText,
+		AttributePrinterResolutionDefault:               TagResolution,
+		AttributePrinterResolutionSupported:             TagResolution,
+		AttributePrinterKind:                            TagKeyword,
+		AttributePrinterDeviceID:                        TagText,
+		AttributePwgRasterDocumentResolutionSupported:   TagResolution,
+		AttributePwgRasterDocumentSheetBack:             TagKeyword,
+		AttributePwgRasterDocumentTypeSupported:         TagKeyword,
+		AttributeSidesDefault:                           TagKeyword,
+		AttributeWhichJobsSupported:                     TagKeyword,
+		AttributeJobIdsSupported:                        TagBoolean,
+		AttributePrinterGetAttributesSupported:          TagKeyword,
+		AttributeNotifySubscriptionIDs:                  TagInteger,
+		AttributeNotifySequenceNumbers:                  TagInteger,
+		AttributeNotifyWait:                             TagBoolean,
+		AttributeNotifyGetInterval:                      TagInteger,
+		AttributeNotifyEvents:                           TagKeyword,
+		AttributeNotifyEventsDefault:                    TagKeyword,
+		AttributeNotifyEventsSupported:                  TagKeyword,
+		AttributeNotifyPullMethod:                       TagKeyword,
+		AttributeNotifyPullMethodSupported:              TagKeyword,
+		AttributeNotifyRecipientURI:                     TagUri,
+		AttributeNotifySchemesSupported:                 TagUriScheme,
+		AttributeNotifyLeaseDuration:                    TagInteger,
+		AttributeNotifyLeaseDurationDefault:             TagInteger,
+		AttributeNotifyLeaseDurationSupported:           TagRange,
+		AttributeNotifyJobID:                            TagInteger,
+		AttributeNotifyTimeInterval:                     TagInteger,
+		AttributeNotifyUserData:                         TagString,
+		AttributeNotifyCharset:                          TagCharset,
+		AttributeNotifyNaturalLanguage:                  TagLanguage,
+		AttributeNotifySubscriberUserName:               TagName,
+		AttributeNotifyPrinterURI:                       TagUri,
+		AttributeNotifySequenceNumber:                   TagInteger,
+		AttributeNotifySubscribedEvent:                  TagKeyword,
+		AttributeNotifyText:                             TagText,
+		AttributeNotifyStatusCode:                       TagEnum,
+		AttributeNotifyMaxEventsSupported:               TagInteger,
+		AttributeMySubscriptions:                        TagBoolean,
+		AttributeMultipleDocumentHandling:               TagKeyword,
+		AttributeJobHoldUntilSupported:                  TagKeyword,
+		AttributeJobHoldUntilDefault:                    TagKeyword,
+		AttributeJobPrioritySupported:                   TagInteger,
+		AttributeJobPriorityDefault:                     TagInteger,
+		AttributeJobSheetsDefault:                       TagKeyword,
+		AttributeJobSheetsSupported:                     TagKeyword,
+		AttributeJobQuotaPeriod:                         TagInteger,
+		AttributeJobPageLimit:                           TagInteger,
+		AttributeJobKLimit:                              TagInteger,
+		AttributeJobPagesUsed:                           TagInteger,
+		AttributeJobKOctetsUsed:                         TagInteger,
+		AttributeJobImpressions:                         TagInteger,
+		AttributePrinterStringsLanguagesSupported:       TagLanguage,
+		AttributePrinterStringsURI:                      TagUri,
+		AttributePrinterIcons:                           TagUri,
+		AttributeDateTimeAtCreation:                     TagDate,
+		AttributeDateTimeAtProcessing:                   TagDate,
+		AttributeDateTimeAtCompleted:                    TagDate,
+		AttributeMediaColDefault:                        TagBeginCollection,
+		AttributeMediaColReady:                          TagBeginCollection,
+		AttributeMediaColSupported:                      TagKeyword,
+		AttributeMediaSize:                              TagBeginCollection,
+		AttributeMediaSizeName:                          TagKeyword,
+		AttributeXDimension:                             TagInteger,
+		AttributeYDimension:                             TagInteger,
+		AttributeMediaBottomMargin:                      TagInteger,
+		AttributeMediaLeftMargin:                        TagInteger,
+		AttributeMediaRightMargin:                       TagInteger,
+		AttributeMediaTopMargin:                         TagInteger,
+		AttributeMediaColor:                             TagKeyword,
+		AttributeMediaCol:                               TagBeginCollection,
+		AttributeMediaColDatabase:                       TagBeginCollection,
+		AttributeMediaSizeSupported:                     TagBeginCollection,
+		AttributeDocumentNaturalLanguage:                TagLanguage,
+		AttributeDocumentFormatDetails:                  TagBeginCollection,
+		AttributeDocumentMessage:                        TagText,
+		AttributeFirstIndex:                             TagInteger,
+		AttributeIppAttributeFidelity:                   TagBoolean,
+		AttributeJobIDs:                                 TagInteger,
+		AttributeJobMandatoryAttributes:                 TagKeyword,
+		AttributeJobMediaSheets:                         TagInteger,
+		AttributeJobPassword:                            TagString,
+		AttributeJobPasswordEncryption:                  TagKeyword,
+		AttributeOriginalRequestingUserName:             TagName,
+		AttributePreferredAttributes:                    TagBeginCollection,
+		AttributePrinterMessageFromOperator:             TagText,
+		AttributeRequestingUserURI:                      TagUri,
+		AttributeJobMessageFromOperator:                 TagText,
+		AttributeJobMessageToOperator:                   TagText,
+		AttributeDocumentFormatSupplied:                 TagMimeType,
+		AttributeDocumentNameSupplied:                   TagName,
+		AttributeJobAccountID:                           TagName,
+		AttributeJobAccountingUserID:                    TagName,
+		AttributeJobAccountType:                         TagKeyword,
+		AttributeJobCopies:                              TagInteger,
+		AttributeProofPrint:                             TagBeginCollection,
+		AttributeJobCancelAfter:                         TagInteger,
+		AttributeJobDelayOutputUntil:                    TagKeyword,
+		AttributeJobErrorAction:                         TagKeyword,
+		AttributeJobPhoneNumber:                         TagUri,
+		AttributeJobRecipientName:                       TagName,
+		AttributeJobRetainUntil:                         TagKeyword,
+		AttributeJobSheetsCol:                           TagBeginCollection,
+		AttributeCoverBack:                              TagBeginCollection,
+		AttributeCoverFront:                             TagBeginCollection,
+		AttributeFeedOrientation:                        TagKeyword,
+		AttributeFontNameRequested:                      TagName,
+		AttributeFontSizeRequested:                      TagInteger,
+		AttributeImpositionTemplate:                     TagKeyword,
+		AttributeInsertSheet:                            TagBeginCollection,
+		AttributeOverrides:                              TagBeginCollection,
+		AttributePageDelivery:                           TagKeyword,
+		AttributePresentationDirectionNumberUp:          TagKeyword,
+		AttributePrintContentOptimize:                   TagKeyword,
+		AttributePrintDarkness:                          TagInteger,
+		AttributePrintRenderingIntent:                   TagKeyword,
+		AttributeSeparatorSheets:                        TagBeginCollection,
+		AttributeXImagePosition:                         TagKeyword,
+		AttributeXImageShift:                            TagInteger,
+		AttributeYImagePosition:                         TagKeyword,
+		AttributeYImageShift:                            TagInteger,
+		AttributeJobDetailedStatusMessages:              TagText,
+		AttributeJobDocumentAccessErrors:                TagText,
+		AttributeJobKOctetsProcessed:                    TagInteger,
+		AttributeJobMediaSheetsCompleted:                TagInteger,
+		AttributeJobMoreInfo:                            TagUri,
+		AttributeJobPages:                               TagInteger,
+		AttributeJobPagesCompleted:                      TagInteger,
+		AttributeNumberOfInterveningJobs:                TagInteger,
+		AttributeOutputDeviceAssigned:                   TagName,
+		AttributeCoverBackSupported:                     TagKeyword,
+		AttributeCoverFrontSupported:                    TagKeyword,
+		AttributeDocumentPasswordSupported:              TagInteger,
+		AttributeFeedOrientationSupported:               TagKeyword,
+		AttributeFinishingsColDatabase:                  TagBeginCollection,
+		AttributeFinishingsColDefault:                   TagBeginCollection,
+		AttributeFinishingsColReady:                     TagBeginCollection,
+		AttributeFinishingsColSupported:                 TagKeyword,
+		AttributeFinishingsReady:                        TagEnum,
+		AttributeJobAccountIDDefault:                    TagName,
+		AttributeJobAccountIDSupported:                  TagBoolean,
+		AttributeJobAccountingUserIDDefault:             TagName,
+		AttributeJobAccountingUserIDSupported:           TagBoolean,
+		AttributeJobAccountTypeDefault:                  TagKeyword,
+		AttributeJobAccountTypeSupported:                TagKeyword,
+		AttributeJobCopiesDefault:                       TagInteger,
+		AttributeJobCopiesSupported:                     TagRange,
+		AttributeProofPrintDefault:                      TagBeginCollection,
+		AttributeProofPrintSupported:                    TagKeyword,
+		AttributeDocumentFormatDetailsSupported:         TagKeyword,
+		AttributeJobCancelAfterDefault:                  TagInteger,
+		AttributeJobCancelAfterSupported:                TagRange,
+		AttributeJobConstraintsSupported:                TagBeginCollection,
+		AttributeJobDelayOutputUntilDefault:             TagKeyword,
+		AttributeJobDelayOutputUntilSupported:           TagKeyword,
+		AttributeJobErrorActionDefault:                  TagKeyword,
+		AttributeJobErrorActionSupported:                TagKeyword,
+		AttributeJobImpressionsSupported:                TagRange,
+		AttributeJobKOctetsSupported:                    TagRange,
+		AttributeJobMediaSheetsSupported:                TagRange,
+		AttributeJobPasswordEncryptionSupported:         TagKeyword,
+		AttributeJobPasswordSupported:                   TagInteger,
+		AttributeJobPreferredAttributesSupported:        TagBoolean,
+		AttributeJobResolversSupported:                  TagBeginCollection,
+		AttributeJobRetainUntilDefault:                  TagKeyword,
+		AttributeJobRetainUntilSupported:                TagKeyword,
+		AttributeJpegKOctetsSupported:                   TagRange,
+		AttributeJpegXDimensionSupported:                TagRange,
+		AttributeJpegYDimensionSupported:                TagRange,
+		AttributeLandscapeOrientationRequestedPreferred: TagEnum,
+		AttributeMediaColorSupported:                    TagKeyword,
+		AttributeMultipleDocumentHandlingDefault:        TagKeyword,
+		AttributeMultipleDocumentHandlingSupported:      TagKeyword,
+		AttributeNumberUpDefault:                        TagInteger,
+		AttributeNumberUpSupported:                      TagInteger,
+		AttributeOverridesSupported:                     TagKeyword,
+		AttributePageDeliveryDefault:                    TagKeyword,
+		AttributePageDeliverySupported:                  TagKeyword,
+		AttributePagesPerMinute:                         TagInteger,
+		AttributePagesPerMinuteColor:                    TagInteger,
+		AttributePdfKOctetsSupported:                    TagRange,
+		AttributePdfVersionsSupported:                   TagKeyword,
+		AttributePreferredAttributesSupported:           TagBoolean,
+		AttributePresentationDirectionNumberUpDefault:   TagKeyword,
+		AttributePresentationDirectionNumberUpSupported: TagKeyword,
+		AttributePrintContentOptimizeDefault:            TagKeyword,
+		AttributePrintContentOptimizeSupported:          TagKeyword,
+		AttributePrintDarknessDefault:                   TagInteger,
+		AttributePrintDarknessSupported:                 TagInteger,
+		AttributePrintRenderingIntentDefault:            TagKeyword,
+		AttributePrintRenderingIntentSupported:          TagKeyword,
+		AttributePrintScalingDefault:                    TagKeyword,
+		AttributePrintScalingSupported:                  TagKeyword,
+		AttributePrinterAlert:                           TagString,
+		AttributePrinterAlertDescription:                TagText,
+		AttributePrinterChargeInfo:                      TagText,
+		AttributePrinterChargeInfoURI:                   TagUri,
+		AttributePrinterConfigChangeDateTime:            TagDate,
+		AttributePrinterConfigChangeTime:                TagInteger,
+		AttributePrinterCurrentTime:                     TagDate,
+		AttributePrinterDarknessConfigured:              TagInteger,
+		AttributePrinterDarknessSupported:               TagInteger,
+		AttributePrinterDnsSdName:                       TagName,
+		AttributePrinterDriverInstaller:                 TagUri,
+		AttributePrinterFinisher:                        TagString,
+		AttributePrinterFinisherDescription:             TagText,
+		AttributePrinterFirmwareName:                    TagName,
+		AttributePrinterFirmwareStringVersion:           TagText,
+		AttributePrinterFirmwareVersion:                 TagString,
+		AttributePrinterIccProfiles:                     TagBeginCollection,
+		AttributePrinterImpressionsCompleted:            TagInteger,
+		AttributePrinterInputTray:                       TagString,
+		AttributePrinterMandatoryJobAttributes:          TagKeyword,
+		AttributePrinterMediaSheetsCompleted:            TagInteger,
+		AttributePrinterMoreInfoManufacturer:            TagUri,
+		AttributePrinterOutputTray:                      TagString,
+		AttributePrinterPagesCompleted:                  TagInteger,
+		AttributePrinterStateChangeDateTime:             TagDate,
+		AttributePrinterStateChangeTime:                 TagInteger,
+		AttributePrinterSupply:                          TagString,
+		AttributePrinterSupplyDescription:               TagText,
+		AttributePrinterSupplyInfoURI:                   TagUri,
+		AttributePrinterXriSupported:                    TagBeginCollection,
+		AttributeReferenceURISchemesSupported:           TagUriScheme,
+		AttributeJobPagesPerSetSupported:                TagBoolean,
+		AttributeInsertSheetSupported:                   TagKeyword,
+		AttributeJobSheetsColSupported:                  TagKeyword,
+		AttributeSeparatorSheetsSupported:               TagKeyword,
+		AttributeXImagePositionSupported:                TagKeyword,
+		AttributeXImageShiftSupported:                   TagRange,
+		AttributeYImagePositionSupported:                TagKeyword,
+		AttributeYImageShiftSupported:                   TagRange,
+		AttributeFinishingTemplate:                      TagKeyword,
+		AttributeStitching:                              TagBeginCollection,
+		AttributeStitchingLocations:                     TagInteger,
+		AttributeStitchingOffset:                        TagInteger,
+		AttributeStitchingReferenceEdge:                 TagKeyword,
+		AttributeStitchingMethod:                        TagKeyword,
+		AttributePunching:                               TagBeginCollection,
+		AttributePunchingLocations:                      TagInteger,
+		AttributePunchingOffset:                         TagInteger,
+		AttributePunchingReferenceEdge:                  TagKeyword,
+		AttributeFolding:                                TagBeginCollection,
+		AttributeFoldingDirection:                       TagKeyword,
+		AttributeFoldingOffset:                          TagInteger,
+		AttributeFoldingReferenceEdge:                   TagKeyword,
+		AttributeProofPrintCopies:                       TagInteger,
+		AttributeDocumentFormatDeviceID:                 TagText,
+		AttributeDocumentFormatVersion:                  TagText,
+		AttributeDocumentSourceApplicationName:          TagName,
+		AttributeDocumentSourceApplicationVersion:       TagText,
+		AttributeDocumentSourceOsName:                   TagName,
+		AttributeDocumentSourceOsVersion:                TagText,
+		AttributeResolverName:                           TagName,
+		AttributeFinishingsCol:                          TagBeginCollection,
+		AttributeMarkerNames:                            TagName,
+		AttributeMarkerTypes:                            TagKeyword,
+		AttributeMarkerColors:                           TagName,
+		AttributeMarkerLevels:                           TagInteger,
+		AttributeMarkerLowLevels:                        TagInteger,
+		AttributeMarkerHighLevels:                       TagInteger,
+		AttributeMarkerMessage:                          TagText,
+		AttributeMarkerChangeTime:                       TagInteger,
 	}
 )
@@ -78,6 +78,19 @@ func (c *CUPSClient) GetPPDs() (map[string]Attributes, error) {
 	return ppdNameMap, nil
 }
 
+// GetPPD returns the ppd file of a printer
+func (c *CUPSClient) GetPPD(printer string) ([]byte, error) {
+	req := NewRequest(OperationCupsGetPpd, 1)
+	req.OperationAttributes[AttributePrinterURI] = c.getPrinterUri(printer)
+
+	buf := new(bytes.Buffer)
+	if _, err := c.SendRequest(c.adapter.GetHttpUri("", nil), req, buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
 // AcceptJobs lets a printer accept jobs again
 func (c *CUPSClient) AcceptJobs(printer string) error {
 	req := NewRequest(OperationCupsAcceptJobs, 1)
@@ -0,0 +1,75 @@
+package ipp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// readDeadliner is implemented by streams with read deadlines like net.Conn
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// contextReader fails once the context is done
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.reader.Read(p)
+}
+
+// withContext binds the reader to the context. the read deadline of a stream with read deadlines is set to the
+// deadline of the context and moved into the past once the context is done, so a blocked read returns. the returned
+// function resets the read deadline and turns the errors caused by the context into a ReadTimeoutError
+func withContext(ctx context.Context, r io.Reader) (io.Reader, func(error) error) {
+	conn, ok := r.(readDeadliner)
+
+	var (
+		stop = func() bool { return true }
+		done = make(chan struct{})
+	)
+	if ok {
+		deadline, _ := ctx.Deadline()
+		_ = conn.SetReadDeadline(deadline)
+		stop = context.AfterFunc(ctx, func() {
+			defer close(done)
+			_ = conn.SetReadDeadline(time.Unix(1, 0))
+		})
+	}
+
+	release := func(err error) error {
+		if ok {
+			// the deadline set by a running cancellation must not outlive the reset
+			if !stop() {
+				<-done
+			}
+			_ = conn.SetReadDeadline(time.Time{})
+		}
+
+		if err == nil {
+			return nil
+		}
+		ctxErr := ctx.Err()
+		if errors.Is(err, os.ErrDeadlineExceeded) && ctxErr == nil {
+			// the read deadline is the deadline of the context, it may pass before the context notices
+			ctxErr = context.DeadlineExceeded
+		}
+		if ctxErr == nil || !(errors.Is(err, ctxErr) || errors.Is(err, os.ErrDeadlineExceeded)) {
+			return err
+		}
+
+		return fmt.Errorf("%w: %w", ReadTimeoutError, ctxErr)
+	}
+
+	return contextReader{ctx: ctx, reader: r}, release
+}
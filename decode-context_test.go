@@ -0,0 +1,78 @@
+package ipp
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseDecoder_DecodeContext(t *testing.T) {
+	resp := NewResponse(StatusOk, 1)
+	resp.OperationAttributes.Set(AttributeStatusMessage, TagText, "ok")
+	data, err := resp.Encode()
+	assert.Nil(t, err)
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	// a stalled peer sends only a part of the message
+	go server.Write(data[:10])
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = NewResponseDecoder(client).DecodeContext(ctx, nil)
+	assert.ErrorIs(t, err, ReadTimeoutError)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// the read deadline is reset
+	go server.Write(data)
+	decoded, err := NewResponseDecoder(client).Decode(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", decoded.OperationAttributes[AttributeStatusMessage][0].Value)
+
+	// a canceled context interrupts a blocked read
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err = NewResponseDecoder(client).DecodeContext(ctx, nil)
+	assert.ErrorIs(t, err, ReadTimeoutError)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// readers without deadlines are checked between reads
+	decoded, err = NewResponseDecoder(bytes.NewReader(data)).DecodeContext(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusOk, decoded.StatusCode)
+	_, err = NewResponseDecoder(bytes.NewReader(data)).DecodeContext(ctx, nil)
+	assert.ErrorIs(t, err, ReadTimeoutError)
+
+	// other errors are kept
+	_, err = NewResponseDecoder(bytes.NewReader(data[:10])).DecodeContext(context.Background(), nil)
+	assert.ErrorIs(t, err, ShortReadError)
+	assert.NotErrorIs(t, err, ReadTimeoutError)
+}
+
+func TestRequestDecoder_DecodeContext(t *testing.T) {
+	data, err := NewRequest(OperationGetPrinterAttributes, 1, WithPrinterURI("ipp://printer/ipp/print")).Encode()
+	assert.Nil(t, err)
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	go server.Write(data[:len(data)-5])
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = NewRequestDecoder(client).DecodeContext(ctx, nil)
+	assert.ErrorIs(t, err, ReadTimeoutError)
+
+	req, err := NewRequestDecoder(bytes.NewReader(data)).DecodeContext(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, OperationGetPrinterAttributes, req.Operation)
+}
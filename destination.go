@@ -0,0 +1,257 @@
+package ipp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var NoDefaultDestinationError = errors.New("no default destination found")
+
+var (
+	// SystemLpoptionsFile is the system wide lpoptions file
+	SystemLpoptionsFile = "/etc/cups/lpoptions"
+	// UserLpoptionsFile is the lpoptions file of the current user relative to the home directory
+	UserLpoptionsFile = ".cups/lpoptions"
+)
+
+// Destination defines a printer or class with its saved options, the same way cups clients like lp and lpr see it
+type Destination struct {
+	Name      string
+	Instance  string
+	IsDefault bool
+	Options   map[string]string
+}
+
+// ParseLpoptions parses a lpoptions file and returns the contained destinations
+func ParseLpoptions(r io.Reader) ([]Destination, error) {
+	dests := make([]Destination, 0)
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := splitLpoptionsLine(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		var isDefault bool
+		switch strings.ToLower(fields[0]) {
+		case "dest":
+		case "default":
+			isDefault = true
+		default:
+			continue
+		}
+
+		dest := Destination{
+			IsDefault: isDefault,
+			Options:   make(map[string]string),
+		}
+
+		dest.Name, dest.Instance = splitDestinationName(fields[1])
+
+		for _, option := range fields[2:] {
+			if i := strings.Index(option, "="); i > 0 {
+				dest.Options[option[:i]] = option[i+1:]
+			} else {
+				dest.Options[option] = "true"
+			}
+		}
+
+		dests = append(dests, dest)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return dests, nil
+}
+
+// LoadDestinations reads the system and user lpoptions files. destinations of the user file override the system ones
+func LoadDestinations() ([]Destination, error) {
+	paths := []string{SystemLpoptionsFile}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, UserLpoptionsFile))
+	}
+
+	dests := make([]Destination, 0)
+
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) || os.IsPermission(err) {
+				continue
+			}
+			return nil, fmt.Errorf("unable to read lpoptions: %w", err)
+		}
+
+		parsed, err := ParseLpoptions(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", path, err)
+		}
+
+		for _, dest := range parsed {
+			dests = mergeDestination(dests, dest)
+		}
+	}
+
+	return dests, nil
+}
+
+// LookupDestination returns the saved destination with the given name and instance from the lpoptions files
+func LookupDestination(name, instance string) (*Destination, error) {
+	dests, err := LoadDestinations()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range dests {
+		if strings.EqualFold(dests[i].Name, name) && strings.EqualFold(dests[i].Instance, instance) {
+			return &dests[i], nil
+		}
+	}
+
+	return &Destination{Name: name, Instance: instance, Options: make(map[string]string)}, nil
+}
+
+// GetEnvironmentDestination returns the default destination set by the LPDEST or PRINTER environment variables.
+// like cups, a PRINTER value of lp is ignored
+func GetEnvironmentDestination() (string, bool) {
+	if dest := os.Getenv("LPDEST"); dest != "" {
+		return dest, true
+	}
+
+	if dest := os.Getenv("PRINTER"); dest != "" && dest != "lp" {
+		return dest, true
+	}
+
+	return "", false
+}
+
+// GetDefaultPrinter returns the name of the server default destination using a CUPS-Get-Default operation
+func (c *CUPSClient) GetDefaultPrinter() (string, error) {
+	req := NewRequest(OperationCupsGetDefault, 1)
+	req.OperationAttributes[AttributeRequestedAttributes] = []string{AttributePrinterName}
+
+	resp, err := c.SendRequest(c.adapter.GetHttpUri("", nil), req, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if len(resp.PrinterAttributes) == 0 || len(resp.PrinterAttributes[0][AttributePrinterName]) == 0 {
+		return "", NoDefaultDestinationError
+	}
+
+	return resp.PrinterAttributes[0][AttributePrinterName][0].Value.(string), nil
+}
+
+// GetDefaultDestination determines the default destination of the current user the same way cups clients do.
+// the LPDEST and PRINTER environment variables are checked first, then the lpoptions files and finally the server default
+func (c *CUPSClient) GetDefaultDestination() (*Destination, error) {
+	if name, ok := GetEnvironmentDestination(); ok {
+		return LookupDestination(splitDestinationName(name))
+	}
+
+	dests, err := LoadDestinations()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range dests {
+		if dests[i].IsDefault {
+			return &dests[i], nil
+		}
+	}
+
+	name, err := c.GetDefaultPrinter()
+	if err != nil {
+		return nil, err
+	}
+
+	return LookupDestination(name, "")
+}
+
+// mergeDestination adds the destination to the list or overrides the options of an existing entry
+func mergeDestination(dests []Destination, dest Destination) []Destination {
+	if dest.IsDefault {
+		for i := range dests {
+			dests[i].IsDefault = false
+		}
+	}
+
+	for i := range dests {
+		if strings.EqualFold(dests[i].Name, dest.Name) && strings.EqualFold(dests[i].Instance, dest.Instance) {
+			for key, value := range dest.Options {
+				dests[i].Options[key] = value
+			}
+			dests[i].IsDefault = dests[i].IsDefault || dest.IsDefault
+			return dests
+		}
+	}
+
+	return append(dests, dest)
+}
+
+func splitDestinationName(name string) (string, string) {
+	if i := strings.Index(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+
+	return name, ""
+}
+
+// splitLpoptionsLine splits a line at whitespaces while respecting quotes and backslash escapes
+func splitLpoptionsLine(line string) []string {
+	fields := make([]string, 0)
+
+	var sb strings.Builder
+	var quote rune
+	inField := false
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			sb.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+			inField = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				sb.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inField = true
+		case r == ' ' || r == '\t':
+			if inField {
+				fields = append(fields, sb.String())
+				sb.Reset()
+				inField = false
+			}
+		default:
+			sb.WriteRune(r)
+			inField = true
+		}
+	}
+
+	if inField {
+		fields = append(fields, sb.String())
+	}
+
+	return fields
+}
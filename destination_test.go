@@ -0,0 +1,75 @@
+package ipp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLpoptions(t *testing.T) {
+	input := `# saved options
+Dest office sides=two-sided-long-edge media=iso_a4_210x297mm
+Default office/draft print-quality=3 job-name="weekly report" fit-to-page
+Dest lab job-sheets='none,none'
+`
+
+	dests, err := ParseLpoptions(strings.NewReader(input))
+	assert.Nil(t, err)
+	assert.Equal(t, []Destination{
+		{
+			Name:    "office",
+			Options: map[string]string{"sides": "two-sided-long-edge", "media": "iso_a4_210x297mm"},
+		},
+		{
+			Name:      "office",
+			Instance:  "draft",
+			IsDefault: true,
+			Options:   map[string]string{"print-quality": "3", "job-name": "weekly report", "fit-to-page": "true"},
+		},
+		{
+			Name:    "lab",
+			Options: map[string]string{"job-sheets": "none,none"},
+		},
+	}, dests)
+}
+
+func TestLoadDestinations(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+
+	system := filepath.Join(dir, "lpoptions")
+	assert.Nil(t, os.WriteFile(system, []byte("Default office sides=one-sided\nDest lab copies=2\n"), 0644))
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, ".cups"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, UserLpoptionsFile), []byte("Default lab sides=two-sided-long-edge\n"), 0644))
+
+	oldSystem := SystemLpoptionsFile
+	SystemLpoptionsFile = system
+	defer func() { SystemLpoptionsFile = oldSystem }()
+
+	dests, err := LoadDestinations()
+	assert.Nil(t, err)
+	assert.Len(t, dests, 2)
+	assert.False(t, dests[0].IsDefault)
+	assert.True(t, dests[1].IsDefault)
+	assert.Equal(t, map[string]string{"copies": "2", "sides": "two-sided-long-edge"}, dests[1].Options)
+}
+
+func TestGetEnvironmentDestination(t *testing.T) {
+	t.Setenv("LPDEST", "")
+	t.Setenv("PRINTER", "lp")
+
+	_, ok := GetEnvironmentDestination()
+	assert.False(t, ok)
+
+	t.Setenv("PRINTER", "office")
+	dest, ok := GetEnvironmentDestination()
+	assert.True(t, ok)
+	assert.Equal(t, "office", dest)
+
+	t.Setenv("LPDEST", "lab")
+	dest, _ = GetEnvironmentDestination()
+	assert.Equal(t, "lab", dest)
+}
@@ -0,0 +1,266 @@
+// Package discovery finds ipp printers on the local network. it browses the _ipp._tcp and _ipps._tcp dns-sd service
+// types with the dnssd sub-package and converts the resolved services into printer uris and the capabilities
+// advertised in their txt records.
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/dnssd"
+)
+
+// UnsupportedSchemeError is returned by ParseURI for uris other than ipp and ipps uris
+var UnsupportedSchemeError = errors.New("unsupported printer uri scheme")
+
+// Printer is a printer found on the local network
+type Printer struct {
+	// Name is the service instance name, e.g. Office Printer
+	Name string
+	// URI is the printer uri built from the service type, host, port and resource path, e.g.
+	// ipps://printer.local:631/ipp/print
+	URI  string
+	Host string
+	Port int
+	IPs  []net.IP
+	// Secure is set for printers advertised as _ipps._tcp
+	Secure bool
+	// Text contains the key value pairs of the txt record, the keys are in lower case
+	Text         map[string]string
+	Capabilities Capabilities
+}
+
+// Capabilities are the capabilities a printer advertises in its txt record. the txt record is only a preliminary
+// summary, Probe fills in the details from the printer attributes
+type Capabilities struct {
+	// DocumentFormats are the mime types of the pdl key
+	DocumentFormats []string
+	Color           bool
+	Duplex          bool
+	// UUID is the printer uuid without the urn:uuid: prefix
+	UUID string
+	// ResourcePath is the path of the printer uri without the leading slash, the rp key
+	ResourcePath string
+
+	// MakeAndModel is the ty key
+	MakeAndModel string
+	// Location is the note key
+	Location string
+	// AdminURL is the adminurl key, the web interface of the printer
+	AdminURL string
+	// Kinds are the printer-kind keywords of the kind key, e.g. document, envelope or photo
+	Kinds []string
+	// PaperMax is the largest supported media size of the papermax key, e.g. legal-a4
+	PaperMax string
+	// URF are the apple raster capabilities of the urf key, e.g. W8, SRGB24 or RS300
+	URF []string
+	// TLS is the highest tls version of the tls key, it is set if an _ipp._tcp printer supports an upgrade to tls
+	TLS string
+
+	// Probed is set once the capabilities are completed from the printer attributes
+	Probed bool
+}
+
+// Discover browses the local network for ipp printers until the context is done, e.g. use a context with a timeout
+// of a few seconds. a printer advertised as _ipp._tcp and _ipps._tcp is returned once, see Merge
+func Discover(ctx context.Context) ([]Printer, error) {
+	services, err := dnssd.Browse(ctx, dnssd.ServiceTypeIPP, dnssd.ServiceTypeIPPS)
+	if err != nil {
+		return nil, err
+	}
+
+	printers := make([]Printer, len(services))
+	for i, service := range services {
+		printers[i] = NewPrinter(service)
+	}
+
+	return Merge(printers), nil
+}
+
+// Merge combines the _ipp._tcp and _ipps._tcp instances of the same printer, the ipps instance is preferred. printers
+// are the same if they have the same uuid, or the same name and host if the uuid is not advertised. the order of the
+// first instances is kept
+func Merge(printers []Printer) []Printer {
+	var merged []Printer
+	index := make(map[string]int)
+
+	for _, p := range printers {
+		key := p.key()
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			merged = append(merged, p)
+			continue
+		}
+		if p.Secure && !merged[i].Secure {
+			merged[i] = p
+		}
+	}
+
+	return merged
+}
+
+// NewPrinter converts a resolved _ipp._tcp or _ipps._tcp service into a printer
+func NewPrinter(service *dnssd.Service) Printer {
+	text := service.Text
+	if text == nil {
+		text = make(map[string]string)
+	}
+
+	p := Printer{
+		Name:         service.Instance,
+		Host:         strings.TrimSuffix(service.Host, "."),
+		Port:         service.Port,
+		IPs:          service.IPs,
+		Secure:       service.Type == dnssd.ServiceTypeIPPS,
+		Text:         text,
+		Capabilities: ParseCapabilities(text),
+	}
+
+	scheme := "ipp"
+	if p.Secure {
+		scheme = "ipps"
+	}
+	p.URI = scheme + "://" + net.JoinHostPort(p.Host, strconv.Itoa(p.Port)) + "/" + p.Capabilities.ResourcePath
+
+	return p
+}
+
+// ParseURI returns the printer of an ipp or ipps uri for printers which are not found on the network, e.g. printers
+// in other subnets. the printer has no txt record, the port defaults to 631
+func ParseURI(uri string) (Printer, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return Printer{}, err
+	}
+	if u.Scheme != "ipp" && u.Scheme != "ipps" {
+		return Printer{}, fmt.Errorf("%w: %s", UnsupportedSchemeError, u.Scheme)
+	}
+
+	p := Printer{
+		Name:   u.Hostname(),
+		URI:    uri,
+		Host:   u.Hostname(),
+		Port:   631,
+		Secure: u.Scheme == "ipps",
+		Text:   make(map[string]string),
+	}
+	if u.Port() != "" {
+		if p.Port, err = strconv.Atoi(u.Port()); err != nil {
+			return Printer{}, err
+		}
+	}
+	p.Capabilities.ResourcePath = strings.Trim(u.Path, "/")
+
+	return p, nil
+}
+
+// key identifies the printer by its uuid, or by its name and host if the uuid is not advertised
+func (p *Printer) key() string {
+	if p.Capabilities.UUID != "" {
+		return p.Capabilities.UUID
+	}
+
+	return strings.ToLower(p.Name + "@" + p.Host)
+}
+
+// ParseCapabilities reads the keys of an ipp everywhere txt record as returned by dnssd.ParseTXT
+func ParseCapabilities(text map[string]string) Capabilities {
+	c := Capabilities{
+		DocumentFormats: splitList(text["pdl"]),
+		Color:           text["color"] == "T",
+		Duplex:          text["duplex"] == "T",
+		UUID:            strings.TrimPrefix(strings.ToLower(text["uuid"]), "urn:uuid:"),
+		ResourcePath:    strings.Trim(text["rp"], "/"),
+		MakeAndModel:    text["ty"],
+		Location:        text["note"],
+		AdminURL:        text["adminurl"],
+		Kinds:           splitList(text["kind"]),
+		PaperMax:        text["papermax"],
+		TLS:             text["tls"],
+	}
+
+	if urf := text["urf"]; urf != "none" {
+		c.URF = splitList(urf)
+	}
+
+	return c
+}
+
+// splitList splits the comma separated values of a txt key
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+// HTTPURL returns the http or https url the ipp requests for the printer are posted to
+func (p *Printer) HTTPURL() string {
+	scheme := "http"
+	if p.Secure {
+		scheme = "https"
+	}
+
+	return scheme + "://" + net.JoinHostPort(p.Host, strconv.Itoa(p.Port)) + "/" + p.Capabilities.ResourcePath
+}
+
+// Probe completes the capabilities of the txt record with a Get-Printer-Attributes request to the printer, the
+// printer attributes take precedence over the txt values. a client for the host and port of the printer is used if
+// client is nil
+func (p *Printer) Probe(client *ipp.IPPClient) error {
+	if client == nil {
+		client = ipp.NewIPPClient(p.Host, p.Port, "", "", p.Secure)
+	}
+
+	req := ipp.NewRequest(ipp.OperationGetPrinterAttributes, 1, ipp.WithPrinterURI(p.URI),
+		ipp.WithGroups(ipp.RequestedPrinterDescription, ipp.RequestedJobTemplate))
+
+	resp, err := client.SendRequest(p.HTTPURL(), req, nil)
+	if err != nil {
+		return err
+	}
+	if len(resp.PrinterAttributes) == 0 {
+		return errors.New("printer doesn't return any printer attributes")
+	}
+
+	var d ipp.PrinterDescription
+	if err := d.Unmarshal(resp.PrinterAttributes[0]); err != nil {
+		return err
+	}
+
+	c := &p.Capabilities
+	if len(d.DocumentFormatsSupported) > 0 {
+		c.DocumentFormats = d.DocumentFormatsSupported
+	}
+	c.Color = d.ColorSupported
+	c.Duplex = false
+	for _, sides := range d.SidesSupported {
+		c.Duplex = c.Duplex || strings.HasPrefix(sides, "two-sided")
+	}
+	if d.UUID != "" {
+		c.UUID = strings.TrimPrefix(strings.ToLower(d.UUID), "urn:uuid:")
+	}
+	if d.MakeAndModel != "" {
+		c.MakeAndModel = d.MakeAndModel
+	}
+	if d.Location != "" {
+		c.Location = d.Location
+	}
+	if d.MoreInfo != "" && c.AdminURL == "" {
+		c.AdminURL = d.MoreInfo
+	}
+	c.Probed = true
+
+	return nil
+}
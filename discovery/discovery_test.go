@@ -0,0 +1,135 @@
+package discovery
+
+import (
+	"io"
+	"net"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/dnssd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPrinter(t *testing.T) {
+	service := &dnssd.Service{
+		Instance: "Office Printer",
+		Type:     dnssd.ServiceTypeIPPS,
+		Host:     "printer.local.",
+		Port:     631,
+		IPs:      []net.IP{net.IPv4(10, 0, 0, 5)},
+		Text: dnssd.ParseTXT([]string{"txtvers=1", "rp=ipp/print", "pdl=application/pdf,image/urf",
+			"Color=T", "Duplex=F", "UUID=3E6F0A2C-1D4B-4F8E-9C1A-5B7D2E8F6A01"}),
+	}
+
+	p := NewPrinter(service)
+	assert.Equal(t, "Office Printer", p.Name)
+	assert.Equal(t, "ipps://printer.local:631/ipp/print", p.URI)
+	assert.True(t, p.Secure)
+	assert.Equal(t, Capabilities{
+		DocumentFormats: []string{"application/pdf", "image/urf"},
+		Color:           true,
+		UUID:            "3e6f0a2c-1d4b-4f8e-9c1a-5b7d2e8f6a01",
+		ResourcePath:    "ipp/print",
+	}, p.Capabilities)
+
+	p = NewPrinter(&dnssd.Service{Instance: "Basic", Type: dnssd.ServiceTypeIPP, Host: "basic.local.", Port: 631})
+	assert.Equal(t, "ipp://basic.local:631/", p.URI)
+	assert.False(t, p.Secure)
+	assert.Empty(t, p.Capabilities.DocumentFormats)
+}
+
+func TestParseCapabilities(t *testing.T) {
+	c := ParseCapabilities(dnssd.ParseTXT([]string{"ty=ACME Laser 5000", "note=2nd floor", "kind=document,envelope",
+		"PaperMax=legal-A4", "URF=W8,SRGB24,RS300", "TLS=1.2", "adminurl=https://printer.local/"}))
+
+	assert.Equal(t, "ACME Laser 5000", c.MakeAndModel)
+	assert.Equal(t, "2nd floor", c.Location)
+	assert.Equal(t, []string{"document", "envelope"}, c.Kinds)
+	assert.Equal(t, "legal-A4", c.PaperMax)
+	assert.Equal(t, []string{"W8", "SRGB24", "RS300"}, c.URF)
+	assert.Equal(t, "1.2", c.TLS)
+	assert.Equal(t, "https://printer.local/", c.AdminURL)
+	assert.False(t, c.Probed)
+
+	assert.Empty(t, ParseCapabilities(map[string]string{"urf": "none"}).URF)
+}
+
+func TestMerge(t *testing.T) {
+	text := map[string]string{"uuid": "1234", "rp": "ipp/print"}
+	ippPrinter := NewPrinter(&dnssd.Service{Instance: "Office", Type: dnssd.ServiceTypeIPP, Host: "office.local.",
+		Port: 631, Text: text})
+	ippsPrinter := NewPrinter(&dnssd.Service{Instance: "Office", Type: dnssd.ServiceTypeIPPS, Host: "office.local.",
+		Port: 631, Text: text})
+	lab := NewPrinter(&dnssd.Service{Instance: "Lab", Type: dnssd.ServiceTypeIPP, Host: "lab.local.", Port: 631})
+
+	merged := Merge([]Printer{ippPrinter, lab, ippsPrinter})
+	assert.Len(t, merged, 2)
+	assert.Equal(t, "ipps://office.local:631/ipp/print", merged[0].URI)
+	assert.Equal(t, "https://office.local:631/ipp/print", merged[0].HTTPURL())
+	assert.Equal(t, "Lab", merged[1].Name)
+}
+
+type probeAdapter struct {
+	url string
+	req *ipp.Request
+}
+
+func (a *probeAdapter) SendRequest(url string, req *ipp.Request, _ io.Writer) (*ipp.Response, error) {
+	a.url, a.req = url, req
+
+	printer := make(ipp.Attributes)
+	printer.Set(ipp.AttributeDocumentFormatSupported, ipp.TagMimeType, "application/pdf", "image/pwg-raster")
+	printer.Set(ipp.AttributeColorSupported, ipp.TagBoolean, true)
+	printer.Set(ipp.AttributeSidesSupported, ipp.TagKeyword, ipp.SidesOneSided, ipp.SidesTwoSidedLongEdge)
+	printer.Set(ipp.AttributePrinterUUID, ipp.TagUri, "urn:uuid:ABCD")
+	printer.Set(ipp.AttributePrinterMakeAndModel, ipp.TagText, "ACME Laser 5000")
+
+	resp := ipp.NewResponse(ipp.StatusOk, req.RequestId)
+	resp.PrinterAttributes = append(resp.PrinterAttributes, printer)
+	return resp, nil
+}
+
+func (a *probeAdapter) GetHttpUri(namespace string, object interface{}) string {
+	return ""
+}
+
+func (a *probeAdapter) TestConnection() error {
+	return nil
+}
+
+func TestPrinter_Probe(t *testing.T) {
+	p := NewPrinter(&dnssd.Service{Instance: "Office", Type: dnssd.ServiceTypeIPP, Host: "office.local.", Port: 631,
+		Text: map[string]string{"rp": "ipp/print", "pdl": "application/pdf", "color": "F"}})
+
+	adapter := &probeAdapter{}
+	assert.Nil(t, p.Probe(ipp.NewIPPClientWithAdapter("", adapter)))
+
+	assert.Equal(t, "http://office.local:631/ipp/print", adapter.url)
+	assert.Equal(t, "ipp://office.local:631/ipp/print", adapter.req.OperationAttributes[ipp.AttributePrinterURI])
+	assert.Equal(t, Capabilities{
+		DocumentFormats: []string{"application/pdf", "image/pwg-raster"},
+		Color:           true,
+		Duplex:          true,
+		UUID:            "abcd",
+		ResourcePath:    "ipp/print",
+		MakeAndModel:    "ACME Laser 5000",
+		Probed:          true,
+	}, p.Capabilities)
+}
+
+func TestParseURI(t *testing.T) {
+	p, err := ParseURI("ipps://printer.example.com/ipp/print")
+	assert.Nil(t, err)
+	assert.Equal(t, "printer.example.com", p.Host)
+	assert.Equal(t, 631, p.Port)
+	assert.True(t, p.Secure)
+	assert.Equal(t, "https://printer.example.com:631/ipp/print", p.HTTPURL())
+
+	p, err = ParseURI("ipp://10.0.0.5:8631/printers/office")
+	assert.Nil(t, err)
+	assert.Equal(t, 8631, p.Port)
+	assert.Equal(t, "http://10.0.0.5:8631/printers/office", p.HTTPURL())
+
+	_, err = ParseURI("lpd://10.0.0.5/queue")
+	assert.ErrorIs(t, err, UnsupportedSchemeError)
+}
@@ -0,0 +1,175 @@
+package discovery
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// EventType is the kind of change reported by a Watcher
+type EventType int
+
+// event types of a Watcher
+const (
+	PrinterAppeared EventType = iota
+	PrinterUpdated
+	PrinterDisappeared
+)
+
+var eventTypeNames = map[EventType]string{
+	PrinterAppeared:    "printer-appeared",
+	PrinterUpdated:     "printer-updated",
+	PrinterDisappeared: "printer-disappeared",
+}
+
+// String returns the name of the event type, e.g. printer-appeared
+func (t EventType) String() string {
+	return eventTypeNames[t]
+}
+
+// Event is a change of the printers on the network. for PrinterDisappeared, Printer is the last seen state
+type Event struct {
+	Type    EventType
+	Printer Printer
+}
+
+// Watcher browses the network in rounds and reports printers coming and going, e.g. for long running spooler
+// daemons. a printer is only reported as disappeared after it was missing in several rounds, since mdns answers may
+// get lost
+type Watcher struct {
+	// Interval is the delay between the start of two browse rounds, defaults to 30 seconds
+	Interval time.Duration
+	// BrowseTimeout is the duration of a browse round, defaults to 3 seconds
+	BrowseTimeout time.Duration
+	// Misses is the number of rounds a printer must be missing before it is reported as disappeared, defaults to 2
+	Misses int
+
+	// discover browses the network, it is replaced by tests
+	discover func(ctx context.Context) ([]Printer, error)
+
+	known  map[string]Printer
+	missed map[string]int
+}
+
+// Watch starts a Watcher with the default settings, see Watcher.Watch
+func Watch(ctx context.Context) <-chan Event {
+	return (&Watcher{}).Watch(ctx)
+}
+
+// Watch browses the network until the context is done and sends the changes to the returned channel. the first
+// round reports every printer found as appeared. failed rounds, e.g. while the network is down, are skipped. the
+// channel is closed when the context is done
+func (w *Watcher) Watch(ctx context.Context) <-chan Event {
+	events := make(chan Event)
+
+	go func() {
+		defer close(events)
+
+		interval := w.Interval
+		if interval <= 0 {
+			interval = 30 * time.Second
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if !w.round(ctx, events) {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return events
+}
+
+// round browses once and sends the changes, false is returned if the context is done
+func (w *Watcher) round(ctx context.Context, events chan<- Event) bool {
+	timeout := w.BrowseTimeout
+	if timeout <= 0 {
+		timeout = 3 * time.Second
+	}
+	discover := w.discover
+	if discover == nil {
+		discover = Discover
+	}
+
+	browseCtx, cancel := context.WithTimeout(ctx, timeout)
+	printers, err := discover(browseCtx)
+	cancel()
+
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+
+	for _, event := range w.update(printers) {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	return true
+}
+
+// update compares the printers of a round with the known printers and returns the changes
+func (w *Watcher) update(printers []Printer) []Event {
+	if w.known == nil {
+		w.known = make(map[string]Printer)
+		w.missed = make(map[string]int)
+	}
+	misses := w.Misses
+	if misses <= 0 {
+		misses = 2
+	}
+
+	var events []Event
+	seen := make(map[string]bool, len(printers))
+
+	for _, p := range printers {
+		key := p.key()
+		seen[key] = true
+		delete(w.missed, key)
+
+		known, ok := w.known[key]
+		switch {
+		case !ok:
+			events = append(events, Event{Type: PrinterAppeared, Printer: p})
+		case changed(known, p):
+			events = append(events, Event{Type: PrinterUpdated, Printer: p})
+		default:
+			continue
+		}
+		w.known[key] = p
+	}
+
+	for key, p := range w.known {
+		if seen[key] {
+			continue
+		}
+
+		w.missed[key]++
+		if w.missed[key] >= misses {
+			events = append(events, Event{Type: PrinterDisappeared, Printer: p})
+			delete(w.known, key)
+			delete(w.missed, key)
+		}
+	}
+
+	return events
+}
+
+// changed reports whether the advertisement of a printer changed, probed capabilities are not compared
+func changed(old, new Printer) bool {
+	return old.Name != new.Name || old.URI != new.URI || !reflect.DeepEqual(old.IPs, new.IPs) ||
+		!reflect.DeepEqual(old.Text, new.Text)
+}
@@ -0,0 +1,82 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/phin1x/go-ipp/dnssd"
+	"github.com/stretchr/testify/assert"
+)
+
+func watchTestPrinter(name, note string) Printer {
+	return NewPrinter(&dnssd.Service{Instance: name, Type: dnssd.ServiceTypeIPP, Host: "printer.local.", Port: 631,
+		Text: map[string]string{"rp": "ipp/print", "note": note}})
+}
+
+func eventTypes(events []Event) []EventType {
+	var types []EventType
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+
+	return types
+}
+
+func TestWatcher_Update(t *testing.T) {
+	w := &Watcher{Misses: 2}
+	office := watchTestPrinter("Office", "2nd floor")
+
+	assert.Equal(t, []EventType{PrinterAppeared}, eventTypes(w.update([]Printer{office})))
+	assert.Empty(t, w.update([]Printer{office}))
+
+	moved := watchTestPrinter("Office", "3rd floor")
+	events := w.update([]Printer{moved})
+	assert.Equal(t, []EventType{PrinterUpdated}, eventTypes(events))
+	assert.Equal(t, "3rd floor", events[0].Printer.Capabilities.Location)
+
+	// a single missed round is tolerated
+	assert.Empty(t, w.update(nil))
+	assert.Empty(t, w.update([]Printer{moved}))
+	assert.Empty(t, w.update(nil))
+
+	events = w.update(nil)
+	assert.Equal(t, []EventType{PrinterDisappeared}, eventTypes(events))
+	assert.Equal(t, "Office", events[0].Printer.Name)
+
+	assert.Equal(t, "printer-disappeared", PrinterDisappeared.String())
+}
+
+func TestWatcher_Watch(t *testing.T) {
+	rounds := [][]Printer{
+		{watchTestPrinter("Office", "")},
+		{watchTestPrinter("Office", ""), watchTestPrinter("Lab", "")},
+	}
+
+	w := &Watcher{Interval: time.Millisecond, Misses: 1}
+	w.discover = func(ctx context.Context) ([]Printer, error) {
+		if len(rounds) == 0 {
+			return nil, nil
+		}
+		printers := rounds[0]
+		rounds = rounds[1:]
+		return printers, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := w.Watch(ctx)
+	var received []Event
+	for event := range events {
+		received = append(received, event)
+		if len(received) == 4 {
+			cancel()
+		}
+	}
+
+	assert.Equal(t, []EventType{PrinterAppeared, PrinterAppeared, PrinterDisappeared, PrinterDisappeared},
+		eventTypes(received))
+	assert.Equal(t, "Office", received[0].Printer.Name)
+	assert.Equal(t, "Lab", received[1].Printer.Name)
+}
@@ -0,0 +1,220 @@
+package dnssd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Browse queries the local network for instances of the service types, e.g. ServiceTypeIPP and ServiceTypeIPPS,
+// until the context is done. the queries are sent as legacy unicast queries, so the responders answer directly to
+// the browser. instances found without their srv or txt record are queried again. the resolved instances are
+// returned sorted by type and instance name, instances without srv record are dropped
+func Browse(ctx context.Context, serviceTypes ...string) ([]*Service, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4zero})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// unblock the read when the context is canceled
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.SetReadDeadline(time.Now())
+		case <-done:
+		}
+	}()
+
+	query := &Message{}
+	for _, serviceType := range serviceTypes {
+		query.Questions = append(query.Questions, Question{Name: serviceType + "." + DefaultDomain + ".", Type: TypePTR})
+	}
+	if err := browserSend(conn, query); err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	queried := make(map[string]bool)
+
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			var netErr net.Error
+			if ctx.Err() != nil && errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return nil, err
+		}
+
+		msg, err := Unpack(buf[:n])
+		if err != nil || !msg.Response {
+			continue
+		}
+		records = append(records, msg.Records()...)
+
+		if followUp := unresolvedQuestions(records, serviceTypes, queried); len(followUp) > 0 {
+			_ = browserSend(conn, &Message{Questions: followUp})
+		}
+	}
+
+	return resolveServices(records, serviceTypes), nil
+}
+
+func browserSend(conn *net.UDPConn, msg *Message) error {
+	b, err := msg.Pack()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.WriteToUDP(b, IPv4Group)
+	return err
+}
+
+// unresolvedQuestions returns the questions for the srv and txt records of instances and the address records of
+// hosts which are missing in the records. every name is only queried once
+func unresolvedQuestions(records []Record, serviceTypes []string, queried map[string]bool) []Question {
+	index := indexRecords(records)
+
+	var questions []Question
+	ask := func(name string, types ...uint16) {
+		if queried[strings.ToLower(name)] {
+			return
+		}
+		queried[strings.ToLower(name)] = true
+		for _, t := range types {
+			questions = append(questions, Question{Name: name, Type: t})
+		}
+	}
+
+	for _, instance := range index.instances(serviceTypes) {
+		srv, ok := index.srv[strings.ToLower(instance.name)]
+		if !ok {
+			ask(instance.name, TypeSRV, TypeTXT)
+			continue
+		}
+		if _, ok := index.txt[strings.ToLower(instance.name)]; !ok {
+			ask(instance.name, TypeTXT)
+		}
+		if len(index.ips[strings.ToLower(srv.Target)]) == 0 {
+			ask(srv.Target, TypeA, TypeAAAA)
+		}
+	}
+
+	return questions
+}
+
+// recordIndex indexes the records of browse responses by their lower case names
+type recordIndex struct {
+	ptr []Record
+	srv map[string]Record
+	txt map[string]Record
+	ips map[string][]net.IP
+}
+
+// browsedInstance is an instance name found in a ptr record of a service type
+type browsedInstance struct {
+	name        string
+	serviceType string
+}
+
+func indexRecords(records []Record) recordIndex {
+	index := recordIndex{srv: make(map[string]Record), txt: make(map[string]Record), ips: make(map[string][]net.IP)}
+
+	for _, record := range records {
+		name := strings.ToLower(record.Name)
+
+		switch record.Type {
+		case TypePTR:
+			index.ptr = append(index.ptr, record)
+		case TypeSRV:
+			index.srv[name] = record
+		case TypeTXT:
+			index.txt[name] = record
+		case TypeA, TypeAAAA:
+			duplicate := false
+			for _, ip := range index.ips[name] {
+				duplicate = duplicate || ip.Equal(record.IP)
+			}
+			if !duplicate {
+				index.ips[name] = append(index.ips[name], record.IP)
+			}
+		}
+	}
+
+	return index
+}
+
+// instances returns the instances of the service types, instances removed by a goodbye record are skipped
+func (index recordIndex) instances(serviceTypes []string) []browsedInstance {
+	var instances []browsedInstance
+	seen := make(map[string]bool)
+	removed := make(map[string]bool)
+
+	for _, record := range index.ptr {
+		if record.TTL == 0 {
+			removed[strings.ToLower(record.Target)] = true
+		}
+	}
+
+	for _, record := range index.ptr {
+		name := strings.ToLower(record.Target)
+		if seen[name] || removed[name] {
+			continue
+		}
+
+		for _, serviceType := range serviceTypes {
+			if EqualNames(record.Name, serviceType+"."+DefaultDomain) {
+				seen[name] = true
+				instances = append(instances, browsedInstance{name: record.Target, serviceType: serviceType})
+				break
+			}
+		}
+	}
+
+	return instances
+}
+
+// resolveServices builds the services of the service types from the records of browse responses
+func resolveServices(records []Record, serviceTypes []string) []*Service {
+	index := indexRecords(records)
+
+	var services []*Service
+	for _, instance := range index.instances(serviceTypes) {
+		srv, ok := index.srv[strings.ToLower(instance.name)]
+		if !ok {
+			continue
+		}
+
+		labels, err := SplitName(instance.name)
+		if err != nil || len(labels) == 0 {
+			continue
+		}
+
+		service := &Service{
+			Instance: labels[0],
+			Type:     instance.serviceType,
+			Domain:   DefaultDomain,
+			Host:     srv.Target,
+			Port:     int(srv.Port),
+			Text:     ParseTXT(index.txt[strings.ToLower(instance.name)].Text),
+			IPs:      index.ips[strings.ToLower(srv.Target)],
+		}
+		services = append(services, service)
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		if services[i].Type != services[j].Type {
+			return services[i].Type < services[j].Type
+		}
+		return services[i].Instance < services[j].Instance
+	})
+
+	return services
+}
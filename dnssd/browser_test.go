@@ -0,0 +1,57 @@
+package dnssd
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveServices(t *testing.T) {
+	office := newTestService()
+	lab := &Service{Instance: "Lab.Printer", Type: ServiceTypeIPPS, Host: "lab", Port: 443,
+		Text: map[string]string{"rp": "ipp/print"}, IPs: []net.IP{net.IPv4(10, 0, 0, 6)}}
+	gone := &Service{Instance: "Gone", Type: ServiceTypeIPP, Host: "gone", Port: 631}
+
+	var records []Record
+	for _, service := range []*Service{office, lab, gone} {
+		answers, additionals := serviceRecords(service)
+		records = append(append(records, answers...), additionals...)
+	}
+	records = append(records, goodbye(gone).Answers...)
+
+	services := resolveServices(records, []string{ServiceTypeIPP, ServiceTypeIPPS})
+	assert.Len(t, services, 2)
+
+	assert.Equal(t, "Office Printer", services[0].Instance)
+	assert.Equal(t, ServiceTypeIPP, services[0].Type)
+	assert.Equal(t, "printer.local.", services[0].Host)
+	assert.Equal(t, 631, services[0].Port)
+	assert.Equal(t, "ipp/print", services[0].Text["rp"])
+	assert.Equal(t, "1234", services[0].Text["uuid"])
+	assert.True(t, services[0].IPs[0].Equal(net.IPv4(10, 0, 0, 5)))
+
+	assert.Equal(t, "Lab.Printer", services[1].Instance)
+	assert.Equal(t, ServiceTypeIPPS, services[1].Type)
+}
+
+func TestUnresolvedQuestions(t *testing.T) {
+	service := newTestService()
+	answers, _ := serviceRecords(service)
+	queried := make(map[string]bool)
+
+	// only the ptr record is known
+	questions := unresolvedQuestions(answers[:1], []string{ServiceTypeIPP}, queried)
+	assert.Equal(t, []Question{
+		{Name: service.InstanceName(), Type: TypeSRV},
+		{Name: service.InstanceName(), Type: TypeTXT},
+	}, questions)
+	assert.Empty(t, unresolvedQuestions(answers[:1], []string{ServiceTypeIPP}, queried))
+
+	// the address of the host is missing
+	questions = unresolvedQuestions(answers, []string{ServiceTypeIPP}, queried)
+	assert.Equal(t, []Question{
+		{Name: service.HostName(), Type: TypeA},
+		{Name: service.HostName(), Type: TypeAAAA},
+	}, questions)
+}
@@ -0,0 +1,495 @@
+package dnssd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// dns record types used by dns-sd
+const (
+	TypeA    uint16 = 1
+	TypePTR  uint16 = 12
+	TypeTXT  uint16 = 16
+	TypeAAAA uint16 = 28
+	TypeSRV  uint16 = 33
+	TypeNSEC uint16 = 47
+	TypeANY  uint16 = 255
+)
+
+// ClassINET is the internet class of dns records
+const ClassINET uint16 = 1
+
+const (
+	// flagCacheFlush is the mdns cache flush bit of the record class
+	flagCacheFlush uint16 = 0x8000
+	// flagUnicastResponse is the mdns unicast response bit of the question class
+	flagUnicastResponse uint16 = 0x8000
+
+	flagResponse      uint16 = 0x8000
+	flagAuthoritative uint16 = 0x0400
+
+	maxLabelLength = 63
+	maxPointers    = 32
+)
+
+var (
+	// ShortMessageError is returned if a message ends before all fields are read
+	ShortMessageError = errors.New("dns message is too short")
+	// InvalidNameError is returned if a name can not be encoded or decoded
+	InvalidNameError = errors.New("invalid dns name")
+)
+
+// Question defines a question of a dns message
+type Question struct {
+	Name string
+	Type uint16
+	// Unicast requests a unicast response from mdns responders
+	Unicast bool
+}
+
+// Record defines a resource record of a dns message. the fields used depend on the type of the record
+type Record struct {
+	Name string
+	Type uint16
+	// CacheFlush indicates that the record replaces all cached records with the same name and type
+	CacheFlush bool
+	TTL        uint32
+
+	// Target is the domain name of ptr and srv records
+	Target   string
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+	// Text contains the strings of txt records
+	Text []string
+	// IP is the address of a and aaaa records
+	IP net.IP
+	// Data contains the raw data of all other record types
+	Data []byte
+}
+
+// Message defines a dns message
+type Message struct {
+	ID            uint16
+	Response      bool
+	Authoritative bool
+
+	Questions   []Question
+	Answers     []Record
+	Authorities []Record
+	Additionals []Record
+}
+
+// Records returns all answer, authority and additional records of the message
+func (m *Message) Records() []Record {
+	records := make([]Record, 0, len(m.Answers)+len(m.Authorities)+len(m.Additionals))
+	records = append(records, m.Answers...)
+	records = append(records, m.Authorities...)
+	return append(records, m.Additionals...)
+}
+
+// Pack encodes the message into its wire format. names are compressed
+func (m *Message) Pack() ([]byte, error) {
+	var flags uint16
+	if m.Response {
+		flags |= flagResponse
+	}
+	if m.Authoritative {
+		flags |= flagAuthoritative
+	}
+
+	b := make([]byte, 12, 512)
+	binary.BigEndian.PutUint16(b[0:], m.ID)
+	binary.BigEndian.PutUint16(b[2:], flags)
+	binary.BigEndian.PutUint16(b[4:], uint16(len(m.Questions)))
+	binary.BigEndian.PutUint16(b[6:], uint16(len(m.Answers)))
+	binary.BigEndian.PutUint16(b[8:], uint16(len(m.Authorities)))
+	binary.BigEndian.PutUint16(b[10:], uint16(len(m.Additionals)))
+
+	p := &packer{buf: b, names: make(map[string]int)}
+
+	for _, q := range m.Questions {
+		class := ClassINET
+		if q.Unicast {
+			class |= flagUnicastResponse
+		}
+
+		if err := p.name(q.Name); err != nil {
+			return nil, err
+		}
+		p.uint16(q.Type)
+		p.uint16(class)
+	}
+
+	for _, r := range m.Records() {
+		if err := p.record(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.buf, nil
+}
+
+// Unpack decodes a message from its wire format
+func Unpack(b []byte) (*Message, error) {
+	if len(b) < 12 {
+		return nil, ShortMessageError
+	}
+
+	flags := binary.BigEndian.Uint16(b[2:])
+	m := &Message{
+		ID:            binary.BigEndian.Uint16(b[0:]),
+		Response:      flags&flagResponse != 0,
+		Authoritative: flags&flagAuthoritative != 0,
+	}
+
+	u := &unpacker{buf: b, off: 12}
+
+	counts := make([]int, 4)
+	for i := range counts {
+		counts[i] = int(binary.BigEndian.Uint16(b[4+i*2:]))
+	}
+
+	for i := 0; i < counts[0]; i++ {
+		name, err := u.name()
+		if err != nil {
+			return nil, err
+		}
+
+		typ, err := u.uint16()
+		if err != nil {
+			return nil, err
+		}
+
+		class, err := u.uint16()
+		if err != nil {
+			return nil, err
+		}
+
+		m.Questions = append(m.Questions, Question{Name: name, Type: typ, Unicast: class&flagUnicastResponse != 0})
+	}
+
+	sections := []*[]Record{&m.Answers, &m.Authorities, &m.Additionals}
+	for i, section := range sections {
+		for j := 0; j < counts[i+1]; j++ {
+			r, err := u.record()
+			if err != nil {
+				return nil, err
+			}
+			*section = append(*section, r)
+		}
+	}
+
+	return m, nil
+}
+
+type packer struct {
+	buf   []byte
+	names map[string]int
+}
+
+func (p *packer) uint16(v uint16) {
+	p.buf = append(p.buf, byte(v>>8), byte(v))
+}
+
+func (p *packer) uint32(v uint32) {
+	p.buf = append(p.buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+// name writes a domain name, known suffixes are replaced by a pointer to their first occurrence
+func (p *packer) name(name string) error {
+	labels, err := SplitName(name)
+	if err != nil {
+		return err
+	}
+
+	for i := range labels {
+		suffix := strings.ToLower(JoinName(labels[i:]...))
+		if off, ok := p.names[suffix]; ok {
+			p.uint16(0xC000 | uint16(off))
+			return nil
+		}
+
+		if len(p.buf) < 0x3FFF {
+			p.names[suffix] = len(p.buf)
+		}
+
+		p.buf = append(p.buf, byte(len(labels[i])))
+		p.buf = append(p.buf, labels[i]...)
+	}
+
+	p.buf = append(p.buf, 0)
+	return nil
+}
+
+func (p *packer) record(r Record) error {
+	class := ClassINET
+	if r.CacheFlush {
+		class |= flagCacheFlush
+	}
+
+	if err := p.name(r.Name); err != nil {
+		return err
+	}
+	p.uint16(r.Type)
+	p.uint16(class)
+	p.uint32(r.TTL)
+
+	// reserve the length field and fill it after the data is written
+	lengthOff := len(p.buf)
+	p.uint16(0)
+
+	switch r.Type {
+	case TypePTR:
+		if err := p.name(r.Target); err != nil {
+			return err
+		}
+	case TypeSRV:
+		p.uint16(r.Priority)
+		p.uint16(r.Weight)
+		p.uint16(r.Port)
+		if err := p.name(r.Target); err != nil {
+			return err
+		}
+	case TypeTXT:
+		text := r.Text
+		if len(text) == 0 {
+			// a txt record must contain at least one string
+			text = []string{""}
+		}
+		for _, s := range text {
+			if len(s) > 255 {
+				return fmt.Errorf("txt string %q is longer than 255 bytes", s)
+			}
+			p.buf = append(p.buf, byte(len(s)))
+			p.buf = append(p.buf, s...)
+		}
+	case TypeA:
+		ip := r.IP.To4()
+		if ip == nil {
+			return fmt.Errorf("%s is not a ipv4 address", r.IP)
+		}
+		p.buf = append(p.buf, ip...)
+	case TypeAAAA:
+		ip := r.IP.To16()
+		if ip == nil {
+			return fmt.Errorf("%s is not a ipv6 address", r.IP)
+		}
+		p.buf = append(p.buf, ip...)
+	default:
+		p.buf = append(p.buf, r.Data...)
+	}
+
+	binary.BigEndian.PutUint16(p.buf[lengthOff:], uint16(len(p.buf)-lengthOff-2))
+	return nil
+}
+
+type unpacker struct {
+	buf []byte
+	off int
+}
+
+func (u *unpacker) uint16() (uint16, error) {
+	if u.off+2 > len(u.buf) {
+		return 0, ShortMessageError
+	}
+
+	v := binary.BigEndian.Uint16(u.buf[u.off:])
+	u.off += 2
+	return v, nil
+}
+
+func (u *unpacker) uint32() (uint32, error) {
+	if u.off+4 > len(u.buf) {
+		return 0, ShortMessageError
+	}
+
+	v := binary.BigEndian.Uint32(u.buf[u.off:])
+	u.off += 4
+	return v, nil
+}
+
+// name reads a possibly compressed domain name
+func (u *unpacker) name() (string, error) {
+	var labels []string
+
+	off := u.off
+	end := -1
+	for pointers := 0; ; {
+		if off >= len(u.buf) {
+			return "", ShortMessageError
+		}
+
+		length := int(u.buf[off])
+		switch {
+		case length == 0:
+			off++
+			if end < 0 {
+				end = off
+			}
+			u.off = end
+			return JoinName(labels...), nil
+		case length&0xC0 == 0xC0:
+			if off+2 > len(u.buf) {
+				return "", ShortMessageError
+			}
+			if pointers++; pointers > maxPointers {
+				return "", InvalidNameError
+			}
+			if end < 0 {
+				end = off + 2
+			}
+			off = int(binary.BigEndian.Uint16(u.buf[off:]) & 0x3FFF)
+		case length > maxLabelLength:
+			return "", InvalidNameError
+		default:
+			if off+1+length > len(u.buf) {
+				return "", ShortMessageError
+			}
+			labels = append(labels, string(u.buf[off+1:off+1+length]))
+			off += 1 + length
+		}
+	}
+}
+
+func (u *unpacker) record() (Record, error) {
+	var r Record
+
+	name, err := u.name()
+	if err != nil {
+		return r, err
+	}
+	r.Name = name
+
+	if r.Type, err = u.uint16(); err != nil {
+		return r, err
+	}
+
+	class, err := u.uint16()
+	if err != nil {
+		return r, err
+	}
+	r.CacheFlush = class&flagCacheFlush != 0
+
+	if r.TTL, err = u.uint32(); err != nil {
+		return r, err
+	}
+
+	length, err := u.uint16()
+	if err != nil {
+		return r, err
+	}
+
+	end := u.off + int(length)
+	if end > len(u.buf) {
+		return r, ShortMessageError
+	}
+	data := u.buf[u.off:end]
+
+	switch r.Type {
+	case TypePTR:
+		if r.Target, err = u.name(); err != nil {
+			return r, err
+		}
+	case TypeSRV:
+		if r.Priority, err = u.uint16(); err != nil {
+			return r, err
+		}
+		if r.Weight, err = u.uint16(); err != nil {
+			return r, err
+		}
+		if r.Port, err = u.uint16(); err != nil {
+			return r, err
+		}
+		if r.Target, err = u.name(); err != nil {
+			return r, err
+		}
+	case TypeTXT:
+		for i := 0; i < len(data); {
+			n := int(data[i])
+			if i+1+n > len(data) {
+				return r, ShortMessageError
+			}
+			if n > 0 {
+				r.Text = append(r.Text, string(data[i+1:i+1+n]))
+			}
+			i += 1 + n
+		}
+	case TypeA, TypeAAAA:
+		r.IP = append(net.IP{}, data...)
+	default:
+		r.Data = append([]byte{}, data...)
+	}
+
+	u.off = end
+	return r, nil
+}
+
+// SplitName splits a domain name into its labels. dots and backslashes within a label are escaped with a backslash
+func SplitName(name string) ([]string, error) {
+	if name == "" || name == "." {
+		return nil, nil
+	}
+
+	var labels []string
+	var label []byte
+
+	for i := 0; i < len(name); i++ {
+		switch c := name[i]; c {
+		case '\\':
+			if i++; i >= len(name) {
+				return nil, InvalidNameError
+			}
+			label = append(label, name[i])
+		case '.':
+			if len(label) == 0 {
+				return nil, InvalidNameError
+			}
+			labels = append(labels, string(label))
+			label = label[:0]
+		default:
+			label = append(label, c)
+		}
+	}
+
+	if len(label) > 0 {
+		labels = append(labels, string(label))
+	}
+
+	for _, label := range labels {
+		if len(label) > maxLabelLength {
+			return nil, InvalidNameError
+		}
+	}
+
+	return labels, nil
+}
+
+// JoinName joins labels to a fully qualified domain name, dots and backslashes within the labels are escaped
+func JoinName(labels ...string) string {
+	var b strings.Builder
+
+	for _, label := range labels {
+		for i := 0; i < len(label); i++ {
+			if label[i] == '.' || label[i] == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(label[i])
+		}
+		b.WriteByte('.')
+	}
+
+	if b.Len() == 0 {
+		return "."
+	}
+
+	return b.String()
+}
+
+// EqualNames compares two domain names case insensitive
+func EqualNames(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+}
@@ -0,0 +1,73 @@
+package dnssd
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage_PackUnpack(t *testing.T) {
+	msg := &Message{
+		ID:            7,
+		Response:      true,
+		Authoritative: true,
+		Questions:     []Question{{Name: "_ipp._tcp.local.", Type: TypePTR, Unicast: true}},
+		Answers: []Record{
+			{Name: "_ipp._tcp.local.", Type: TypePTR, TTL: 4500, Target: `Office\.Printer._ipp._tcp.local.`},
+		},
+		Additionals: []Record{
+			{Name: `Office\.Printer._ipp._tcp.local.`, Type: TypeSRV, CacheFlush: true, TTL: 120, Target: "host.local.", Port: 631},
+			{Name: `Office\.Printer._ipp._tcp.local.`, Type: TypeTXT, TTL: 4500, Text: []string{"txtvers=1", "rp=ipp/print"}},
+			{Name: "host.local.", Type: TypeA, TTL: 120, IP: net.IPv4(192, 168, 1, 2).To4()},
+			{Name: "host.local.", Type: TypeAAAA, TTL: 120, IP: net.ParseIP("fe80::1")},
+			{Name: "host.local.", Type: TypeNSEC, TTL: 120, Data: []byte{1, 2, 3}},
+		},
+	}
+
+	b, err := msg.Pack()
+	assert.Nil(t, err)
+
+	decoded, err := Unpack(b)
+	assert.Nil(t, err)
+	assert.Equal(t, msg, decoded)
+
+	// the instance and service names are compressed
+	assert.Less(t, len(b), 220)
+}
+
+func TestUnpack_Invalid(t *testing.T) {
+	_, err := Unpack([]byte{0, 1})
+	assert.Equal(t, ShortMessageError, err)
+
+	// a pointer loop
+	b := []byte{0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0xC0, 12, 0, 12, 0, 1}
+	_, err = Unpack(b)
+	assert.Equal(t, InvalidNameError, err)
+}
+
+func TestSplitName(t *testing.T) {
+	var cases = []struct {
+		Name   string
+		Labels []string
+		Err    error
+	}{
+		{Name: "_ipp._tcp.local.", Labels: []string{"_ipp", "_tcp", "local"}},
+		{Name: "_ipp._tcp.local", Labels: []string{"_ipp", "_tcp", "local"}},
+		{Name: `My\.Printer\\1.local.`, Labels: []string{`My.Printer\1`, "local"}},
+		{Name: ".", Labels: nil},
+		{Name: "a..local", Err: InvalidNameError},
+		{Name: `trailing\`, Err: InvalidNameError},
+	}
+
+	for _, c := range cases {
+		labels, err := SplitName(c.Name)
+		assert.Equal(t, c.Err, err, c.Name)
+		assert.Equal(t, c.Labels, labels, c.Name)
+
+		if err == nil && labels != nil {
+			again, _ := SplitName(JoinName(labels...))
+			assert.Equal(t, labels, again)
+		}
+	}
+}
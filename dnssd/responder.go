@@ -0,0 +1,364 @@
+package dnssd
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// mdns multicast groups and port
+var (
+	IPv4Group = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+	IPv6Group = &net.UDPAddr{IP: net.ParseIP("ff02::fb"), Port: 5353}
+)
+
+// record ttls recommended by rfc 6762
+const (
+	hostTTL    = 120
+	serviceTTL = 4500
+
+	maxPacketSize = 9000
+)
+
+// ResponderClosedError is returned by a closed responder
+var ResponderClosedError = errors.New("responder is closed")
+
+// Responder implements a mdns responder which advertises dns-sd services on the local network
+type Responder struct {
+	// AnnounceInterval is the delay between the two unsolicited announcements of a registered service
+	AnnounceInterval time.Duration
+
+	mu       sync.Mutex
+	services map[string]*Service
+	conns    []multicastConn
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// multicastConn is a connection which joined the mdns group of its address family
+type multicastConn struct {
+	*net.UDPConn
+	group *net.UDPAddr
+}
+
+// NewResponder joins the mdns multicast groups and starts answering queries. at least the ipv4 group must be joined,
+// ipv6 is optional
+func NewResponder() (*Responder, error) {
+	conn4, err := net.ListenMulticastUDP("udp4", nil, IPv4Group)
+	if err != nil {
+		return nil, err
+	}
+
+	r := newResponder()
+	r.conns = append(r.conns, multicastConn{conn4, IPv4Group})
+
+	if conn6, err := net.ListenMulticastUDP("udp6", nil, IPv6Group); err == nil {
+		r.conns = append(r.conns, multicastConn{conn6, IPv6Group})
+	}
+
+	for _, conn := range r.conns {
+		r.wg.Add(1)
+		go r.serve(conn)
+	}
+
+	return r, nil
+}
+
+func newResponder() *Responder {
+	return &Responder{
+		AnnounceInterval: time.Second,
+		services:         make(map[string]*Service),
+	}
+}
+
+// Register advertises a service. the service is announced twice and answered until it is unregistered
+func (r *Responder) Register(service *Service) error {
+	if err := service.validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return ResponderClosedError
+	}
+	r.services[strings.ToLower(service.InstanceName())] = service
+	r.mu.Unlock()
+
+	msg := &Message{Response: true, Authoritative: true}
+	msg.Answers, msg.Additionals = serviceRecords(service)
+
+	if err := r.send(msg); err != nil {
+		return err
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		time.Sleep(r.AnnounceInterval)
+		if r.isRegistered(service) {
+			_ = r.send(msg)
+		}
+	}()
+
+	return nil
+}
+
+// Unregister stops advertising a service and sends a goodbye message
+func (r *Responder) Unregister(service *Service) error {
+	r.mu.Lock()
+	name := strings.ToLower(service.InstanceName())
+	_, ok := r.services[name]
+	delete(r.services, name)
+	r.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	return r.send(goodbye(service))
+}
+
+// Close unregisters all services and leaves the multicast groups
+func (r *Responder) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+
+	services := make([]*Service, 0, len(r.services))
+	for _, service := range r.services {
+		services = append(services, service)
+	}
+	r.services = make(map[string]*Service)
+	r.mu.Unlock()
+
+	for _, service := range services {
+		_ = r.send(goodbye(service))
+	}
+
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+
+	var err error
+	for _, conn := range r.conns {
+		if closeErr := conn.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	r.wg.Wait()
+	return err
+}
+
+func (r *Responder) isRegistered(service *Service) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.services[strings.ToLower(service.InstanceName())] == service
+}
+
+func (r *Responder) serve(conn multicastConn) {
+	defer r.wg.Done()
+
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, src, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+
+		query, err := Unpack(buf[:n])
+		if err != nil || query.Response {
+			continue
+		}
+
+		resp := r.answer(query)
+		if resp == nil {
+			continue
+		}
+
+		// legacy unicast queries are not sent from the mdns port and expect a direct answer
+		if src.Port != conn.group.Port {
+			resp.ID = query.ID
+			resp.Questions = query.Questions
+			_ = r.sendTo(conn, resp, src)
+			continue
+		}
+
+		unicast := true
+		for _, q := range query.Questions {
+			unicast = unicast && q.Unicast
+		}
+
+		if unicast {
+			_ = r.sendTo(conn, resp, src)
+		} else {
+			_ = r.send(resp)
+		}
+	}
+}
+
+// answer builds the response to a query, nil is returned if no question concerns a registered service
+func (r *Responder) answer(query *Message) *Message {
+	r.mu.Lock()
+	services := make([]*Service, 0, len(r.services))
+	for _, service := range r.services {
+		services = append(services, service)
+	}
+	r.mu.Unlock()
+
+	resp := &Message{Response: true, Authoritative: true}
+	type recordKey struct {
+		name, target, ip string
+		typ              uint16
+	}
+	seen := make(map[recordKey]bool)
+
+	add := func(section *[]Record, records ...Record) {
+		for _, record := range records {
+			key := recordKey{strings.ToLower(record.Name), strings.ToLower(record.Target), record.IP.String(), record.Type}
+			if !seen[key] {
+				seen[key] = true
+				*section = append(*section, record)
+			}
+		}
+	}
+
+	for _, q := range query.Questions {
+		for _, service := range services {
+			answers, additionals := serviceRecords(service)
+
+			switch {
+			case EqualNames(q.Name, servicesName+"."+service.domain()) && matchType(q.Type, TypePTR):
+				add(&resp.Answers, Record{
+					Name: servicesName + "." + service.domain() + ".", Type: TypePTR, TTL: serviceTTL,
+					Target: service.ServiceName(),
+				})
+			case matchesServiceName(service, q.Name) && matchType(q.Type, TypePTR):
+				ptr := answers[0]
+				ptr.Name = q.Name
+				add(&resp.Answers, ptr)
+				add(&resp.Additionals, answers[len(service.Subtypes)+1:]...)
+				add(&resp.Additionals, additionals...)
+			case EqualNames(q.Name, service.InstanceName()):
+				for _, record := range answers[len(service.Subtypes)+1:] {
+					if matchType(q.Type, record.Type) {
+						add(&resp.Answers, record)
+					}
+				}
+				if matchType(q.Type, TypeSRV) {
+					add(&resp.Additionals, additionals...)
+				}
+			case EqualNames(q.Name, service.HostName()):
+				for _, record := range additionals {
+					if matchType(q.Type, record.Type) {
+						add(&resp.Answers, record)
+					}
+				}
+			}
+		}
+	}
+
+	if len(resp.Answers) == 0 {
+		return nil
+	}
+
+	return resp
+}
+
+// send multicasts a message to the mdns groups
+func (r *Responder) send(msg *Message) error {
+	r.mu.Lock()
+	conns := r.conns
+	closed := r.closed
+	r.mu.Unlock()
+
+	if closed {
+		return ResponderClosedError
+	}
+
+	var err error
+	for _, conn := range conns {
+		if sendErr := r.sendTo(conn, msg, conn.group); err == nil {
+			err = sendErr
+		}
+	}
+
+	return err
+}
+
+func (r *Responder) sendTo(conn multicastConn, msg *Message, addr *net.UDPAddr) error {
+	b, err := msg.Pack()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.WriteToUDP(b, addr)
+	return err
+}
+
+// serviceRecords returns the ptr records of the service type and subtypes followed by the srv and txt record of the
+// instance as answers, and the address records of the host as additionals
+func serviceRecords(service *Service) ([]Record, []Record) {
+	instance := service.InstanceName()
+	host := service.HostName()
+
+	answers := []Record{{Name: service.ServiceName(), Type: TypePTR, TTL: serviceTTL, Target: instance}}
+	for _, subtype := range service.Subtypes {
+		answers = append(answers, Record{
+			Name: service.SubtypeName(subtype), Type: TypePTR, TTL: serviceTTL, Target: instance,
+		})
+	}
+
+	answers = append(answers,
+		Record{
+			Name: instance, Type: TypeSRV, CacheFlush: true, TTL: hostTTL,
+			Target: host, Port: uint16(service.Port),
+		},
+		Record{Name: instance, Type: TypeTXT, CacheFlush: true, TTL: serviceTTL, Text: service.TXT()},
+	)
+
+	var additionals []Record
+	for _, ip := range service.addresses() {
+		record := Record{Name: host, Type: TypeAAAA, CacheFlush: true, TTL: hostTTL, IP: ip}
+		if ip.To4() != nil {
+			record.Type = TypeA
+		}
+		additionals = append(additionals, record)
+	}
+
+	return answers, additionals
+}
+
+// goodbye returns a message which removes the records of the service from all caches
+func goodbye(service *Service) *Message {
+	answers, _ := serviceRecords(service)
+	for i := range answers {
+		answers[i].TTL = 0
+	}
+
+	return &Message{Response: true, Authoritative: true, Answers: answers}
+}
+
+func matchesServiceName(service *Service, name string) bool {
+	if EqualNames(name, service.ServiceName()) {
+		return true
+	}
+
+	for _, subtype := range service.Subtypes {
+		if EqualNames(name, service.SubtypeName(subtype)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchType(question, record uint16) bool {
+	return question == TypeANY || question == record
+}
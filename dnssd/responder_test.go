@@ -0,0 +1,69 @@
+package dnssd
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestService() *Service {
+	return &Service{
+		Instance: "Office Printer",
+		Type:     ServiceTypeIPP,
+		Subtypes: []string{"_universal", "_print"},
+		Host:     "printer",
+		Port:     631,
+		Text:     map[string]string{"txtvers": "1", "rp": "ipp/print", "UUID": "1234"},
+		IPs:      []net.IP{net.IPv4(10, 0, 0, 5)},
+	}
+}
+
+func TestService(t *testing.T) {
+	service := newTestService()
+
+	assert.Equal(t, "_ipp._tcp.local.", service.ServiceName())
+	assert.Equal(t, "_universal._sub._ipp._tcp.local.", service.SubtypeName("_universal"))
+	assert.Equal(t, "Office Printer._ipp._tcp.local.", service.InstanceName())
+	assert.Equal(t, "printer.local.", service.HostName())
+	assert.Equal(t, []string{"txtvers=1", "UUID=1234", "rp=ipp/print"}, service.TXT())
+
+	assert.Equal(t, map[string]string{"txtvers": "1", "uuid": "1234", "rp": "ipp/print"}, ParseTXT(service.TXT()))
+}
+
+func TestResponder_Answer(t *testing.T) {
+	r := newResponder()
+	service := newTestService()
+	assert.Nil(t, r.Register(service))
+	assert.Equal(t, InvalidServiceError, r.Register(&Service{Instance: "broken"}))
+
+	resp := r.answer(&Message{Questions: []Question{{Name: "_universal._sub._ipp._tcp.local.", Type: TypePTR}}})
+	assert.NotNil(t, resp)
+	assert.Len(t, resp.Answers, 1)
+	assert.Equal(t, "Office Printer._ipp._tcp.local.", resp.Answers[0].Target)
+	assert.Len(t, resp.Additionals, 3)
+
+	resp = r.answer(&Message{Questions: []Question{{Name: "office printer._IPP._tcp.local.", Type: TypeTXT}}})
+	assert.Len(t, resp.Answers, 1)
+	assert.Equal(t, service.TXT(), resp.Answers[0].Text)
+
+	resp = r.answer(&Message{Questions: []Question{{Name: "printer.local.", Type: TypeANY}}})
+	assert.Len(t, resp.Answers, 1)
+	assert.Equal(t, TypeA, resp.Answers[0].Type)
+
+	resp = r.answer(&Message{Questions: []Question{{Name: "_services._dns-sd._udp.local.", Type: TypePTR}}})
+	assert.Equal(t, "_ipp._tcp.local.", resp.Answers[0].Target)
+
+	assert.Nil(t, r.answer(&Message{Questions: []Question{{Name: "_ipps._tcp.local.", Type: TypePTR}}}))
+
+	assert.Nil(t, r.Unregister(service))
+	assert.Nil(t, r.answer(&Message{Questions: []Question{{Name: "_ipp._tcp.local.", Type: TypePTR}}}))
+}
+
+func TestGoodbye(t *testing.T) {
+	msg := goodbye(newTestService())
+	assert.Len(t, msg.Answers, 5)
+	for _, record := range msg.Answers {
+		assert.Equal(t, uint32(0), record.TTL)
+	}
+}
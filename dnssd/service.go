@@ -0,0 +1,161 @@
+package dnssd
+
+import (
+	"errors"
+	"net"
+	"os"
+	"sort"
+	"strings"
+)
+
+// common service types and the default domain
+const (
+	ServiceTypeIPP  = "_ipp._tcp"
+	ServiceTypeIPPS = "_ipps._tcp"
+	DefaultDomain   = "local"
+
+	// servicesName is the name used to enumerate all service types of a domain
+	servicesName = "_services._dns-sd._udp"
+)
+
+// InvalidServiceError is returned if a service is missing its instance, type or port
+var InvalidServiceError = errors.New("service requires a instance name, a type and a port")
+
+// Service defines a dns-sd service instance
+type Service struct {
+	// Instance is the user visible name of the service, it may contain spaces and dots
+	Instance string
+	// Type is the service type like _ipp._tcp
+	Type string
+	// Subtypes are additional subtypes of the service type like _universal or _print
+	Subtypes []string
+	// Domain is the domain of the service, defaults to local
+	Domain string
+	// Host is the host name of the target, defaults to the host name of the system
+	Host string
+	Port int
+	// Text contains the key value pairs of the txt record
+	Text map[string]string
+	// IPs are the addresses of the host, defaults to the addresses of all up interfaces
+	IPs []net.IP
+}
+
+// ServiceName returns the fully qualified name of the service type, e.g. _ipp._tcp.local.
+func (s *Service) ServiceName() string {
+	return s.Type + "." + s.domain() + "."
+}
+
+// SubtypeName returns the fully qualified name of a subtype, e.g. _universal._sub._ipp._tcp.local.
+func (s *Service) SubtypeName(subtype string) string {
+	return subtype + "._sub." + s.ServiceName()
+}
+
+// InstanceName returns the fully qualified name of the service instance
+func (s *Service) InstanceName() string {
+	return JoinName(s.Instance) + s.ServiceName()
+}
+
+// HostName returns the fully qualified host name of the target
+func (s *Service) HostName() string {
+	host := s.Host
+	if host == "" {
+		host, _ = os.Hostname()
+		if i := strings.IndexByte(host, '.'); i >= 0 {
+			host = host[:i]
+		}
+		if host == "" {
+			host = "localhost"
+		}
+	}
+
+	if !strings.HasSuffix(host, ".") {
+		host += "." + s.domain() + "."
+	}
+
+	return host
+}
+
+// TXT encodes the text of the service as txt strings. the txtvers key is always written first, the other keys are
+// sorted
+func (s *Service) TXT() []string {
+	keys := make([]string, 0, len(s.Text))
+	for key := range s.Text {
+		if key != "txtvers" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	if _, ok := s.Text["txtvers"]; ok {
+		keys = append([]string{"txtvers"}, keys...)
+	}
+
+	text := make([]string, len(keys))
+	for i, key := range keys {
+		text[i] = key + "=" + s.Text[key]
+	}
+
+	return text
+}
+
+// addresses returns the configured addresses or the addresses of all up interfaces
+func (s *Service) addresses() []net.IP {
+	if len(s.IPs) > 0 {
+		return s.IPs
+	}
+
+	var ips []net.IP
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() {
+			ips = append(ips, ipNet.IP)
+		}
+	}
+
+	return ips
+}
+
+func (s *Service) domain() string {
+	if s.Domain == "" {
+		return DefaultDomain
+	}
+
+	return strings.Trim(s.Domain, ".")
+}
+
+func (s *Service) validate() error {
+	if s.Instance == "" || s.Type == "" || s.Port <= 0 || s.Port > 0xFFFF {
+		return InvalidServiceError
+	}
+
+	return nil
+}
+
+// ParseTXT parses txt strings into key value pairs. keys are case insensitive and are returned in lower case, only the
+// first occurrence of a key is used
+func ParseTXT(text []string) map[string]string {
+	values := make(map[string]string, len(text))
+
+	for _, s := range text {
+		key, value := s, ""
+		if i := strings.IndexByte(s, '='); i >= 0 {
+			key, value = s[:i], s[i+1:]
+		}
+
+		key = strings.ToLower(key)
+		if key == "" {
+			continue
+		}
+
+		if _, ok := values[key]; !ok {
+			values[key] = value
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,227 @@
+package ipp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NoFilterError is returned if no chain of filters converts a document into one of the requested formats
+var NoFilterError = errors.New("no document filter")
+
+// DocumentFilter converts documents from one format into another, e.g. pdf into pwg raster. filters are registered
+// in a FilterRegistry, which is used by the client to convert documents the printer does not support and by the
+// server to normalize received documents
+type DocumentFilter interface {
+	// InputFormat is the mime type of the documents the filter reads
+	InputFormat() string
+	// OutputFormat is the mime type of the documents the filter writes
+	OutputFormat() string
+	// Transform returns the converted document. conversion errors are returned by the reader, readers which are not
+	// read to the end should be closed if they implement io.Closer
+	Transform(r io.Reader) io.Reader
+}
+
+// NewDocumentFilter returns a filter which converts documents with the transform function
+func NewDocumentFilter(input, output string, transform func(r io.Reader) io.Reader) DocumentFilter {
+	return &funcFilter{input: input, output: output, transform: transform}
+}
+
+type funcFilter struct {
+	input, output string
+	transform     func(r io.Reader) io.Reader
+}
+
+func (f *funcFilter) InputFormat() string {
+	return f.input
+}
+
+func (f *funcFilter) OutputFormat() string {
+	return f.output
+}
+
+func (f *funcFilter) Transform(r io.Reader) io.Reader {
+	return f.transform(r)
+}
+
+// CommandFilter is a DocumentFilter which runs an external converter like ghostscript or mutool. the document is
+// written to the standard input of the command and the converted document is read from its standard output, e.g.
+//
+//	&CommandFilter{Input: MimeTypePDF, Output: MimeTypePwgRaster, Path: "gs",
+//		Args: []string{"-q", "-dNOPAUSE", "-dBATCH", "-sDEVICE=pwgraster", "-r300", "-sOutputFile=-", "-"}}
+type CommandFilter struct {
+	Input  string
+	Output string
+	// Path is the command, it is looked up in PATH if it contains no path separator
+	Path string
+	Args []string
+}
+
+// InputFormat returns the input format of the command
+func (f *CommandFilter) InputFormat() string {
+	return f.Input
+}
+
+// OutputFormat returns the output format of the command
+func (f *CommandFilter) OutputFormat() string {
+	return f.Output
+}
+
+// Transform starts the command, the returned reader fails with the exit status and the standard error output of a
+// failed command. closing the reader stops a command which is still running
+func (f *CommandFilter) Transform(r io.Reader) io.Reader {
+	pr, pw := io.Pipe()
+
+	cmd := exec.Command(f.Path, f.Args...)
+	cmd.Stdin = r
+	cmd.Stdout = pw
+	stderr := &limitedBuffer{limit: 4096}
+	cmd.Stderr = stderr
+
+	go func() {
+		err := cmd.Run()
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				err = fmt.Errorf("%s: %w: %s", f.Path, err, msg)
+			} else {
+				err = fmt.Errorf("%s: %w", f.Path, err)
+			}
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return pr
+}
+
+// limitedBuffer keeps the first bytes written to it and drops the rest
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(room, len(p))])
+	}
+
+	return len(p), nil
+}
+
+// FilterRegistry holds document filters and finds chains of filters between formats. it is safe for concurrent use
+type FilterRegistry struct {
+	mu      sync.RWMutex
+	filters []DocumentFilter
+}
+
+// NewFilterRegistry returns a registry with the filters
+func NewFilterRegistry(filters ...DocumentFilter) *FilterRegistry {
+	r := &FilterRegistry{}
+	r.Register(filters...)
+	return r
+}
+
+// Register adds filters to the registry, filters registered first are preferred for chains of the same length
+func (r *FilterRegistry) Register(filters ...DocumentFilter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.filters = append(r.filters, filters...)
+}
+
+// Filters returns the registered filters
+func (r *FilterRegistry) Filters() []DocumentFilter {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]DocumentFilter(nil), r.filters...)
+}
+
+// Chain returns the shortest chain of filters which converts the input format into one of the output formats. if
+// several outputs are reached with chains of the same length, the first of the outputs is chosen. an empty chain is
+// returned if the input is one of the outputs, an error wrapping NoFilterError if no chain exists
+func (r *FilterRegistry) Chain(input string, outputs ...string) ([]DocumentFilter, error) {
+	filters := r.Filters()
+
+	// chains contains the shortest chain to every reached format
+	chains := map[string][]DocumentFilter{strings.ToLower(input): {}}
+	level := []string{strings.ToLower(input)}
+
+	for len(level) > 0 {
+		for _, output := range outputs {
+			if chain, ok := chains[strings.ToLower(output)]; ok {
+				return chain, nil
+			}
+		}
+
+		var next []string
+		for _, format := range level {
+			for _, filter := range filters {
+				out := strings.ToLower(filter.OutputFormat())
+				if !strings.EqualFold(filter.InputFormat(), format) {
+					continue
+				}
+				if _, ok := chains[out]; ok {
+					continue
+				}
+				chains[out] = append(append([]DocumentFilter{}, chains[format]...), filter)
+				next = append(next, out)
+			}
+		}
+		level = next
+	}
+
+	return nil, fmt.Errorf("%w: converting %s into %s", NoFilterError, input, strings.Join(outputs, ", "))
+}
+
+// Transform converts the document from the input format into one of the output formats with the filters of Chain.
+// the converted document and its format are returned
+func (r *FilterRegistry) Transform(document io.Reader, input string, outputs ...string) (io.Reader, string, error) {
+	chain, err := r.Chain(input, outputs...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	format := input
+	for _, filter := range chain {
+		document = filter.Transform(document)
+		format = filter.OutputFormat()
+	}
+
+	return document, format, nil
+}
+
+// InputFormats returns the formats which can be converted into one of the output formats, the outputs first and
+// the other formats sorted, e.g. to advertise the document-format-supported of a printer whose sink handles the
+// outputs
+func (r *FilterRegistry) InputFormats(outputs ...string) []string {
+	filters := r.Filters()
+
+	reached := make(map[string]bool)
+	formats := make([]string, 0, len(outputs))
+	for _, output := range outputs {
+		if !reached[strings.ToLower(output)] {
+			reached[strings.ToLower(output)] = true
+			formats = append(formats, output)
+		}
+	}
+
+	var inputs []string
+	for i := 0; i < len(formats); i++ {
+		for _, filter := range filters {
+			input := filter.InputFormat()
+			if strings.EqualFold(filter.OutputFormat(), formats[i]) && !reached[strings.ToLower(input)] {
+				reached[strings.ToLower(input)] = true
+				formats = append(formats, input)
+				inputs = append(inputs, input)
+			}
+		}
+	}
+
+	sort.Strings(inputs)
+	return append(formats[:len(formats)-len(inputs)], inputs...)
+}
@@ -0,0 +1,129 @@
+package ipp
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// upperFilter converts the document to upper case and changes its format
+func upperFilter(input, output string) DocumentFilter {
+	return NewDocumentFilter(input, output, func(r io.Reader) io.Reader {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return &errorReader{err}
+		}
+		return strings.NewReader(strings.ToUpper(string(b)) + "|" + output)
+	})
+}
+
+type errorReader struct {
+	err error
+}
+
+func (r *errorReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
+
+func TestFilterRegistry_Chain(t *testing.T) {
+	r := NewFilterRegistry(
+		upperFilter("text/plain", MimeTypePDF),
+		upperFilter(MimeTypePDF, MimeTypePwgRaster),
+		upperFilter(MimeTypePwgRaster, MimeTypeUrf),
+		upperFilter("image/jpeg", MimeTypeUrf),
+	)
+
+	formats := func(chain []DocumentFilter) []string {
+		var f []string
+		for _, filter := range chain {
+			f = append(f, filter.OutputFormat())
+		}
+		return f
+	}
+
+	chain, err := r.Chain("text/plain", MimeTypeUrf)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{MimeTypePDF, MimeTypePwgRaster, MimeTypeUrf}, formats(chain))
+
+	// the shorter chain wins over the order of the outputs
+	chain, err = r.Chain("text/plain", MimeTypeUrf, MimeTypePwgRaster)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{MimeTypePDF, MimeTypePwgRaster}, formats(chain))
+
+	chain, err = r.Chain(MimeTypePDF, "application/PDF")
+	assert.Nil(t, err)
+	assert.Empty(t, chain)
+
+	_, err = r.Chain(MimeTypeUrf, MimeTypePDF)
+	assert.ErrorIs(t, err, NoFilterError)
+
+	document, format, err := r.Transform(strings.NewReader("hello"), "text/plain", MimeTypePwgRaster)
+	assert.Nil(t, err)
+	assert.Equal(t, MimeTypePwgRaster, format)
+	b, _ := io.ReadAll(document)
+	assert.Equal(t, "HELLO|APPLICATION/PDF|image/pwg-raster", string(b))
+
+	assert.Equal(t, []string{MimeTypePwgRaster, MimeTypePDF, "text/plain"}, r.InputFormats(MimeTypePwgRaster))
+	assert.Equal(t, []string{MimeTypeUrf, MimeTypePDF, "image/jpeg", MimeTypePwgRaster, "text/plain"},
+		r.InputFormats(MimeTypeUrf))
+}
+
+func TestCommandFilter(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	filter := &CommandFilter{Input: "text/plain", Output: "text/x-reversed", Path: "sh", Args: []string{"-c", "tr a-z A-Z"}}
+	b, err := io.ReadAll(filter.Transform(strings.NewReader("hello")))
+	assert.Nil(t, err)
+	assert.Equal(t, "HELLO", string(b))
+
+	filter.Args = []string{"-c", "echo broken input >&2; exit 3"}
+	_, err = io.ReadAll(filter.Transform(bytes.NewReader(nil)))
+	var exitErr *exec.ExitError
+	assert.ErrorAs(t, err, &exitErr)
+	assert.Contains(t, err.Error(), "broken input")
+}
+
+func TestIPPClient_Filters(t *testing.T) {
+	adapter := &testAdapter{respond: func(req *Request) *Response {
+		resp := NewResponse(StatusOk, req.RequestId)
+		if req.Operation == OperationGetPrinterAttributes {
+			attributes := make(Attributes)
+			attributes.Set(AttributeDocumentFormatSupported, TagMimeType, MimeTypePDF, MimeTypeOctetStream)
+			resp.PrinterAttributes = append(resp.PrinterAttributes, attributes)
+			return resp
+		}
+		resp.JobAttributes = append(resp.JobAttributes, Attributes{
+			AttributeJobID: []Attribute{{Tag: TagInteger, Value: 3}},
+		})
+		return resp
+	}}
+	client := NewIPPClientWithAdapter("user", adapter)
+	client.SetFilters(NewFilterRegistry(upperFilter("text/plain", MimeTypePDF)))
+
+	_, err := client.PrintJob(Document{Document: strings.NewReader("hello"), Size: 5, Name: "note", MimeType: "text/plain"},
+		"printer", map[string]interface{}{})
+	assert.Nil(t, err)
+
+	req := adapter.requests[len(adapter.requests)-1]
+	assert.Equal(t, MimeTypePDF, req.OperationAttributes[AttributeDocumentFormat])
+	assert.Equal(t, 21, req.FileSize)
+	b, _ := io.ReadAll(req.File)
+	assert.Equal(t, "HELLO|application/pdf", string(b))
+
+	// supported formats are sent unchanged
+	_, err = client.PrintDocuments([]Document{{Document: strings.NewReader("%PDF"), Size: 4, MimeType: MimeTypePDF}},
+		"printer", map[string]interface{}{})
+	assert.Nil(t, err)
+	req = adapter.requests[len(adapter.requests)-1]
+	assert.Equal(t, 4, req.FileSize)
+
+	_, err = client.PrintJob(Document{Document: strings.NewReader(""), MimeType: "image/tiff"}, "printer",
+		map[string]interface{}{})
+	assert.ErrorIs(t, err, NoFilterError)
+}
@@ -0,0 +1,89 @@
+package ipp
+
+// DocumentFormatDetails is the document-format-details collection of pwg 5100.7. it describes the document data in
+// more detail than document-format, production printers use it to select the interpreter, e.g. for a pdf/x version
+type DocumentFormatDetails struct {
+	// Format is the document-format, e.g. application/pdf
+	Format string
+	// FormatVersion is the document-format-version, e.g. PDF/X-4
+	FormatVersion string
+	// FormatDeviceID is the document-format-device-id, the ieee 1284 device id of the printer the document was
+	// generated for
+	FormatDeviceID string
+	// NaturalLanguages are the document-natural-language values of the content, e.g. en-us
+	NaturalLanguages []string
+
+	SourceApplicationName    string
+	SourceApplicationVersion string
+	SourceOSName             string
+	SourceOSVersion          string
+}
+
+// Collection returns the wire form of the document-format-details, it can be used as value of a
+// document-format-details operation attribute. empty members are not sent
+func (d DocumentFormatDetails) Collection() Attributes {
+	c := make(Attributes)
+
+	if d.Format != "" {
+		c.Set(AttributeDocumentFormat, TagMimeType, d.Format)
+	}
+	if d.FormatVersion != "" {
+		c.Set(AttributeDocumentFormatVersion, TagText, d.FormatVersion)
+	}
+	if d.FormatDeviceID != "" {
+		c.Set(AttributeDocumentFormatDeviceID, TagText, d.FormatDeviceID)
+	}
+	if len(d.NaturalLanguages) > 0 {
+		languages := make([]interface{}, len(d.NaturalLanguages))
+		for i, language := range d.NaturalLanguages {
+			languages[i] = language
+		}
+		c.Set(AttributeDocumentNaturalLanguage, TagLanguage, languages...)
+	}
+	if d.SourceApplicationName != "" {
+		c.Set(AttributeDocumentSourceApplicationName, TagName, d.SourceApplicationName)
+	}
+	if d.SourceApplicationVersion != "" {
+		c.Set(AttributeDocumentSourceApplicationVersion, TagText, d.SourceApplicationVersion)
+	}
+	if d.SourceOSName != "" {
+		c.Set(AttributeDocumentSourceOsName, TagName, d.SourceOSName)
+	}
+	if d.SourceOSVersion != "" {
+		c.Set(AttributeDocumentSourceOsVersion, TagText, d.SourceOSVersion)
+	}
+
+	return c
+}
+
+// Unmarshal populates the document-format-details from its decoded collection
+func (d *DocumentFormatDetails) Unmarshal(c Attributes) error {
+	u := attributeUnmarshaler{attributes: c}
+
+	*d = DocumentFormatDetails{
+		Format:                   u.string(AttributeDocumentFormat),
+		FormatVersion:            u.string(AttributeDocumentFormatVersion),
+		FormatDeviceID:           u.string(AttributeDocumentFormatDeviceID),
+		NaturalLanguages:         u.strings(AttributeDocumentNaturalLanguage),
+		SourceApplicationName:    u.string(AttributeDocumentSourceApplicationName),
+		SourceApplicationVersion: u.string(AttributeDocumentSourceApplicationVersion),
+		SourceOSName:             u.string(AttributeDocumentSourceOsName),
+		SourceOSVersion:          u.string(AttributeDocumentSourceOsVersion),
+	}
+
+	return u.err
+}
+
+// WithDocumentFormatDetails sets the document-format-details operation attribute of a Print-Job or Send-Document
+// request
+func WithDocumentFormatDetails(details DocumentFormatDetails) RequestOption {
+	return func(r *Request) {
+		r.OperationAttributes[AttributeDocumentFormatDetails] = details.Collection()
+	}
+}
+
+// SupportsDocumentFormatDetails reports whether the printer accepts the member of document-format-details, e.g.
+// document-format-version. the printer ignores the collection if document-format-details-supported is empty
+func (d *PrinterDescription) SupportsDocumentFormatDetails(member string) bool {
+	return containsString(d.DocumentFormatDetailsSupported, member)
+}
@@ -0,0 +1,57 @@
+package ipp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentFormatDetails(t *testing.T) {
+	details := DocumentFormatDetails{
+		Format:                   MimeTypePDF,
+		FormatVersion:            "PDF/X-4",
+		NaturalLanguages:         []string{"en-us", "de-de"},
+		SourceApplicationName:    "Press Layout",
+		SourceApplicationVersion: "12.1",
+		SourceOSName:             "Linux",
+	}
+
+	adapter := &testAdapter{}
+	client := NewIPPClientWithAdapter("user", adapter)
+	_, err := client.SubmitJob(Document{Document: bytes.NewReader([]byte("%PDF-1.7")), Size: 8, Name: "brochure",
+		MimeType: MimeTypePDF, FormatDetails: &details}, "press", nil)
+	assert.Nil(t, err)
+
+	req := adapter.requests[0]
+	assert.Equal(t, details.Collection(), req.OperationAttributes[AttributeDocumentFormatDetails])
+	assert.Empty(t, Lint(withCharset(req)))
+
+	// the members are sent with their value tags
+	req.File = nil
+	data, err := req.Encode()
+	assert.Nil(t, err)
+	scanner, err := NewMessageScanner(bytes.NewReader(data))
+	assert.Nil(t, err)
+	var collection Attribute
+	for _, attr := range scanner.Values() {
+		if attr.Name == AttributeDocumentFormatDetails {
+			collection = attr
+		}
+	}
+	assert.Nil(t, scanner.Err())
+	assert.Equal(t, TagBeginCollection, collection.Tag)
+	members, _ := collection.Value.(Attributes)
+	assert.Equal(t, TagMimeType, members[AttributeDocumentFormat][0].Tag)
+	assert.Equal(t, TagLanguage, members[AttributeDocumentNaturalLanguage][1].Tag)
+	assert.Equal(t, TagName, members[AttributeDocumentSourceOsName][0].Tag)
+
+	var decoded DocumentFormatDetails
+	assert.Nil(t, decoded.Unmarshal(members))
+	assert.Equal(t, details, decoded)
+
+	d := PrinterDescription{DocumentFormatDetailsSupported: []string{AttributeDocumentFormat,
+		AttributeDocumentFormatVersion}}
+	assert.True(t, d.SupportsDocumentFormatDetails(AttributeDocumentFormatVersion))
+	assert.False(t, d.SupportsDocumentFormatDetails(AttributeDocumentSourceOsName))
+}
@@ -0,0 +1,222 @@
+package ipp
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// enumKeyword returns the keyword of an enum value of the known enum attributes and their -default and -supported
+// attributes
+func enumKeyword(name string, value int) (string, bool) {
+	switch strings.TrimSuffix(strings.TrimSuffix(name, "-supported"), "-default") {
+	case AttributePrinterState:
+		return PrinterState(value).String(), true
+	case AttributeJobState:
+		return JobState(value).String(), true
+	case "operations":
+		return Operation(value).String(), true
+	case AttributeFinishings:
+		return Finishings(value).String(), true
+	case AttributeOrientationRequested:
+		return Orientation(value).String(), true
+	case AttributePrintQuality:
+		return PrintQuality(value).String(), true
+	}
+
+	return "", false
+}
+
+// ParseEnumKeyword returns the enum value of a keyword of the known enum attributes and their -default and
+// -supported attributes, e.g. high for print-quality
+func ParseEnumKeyword(name, keyword string) (int, bool) {
+	var value int
+	var err error
+
+	switch strings.TrimSuffix(strings.TrimSuffix(name, "-supported"), "-default") {
+	case AttributePrinterState:
+		value, err = parseEnum(printerStateKeywords, keyword)
+	case AttributeJobState:
+		value, err = parseEnum(jobStateKeywords, keyword)
+	case "operations":
+		value, err = parseEnum(operationNames, keyword)
+	case AttributeFinishings:
+		value, err = parseEnum(finishingsKeywords, keyword)
+	case AttributeOrientationRequested:
+		value, err = parseEnum(orientationKeywords, keyword)
+	case AttributePrintQuality:
+		value, err = parseEnum(printQualityKeywords, keyword)
+	default:
+		return 0, false
+	}
+
+	return value, err == nil
+}
+
+// Dump writes a human readable rendering of a request or response in the style of ipptool to w. every attribute is
+// printed with its syntax and values, e.g.
+//
+//	printer-state (enum) = idle
+//	copies-supported (rangeOfInteger) = 1-99
+//
+// msg must be a Request or Response or a pointer to one
+func Dump(w io.Writer, msg interface{}) error {
+	var b strings.Builder
+
+	switch m := msg.(type) {
+	case *Request:
+		dumpRequest(&b, m)
+	case Request:
+		dumpRequest(&b, &m)
+	case *Response:
+		dumpResponse(&b, m)
+	case Response:
+		dumpResponse(&b, &m)
+	default:
+		return fmt.Errorf("cannot dump message of type %T", msg)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dumpRequest(b *strings.Builder, r *Request) {
+	fmt.Fprintf(b, "version: %d.%d\n", r.ProtocolVersionMajor, r.ProtocolVersionMinor)
+	fmt.Fprintf(b, "operation-id: %s\n", Operation(r.Operation))
+	fmt.Fprintf(b, "request-id: %d\n", r.RequestId)
+
+	groups := []struct {
+		tag        int8
+		attributes map[string]interface{}
+	}{
+		{TagOperation, r.OperationAttributes},
+		{TagJob, r.JobAttributes},
+		{TagPrinter, r.PrinterAttributes},
+		{TagSubscription, r.SubscriptionAttributes},
+	}
+
+	for _, group := range groups {
+		if len(group.attributes) == 0 {
+			continue
+		}
+
+		attributes := make(Attributes, len(group.attributes))
+		for name, value := range group.attributes {
+			tag, _ := r.AttributeTag(name)
+			attributes[name] = requestAttributes(name, tag, value)
+		}
+		dumpGroup(b, group.tag, attributes)
+	}
+}
+
+func dumpResponse(b *strings.Builder, r *Response) {
+	fmt.Fprintf(b, "version: %d.%d\n", r.ProtocolVersionMajor, r.ProtocolVersionMinor)
+	fmt.Fprintf(b, "status-code: %s\n", Status(r.StatusCode))
+	fmt.Fprintf(b, "request-id: %d\n", r.RequestId)
+
+	dumpGroup(b, TagOperation, r.OperationAttributes)
+	if len(r.UnsupportedAttributes) > 0 {
+		dumpGroup(b, TagUnsupportedGroup, r.UnsupportedAttributes)
+	}
+
+	groups := []struct {
+		tag        int8
+		attributes []Attributes
+	}{
+		{TagPrinter, r.PrinterAttributes},
+		{TagJob, r.JobAttributes},
+		{TagSubscription, r.SubscriptionAttributes},
+		{TagEventNotification, r.EventNotificationAttributes},
+	}
+	for _, group := range groups {
+		for _, attributes := range group.attributes {
+			dumpGroup(b, group.tag, attributes)
+		}
+	}
+}
+
+func dumpGroup(b *strings.Builder, tag int8, attributes Attributes) {
+	fmt.Fprintf(b, "%s-attributes-tag:\n", groupNames[tag])
+
+	for _, name := range sortedAttributeNames(attributes) {
+		values := attributes[name]
+		if len(values) == 0 {
+			continue
+		}
+
+		syntax := TagSyntax(dumpTag(name, values[0].Tag))
+		if len(values) > 1 {
+			syntax = "1setOf " + syntax
+		}
+
+		if isOutOfBandTag(values[0].Tag) {
+			fmt.Fprintf(b, "    %s (%s)\n", name, syntax)
+			continue
+		}
+		fmt.Fprintf(b, "    %s (%s) = %s\n", name, syntax, formatValues(name, values))
+	}
+}
+
+// dumpTag returns the tag of a value, values without tag use the AttributeTagMapping like the encoder
+func dumpTag(name string, tag int8) int8 {
+	if tag == TagZero {
+		return AttributeTagMapping[name]
+	}
+
+	return tag
+}
+
+// formatValues formats the values of an attribute separated by commas
+func formatValues(name string, values []Attribute) string {
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		formatted[i] = formatValue(name, dumpTag(name, value.Tag), value.Value)
+	}
+
+	return strings.Join(formatted, ",")
+}
+
+// FormatValue formats an attribute value like Dump, e.g. enum values of known attributes as keyword, ranges as 1-99
+// and resolutions as 600dpi
+func FormatValue(name string, value Attribute) string {
+	return formatValue(name, dumpTag(name, value.Tag), value.Value)
+}
+
+func formatValue(name string, tag int8, value interface{}) string {
+	switch v := value.(type) {
+	case int:
+		if keyword, ok := enumKeyword(name, v); ok && tag == TagEnum {
+			return keyword
+		}
+		return strconv.Itoa(v)
+	case []int32:
+		if tag == TagRange && len(v) == 2 {
+			return fmt.Sprintf("%d-%d", v[0], v[1])
+		}
+	case []int:
+		if t, ok := DecodeDateTime(v); ok && tag == TagDate {
+			return t.Format(time.RFC3339)
+		}
+	case Resolution:
+		units := "dpi"
+		if v.Depth == 4 {
+			units = "dpcm"
+		}
+		if v.Width == v.Height {
+			return fmt.Sprintf("%d%s", v.Width, units)
+		}
+		return fmt.Sprintf("%dx%d%s", v.Width, v.Height, units)
+	case Attributes:
+		members := make([]string, 0, len(v))
+		for _, member := range sortedAttributeNames(v) {
+			if len(v[member]) > 0 {
+				members = append(members, member+"="+formatValues(member, v[member]))
+			}
+		}
+		return "{" + strings.Join(members, " ") + "}"
+	}
+
+	return fmt.Sprint(value)
+}
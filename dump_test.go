@@ -0,0 +1,74 @@
+package ipp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDump(t *testing.T) {
+	media, _ := NewMediaCol("iso_a4_210x297mm")
+
+	req := NewRequest(OperationPrintJob, 1)
+	req.OperationAttributes[AttributePrinterURI] = "ipp://localhost/printers/office"
+	req.JobAttributes[AttributeFinishings] = []int{4, 20}
+	req.JobAttributes[AttributeMediaCol] = media.Collection()
+
+	var buf bytes.Buffer
+	assert.Nil(t, Dump(&buf, req))
+	assert.Equal(t, `version: 2.0
+operation-id: Print-Job
+request-id: 1
+operation-attributes-tag:
+    printer-uri (uri) = ipp://localhost/printers/office
+job-attributes-tag:
+    finishings (1setOf enum) = staple,staple-top-left
+    media-col (collection) = {media-size={x-dimension=21000 y-dimension=29700}}
+`, buf.String())
+
+	resp := NewResponse(StatusOk, 1)
+	printer := make(Attributes)
+	printer.Set(AttributePrinterState, TagEnum, int(PrinterStateStopped))
+	printer.Set(AttributeOperationsSupported, TagEnum, int(OperationPrintJob), int(OperationGetJobs))
+	printer.Set(AttributeCopiesSupported, TagRange, []int32{1, 99})
+	printer.Set(AttributePrinterResolutionSupported, TagResolution, Resolution{Width: 600, Height: 600, Depth: 3},
+		Resolution{Width: 1200, Height: 600, Depth: 3})
+	printer.Set(AttributePrinterLocation, TagNoValue, "")
+	resp.PrinterAttributes = append(resp.PrinterAttributes, printer)
+
+	buf.Reset()
+	assert.Nil(t, Dump(&buf, *resp))
+	lines := strings.Split(buf.String(), "\n")
+	assert.Equal(t, "status-code: successful-ok", lines[1])
+	assert.Contains(t, lines, "printer-attributes-tag:")
+	assert.Contains(t, lines, "    copies-supported (rangeOfInteger) = 1-99")
+	assert.Contains(t, lines, "    operations-supported (1setOf enum) = Print-Job,Get-Jobs")
+	assert.Contains(t, lines, "    printer-location (no-value)")
+	assert.Contains(t, lines, "    printer-resolution-supported (1setOf resolution) = 600dpi,1200x600dpi")
+	assert.Contains(t, lines, "    printer-state (enum) = stopped")
+
+	assert.NotNil(t, Dump(&buf, "request"))
+}
+
+func TestFormatValue(t *testing.T) {
+	assert.Equal(t, "high", FormatValue(AttributePrintQuality, Attribute{Tag: TagEnum, Value: int(PrintQualityHigh)}))
+	assert.Equal(t, "1-99", FormatValue(AttributeCopiesSupported, Attribute{Value: []int32{1, 99}}))
+	assert.Equal(t, "5", FormatValue(AttributeCopies, Attribute{Tag: TagInteger, Value: 5}))
+}
+
+func TestParseEnumKeyword(t *testing.T) {
+	value, ok := ParseEnumKeyword(AttributePrintQualitySupported, "high")
+	assert.True(t, ok)
+	assert.Equal(t, int(PrintQualityHigh), value)
+
+	value, ok = ParseEnumKeyword(AttributeOperationsSupported, "get-printer-attributes")
+	assert.True(t, ok)
+	assert.Equal(t, int(OperationGetPrinterAttributes), value)
+
+	_, ok = ParseEnumKeyword(AttributePrintQuality, "best")
+	assert.False(t, ok)
+	_, ok = ParseEnumKeyword(AttributeSides, "one-sided")
+	assert.False(t, ok)
+}
@@ -0,0 +1,239 @@
+package ipp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var EnumKeywordError = errors.New("unknown enum keyword")
+
+// PrinterState is a printer-state value, the printer state constants can be converted to it
+type PrinterState int8
+
+// JobState is a job-state value, the job state constants can be converted to it
+type JobState int8
+
+// Operation is an operation id, the operation constants can be converted to it
+type Operation int16
+
+// Status is a status code, the status constants can be converted to it
+type Status int16
+
+// Finishings is a finishings value
+type Finishings int
+
+// Orientation is an orientation-requested value, the orientation constants can be converted to it
+type Orientation int8
+
+// PrintQuality is a print-quality value, the print quality constants can be converted to it
+type PrintQuality int8
+
+var printerStateKeywords = map[int]string{
+	int(PrinterStateIdle):       "idle",
+	int(PrinterStateProcessing): "processing",
+	int(PrinterStateStopped):    "stopped",
+}
+
+var jobStateKeywords = map[int]string{
+	int(JobStatePending):    "pending",
+	int(JobStateHeld):       "pending-held",
+	int(JobStateProcessing): "processing",
+	int(JobStateStopped):    "processing-stopped",
+	int(JobStateCanceled):   "canceled",
+	int(JobStateAborted):    "aborted",
+	int(JobStateCompleted):  "completed",
+}
+
+var finishingsKeywords = map[int]string{
+	int(FinishingsNone):                "none",
+	int(FinishingsStaple):              "staple",
+	int(FinishingsPunch):               "punch",
+	int(FinishingsCover):               "cover",
+	int(FinishingsBind):                "bind",
+	int(FinishingsSaddleStitch):        "saddle-stitch",
+	int(FinishingsEdgeStitch):          "edge-stitch",
+	int(FinishingsFold):                "fold",
+	int(FinishingsTrim):                "trim",
+	int(FinishingsBale):                "bale",
+	int(FinishingsBookletMaker):        "booklet-maker",
+	int(FinishingsJogOffset):           "jog-offset",
+	int(FinishingsCoat):                "coat",
+	int(FinishingsLaminate):            "laminate",
+	int(FinishingsStapleTopLeft):       "staple-top-left",
+	int(FinishingsStapleBottomLeft):    "staple-bottom-left",
+	int(FinishingsStapleTopRight):      "staple-top-right",
+	int(FinishingsStapleBottomRight):   "staple-bottom-right",
+	int(FinishingsEdgeStitchLeft):      "edge-stitch-left",
+	int(FinishingsEdgeStitchTop):       "edge-stitch-top",
+	int(FinishingsEdgeStitchRight):     "edge-stitch-right",
+	int(FinishingsEdgeStitchBottom):    "edge-stitch-bottom",
+	int(FinishingsStapleDualLeft):      "staple-dual-left",
+	int(FinishingsStapleDualTop):       "staple-dual-top",
+	int(FinishingsStapleDualRight):     "staple-dual-right",
+	int(FinishingsStapleDualBottom):    "staple-dual-bottom",
+	int(FinishingsStapleTripleLeft):    "staple-triple-left",
+	int(FinishingsStapleTripleTop):     "staple-triple-top",
+	int(FinishingsStapleTripleRight):   "staple-triple-right",
+	int(FinishingsStapleTripleBottom):  "staple-triple-bottom",
+	int(FinishingsBindLeft):            "bind-left",
+	int(FinishingsBindTop):             "bind-top",
+	int(FinishingsBindRight):           "bind-right",
+	int(FinishingsBindBottom):          "bind-bottom",
+	int(FinishingsTrimAfterPages):      "trim-after-pages",
+	int(FinishingsTrimAfterDocuments):  "trim-after-documents",
+	int(FinishingsTrimAfterCopies):     "trim-after-copies",
+	int(FinishingsTrimAfterJob):        "trim-after-job",
+	int(FinishingsPunchTopLeft):        "punch-top-left",
+	int(FinishingsPunchBottomLeft):     "punch-bottom-left",
+	int(FinishingsPunchTopRight):       "punch-top-right",
+	int(FinishingsPunchBottomRight):    "punch-bottom-right",
+	int(FinishingsPunchDualLeft):       "punch-dual-left",
+	int(FinishingsPunchDualTop):        "punch-dual-top",
+	int(FinishingsPunchDualRight):      "punch-dual-right",
+	int(FinishingsPunchDualBottom):     "punch-dual-bottom",
+	int(FinishingsPunchTripleLeft):     "punch-triple-left",
+	int(FinishingsPunchTripleTop):      "punch-triple-top",
+	int(FinishingsPunchTripleRight):    "punch-triple-right",
+	int(FinishingsPunchTripleBottom):   "punch-triple-bottom",
+	int(FinishingsPunchQuadLeft):       "punch-quad-left",
+	int(FinishingsPunchQuadTop):        "punch-quad-top",
+	int(FinishingsPunchQuadRight):      "punch-quad-right",
+	int(FinishingsPunchQuadBottom):     "punch-quad-bottom",
+	int(FinishingsPunchMultipleLeft):   "punch-multiple-left",
+	int(FinishingsPunchMultipleTop):    "punch-multiple-top",
+	int(FinishingsPunchMultipleRight):  "punch-multiple-right",
+	int(FinishingsPunchMultipleBottom): "punch-multiple-bottom",
+	int(FinishingsFoldAccordion):       "fold-accordion",
+	int(FinishingsFoldDoubleGate):      "fold-double-gate",
+	int(FinishingsFoldGate):            "fold-gate",
+	int(FinishingsFoldHalf):            "fold-half",
+	int(FinishingsFoldHalfZ):           "fold-half-z",
+	int(FinishingsFoldLeftGate):        "fold-left-gate",
+	int(FinishingsFoldLetter):          "fold-letter",
+	int(FinishingsFoldParallel):        "fold-parallel",
+	int(FinishingsFoldPoster):          "fold-poster",
+	int(FinishingsFoldRightGate):       "fold-right-gate",
+	int(FinishingsFoldZ):               "fold-z",
+	int(FinishingsFoldEngineeringZ):    "fold-engineering-z",
+}
+
+var orientationKeywords = map[int]string{
+	int(OrientationPortrait):         "portrait",
+	int(OrientationLandscape):        "landscape",
+	int(OrientationReverseLandscape): "reverse-landscape",
+	int(OrientationReversePortrait):  "reverse-portrait",
+	int(OrientationNone):             "none",
+}
+
+var printQualityKeywords = map[int]string{
+	int(PrintQualityDraft):  "draft",
+	int(PrintQualityNormal): "normal",
+	int(PrintQualityHigh):   "high",
+}
+
+// String returns the keyword of the printer state, e.g. idle
+func (s PrinterState) String() string {
+	return enumString(printerStateKeywords, int(s))
+}
+
+// ParsePrinterState parses a printer state keyword
+func ParsePrinterState(keyword string) (PrinterState, error) {
+	value, err := parseEnum(printerStateKeywords, keyword)
+	return PrinterState(value), err
+}
+
+// String returns the keyword of the job state, e.g. processing-stopped
+func (s JobState) String() string {
+	return enumString(jobStateKeywords, int(s))
+}
+
+// ParseJobState parses a job state keyword
+func ParseJobState(keyword string) (JobState, error) {
+	value, err := parseEnum(jobStateKeywords, keyword)
+	return JobState(value), err
+}
+
+// String returns the name of the operation, e.g. Print-Job. unknown operations are formatted in hex
+func (o Operation) String() string {
+	if name, ok := operationNames[int(o)]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("0x%04x", uint16(o))
+}
+
+// ParseOperation parses an operation name, the name is case insensitive
+func ParseOperation(name string) (Operation, error) {
+	value, err := parseEnum(operationNames, name)
+	return Operation(value), err
+}
+
+// String returns the keyword of the status code, e.g. client-error-not-found. unknown codes are formatted in hex
+func (s Status) String() string {
+	if keyword, ok := statusKeywords[int(s)]; ok {
+		return keyword
+	}
+
+	return fmt.Sprintf("0x%04x", uint16(s))
+}
+
+// ParseStatus parses a status code keyword
+func ParseStatus(keyword string) (Status, error) {
+	value, err := parseEnum(statusKeywords, keyword)
+	return Status(value), err
+}
+
+// String returns the keyword of the finishing, e.g. staple-top-left
+func (f Finishings) String() string {
+	return enumString(finishingsKeywords, int(f))
+}
+
+// ParseFinishings parses a finishings keyword
+func ParseFinishings(keyword string) (Finishings, error) {
+	value, err := parseEnum(finishingsKeywords, keyword)
+	return Finishings(value), err
+}
+
+// String returns the keyword of the orientation, e.g. landscape
+func (o Orientation) String() string {
+	return enumString(orientationKeywords, int(o))
+}
+
+// ParseOrientation parses an orientation keyword
+func ParseOrientation(keyword string) (Orientation, error) {
+	value, err := parseEnum(orientationKeywords, keyword)
+	return Orientation(value), err
+}
+
+// String returns the keyword of the print quality, e.g. high
+func (q PrintQuality) String() string {
+	return enumString(printQualityKeywords, int(q))
+}
+
+// ParsePrintQuality parses a print quality keyword
+func ParsePrintQuality(keyword string) (PrintQuality, error) {
+	value, err := parseEnum(printQualityKeywords, keyword)
+	return PrintQuality(value), err
+}
+
+// enumString returns the keyword of the value, unknown values are formatted as number
+func enumString(keywords map[int]string, value int) string {
+	if keyword, ok := keywords[value]; ok {
+		return keyword
+	}
+
+	return strconv.Itoa(value)
+}
+
+// parseEnum returns the value of the keyword, case insensitive
+func parseEnum(keywords map[int]string, keyword string) (int, error) {
+	for value, k := range keywords {
+		if strings.EqualFold(k, keyword) {
+			return value, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %s", EnumKeywordError, keyword)
+}
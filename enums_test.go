@@ -0,0 +1,100 @@
+package ipp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnumString(t *testing.T) {
+	tests := []struct {
+		value    interface{ String() string }
+		expected string
+	}{
+		{value: PrinterState(PrinterStateStopped), expected: "stopped"},
+		{value: JobState(JobStateStopped), expected: "processing-stopped"},
+		{value: JobState(42), expected: "42"},
+		{value: Operation(OperationGetPrinterAttributes), expected: "Get-Printer-Attributes"},
+		{value: Operation(OperationCupsGetPPDs), expected: "CUPS-Get-PPDs"},
+		{value: Operation(OperationGetUserPrinterAttributes), expected: "Get-User-Printer-Attributes"},
+		{value: Status(StatusErrorNotFetchable), expected: "client-error-not-fetchable"},
+		{value: Operation(0x3fff), expected: "0x3fff"},
+		{value: Status(StatusErrorNotFound), expected: "client-error-not-found"},
+		{value: Status(0x04ff), expected: "0x04ff"},
+		{value: Finishings(FinishingsNone), expected: "none"},
+		{value: Finishings(20), expected: "staple-top-left"},
+		{value: Orientation(OrientationReverseLandscape), expected: "reverse-landscape"},
+		{value: PrintQuality(PrintQualityHigh), expected: "high"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.value.String())
+	}
+}
+
+func TestParseEnum(t *testing.T) {
+	jobState, err := ParseJobState("pending-held")
+	assert.Nil(t, err)
+	assert.Equal(t, JobState(JobStateHeld), jobState)
+
+	printerState, err := ParsePrinterState("idle")
+	assert.Nil(t, err)
+	assert.Equal(t, PrinterState(PrinterStateIdle), printerState)
+
+	operation, err := ParseOperation("print-job")
+	assert.Nil(t, err)
+	assert.Equal(t, Operation(OperationPrintJob), operation)
+
+	status, err := ParseStatus("server-error-busy")
+	assert.Nil(t, err)
+	assert.Equal(t, Status(StatusErrorBusy), status)
+
+	finishings, err := ParseFinishings("punch-dual-left")
+	assert.Nil(t, err)
+	assert.Equal(t, Finishings(74), finishings)
+
+	orientation, err := ParseOrientation("landscape")
+	assert.Nil(t, err)
+	assert.Equal(t, Orientation(OrientationLandscape), orientation)
+
+	quality, err := ParsePrintQuality("draft")
+	assert.Nil(t, err)
+	assert.Equal(t, PrintQuality(PrintQualityDraft), quality)
+
+	_, err = ParseJobState("printing")
+	assert.True(t, errors.Is(err, EnumKeywordError))
+}
+
+// the status codes after client-error-conflicting-attributes were one less before
+// client-error-compression-not-supported was generated from the iana registry
+func TestStatusCodeValues(t *testing.T) {
+	tests := []struct {
+		status   int16
+		expected int16
+	}{
+		{status: StatusErrorConflicting, expected: 0x040e},
+		{status: StatusErrorCompressionNotSupported, expected: 0x040f},
+		{status: StatusErrorCompressionError, expected: 0x0410},
+		{status: StatusErrorDocumentFormatError, expected: 0x0411},
+		{status: StatusErrorDocumentAccess, expected: 0x0412},
+		{status: StatusErrorAttributesNotSettable, expected: 0x0413},
+		{status: StatusErrorIgnoredAllSubscriptions, expected: 0x0414},
+		{status: StatusErrorTooManySubscriptions, expected: 0x0415},
+		{status: StatusErrorIgnoredAllNotifications, expected: 0x0416},
+		{status: StatusErrorPrintSupportFileNotFound, expected: 0x0417},
+		{status: StatusErrorDocumentPassword, expected: 0x0418},
+		{status: StatusErrorDocumentPermission, expected: 0x0419},
+		{status: StatusErrorDocumentSecurity, expected: 0x041a},
+		{status: StatusErrorDocumentUnprintable, expected: 0x041b},
+		{status: StatusErrorAccountInfoNeeded, expected: 0x041c},
+		{status: StatusErrorAccountClosed, expected: 0x041d},
+		{status: StatusErrorAccountLimitReached, expected: 0x041e},
+		{status: StatusErrorAccountAuthorizationFailed, expected: 0x041f},
+		{status: StatusErrorNotFetchable, expected: 0x0420},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.status, Status(test.status).String())
+	}
+}
@@ -1,6 +1,56 @@
 package ipp
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// sentinel errors to test a StatusError or HTTPError with errors.Is
+var (
+	ClientError       = errors.New("ipp client error")
+	ServerError       = errors.New("ipp server error")
+	AuthRequiredError = errors.New("ipp authentication required")
+	NotFoundError     = errors.New("ipp object not found")
+	// OperationNotSupportedError is returned by the client if the printer doesn't advertise an operation in
+	// operations-supported, it also matches the server-error-operation-not-supported status
+	OperationNotSupportedError = errors.New("ipp operation not supported")
+)
+
+// RequestIDMismatchError is returned by the client if the request-id of a response doesn't match the request
+var RequestIDMismatchError = errors.New("ipp request id mismatch")
+
+// MissingTargetError is returned by Request.Validate and the client if the attributes which address the printer or
+// job of the operation are missing
+var MissingTargetError = errors.New("ipp request target missing")
+
+// sentinel errors for malformed ipp messages, the encoders and decoders wrap them with the details
+var (
+	// ShortReadError is returned if a message ends within the header or within an attribute
+	ShortReadError = errors.New("ipp message truncated")
+	// InvalidTagError is returned for tags which can't be encoded or decoded, e.g. a tag not matching the value
+	// type or a delimiter tag in place of a value
+	InvalidTagError = errors.New("invalid ipp tag")
+	// UnexpectedGroupError is returned for attributes outside of a group and for groups which are not allowed in
+	// the message, e.g. a printer group in a response of an unknown kind
+	UnexpectedGroupError = errors.New("unexpected ipp attribute group")
+	// ConformanceError is returned by the decoders with the strict profile for messages which deviate from rfc 8011,
+	// the deviations the lenient profile reports as Warning
+	ConformanceError = errors.New("ipp message doesn't conform to rfc 8011")
+	// ReadTimeoutError is returned by DecodeContext if the context is done or its deadline passes before the
+	// message is read, it wraps the error of the context or os.ErrDeadlineExceeded
+	ReadTimeoutError = errors.New("ipp message read timed out")
+)
+
+// readError wraps the end of file errors of a truncated message in ShortReadError
+func readError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %w", ShortReadError, err)
+	}
+
+	return err
+}
 
 // IsNotExistsError checks a given error whether a printer or class does not exist
 func IsNotExistsError(err error) bool {
@@ -11,14 +61,68 @@ func IsNotExistsError(err error) bool {
 	return err.Error() == "The printer or class does not exist."
 }
 
-// IPPError used for non ok ipp status codes
-type IPPError struct {
-	Status  int16
+// IsClientError checks whether the error is caused by a client-error status code
+func IsClientError(err error) bool {
+	return errors.Is(err, ClientError)
+}
+
+// IsServerError checks whether the error is caused by a server-error status code
+func IsServerError(err error) bool {
+	return errors.Is(err, ServerError)
+}
+
+// IsAuthRequired checks whether the request must be authenticated, either by the client-error-not-authenticated
+// status or by the http status unauthorized
+func IsAuthRequired(err error) bool {
+	return errors.Is(err, AuthRequiredError)
+}
+
+// IsNotFound checks whether the target of the request, e.g. the printer or the job, does not exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, NotFoundError)
+}
+
+// StatusError is used for non ok ipp status codes. errors.Is reports true for a StatusError with the same status
+// and for the sentinels ClientError, ServerError, AuthRequiredError, NotFoundError and OperationNotSupportedError
+type StatusError struct {
+	Status int16
+	// Message is the status-message of the response
 	Message string
+	// DetailedMessage is the detailed-status-message of the response
+	DetailedMessage string
+	// Response is the decoded response, it is set by the IPPClient
+	Response *Response
 }
 
-func (e IPPError) Error() string {
-	return fmt.Sprintf("ipp status: %d, message: %s", e.Status, e.Message)
+// IPPError is the former name of StatusError.
+//
+// Deprecated: use StatusError
+type IPPError = StatusError
+
+func (e StatusError) Error() string {
+	return fmt.Sprintf("ipp status: %s, message: %s", Status(e.Status), e.Message)
+}
+
+// Is matches the status code against the sentinel errors or another StatusError
+func (e StatusError) Is(target error) bool {
+	switch target {
+	case ClientError:
+		return e.Status >= 0x0400 && e.Status < 0x0500
+	case ServerError:
+		return e.Status >= 0x0500 && e.Status < 0x0600
+	case AuthRequiredError:
+		return e.Status == StatusErrorNotAuthenticated
+	case NotFoundError:
+		return e.Status == StatusErrorNotFound
+	case OperationNotSupportedError:
+		return e.Status == StatusErrorOperationNotSupported
+	}
+
+	if t, ok := target.(StatusError); ok {
+		return t.Status == e.Status
+	}
+
+	return false
 }
 
 // HTTPError used for non 200 http codes
@@ -29,3 +133,15 @@ type HTTPError struct {
 func (e HTTPError) Error() string {
 	return fmt.Sprintf("got http code %d", e.Code)
 }
+
+// Is matches the http status unauthorized against AuthRequiredError and not found against NotFoundError
+func (e HTTPError) Is(target error) bool {
+	switch target {
+	case AuthRequiredError:
+		return e.Code == http.StatusUnauthorized
+	case NotFoundError:
+		return e.Code == http.StatusNotFound
+	}
+
+	return false
+}
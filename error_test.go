@@ -0,0 +1,98 @@
+package ipp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		err          error
+		clientError  bool
+		serverError  bool
+		authRequired bool
+		notFound     bool
+	}{
+		{err: StatusError{Status: StatusErrorNotFound}, clientError: true, notFound: true},
+		{err: StatusError{Status: StatusErrorNotAuthenticated}, clientError: true, authRequired: true},
+		{err: fmt.Errorf("get jobs: %w", StatusError{Status: StatusErrorBadRequest}), clientError: true},
+		{err: StatusError{Status: StatusErrorBusy}, serverError: true},
+		{err: HTTPError{Code: http.StatusUnauthorized}, authRequired: true},
+		{err: HTTPError{Code: http.StatusNotFound}, notFound: true},
+		{err: errors.New("connection refused")},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.clientError, IsClientError(test.err), test.err)
+		assert.Equal(t, test.serverError, IsServerError(test.err), test.err)
+		assert.Equal(t, test.authRequired, IsAuthRequired(test.err), test.err)
+		assert.Equal(t, test.notFound, IsNotFound(test.err), test.err)
+	}
+
+	err := StatusError{Status: StatusErrorNotFound, Message: "printer not found"}
+	assert.True(t, errors.Is(err, StatusError{Status: StatusErrorNotFound}))
+	assert.False(t, errors.Is(err, StatusError{Status: StatusErrorGone}))
+	assert.Equal(t, "ipp status: client-error-not-found, message: printer not found", err.Error())
+}
+
+func TestResponse_CheckForErrors(t *testing.T) {
+	resp := NewResponse(StatusErrorNotAuthorized, 1)
+	resp.OperationAttributes[AttributeStatusMessage] = []Attribute{{Value: "not allowed"}}
+	resp.OperationAttributes[AttributeDetailedStatusMessage] = []Attribute{{Value: "user alice is not allowed"}}
+
+	var statusErr StatusError
+	assert.True(t, errors.As(resp.CheckForErrors(), &statusErr))
+	assert.Equal(t, StatusError{Status: StatusErrorNotAuthorized, Message: "not allowed",
+		DetailedMessage: "user alice is not allowed"}, statusErr)
+
+	assert.Nil(t, NewResponse(StatusOk, 1).CheckForErrors())
+}
+
+func TestDecodeErrors(t *testing.T) {
+	resp := NewResponse(StatusOk, 1)
+	resp.PrinterAttributes = []Attributes{{AttributePrinterName: {{Tag: TagName, Name: AttributePrinterName, Value: "office"}}}}
+	data, err := resp.Encode()
+	assert.Nil(t, err)
+
+	header := []byte{2, 0, 0, 0, 0, 0, 0, 1}
+	// a media-col collection with the member name size followed by a job group delimiter
+	collection := []byte{0x01, 0x34, 0, 9, 'm', 'e', 'd', 'i', 'a', '-', 'c', 'o', 'l', 0, 0,
+		0x4a, 0, 0, 0, 4, 's', 'i', 'z', 'e', 0x02}
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{name: "short header", data: data[:5], err: ShortReadError},
+		{name: "short value", data: data[:len(data)-3], err: ShortReadError},
+		{name: "attribute outside of a group", data: append(header, data[9:]...), err: UnexpectedGroupError},
+		{name: "reserved group", data: append(append(header, 0x0f), data[9:]...), err: UnexpectedGroupError},
+		{name: "delimiter in collection", data: append(header, collection...), err: InvalidTagError},
+	}
+
+	for _, test := range tests {
+		_, err := NewResponseDecoder(bytes.NewReader(test.data)).Decode(nil)
+		assert.ErrorIs(t, err, test.err, test.name)
+
+		_, err = NewRequestDecoder(bytes.NewReader(test.data)).Decode(nil)
+		assert.ErrorIs(t, err, test.err, test.name)
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	req := NewRequest(OperationPrintJob, 1)
+	req.JobAttributes[AttributeCopies] = true
+	_, err := req.Encode()
+	assert.ErrorIs(t, err, InvalidTagError)
+
+	req = NewRequest(OperationPrintJob, 1)
+	req.JobAttributes["x-unknown"] = 1
+	_, err = req.Encode()
+	assert.ErrorIs(t, err, InvalidTagError)
+}
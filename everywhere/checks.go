@@ -0,0 +1,408 @@
+package everywhere
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/discovery"
+)
+
+// AttributeSyntax is a printer description attribute and its syntax. syntax names are separated by | like the
+// OF-TYPE predicate of ipptool, name and text match the syntaxes with and without language
+type AttributeSyntax struct {
+	Name   string
+	Syntax string
+}
+
+// RequiredAttributes are the printer description attributes required by ipp everywhere
+var RequiredAttributes = []AttributeSyntax{
+	{ipp.AttributeCharsetConfigured, "charset"},
+	{ipp.AttributeCharsetSupported, "charset"},
+	{ipp.AttributeColorSupported, "boolean"},
+	{ipp.AttributeCompressionSupported, "keyword"},
+	{ipp.AttributeCopiesDefault, "integer"},
+	{ipp.AttributeCopiesSupported, "rangeOfInteger"},
+	{ipp.AttributeDocumentFormatDefault, "mimeMediaType"},
+	{ipp.AttributeDocumentFormatSupported, "mimeMediaType"},
+	{ipp.AttributeFinishingsDefault, "enum"},
+	{ipp.AttributeFinishingsSupported, "enum"},
+	{ipp.AttributeGeneratedNaturalLanguageSupported, "naturalLanguage"},
+	{ipp.AttributeIdentifyActionsDefault, "keyword"},
+	{ipp.AttributeIdentifyActionsSupported, "keyword"},
+	{ipp.AttributeIppFeaturesSupported, "keyword"},
+	{ipp.AttributeIppVersionsSupported, "keyword"},
+	{ipp.AttributeJobCreationAttributesSupported, "keyword"},
+	{ipp.AttributeJobIdsSupported, "boolean"},
+	{ipp.AttributeMediaBottomMarginSupported, "integer"},
+	{ipp.AttributeMediaColDatabase, "collection"},
+	{ipp.AttributeMediaColDefault, "collection"},
+	{ipp.AttributeMediaColReady, "collection"},
+	{ipp.AttributeMediaColSupported, "keyword"},
+	{ipp.AttributeMediaDefault, "keyword|name"},
+	{ipp.AttributeMediaLeftMarginSupported, "integer"},
+	{ipp.AttributeMediaReady, "keyword|name"},
+	{ipp.AttributeMediaRightMarginSupported, "integer"},
+	{ipp.AttributeMediaSizeSupported, "collection"},
+	{ipp.AttributeMediaSourceSupported, "keyword|name"},
+	{ipp.AttributeMediaSupported, "keyword|name"},
+	{ipp.AttributeMediaTopMarginSupported, "integer"},
+	{ipp.AttributeMediaTypeSupported, "keyword|name"},
+	{ipp.AttributeMultipleDocumentJobsSupported, "boolean"},
+	{ipp.AttributeMultipleOperationTimeOut, "integer"},
+	{ipp.AttributeNaturalLanguageConfigured, "naturalLanguage"},
+	{ipp.AttributeOperationsSupported, "enum"},
+	{ipp.AttributeOrientationRequestedDefault, "enum|no-value"},
+	{ipp.AttributeOrientationRequestedSupported, "enum"},
+	{ipp.AttributeOutputBinDefault, "keyword|name"},
+	{ipp.AttributeOutputBinSupported, "keyword|name"},
+	{ipp.AttributePdlOverrideSupported, "keyword"},
+	{ipp.AttributePrintColorModeDefault, "keyword"},
+	{ipp.AttributePrintColorModeSupported, "keyword"},
+	{ipp.AttributePrintQualityDefault, "enum"},
+	{ipp.AttributePrintQualitySupported, "enum"},
+	{ipp.AttributePrinterDeviceID, "text"},
+	{ipp.AttributePrinterGeoLocation, "uri|unknown"},
+	{ipp.AttributePrinterInfo, "text"},
+	{ipp.AttributePrinterIsAcceptingJobs, "boolean"},
+	{ipp.AttributePrinterLocation, "text"},
+	{ipp.AttributePrinterMakeAndModel, "text"},
+	{ipp.AttributePrinterMoreInfo, "uri"},
+	{ipp.AttributePrinterName, "name"},
+	{ipp.AttributePrinterOrganization, "text"},
+	{ipp.AttributePrinterOrganizationalUnit, "text"},
+	{ipp.AttributePrinterResolutionDefault, "resolution"},
+	{ipp.AttributePrinterResolutionSupported, "resolution"},
+	{ipp.AttributePrinterState, "enum"},
+	{ipp.AttributePrinterStateReasons, "keyword"},
+	{ipp.AttributePrinterUpTime, "integer"},
+	{ipp.AttributePrinterUriSupported, "uri"},
+	{ipp.AttributePrinterUUID, "uri"},
+	{ipp.AttributePwgRasterDocumentResolutionSupported, "resolution"},
+	{ipp.AttributePwgRasterDocumentSheetBack, "keyword"},
+	{ipp.AttributePwgRasterDocumentTypeSupported, "keyword"},
+	{ipp.AttributeSidesDefault, "keyword"},
+	{ipp.AttributeSidesSupported, "keyword"},
+	{ipp.AttributeUriAuthenticationSupported, "keyword"},
+	{ipp.AttributeUriSecuritySupported, "keyword"},
+	{ipp.AttributeWhichJobsSupported, "keyword"},
+}
+
+// RecommendedAttributes are the printer description attributes recommended by ipp everywhere, missing attributes are
+// reported as warnings
+var RecommendedAttributes = []AttributeSyntax{
+	{ipp.AttributePrintScalingDefault, "keyword"},
+	{ipp.AttributePrintScalingSupported, "keyword"},
+	{ipp.AttributePrinterIcons, "uri"},
+	{ipp.AttributePrinterKind, "keyword"},
+	{ipp.AttributeUrfSupported, "keyword"},
+}
+
+// RequiredOperations are the operations required by ipp everywhere
+var RequiredOperations = []ipp.Operation{
+	ipp.Operation(ipp.OperationPrintJob),
+	ipp.Operation(ipp.OperationValidateJob),
+	ipp.Operation(ipp.OperationCreateJob),
+	ipp.Operation(ipp.OperationSendDocument),
+	ipp.Operation(ipp.OperationCancelJob),
+	ipp.Operation(ipp.OperationGetJobAttributes),
+	ipp.Operation(ipp.OperationGetJobs),
+	ipp.Operation(ipp.OperationGetPrinterAttributes),
+	ipp.Operation(ipp.OperationCancelMyJobs),
+	ipp.Operation(ipp.OperationCloseJob),
+	ipp.Operation(ipp.OperationIdentifyPrinter),
+}
+
+// document formats required and recommended by ipp everywhere
+var (
+	RequiredDocumentFormats    = []string{ipp.MimeTypePwgRaster, "image/jpeg"}
+	RecommendedDocumentFormats = []string{ipp.MimeTypePDF}
+)
+
+// checkList collects the checks of a category
+type checkList struct {
+	category string
+	checks   []Check
+}
+
+func (l *checkList) add(name string, result Result, format string, args ...interface{}) {
+	l.checks = append(l.checks, Check{Category: l.category, Name: name, Result: result, Message: fmt.Sprintf(format, args...)})
+}
+
+// CheckAttributes checks the presence and syntax of the required and recommended printer attributes and the values
+// of the attributes ipp everywhere puts requirements on, e.g. ipp-versions-supported must contain 2.0
+func CheckAttributes(attributes ipp.Attributes) []Check {
+	l := &checkList{category: CategoryAttributes}
+
+	for i, attrs := range [][]AttributeSyntax{RequiredAttributes, RecommendedAttributes} {
+		missing := Fail
+		if i > 0 {
+			missing = Warn
+		}
+
+		for _, a := range attrs {
+			values := attributes[a.Name]
+			if len(values) == 0 {
+				l.add(a.Name, missing, "missing")
+				continue
+			}
+
+			if syntax, ok := wrongSyntax(values, a.Syntax); ok {
+				l.add(a.Name, Fail, "expected %s, got %s", a.Syntax, syntax)
+				continue
+			}
+
+			if msg := checkValues(attributes, a.Name); msg != "" {
+				l.add(a.Name, Fail, "%s", msg)
+				continue
+			}
+
+			l.add(a.Name, Pass, "")
+		}
+	}
+
+	return l.checks
+}
+
+// wrongSyntax returns the syntax of the first value which is not one of the syntax names
+func wrongSyntax(values []ipp.Attribute, syntax string) (string, bool) {
+	for _, v := range values {
+		name := ipp.TagSyntax(v.Tag)
+		ok := false
+		for _, s := range strings.Split(syntax, "|") {
+			switch {
+			case s == name:
+			case s == "name" && (v.Tag == ipp.TagName || v.Tag == ipp.TagNameLang):
+			case s == "text" && (v.Tag == ipp.TagText || v.Tag == ipp.TagTextLang):
+			default:
+				continue
+			}
+			ok = true
+		}
+		if !ok {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// checkValues checks the values of the attributes ipp everywhere puts requirements on, the problem is returned
+func checkValues(attributes ipp.Attributes, name string) string {
+	values := stringValues(attributes[name])
+
+	switch name {
+	case ipp.AttributeIppVersionsSupported:
+		if !contains(values, "2.0") {
+			return "2.0 is not supported"
+		}
+	case ipp.AttributeIppFeaturesSupported:
+		if !contains(values, "ipp-everywhere") {
+			return "ipp-everywhere is not listed"
+		}
+	case ipp.AttributePrinterUUID:
+		if len(values) == 0 || !strings.HasPrefix(values[0], "urn:uuid:") {
+			return "not a urn:uuid uri"
+		}
+	case ipp.AttributePwgRasterDocumentTypeSupported:
+		if !contains(values, "sgray_8") {
+			return "sgray_8 is not supported"
+		}
+		if color := attributes[ipp.AttributeColorSupported]; len(color) > 0 && color[0].Value == true &&
+			!contains(values, "srgb_8") {
+			return "srgb_8 is not supported by a color printer"
+		}
+	case ipp.AttributeMediaColDatabase:
+		for i, v := range attributes[name] {
+			col, _ := v.Value.(ipp.Attributes)
+			size := col[ipp.AttributeMediaSize]
+			if len(size) == 0 {
+				return fmt.Sprintf("value %d has no media-size", i+1)
+			}
+			dimensions, _ := size[0].Value.(ipp.Attributes)
+			if len(dimensions["x-dimension"]) == 0 || len(dimensions["y-dimension"]) == 0 {
+				return fmt.Sprintf("media-size of value %d has no x-dimension or y-dimension", i+1)
+			}
+		}
+	}
+
+	return ""
+}
+
+// CheckOperations checks that the required operations are listed in operations-supported
+func CheckOperations(attributes ipp.Attributes) []Check {
+	l := &checkList{category: CategoryOperations}
+
+	supported := make(map[int]bool)
+	for _, v := range attributes[ipp.AttributeOperationsSupported] {
+		if op, ok := v.Value.(int); ok {
+			supported[op] = true
+		}
+	}
+
+	for _, op := range RequiredOperations {
+		if supported[int(op)] {
+			l.add(op.String(), Pass, "")
+		} else {
+			l.add(op.String(), Fail, "not supported")
+		}
+	}
+
+	return l.checks
+}
+
+// CheckDocumentFormats checks that the required and recommended document formats are listed in
+// document-format-supported and that document-format-default is one of them
+func CheckDocumentFormats(attributes ipp.Attributes) []Check {
+	l := &checkList{category: CategoryDocumentFormats}
+	supported := stringValues(attributes[ipp.AttributeDocumentFormatSupported])
+
+	for i, formats := range [][]string{RequiredDocumentFormats, RecommendedDocumentFormats} {
+		missing := Fail
+		if i > 0 {
+			missing = Warn
+		}
+
+		for _, format := range formats {
+			if contains(supported, format) {
+				l.add(format, Pass, "")
+			} else {
+				l.add(format, missing, "not supported")
+			}
+		}
+	}
+
+	if def := stringValues(attributes[ipp.AttributeDocumentFormatDefault]); len(def) > 0 && !contains(supported, def[0]) {
+		l.add(ipp.AttributeDocumentFormatDefault, Fail, "%s is not supported", def[0])
+	}
+
+	return l.checks
+}
+
+// required and recommended keys of the ipp everywhere txt record
+var (
+	requiredTXTKeys    = []string{"txtvers", "rp", "ty", "pdl", "UUID", "URF"}
+	recommendedTXTKeys = []string{"adminurl", "Color", "Duplex", "kind", "note", "PaperMax"}
+)
+
+// CheckTXTRecord checks the dns-sd txt record of the printer: the required and recommended keys must be present and
+// match the printer attributes. the checks are skipped if the printer has no txt record, e.g. a printer of
+// discovery.ParseURI
+func CheckTXTRecord(printer discovery.Printer, attributes ipp.Attributes) []Check {
+	l := &checkList{category: CategoryDNSSD}
+
+	if len(printer.Text) == 0 {
+		l.add("txt record", Skip, "the printer has no txt record")
+		return l.checks
+	}
+
+	// the keys of browsed services are in lower case
+	text := make(map[string]string, len(printer.Text))
+	for key, value := range printer.Text {
+		text[strings.ToLower(key)] = value
+	}
+
+	for i, keys := range [][]string{requiredTXTKeys, recommendedTXTKeys} {
+		missing := Fail
+		if i > 0 {
+			missing = Warn
+		}
+
+		for _, key := range keys {
+			value, ok := text[strings.ToLower(key)]
+			switch {
+			case !ok:
+				l.add(key, missing, "missing")
+			default:
+				if msg := checkTXTValue(key, value, attributes); msg != "" {
+					l.add(key, Fail, "%s", msg)
+				} else {
+					l.add(key, Pass, "")
+				}
+			}
+		}
+	}
+
+	if printer.Secure {
+		if _, ok := text["tls"]; ok {
+			l.add("TLS", Pass, "")
+		} else {
+			l.add("TLS", Warn, "missing for an ipps printer")
+		}
+	}
+
+	return l.checks
+}
+
+// checkTXTValue compares the value of a txt key with the printer attributes, the problem is returned
+func checkTXTValue(key, value string, attributes ipp.Attributes) string {
+	switch key {
+	case "txtvers":
+		if value != "1" {
+			return fmt.Sprintf("expected 1, got %s", value)
+		}
+	case "pdl":
+		supported := stringValues(attributes[ipp.AttributeDocumentFormatSupported])
+		formats := strings.Split(value, ",")
+		for _, format := range formats {
+			if !contains(supported, strings.TrimSpace(format)) {
+				return fmt.Sprintf("%s is not in document-format-supported", format)
+			}
+		}
+		if !contains(formats, ipp.MimeTypePwgRaster) {
+			return ipp.MimeTypePwgRaster + " is missing"
+		}
+	case "UUID":
+		uuid := stringValues(attributes[ipp.AttributePrinterUUID])
+		if len(uuid) > 0 && !strings.EqualFold("urn:uuid:"+value, uuid[0]) {
+			return fmt.Sprintf("%s does not match printer-uuid %s", value, uuid[0])
+		}
+	case "rp":
+		uris := stringValues(attributes[ipp.AttributePrinterUriSupported])
+		for _, uri := range uris {
+			if u, err := url.Parse(uri); err == nil && strings.Trim(u.Path, "/") == strings.Trim(value, "/") {
+				return ""
+			}
+		}
+		if len(uris) > 0 {
+			return fmt.Sprintf("%s does not match printer-uri-supported", value)
+		}
+	case "Color":
+		color := attributes[ipp.AttributeColorSupported]
+		if len(color) > 0 && (value == "T") != (color[0].Value == true) {
+			return fmt.Sprintf("%s does not match color-supported", value)
+		}
+	case "Duplex":
+		duplex := false
+		for _, sides := range stringValues(attributes[ipp.AttributeSidesSupported]) {
+			duplex = duplex || strings.HasPrefix(sides, "two-sided")
+		}
+		if (value == "T") != duplex {
+			return fmt.Sprintf("%s does not match sides-supported", value)
+		}
+	}
+
+	return ""
+}
+
+func stringValues(values []ipp.Attribute) []string {
+	s := make([]string, 0, len(values))
+	for _, v := range values {
+		if str, ok := v.Value.(string); ok {
+			s = append(s, str)
+		}
+	}
+
+	return s
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,171 @@
+package everywhere
+
+import (
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/discovery"
+	"github.com/stretchr/testify/assert"
+)
+
+func find(checks []Check, name string) Check {
+	for _, c := range checks {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	return Check{}
+}
+
+func TestCheckAttributes(t *testing.T) {
+	attributes := ipp.Attributes{
+		ipp.AttributeColorSupported:                 {{Tag: ipp.TagBoolean, Value: true}},
+		ipp.AttributeCopiesDefault:                  {{Tag: ipp.TagKeyword, Value: "1"}},
+		ipp.AttributeIppVersionsSupported:           {{Tag: ipp.TagKeyword, Value: "1.1"}},
+		ipp.AttributeIppFeaturesSupported:           {{Tag: ipp.TagKeyword, Value: "ipp-everywhere"}},
+		ipp.AttributePrinterName:                    {{Tag: ipp.TagNameLang, Value: "test"}},
+		ipp.AttributePrinterUUID:                    {{Tag: ipp.TagUri, Value: "urn:uuid:3e6f0a2c-1d4b-4f8e-9c1a-5b7d2e8f6a01"}},
+		ipp.AttributePwgRasterDocumentTypeSupported: {{Tag: ipp.TagKeyword, Value: "sgray_8"}},
+		ipp.AttributeMediaColDatabase: {{Tag: ipp.TagBeginCollection, Value: ipp.Attributes{
+			ipp.AttributeMediaSize: {{Tag: ipp.TagBeginCollection, Value: ipp.Attributes{
+				"x-dimension": {{Tag: ipp.TagInteger, Value: 21000}},
+			}}},
+		}}},
+		ipp.AttributePrinterKind: {{Tag: ipp.TagKeyword, Value: "document"}},
+	}
+
+	checks := CheckAttributes(attributes)
+	assert.Len(t, checks, len(RequiredAttributes)+len(RecommendedAttributes))
+
+	tests := []struct {
+		name   string
+		result Result
+		msg    string
+	}{
+		{ipp.AttributeColorSupported, Pass, ""},
+		{ipp.AttributeCopiesDefault, Fail, "expected integer, got keyword"},
+		{ipp.AttributeIppVersionsSupported, Fail, "2.0 is not supported"},
+		{ipp.AttributeIppFeaturesSupported, Pass, ""},
+		{ipp.AttributePrinterName, Pass, ""},
+		{ipp.AttributePrinterUUID, Pass, ""},
+		{ipp.AttributePwgRasterDocumentTypeSupported, Fail, "srgb_8 is not supported by a color printer"},
+		{ipp.AttributeMediaColDatabase, Fail, "media-size of value 1 has no x-dimension or y-dimension"},
+		{ipp.AttributeCharsetConfigured, Fail, "missing"},
+		{ipp.AttributePrinterKind, Pass, ""},
+		{ipp.AttributePrinterIcons, Warn, "missing"},
+	}
+
+	for _, test := range tests {
+		c := find(checks, test.name)
+		assert.Equal(t, CategoryAttributes, c.Category, test.name)
+		assert.Equal(t, test.result, c.Result, test.name)
+		assert.Equal(t, test.msg, c.Message, test.name)
+	}
+}
+
+func TestCheckOperations(t *testing.T) {
+	var operations []ipp.Attribute
+	for _, op := range RequiredOperations[1:] {
+		operations = append(operations, ipp.Attribute{Tag: ipp.TagEnum, Value: int(op)})
+	}
+
+	checks := CheckOperations(ipp.Attributes{ipp.AttributeOperationsSupported: operations})
+	assert.Len(t, checks, len(RequiredOperations))
+	assert.Equal(t, Check{Category: CategoryOperations, Name: "Print-Job", Result: Fail, Message: "not supported"},
+		checks[0])
+	assert.Equal(t, Pass, find(checks, "Close-Job").Result)
+}
+
+func TestCheckDocumentFormats(t *testing.T) {
+	checks := CheckDocumentFormats(ipp.Attributes{
+		ipp.AttributeDocumentFormatSupported: {
+			{Tag: ipp.TagMimeType, Value: ipp.MimeTypePwgRaster},
+			{Tag: ipp.TagMimeType, Value: "image/jpeg"},
+		},
+		ipp.AttributeDocumentFormatDefault: {{Tag: ipp.TagMimeType, Value: ipp.MimeTypeOctetStream}},
+	})
+
+	assert.Equal(t, []Check{
+		{Category: CategoryDocumentFormats, Name: ipp.MimeTypePwgRaster, Result: Pass},
+		{Category: CategoryDocumentFormats, Name: "image/jpeg", Result: Pass},
+		{Category: CategoryDocumentFormats, Name: ipp.MimeTypePDF, Result: Warn, Message: "not supported"},
+		{Category: CategoryDocumentFormats, Name: ipp.AttributeDocumentFormatDefault, Result: Fail,
+			Message: ipp.MimeTypeOctetStream + " is not supported"},
+	}, checks)
+}
+
+func TestCheckTXTRecord(t *testing.T) {
+	attributes := ipp.Attributes{
+		ipp.AttributeDocumentFormatSupported: {
+			{Tag: ipp.TagMimeType, Value: ipp.MimeTypePwgRaster},
+			{Tag: ipp.TagMimeType, Value: "image/jpeg"},
+		},
+		ipp.AttributePrinterUUID:         {{Tag: ipp.TagUri, Value: "urn:uuid:3e6f0a2c-1d4b-4f8e-9c1a-5b7d2e8f6a01"}},
+		ipp.AttributePrinterUriSupported: {{Tag: ipp.TagUri, Value: "ipps://printer.local/ipp/print"}},
+		ipp.AttributeColorSupported:      {{Tag: ipp.TagBoolean, Value: false}},
+		ipp.AttributeSidesSupported: {
+			{Tag: ipp.TagKeyword, Value: "one-sided"},
+			{Tag: ipp.TagKeyword, Value: "two-sided-long-edge"},
+		},
+	}
+
+	printer := discovery.Printer{Secure: true, Text: map[string]string{
+		"txtvers": "1",
+		"rp":      "ipp/print",
+		"ty":      "Test Printer",
+		"pdl":     "image/jpeg,application/pdf",
+		"uuid":    "3E6F0A2C-1D4B-4F8E-9C1A-5B7D2E8F6A01",
+		"color":   "T",
+		"duplex":  "T",
+	}}
+	checks := CheckTXTRecord(printer, attributes)
+
+	tests := []struct {
+		name   string
+		result Result
+		msg    string
+	}{
+		{"txtvers", Pass, ""},
+		{"rp", Pass, ""},
+		{"pdl", Fail, "application/pdf is not in document-format-supported"},
+		{"UUID", Pass, ""},
+		{"URF", Fail, "missing"},
+		{"Color", Fail, "T does not match color-supported"},
+		{"Duplex", Pass, ""},
+		{"adminurl", Warn, "missing"},
+		{"TLS", Warn, "missing for an ipps printer"},
+	}
+
+	for _, test := range tests {
+		c := find(checks, test.name)
+		assert.Equal(t, CategoryDNSSD, c.Category, test.name)
+		assert.Equal(t, test.result, c.Result, test.name)
+		assert.Equal(t, test.msg, c.Message, test.name)
+	}
+
+	checks = CheckTXTRecord(discovery.Printer{}, attributes)
+	assert.Equal(t, []Check{{Category: CategoryDNSSD, Name: "txt record", Result: Skip,
+		Message: "the printer has no txt record"}}, checks)
+}
+
+func TestReport(t *testing.T) {
+	report := &Report{URI: "ipp://printer.local/ipp/print", Checks: []Check{
+		{Category: CategoryAttributes, Name: ipp.AttributePrinterName, Result: Pass},
+		{Category: CategoryAttributes, Name: ipp.AttributePrinterIcons, Result: Warn, Message: "missing"},
+		{Category: CategoryOperations, Name: "Close-Job", Result: Fail, Message: "not supported"},
+	}}
+
+	assert.False(t, report.Passed())
+	assert.Len(t, report.Failures(), 1)
+	assert.Len(t, report.Warnings(), 1)
+	assert.Equal(t, `PASS attributes: printer-name
+WARN attributes: printer-icons (missing)
+FAIL operations: Close-Job (not supported)
+ipp://printer.local/ipp/print: 1 passed, 1 warnings, 1 failed, 0 skipped
+`, report.String())
+
+	report.Checks = report.Checks[:2]
+	assert.True(t, report.Passed())
+	assert.Equal(t, "Result(7)", Result(7).String())
+}
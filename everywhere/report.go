@@ -0,0 +1,121 @@
+// Package everywhere checks printers against the requirements of the ipp everywhere self-certification (pwg
+// 5100.14): the required printer attributes and their syntax, the required operations, the required document
+// formats and the dns-sd txt record. the checks produce a report of passed and failed checks, they run against real
+// printers as well as the printers of the server sub-package
+package everywhere
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Result is the outcome of a check
+type Result int
+
+const (
+	Pass Result = iota
+	// Warn is the result of failed checks for recommended features
+	Warn
+	Fail
+	// Skip is the result of checks which could not run, e.g. the dns-sd checks of printers without txt record
+	Skip
+)
+
+var resultNames = map[Result]string{
+	Pass: "PASS",
+	Warn: "WARN",
+	Fail: "FAIL",
+	Skip: "SKIP",
+}
+
+// String returns the result in upper case, e.g. PASS
+func (r Result) String() string {
+	if name, ok := resultNames[r]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Result(%d)", int(r))
+}
+
+// check categories
+const (
+	CategoryAttributes      = "attributes"
+	CategoryOperations      = "operations"
+	CategoryDocumentFormats = "document-formats"
+	CategoryDNSSD           = "dns-sd"
+)
+
+// Check is a single check of a report
+type Check struct {
+	// Category is one of the Category constants
+	Category string
+	// Name is the checked attribute, operation, document format or txt key
+	Name   string
+	Result Result
+	// Message explains failed and skipped checks
+	Message string
+}
+
+// String returns the check in the format of the report, e.g. FAIL operations: Close-Job (not supported)
+func (c Check) String() string {
+	s := fmt.Sprintf("%s %s: %s", c.Result, c.Category, c.Name)
+	if c.Message != "" {
+		s += " (" + c.Message + ")"
+	}
+
+	return s
+}
+
+// Report is the result of the checks of a printer
+type Report struct {
+	// URI is the printer uri of the checked printer
+	URI    string
+	Checks []Check
+}
+
+// Passed reports whether no check failed, warnings and skipped checks pass
+func (r *Report) Passed() bool {
+	return len(r.Failures()) == 0
+}
+
+// Failures returns the failed checks
+func (r *Report) Failures() []Check {
+	return r.filter(Fail)
+}
+
+// Warnings returns the checks of recommended features which failed
+func (r *Report) Warnings() []Check {
+	return r.filter(Warn)
+}
+
+func (r *Report) filter(result Result) []Check {
+	var checks []Check
+	for _, c := range r.Checks {
+		if c.Result == result {
+			checks = append(checks, c)
+		}
+	}
+
+	return checks
+}
+
+// String returns one line per check followed by a summary, e.g.
+//
+//	PASS attributes: printer-name
+//	FAIL operations: Close-Job (not supported)
+//	ipps://printer.local/ipp/print: 71 passed, 2 warnings, 1 failed, 0 skipped
+func (r *Report) String() string {
+	var b strings.Builder
+	counts := make(map[Result]int)
+
+	for _, c := range r.Checks {
+		b.WriteString(c.String())
+		b.WriteByte('\n')
+		counts[c.Result]++
+	}
+
+	fmt.Fprintf(&b, "%s: %d passed, %d warnings, %d failed, %d skipped\n", r.URI, counts[Pass], counts[Warn], counts[Fail],
+		counts[Skip])
+
+	return b.String()
+}
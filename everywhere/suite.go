@@ -0,0 +1,62 @@
+package everywhere
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/discovery"
+)
+
+// Run checks a printer against the ipp everywhere requirements. the printer attributes are requested with
+// Get-Printer-Attributes, Validate-Job and Get-Jobs are sent to check that the printer answers the operations it
+// lists. a client for the host and port of the printer is used if client is nil. an error is returned if the printer
+// attributes could not be requested, failed checks are part of the report
+func Run(client *ipp.IPPClient, printer discovery.Printer) (*Report, error) {
+	if client == nil {
+		client = ipp.NewIPPClient(printer.Host, printer.Port, "", "", printer.Secure)
+	}
+
+	req := ipp.NewRequest(ipp.OperationGetPrinterAttributes, 1, ipp.WithPrinterURI(printer.URI),
+		ipp.WithGroups(ipp.RequestedAll, ipp.RequestedMediaColDatabase))
+
+	resp, err := client.SendRequest(printer.HTTPURL(), req, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the printer attributes: %w", err)
+	}
+	if len(resp.PrinterAttributes) == 0 {
+		return nil, errors.New("printer doesn't return any printer attributes")
+	}
+	attributes := resp.PrinterAttributes[0]
+
+	report := &Report{URI: printer.URI}
+	report.Checks = append(report.Checks, CheckAttributes(attributes)...)
+	report.Checks = append(report.Checks, CheckOperations(attributes)...)
+	report.Checks = append(report.Checks, checkRequests(client, printer)...)
+	report.Checks = append(report.Checks, CheckDocumentFormats(attributes)...)
+	report.Checks = append(report.Checks, CheckTXTRecord(printer, attributes)...)
+
+	return report, nil
+}
+
+// checkRequests sends requests which don't create jobs to the printer and checks that they succeed
+func checkRequests(client *ipp.IPPClient, printer discovery.Printer) []Check {
+	l := &checkList{category: CategoryOperations}
+
+	requests := []*ipp.Request{
+		ipp.NewRequest(ipp.OperationValidateJob, 1, ipp.WithPrinterURI(printer.URI),
+			ipp.WithOperationAttributes(map[string]interface{}{ipp.AttributeDocumentFormat: ipp.MimeTypePwgRaster})),
+		ipp.NewRequest(ipp.OperationGetJobs, 1, ipp.WithPrinterURI(printer.URI)),
+	}
+
+	for _, req := range requests {
+		name := ipp.Operation(req.Operation).String() + " request"
+		if _, err := client.SendRequest(printer.HTTPURL(), req, nil); err != nil {
+			l.add(name, Fail, "%v", err)
+		} else {
+			l.add(name, Pass, "")
+		}
+	}
+
+	return l.checks
+}
@@ -0,0 +1,58 @@
+package everywhere
+
+import (
+	"net"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/discovery"
+	"github.com/phin1x/go-ipp/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	printer := server.NewVirtualPrinter("test", nil)
+	printer.SetCapabilities(server.Capabilities{
+		MakeAndModel:    "Test Printer",
+		DocumentFormats: []string{"image/jpeg", ipp.MimeTypePDF},
+		Color:           true,
+		Duplex:          true,
+	})
+
+	s := server.NewServer()
+	printer.Register(s, "/printers/test")
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	host, port, _ := net.SplitHostPort(strings.TrimPrefix(ts.URL, "http://"))
+	portNumber, _ := strconv.Atoi(port)
+
+	service := printer.Service("/printers/test", portNumber, false)
+	service.Host = host
+	report, err := Run(nil, discovery.NewPrinter(service))
+	assert.Nil(t, err)
+	assert.Equal(t, "ipp://"+net.JoinHostPort(host, port)+"/printers/test", report.URI)
+
+	// the virtual printer has no printer-more-info and doesn't implement Cancel-My-Jobs and Close-Job
+	assert.Equal(t, []Check{
+		{Category: CategoryAttributes, Name: ipp.AttributePrinterMoreInfo, Result: Fail, Message: "missing"},
+		{Category: CategoryOperations, Name: "Cancel-My-Jobs", Result: Fail, Message: "not supported"},
+		{Category: CategoryOperations, Name: "Close-Job", Result: Fail, Message: "not supported"},
+	}, report.Failures())
+	assert.Contains(t, report.Checks, Check{Category: CategoryOperations, Name: "Validate-Job request", Result: Pass})
+	assert.Contains(t, report.Checks, Check{Category: CategoryDNSSD, Name: "pdl", Result: Pass})
+
+	uriPrinter, err := discovery.ParseURI(report.URI)
+	assert.Nil(t, err)
+	report, err = Run(nil, uriPrinter)
+	assert.Nil(t, err)
+	assert.Contains(t, report.Checks, Check{Category: CategoryDNSSD, Name: "txt record", Result: Skip,
+		Message: "the printer has no txt record"})
+
+	ts.Close()
+	_, err = Run(nil, uriPrinter)
+	assert.NotNil(t, err)
+}
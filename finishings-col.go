@@ -0,0 +1,214 @@
+package ipp
+
+// FinishingsCol is the finishings-col collection of pwg 5100.1. a printer usually lists the supported combinations
+// in finishings-col-database, a job can request one of them by its template or describe the finishing in detail.
+// offsets and locations are in hundredths of millimeters like in the wire form
+type FinishingsCol struct {
+	// Template is the finishing-template, a finishings keyword like staple-top-left or punch-dual-left
+	Template string
+	// ImpositionTemplate is the imposition-template, e.g. booklet
+	ImpositionTemplate string
+	// MediaSizeName is the media-size-name the finishing applies to, empty for all media
+	MediaSizeName string
+
+	Stitching *FinishingsStitching
+	Punching  *FinishingsPunching
+	Folding   []FinishingsFolding
+}
+
+// FinishingsStitching is the stitching member of a finishings-col
+type FinishingsStitching struct {
+	// Locations are the distances of the stitches from the start of the reference edge
+	Locations     []int
+	Offset        int
+	ReferenceEdge string
+	// Method is the stitching-method, e.g. auto, crimp or wire
+	Method string
+}
+
+// FinishingsPunching is the punching member of a finishings-col
+type FinishingsPunching struct {
+	// Locations are the distances of the holes from the start of the reference edge
+	Locations     []int
+	Offset        int
+	ReferenceEdge string
+}
+
+// FinishingsFolding is a folding member of a finishings-col
+type FinishingsFolding struct {
+	// Direction is the folding-direction, inward or outward
+	Direction     string
+	Offset        int
+	ReferenceEdge string
+}
+
+// NewFinishingsCol creates a finishings-col requesting the finishing template, e.g. FinishingsStapleTopLeft
+func NewFinishingsCol(template int8) FinishingsCol {
+	return FinishingsCol{Template: Finishings(template).String()}
+}
+
+// Collection returns the wire form of the finishings-col, it can be used as value of a finishings-col job attribute
+func (f FinishingsCol) Collection() Attributes {
+	c := make(Attributes)
+
+	if f.Template != "" {
+		c.Set(AttributeFinishingTemplate, TagKeyword, f.Template)
+	}
+	if f.ImpositionTemplate != "" {
+		c.Set(AttributeImpositionTemplate, TagKeyword, f.ImpositionTemplate)
+	}
+	if f.MediaSizeName != "" {
+		c.Set(AttributeMediaSizeName, TagKeyword, f.MediaSizeName)
+	}
+
+	if f.Stitching != nil {
+		s := make(Attributes)
+		if len(f.Stitching.Locations) > 0 {
+			s.Set(AttributeStitchingLocations, TagInteger, intValues(f.Stitching.Locations)...)
+		}
+		s.Set(AttributeStitchingOffset, TagInteger, f.Stitching.Offset)
+		if f.Stitching.ReferenceEdge != "" {
+			s.Set(AttributeStitchingReferenceEdge, TagKeyword, f.Stitching.ReferenceEdge)
+		}
+		if f.Stitching.Method != "" {
+			s.Set(AttributeStitchingMethod, TagKeyword, f.Stitching.Method)
+		}
+		c.Set(AttributeStitching, TagBeginCollection, s)
+	}
+
+	if f.Punching != nil {
+		p := make(Attributes)
+		if len(f.Punching.Locations) > 0 {
+			p.Set(AttributePunchingLocations, TagInteger, intValues(f.Punching.Locations)...)
+		}
+		p.Set(AttributePunchingOffset, TagInteger, f.Punching.Offset)
+		if f.Punching.ReferenceEdge != "" {
+			p.Set(AttributePunchingReferenceEdge, TagKeyword, f.Punching.ReferenceEdge)
+		}
+		c.Set(AttributePunching, TagBeginCollection, p)
+	}
+
+	if len(f.Folding) > 0 {
+		folds := make([]interface{}, len(f.Folding))
+		for i, folding := range f.Folding {
+			fold := make(Attributes)
+			if folding.Direction != "" {
+				fold.Set(AttributeFoldingDirection, TagKeyword, folding.Direction)
+			}
+			fold.Set(AttributeFoldingOffset, TagInteger, folding.Offset)
+			if folding.ReferenceEdge != "" {
+				fold.Set(AttributeFoldingReferenceEdge, TagKeyword, folding.ReferenceEdge)
+			}
+			folds[i] = fold
+		}
+		c.Set(AttributeFolding, TagBeginCollection, folds...)
+	}
+
+	return c
+}
+
+// intValues converts integers into attribute values
+func intValues(values []int) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+
+	return result
+}
+
+// Unmarshal populates the finishings-col from its decoded collection
+func (f *FinishingsCol) Unmarshal(c Attributes) error {
+	u := attributeUnmarshaler{attributes: c}
+
+	*f = FinishingsCol{
+		Template:           u.string(AttributeFinishingTemplate),
+		ImpositionTemplate: u.string(AttributeImpositionTemplate),
+		MediaSizeName:      u.string(AttributeMediaSizeName),
+	}
+
+	if stitching, ok := u.collection(AttributeStitching); ok {
+		s := attributeUnmarshaler{attributes: stitching}
+		f.Stitching = &FinishingsStitching{
+			Locations:     s.ints(AttributeStitchingLocations),
+			Offset:        s.int(AttributeStitchingOffset),
+			ReferenceEdge: s.string(AttributeStitchingReferenceEdge),
+			Method:        s.string(AttributeStitchingMethod),
+		}
+		if s.err != nil && u.err == nil {
+			u.err = s.err
+		}
+	}
+
+	if punching, ok := u.collection(AttributePunching); ok {
+		p := attributeUnmarshaler{attributes: punching}
+		f.Punching = &FinishingsPunching{
+			Locations:     p.ints(AttributePunchingLocations),
+			Offset:        p.int(AttributePunchingOffset),
+			ReferenceEdge: p.string(AttributePunchingReferenceEdge),
+		}
+		if p.err != nil && u.err == nil {
+			u.err = p.err
+		}
+	}
+
+	for _, folding := range u.collections(AttributeFolding) {
+		fu := attributeUnmarshaler{attributes: folding}
+		f.Folding = append(f.Folding, FinishingsFolding{
+			Direction:     fu.string(AttributeFoldingDirection),
+			Offset:        fu.int(AttributeFoldingOffset),
+			ReferenceEdge: fu.string(AttributeFoldingReferenceEdge),
+		})
+		if fu.err != nil && u.err == nil {
+			u.err = fu.err
+		}
+	}
+
+	return u.err
+}
+
+// finishingsCols returns the finishings-col values of the attribute
+func (u *attributeUnmarshaler) finishingsCols(name string) []FinishingsCol {
+	var values []FinishingsCol
+	for _, c := range u.collections(name) {
+		var f FinishingsCol
+		if err := f.Unmarshal(c); err != nil && u.err == nil {
+			u.err = err
+		}
+		values = append(values, f)
+	}
+
+	return values
+}
+
+// SupportsFinishings reports whether the finishings value is listed in finishings-supported
+func (d *PrinterDescription) SupportsFinishings(finishings int8) bool {
+	for _, supported := range d.FinishingsSupported {
+		if supported == int(finishings) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SupportsFinishingsCol reports whether the printer supports the finishings-col. if the printer has a
+// finishings-col-database, the template and media size name must match an entry of the database. otherwise the
+// template must be a finishings keyword listed in finishings-supported
+func (d *PrinterDescription) SupportsFinishingsCol(col FinishingsCol) bool {
+	if len(d.FinishingsColDatabase) == 0 {
+		finishings, err := ParseFinishings(col.Template)
+		return err == nil && d.SupportsFinishings(int8(finishings))
+	}
+
+	for _, entry := range d.FinishingsColDatabase {
+		if entry.Template != col.Template {
+			continue
+		}
+		if entry.MediaSizeName == "" || col.MediaSizeName == "" || entry.MediaSizeName == col.MediaSizeName {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,53 @@
+package ipp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinishingsCol(t *testing.T) {
+	staple := NewFinishingsCol(FinishingsStapleTopLeft)
+	assert.Equal(t, "staple-top-left", staple.Template)
+
+	punch := FinishingsCol{
+		Template:      "punch-dual-left",
+		MediaSizeName: "iso_a4_210x297mm",
+		Punching:      &FinishingsPunching{Locations: []int{10800, 18800}, Offset: 1200, ReferenceEdge: "left"},
+	}
+	fold := FinishingsCol{
+		Template:           "fold-half",
+		ImpositionTemplate: "booklet",
+		Stitching:          &FinishingsStitching{Locations: []int{7000, 22700}, ReferenceEdge: "left", Method: "wire"},
+		Folding:            []FinishingsFolding{{Direction: "inward", Offset: 14850, ReferenceEdge: "left"}},
+	}
+
+	resp := NewResponse(StatusOk, 1)
+	attributes := make(Attributes)
+	attributes.Set(AttributeFinishingsSupported, TagEnum, int(FinishingsNone), int(FinishingsStaple),
+		int(FinishingsStapleTopLeft))
+	attributes.Set(AttributeFinishingsColDatabase, TagBeginCollection, punch.Collection(), fold.Collection())
+	resp.PrinterAttributes = append(resp.PrinterAttributes, attributes)
+
+	data, err := resp.Encode()
+	assert.Nil(t, err)
+	decoded, err := NewResponseDecoder(bytes.NewReader(data)).Decode(nil)
+	assert.Nil(t, err)
+
+	var d PrinterDescription
+	assert.Nil(t, d.Unmarshal(decoded.PrinterAttributes[0]))
+	assert.Equal(t, []FinishingsCol{punch, fold}, d.FinishingsColDatabase)
+
+	assert.True(t, d.SupportsFinishings(FinishingsStapleTopLeft))
+	assert.False(t, d.SupportsFinishings(FinishingsPunch))
+
+	assert.True(t, d.SupportsFinishingsCol(FinishingsCol{Template: "fold-half"}))
+	assert.True(t, d.SupportsFinishingsCol(FinishingsCol{Template: "punch-dual-left", MediaSizeName: "iso_a4_210x297mm"}))
+	assert.False(t, d.SupportsFinishingsCol(FinishingsCol{Template: "punch-dual-left", MediaSizeName: "na_letter_8.5x11in"}))
+	assert.False(t, d.SupportsFinishingsCol(staple))
+
+	d.FinishingsColDatabase = nil
+	assert.True(t, d.SupportsFinishingsCol(staple))
+	assert.False(t, d.SupportsFinishingsCol(FinishingsCol{Template: "fold-half"}))
+}
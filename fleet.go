@@ -0,0 +1,278 @@
+package ipp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"sync"
+	"time"
+)
+
+// defaults of FleetOptions
+const (
+	DefaultFleetConcurrency = 8
+	DefaultFleetTimeout     = 10 * time.Second
+)
+
+// FleetAttributes are the printer attributes requested by Snapshot
+var FleetAttributes = []string{
+	AttributePrinterName,
+	AttributePrinterMakeAndModel,
+	AttributePrinterLocation,
+	AttributePrinterUUID,
+	AttributePrinterState,
+	AttributePrinterStateReasons,
+	AttributePrinterStateMessage,
+	AttributePrinterIsAcceptingJobs,
+	AttributeQueuedJobCount,
+	AttributePrinterUpTime,
+	AttributePrinterSupply,
+	AttributePrinterSupplyDescription,
+	AttributePrinterAlert,
+	AttributePrinterAlertDescription,
+	AttributeMarkerNames,
+	AttributeMarkerTypes,
+	AttributeMarkerColors,
+	AttributeMarkerLevels,
+	AttributeMarkerLowLevels,
+	AttributeMarkerHighLevels,
+}
+
+// FleetOptions configure Snapshot
+type FleetOptions struct {
+	// Concurrency limits the number of printers queried at the same time, defaults to DefaultFleetConcurrency
+	Concurrency int
+	// Timeout limits the time to query a printer, defaults to DefaultFleetTimeout
+	Timeout time.Duration
+	// Username and Password are sent with basic authentication to all printers
+	Username string
+	Password string
+	// TLSSessionCache resumes the tls sessions of the created clients, it saves the full handshakes if a fleet is
+	// polled repeatedly with the same cache
+	TLSSessionCache tls.ClientSessionCache
+	// Client returns the client used for a printer uri, by default a client for the host and port of the uri is
+	// created. the timeout is only applied to the created clients, a custom client should set its own timeout
+	Client func(uri string) (*IPPClient, error)
+}
+
+// PrinterSnapshot is the state of a printer of a fleet. Err is set if the printer could not be queried, the other
+// fields are empty in this case
+type PrinterSnapshot struct {
+	URI         string
+	Description *PrinterDescription
+	Supplies    []Supply
+	Alerts      []Alert
+	// Jobs is the number of pending and processing jobs, the queued-job-count of the printer
+	Jobs int
+	// Duration is the time the query of the printer took
+	Duration time.Duration
+	Err      error
+}
+
+// Reasons returns the printer-state-reasons of the printer
+func (s *PrinterSnapshot) Reasons() StateReasons {
+	if s.Description == nil {
+		return nil
+	}
+
+	return s.Description.Reasons()
+}
+
+// LowSupplies returns the supplies of the printer which are low or empty
+func (s *PrinterSnapshot) LowSupplies() []Supply {
+	var low []Supply
+	for _, supply := range s.Supplies {
+		if supply.Low() {
+			low = append(low, supply)
+		}
+	}
+
+	return low
+}
+
+// FleetReport is the result of Snapshot
+type FleetReport struct {
+	// Printers are the snapshots in the order of the uris
+	Printers []PrinterSnapshot
+	Time     time.Time
+}
+
+// FleetSummary counts the printers of a fleet report by their condition
+type FleetSummary struct {
+	Printers    int
+	Idle        int
+	Processing  int
+	Stopped     int
+	Unreachable int
+	// Errors is the number of printers with a printer-state-reason of error severity
+	Errors int
+	// LowSupplies is the number of printers with a low or empty supply
+	LowSupplies int
+	// Jobs is the number of pending and processing jobs of all printers
+	Jobs int
+}
+
+// Summary counts the printers of the report by their condition
+func (r *FleetReport) Summary() FleetSummary {
+	summary := FleetSummary{Printers: len(r.Printers)}
+
+	for i := range r.Printers {
+		p := &r.Printers[i]
+		if p.Err != nil {
+			summary.Unreachable++
+			continue
+		}
+
+		switch p.Description.State {
+		case PrinterStateIdle:
+			summary.Idle++
+		case PrinterStateProcessing:
+			summary.Processing++
+		case PrinterStateStopped:
+			summary.Stopped++
+		}
+		if p.Reasons().HasError() {
+			summary.Errors++
+		}
+		if len(p.LowSupplies()) > 0 {
+			summary.LowSupplies++
+		}
+		summary.Jobs += p.Jobs
+	}
+
+	return summary
+}
+
+// Unreachable returns the snapshots of the printers which could not be queried
+func (r *FleetReport) Unreachable() []PrinterSnapshot {
+	var unreachable []PrinterSnapshot
+	for _, p := range r.Printers {
+		if p.Err != nil {
+			unreachable = append(unreachable, p)
+		}
+	}
+
+	return unreachable
+}
+
+// Snapshot queries the state, supplies, alerts and job counts of the printers with a Get-Printer-Attributes request
+// for FleetAttributes. at most Concurrency printers are queried at the same time and each query is limited to
+// Timeout. printers which are not queried before the context is done get the error of the context
+func Snapshot(ctx context.Context, uris []string, opts FleetOptions) *FleetReport {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultFleetConcurrency
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultFleetTimeout
+	}
+
+	report := &FleetReport{Printers: make([]PrinterSnapshot, len(uris)), Time: time.Now()}
+	slots := make(chan struct{}, opts.Concurrency)
+
+	var wg sync.WaitGroup
+	for i, uri := range uris {
+		// a free slot must not win over a done context
+		err := ctx.Err()
+		if err == nil {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+		}
+		if err != nil {
+			report.Printers[i] = PrinterSnapshot{URI: uri, Err: err}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, uri string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			report.Printers[i] = snapshotPrinter(ctx, uri, opts)
+		}(i, uri)
+	}
+
+	wg.Wait()
+
+	return report
+}
+
+// snapshotPrinter queries a printer, the query is abandoned if the context is done or the timeout expires
+func snapshotPrinter(ctx context.Context, uri string, opts FleetOptions) PrinterSnapshot {
+	start := time.Now()
+	snapshot := PrinterSnapshot{URI: uri}
+
+	client, url, err := fleetClient(uri, opts)
+	if err != nil {
+		snapshot.Err = err
+		return snapshot
+	}
+	// the connections of a created client are not reused, they are closed so large fleets don't exhaust sockets
+	if adapter, ok := client.adapter.(*HttpAdapter); ok && opts.Client == nil {
+		defer adapter.CloseIdleConnections()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	type result struct {
+		resp *Response
+		err  error
+	}
+	results := make(chan result, 1)
+
+	go func() {
+		req := NewRequest(OperationGetPrinterAttributes, 1, WithPrinterURI(uri), WithRequestedAttributes(FleetAttributes...))
+		resp, err := client.SendRequest(url, req, nil)
+		results <- result{resp, err}
+	}()
+
+	var r result
+	select {
+	case r = <-results:
+	case <-ctx.Done():
+		r.err = ctx.Err()
+	}
+
+	snapshot.Duration = time.Since(start)
+	if r.err == nil && len(r.resp.PrinterAttributes) == 0 {
+		r.err = errors.New("printer doesn't return any printer attributes")
+	}
+	if r.err != nil {
+		snapshot.Err = r.err
+		return snapshot
+	}
+
+	attributes := r.resp.PrinterAttributes[0]
+	snapshot.Description = new(PrinterDescription)
+	// attributes of unexpected types are left out, the snapshot is still useful
+	_ = snapshot.Description.Unmarshal(attributes)
+	snapshot.Supplies = ParseSupplies(attributes)
+	snapshot.Alerts = ParseAlerts(attributes)
+	snapshot.Jobs = snapshot.Description.QueuedJobCount
+
+	return snapshot
+}
+
+// fleetClient returns the client and the http url of a printer uri
+func fleetClient(uri string, opts FleetOptions) (*IPPClient, string, error) {
+	adapter, u, err := uriAdapter(uri, opts.Username, opts.Password)
+	if err != nil {
+		return nil, "", err
+	}
+	url := adapter.GetHttpUri("", nil) + u.EscapedPath()
+
+	if opts.Client != nil {
+		client, err := opts.Client(uri)
+		return client, url, err
+	}
+
+	adapter.client.Timeout = opts.Timeout
+	if err := adapter.SetTLSOptions(TLSOptions{SessionCache: opts.TLSSessionCache}); err != nil {
+		return nil, "", err
+	}
+
+	return NewIPPClientWithAdapter(opts.Username, adapter), url, nil
+}
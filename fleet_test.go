@@ -0,0 +1,112 @@
+package ipp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	var active, maxActive int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			m := atomic.LoadInt32(&maxActive)
+			if n <= m || atomic.CompareAndSwapInt32(&maxActive, m, n) {
+				break
+			}
+		}
+
+		req, err := NewRequestDecoder(r.Body).Decode(nil)
+		assert.Nil(t, err)
+
+		attributes := make(Attributes)
+		attributes.Set(AttributePrinterName, TagName, strings.TrimPrefix(r.URL.Path, "/ipp/"))
+		attributes.Set(AttributePrinterIsAcceptingJobs, TagBoolean, true)
+
+		switch r.URL.Path {
+		case "/ipp/idle":
+			attributes.Set(AttributePrinterState, TagEnum, int(PrinterStateIdle))
+			attributes.Set(AttributePrinterStateReasons, TagKeyword, "none")
+			attributes.Set(AttributePrinterSupply, TagString,
+				"index=1;class=supplyThatIsConsumed;type=toner;unit=percent;maxcapacity=100;level=5;colorantname=black;")
+		case "/ipp/busy":
+			attributes.Set(AttributePrinterState, TagEnum, int(PrinterStateProcessing))
+			attributes.Set(AttributePrinterStateReasons, TagKeyword, "none")
+			attributes.Set(AttributeQueuedJobCount, TagInteger, 4)
+		case "/ipp/jammed":
+			attributes.Set(AttributePrinterState, TagEnum, int(PrinterStateStopped))
+			attributes.Set(AttributePrinterStateReasons, TagKeyword, "media-jam-error")
+			attributes.Set(AttributeQueuedJobCount, TagInteger, 1)
+		case "/ipp/slow":
+			time.Sleep(time.Second)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+
+		resp := NewResponse(StatusOk, req.RequestId)
+		resp.PrinterAttributes = append(resp.PrinterAttributes, attributes)
+		payload, _ := resp.Encode()
+		w.Header().Set("Content-Type", ContentTypeIPP)
+		w.Write(payload)
+	}))
+	defer ts.Close()
+
+	host := "ipp://" + strings.TrimPrefix(ts.URL, "http://")
+	uris := []string{
+		host + "/ipp/idle",
+		host + "/ipp/busy",
+		host + "/ipp/jammed",
+		"ipp://" + closedAddress(t) + "/ipp/print",
+		host + "/ipp/slow",
+		"lpd://printer/queue",
+	}
+
+	report := Snapshot(context.Background(), uris, FleetOptions{Concurrency: 2, Timeout: 200 * time.Millisecond})
+	assert.Len(t, report.Printers, len(uris))
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxActive), int32(2))
+
+	for i, p := range report.Printers {
+		assert.Equal(t, uris[i], p.URI)
+	}
+
+	idle := report.Printers[0]
+	assert.Nil(t, idle.Err)
+	assert.Equal(t, "idle", idle.Description.Name)
+	assert.Len(t, idle.Supplies, 1)
+	assert.Len(t, idle.LowSupplies(), 1)
+
+	assert.Equal(t, 4, report.Printers[1].Jobs)
+	assert.True(t, report.Printers[2].Reasons().HasError())
+
+	assert.NotNil(t, report.Printers[3].Err)
+	assert.ErrorIs(t, report.Printers[4].Err, context.DeadlineExceeded)
+	assert.NotNil(t, report.Printers[5].Err)
+	assert.Len(t, report.Unreachable(), 3)
+
+	assert.Equal(t, FleetSummary{
+		Printers:    6,
+		Idle:        1,
+		Processing:  1,
+		Stopped:     1,
+		Unreachable: 3,
+		Errors:      1,
+		LowSupplies: 1,
+		Jobs:        5,
+	}, report.Summary())
+
+	// printers which are not queried before the context is done are not contacted
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report = Snapshot(ctx, uris[:2], FleetOptions{Concurrency: 1})
+	for _, p := range report.Printers {
+		assert.ErrorIs(t, p.Err, context.Canceled)
+	}
+}
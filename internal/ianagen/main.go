@@ -0,0 +1,265 @@
+// Command ianagen generates the operation, status code and tag constants of the ipp package from the csv exports of
+// the iana ipp registry, https://www.iana.org/assignments/ipp-registrations. the exports are kept in the registry
+// directory together with the cups extensions, which are not registered at iana. to update the constants download
+// the current exports of the operations, status codes and tags sub-registries into the registry directory and run
+// go generate in the root of the module
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// legacyNames are the go names of registry entries which predate the generator and don't follow the naming rules,
+// they are kept for compatibility
+var legacyNames = map[string]string{
+	"CUPS-Get-PPDs": "OperationCupsGetPPDs",
+
+	"successful-ok-ignored-or-substituted-attributes":   "StatusOkIgnoredOrSubstituted",
+	"successful-ok-conflicting-attributes":              "StatusOkConflicting",
+	"client-error-request-entity-too-large":             "StatusErrorRequestEntity",
+	"client-error-request-value-too-long":               "StatusErrorRequestValue",
+	"client-error-attributes-or-values-not-supported":   "StatusErrorAttributesOrValues",
+	"client-error-uri-scheme-not-supported":             "StatusErrorUriScheme",
+	"client-error-charset-not-supported":                "StatusErrorCharset",
+	"client-error-conflicting-attributes":               "StatusErrorConflicting",
+	"client-error-document-access-error":                "StatusErrorDocumentAccess",
+	"client-error-document-password-error":              "StatusErrorDocumentPassword",
+	"client-error-document-permission-error":            "StatusErrorDocumentPermission",
+	"client-error-document-security-error":              "StatusErrorDocumentSecurity",
+	"client-error-document-unprintable-error":           "StatusErrorDocumentUnprintable",
+	"server-error-internal-error":                       "StatusErrorInternal",
+	"server-error-device-error":                         "StatusErrorDevice",
+	"server-error-temporary-error":                      "StatusErrorTemporary",
+	"server-error-multiple-document-jobs-not-supported": "StatusErrorMultipleJobsNotSupported",
+	"cups-error-account-info-needed":                    "StatusErrorCupsAccountInfoNeeded",
+	"cups-error-account-closed":                         "StatusErrorCupsAccountClosed",
+	"cups-error-account-limit-reached":                  "StatusErrorCupsAccountLimitReached",
+	"cups-error-account-authorization-failed":           "StatusErrorCupsAccountAuthorizationFailed",
+	"cups-authentication-canceled":                      "StatusErrorCupsAuthenticationCanceled",
+	"cups-pki-error":                                    "StatusErrorCupsPki",
+	"cups-upgrade-required":                             "StatusErrorCupsUpgradeRequired",
+
+	"end-of-attributes-tag":      "TagEnd",
+	"unsupported-attributes-tag": "TagUnsupportedGroup",
+	"unsupported":                "TagUnsupportedValue",
+	"delete-attribute":           "TagDeleteAttr",
+	"octetString":                "TagString",
+	"dateTime":                   "TagDate",
+	"rangeOfInteger":             "TagRange",
+	"begCollection":              "TagBeginCollection",
+	"textWithLanguage":           "TagTextLang",
+	"nameWithLanguage":           "TagNameLang",
+	"textWithoutLanguage":        "TagText",
+	"nameWithoutLanguage":        "TagName",
+	"naturalLanguage":            "TagLanguage",
+	"mimeMediaType":              "TagMimeType",
+	"memberAttrName":             "TagMemberName",
+}
+
+// entry is an assigned value of a registry
+type entry struct {
+	value  int
+	name   string
+	goName string
+}
+
+func main() {
+	registry := flag.String("registry", "internal/ianagen/registry", "directory of the registry exports")
+	out := flag.String("out", "constants-generated.go", "generated go file")
+	flag.Parse()
+
+	source, err := generate(*registry)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*out, source, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate returns the formatted source of the constants of the registry exports in dir
+func generate(dir string) ([]byte, error) {
+	operations, err := readEntries(dir, operationName, "operations.csv", "cups-operations.csv")
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := readEntries(dir, statusName, "status-codes.csv", "cups-status-codes.csv")
+	if err != nil {
+		return nil, err
+	}
+	tags, err := readEntries(dir, tagName, "tags.csv")
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by internal/ianagen from the iana ipp registry. DO NOT EDIT.\n\npackage ipp\n\n")
+
+	writeConstants(&b, "ipp status codes", "int16", "0x%04x", statuses)
+	writeConstants(&b, "ipp operations", "int16", "0x%04x", operations)
+	writeConstants(&b, "ipp tags", "int8", "0x%02x", tags)
+
+	writeNames(&b, "operationNames are the names of the operations, e.g. Print-Job", "operationNames", operations)
+	writeNames(&b, "statusKeywords are the keywords of the status codes, e.g. client-error-not-found", "statusKeywords",
+		statuses)
+
+	return format.Source(b.Bytes())
+}
+
+// readEntries reads the assigned values of the csv files, reserved and unassigned values and ranges are skipped
+func readEntries(dir string, goName func(string) string, files ...string) ([]entry, error) {
+	var entries []entry
+	for _, file := range files {
+		f, err := os.Open(filepath.Join(dir, file))
+		if err != nil {
+			return nil, err
+		}
+		records, err := readRecords(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		for _, record := range records {
+			value, err := strconv.ParseInt(record.value, 0, 32)
+			if err != nil || strings.HasPrefix(record.name, "Reserved") || record.name == "Unassigned" {
+				continue
+			}
+			entries = append(entries, entry{value: int(value), name: record.name, goName: goName(record.name)})
+		}
+	}
+
+	slices.SortFunc(entries, func(a, b entry) int { return a.value - b.value })
+	for i := 1; i < len(entries); i++ {
+		if entries[i].value == entries[i-1].value {
+			return nil, fmt.Errorf("%s and %s have the same value 0x%x", entries[i-1].name, entries[i].name,
+				entries[i].value)
+		}
+	}
+
+	return entries, nil
+}
+
+type record struct {
+	value string
+	name  string
+}
+
+// readRecords reads the value and name columns of a registry export, the columns are found by their header
+func readRecords(r io.Reader) ([]record, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("empty registry export")
+	}
+
+	valueColumn, nameColumn := -1, -1
+	for i, column := range rows[0] {
+		switch strings.ToLower(strings.TrimSpace(column)) {
+		case "value":
+			valueColumn = i
+		case "name":
+			nameColumn = i
+		}
+	}
+	if valueColumn < 0 || nameColumn < 0 {
+		return nil, fmt.Errorf("registry export without value and name columns")
+	}
+
+	records := make([]record, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		records = append(records, record{
+			value: strings.TrimSpace(row[valueColumn]),
+			name:  strings.TrimSpace(row[nameColumn]),
+		})
+	}
+
+	return records, nil
+}
+
+// operationName returns the go name of an operation, e.g. OperationPrintUri for Print-URI
+func operationName(name string) string {
+	if goName, ok := legacyNames[name]; ok {
+		return goName
+	}
+
+	return "Operation" + camelCase(strings.Split(name, "-"))
+}
+
+// statusName returns the go name of a status code, e.g. StatusErrorNotFound for client-error-not-found
+func statusName(name string) string {
+	if goName, ok := legacyNames[name]; ok {
+		return goName
+	}
+
+	for prefix, replacement := range map[string]string{
+		"successful-ok": "Ok",
+		"client-error-": "Error",
+		"server-error-": "Error",
+	} {
+		if rest, ok := strings.CutPrefix(name, prefix); ok {
+			return "Status" + replacement + camelCase(strings.Split(strings.TrimPrefix(rest, "-"), "-"))
+		}
+	}
+
+	return "Status" + camelCase(strings.Split(name, "-"))
+}
+
+// tagName returns the go name of a tag, e.g. TagJob for job-attributes-tag and TagUriScheme for uriScheme
+func tagName(name string) string {
+	if goName, ok := legacyNames[name]; ok {
+		return goName
+	}
+
+	return "Tag" + camelCase(strings.Split(strings.TrimSuffix(name, "-attributes-tag"), "-"))
+}
+
+// camelCase joins the words with an upper case first letter, the following letters are lowered unless the word is
+// already camel cased like uriScheme
+func camelCase(words []string) string {
+	var b strings.Builder
+	for _, word := range words {
+		if word == "" {
+			continue
+		}
+		runes := []rune(word)
+		if strings.ToUpper(word) == word {
+			runes = []rune(strings.ToLower(word))
+		}
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	return b.String()
+}
+
+func writeConstants(b *bytes.Buffer, comment, typ, valueFormat string, entries []entry) {
+	fmt.Fprintf(b, "// %s\nconst (\n", comment)
+	for _, e := range entries {
+		fmt.Fprintf(b, "\t%s %s = "+valueFormat+" // %s\n", e.goName, typ, e.value, e.name)
+	}
+	b.WriteString(")\n\n")
+}
+
+func writeNames(b *bytes.Buffer, comment, variable string, entries []entry) {
+	fmt.Fprintf(b, "// %s\nvar %s = map[int]string{\n", comment, variable)
+	for _, e := range entries {
+		fmt.Fprintf(b, "\tint(%s): %q,\n", e.goName, e.name)
+	}
+	b.WriteString("}\n\n")
+}
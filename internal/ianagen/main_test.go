@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	generated, err := generate("registry")
+	assert.Nil(t, err)
+
+	committed, err := os.ReadFile("../../constants-generated.go")
+	assert.Nil(t, err)
+	assert.Equal(t, string(committed), string(generated), "constants-generated.go is outdated, run go generate")
+}
+
+func TestGoNames(t *testing.T) {
+	tests := []struct {
+		name   func(string) string
+		input  string
+		goName string
+	}{
+		{operationName, "Print-Job", "OperationPrintJob"},
+		{operationName, "Print-URI", "OperationPrintUri"},
+		{operationName, "Get-User-Printer-Attributes", "OperationGetUserPrinterAttributes"},
+		{operationName, "CUPS-Get-PPD", "OperationCupsGetPpd"},
+		{operationName, "CUPS-Get-PPDs", "OperationCupsGetPPDs"},
+		{statusName, "successful-ok", "StatusOk"},
+		{statusName, "successful-ok-events-complete", "StatusOkEventsComplete"},
+		{statusName, "client-error-not-found", "StatusErrorNotFound"},
+		{statusName, "server-error-busy", "StatusErrorBusy"},
+		{statusName, "redirection-other-site", "StatusRedirectionOtherSite"},
+		{statusName, "server-error-internal-error", "StatusErrorInternal"},
+		{tagName, "job-attributes-tag", "TagJob"},
+		{tagName, "uriScheme", "TagUriScheme"},
+		{tagName, "no-value", "TagNoValue"},
+		{tagName, "textWithoutLanguage", "TagText"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.goName, test.name(test.input), test.input)
+	}
+}
@@ -1,11 +1,19 @@
 package ipp
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
+	"math"
 	"os"
 	"path"
+	"slices"
+	"sync"
+	"time"
 )
 
 // Document wraps an io.Reader with more information, needed for encoding
@@ -14,12 +22,33 @@ type Document struct {
 	Size     int
 	Name     string
 	MimeType string
+	// FormatDetails is sent as document-format-details if set, it is dropped if the document is converted by a filter
+	FormatDetails *DocumentFormatDetails
 }
 
 // IPPClient implements a generic ipp client
 type IPPClient struct {
 	username string
 	adapter  Adapter
+
+	defaultOperationAttributes map[string]interface{}
+	defaultJobAttributes       map[string]interface{}
+
+	requestIDsMu sync.Mutex
+	requestIDs   map[string]int32
+
+	operationsMu sync.Mutex
+	operations   map[string][]int16
+
+	capabilitiesMu sync.Mutex
+	capabilities   map[capabilityKey]*capabilities
+	userAttributes map[string]Attributes
+
+	rawResponses bool
+
+	filters *FilterRegistry
+
+	logger *slog.Logger
 }
 
 // NewIPPClient creates a new generic ipp client (used HttpAdapter internally)
@@ -52,22 +81,256 @@ func (c *IPPClient) getClassUri(printer string) string {
 	return fmt.Sprintf("ipp://localhost/classes/%s", printer)
 }
 
-// SendRequest sends a request to a remote uri end returns the response
+// SetDefaultOperationAttributes sets operation attributes which are added to every outgoing request.
+// attributes set explicitly on a request take precedence over the defaults
+func (c *IPPClient) SetDefaultOperationAttributes(attributes map[string]interface{}) {
+	c.defaultOperationAttributes = copyAttributeMap(attributes)
+}
+
+// SetDefaultJobAttributes sets job attributes which are added to every outgoing job creation request
+// (Print-Job, Print-URI, Create-Job and Validate-Job). attributes set explicitly on a request take precedence over the defaults
+func (c *IPPClient) SetDefaultJobAttributes(attributes map[string]interface{}) {
+	c.defaultJobAttributes = copyAttributeMap(attributes)
+}
+
+func (c *IPPClient) applyDefaultAttributes(req *Request) {
+	if req.OperationAttributes == nil {
+		req.OperationAttributes = make(map[string]interface{})
+	}
+
+	for key, value := range c.defaultOperationAttributes {
+		if _, ok := req.OperationAttributes[key]; !ok {
+			req.OperationAttributes[key] = value
+		}
+	}
+
+	switch req.Operation {
+	case OperationPrintJob, OperationPrintUri, OperationCreateJob, OperationValidateJob:
+	default:
+		return
+	}
+
+	if req.JobAttributes == nil {
+		req.JobAttributes = make(map[string]interface{})
+	}
+
+	for key, value := range c.defaultJobAttributes {
+		if _, ok := req.JobAttributes[key]; !ok {
+			req.JobAttributes[key] = value
+		}
+	}
+}
+
+func copyAttributeMap(attributes map[string]interface{}) map[string]interface{} {
+	if attributes == nil {
+		return nil
+	}
+
+	m := make(map[string]interface{}, len(attributes))
+	for key, value := range attributes {
+		m[key] = value
+	}
+
+	return m
+}
+
+// SetRawResponses disables the status check of the client. by default SendRequest and the client methods return a
+// StatusError for a non successful status code, the decoded response is available in the Response field of the
+// error. in raw mode SendRequest returns the response regardless of its status and the caller has to check it, e.g.
+// with CheckForErrors
+func (c *IPPClient) SetRawResponses(raw bool) {
+	c.rawResponses = raw
+}
+
+// SetLogger logs every request with its operation, status and duration. successful requests are logged at debug
+// level, error statuses and attributes the printer ignored or substituted at warn level and failed requests at error
+// level. nil disables the logging
+func (c *IPPClient) SetLogger(logger *slog.Logger) {
+	c.logger = logger
+}
+
+// SetFilters sets the filters which convert documents in formats the printer does not support before they are
+// sent. the document-format-supported of the printer is requested for every job, the converted documents are
+// buffered in memory to determine their size. documents without format or with application/octet-stream are sent
+// unchanged. nil disables the conversion
+func (c *IPPClient) SetFilters(filters *FilterRegistry) {
+	c.filters = filters
+}
+
+// filterDocuments converts the documents the printer does not support with the filters of the client
+func (c *IPPClient) filterDocuments(printer string, docs []Document) ([]Document, error) {
+	if c.filters == nil {
+		return docs, nil
+	}
+
+	attributes, err := c.GetPrinterAttributes(printer, []string{AttributeDocumentFormatSupported})
+	if err != nil {
+		return nil, err
+	}
+	u := attributeUnmarshaler{attributes: attributes}
+	supported := u.strings(AttributeDocumentFormatSupported)
+	if len(supported) == 0 {
+		return docs, nil
+	}
+
+	converted := make([]Document, len(docs))
+	for i, doc := range docs {
+		converted[i] = doc
+		if doc.MimeType == "" || doc.MimeType == MimeTypeOctetStream || containsString(supported, doc.MimeType) {
+			continue
+		}
+
+		r, format, err := c.filters.Transform(doc.Document, doc.MimeType, supported...)
+		if err != nil {
+			return nil, err
+		}
+
+		buf := new(bytes.Buffer)
+		if _, err := io.Copy(buf, r); err != nil {
+			return nil, fmt.Errorf("unable to convert %s into %s: %w", doc.MimeType, format, err)
+		}
+		converted[i] = Document{Document: buf, Size: buf.Len(), Name: doc.Name, MimeType: format}
+	}
+
+	return converted, nil
+}
+
+// nextRequestID returns the next request id for the target url. the ids are increasing and never zero as required
+// by rfc 8011, they wrap around to 1 after the maximum
+func (c *IPPClient) nextRequestID(url string) int32 {
+	c.requestIDsMu.Lock()
+	defer c.requestIDsMu.Unlock()
+
+	if c.requestIDs == nil {
+		c.requestIDs = make(map[string]int32)
+	}
+
+	id := c.requestIDs[url]
+	if id == math.MaxInt32 {
+		id = 0
+	}
+	id++
+	c.requestIDs[url] = id
+
+	return id
+}
+
+// SendRequest sends a request to a remote uri end returns the response. the request-id of the request is replaced
+// by the next id of the uri, a response with a different request-id fails with RequestIDMismatchError. a response
+// with a non successful status is returned together with a StatusError, unless raw responses are enabled with
+// SetRawResponses
 func (c *IPPClient) SendRequest(url string, req *Request, additionalResponseData io.Writer) (*Response, error) {
+	start := time.Now()
+	resp, err := c.sendRequest(url, req, additionalResponseData)
+	if c.logger != nil {
+		c.logRequest(url, req, resp, err, time.Since(start))
+	}
+
+	return resp, err
+}
+
+func (c *IPPClient) sendRequest(url string, req *Request, additionalResponseData io.Writer) (*Response, error) {
+	// the defaults may supply the target of the request, so they are applied before the request is validated
+	c.applyDefaultAttributes(req)
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
 	if _, ok := req.OperationAttributes[AttributeRequestingUserName]; !ok {
 		req.OperationAttributes[AttributeRequestingUserName] = c.username
 	}
 
-	return c.adapter.SendRequest(url, req, additionalResponseData)
+	req.RequestId = c.nextRequestID(url)
+
+	// the adapters return the response together with the status error
+	resp, err := c.adapter.SendRequest(url, req, additionalResponseData)
+	if err != nil && (resp == nil || !errors.As(err, new(StatusError))) {
+		return nil, err
+	}
+
+	if resp.RequestId != req.RequestId {
+		return nil, fmt.Errorf("%w: sent %d, received %d", RequestIDMismatchError, req.RequestId, resp.RequestId)
+	}
+
+	if c.rawResponses {
+		return resp, nil
+	}
+
+	if err := resp.CheckForErrors(); err != nil {
+		statusErr := err.(StatusError)
+		statusErr.Response = resp
+		return resp, statusErr
+	}
+
+	return resp, nil
+}
+
+// logRequest logs a request sent by SendRequest
+func (c *IPPClient) logRequest(url string, req *Request, resp *Response, err error, duration time.Duration) {
+	attrs := []slog.Attr{
+		slog.String("operation", Operation(req.Operation).String()),
+		slog.String("url", url),
+		slog.Int("request-id", int(req.RequestId)),
+		slog.Duration("duration", duration),
+	}
+
+	if resp == nil {
+		attrs = append(attrs, slog.Any("error", err))
+		c.logger.LogAttrs(context.Background(), slog.LevelError, "ipp request failed", attrs...)
+		return
+	}
+
+	attrs = append(attrs, slog.String("status", Status(resp.StatusCode).String()))
+	if resp.StatusCode >= StatusErrorBadRequest {
+		if message := resp.OperationAttributes[AttributeStatusMessage]; len(message) > 0 {
+			attrs = append(attrs, slog.Any("status-message", message[0].Value))
+		}
+		c.logger.LogAttrs(context.Background(), slog.LevelWarn, "ipp request", attrs...)
+		return
+	}
+
+	c.logger.LogAttrs(context.Background(), slog.LevelDebug, "ipp request", attrs...)
+
+	if len(resp.UnsupportedAttributes) > 0 {
+		names := slices.Sorted(maps.Keys(resp.UnsupportedAttributes))
+		c.logger.LogAttrs(context.Background(), slog.LevelWarn, "printer ignored or substituted attributes",
+			slog.String("operation", Operation(req.Operation).String()),
+			slog.String("url", url),
+			slog.Any("attributes", names))
+	}
 }
 
 // PrintDocuments prints one or more documents using a Create-Job operation followed by one or more Send-Document operation(s). custom job settings can be specified via the jobAttributes parameter
 func (c *IPPClient) PrintDocuments(docs []Document, printer string, jobAttributes map[string]interface{}) (int, error) {
+	job, err := c.SubmitDocuments(docs, printer, jobAttributes)
+	if err != nil {
+		return -1, err
+	}
+
+	return job.ID, nil
+}
+
+// SubmitDocuments works like PrintDocuments but returns the created job in typed form. attributes the printer ignored
+// or substituted, e.g. an unsupported sides value, are collected in the Unsupported field of the job
+func (c *IPPClient) SubmitDocuments(docs []Document, printer string, jobAttributes map[string]interface{}) (*Job, error) {
+	docs, err := c.filterDocuments(printer, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	// printers without Create-Job can still print a single document with Print-Job
+	if supported, known := c.supportsOperation(printer, OperationCreateJob); known && !supported {
+		if len(docs) != 1 {
+			return nil, fmt.Errorf("%w: %s", OperationNotSupportedError, Operation(OperationCreateJob))
+		}
+		return c.submitJob(docs[0], printer, jobAttributes)
+	}
+
 	printerURI := c.getPrinterUri(printer)
 
 	req := NewRequest(OperationCreateJob, 1)
 	req.OperationAttributes[AttributePrinterURI] = printerURI
-	req.OperationAttributes[AttributeRequestingUserName] = c.username
 
 	// set defaults for some attributes, may get overwritten
 	req.OperationAttributes[AttributeJobName] = docs[0].Name
@@ -80,70 +343,121 @@ func (c *IPPClient) PrintDocuments(docs []Document, printer string, jobAttribute
 
 	resp, err := c.SendRequest(c.adapter.GetHttpUri("printers", printer), req, nil)
 	if err != nil {
-		return -1, err
+		return nil, err
 	}
 
-	if len(resp.JobAttributes) == 0 {
-		return 0, errors.New("server doesn't returned a job id")
+	job, err := submittedJob(resp)
+	if err != nil {
+		return nil, err
 	}
 
-	jobID := resp.JobAttributes[0][AttributeJobID][0].Value.(int)
-
 	documentCount := len(docs) - 1
 
 	for docID, doc := range docs {
-		req = NewRequest(OperationSendDocument, 2)
-		req.OperationAttributes[AttributePrinterURI] = printerURI
-		req.OperationAttributes[AttributeRequestingUserName] = c.username
-		req.OperationAttributes[AttributeJobID] = jobID
-		req.OperationAttributes[AttributeDocumentName] = doc.Name
-		req.OperationAttributes[AttributeDocumentFormat] = doc.MimeType
-		req.OperationAttributes[AttributeLastDocument] = docID == documentCount
-		req.File = doc.Document
-		req.FileSize = doc.Size
-
-		_, err = c.SendRequest(c.adapter.GetHttpUri("printers", printer), req, nil)
+		req = NewRequest(OperationSendDocument, 2,
+			WithPrinterURI(printerURI),
+			WithJobID(job.ID),
+			WithOperationAttributes(map[string]interface{}{
+				AttributeDocumentName: doc.Name,
+				AttributeLastDocument: docID == documentCount,
+			}),
+			WithDocument(doc.Document, doc.Size, doc.MimeType),
+		)
+		if doc.FormatDetails != nil {
+			WithDocumentFormatDetails(*doc.FormatDetails)(req)
+		}
+
+		resp, err = c.SendRequest(c.adapter.GetHttpUri("printers", printer), req, nil)
 		if err != nil {
-			return -1, err
+			return nil, err
 		}
+
+		job.Unsupported = mergeUnsupported(job.Unsupported, resp.UnsupportedAttributes)
 	}
 
-	return jobID, nil
+	return job, nil
 }
 
 // PrintJob prints a document using a Print-Job operation. custom job settings can be specified via the jobAttributes parameter
 func (c *IPPClient) PrintJob(doc Document, printer string, jobAttributes map[string]interface{}) (int, error) {
-	printerURI := c.getPrinterUri(printer)
-
-	req := NewRequest(OperationPrintJob, 1)
-	req.OperationAttributes[AttributePrinterURI] = printerURI
-	req.OperationAttributes[AttributeRequestingUserName] = c.username
-	req.OperationAttributes[AttributeJobName] = doc.Name
-	req.OperationAttributes[AttributeDocumentFormat] = doc.MimeType
+	job, err := c.SubmitJob(doc, printer, jobAttributes)
+	if err != nil {
+		return -1, err
+	}
 
-	// set defaults for some attributes, may get overwritten
-	req.OperationAttributes[AttributeCopies] = 1
-	req.OperationAttributes[AttributeJobPriority] = DefaultJobPriority
+	return job.ID, nil
+}
 
-	for key, value := range jobAttributes {
-		req.JobAttributes[key] = value
+// SubmitJob works like PrintJob but returns the created job in typed form. attributes the printer ignored or
+// substituted, e.g. an unsupported sides value, are returned in the Unsupported field of the job
+func (c *IPPClient) SubmitJob(doc Document, printer string, jobAttributes map[string]interface{}) (*Job, error) {
+	docs, err := c.filterDocuments(printer, []Document{doc})
+	if err != nil {
+		return nil, err
 	}
 
-	req.File = doc.Document
-	req.FileSize = doc.Size
+	return c.submitJob(docs[0], printer, jobAttributes)
+}
+
+// submitJob sends the Print-Job request of SubmitJob
+func (c *IPPClient) submitJob(doc Document, printer string, jobAttributes map[string]interface{}) (*Job, error) {
+	printerURI := c.getPrinterUri(printer)
+
+	req := NewRequest(OperationPrintJob, 1,
+		WithPrinterURI(printerURI),
+		// set defaults for some attributes, may get overwritten
+		WithOperationAttributes(map[string]interface{}{
+			AttributeJobName:     doc.Name,
+			AttributeCopies:      1,
+			AttributeJobPriority: DefaultJobPriority,
+		}),
+		WithJobAttributes(jobAttributes),
+		WithDocument(doc.Document, doc.Size, doc.MimeType),
+	)
+	if doc.FormatDetails != nil {
+		WithDocumentFormatDetails(*doc.FormatDetails)(req)
+	}
 
 	resp, err := c.SendRequest(c.adapter.GetHttpUri("printers", printer), req, nil)
 	if err != nil {
-		return -1, err
+		return nil, err
 	}
 
+	return submittedJob(resp)
+}
+
+// submittedJob returns the job of a Print-Job or Create-Job response including the unsupported attributes
+func submittedJob(resp *Response) (*Job, error) {
 	if len(resp.JobAttributes) == 0 {
-		return 0, errors.New("server doesn't returned a job id")
+		return nil, errors.New("server doesn't returned a job id")
+	}
+
+	job := new(Job)
+	if err := job.Unmarshal(resp.JobAttributes[0]); err != nil {
+		return nil, err
+	}
+	if job.ID == 0 {
+		return nil, errors.New("server doesn't returned a job id")
+	}
+	job.Unsupported = mergeUnsupported(nil, resp.UnsupportedAttributes)
+
+	return job, nil
+}
+
+// mergeUnsupported adds the unsupported attributes of a response to the already collected ones
+func mergeUnsupported(unsupported, attributes Attributes) Attributes {
+	if len(attributes) == 0 {
+		return unsupported
 	}
 
-	jobID := resp.JobAttributes[0][AttributeJobID][0].Value.(int)
+	if unsupported == nil {
+		unsupported = make(Attributes, len(attributes))
+	}
+	for name, values := range attributes {
+		unsupported[name] = values
+	}
 
-	return jobID, nil
+	return unsupported
 }
 
 // PrintFile prints a local file on the file system. custom job settings can be specified via the jobAttributes parameter
@@ -175,9 +489,41 @@ func (c *IPPClient) PrintFile(filePath, printer string, jobAttributes map[string
 
 // GetPrinterAttributes returns the requested attributes for the specified printer, if attributes is nil the default attributes will be used
 func (c *IPPClient) GetPrinterAttributes(printer string, attributes []string) (Attributes, error) {
+	resp, err := c.getPrinterAttributes(printer, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.PrinterAttributes[0], nil
+}
+
+// QueryPrinterAttributes returns the printer attributes selected by the options, e.g.
+//
+//	client.QueryPrinterAttributes("office", WithGroups(RequestedJobTemplate, RequestedMediaColDatabase))
+//
+// the default attributes are requested if no option sets requested-attributes
+func (c *IPPClient) QueryPrinterAttributes(printer string, opts ...RequestOption) (Attributes, error) {
+	opts = append([]RequestOption{WithPrinterURI(c.getPrinterUri(printer))}, opts...)
+	req := NewRequest(OperationGetPrinterAttributes, 1, opts...)
+	if _, ok := req.OperationAttributes[AttributeRequestedAttributes]; !ok {
+		req.OperationAttributes[AttributeRequestedAttributes] = DefaultPrinterAttributes
+	}
+
+	resp, err := c.SendRequest(c.adapter.GetHttpUri("printers", printer), req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.PrinterAttributes) == 0 {
+		return nil, errors.New("server doesn't return any printer attributes")
+	}
+
+	return resp.PrinterAttributes[0], nil
+}
+
+func (c *IPPClient) getPrinterAttributes(printer string, attributes []string) (*Response, error) {
 	req := NewRequest(OperationGetPrinterAttributes, 1)
 	req.OperationAttributes[AttributePrinterURI] = c.getPrinterUri(printer)
-	req.OperationAttributes[AttributeRequestingUserName] = c.username
 
 	if attributes == nil {
 		req.OperationAttributes[AttributeRequestedAttributes] = DefaultPrinterAttributes
@@ -194,7 +540,23 @@ func (c *IPPClient) GetPrinterAttributes(printer string, attributes []string) (A
 		return nil, errors.New("server doesn't return any printer attributes")
 	}
 
-	return resp.PrinterAttributes[0], nil
+	return resp, nil
+}
+
+// GetPrinterDescription requests all attributes of the specified printer and returns them in typed form
+func (c *IPPClient) GetPrinterDescription(printer string) (*PrinterDescription, error) {
+	resp, err := c.getPrinterAttributes(printer, []string{"all"})
+	if err != nil {
+		return nil, err
+	}
+
+	d := new(PrinterDescription)
+	if err := d.Unmarshal(resp.PrinterAttributes[0]); err != nil {
+		return nil, err
+	}
+	d.Unsupported = mergeUnsupported(nil, resp.UnsupportedAttributes)
+
+	return d, nil
 }
 
 // ResumePrinter resumes a printer
@@ -217,6 +579,15 @@ func (c *IPPClient) PausePrinter(printer string) error {
 
 // GetJobAttributes returns the requested attributes for the specified job, if attributes is nil the default job will be used
 func (c *IPPClient) GetJobAttributes(jobID int, attributes []string) (Attributes, error) {
+	resp, err := c.getJobAttributes(jobID, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.JobAttributes[0], nil
+}
+
+func (c *IPPClient) getJobAttributes(jobID int, attributes []string) (*Response, error) {
 	req := NewRequest(OperationGetJobAttributes, 1)
 	req.OperationAttributes[AttributeJobURI] = c.getJobUri(jobID)
 
@@ -235,7 +606,23 @@ func (c *IPPClient) GetJobAttributes(jobID int, attributes []string) (Attributes
 		return nil, errors.New("server doesn't return any job attributes")
 	}
 
-	return resp.JobAttributes[0], nil
+	return resp, nil
+}
+
+// GetJob requests all attributes of the specified job and returns them in typed form
+func (c *IPPClient) GetJob(jobID int) (*Job, error) {
+	resp, err := c.getJobAttributes(jobID, []string{"all"})
+	if err != nil {
+		return nil, err
+	}
+
+	job := new(Job)
+	if err := job.Unmarshal(resp.JobAttributes[0]); err != nil {
+		return nil, err
+	}
+	job.Unsupported = mergeUnsupported(nil, resp.UnsupportedAttributes)
+
+	return job, nil
 }
 
 // GetJobs returns jobs from a printer or class
@@ -260,10 +647,6 @@ func (c *IPPClient) GetJobs(printer, class string, whichJobs string, myJobs bool
 		req.OperationAttributes[AttributeLimit] = limit
 	}
 
-	if myJobs {
-		req.OperationAttributes[AttributeRequestingUserName] = c.username
-	}
-
 	if attributes == nil {
 		req.OperationAttributes[AttributeRequestedAttributes] = DefaultJobAttributes
 	} else {
@@ -296,6 +679,10 @@ func (c *IPPClient) CancelJob(jobID int, purge bool) error {
 
 // CancelAllJob cancels all jobs for a specified printer. if purge is true, the jobs will also be removed
 func (c *IPPClient) CancelAllJob(printer string, purge bool) error {
+	if supported, known := c.supportsOperation(printer, OperationCancelJobs); known && !supported {
+		return fmt.Errorf("%w: %s", OperationNotSupportedError, Operation(OperationCancelJobs))
+	}
+
 	req := NewRequest(OperationCancelJobs, 1)
 	req.OperationAttributes[AttributePrinterURI] = c.getPrinterUri(printer)
 	req.OperationAttributes[AttributePurgeJobs] = purge
@@ -323,6 +710,15 @@ func (c *IPPClient) HoldJobUntil(jobID int, holdUntil string) error {
 	return err
 }
 
+// ReleaseJob releases a held job, e.g. after its proof copies were checked
+func (c *IPPClient) ReleaseJob(jobID int) error {
+	req := NewRequest(OperationReleaseJob, 1)
+	req.OperationAttributes[AttributeJobURI] = c.getJobUri(jobID)
+
+	_, err := c.SendRequest(c.adapter.GetHttpUri("jobs", ""), req, nil)
+	return err
+}
+
 // TestConnection tests if a tcp connection to the remote server is possible
 func (c *IPPClient) TestConnection() error {
 	return c.adapter.TestConnection()
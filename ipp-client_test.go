@@ -0,0 +1,240 @@
+package ipp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testAdapter records sent requests and answers them with a preset response
+type testAdapter struct {
+	requests []*Request
+	response *Response
+	// requestID overrides the echoed request id if not zero
+	requestID int32
+	// respond creates the response if set
+	respond func(req *Request) *Response
+}
+
+func (a *testAdapter) SendRequest(url string, req *Request, additionalResponseData io.Writer) (*Response, error) {
+	a.requests = append(a.requests, req)
+
+	if a.respond != nil {
+		resp := a.respond(req)
+		resp.RequestId = req.RequestId
+		return resp, nil
+	}
+
+	if a.response != nil {
+		resp := *a.response
+		resp.RequestId = req.RequestId
+		return &resp, nil
+	}
+
+	resp := NewResponse(StatusOk, req.RequestId)
+	if a.requestID != 0 {
+		resp.RequestId = a.requestID
+	}
+	resp.JobAttributes = append(resp.JobAttributes, Attributes{
+		AttributeJobID: []Attribute{{Tag: TagInteger, Name: AttributeJobID, Value: 42}},
+	})
+	return resp, nil
+}
+
+func (a *testAdapter) GetHttpUri(namespace string, object interface{}) string {
+	return "http://localhost:631/" + namespace
+}
+
+func (a *testAdapter) TestConnection() error {
+	return nil
+}
+
+func TestIPPClient_DefaultAttributes(t *testing.T) {
+	adapter := &testAdapter{}
+	client := NewIPPClientWithAdapter("user", adapter)
+	client.SetDefaultOperationAttributes(map[string]interface{}{
+		AttributeRequestingUserName: "template-user",
+	})
+	client.SetDefaultJobAttributes(map[string]interface{}{
+		AttributeSides: "two-sided-long-edge",
+		AttributeMedia: "iso_a4_210x297mm",
+	})
+
+	_, err := client.PrintJob(Document{Name: "doc", Size: -1}, "printer", map[string]interface{}{
+		AttributeMedia: "na_letter_8.5x11in",
+	})
+	assert.Nil(t, err)
+
+	req := adapter.requests[0]
+	assert.Equal(t, "template-user", req.OperationAttributes[AttributeRequestingUserName])
+	assert.Equal(t, "two-sided-long-edge", req.JobAttributes[AttributeSides])
+	assert.Equal(t, "na_letter_8.5x11in", req.JobAttributes[AttributeMedia])
+
+	assert.Nil(t, client.PausePrinter("printer"))
+
+	req = adapter.requests[1]
+	assert.Equal(t, "template-user", req.OperationAttributes[AttributeRequestingUserName])
+	assert.Empty(t, req.JobAttributes)
+}
+
+func TestIPPClient_SubmitJob(t *testing.T) {
+	resp := NewResponse(StatusOkIgnoredOrSubstituted, 1)
+	resp.JobAttributes = append(resp.JobAttributes, Attributes{
+		AttributeJobID:    []Attribute{{Tag: TagInteger, Value: 7}},
+		AttributeJobState: []Attribute{{Tag: TagEnum, Value: int(JobStatePending)}},
+	})
+	resp.UnsupportedAttributes = Attributes{
+		AttributeSides: []Attribute{{Tag: TagKeyword, Value: "two-sided-long-edge"}},
+	}
+
+	client := NewIPPClientWithAdapter("user", &testAdapter{response: resp})
+	job, err := client.SubmitJob(Document{Name: "doc", Size: -1}, "printer", map[string]interface{}{
+		AttributeSides: "two-sided-long-edge",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 7, job.ID)
+	assert.Equal(t, JobStatePending, job.State)
+	assert.Equal(t, "two-sided-long-edge", job.Unsupported[AttributeSides][0].Value)
+
+	jobID, err := client.PrintDocuments([]Document{{Name: "doc", Size: -1}}, "printer", map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Equal(t, 7, jobID)
+}
+
+func TestIPPClient_RequestID(t *testing.T) {
+	adapter := &testAdapter{}
+	client := NewIPPClientWithAdapter("user", adapter)
+
+	assert.Nil(t, client.PausePrinter("office"))
+	assert.Nil(t, client.ResumePrinter("office"))
+	assert.Nil(t, client.CancelJob(1, false))
+	_, err := client.SendRequest("http://localhost:631/admin", NewRequest(OperationPausePrinter, 99,
+		WithPrinterURI("ipp://localhost:631/printers/office")), nil)
+	assert.Nil(t, err)
+
+	var ids []int32
+	for _, req := range adapter.requests {
+		ids = append(ids, req.RequestId)
+	}
+	assert.Equal(t, []int32{1, 2, 1, 3}, ids)
+
+	client.requestIDs["http://localhost:631/admin"] = math.MaxInt32
+	assert.Equal(t, int32(1), client.nextRequestID("http://localhost:631/admin"))
+
+	adapter.requestID = 12345
+	err = client.PausePrinter("office")
+	assert.True(t, errors.Is(err, RequestIDMismatchError))
+}
+
+func TestIPPClient_MissingTarget(t *testing.T) {
+	adapter := &testAdapter{}
+	client := NewIPPClientWithAdapter("user", adapter)
+
+	_, err := client.SendRequest("http://localhost:631/admin", NewRequest(OperationPausePrinter, 1), nil)
+	assert.True(t, errors.Is(err, MissingTargetError))
+	assert.Empty(t, adapter.requests)
+}
+
+func TestIPPClient_DefaultTarget(t *testing.T) {
+	adapter := &testAdapter{}
+	client := NewIPPClientWithAdapter("user", adapter)
+	client.SetDefaultOperationAttributes(map[string]interface{}{
+		AttributePrinterURI: "ipp://localhost:631/printers/test",
+	})
+
+	_, err := client.SendRequest("http://localhost:631/printers/test", NewRequest(OperationPausePrinter, 1), nil)
+	assert.Nil(t, err)
+	assert.Len(t, adapter.requests, 1)
+}
+
+func TestIPPClient_StatusCheck(t *testing.T) {
+	resp := NewResponse(StatusErrorNotFound, 1)
+	resp.OperationAttributes[AttributeStatusMessage] = []Attribute{{Tag: TagText, Value: "no such printer"}}
+	client := NewIPPClientWithAdapter("user", &testAdapter{response: resp})
+
+	received, err := client.SendRequest("http://localhost:631/printers/missing",
+		NewRequest(OperationGetPrinterAttributes, 1, WithPrinterURI("ipp://localhost:631/printers/missing")), nil)
+	assert.True(t, IsNotFound(err))
+	assert.Equal(t, StatusErrorNotFound, received.StatusCode)
+
+	var statusErr StatusError
+	assert.True(t, errors.As(client.PausePrinter("missing"), &statusErr))
+	assert.Equal(t, "no such printer", statusErr.Message)
+	assert.Equal(t, StatusErrorNotFound, statusErr.Response.StatusCode)
+
+	client.SetRawResponses(true)
+	received, err = client.SendRequest("http://localhost:631/printers/missing",
+		NewRequest(OperationGetPrinterAttributes, 1, WithPrinterURI("ipp://localhost:631/printers/missing")), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusErrorNotFound, received.StatusCode)
+}
+
+func TestIPPClient_QueryPrinterAttributes(t *testing.T) {
+	resp := NewResponse(StatusOk, 1)
+	printer := make(Attributes)
+	printer.Set(AttributeCopiesDefault, TagInteger, 1)
+	resp.PrinterAttributes = append(resp.PrinterAttributes, printer)
+
+	adapter := &testAdapter{response: resp}
+	client := NewIPPClientWithAdapter("user", adapter)
+
+	attributes, err := client.QueryPrinterAttributes("office", WithGroups(RequestedJobTemplate))
+	assert.Nil(t, err)
+	assert.Equal(t, printer, attributes)
+
+	_, err = client.QueryPrinterAttributes("office")
+	assert.Nil(t, err)
+
+	assert.Equal(t, "ipp://localhost/printers/office", adapter.requests[0].OperationAttributes[AttributePrinterURI])
+	assert.Equal(t, []string{RequestedJobTemplate}, adapter.requests[0].OperationAttributes[AttributeRequestedAttributes])
+	assert.Equal(t, DefaultPrinterAttributes, adapter.requests[1].OperationAttributes[AttributeRequestedAttributes])
+}
+
+func TestIPPClient_SetLogger(t *testing.T) {
+	var log bytes.Buffer
+	adapter := &testAdapter{}
+	client := NewIPPClientWithAdapter("user", adapter)
+	client.SetLogger(slog.New(slog.NewTextHandler(&log, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	_, err := client.PrintJob(Document{Name: "doc", Size: -1}, "printer", nil)
+	assert.Nil(t, err)
+
+	adapter.respond = func(req *Request) *Response {
+		resp := NewResponse(StatusOkIgnoredOrSubstituted, req.RequestId)
+		resp.JobAttributes = append(resp.JobAttributes, Attributes{
+			AttributeJobID: []Attribute{{Tag: TagInteger, Value: 7}},
+		})
+		resp.UnsupportedAttributes = Attributes{
+			AttributeSides: []Attribute{{Tag: TagKeyword, Value: "two-sided-long-edge"}},
+		}
+		return resp
+	}
+	_, err = client.PrintJob(Document{Name: "doc", Size: -1}, "printer", nil)
+	assert.Nil(t, err)
+
+	adapter.respond = func(req *Request) *Response {
+		resp := NewResponse(StatusErrorNotFound, req.RequestId)
+		resp.OperationAttributes.Set(AttributeStatusMessage, TagText, "no such job")
+		return resp
+	}
+	assert.NotNil(t, client.CancelJob(1, false))
+
+	client.SetLogger(nil)
+	assert.NotNil(t, client.CancelJob(1, false))
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.Contains(t, lines[0], `level=DEBUG msg="ipp request" operation=Print-Job url=http://localhost:631/printers request-id=1`)
+	assert.Contains(t, lines[0], "status=successful-ok")
+	assert.Contains(t, lines[1], "status=successful-ok-ignored-or-substituted-attributes")
+	assert.Contains(t, lines[2], `level=WARN msg="printer ignored or substituted attributes" operation=Print-Job`)
+	assert.Contains(t, lines[2], "attributes=[sides]")
+	assert.Contains(t, lines[3], `level=WARN msg="ipp request" operation=Cancel-Job`)
+	assert.Contains(t, lines[3], `status=client-error-not-found status-message="no such job"`)
+}
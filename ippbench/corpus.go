@@ -0,0 +1,171 @@
+package ippbench
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/pwg"
+)
+
+// the sizes of the messages of Corpus
+const (
+	// DefaultJobs is the number of jobs of the Get-Jobs response
+	DefaultJobs = 500
+	// DefaultMediaSizes is the number of media sizes of the Get-Printer-Attributes response, each size is listed
+	// with two media types and two sources in media-col-database
+	DefaultMediaSizes = 60
+	// DefaultRasterPages is the number of pages of the raster job
+	DefaultRasterPages = 4
+)
+
+// Corpus returns the representative messages: a small request, a huge Get-Printer-Attributes response, a Get-Jobs
+// response with 500 jobs and a Print-Job request with a pwg raster document
+func Corpus() []Message {
+	return []Message{
+		SmallRequest(),
+		PrinterAttributes(DefaultMediaSizes),
+		Jobs(DefaultJobs),
+		RasterJob(DefaultRasterPages),
+	}
+}
+
+// SmallRequest returns a Get-Job-Attributes request as sent when polling the state of a job
+func SmallRequest() Message {
+	req := ipp.NewRequest(ipp.OperationGetJobAttributes, 1,
+		ipp.WithPrinterURI("ipp://printer.example.com/ipp/print"),
+		ipp.WithJobID(42),
+		ipp.WithUser("alice"),
+		ipp.WithRequestedAttributes(ipp.AttributeJobState, ipp.AttributeJobStateReasons,
+			ipp.AttributeJobImpressionsCompleted),
+	)
+
+	return Message{Name: "small-request", Request: req}
+}
+
+// PrinterAttributes returns the Get-Printer-Attributes response of a printer with the number of media sizes
+func PrinterAttributes(mediaSizes int) Message {
+	sizes := ipp.MediaSizes[:min(mediaSizes, len(ipp.MediaSizes))]
+
+	attributes := make(ipp.Attributes)
+	attributes.Set(ipp.AttributeCharsetSupported, ipp.TagCharset, "utf-8")
+	attributes.Set(ipp.AttributeNaturalLanguageConfigured, ipp.TagLanguage, "en")
+	attributes.Set(ipp.AttributeIppVersionsSupported, ipp.TagKeyword, "1.0", "1.1", "2.0")
+	attributes.Set(ipp.AttributePrinterName, ipp.TagName, "office")
+	attributes.Set(ipp.AttributePrinterInfo, ipp.TagText, "Office printer")
+	attributes.Set(ipp.AttributePrinterLocation, ipp.TagText, "2nd floor")
+	attributes.Set(ipp.AttributePrinterGeoLocation, ipp.TagUri, "geo:52.52,13.40")
+	attributes.Set(ipp.AttributePrinterMakeAndModel, ipp.TagText, "Example Laser 4000")
+	attributes.Set(ipp.AttributePrinterMoreInfo, ipp.TagUri, "http://printer.example.com/")
+	attributes.Set(ipp.AttributePrinterUUID, ipp.TagUri, "urn:uuid:0b5a8d58-1a8c-4b4e-9d7a-5e0a8c7a2f10")
+	attributes.Set(ipp.AttributePrinterUriSupported, ipp.TagUri,
+		"ipp://printer.example.com/ipp/print", "ipps://printer.example.com/ipp/print")
+	attributes.Set(ipp.AttributePrinterState, ipp.TagEnum, int(ipp.PrinterStateIdle))
+	attributes.Set(ipp.AttributePrinterStateReasons, ipp.TagKeyword, "none")
+	attributes.Set(ipp.AttributePrinterIsAcceptingJobs, ipp.TagBoolean, true)
+	attributes.Set(ipp.AttributeOperationsSupported, ipp.TagEnum, int(ipp.OperationPrintJob),
+		int(ipp.OperationValidateJob), int(ipp.OperationCreateJob), int(ipp.OperationSendDocument),
+		int(ipp.OperationCancelJob), int(ipp.OperationGetJobAttributes), int(ipp.OperationGetJobs),
+		int(ipp.OperationGetPrinterAttributes))
+	attributes.Set(ipp.AttributeDocumentFormatSupported, ipp.TagMimeType, ipp.MimeTypePDF, "image/jpeg",
+		ipp.MimeTypePwgRaster, ipp.MimeTypeUrf, ipp.MimeTypeOctetStream)
+	attributes.Set(ipp.AttributeCopiesSupported, ipp.TagRange, []int32{1, 999})
+	attributes.Set(ipp.AttributeSidesSupported, ipp.TagKeyword, ipp.SidesOneSided, "two-sided-long-edge",
+		"two-sided-short-edge")
+	attributes.Set(ipp.AttributePrintQualitySupported, ipp.TagEnum, 3, 4, 5)
+	attributes.Set(ipp.AttributeFinishingsSupported, ipp.TagEnum, 3, 4, 5, 20, 21, 22)
+	attributes.Set(ipp.AttributeOutputBinSupported, ipp.TagKeyword, "face-down", "face-up")
+	attributes.Set(ipp.AttributeMediaSourceSupported, ipp.TagKeyword, "main", "manual")
+	attributes.Set(ipp.AttributeMediaTypeSupported, ipp.TagKeyword, "stationery", "photographic")
+	attributes.Set(ipp.AttributePrinterResolutionSupported, ipp.TagResolution,
+		ipp.Resolution{Height: 300, Width: 300, Depth: 3}, ipp.Resolution{Height: 600, Width: 600, Depth: 3})
+	attributes.Set(ipp.AttributePwgRasterDocumentResolutionSupported, ipp.TagResolution,
+		ipp.Resolution{Height: 300, Width: 300, Depth: 3}, ipp.Resolution{Height: 600, Width: 600, Depth: 3})
+	attributes.Set(ipp.AttributePwgRasterDocumentTypeSupported, ipp.TagKeyword, pwg.TypeBlack1, pwg.TypeSGray8,
+		pwg.TypeSRGB8)
+
+	var media, database []interface{}
+	for _, size := range sizes {
+		media = append(media, size.Name)
+		for _, source := range []string{"main", "manual"} {
+			for _, mediaType := range []string{"stationery", "photographic"} {
+				col := ipp.MediaCol{SizeName: size.Name, Width: size.Width, Height: size.Height}
+				database = append(database,
+					col.WithSource(source).WithType(mediaType).WithMargins(423, 423, 423, 423).Collection())
+			}
+		}
+	}
+	attributes.Set(ipp.AttributeMediaSupported, ipp.TagKeyword, media...)
+	attributes.Set(ipp.AttributeMediaColDatabase, ipp.TagBeginCollection, database...)
+
+	resp := ipp.NewResponse(ipp.StatusOk, 1)
+	resp.PrinterAttributes = append(resp.PrinterAttributes, attributes)
+
+	return Message{Name: fmt.Sprintf("get-printer-attributes-%d-sizes", len(sizes)), Response: resp}
+}
+
+// Jobs returns the Get-Jobs response of a printer with the number of jobs
+func Jobs(jobs int) Message {
+	resp := ipp.NewResponse(ipp.StatusOk, 1)
+
+	users := []string{"alice", "bob", "carol", "dave"}
+	for id := 1; id <= jobs; id++ {
+		job := make(ipp.Attributes)
+		job.Set(ipp.AttributeJobID, ipp.TagInteger, id)
+		job.Set(ipp.AttributeJobURI, ipp.TagUri, fmt.Sprintf("ipp://printer.example.com/ipp/print/%d", id))
+		job.Set(ipp.AttributeJobPrinterURI, ipp.TagUri, "ipp://printer.example.com/ipp/print")
+		job.Set(ipp.AttributeJobName, ipp.TagName, fmt.Sprintf("invoice-%05d.pdf", id))
+		job.Set(ipp.AttributeJobOriginatingUserName, ipp.TagName, users[id%len(users)])
+		job.Set(ipp.AttributeJobState, ipp.TagEnum, int(ipp.JobStateCompleted))
+		job.Set(ipp.AttributeJobStateReasons, ipp.TagKeyword, "job-completed-successfully")
+		job.Set(ipp.AttributeJobImpressionsCompleted, ipp.TagInteger, id%7+1)
+		job.Set(ipp.AttributeTimeAtCreation, ipp.TagInteger, 1000+id*60)
+		job.Set(ipp.AttributeTimeAtCompleted, ipp.TagInteger, 1030+id*60)
+		resp.JobAttributes = append(resp.JobAttributes, job)
+	}
+
+	return Message{Name: fmt.Sprintf("get-jobs-%d", jobs), Response: resp}
+}
+
+// RasterJob returns a Print-Job request with a letter sized pwg raster document at 300 dpi with the number of pages
+func RasterJob(pages int) Message {
+	// a page of text lines within margins, it compresses like a typical text document
+	const width, height = 2550, 3300
+	page := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := uint8(255)
+			if x >= 150 && x < width-150 && y >= 150 && y < height-150 && y%50 < 30 && (x*x/13+y/50)%5 == 0 {
+				c = uint8(x % 3 * 60)
+			}
+			page.SetGray(x, y, color.Gray{Y: c})
+		}
+	}
+
+	images := make([]image.Image, pages)
+	for i := range images {
+		images[i] = page
+	}
+
+	var document bytes.Buffer
+	// the options are valid, encoding into a buffer can't fail
+	_ = pwg.Encode(&document, images, pwg.Options{PageSizeName: "na_letter_8.5x11in"})
+
+	req := ipp.NewRequest(ipp.OperationPrintJob, 1,
+		ipp.WithPrinterURI("ipp://printer.example.com/ipp/print"),
+		ipp.WithUser("alice"),
+		ipp.WithOperationAttributes(map[string]interface{}{
+			ipp.AttributeJobName:        "scan.pwg",
+			ipp.AttributeDocumentFormat: ipp.MimeTypePwgRaster,
+		}),
+		ipp.WithJobAttributes(map[string]interface{}{
+			ipp.AttributeMedia:        "na_letter_8.5x11in",
+			ipp.AttributeSides:        ipp.SidesOneSided,
+			ipp.AttributePrintQuality: 4,
+		}),
+	)
+
+	return Message{Name: fmt.Sprintf("raster-job-%d-pages", pages), Request: req, Document: document.Bytes()}
+}
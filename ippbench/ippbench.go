@@ -0,0 +1,124 @@
+// Package ippbench provides representative ipp messages and benchmark helpers to measure the encode and decode
+// throughput of the ipp package and to catch performance regressions, e.g.
+//
+//	func BenchmarkIPP(b *testing.B) {
+//		ippbench.Run(b, ippbench.Corpus())
+//	}
+package ippbench
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// Message is a request or a response of the corpus
+type Message struct {
+	Name string
+	// Request or Response is set
+	Request  *ipp.Request
+	Response *ipp.Response
+	// Document is the data following the message, e.g. the document of a Print-Job request
+	Document []byte
+}
+
+// Encode encodes the message followed by the document
+func (m Message) Encode() ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch {
+	case m.Request != nil:
+		data, err = m.Request.Encode()
+	case m.Response != nil:
+		data, err = m.Response.Encode()
+	default:
+		return nil, errors.New("message has neither a request nor a response")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, m.Document...), nil
+}
+
+// Decode decodes encoded data of the message, the document is discarded
+func (m Message) Decode(data []byte) error {
+	if m.Request != nil {
+		_, err := ipp.NewRequestDecoder(bytes.NewReader(data)).Decode(io.Discard)
+		return err
+	}
+
+	_, err := ipp.NewResponseDecoder(bytes.NewReader(data)).Decode(io.Discard)
+	return err
+}
+
+// BenchmarkEncode measures the encoding of the message, the throughput includes the document
+func BenchmarkEncode(b *testing.B, m Message) {
+	data, err := m.Encode()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := m.Encode(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkDecode measures the decoding of the encoded message, the throughput includes the document
+func BenchmarkDecode(b *testing.B, m Message) {
+	data, err := m.Encode()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := m.Decode(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Run runs the encode and decode benchmarks of the messages as sub-benchmarks named <message>/encode and
+// <message>/decode
+func Run(b *testing.B, messages []Message) {
+	for _, m := range messages {
+		b.Run(m.Name+"/encode", func(b *testing.B) { BenchmarkEncode(b, m) })
+		b.Run(m.Name+"/decode", func(b *testing.B) { BenchmarkDecode(b, m) })
+	}
+}
+
+// Allocs is the average number of allocations to encode and decode a message
+type Allocs struct {
+	Encode float64
+	Decode float64
+}
+
+// MeasureAllocs returns the average number of allocations to encode and decode the message over runs, it can be used
+// in tests to keep the allocations of a message below a budget
+func MeasureAllocs(m Message, runs int) (Allocs, error) {
+	data, err := m.Encode()
+	if err != nil {
+		return Allocs{}, err
+	}
+	if err := m.Decode(data); err != nil {
+		return Allocs{}, err
+	}
+
+	return Allocs{
+		Encode: testing.AllocsPerRun(runs, func() { _, _ = m.Encode() }),
+		Decode: testing.AllocsPerRun(runs, func() { _ = m.Decode(data) }),
+	}, nil
+}
@@ -0,0 +1,44 @@
+package ippbench
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorpus(t *testing.T) {
+	for _, m := range Corpus() {
+		data, err := m.Encode()
+		assert.Nil(t, err, m.Name)
+		assert.Nil(t, m.Decode(data), m.Name)
+		assert.True(t, (m.Request == nil) != (m.Response == nil), m.Name)
+	}
+
+	m := RasterJob(1)
+	assert.Equal(t, "RaS2", string(m.Document[:4]))
+
+	_, err := Message{Name: "empty"}.Encode()
+	assert.NotNil(t, err)
+}
+
+// TestAllocs keeps the allocations of the corpus below budgets with some headroom, a failure is a performance
+// regression or, if the allocations went down, a reason to lower the budget
+func TestAllocs(t *testing.T) {
+	budgets := map[string]Allocs{
+		"small-request":                   {Encode: 8, Decode: 40},
+		"get-printer-attributes-60-sizes": {Encode: 400, Decode: 10000},
+		"get-jobs-500":                    {Encode: 40, Decode: 18000},
+		"raster-job-4-pages":              {Encode: 8, Decode: 40},
+	}
+
+	for _, m := range Corpus() {
+		allocs, err := MeasureAllocs(m, 5)
+		assert.Nil(t, err, m.Name)
+		assert.LessOrEqual(t, allocs.Encode, budgets[m.Name].Encode, m.Name)
+		assert.LessOrEqual(t, allocs.Decode, budgets[m.Name].Decode, m.Name)
+	}
+}
+
+func BenchmarkCorpus(b *testing.B) {
+	Run(b, Corpus())
+}
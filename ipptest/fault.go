@@ -0,0 +1,143 @@
+package ipptest
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// Fault is a misbehavior of real printers the fake printer can be told to show, see Printer.InjectFault
+type Fault int
+
+const (
+	// FaultTruncate cuts the ipp response in half, the http response itself is complete
+	FaultTruncate Fault = iota + 1
+	// FaultWrongRequestID echoes another request-id than the one of the request
+	FaultWrongRequestID
+	// FaultRefuseContinue answers with 417 Expectation Failed before the request body is read, like printers which
+	// don't support Expect: 100-continue. the request is not recorded
+	FaultRefuseContinue
+	// FaultResetConnection resets the connection in the middle of the response body
+	FaultResetConnection
+	// FaultBogusValueTags sends the values of the job and printer attributes with wrong value tags, integers as
+	// enums and vice versa and strings with the reserved tag 0x43
+	FaultBogusValueTags
+)
+
+func (f Fault) String() string {
+	switch f {
+	case FaultTruncate:
+		return "truncate"
+	case FaultWrongRequestID:
+		return "wrong-request-id"
+	case FaultRefuseContinue:
+		return "refuse-continue"
+	case FaultResetConnection:
+		return "reset-connection"
+	case FaultBogusValueTags:
+		return "bogus-value-tags"
+	}
+
+	return fmt.Sprintf("fault(%d)", int(f))
+}
+
+// InjectFault makes the next request fail with the fault, whatever its operation. calls queue up, so a sequence of
+// faults can be scripted, e.g. to test the retries of a client
+func (p *Printer) InjectFault(fault Fault) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.faults = append(p.faults, fault)
+}
+
+// nextFault returns and removes the next injected fault, zero if none is pending
+func (p *Printer) nextFault() Fault {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.faults) == 0 {
+		return 0
+	}
+
+	fault := p.faults[0]
+	p.faults = p.faults[1:]
+	return fault
+}
+
+// applyFault changes the response before it is encoded
+func applyFault(fault Fault, resp *ipp.Response) {
+	switch fault {
+	case FaultWrongRequestID:
+		resp.RequestId++
+	case FaultBogusValueTags:
+		resp.PrinterAttributes = bogusGroups(resp.PrinterAttributes)
+		resp.JobAttributes = bogusGroups(resp.JobAttributes)
+	}
+}
+
+// writeFault writes the encoded response as the fault requires, false if the response is to be written as usual
+func writeFault(fault Fault, w http.ResponseWriter, payload []byte) bool {
+	switch fault {
+	case FaultTruncate:
+		w.Header().Set("Content-Type", ipp.ContentTypeIPP)
+		_, _ = w.Write(truncate(payload))
+		return true
+	case FaultResetConnection:
+		conn, buf, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			return false
+		}
+		_, _ = fmt.Fprintf(buf, "HTTP/1.1 200 OK\r\nContent-Type: %s\r\nContent-Length: %d\r\n\r\n",
+			ipp.ContentTypeIPP, len(payload))
+		_, _ = buf.Write(payload[:len(payload)/2])
+		_ = buf.Flush()
+		// without lingering the close sends a tcp reset
+		if tcp, ok := conn.(*net.TCPConn); ok {
+			_ = tcp.SetLinger(0)
+		}
+		_ = conn.Close()
+		return true
+	}
+
+	return false
+}
+
+// truncate cuts the payload in half. the decoder tolerates a missing end tag, so the cut is moved back until it
+// falls into an attribute and the response can't be decoded
+func truncate(payload []byte) []byte {
+	n := len(payload) / 2
+	for ; n > 1; n-- {
+		if _, err := ipp.NewResponseDecoder(bytes.NewReader(payload[:n])).Decode(nil); err != nil {
+			break
+		}
+	}
+
+	return payload[:n]
+}
+
+// bogusGroups copies the attribute groups with wrong value tags, the groups of the printer are left unchanged
+func bogusGroups(groups []ipp.Attributes) []ipp.Attributes {
+	bogus := make([]ipp.Attributes, len(groups))
+	for i, group := range groups {
+		bogus[i] = make(ipp.Attributes, len(group))
+		for name, values := range group {
+			bogus[i][name] = make([]ipp.Attribute, len(values))
+			for j, value := range values {
+				switch value.Tag {
+				case ipp.TagInteger:
+					value.Tag = ipp.TagEnum
+				case ipp.TagEnum:
+					value.Tag = ipp.TagInteger
+				case ipp.TagText, ipp.TagName, ipp.TagKeyword, ipp.TagUri, ipp.TagMimeType:
+					value.Tag = ipp.TagReservedString
+				}
+				bogus[i][name][j] = value
+			}
+		}
+	}
+
+	return bogus
+}
@@ -0,0 +1,69 @@
+package ipptest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/phin1x/go-ipp"
+)
+
+func TestPrinter_InjectFault(t *testing.T) {
+	tests := []struct {
+		fault Fault
+		check func(t *testing.T, attributes ipp.Attributes, err error)
+	}{
+		{
+			fault: FaultTruncate,
+			check: func(t *testing.T, attributes ipp.Attributes, err error) {
+				assert.ErrorContains(t, err, "unable to decode IPP response")
+			},
+		},
+		{
+			fault: FaultWrongRequestID,
+			check: func(t *testing.T, attributes ipp.Attributes, err error) {
+				assert.True(t, errors.Is(err, ipp.RequestIDMismatchError))
+			},
+		},
+		{
+			fault: FaultRefuseContinue,
+			check: func(t *testing.T, attributes ipp.Attributes, err error) {
+				var httpErr ipp.HTTPError
+				assert.True(t, errors.As(err, &httpErr))
+				assert.Equal(t, 417, httpErr.Code)
+			},
+		},
+		{
+			fault: FaultResetConnection,
+			check: func(t *testing.T, attributes ipp.Attributes, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+		{
+			fault: FaultBogusValueTags,
+			check: func(t *testing.T, attributes ipp.Attributes, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, ipp.TagReservedString, attributes[ipp.AttributePrinterMakeAndModel][0].Tag)
+				assert.Equal(t, ipp.TagInteger, attributes[ipp.AttributePrinterState][0].Tag)
+				assert.Equal(t, "Fake Printer", attributes[ipp.AttributePrinterMakeAndModel][0].Value)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fault.String(), func(t *testing.T) {
+			p := NewPrinter(t)
+			client := p.Client()
+
+			p.InjectFault(test.fault)
+			attributes, err := client.GetPrinterAttributes("office", nil)
+			test.check(t, attributes, err)
+
+			// the fault only affects a single request
+			attributes, err = client.GetPrinterAttributes("office", nil)
+			assert.Nil(t, err)
+			assert.Equal(t, ipp.TagEnum, attributes[ipp.AttributePrinterState][0].Tag)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package ipptest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+)
+
+var update = flag.Bool("ipptest.update", false, "rewrite the golden files of AssertGolden instead of comparing them")
+
+// AssertGolden encodes msg, a Request or Response, and compares it with the golden file at path. the message is
+// decoded back from the golden file and must be semantically equal to msg. run the tests with -ipptest.update to
+// write the golden files, e.g.
+//
+//	go test ./... -args -ipptest.update
+func AssertGolden(t testing.TB, path string, msg interface{}) bool {
+	t.Helper()
+
+	encoded, err := encodeMessage(msg)
+	if err != nil {
+		t.Errorf("cannot encode %s: %v", path, err)
+		return false
+	}
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Errorf("cannot create the directory of %s: %v", path, err)
+			return false
+		}
+		if err := os.WriteFile(path, encoded, 0644); err != nil {
+			t.Errorf("cannot write %s: %v", path, err)
+			return false
+		}
+		return true
+	}
+
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("cannot read the golden file, run the test with -ipptest.update to create it: %v", err)
+		return false
+	}
+
+	// the encoding must match the golden file on the wire, apart from the order of the attributes in a group
+	want, err := canonicalMessage(golden)
+	if err != nil {
+		t.Errorf("cannot decode %s: %v", path, err)
+		return false
+	}
+	got, err := canonicalMessage(encoded)
+	if err != nil {
+		t.Errorf("cannot decode the encoded message: %v", err)
+		return false
+	}
+	if want != got {
+		t.Errorf("encoding differs from %s, run the test with -ipptest.update if the change is intended\n"+
+			"--- golden\n%s\n+++ encoded\n%s", path, want, got)
+		return false
+	}
+
+	// the message decoded from the golden file must be the same as the original
+	decoded, err := decodeMessage(golden, msg)
+	if err != nil {
+		t.Errorf("cannot decode %s: %v", path, err)
+		return false
+	}
+	want, got = dump(msg), dump(decoded)
+	if want != got {
+		t.Errorf("decoding %s doesn't return the message\n--- message\n%s\n+++ decoded\n%s", path, want, got)
+		return false
+	}
+
+	return true
+}
+
+func encodeMessage(msg interface{}) ([]byte, error) {
+	switch m := msg.(type) {
+	case *ipp.Request:
+		return m.Encode()
+	case *ipp.Response:
+		return m.Encode()
+	}
+
+	return nil, fmt.Errorf("cannot encode message of type %T", msg)
+}
+
+// decodeMessage decodes data into a message of the type of msg
+func decodeMessage(data []byte, msg interface{}) (interface{}, error) {
+	if _, ok := msg.(*ipp.Request); ok {
+		return ipp.NewRequestDecoder(bytes.NewReader(data)).Decode(nil)
+	}
+
+	return ipp.NewResponseDecoder(bytes.NewReader(data)).Decode(nil)
+}
+
+// canonicalMessage renders the header and the groups of an encoded message in wire order with the attributes of
+// each group sorted by name, as the encoders write the attributes of a group in map order. the values of an attribute
+// keep their order
+func canonicalMessage(data []byte) (string, error) {
+	scanner, err := ipp.NewMessageScanner(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%+v\n", scanner.Header())
+	for tag, attributes := range scanner.Groups() {
+		slices.SortStableFunc(attributes, func(a, b ipp.Attribute) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		fmt.Fprintf(&b, "group 0x%02x\n", uint8(tag))
+		for _, attr := range attributes {
+			fmt.Fprintf(&b, "    %s (0x%02x) = %s\n", attr.Name, uint8(attr.Tag), ipp.FormatValue(attr.Name, attr))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+func dump(msg interface{}) string {
+	var b strings.Builder
+	if err := ipp.Dump(&b, msg); err != nil {
+		return err.Error()
+	}
+
+	return b.String()
+}
@@ -0,0 +1,324 @@
+package ipptest
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// goldenResponse returns a successful response with the groups of the attributes
+func goldenResponse(printers, jobs []ipp.Attributes) *ipp.Response {
+	resp := ipp.NewResponse(ipp.StatusOk, 1)
+	resp.OperationAttributes.Set(ipp.AttributeCharset, ipp.TagCharset, ipp.Charset)
+	resp.OperationAttributes.Set(ipp.AttributeNaturalLanguage, ipp.TagLanguage, "en")
+	resp.PrinterAttributes = append(resp.PrinterAttributes, printers...)
+	resp.JobAttributes = append(resp.JobAttributes, jobs...)
+
+	return resp
+}
+
+func goldenJob(id int, state int8) ipp.Attributes {
+	job := make(ipp.Attributes)
+	job.Set(ipp.AttributeJobID, ipp.TagInteger, id)
+	job.Set(ipp.AttributeJobURI, ipp.TagUri, fmt.Sprintf("ipp://localhost/jobs/%d", id))
+	job.Set(ipp.AttributeJobState, ipp.TagEnum, int(state))
+	job.Set(ipp.AttributeJobStateReasons, ipp.TagKeyword, "none")
+
+	return job
+}
+
+func goldenPrinter(name string) ipp.Attributes {
+	printer := make(ipp.Attributes)
+	printer.Set(ipp.AttributePrinterName, ipp.TagName, name)
+	printer.Set(ipp.AttributePrinterUriSupported, ipp.TagUri, "ipp://localhost/printers/"+name)
+	printer.Set(ipp.AttributePrinterState, ipp.TagEnum, int(ipp.PrinterStateIdle))
+	printer.Set(ipp.AttributePrinterStateReasons, ipp.TagKeyword, "none")
+	printer.Set(ipp.AttributePrinterIsAcceptingJobs, ipp.TagBoolean, true)
+	printer.Set(ipp.AttributeCopiesSupported, ipp.TagRange, []int32{1, 99})
+	printer.Set(ipp.AttributePrinterResolutionSupported, ipp.TagResolution,
+		ipp.Resolution{Height: 600, Width: 600, Depth: 3})
+	printer.Set(ipp.AttributeMediaColDefault, ipp.TagBeginCollection,
+		ipp.MediaCol{SizeName: "iso_a4_210x297mm", Width: 21000, Height: 29700}.Collection())
+
+	return printer
+}
+
+func goldenDocument() ipp.Document {
+	return ipp.Document{Document: bytes.NewReader([]byte("%PDF-1.7")), Size: 8, Name: "report.pdf",
+		MimeType: ipp.MimeTypePDF}
+}
+
+func TestGoldenCorpus(t *testing.T) {
+	device := make(ipp.Attributes)
+	device.Set(ipp.AttributeDeviceURI, ipp.TagUri, "socket://192.168.1.10")
+	device.Set("device-class", ipp.TagKeyword, "network")
+	ppd := make(ipp.Attributes)
+	ppd.Set(ipp.AttributePPDName, ipp.TagName, "drv:///sample.drv/generic.ppd")
+	ppd.Set(ipp.AttributePPDMakeAndModel, ipp.TagText, "Generic PostScript Printer")
+
+	tests := []struct {
+		name      string
+		operation int16
+		call      func(c *ipp.CUPSClient) error
+		response  *ipp.Response
+		setup     func(p *Printer)
+	}{
+		{
+			name:      "print-job",
+			operation: ipp.OperationPrintJob,
+			call: func(c *ipp.CUPSClient) error {
+				_, err := c.PrintJob(goldenDocument(), "office", map[string]interface{}{
+					ipp.AttributeCopies: 2,
+					ipp.AttributeSides:  "two-sided-long-edge",
+				})
+				return err
+			},
+			response: goldenResponse(nil, []ipp.Attributes{goldenJob(1, ipp.JobStatePending)}),
+		},
+		{
+			name:      "create-job",
+			operation: ipp.OperationCreateJob,
+			call: func(c *ipp.CUPSClient) error {
+				_, err := c.PrintDocuments([]ipp.Document{goldenDocument()}, "office", nil)
+				return err
+			},
+			response: goldenResponse(nil, []ipp.Attributes{goldenJob(1, ipp.JobStatePending)}),
+			setup: func(p *Printer) {
+				p.Respond(ipp.OperationSendDocument, goldenResponse(nil, nil))
+			},
+		},
+		{
+			name:      "send-document",
+			operation: ipp.OperationSendDocument,
+			call: func(c *ipp.CUPSClient) error {
+				_, err := c.PrintDocuments([]ipp.Document{goldenDocument()}, "office", nil)
+				return err
+			},
+			response: goldenResponse(nil, []ipp.Attributes{goldenJob(1, ipp.JobStateProcessing)}),
+		},
+		{
+			name:      "cancel-job",
+			operation: ipp.OperationCancelJob,
+			call:      func(c *ipp.CUPSClient) error { return c.CancelJob(1, false) },
+			response:  goldenResponse(nil, nil),
+		},
+		{
+			name:      "cancel-jobs",
+			operation: ipp.OperationCancelJobs,
+			call:      func(c *ipp.CUPSClient) error { return c.CancelAllJob("office", true) },
+			response:  goldenResponse(nil, nil),
+			setup: func(p *Printer) {
+				attributes := DefaultAttributes()
+				attributes[ipp.AttributeOperationsSupported] = append(attributes[ipp.AttributeOperationsSupported],
+					ipp.Attribute{Tag: ipp.TagEnum, Value: int(ipp.OperationCancelJobs)})
+				p.SetAttributes(attributes)
+			},
+		},
+		{
+			name:      "get-job-attributes",
+			operation: ipp.OperationGetJobAttributes,
+			call: func(c *ipp.CUPSClient) error {
+				_, err := c.GetJobAttributes(1, []string{ipp.AttributeJobState})
+				return err
+			},
+			response: goldenResponse(nil, []ipp.Attributes{goldenJob(1, ipp.JobStateCompleted)}),
+		},
+		{
+			name:      "get-jobs",
+			operation: ipp.OperationGetJobs,
+			call: func(c *ipp.CUPSClient) error {
+				_, err := c.GetJobs("office", "", ipp.JobStateFilterAll, true, 0, 10, nil)
+				return err
+			},
+			response: goldenResponse(nil, []ipp.Attributes{goldenJob(1, ipp.JobStateCompleted),
+				goldenJob(2, ipp.JobStateProcessing)}),
+		},
+		{
+			name:      "get-printer-attributes",
+			operation: ipp.OperationGetPrinterAttributes,
+			call: func(c *ipp.CUPSClient) error {
+				_, err := c.GetPrinterAttributes("office", nil)
+				return err
+			},
+			response: goldenResponse([]ipp.Attributes{goldenPrinter("office")}, nil),
+		},
+		{
+			name:      "pause-printer",
+			operation: ipp.OperationPausePrinter,
+			call:      func(c *ipp.CUPSClient) error { return c.PausePrinter("office") },
+			response:  goldenResponse(nil, nil),
+		},
+		{
+			name:      "resume-printer",
+			operation: ipp.OperationResumePrinter,
+			call:      func(c *ipp.CUPSClient) error { return c.ResumePrinter("office") },
+			response:  goldenResponse(nil, nil),
+		},
+		{
+			name:      "restart-job",
+			operation: ipp.OperationRestartJob,
+			call:      func(c *ipp.CUPSClient) error { return c.RestartJob(1) },
+			response:  goldenResponse(nil, nil),
+		},
+		{
+			name:      "hold-job-until",
+			operation: ipp.OperationRestartJob,
+			call:      func(c *ipp.CUPSClient) error { return c.HoldJobUntil(1, "weekend") },
+			response:  goldenResponse(nil, nil),
+		},
+		{
+			name:      "release-job",
+			operation: ipp.OperationReleaseJob,
+			call:      func(c *ipp.CUPSClient) error { return c.ReleaseJob(1) },
+			response:  goldenResponse(nil, nil),
+		},
+		{
+			name:      "cups-get-default",
+			operation: ipp.OperationCupsGetDefault,
+			call: func(c *ipp.CUPSClient) error {
+				_, err := c.GetDefaultPrinter()
+				return err
+			},
+			response: goldenResponse([]ipp.Attributes{goldenPrinter("office")}, nil),
+		},
+		{
+			name:      "cups-get-printers",
+			operation: ipp.OperationCupsGetPrinters,
+			call: func(c *ipp.CUPSClient) error {
+				_, err := c.GetPrinters(nil)
+				return err
+			},
+			response: goldenResponse([]ipp.Attributes{goldenPrinter("office"), goldenPrinter("lab")}, nil),
+		},
+		{
+			name:      "cups-get-classes",
+			operation: ipp.OperationCupsGetClasses,
+			call: func(c *ipp.CUPSClient) error {
+				_, err := c.GetClasses(nil)
+				return err
+			},
+			response: goldenResponse([]ipp.Attributes{goldenPrinter("all")}, nil),
+		},
+		{
+			name:      "cups-accept-jobs",
+			operation: ipp.OperationCupsAcceptJobs,
+			call:      func(c *ipp.CUPSClient) error { return c.AcceptJobs("office") },
+			response:  goldenResponse(nil, nil),
+		},
+		{
+			name:      "cups-reject-jobs",
+			operation: ipp.OperationCupsRejectJobs,
+			call:      func(c *ipp.CUPSClient) error { return c.RejectJobs("office") },
+			response:  goldenResponse(nil, nil),
+		},
+		{
+			name:      "cups-add-modify-printer",
+			operation: ipp.OperationCupsAddModifyPrinter,
+			call: func(c *ipp.CUPSClient) error {
+				return c.CreatePrinter("office", "socket://192.168.1.10", "everywhere", true, "retry-job",
+					"Office printer", "2nd floor")
+			},
+			response: goldenResponse(nil, nil),
+		},
+		{
+			name:      "cups-delete-printer",
+			operation: ipp.OperationCupsDeletePrinter,
+			call:      func(c *ipp.CUPSClient) error { return c.DeletePrinter("office") },
+			response:  goldenResponse(nil, nil),
+		},
+		{
+			name:      "cups-delete-class",
+			operation: ipp.OperationCupsDeleteClass,
+			call:      func(c *ipp.CUPSClient) error { return c.DeleteClass("all") },
+			response:  goldenResponse(nil, nil),
+		},
+		{
+			name:      "cups-move-job",
+			operation: ipp.OperationCupsMoveJob,
+			call:      func(c *ipp.CUPSClient) error { return c.MoveJob(1, "lab") },
+			response:  goldenResponse(nil, nil),
+		},
+		{
+			name:      "cups-get-devices",
+			operation: ipp.OperationCupsGetDevices,
+			call: func(c *ipp.CUPSClient) error {
+				_, err := c.GetDevices()
+				return err
+			},
+			response: goldenResponse([]ipp.Attributes{device}, nil),
+		},
+		{
+			name:      "cups-get-ppds",
+			operation: ipp.OperationCupsGetPPDs,
+			call: func(c *ipp.CUPSClient) error {
+				_, err := c.GetPPDs()
+				return err
+			},
+			response: goldenResponse([]ipp.Attributes{ppd}, nil),
+		},
+		{
+			name:      "cups-get-ppd",
+			operation: ipp.OperationCupsGetPpd,
+			call: func(c *ipp.CUPSClient) error {
+				_, err := c.GetPPD("office")
+				return err
+			},
+			response: goldenResponse(nil, nil),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := NewPrinter(t)
+			p.Respond(test.operation, test.response)
+			if test.setup != nil {
+				test.setup(p)
+			}
+
+			client := ipp.NewCUPSClient(p.Host(), p.Port(), "test", "", false)
+			assert.Nil(t, test.call(client))
+
+			var req *ipp.Request
+			for _, r := range p.Requests() {
+				if r.Operation == test.operation {
+					req = r.Request
+				}
+			}
+			if !assert.NotNil(t, req, "no %s request sent", ipp.Operation(test.operation)) {
+				return
+			}
+			req.File = nil
+
+			dir := filepath.Join("testdata", "golden")
+			AssertGolden(t, filepath.Join(dir, test.name+"-request.ipp"), req)
+			AssertGolden(t, filepath.Join(dir, test.name+"-response.ipp"), test.response)
+		})
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.ipp")
+	req := ipp.NewRequest(ipp.OperationGetJobs, 1, ipp.WithPrinterURI("ipp://localhost/printers/office"))
+
+	*update = true
+	assert.True(t, AssertGolden(t, path, req))
+	*update = false
+	assert.True(t, AssertGolden(t, path, req))
+
+	encoded, err := req.Encode()
+	assert.Nil(t, err)
+	golden, err := canonicalMessage(encoded)
+	assert.Nil(t, err)
+
+	req.OperationAttributes[ipp.AttributeRequestingUserName] = "test"
+	encoded, err = req.Encode()
+	assert.Nil(t, err)
+	changed, err := canonicalMessage(encoded)
+	assert.Nil(t, err)
+	assert.NotEqual(t, golden, changed)
+}
@@ -0,0 +1,429 @@
+// Package ipptest provides a fake ipp printer for hermetic tests of ipp clients. the printer runs on a
+// httptest.Server, answers the common operations out of the box, records all requests and can be scripted to return
+// other responses, to respond slowly or to fail
+package ipptest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/server"
+)
+
+// Request is a request received by the fake printer
+type Request struct {
+	*ipp.Request
+	// Path is the http path the request was sent to
+	Path string
+	// Header are the http headers of the request
+	Header http.Header
+	// Document is the document data sent with the request
+	Document []byte
+}
+
+// Handler answers a request to the fake printer. if an ipp.StatusError is returned, its status code and message are
+// sent to the client, other errors are reported as server-error-internal-error
+type Handler func(req *Request) (*ipp.Response, error)
+
+// Printer is a fake ipp printer. by default it answers Get-Printer-Attributes with its Attributes, accepts all jobs
+// and keeps them in memory, other operations are answered with server-error-operation-not-supported. misbehaving
+// printers are simulated with InjectFault
+type Printer struct {
+	server *httptest.Server
+
+	mu         sync.Mutex
+	attributes ipp.Attributes
+	handlers   map[int16]Handler
+	failures   map[int16][]int16
+	faults     []Fault
+	latency    time.Duration
+	requests   []Request
+	jobs       map[int]ipp.Attributes
+	nextJobID  int
+}
+
+// NewPrinter starts a fake printer, it is closed at the end of the test
+func NewPrinter(t testing.TB) *Printer {
+	p := &Printer{
+		attributes: DefaultAttributes(),
+		handlers:   make(map[int16]Handler),
+		failures:   make(map[int16][]int16),
+		jobs:       make(map[int]ipp.Attributes),
+		nextJobID:  1,
+	}
+
+	p.handlers[ipp.OperationGetPrinterAttributes] = p.getPrinterAttributes
+	p.handlers[ipp.OperationValidateJob] = p.validateJob
+	p.handlers[ipp.OperationPrintJob] = p.createJob
+	p.handlers[ipp.OperationCreateJob] = p.createJob
+	p.handlers[ipp.OperationSendDocument] = p.sendDocument
+	p.handlers[ipp.OperationCancelJob] = p.cancelJob
+	p.handlers[ipp.OperationGetJobAttributes] = p.getJobAttributes
+	p.handlers[ipp.OperationGetJobs] = p.getJobs
+
+	p.server = httptest.NewServer(http.HandlerFunc(p.serveHTTP))
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+// DefaultAttributes returns the printer description attributes of a new fake printer
+func DefaultAttributes() ipp.Attributes {
+	attributes := make(ipp.Attributes)
+	attributes.Set(ipp.AttributePrinterName, ipp.TagName, "ipptest")
+	attributes.Set(ipp.AttributePrinterMakeAndModel, ipp.TagText, "Fake Printer")
+	attributes.Set(ipp.AttributePrinterState, ipp.TagEnum, int(ipp.PrinterStateIdle))
+	attributes.Set(ipp.AttributePrinterStateReasons, ipp.TagKeyword, "none")
+	attributes.Set(ipp.AttributePrinterIsAcceptingJobs, ipp.TagBoolean, true)
+	attributes.Set(ipp.AttributeCharsetConfigured, ipp.TagCharset, ipp.Charset)
+	attributes.Set(ipp.AttributeCharsetSupported, ipp.TagCharset, ipp.Charset)
+	attributes.Set(ipp.AttributeNaturalLanguageConfigured, ipp.TagLanguage, "en")
+	attributes.Set(ipp.AttributeIppVersionsSupported, ipp.TagKeyword, "1.1", "2.0")
+	attributes.Set(ipp.AttributeOperationsSupported, ipp.TagEnum, int(ipp.OperationPrintJob),
+		int(ipp.OperationValidateJob), int(ipp.OperationCreateJob), int(ipp.OperationSendDocument),
+		int(ipp.OperationCancelJob), int(ipp.OperationGetJobAttributes), int(ipp.OperationGetJobs),
+		int(ipp.OperationGetPrinterAttributes))
+	attributes.Set(ipp.AttributeDocumentFormatSupported, ipp.TagMimeType, ipp.MimeTypePDF, ipp.MimeTypeOctetStream)
+
+	return attributes
+}
+
+// Close shuts the printer down, it is called automatically at the end of the test
+func (p *Printer) Close() {
+	p.server.Close()
+}
+
+// URL returns the base url of the printer, e.g. http://127.0.0.1:49152
+func (p *Printer) URL() string {
+	return p.server.URL
+}
+
+// Host returns the host the printer listens on
+func (p *Printer) Host() string {
+	host, _, _ := net.SplitHostPort(p.server.Listener.Addr().String())
+	return host
+}
+
+// Port returns the port the printer listens on
+func (p *Printer) Port() int {
+	_, port, _ := net.SplitHostPort(p.server.Listener.Addr().String())
+	n, _ := strconv.Atoi(port)
+	return n
+}
+
+// URI returns the ipp uri of the printer with the given name as addressed by the IPPClient
+func (p *Printer) URI(name string) string {
+	return fmt.Sprintf("ipp://%s/printers/%s", p.server.Listener.Addr(), name)
+}
+
+// Client returns a client for the printer which sends requests as the user test
+func (p *Printer) Client() *ipp.IPPClient {
+	return ipp.NewIPPClient(p.Host(), p.Port(), "test", "", false)
+}
+
+// SetAttributes replaces the printer description attributes returned by Get-Printer-Attributes
+func (p *Printer) SetAttributes(attributes ipp.Attributes) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.attributes = attributes
+}
+
+// Handle replaces the handler of the operation
+func (p *Printer) Handle(operation int16, handler Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.handlers[operation] = handler
+}
+
+// Respond answers all requests of the operation with the response, the request id and version of the requests are
+// echoed
+func (p *Printer) Respond(operation int16, resp *ipp.Response) {
+	p.Handle(operation, func(req *Request) (*ipp.Response, error) {
+		echo := *resp
+		echo.OperationAttributes = maps.Clone(resp.OperationAttributes)
+		return &echo, nil
+	})
+}
+
+// FailNext answers the next request of the operation with the error status, calls queue up
+func (p *Printer) FailNext(operation, status int16) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.failures[operation] = append(p.failures[operation], status)
+}
+
+// SetLatency delays every response by d
+func (p *Printer) SetLatency(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.latency = d
+}
+
+// Requests returns the received requests in order
+func (p *Printer) Requests() []Request {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Request(nil), p.requests...)
+}
+
+// LastRequest returns the most recent request, false if no request was received
+func (p *Printer) LastRequest() (Request, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.requests) == 0 {
+		return Request{}, false
+	}
+
+	return p.requests[len(p.requests)-1], true
+}
+
+// Job returns the attributes of a job created on the printer
+func (p *Printer) Job(id int) (ipp.Attributes, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	job, ok := p.jobs[id]
+	return job, ok
+}
+
+// Reset forgets the received requests, the jobs and the pending failures, the handlers and attributes are kept
+func (p *Printer) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests = nil
+	p.failures = make(map[int16][]int16)
+	p.faults = nil
+	p.jobs = make(map[int]ipp.Attributes)
+	p.nextJobID = 1
+}
+
+func (p *Printer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	fault := p.nextFault()
+	if fault == FaultRefuseContinue {
+		http.Error(w, "100-continue is not supported", http.StatusExpectationFailed)
+		return
+	}
+
+	decoded, err := ipp.NewRequestDecoder(r.Body).Decode(nil)
+	if err != nil {
+		http.Error(w, "unable to decode ipp request", http.StatusBadRequest)
+		return
+	}
+	document, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "unable to read document", http.StatusBadRequest)
+		return
+	}
+
+	req := Request{Request: decoded, Path: r.URL.Path, Header: r.Header.Clone(), Document: document}
+	req.File = bytes.NewReader(document)
+	req.FileSize = len(document)
+
+	p.mu.Lock()
+	p.requests = append(p.requests, req)
+	latency := p.latency
+	handler := p.handlers[req.Operation]
+	var failure int16
+	if statuses := p.failures[req.Operation]; len(statuses) > 0 {
+		failure, p.failures[req.Operation] = statuses[0], statuses[1:]
+	}
+	p.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	var resp *ipp.Response
+	switch {
+	case failure != 0:
+		resp = errorResponse(&req, failure, "injected failure")
+	case handler == nil:
+		resp = errorResponse(&req, ipp.StatusErrorOperationNotSupported, "operation not supported")
+	default:
+		resp = p.handle(&req, handler)
+	}
+
+	applyFault(fault, resp)
+	payload, err := resp.Encode()
+	if err != nil {
+		http.Error(w, "unable to encode ipp response", http.StatusInternalServerError)
+		return
+	}
+	if writeFault(fault, w, payload) {
+		return
+	}
+
+	w.Header().Set("Content-Type", ipp.ContentTypeIPP)
+	_, _ = w.Write(payload)
+}
+
+func (p *Printer) handle(req *Request, handler Handler) *ipp.Response {
+	resp, err := handler(req)
+	if err != nil {
+		var statusErr ipp.StatusError
+		if errors.As(err, &statusErr) {
+			return errorResponse(req, statusErr.Status, statusErr.Message)
+		}
+		return errorResponse(req, ipp.StatusErrorInternal, err.Error())
+	}
+
+	if resp == nil {
+		resp = builder(req).Build()
+	}
+	resp.RequestId = req.RequestId
+	resp.ProtocolVersionMajor = req.ProtocolVersionMajor
+	resp.ProtocolVersionMinor = req.ProtocolVersionMinor
+
+	return resp
+}
+
+func (p *Printer) getPrinterAttributes(req *Request) (*ipp.Response, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return builder(req).PrinterAttributes(p.attributes).Build(), nil
+}
+
+func (p *Printer) validateJob(req *Request) (*ipp.Response, error) {
+	return nil, nil
+}
+
+func (p *Printer) createJob(req *Request) (*ipp.Response, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	id := p.nextJobID
+	p.nextJobID++
+
+	state := ipp.JobStatePending
+	if req.Operation == ipp.OperationPrintJob {
+		state = ipp.JobStateCompleted
+	}
+
+	job := make(ipp.Attributes)
+	job.Set(ipp.AttributeJobID, ipp.TagInteger, id)
+	job.Set(ipp.AttributeJobURI, ipp.TagUri, p.jobURI(id))
+	job.Set(ipp.AttributeJobState, ipp.TagEnum, int(state))
+	job.Set(ipp.AttributeJobStateReasons, ipp.TagKeyword, "none")
+	if name, ok := req.OperationAttributes[ipp.AttributeJobName].(string); ok {
+		job.Set(ipp.AttributeJobName, ipp.TagName, name)
+	}
+	if user, ok := req.OperationAttributes[ipp.AttributeRequestingUserName].(string); ok {
+		job.Set(ipp.AttributeJobOriginatingUserName, ipp.TagName, user)
+	}
+	p.jobs[id] = job
+
+	return builder(req).Job(id, p.jobURI(id), state).Build(), nil
+}
+
+func (p *Printer) sendDocument(req *Request) (*ipp.Response, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	job, err := p.lookupJob(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if last, _ := req.OperationAttributes[ipp.AttributeLastDocument].(bool); last {
+		job.Set(ipp.AttributeJobState, ipp.TagEnum, int(ipp.JobStateCompleted))
+	}
+
+	return builder(req).JobAttributes(job).Build(), nil
+}
+
+func (p *Printer) cancelJob(req *Request) (*ipp.Response, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	job, err := p.lookupJob(req)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Set(ipp.AttributeJobState, ipp.TagEnum, int(ipp.JobStateCanceled))
+	job.Set(ipp.AttributeJobStateReasons, ipp.TagKeyword, "job-canceled-by-user")
+
+	return nil, nil
+}
+
+func (p *Printer) getJobAttributes(req *Request) (*ipp.Response, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	job, err := p.lookupJob(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return builder(req).JobAttributes(job).Build(), nil
+}
+
+func (p *Printer) getJobs(req *Request) (*ipp.Response, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	b := builder(req)
+	for id := 1; id < p.nextJobID; id++ {
+		if job, ok := p.jobs[id]; ok {
+			b.JobAttributes(job)
+		}
+	}
+
+	return b.Build(), nil
+}
+
+// lookupJob returns the job addressed by the job-id or the job-uri operation attribute, the caller must hold the lock
+func (p *Printer) lookupJob(req *Request) (ipp.Attributes, error) {
+	id, ok := req.OperationAttributes[ipp.AttributeJobID].(int)
+	if uri, isURI := req.OperationAttributes[ipp.AttributeJobURI].(string); !ok && isURI {
+		id, _ = strconv.Atoi(path.Base(uri))
+	}
+
+	job, ok := p.jobs[id]
+	if !ok {
+		return nil, ipp.StatusError{Status: ipp.StatusErrorNotFound, Message: fmt.Sprintf("job %d not found", id)}
+	}
+
+	return job, nil
+}
+
+func (p *Printer) jobURI(id int) string {
+	return fmt.Sprintf("ipp://%s/jobs/%d", p.server.Listener.Addr(), id)
+}
+
+func builder(req *Request) *server.ResponseBuilder {
+	return server.NewResponseBuilder(&server.Request{Request: req.Request})
+}
+
+func errorResponse(req *Request, status int16, message string) *ipp.Response {
+	return builder(req).Status(status).StatusMessage("%s", message).Build()
+}
@@ -0,0 +1,91 @@
+package ipptest
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/phin1x/go-ipp"
+)
+
+func TestPrinter_Defaults(t *testing.T) {
+	p := NewPrinter(t)
+	client := p.Client()
+
+	attributes, err := client.GetPrinterAttributes("office", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "Fake Printer", attributes[ipp.AttributePrinterMakeAndModel][0].Value)
+
+	jobID, err := client.PrintJob(ipp.Document{
+		Document: bytes.NewReader([]byte("%PDF-1.7")),
+		Size:     8,
+		Name:     "report.pdf",
+		MimeType: ipp.MimeTypePDF,
+	}, "office", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, jobID)
+
+	job, ok := p.Job(jobID)
+	assert.True(t, ok)
+	assert.Equal(t, "report.pdf", job[ipp.AttributeJobName][0].Value)
+
+	requests := p.Requests()
+	assert.Len(t, requests, 2)
+	assert.Equal(t, ipp.OperationGetPrinterAttributes, requests[0].Operation)
+	assert.Equal(t, "/printers/office", requests[1].Path)
+	assert.Equal(t, []byte("%PDF-1.7"), requests[1].Document)
+
+	assert.Nil(t, client.CancelJob(jobID, false))
+	job, _ = p.Job(jobID)
+	assert.Equal(t, int(ipp.JobStateCanceled), job[ipp.AttributeJobState][0].Value)
+
+	err = client.CancelJob(42, false)
+	assert.True(t, errors.Is(err, ipp.StatusError{Status: ipp.StatusErrorNotFound}))
+
+	err = client.ResumePrinter("office")
+	assert.True(t, errors.Is(err, ipp.StatusError{Status: ipp.StatusErrorOperationNotSupported}))
+}
+
+func TestPrinter_Script(t *testing.T) {
+	p := NewPrinter(t)
+	client := p.Client()
+
+	resp := ipp.NewResponse(ipp.StatusOk, 0)
+	printer := make(ipp.Attributes)
+	printer.Set(ipp.AttributePrinterName, ipp.TagName, "scripted")
+	resp.PrinterAttributes = append(resp.PrinterAttributes, printer)
+	p.Respond(ipp.OperationGetPrinterAttributes, resp)
+
+	attributes, err := client.GetPrinterAttributes("office", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "scripted", attributes[ipp.AttributePrinterName][0].Value)
+
+	p.FailNext(ipp.OperationGetPrinterAttributes, ipp.StatusErrorBusy)
+	_, err = client.GetPrinterAttributes("office", nil)
+	assert.True(t, errors.Is(err, ipp.StatusError{Status: ipp.StatusErrorBusy}))
+	_, err = client.GetPrinterAttributes("office", nil)
+	assert.Nil(t, err)
+
+	p.Handle(ipp.OperationGetPrinterAttributes, func(req *Request) (*ipp.Response, error) {
+		return nil, ipp.StatusError{Status: ipp.StatusErrorForbidden, Message: "no"}
+	})
+	_, err = client.GetPrinterAttributes("office", nil)
+	assert.True(t, errors.Is(err, ipp.StatusError{Status: ipp.StatusErrorForbidden}))
+
+	p.Reset()
+	_, ok := p.LastRequest()
+	assert.False(t, ok)
+}
+
+func TestPrinter_SetLatency(t *testing.T) {
+	p := NewPrinter(t)
+	p.SetLatency(50 * time.Millisecond)
+
+	start := time.Now()
+	_, err := p.Client().GetPrinterAttributes("office", nil)
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
@@ -0,0 +1,381 @@
+package ipptool
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// responseGroup is an attribute group of a response
+type responseGroup struct {
+	tag        int8
+	attributes ipp.Attributes
+}
+
+func responseGroups(resp *ipp.Response) []responseGroup {
+	groups := []responseGroup{{ipp.TagOperation, resp.OperationAttributes}}
+	if len(resp.UnsupportedAttributes) > 0 {
+		groups = append(groups, responseGroup{ipp.TagUnsupportedGroup, resp.UnsupportedAttributes})
+	}
+
+	for _, g := range []struct {
+		tag        int8
+		attributes []ipp.Attributes
+	}{
+		{ipp.TagPrinter, resp.PrinterAttributes},
+		{ipp.TagJob, resp.JobAttributes},
+		{ipp.TagSubscription, resp.SubscriptionAttributes},
+		{ipp.TagEventNotification, resp.EventNotificationAttributes},
+	} {
+		for _, attributes := range g.attributes {
+			groups = append(groups, responseGroup{g.tag, attributes})
+		}
+	}
+
+	return groups
+}
+
+// findAttribute returns the values of the first attribute with the name and the tag of its group. the name may be a
+// path to a collection member like media-col-default/media-size, the member values of all collection values are
+// returned
+func findAttribute(groups []responseGroup, name string) ([]ipp.Attribute, int8, bool) {
+	path := strings.Split(name, "/")
+
+	for _, g := range groups {
+		values, ok := g.attributes[path[0]]
+		if !ok {
+			continue
+		}
+
+		for _, member := range path[1:] {
+			var members []ipp.Attribute
+			for _, v := range values {
+				if collection, ok := v.Value.(ipp.Attributes); ok {
+					members = append(members, collection[member]...)
+				}
+			}
+			values = members
+		}
+
+		if len(values) > 0 {
+			return values, g.tag, true
+		}
+	}
+
+	return nil, 0, false
+}
+
+// check checks the status and the EXPECT statements of a test against the response, the failed checks are added to
+// the errors of the result
+func (r *run) check(t *Test, result *Result) {
+	resp := result.Response
+	groups := responseGroups(resp)
+
+	if msg := r.checkStatus(t.statuses, resp.StatusCode); msg != "" {
+		result.Errors = append(result.Errors, msg)
+	}
+
+	for _, e := range t.expects {
+		if msg := r.checkExpect(e, groups); msg != "" {
+			result.Errors = append(result.Errors, msg)
+		}
+	}
+
+	for _, display := range t.displays {
+		name := r.expand(display)
+		if values, _, ok := findAttribute(groups, name); ok {
+			result.Displayed = append(result.Displayed, fmt.Sprintf("%s (%s) = %s", name,
+				ipp.TagSyntax(values[0].Tag), formatValues(name, values)))
+		}
+	}
+
+	// the ids of created jobs and subscriptions are available to the following tests
+	for _, name := range []string{ipp.AttributeJobID, ipp.AttributeJobURI, "notify-subscription-id"} {
+		if values, _, ok := findAttribute(groups, name); ok {
+			r.vars[name] = ipp.FormatValue(name, values[0])
+		}
+	}
+}
+
+// checkStatus returns the failed check of the STATUS statements, the status must be successful without statements
+func (r *run) checkStatus(statuses []*expect, code int16) string {
+	var expected []string
+	matched := false
+
+	for _, s := range statuses {
+		if !r.conditions(s) {
+			continue
+		}
+
+		name := r.expand(s.name)
+		expected = append(expected, name)
+
+		status, err := ipp.ParseStatus(name)
+		if err != nil {
+			value, parseErr := strconv.ParseInt(name, 0, 16)
+			if parseErr != nil {
+				return fmt.Sprintf("unknown status %s", name)
+			}
+			status = ipp.Status(value)
+		}
+
+		match := int16(status) == code
+		matched = matched || match
+		r.defines(s, match, nil)
+	}
+
+	got := ipp.Status(code).String()
+	switch {
+	case len(expected) == 0 && (code < ipp.StatusOk || code >= ipp.StatusRedirectionOtherSite):
+		return fmt.Sprintf("EXPECTED: STATUS successful-ok (got %s)", got)
+	case len(expected) > 0 && !matched:
+		return fmt.Sprintf("EXPECTED: STATUS %s (got %s)", strings.Join(expected, " or "), got)
+	}
+
+	return ""
+}
+
+// checkExpect returns the failed check of an EXPECT statement. a failed check with DEFINE-MATCH or DEFINE-NO-MATCH
+// only defines the variables and does not fail the test
+func (r *run) checkExpect(e *expect, groups []responseGroup) string {
+	if !r.conditions(e) {
+		return ""
+	}
+
+	name := r.expand(e.name)
+	absent, optional := strings.HasPrefix(name, "!"), strings.HasPrefix(name, "?")
+	name = strings.TrimLeft(name, "!?")
+
+	values, tag, found := findAttribute(groups, name)
+
+	var msg string
+	switch {
+	case absent && found:
+		msg = fmt.Sprintf("UNEXPECTED: %s", name)
+	case absent || !found && optional:
+	case !found:
+		msg = fmt.Sprintf("EXPECTED: %s", name)
+	default:
+		msg = r.checkPredicates(e, name, values, tag, groups)
+	}
+
+	if r.defines(e, msg == "", values) {
+		return ""
+	}
+
+	return msg
+}
+
+// conditions reports whether the IF-DEFINED and IF-NOT-DEFINED predicates of a statement are met
+func (r *run) conditions(e *expect) bool {
+	for _, p := range e.predicates {
+		if p.name != "IF-DEFINED" && p.name != "IF-NOT-DEFINED" {
+			continue
+		}
+		if _, defined := r.vars[r.expand(p.arg)]; defined != (p.name == "IF-DEFINED") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// defines applies the DEFINE-MATCH, DEFINE-NO-MATCH and DEFINE-VALUE predicates of a statement, it reports whether
+// the statement has one of the DEFINE-MATCH and DEFINE-NO-MATCH predicates
+func (r *run) defines(e *expect, match bool, values []ipp.Attribute) bool {
+	var define bool
+	for _, p := range e.predicates {
+		name := r.expand(p.arg)
+
+		switch p.name {
+		case "DEFINE-MATCH":
+			define = true
+			if match {
+				r.vars[name] = "1"
+			}
+		case "DEFINE-NO-MATCH":
+			define = true
+			if !match {
+				r.vars[name] = "1"
+			}
+		case "DEFINE-VALUE":
+			if match && len(values) > 0 {
+				r.vars[name] = formatValues(strings.TrimLeft(r.expand(e.name), "!?"), values)
+			}
+		}
+	}
+
+	return define
+}
+
+// checkPredicates returns the first failed predicate of an EXPECT statement for the found attribute
+func (r *run) checkPredicates(e *expect, name string, values []ipp.Attribute, tag int8, groups []responseGroup) string {
+	for _, p := range e.predicates {
+		arg := r.expand(p.arg)
+
+		switch p.name {
+		case "OF-TYPE":
+			for _, v := range values {
+				if !ofType(v.Tag, arg) {
+					return fmt.Sprintf("EXPECTED: %s OF-TYPE %s (got %s)", name, arg, ipp.TagSyntax(v.Tag))
+				}
+			}
+		case "IN-GROUP":
+			expected, ok := groupTags[strings.TrimSuffix(arg, "-attributes-tag")]
+			if !ok || expected != tag {
+				return fmt.Sprintf("EXPECTED: %s IN-GROUP %s (got %s)", name, arg, groupName(tag))
+			}
+		case "COUNT":
+			if strconv.Itoa(len(values)) != arg {
+				return fmt.Sprintf("EXPECTED: %s COUNT %s (got %d)", name, arg, len(values))
+			}
+		case "SAME-COUNT-AS":
+			other, _, _ := findAttribute(groups, arg)
+			if len(other) != len(values) {
+				return fmt.Sprintf("EXPECTED: %s (%d values) SAME-COUNT-AS %s (%d values)", name, len(values), arg,
+					len(other))
+			}
+		case "WITH-VALUE", "WITH-ALL-VALUES":
+			all := p.name == "WITH-ALL-VALUES"
+			matched, err := matchValues(name, values, arg, all)
+			if err != nil {
+				return fmt.Sprintf("EXPECTED: %s %s %q (%v)", name, p.name, arg, err)
+			}
+			if !matched {
+				return fmt.Sprintf("EXPECTED: %s %s %q (got %s)", name, p.name, arg, formatValues(name, values))
+			}
+		case "WITH-VALUE-FROM", "WITH-ALL-VALUES-FROM":
+			other, _, _ := findAttribute(groups, arg)
+			allowed := make(map[string]bool, len(other))
+			for _, v := range other {
+				allowed[ipp.FormatValue(arg, v)] = true
+			}
+			for _, v := range values {
+				if !allowed[ipp.FormatValue(name, v)] {
+					return fmt.Sprintf("EXPECTED: %s %s %s (got %s)", name, p.name, arg, ipp.FormatValue(name, v))
+				}
+			}
+		case "WITH-DISTINCT-VALUES":
+			seen := make(map[string]bool, len(values))
+			for _, v := range values {
+				formatted := ipp.FormatValue(name, v)
+				if seen[formatted] {
+					return fmt.Sprintf("EXPECTED: %s WITH-DISTINCT-VALUES (got %s twice)", name, formatted)
+				}
+				seen[formatted] = true
+			}
+		}
+	}
+
+	return ""
+}
+
+// ofType reports whether a tag is one of the syntax names separated by |, name and text match the tags with and
+// without language
+func ofType(tag int8, types string) bool {
+	for _, t := range strings.Split(types, "|") {
+		switch {
+		case t == ipp.TagSyntax(tag):
+		case t == "name" && (tag == ipp.TagName || tag == ipp.TagNameLang):
+		case t == "text" && (tag == ipp.TagText || tag == ipp.TagTextLang):
+		default:
+			continue
+		}
+		return true
+	}
+
+	return false
+}
+
+// matchValues reports whether one or all values match a WITH-VALUE pattern. a pattern in slashes is a regular
+// expression matched against the formatted value. integers and enums match a comma separated list of numbers which
+// may be prefixed with <, >, <= or >=, ranges match the numbers they contain. all other values must be equal to the
+// pattern
+func matchValues(name string, values []ipp.Attribute, pattern string, all bool) (bool, error) {
+	var re *regexp.Regexp
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		var err error
+		if re, err = regexp.Compile(pattern[1 : len(pattern)-1]); err != nil {
+			return false, err
+		}
+	}
+
+	for _, v := range values {
+		formatted := ipp.FormatValue(name, v)
+
+		var match bool
+		switch value := v.Value.(type) {
+		case int:
+			match = re == nil && matchNumber(pattern, formatted, func(operator string, n int) bool {
+				return compare(operator, value, n, value)
+			})
+		case []int32:
+			// the comparisons of ranges use the upper bound for < and the lower bound for >
+			match = re == nil && len(value) == 2 && matchNumber(pattern, formatted, func(operator string, n int) bool {
+				return compare(operator, int(value[1]), n, int(value[0]))
+			})
+		}
+
+		switch {
+		case re != nil:
+			match = re.MatchString(formatted)
+		case !match:
+			match = formatted == pattern
+		}
+
+		if match != all {
+			return match, nil
+		}
+	}
+
+	return all && len(values) > 0, nil
+}
+
+// matchNumber matches a number against a comma separated list of numbers, keywords and comparisons
+func matchNumber(pattern, formatted string, match func(operator string, n int) bool) bool {
+	for _, alternative := range strings.Split(pattern, ",") {
+		if alternative == formatted {
+			return true
+		}
+
+		operator := strings.TrimRight(alternative, "0123456789-")
+		n, err := strconv.Atoi(alternative[len(operator):])
+		if err == nil && match(operator, n) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compare compares the upper and lower bound of a value with n, an integer has equal bounds. without operator n must
+// be within the bounds
+func compare(operator string, upper, n, lower int) bool {
+	switch operator {
+	case "":
+		return n >= lower && n <= upper
+	case "<":
+		return upper < n
+	case ">":
+		return lower > n
+	case "<=":
+		return upper <= n
+	case ">=":
+		return lower >= n
+	}
+
+	return false
+}
+
+// formatValues formats the values of an attribute separated by commas
+func formatValues(name string, values []ipp.Attribute) string {
+	formatted := make([]string, len(values))
+	for i, v := range values {
+		formatted[i] = ipp.FormatValue(name, v)
+	}
+
+	return strings.Join(formatted, ",")
+}
@@ -0,0 +1,550 @@
+// Package ipptool parses and runs the test files of the CUPS ipptool against a printer, e.g. the IPP Everywhere
+// self-certification tests. the files contain tests with an OPERATION, GROUP and ATTR statements for the request
+// and STATUS and EXPECT statements which check the response:
+//
+//	{
+//		NAME "Get printer attributes"
+//		OPERATION Get-Printer-Attributes
+//		GROUP operation-attributes-tag
+//		ATTR charset attributes-charset utf-8
+//		ATTR naturalLanguage attributes-natural-language en
+//		ATTR uri printer-uri $uri
+//		STATUS successful-ok
+//		EXPECT printer-state OF-TYPE enum IN-GROUP printer-attributes-tag COUNT 1
+//	}
+//
+// the common statements and EXPECT predicates of ipptool are supported, monitoring, repeating and generating files
+// are not
+package ipptool
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// SyntaxError is returned for test files which cannot be parsed
+var SyntaxError = errors.New("syntax error")
+
+// File is a parsed test file
+type File struct {
+	// Name is the path of the file, INCLUDE and FILE paths are relative to its directory
+	Name  string
+	steps []step
+}
+
+// Tests returns the tests of the file without the tests of included files
+func (f *File) Tests() []*Test {
+	var tests []*Test
+	for _, s := range f.steps {
+		if s.test != nil {
+			tests = append(tests, s.test)
+		}
+	}
+
+	return tests
+}
+
+// step is a top level directive or a test of a file, the directives are applied in file order when the file runs
+type step struct {
+	directive string
+	args      []string
+	test      *Test
+}
+
+// Test is a single test of a file, i.e. one request and the checks of its response
+type Test struct {
+	Name      string
+	Operation string
+	Version   string
+
+	groups       []group
+	file         string
+	statuses     []*expect
+	expects      []*expect
+	displays     []string
+	defines      [][2]string
+	skipIf       []condition
+	ignoreErrors string
+	skipPrevious string
+	delay        string
+}
+
+// group is a GROUP of a request and its attributes
+type group struct {
+	name  string
+	attrs []attr
+}
+
+// attr is an ATTR or MEMBER statement, the values are expanded when the request is built
+type attr struct {
+	syntax string
+	name   string
+	values []value
+}
+
+// value is a single ATTR value, members is set for collection values
+type value struct {
+	text    string
+	members []attr
+}
+
+// condition is a SKIP-IF-DEFINED, SKIP-IF-NOT-DEFINED or SKIP-IF-MISSING statement of a test
+type condition struct {
+	directive string
+	arg       string
+}
+
+// expect is an EXPECT or STATUS statement with its predicates. name is the attribute name or status keyword, the
+// attribute name is prefixed with ! if the attribute must be missing and ? if it is optional
+type expect struct {
+	name       string
+	predicates []predicate
+}
+
+type predicate struct {
+	name string
+	arg  string
+}
+
+// predicateArgs are the EXPECT and STATUS predicates and their number of arguments
+var predicateArgs = map[string]int{
+	"COUNT":                1,
+	"DEFINE-MATCH":         1,
+	"DEFINE-NO-MATCH":      1,
+	"DEFINE-VALUE":         1,
+	"IF-DEFINED":           1,
+	"IF-NOT-DEFINED":       1,
+	"IN-GROUP":             1,
+	"OF-TYPE":              1,
+	"REPEAT-LIMIT":         1,
+	"REPEAT-MATCH":         0,
+	"REPEAT-NO-MATCH":      0,
+	"SAME-COUNT-AS":        1,
+	"WITH-ALL-VALUES":      1,
+	"WITH-ALL-VALUES-FROM": 1,
+	"WITH-DISTINCT-VALUES": 0,
+	"WITH-VALUE":           1,
+	"WITH-VALUE-FROM":      1,
+}
+
+// ignoredDirectives are the directives which do not change the outcome of a run and their number of arguments
+var ignoredDirectives = map[string]int{
+	"COMPRESSION":              1,
+	"FILE-ID":                  1,
+	"PAUSE":                    1,
+	"REQUEST-ID":               1,
+	"STOP-AFTER-INCLUDE-ERROR": 1,
+	"TEST-ID":                  1,
+	"TRANSFER":                 1,
+}
+
+// ParseFile parses the test file at path
+func ParseFile(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f, path)
+}
+
+// Parse parses a test file, name is used as File.Name and in errors
+func Parse(r io.Reader, name string) (*File, error) {
+	p := &parser{tokens: &tokenizer{r: bufio.NewReader(r), line: 1}, name: name}
+	file := &File{Name: name}
+
+	for {
+		tok, err := p.next()
+		if err == io.EOF {
+			return file, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if tok == "{" {
+			test, err := p.test()
+			if err != nil {
+				return nil, err
+			}
+			file.steps = append(file.steps, step{test: test})
+			continue
+		}
+
+		directive := strings.ToUpper(tok)
+		var args int
+		switch directive {
+		case "DEFINE", "DEFINE-DEFAULT", "INCLUDE-IF-DEFINED", "INCLUDE-IF-NOT-DEFINED":
+			args = 2
+		case "INCLUDE", "IGNORE-ERRORS", "VERSION", "SKIP-IF-HEADER-DEFINED":
+			args = 1
+		default:
+			n, ok := ignoredDirectives[directive]
+			if !ok {
+				return nil, p.errorf("unexpected %s", tok)
+			}
+			if _, err := p.args(tok, n); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		values, err := p.args(tok, args)
+		if err != nil {
+			return nil, err
+		}
+		file.steps = append(file.steps, step{directive: directive, args: values})
+	}
+}
+
+type parser struct {
+	tokens *tokenizer
+	name   string
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s:%d: %s", SyntaxError, p.name, p.tokens.line, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) next() (string, error) {
+	tok, err := p.tokens.next()
+	if err != nil && err != io.EOF {
+		return "", p.errorf("%v", err)
+	}
+
+	return tok, err
+}
+
+// args reads the n arguments of a directive
+func (p *parser) args(directive string, n int) ([]string, error) {
+	args := make([]string, n)
+	for i := range args {
+		tok, err := p.next()
+		if err == io.EOF || tok == "{" || tok == "}" {
+			return nil, p.errorf("missing argument of %s", directive)
+		}
+		if err != nil {
+			return nil, err
+		}
+		args[i] = tok
+	}
+
+	return args, nil
+}
+
+// test parses the statements of a test up to the closing brace
+func (p *parser) test() (*Test, error) {
+	test := &Test{}
+
+	for {
+		tok, err := p.next()
+		if err == io.EOF {
+			return nil, p.errorf("missing } at the end of the test")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if tok == "}" {
+			return test, nil
+		}
+
+		directive := strings.ToUpper(tok)
+		switch directive {
+		case "ATTR":
+			if len(test.groups) == 0 {
+				return nil, p.errorf("ATTR without GROUP")
+			}
+			a, err := p.attr(tok)
+			if err != nil {
+				return nil, err
+			}
+			g := &test.groups[len(test.groups)-1]
+			g.attrs = append(g.attrs, a)
+			continue
+		case "EXPECT", "STATUS":
+			e, err := p.expect(tok)
+			if err != nil {
+				return nil, err
+			}
+			if directive == "STATUS" {
+				test.statuses = append(test.statuses, e)
+			} else {
+				test.expects = append(test.expects, e)
+			}
+			continue
+		case "DEFINE":
+			args, err := p.args(tok, 2)
+			if err != nil {
+				return nil, err
+			}
+			test.defines = append(test.defines, [2]string{args[0], args[1]})
+			continue
+		}
+
+		// the remaining directives have a single argument
+		if _, ok := ignoredDirectives[directive]; !ok {
+			switch directive {
+			case "NAME", "OPERATION", "VERSION", "GROUP", "FILE", "DISPLAY", "IGNORE-ERRORS", "SKIP-PREVIOUS-ERROR",
+				"DELAY", "SKIP-IF-DEFINED", "SKIP-IF-NOT-DEFINED", "SKIP-IF-MISSING":
+			default:
+				return nil, p.errorf("unsupported %s", tok)
+			}
+		}
+
+		args, err := p.args(tok, 1)
+		if err != nil {
+			return nil, err
+		}
+		arg := args[0]
+
+		switch directive {
+		case "NAME":
+			test.Name = arg
+		case "OPERATION":
+			test.Operation = arg
+		case "VERSION":
+			test.Version = arg
+		case "GROUP":
+			test.groups = append(test.groups, group{name: arg})
+		case "FILE":
+			test.file = arg
+		case "DISPLAY":
+			test.displays = append(test.displays, arg)
+		case "IGNORE-ERRORS":
+			test.ignoreErrors = arg
+		case "SKIP-PREVIOUS-ERROR":
+			test.skipPrevious = arg
+		case "DELAY":
+			test.delay = arg
+		case "SKIP-IF-DEFINED", "SKIP-IF-NOT-DEFINED", "SKIP-IF-MISSING":
+			test.skipIf = append(test.skipIf, condition{directive: directive, arg: arg})
+		}
+	}
+}
+
+// attr parses the syntax, name and values of an ATTR or MEMBER statement
+func (p *parser) attr(directive string) (attr, error) {
+	args, err := p.args(directive, 2)
+	if err != nil {
+		return attr{}, err
+	}
+	a := attr{syntax: args[0], name: args[1]}
+
+	if !strings.EqualFold(a.syntax, "collection") {
+		tok, err := p.next()
+		if err == io.EOF || tok == "{" || tok == "}" {
+			return attr{}, p.errorf("missing value of %s", a.name)
+		}
+		if err != nil {
+			return attr{}, err
+		}
+
+		if p.tokens.quoted {
+			a.values = append(a.values, value{text: tok})
+		} else {
+			for _, v := range splitValues(tok) {
+				a.values = append(a.values, value{text: v})
+			}
+		}
+		return a, nil
+	}
+
+	// collection values are lists of MEMBER statements in braces separated by commas
+	for {
+		tok, err := p.next()
+		if err != nil || tok != "{" {
+			return attr{}, p.errorf("missing { of collection %s", a.name)
+		}
+
+		var members []attr
+		for {
+			tok, err := p.next()
+			if err != nil {
+				return attr{}, p.errorf("missing } of collection %s", a.name)
+			}
+			if tok == "}" {
+				break
+			}
+			if !strings.EqualFold(tok, "MEMBER") {
+				return attr{}, p.errorf("unexpected %s in collection %s", tok, a.name)
+			}
+
+			member, err := p.attr(tok)
+			if err != nil {
+				return attr{}, err
+			}
+			members = append(members, member)
+		}
+		a.values = append(a.values, value{members: members})
+
+		tok, err = p.next()
+		if err != nil && err != io.EOF {
+			return attr{}, err
+		}
+		if tok != "," {
+			p.tokens.unread(tok, err)
+			return a, nil
+		}
+	}
+}
+
+// expect parses the name and the predicates of an EXPECT or STATUS statement
+func (p *parser) expect(directive string) (*expect, error) {
+	args, err := p.args(directive, 1)
+	if err != nil {
+		return nil, err
+	}
+	e := &expect{name: args[0]}
+
+	for {
+		tok, err := p.next()
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		name := strings.ToUpper(tok)
+		n, ok := predicateArgs[name]
+		if err == io.EOF || p.tokens.quoted || !ok {
+			p.tokens.unread(tok, err)
+			return e, nil
+		}
+
+		pred := predicate{name: name}
+		if n > 0 {
+			args, err := p.args(tok, 1)
+			if err != nil {
+				return nil, err
+			}
+			pred.arg = args[0]
+		}
+		e.predicates = append(e.predicates, pred)
+	}
+}
+
+// splitValues splits an unquoted ATTR value at the commas, a comma escaped with a backslash is kept
+func splitValues(s string) []string {
+	var values []string
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == ',':
+			b.WriteByte(',')
+			i++
+		case s[i] == ',':
+			values = append(values, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return append(values, b.String())
+}
+
+// tokenizer splits a test file into tokens. tokens are separated by white space, strings in double or single quotes
+// are a single token and braces at the start of a token are tokens of their own, so ${name} stays a single token.
+// comments start with # at the start of a token and end at the end of the line
+type tokenizer struct {
+	r    *bufio.Reader
+	line int
+	// quoted is set if the last token was a quoted string
+	quoted bool
+
+	pending    *string
+	pendingErr error
+	pendingQ   bool
+}
+
+// unread pushes the last token back
+func (t *tokenizer) unread(tok string, err error) {
+	t.pending, t.pendingErr, t.pendingQ = &tok, err, t.quoted
+}
+
+func (t *tokenizer) next() (string, error) {
+	if t.pending != nil {
+		tok, err := *t.pending, t.pendingErr
+		t.pending, t.quoted = nil, t.pendingQ
+		return tok, err
+	}
+	t.quoted = false
+
+	c, err := t.skipSpace()
+	if err != nil {
+		return "", err
+	}
+
+	if c == '{' || c == '}' {
+		return string(c), nil
+	}
+
+	var b strings.Builder
+	if c == '"' || c == '\'' {
+		t.quoted = true
+		quote := c
+		for {
+			c, err := t.r.ReadByte()
+			if err != nil {
+				return "", errors.New("unterminated string")
+			}
+			if c == quote {
+				return b.String(), nil
+			}
+			if c == '\n' {
+				t.line++
+			}
+			if c == '\\' {
+				if c, err = t.r.ReadByte(); err != nil {
+					return "", errors.New("unterminated string")
+				}
+			}
+			b.WriteByte(c)
+		}
+	}
+
+	b.WriteByte(c)
+	for {
+		c, err := t.r.ReadByte()
+		if err == io.EOF {
+			return b.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		// the comma between two collection values is a token of its own
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' || (c == '{' || c == '}') && b.String() == "," {
+			_ = t.r.UnreadByte()
+			return b.String(), nil
+		}
+		b.WriteByte(c)
+	}
+}
+
+// skipSpace skips white space and comments and returns the first byte of the next token
+func (t *tokenizer) skipSpace() (byte, error) {
+	for {
+		c, err := t.r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+
+		switch c {
+		case '\n':
+			t.line++
+		case '#':
+			if _, err := t.r.ReadString('\n'); err != nil {
+				return 0, err
+			}
+			t.line++
+		case ' ', '\t', '\r':
+		default:
+			return c, nil
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package ipptool
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	file, err := Parse(strings.NewReader(`
+# get the media of the printer
+DEFINE media iso_a4_210x297mm
+IGNORE-ERRORS yes
+{
+	NAME "Print with \"media-col\""
+	OPERATION Print-Job
+	GROUP operation-attributes-tag
+	ATTR keyword requested-attributes media-col-default,media\,ready
+	ATTR name job-name "a, b"
+	GROUP job-attributes-tag
+	ATTR collection media-col {
+		MEMBER collection media-size {
+			MEMBER integer x-dimension 21000
+			MEMBER integer y-dimension 29700
+		}
+		MEMBER keyword media-source tray-1
+	},{
+		MEMBER keyword media-source tray-2
+	}
+	FILE document.pdf
+	STATUS successful-ok
+	STATUS client-error-document-format-not-supported IF-DEFINED pdf
+	EXPECT job-id OF-TYPE integer WITH-VALUE >0
+	EXPECT !job-printer-state-message
+	DISPLAY job-state
+}
+`), "print.test")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []step{
+		{directive: "DEFINE", args: []string{"media", "iso_a4_210x297mm"}},
+		{directive: "IGNORE-ERRORS", args: []string{"yes"}},
+	}, file.steps[:2])
+
+	tests := file.Tests()
+	assert.Len(t, tests, 1)
+	test := tests[0]
+	assert.Equal(t, `Print with "media-col"`, test.Name)
+	assert.Equal(t, "Print-Job", test.Operation)
+	assert.Equal(t, "document.pdf", test.file)
+	assert.Equal(t, []string{"job-state"}, test.displays)
+
+	assert.Len(t, test.groups, 2)
+	assert.Equal(t, []attr{
+		{syntax: "keyword", name: "requested-attributes", values: []value{{text: "media-col-default"}, {text: "media,ready"}}},
+		{syntax: "name", name: "job-name", values: []value{{text: "a, b"}}},
+	}, test.groups[0].attrs)
+
+	mediaCol := test.groups[1].attrs[0]
+	assert.Equal(t, "media-col", mediaCol.name)
+	assert.Len(t, mediaCol.values, 2)
+	assert.Equal(t, "media-size", mediaCol.values[0].members[0].name)
+	assert.Len(t, mediaCol.values[0].members[0].values[0].members, 2)
+	assert.Equal(t, []attr{{syntax: "keyword", name: "media-source", values: []value{{text: "tray-2"}}}},
+		mediaCol.values[1].members)
+
+	assert.Equal(t, []*expect{
+		{name: "successful-ok"},
+		{name: "client-error-document-format-not-supported", predicates: []predicate{{"IF-DEFINED", "pdf"}}},
+	}, test.statuses)
+	assert.Equal(t, []*expect{
+		{name: "job-id", predicates: []predicate{{"OF-TYPE", "integer"}, {"WITH-VALUE", ">0"}}},
+		{name: "!job-printer-state-message"},
+	}, test.expects)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{"unclosed test", "{ NAME test"},
+		{"attr without group", "{ ATTR keyword which-jobs all }"},
+		{"missing argument", "{ OPERATION }"},
+		{"unsupported directive", "{ MONITOR { } }"},
+		{"unknown top level directive", "OPERATION Get-Jobs"},
+		{"unterminated string", `{ NAME "test }`},
+		{"missing member brace", "{ GROUP job-attributes-tag ATTR collection media-col MEMBER }"},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(strings.NewReader(test.source), "broken.test")
+		assert.ErrorIs(t, err, SyntaxError, test.name)
+	}
+}
@@ -0,0 +1,601 @@
+package ipptool
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// IncludeError is returned if an included test file cannot be found or parsed
+var IncludeError = errors.New("cannot include file")
+
+// Result is the outcome of a single test
+type Result struct {
+	// File is the name of the file which contains the test
+	File      string
+	Name      string
+	Operation string
+	// Skipped is set for tests skipped by SKIP-IF-* or SKIP-PREVIOUS-ERROR statements
+	Skipped bool
+	// Errors are the failed checks of the test, e.g. EXPECTED: printer-state OF-TYPE enum (got keyword)
+	Errors []string
+	// Displayed are the values of the DISPLAY attributes, e.g. printer-state (enum) = idle
+	Displayed []string
+	// Response is nil if the request could not be sent
+	Response *ipp.Response
+}
+
+// Passed reports whether all checks of the test passed, skipped tests pass
+func (r Result) Passed() bool {
+	return len(r.Errors) == 0
+}
+
+// Runner runs test files against a printer
+type Runner struct {
+	Client *ipp.IPPClient
+	// URI is the printer uri of the tests, e.g. ipp://printer.local:631/ipp/print. it is available as the $uri
+	// variable and the requests are sent to its http url
+	URI string
+	// Vars are additional variables of the tests, e.g. filename for the document of print tests
+	Vars map[string]string
+	// DataDir is the directory of the files included with INCLUDE <name>, e.g. /usr/share/cups/ipptool
+	DataDir string
+	// Output receives a line with the result of every test and the failed checks in the style of ipptool -t,
+	// nothing is written if it is nil
+	Output io.Writer
+}
+
+// NewRunner returns a runner for the printer uri, the client uses tls for ipps uris and the user of the uri
+func NewRunner(uri string) (*Runner, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	host, port, useTLS, err := uriAddress(u)
+	if err != nil {
+		return nil, err
+	}
+
+	password, _ := u.User.Password()
+	client := ipp.NewIPPClient(host, port, u.User.Username(), password, useTLS)
+
+	return &Runner{Client: client, URI: uri}, nil
+}
+
+// uriAddress returns the host, port and tls setting of an ipp, ipps, http or https uri
+func uriAddress(u *url.URL) (string, int, bool, error) {
+	var useTLS bool
+	switch u.Scheme {
+	case "ipp", "http":
+	case "ipps", "https":
+		useTLS = true
+	default:
+		return "", 0, false, fmt.Errorf("unsupported uri scheme %s", u.Scheme)
+	}
+
+	port := 631
+	if u.Port() != "" {
+		var err error
+		if port, err = strconv.Atoi(u.Port()); err != nil {
+			return "", 0, false, err
+		}
+	}
+
+	return u.Hostname(), port, useTLS, nil
+}
+
+// RunFile parses and runs the test file at path. a failed test stops the file unless IGNORE-ERRORS is set, the
+// results of the tests run so far are returned. the error is only set if a file cannot be parsed or read
+func (r *Runner) RunFile(path string) ([]Result, error) {
+	file, err := ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Run(file)
+}
+
+// Run runs the tests of a parsed file, see RunFile
+func (r *Runner) Run(file *File) ([]Result, error) {
+	if r.Client == nil {
+		return nil, errors.New("runner without client")
+	}
+
+	u, err := url.Parse(r.URI)
+	if err != nil {
+		return nil, err
+	}
+	host, port, useTLS, err := uriAddress(u)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := "http"
+	if useTLS {
+		scheme = "https"
+	}
+
+	state := &run{
+		runner:  r,
+		httpURL: scheme + "://" + net.JoinHostPort(host, strconv.Itoa(port)) + u.RequestURI(),
+		vars: map[string]string{
+			"uri":      r.URI,
+			"scheme":   u.Scheme,
+			"uriuser":  u.User.Username(),
+			"hostname": host,
+			"port":     strconv.Itoa(port),
+			"resource": u.RequestURI(),
+			"user":     os.Getenv("USER"),
+		},
+	}
+	for name, value := range r.Vars {
+		state.vars[name] = value
+	}
+
+	err = state.file(file)
+	return state.results, err
+}
+
+// run is the state of a running file and its included files
+type run struct {
+	runner  *Runner
+	httpURL string
+	vars    map[string]string
+	results []Result
+
+	ignoreErrors bool
+	version      string
+	// failed is set if the previous test failed, stopped if a failed test ended the run
+	failed  bool
+	stopped bool
+}
+
+func (r *run) file(file *File) error {
+	if r.runner.Output != nil {
+		fmt.Fprintf(r.runner.Output, "%s:\n", file.Name)
+	}
+	dir := filepath.Dir(file.Name)
+
+	for _, s := range file.steps {
+		if r.stopped {
+			return nil
+		}
+		if s.test != nil {
+			r.test(s.test, file.Name, dir)
+			continue
+		}
+
+		args := make([]string, len(s.args))
+		for i, arg := range s.args {
+			args[i] = r.expand(arg)
+		}
+
+		switch s.directive {
+		case "DEFINE":
+			r.vars[args[0]] = args[1]
+		case "DEFINE-DEFAULT":
+			if _, ok := r.vars[args[0]]; !ok {
+				r.vars[args[0]] = args[1]
+			}
+		case "IGNORE-ERRORS":
+			r.ignoreErrors = yes(args[0])
+		case "VERSION":
+			r.version = args[0]
+		case "INCLUDE":
+			if err := r.include(args[0], dir); err != nil {
+				return err
+			}
+		case "INCLUDE-IF-DEFINED", "INCLUDE-IF-NOT-DEFINED":
+			if _, ok := r.vars[args[0]]; ok == (s.directive == "INCLUDE-IF-DEFINED") {
+				if err := r.include(args[1], dir); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// include runs an included file, <name> is relative to the data directory and other names to dir
+func (r *run) include(name, dir string) error {
+	path := r.path(name, dir)
+	if path == "" {
+		return fmt.Errorf("%w: %s without data directory", IncludeError, name)
+	}
+
+	file, err := ParseFile(path)
+	if err != nil {
+		return fmt.Errorf("%w: %w", IncludeError, err)
+	}
+
+	return r.file(file)
+}
+
+// path resolves the name of an included or sent file, an empty string is returned for <name> without data directory
+func (r *run) path(name, dir string) string {
+	if strings.HasPrefix(name, "<") && strings.HasSuffix(name, ">") {
+		if r.runner.DataDir == "" {
+			return ""
+		}
+		return filepath.Join(r.runner.DataDir, name[1:len(name)-1])
+	}
+	if filepath.IsAbs(name) {
+		return name
+	}
+
+	return filepath.Join(dir, name)
+}
+
+func (r *run) test(t *Test, fileName, dir string) {
+	for _, define := range t.defines {
+		r.vars[r.expand(define[0])] = r.expand(define[1])
+	}
+
+	result := Result{File: fileName, Name: r.expand(t.Name), Operation: r.expand(t.Operation)}
+	if result.Name == "" {
+		result.Name = result.Operation
+	}
+
+	ignoreErrors := r.ignoreErrors
+	if t.ignoreErrors != "" {
+		ignoreErrors = yes(r.expand(t.ignoreErrors))
+	}
+
+	if r.skip(t, dir) {
+		result.Skipped = true
+		r.report(result)
+		return
+	}
+
+	if t.delay != "" {
+		seconds, _, _ := strings.Cut(r.expand(t.delay), ",")
+		if delay, err := strconv.ParseFloat(seconds, 64); err == nil {
+			time.Sleep(time.Duration(delay * float64(time.Second)))
+		}
+	}
+
+	if err := r.send(t, dir, &result); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	} else {
+		r.check(t, &result)
+	}
+
+	r.report(result)
+	r.failed = !result.Passed()
+	r.stopped = r.failed && !ignoreErrors
+}
+
+// skip reports whether one of the SKIP-IF-* statements or SKIP-PREVIOUS-ERROR skips the test
+func (r *run) skip(t *Test, dir string) bool {
+	if t.skipPrevious != "" && yes(r.expand(t.skipPrevious)) && r.failed {
+		return true
+	}
+
+	for _, c := range t.skipIf {
+		arg := r.expand(c.arg)
+		_, defined := r.vars[arg]
+
+		switch c.directive {
+		case "SKIP-IF-DEFINED":
+			if defined {
+				return true
+			}
+		case "SKIP-IF-NOT-DEFINED":
+			if !defined {
+				return true
+			}
+		case "SKIP-IF-MISSING":
+			path := r.path(arg, dir)
+			if _, err := os.Stat(path); path == "" || err != nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// send builds the request of the test, sends it with the document of FILE and stores the response in the result
+func (r *run) send(t *Test, dir string, result *Result) error {
+	req, err := r.request(t)
+	if err != nil {
+		return err
+	}
+
+	if t.file != "" {
+		path := r.path(r.expand(t.file), dir)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		req.File, req.FileSize = bytes.NewReader(data), len(data)
+	}
+
+	resp, err := r.runner.Client.SendRequest(r.httpURL, req, nil)
+	if err != nil && !errors.As(err, new(ipp.StatusError)) {
+		return err
+	}
+	result.Response = resp
+
+	return nil
+}
+
+// request builds the request of a test
+func (r *run) request(t *Test) (*ipp.Request, error) {
+	name := r.expand(t.Operation)
+	operation, err := ipp.ParseOperation(name)
+	if err != nil {
+		value, parseErr := strconv.ParseInt(name, 0, 16)
+		if parseErr != nil {
+			return nil, err
+		}
+		operation = ipp.Operation(value)
+	}
+
+	req := ipp.NewRequest(int16(operation), 1)
+
+	version := r.version
+	if t.Version != "" {
+		version = r.expand(t.Version)
+	}
+	if version != "" {
+		var major, minor int8
+		if _, err := fmt.Sscanf(version, "%d.%d", &major, &minor); err != nil {
+			return nil, fmt.Errorf("invalid version %s", version)
+		}
+		req.ProtocolVersionMajor, req.ProtocolVersionMinor = major, minor
+	}
+
+	for _, g := range t.groups {
+		var attributes map[string]interface{}
+
+		groupName := r.expand(g.name)
+		switch groupTags[strings.TrimSuffix(strings.ToLower(groupName), "-attributes-tag")] {
+		case ipp.TagOperation:
+			attributes = req.OperationAttributes
+		case ipp.TagJob:
+			attributes = req.JobAttributes
+		case ipp.TagPrinter:
+			attributes = req.PrinterAttributes
+		case ipp.TagSubscription:
+			attributes = req.SubscriptionAttributes
+		default:
+			return nil, fmt.Errorf("unsupported request group %s", groupName)
+		}
+
+		for _, a := range g.attrs {
+			values, err := r.attribute(a)
+			if err != nil {
+				return nil, err
+			}
+			attributes[values[0].Name] = values
+		}
+	}
+
+	return req, nil
+}
+
+// groupTags are the delimiter tags of the group names without the -attributes-tag suffix
+var groupTags = map[string]int8{
+	"operation":          ipp.TagOperation,
+	"job":                ipp.TagJob,
+	"printer":            ipp.TagPrinter,
+	"unsupported":        ipp.TagUnsupportedGroup,
+	"subscription":       ipp.TagSubscription,
+	"event-notification": ipp.TagEventNotification,
+	"resource":           ipp.TagResource,
+	"document":           ipp.TagDocument,
+	"system":             ipp.TagSystem,
+}
+
+// groupName returns the ipptool name of a group tag, e.g. printer-attributes-tag
+func groupName(tag int8) string {
+	for name, t := range groupTags {
+		if t == tag {
+			return name + "-attributes-tag"
+		}
+	}
+
+	return fmt.Sprintf("0x%02x", uint8(tag))
+}
+
+// attribute returns the values of an ATTR or MEMBER statement
+func (r *run) attribute(a attr) ([]ipp.Attribute, error) {
+	tag, ok := ipp.ParseTagSyntax(a.syntax)
+	if !ok {
+		return nil, fmt.Errorf("unknown syntax %s of %s", a.syntax, a.name)
+	}
+
+	name := r.expand(a.name)
+	values := make([]ipp.Attribute, len(a.values))
+	for i, v := range a.values {
+		value, err := r.value(name, tag, v)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = ipp.Attribute{Tag: tag, Name: name, Value: value}
+	}
+
+	return values, nil
+}
+
+// value converts an ATTR value into the value type of the tag
+func (r *run) value(name string, tag int8, v value) (interface{}, error) {
+	if tag == ipp.TagBeginCollection {
+		members := make(ipp.Attributes, len(v.members))
+		for _, member := range v.members {
+			values, err := r.attribute(member)
+			if err != nil {
+				return nil, err
+			}
+			members[values[0].Name] = values
+		}
+		return members, nil
+	}
+
+	text := r.expand(v.text)
+	invalid := fmt.Errorf("invalid %s value %q of %s", ipp.TagSyntax(tag), text, name)
+
+	switch tag {
+	case ipp.TagInteger:
+		i, err := strconv.Atoi(text)
+		if err != nil {
+			return nil, invalid
+		}
+		return i, nil
+	case ipp.TagEnum:
+		if i, err := strconv.Atoi(text); err == nil {
+			return i, nil
+		}
+		i, ok := ipp.ParseEnumKeyword(name, text)
+		if !ok {
+			return nil, invalid
+		}
+		return i, nil
+	case ipp.TagBoolean:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, invalid
+		}
+		return b, nil
+	case ipp.TagRange:
+		lower, upper, _ := strings.Cut(text, "-")
+		l, err1 := strconv.ParseInt(lower, 10, 32)
+		u, err2 := strconv.ParseInt(upper, 10, 32)
+		if err1 != nil || err2 != nil {
+			return nil, invalid
+		}
+		return []int32{int32(l), int32(u)}, nil
+	case ipp.TagResolution:
+		resolution, ok := parseResolution(text)
+		if !ok {
+			return nil, invalid
+		}
+		return resolution, nil
+	case ipp.TagDate:
+		return nil, fmt.Errorf("unsupported dateTime value of %s", name)
+	}
+
+	// out of band values like no-value have an empty value on the wire
+	if tag >= ipp.TagUnsupportedValue && tag <= ipp.TagAdminDefine {
+		return "", nil
+	}
+
+	return text, nil
+}
+
+// parseResolution parses a resolution like 600dpi, 600x300dpi or 118dpcm
+func parseResolution(s string) (ipp.Resolution, bool) {
+	var depth int8
+	switch {
+	case strings.HasSuffix(s, "dpi"):
+		s, depth = strings.TrimSuffix(s, "dpi"), 3
+	case strings.HasSuffix(s, "dpcm"):
+		s, depth = strings.TrimSuffix(s, "dpcm"), 4
+	default:
+		return ipp.Resolution{}, false
+	}
+
+	x, y, found := strings.Cut(s, "x")
+	if !found {
+		y = x
+	}
+
+	width, err1 := strconv.ParseInt(x, 10, 32)
+	height, err2 := strconv.ParseInt(y, 10, 32)
+	if err1 != nil || err2 != nil {
+		return ipp.Resolution{}, false
+	}
+
+	return ipp.Resolution{Width: int32(width), Height: int32(height), Depth: depth}, true
+}
+
+// report writes the result of a test to the output of the runner
+func (r *run) report(result Result) {
+	r.results = append(r.results, result)
+
+	w := r.runner.Output
+	if w == nil {
+		return
+	}
+
+	status := "PASS"
+	if result.Skipped {
+		status = "SKIP"
+	} else if !result.Passed() {
+		status = "FAIL"
+	}
+
+	fmt.Fprintf(w, "    %-68.68s [%s]\n", result.Name, status)
+	for _, e := range result.Errors {
+		fmt.Fprintf(w, "        %s\n", e)
+	}
+	for _, d := range result.Displayed {
+		fmt.Fprintf(w, "        %s\n", d)
+	}
+}
+
+// expand replaces the variables of s. $name and ${name} are replaced by the variable, $ENV[name] by the environment
+// variable and $$ by a dollar sign. undefined variables are replaced by an empty string
+func (r *run) expand(s string) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		rest := s[i+1:]
+		switch {
+		case strings.HasPrefix(rest, "$"):
+			b.WriteByte('$')
+			i++
+		case strings.HasPrefix(rest, "ENV[") && strings.Contains(rest, "]"):
+			end := strings.IndexByte(rest, ']')
+			b.WriteString(os.Getenv(rest[4:end]))
+			i += end + 1
+		case strings.HasPrefix(rest, "{") && strings.Contains(rest, "}"):
+			end := strings.IndexByte(rest, '}')
+			b.WriteString(r.vars[rest[1:end]])
+			i += end + 1
+		default:
+			n := 0
+			for n < len(rest) && isNameByte(rest[n]) {
+				n++
+			}
+			if n == 0 {
+				b.WriteByte('$')
+				continue
+			}
+			b.WriteString(r.vars[rest[:n]])
+			i += n
+		}
+	}
+
+	return b.String()
+}
+
+func isNameByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
+
+// yes reports whether a yes or no argument is yes
+func yes(s string) bool {
+	return strings.EqualFold(s, "yes") || strings.EqualFold(s, "true")
+}
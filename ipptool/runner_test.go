@@ -0,0 +1,249 @@
+package ipptool
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/server"
+	"github.com/stretchr/testify/assert"
+)
+
+const testFile = `
+DEFINE-DEFAULT media iso_a4_210x297mm
+
+{
+	NAME "Get printer attributes"
+	OPERATION Get-Printer-Attributes
+	GROUP operation-attributes-tag
+	ATTR charset attributes-charset utf-8
+	ATTR naturalLanguage attributes-natural-language en
+	ATTR uri printer-uri $uri
+	ATTR keyword requested-attributes all
+	STATUS successful-ok
+	EXPECT printer-name OF-TYPE name IN-GROUP printer-attributes-tag COUNT 1 WITH-VALUE "/^test$/"
+	EXPECT printer-state OF-TYPE enum WITH-VALUE idle,4
+	EXPECT operations-supported WITH-VALUE Print-Job
+	EXPECT ipp-versions-supported WITH-ALL-VALUES "/^[12][.][01]$/" WITH-DISTINCT-VALUES
+	EXPECT ?printer-alert
+	EXPECT !printer-fax-modem-name
+	EXPECT printer-name DEFINE-VALUE printer
+	EXPECT printer-fax-modem-name DEFINE-NO-MATCH no-fax
+	DISPLAY printer-state
+}
+
+{
+	NAME "Print $printer"
+	OPERATION Print-Job
+	GROUP operation-attributes-tag
+	ATTR charset attributes-charset utf-8
+	ATTR naturalLanguage attributes-natural-language en
+	ATTR uri printer-uri $uri
+	ATTR name job-name ${printer}-job
+	ATTR mimeMediaType document-format application/pdf
+	FILE $filename
+	STATUS successful-ok
+	EXPECT job-id OF-TYPE integer WITH-VALUE >0
+}
+
+{
+	NAME "Get the job"
+	OPERATION Get-Job-Attributes
+	GROUP operation-attributes-tag
+	ATTR uri printer-uri $uri
+	ATTR integer job-id $job-id
+	STATUS successful-ok
+	EXPECT job-name WITH-VALUE "test-job"
+	EXPECT job-id WITH-VALUE $job-id
+}
+
+{
+	NAME "Skip the fax test"
+	SKIP-IF-DEFINED no-fax
+	OPERATION Get-Printer-Attributes
+}
+
+{
+	NAME "Get a missing job"
+	OPERATION Get-Job-Attributes
+	GROUP operation-attributes-tag
+	ATTR uri printer-uri $uri
+	ATTR integer job-id 9999
+	STATUS successful-ok
+	EXPECT job-state
+}
+
+{
+	NAME "Not run after the failed test"
+	OPERATION Get-Printer-Attributes
+}
+`
+
+func newTestRunner(t *testing.T) (*Runner, func()) {
+	printer := server.NewVirtualPrinter("test", nil)
+	s := server.NewServer()
+	printer.Register(s, "/printers/test")
+	ts := httptest.NewServer(s)
+
+	runner, err := NewRunner(strings.Replace(ts.URL, "http://", "ipp://", 1) + "/printers/test")
+	assert.Nil(t, err)
+
+	return runner, ts.Close
+}
+
+func TestRunner_RunFile(t *testing.T) {
+	runner, closeServer := newTestRunner(t)
+	defer closeServer()
+
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "document.pdf"), []byte("%PDF-1.7"), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "print.test"), []byte(testFile), 0o644))
+
+	var output bytes.Buffer
+	runner.Vars = map[string]string{"filename": "document.pdf"}
+	runner.Output = &output
+
+	results, err := runner.RunFile(filepath.Join(dir, "print.test"))
+	assert.Nil(t, err)
+	assert.Len(t, results, 5)
+
+	for _, result := range results[:4] {
+		assert.True(t, result.Passed(), result.Name, result.Errors)
+	}
+	assert.Equal(t, "Print test", results[1].Name)
+	assert.Equal(t, []string{"printer-state (enum) = idle"}, results[0].Displayed)
+	assert.True(t, results[3].Skipped)
+	assert.Nil(t, results[3].Response)
+
+	assert.False(t, results[4].Passed())
+	assert.Equal(t, []string{"EXPECTED: STATUS successful-ok (got client-error-not-found)", "EXPECTED: job-state"},
+		results[4].Errors)
+	assert.Equal(t, int16(ipp.StatusErrorNotFound), results[4].Response.StatusCode)
+
+	assert.Contains(t, output.String(), "print.test:\n")
+	assert.Contains(t, output.String(), "[SKIP]")
+	assert.Contains(t, output.String(), "        EXPECTED: job-state\n")
+}
+
+func TestRunner_Include(t *testing.T) {
+	runner, closeServer := newTestRunner(t)
+	defer closeServer()
+
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "common.test"), []byte(`
+IGNORE-ERRORS yes
+{
+	NAME "Failing test"
+	OPERATION Get-Printer-Attributes
+	GROUP operation-attributes-tag
+	ATTR uri printer-uri $uri
+	EXPECT printer-name WITH-VALUE other
+}`), 0o644))
+
+	file, err := Parse(strings.NewReader(`
+INCLUDE "common.test"
+INCLUDE-IF-DEFINED missing "missing.test"
+{
+	NAME "Runs after the failed test"
+	SKIP-PREVIOUS-ERROR no
+	OPERATION Get-Printer-Attributes
+	GROUP operation-attributes-tag
+	ATTR uri printer-uri $uri
+}`), filepath.Join(dir, "main.test"))
+	assert.Nil(t, err)
+
+	results, err := runner.Run(file)
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, []string{`EXPECTED: printer-name WITH-VALUE "other" (got test)`}, results[0].Errors)
+	assert.True(t, results[1].Passed())
+
+	file, err = Parse(strings.NewReader(`INCLUDE <standard.test>`), filepath.Join(dir, "main.test"))
+	assert.Nil(t, err)
+	_, err = runner.Run(file)
+	assert.ErrorIs(t, err, IncludeError)
+}
+
+func TestRun_Expand(t *testing.T) {
+	t.Setenv("IPPTOOL_TEST", "env")
+	r := &run{vars: map[string]string{"uri": "ipp://printer/ipp/print", "job-id": "7"}}
+
+	assert.Equal(t, "ipp://printer/ipp/print/jobs/7", r.expand("$uri/jobs/$job-id"))
+	assert.Equal(t, "7th", r.expand("${job-id}th"))
+	assert.Equal(t, "env $", r.expand("$ENV[IPPTOOL_TEST] $$"))
+	assert.Equal(t, "[]", r.expand("[$undefined]"))
+}
+
+func TestMatchValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []ipp.Attribute
+		pattern string
+		all     bool
+		match   bool
+	}{
+		{"integer", []ipp.Attribute{{Tag: ipp.TagInteger, Value: 5}}, "5", false, true},
+		{"integer list", []ipp.Attribute{{Tag: ipp.TagInteger, Value: 5}}, "1,5", false, true},
+		{"integer comparison", []ipp.Attribute{{Tag: ipp.TagInteger, Value: 5}}, ">=6", false, false},
+		{"enum keyword", []ipp.Attribute{{Tag: ipp.TagEnum, Value: 5}}, "high", false, true},
+		{"range", []ipp.Attribute{{Tag: ipp.TagRange, Value: []int32{1, 99}}}, "50", false, true},
+		{"range upper bound", []ipp.Attribute{{Tag: ipp.TagRange, Value: []int32{1, 99}}}, "<100", false, true},
+		{"range literal", []ipp.Attribute{{Tag: ipp.TagRange, Value: []int32{1, 99}}}, "1-99", false, true},
+		{"keyword", []ipp.Attribute{{Tag: ipp.TagKeyword, Value: "one-sided"}}, "one-sided", false, true},
+		{"regex", []ipp.Attribute{{Tag: ipp.TagKeyword, Value: "two-sided-long-edge"}}, "/^two-/", false, true},
+		{"any value", []ipp.Attribute{
+			{Tag: ipp.TagKeyword, Value: "one-sided"},
+			{Tag: ipp.TagKeyword, Value: "two-sided-long-edge"},
+		}, "/^two-/", false, true},
+		{"all values", []ipp.Attribute{
+			{Tag: ipp.TagKeyword, Value: "one-sided"},
+			{Tag: ipp.TagKeyword, Value: "two-sided-long-edge"},
+		}, "/^two-/", true, false},
+		{"resolution", []ipp.Attribute{{Tag: ipp.TagResolution, Value: ipp.Resolution{Width: 600, Height: 600, Depth: 3}}},
+			"600dpi", false, true},
+	}
+
+	for _, test := range tests {
+		match, err := matchValues(ipp.AttributePrintQuality, test.values, test.pattern, test.all)
+		assert.Nil(t, err, test.name)
+		assert.Equal(t, test.match, match, test.name)
+	}
+
+	_, err := matchValues("sides", []ipp.Attribute{{Tag: ipp.TagKeyword, Value: "one-sided"}}, "/(/", false)
+	assert.NotNil(t, err)
+}
+
+func TestRun_Value(t *testing.T) {
+	r := &run{vars: map[string]string{"copies": "2"}}
+
+	tests := []struct {
+		syntax string
+		name   string
+		text   string
+		value  interface{}
+	}{
+		{"integer", "copies", "$copies", 2},
+		{"enum", "print-quality", "high", 5},
+		{"enum", "orientation-requested", "4", 4},
+		{"boolean", "color-supported", "true", true},
+		{"rangeOfInteger", "page-ranges", "1-5", []int32{1, 5}},
+		{"resolution", "printer-resolution", "600x300dpi", ipp.Resolution{Width: 600, Height: 300, Depth: 3}},
+		{"keyword", "sides", "one-sided", "one-sided"},
+		{"no-value", "job-hold-until", "", ""},
+	}
+
+	for _, test := range tests {
+		values, err := r.attribute(attr{syntax: test.syntax, name: test.name, values: []value{{text: test.text}}})
+		assert.Nil(t, err, test.name)
+		assert.Equal(t, test.value, values[0].Value, test.name)
+	}
+
+	_, err := r.attribute(attr{syntax: "integer", name: "copies", values: []value{{text: "two"}}})
+	assert.NotNil(t, err)
+	_, err = r.attribute(attr{syntax: "unknown-syntax", name: "copies", values: []value{{text: "2"}}})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,54 @@
+package ipp
+
+// JobAccounting contains the job-account-id, job-accounting-user-id and job-account-type attributes of pwg 5100.7,
+// they identify whom a job is charged to, e.g. by managed print services. empty fields are not sent
+type JobAccounting struct {
+	AccountID string
+	UserID    string
+	// AccountType is one of the JobAccountType constants, e.g. JobAccountTypeGroup for cost centers
+	AccountType string
+}
+
+// JobAttributes returns the job attributes of the accounting, they can be added to the job attributes of a
+// submission, e.g. the ones passed to IPPClient.PrintJob
+func (a JobAccounting) JobAttributes() map[string]interface{} {
+	attributes := make(map[string]interface{})
+
+	if a.AccountID != "" {
+		attributes[AttributeJobAccountID] = a.AccountID
+	}
+	if a.UserID != "" {
+		attributes[AttributeJobAccountingUserID] = a.UserID
+	}
+	if a.AccountType != "" {
+		attributes[AttributeJobAccountType] = a.AccountType
+	}
+
+	return attributes
+}
+
+// IsZero reports whether none of the accounting attributes is set
+func (a JobAccounting) IsZero() bool {
+	return a == JobAccounting{}
+}
+
+// Unmarshal populates the accounting from the attributes of a job
+func (a *JobAccounting) Unmarshal(attributes Attributes) error {
+	u := attributeUnmarshaler{attributes: attributes}
+	*a = u.jobAccounting()
+
+	return u.err
+}
+
+func (u *attributeUnmarshaler) jobAccounting() JobAccounting {
+	return JobAccounting{
+		AccountID:   u.string(AttributeJobAccountID),
+		UserID:      u.string(AttributeJobAccountingUserID),
+		AccountType: u.string(AttributeJobAccountType),
+	}
+}
+
+// WithJobAccounting adds the accounting attributes to the job attributes of a request
+func WithJobAccounting(accounting JobAccounting) RequestOption {
+	return WithJobAttributes(accounting.JobAttributes())
+}
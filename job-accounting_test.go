@@ -0,0 +1,36 @@
+package ipp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobAccounting(t *testing.T) {
+	accounting := JobAccounting{AccountID: "cc-4711", UserID: "alice", AccountType: JobAccountTypeGeneral}
+	assert.False(t, accounting.IsZero())
+	assert.True(t, JobAccounting{}.IsZero())
+	assert.Empty(t, JobAccounting{}.JobAttributes())
+	assert.Equal(t, map[string]interface{}{AttributeJobAccountID: "cc-4711"},
+		JobAccounting{AccountID: "cc-4711"}.JobAttributes())
+
+	req := NewRequest(OperationPrintJob, 1, WithJobAccounting(accounting))
+	data, err := req.Encode()
+	assert.Nil(t, err)
+
+	// the values are sent with the tags of pwg 5100.7
+	scanner, err := NewMessageScanner(bytes.NewReader(data))
+	assert.Nil(t, err)
+	attributes := make(Attributes)
+	for _, attr := range scanner.Values() {
+		attributes[attr.Name] = append(attributes[attr.Name], attr)
+	}
+	assert.Nil(t, scanner.Err())
+	assert.Equal(t, TagKeyword, attributes[AttributeJobAccountType][0].Tag)
+	assert.Equal(t, TagName, attributes[AttributeJobAccountingUserID][0].Tag)
+
+	var parsed JobAccounting
+	assert.Nil(t, parsed.Unmarshal(attributes))
+	assert.Equal(t, accounting, parsed)
+}
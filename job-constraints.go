@@ -0,0 +1,246 @@
+package ipp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+var JobConstraintError = errors.New("conflicting job attributes")
+
+// JobConstraint is a collection of job-constraints-supported of pwg 5100.13. the job template attributes of the
+// constraint conflict if a job uses all of them with one of the listed values, e.g. media-type transparency and sides
+// two-sided-long-edge
+type JobConstraint struct {
+	// ResolverName is the name of the JobResolver which resolves the conflict
+	ResolverName string
+	// Attributes are the conflicting job template attributes, collections like media-col conflict if all listed
+	// members match
+	Attributes Attributes
+}
+
+// JobResolver is a collection of job-resolvers-supported, it lists alternative values of job template attributes in
+// the order of preference which resolve the constraints with its name
+type JobResolver struct {
+	Name       string
+	Attributes Attributes
+}
+
+// JobConstraints are the constraints and resolvers of a printer, they detect and resolve conflicting job template
+// attributes on the client before a job is submitted
+type JobConstraints struct {
+	Constraints []JobConstraint
+	// Resolvers are the resolvers by name
+	Resolvers map[string]JobResolver
+	// Defaults are the job template defaults of the printer by attribute name, e.g. sides for sides-default. they
+	// apply to the attributes a job doesn't set
+	Defaults Attributes
+}
+
+// NewJobConstraints reads job-constraints-supported, job-resolvers-supported and the job template defaults from
+// printer attributes, e.g. the result of IPPClient.GetPrinterAttributes with RequestedJobTemplate
+func NewJobConstraints(printer Attributes) *JobConstraints {
+	c := &JobConstraints{Resolvers: make(map[string]JobResolver), Defaults: make(Attributes)}
+	u := attributeUnmarshaler{attributes: printer}
+
+	for _, collection := range u.collections(AttributeJobConstraintsSupported) {
+		r := attributeUnmarshaler{attributes: collection}
+		constraint := JobConstraint{ResolverName: r.string(AttributeResolverName), Attributes: maps.Clone(collection)}
+		delete(constraint.Attributes, AttributeResolverName)
+		c.Constraints = append(c.Constraints, constraint)
+	}
+
+	for _, collection := range u.collections(AttributeJobResolversSupported) {
+		r := attributeUnmarshaler{attributes: collection}
+		resolver := JobResolver{Name: r.string(AttributeResolverName), Attributes: maps.Clone(collection)}
+		delete(resolver.Attributes, AttributeResolverName)
+		c.Resolvers[resolver.Name] = resolver
+	}
+
+	for name := range printer {
+		if template, ok := strings.CutSuffix(name, "-default"); ok {
+			if values := u.values(name); len(values) > 0 {
+				c.Defaults[template] = values
+			}
+		}
+	}
+
+	return c
+}
+
+// GetJobConstraints requests the constraints, resolvers and job template defaults of the printer
+func (c *IPPClient) GetJobConstraints(printer string) (*JobConstraints, error) {
+	attributes, err := c.QueryPrinterAttributes(printer,
+		WithRequestedAttributes(AttributeJobConstraintsSupported, AttributeJobResolversSupported),
+		WithGroups(RequestedJobTemplate))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewJobConstraints(attributes), nil
+}
+
+// ResolveJobAttributes resolves conflicting job template attributes with the constraints of the printer before they
+// are submitted, e.g. with PrintJob. the attributes are returned unchanged if they don't conflict
+func (c *IPPClient) ResolveJobAttributes(printer string, jobAttributes map[string]interface{}) (map[string]interface{},
+	error) {
+	constraints, err := c.GetJobConstraints(printer)
+	if err != nil {
+		return nil, err
+	}
+
+	return constraints.Resolve(jobAttributes)
+}
+
+// Conflicts returns the constraints the job attributes violate, none if the attributes can be submitted
+func (c *JobConstraints) Conflicts(jobAttributes map[string]interface{}) ([]JobConstraint, error) {
+	attributes, err := jobTemplate(jobAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []JobConstraint
+	for _, i := range c.conflicts(attributes) {
+		conflicts = append(conflicts, c.Constraints[i])
+	}
+
+	return conflicts, nil
+}
+
+// Resolve returns a copy of the job attributes without conflicts. each conflict is resolved with the first value of
+// its resolver which removes the conflict without adding others, the values are set as Attribute with the tag of
+// the printer. an error wrapping JobConstraintError is returned if a conflict can't be resolved
+func (c *JobConstraints) Resolve(jobAttributes map[string]interface{}) (map[string]interface{}, error) {
+	resolved := maps.Clone(jobAttributes)
+	if resolved == nil {
+		resolved = make(map[string]interface{})
+	}
+
+	// each resolution reduces the conflicts, so the loop ends after one iteration per constraint at the latest
+	for {
+		attributes, err := jobTemplate(resolved)
+		if err != nil {
+			return nil, err
+		}
+		conflicts := c.conflicts(attributes)
+		if len(conflicts) == 0 {
+			return resolved, nil
+		}
+
+		constraint := c.Constraints[conflicts[0]]
+		next, ok := c.resolve(resolved, attributes, conflicts)
+		if !ok {
+			return nil, fmt.Errorf("%w: resolver %q doesn't resolve %s", JobConstraintError, constraint.ResolverName,
+				strings.Join(slices.Sorted(maps.Keys(constraint.Attributes)), ", "))
+		}
+		resolved = next
+	}
+}
+
+// resolve applies the resolver of the first conflict, it returns false if no value of the resolver reduces the
+// conflicts
+func (c *JobConstraints) resolve(jobAttributes map[string]interface{}, attributes Attributes,
+	conflicts []int) (map[string]interface{}, bool) {
+	resolver, ok := c.Resolvers[c.Constraints[conflicts[0]].ResolverName]
+	if !ok {
+		return nil, false
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(resolver.Attributes)) {
+		for _, value := range resolver.Attributes[name] {
+			candidate := maps.Clone(attributes)
+			candidate[name] = []Attribute{value}
+
+			remaining := c.conflicts(candidate)
+			if len(remaining) < len(conflicts) && !slices.Contains(remaining, conflicts[0]) {
+				resolved := maps.Clone(jobAttributes)
+				resolved[name] = value
+				return resolved, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// conflicts returns the indexes of the violated constraints
+func (c *JobConstraints) conflicts(attributes Attributes) []int {
+	var conflicts []int
+	for i, constraint := range c.Constraints {
+		if len(constraint.Attributes) > 0 && c.matchesConstraint(constraint.Attributes, attributes) {
+			conflicts = append(conflicts, i)
+		}
+	}
+
+	return conflicts
+}
+
+func (c *JobConstraints) matchesConstraint(constraint, attributes Attributes) bool {
+	for name, values := range constraint {
+		job, ok := attributes[name]
+		if !ok {
+			job = c.Defaults[name]
+		}
+		if !matchesConstraintValues(values, job) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesConstraintValues reports whether one of the job values is listed in the constraint, a collection matches if
+// all members of the constraint collection match
+func matchesConstraintValues(constraint, job []Attribute) bool {
+	for _, value := range job {
+		for _, listed := range constraint {
+			if members, ok := listed.Value.(Attributes); ok {
+				if jobMembers, ok := value.Value.(Attributes); ok && matchesCollection(members, jobMembers) {
+					return true
+				}
+				continue
+			}
+			if equalValue(listed.Value, value.Value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func matchesCollection(constraint, collection Attributes) bool {
+	for name, values := range constraint {
+		if !matchesConstraintValues(values, collection[name]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// jobTemplate converts job attributes into their decoded form by encoding them, so they can be compared with the
+// values of the printer independent of the go types
+func jobTemplate(jobAttributes map[string]interface{}) (Attributes, error) {
+	data, err := NewRequest(OperationPrintJob, 1, WithJobAttributes(jobAttributes)).Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	scanner, err := NewMessageScanner(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := make(Attributes)
+	for group, attr := range scanner.Values() {
+		if group == TagJob {
+			attributes[attr.Name] = append(attributes[attr.Name], attr)
+		}
+	}
+
+	return attributes, scanner.Err()
+}
@@ -0,0 +1,91 @@
+package ipp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func constrainedPrinter() Attributes {
+	transparency := make(Attributes)
+	transparency.Set(AttributeMediaType, TagKeyword, "transparency")
+
+	duplex := make(Attributes)
+	duplex.Set(AttributeResolverName, TagName, "duplex-transparency")
+	duplex.Set(AttributeMediaCol, TagBeginCollection, transparency)
+	duplex.Set(AttributeSides, TagKeyword, SidesTwoSidedLongEdge, SidesTwoSidedShortEdge)
+
+	draft := make(Attributes)
+	draft.Set(AttributeResolverName, TagName, "missing")
+	draft.Set(AttributePrintQuality, TagEnum, int(PrintQualityHigh))
+	draft.Set(AttributePrintColorMode, TagKeyword, PrintColorModeMonochrome)
+
+	resolver := make(Attributes)
+	resolver.Set(AttributeResolverName, TagName, "duplex-transparency")
+	resolver.Set(AttributeSides, TagKeyword, SidesOneSided)
+
+	printer := make(Attributes)
+	printer.Set(AttributeJobConstraintsSupported, TagBeginCollection, duplex, draft)
+	printer.Set(AttributeJobResolversSupported, TagBeginCollection, resolver)
+	printer.Set(AttributeSidesDefault, TagKeyword, SidesTwoSidedLongEdge)
+	printer.Set(AttributePrintQualityDefault, TagEnum, int(PrintQualityNormal))
+
+	return printer
+}
+
+func TestJobConstraints(t *testing.T) {
+	constraints := NewJobConstraints(constrainedPrinter())
+	assert.Len(t, constraints.Constraints, 2)
+	assert.Equal(t, "duplex-transparency", constraints.Constraints[0].ResolverName)
+	assert.NotContains(t, constraints.Constraints[0].Attributes, AttributeResolverName)
+	assert.Contains(t, constraints.Resolvers, "duplex-transparency")
+
+	transparency := MediaCol{Width: 21000, Height: 29700, Type: "transparency"}.Collection()
+
+	// the sides default conflicts with the media of the job
+	job := map[string]interface{}{AttributeMediaCol: transparency, AttributeCopies: 2}
+	conflicts, err := constraints.Conflicts(job)
+	assert.Nil(t, err)
+	assert.Len(t, conflicts, 1)
+
+	resolved, err := constraints.Resolve(job)
+	assert.Nil(t, err)
+	assert.Equal(t, Attribute{Tag: TagKeyword, Name: AttributeSides, Value: SidesOneSided}, resolved[AttributeSides])
+	assert.Equal(t, 2, resolved[AttributeCopies])
+	assert.NotContains(t, job, AttributeSides)
+	conflicts, err = constraints.Conflicts(resolved)
+	assert.Nil(t, err)
+	assert.Empty(t, conflicts)
+
+	// attributes without conflicts are returned unchanged
+	job = map[string]interface{}{AttributeMediaCol: transparency, AttributeSides: SidesOneSided}
+	resolved, err = constraints.Resolve(job)
+	assert.Nil(t, err)
+	assert.Equal(t, job, resolved)
+
+	job = map[string]interface{}{AttributePrintQuality: PrintQualityHigh, AttributePrintColorMode: "monochrome"}
+	_, err = constraints.Resolve(job)
+	assert.True(t, errors.Is(err, JobConstraintError))
+
+	_, err = constraints.Conflicts(map[string]interface{}{"unknown-attribute": 1})
+	assert.NotNil(t, err)
+}
+
+func TestIPPClient_ResolveJobAttributes(t *testing.T) {
+	adapter := &testAdapter{}
+	adapter.respond = func(req *Request) *Response {
+		resp := NewResponse(StatusOk, req.RequestId)
+		resp.PrinterAttributes = append(resp.PrinterAttributes, constrainedPrinter())
+		return resp
+	}
+	client := NewIPPClientWithAdapter("user", adapter)
+
+	resolved, err := client.ResolveJobAttributes("office", map[string]interface{}{
+		AttributeMediaCol: MediaCol{Type: "transparency"}.Collection(),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, SidesOneSided, resolved[AttributeSides].(Attribute).Value)
+	assert.Equal(t, []string{AttributeJobConstraintsSupported, AttributeJobResolversSupported, RequestedJobTemplate},
+		adapter.requests[0].OperationAttributes[AttributeRequestedAttributes])
+}
@@ -0,0 +1,96 @@
+package ipp
+
+import (
+	"sort"
+	"time"
+)
+
+// Job contains the common job description and status attributes in typed form, as returned by Get-Jobs and
+// Get-Job-Attributes. missing attributes keep their zero value
+type Job struct {
+	ID              int
+	URI             string
+	UUID            string
+	PrinterURI      string
+	Name            string
+	OriginatingUser string
+
+	State        int8
+	StateReasons []string
+	StateMessage string
+
+	// Impressions is the job-impressions-completed of the job
+	Impressions int
+	// MediaSheets and Pages are the job-media-sheets-completed and job-pages-completed of the job
+	MediaSheets       int
+	Pages             int
+	KOctets           int
+	NumberOfDocuments int
+
+	// Accounting is the account the job is charged to, it is zero if the printer doesn't support job accounting
+	Accounting JobAccounting
+
+	// the times are taken from the date-time-at attributes. if the printer only returns the time-at attributes, they
+	// are converted with the PrinterClock of the job attributes
+	CreatedAt    time.Time
+	ProcessingAt time.Time
+	CompletedAt  time.Time
+
+	// Unsupported contains the unsupported attributes group of the response, e.g. the job attributes the printer
+	// ignored or substituted on job creation. it is not populated by Unmarshal
+	Unsupported Attributes
+}
+
+// Unmarshal populates the job from the job attributes of a response. an error wrapping AttributeTypeError is
+// returned if an attribute has an unexpected value type, the other fields are populated nevertheless
+func (j *Job) Unmarshal(attributes Attributes) error {
+	u := attributeUnmarshaler{attributes: attributes}
+
+	j.ID = u.int(AttributeJobID)
+	j.URI = u.string(AttributeJobURI)
+	j.UUID = u.string(AttributeJobUUID)
+	j.PrinterURI = u.string(AttributeJobPrinterURI)
+	j.Name = u.string(AttributeJobName)
+	j.OriginatingUser = u.string(AttributeJobOriginatingUserName)
+
+	j.State = int8(u.int(AttributeJobState))
+	j.StateReasons = u.strings(AttributeJobStateReasons)
+	j.StateMessage = u.string(AttributeJobStateMessage)
+
+	j.Impressions = u.int(AttributeJobImpressionsCompleted)
+	j.MediaSheets = u.int(AttributeJobMediaSheetsCompleted)
+	j.Pages = u.int(AttributeJobPagesCompleted)
+	j.KOctets = u.int(AttributeJobKilobyteOctets)
+	j.NumberOfDocuments = u.int(AttributeNumberOfDocuments)
+	j.Accounting = u.jobAccounting()
+
+	clock := NewPrinterClock(attributes, time.Now())
+	j.CreatedAt = clock.JobTime(attributes, AttributeDateTimeAtCreation, AttributeTimeAtCreation)
+	j.ProcessingAt = clock.JobTime(attributes, AttributeDateTimeAtProcessing, AttributeTimeAtProcessing)
+	j.CompletedAt = clock.JobTime(attributes, AttributeDateTimeAtCompleted, AttributeTimeAtCompleted)
+
+	return u.err
+}
+
+// IsTerminated reports whether the job is completed, canceled or aborted
+func (j *Job) IsTerminated() bool {
+	return j.State == JobStateCompleted || j.State == JobStateCanceled || j.State == JobStateAborted
+}
+
+// UnmarshalJobs converts the result of IPPClient.GetJobs into jobs ordered by id
+func UnmarshalJobs(jobs map[int]Attributes) ([]*Job, error) {
+	result := make([]*Job, 0, len(jobs))
+	for _, attributes := range jobs {
+		job := new(Job)
+		if err := job.Unmarshal(attributes); err != nil {
+			return nil, err
+		}
+		result = append(result, job)
+	}
+
+	sort.Slice(result, func(i, k int) bool {
+		return result[i].ID < result[k].ID
+	})
+
+	return result, nil
+}
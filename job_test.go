@@ -0,0 +1,62 @@
+package ipp
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJob_Unmarshal(t *testing.T) {
+	resp := NewResponse(StatusOk, 1)
+	for id := 2; id >= 1; id-- {
+		attributes := make(Attributes)
+		attributes.Set(AttributeJobID, TagInteger, id)
+		attributes.Set(AttributeJobURI, TagUri, "ipp://localhost/ipp/print/1")
+		attributes.Set(AttributeJobName, TagName, "report")
+		attributes.Set(AttributeJobOriginatingUserName, TagName, "alice")
+		attributes.Set(AttributeJobState, TagEnum, int(JobStateCompleted))
+		attributes.Set(AttributeJobStateReasons, TagKeyword, "job-completed-successfully")
+		attributes.Set(AttributeJobImpressionsCompleted, TagInteger, 4)
+		attributes.Set(AttributeJobKilobyteOctets, TagInteger, 12)
+		attributes.Set(AttributeJobMediaSheetsCompleted, TagInteger, 2)
+		attributes.Set(AttributeJobAccountID, TagName, "marketing")
+		attributes.Set(AttributeJobAccountType, TagKeyword, JobAccountTypeGroup)
+		attributes.Set(AttributeJobPrinterUpTime, TagInteger, 1000)
+		attributes.Set(AttributeTimeAtCreation, TagInteger, 900)
+		attributes.Set(AttributeTimeAtProcessing, TagInteger, 940)
+		attributes.Set(AttributeTimeAtCompleted, TagNoValue, "")
+		// 2021-06-16 10:30:15.5 +02:00
+		attributes.Set(AttributeDateTimeAtCompleted, TagDate, []int{0x07, 0xe5, 6, 16, 10, 30, 15, 5, '+', 2, 0})
+		resp.JobAttributes = append(resp.JobAttributes, attributes)
+	}
+
+	data, err := resp.Encode()
+	assert.Nil(t, err)
+	decoded, err := NewResponseDecoder(bytes.NewReader(data)).Decode(nil)
+	assert.Nil(t, err)
+
+	var job Job
+	assert.Nil(t, job.Unmarshal(decoded.JobAttributes[0]))
+
+	assert.Equal(t, 2, job.ID)
+	assert.Equal(t, "report", job.Name)
+	assert.Equal(t, "alice", job.OriginatingUser)
+	assert.Equal(t, JobStateCompleted, job.State)
+	assert.True(t, job.IsTerminated())
+	assert.Equal(t, []string{"job-completed-successfully"}, job.StateReasons)
+	assert.Equal(t, 4, job.Impressions)
+	assert.Equal(t, 12, job.KOctets)
+	assert.Equal(t, 2, job.MediaSheets)
+	assert.Equal(t, JobAccounting{AccountID: "marketing", AccountType: JobAccountTypeGroup}, job.Accounting)
+
+	assert.WithinDuration(t, time.Now().Add(-100*time.Second), job.CreatedAt, 2*time.Second)
+	assert.Equal(t, 40*time.Second, job.ProcessingAt.Sub(job.CreatedAt))
+	assert.True(t, time.Date(2021, 6, 16, 8, 30, 15, 500000000, time.UTC).Equal(job.CompletedAt))
+
+	jobs, err := UnmarshalJobs(map[int]Attributes{2: decoded.JobAttributes[0], 1: decoded.JobAttributes[1]})
+	assert.Nil(t, err)
+	assert.Len(t, jobs, 2)
+	assert.Equal(t, 1, jobs[0].ID)
+}
@@ -0,0 +1,147 @@
+package ipp
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"mime"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// keywordPattern are the characters of keywords, digits are allowed first for values like the ipp versions
+	keywordPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	// charsetPattern are the characters of the lowercase iana charset names
+	charsetPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9!#$%&'+^_{}~.:()-]*$`)
+	// languagePattern is a rfc 5646 language tag, they are case-insensitive
+	languagePattern  = regexp.MustCompile(`^(?i)[a-z]{1,8}(-[a-z0-9]{1,8})*$`)
+	uriSchemePattern = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+)
+
+// Lint checks the request against the rules of rfc 8011: the header, the target attributes checked by
+// Request.Validate, the presence of attributes-charset and attributes-natural-language, the value tags of the
+// attributes, the value length limits and the characters of keywords, charsets, languages, mime types and uris. it
+// returns the violations, none if the request conforms, e.g. to check manually constructed requests in tests. the
+// order of the groups and of charset and language as first operation attributes is fixed by the encoder, it can't be
+// violated by a Request. the request is not modified
+func Lint(req *Request) []Warning {
+	var violations []Warning
+	violate := func(attribute, format string, args ...interface{}) {
+		violations = append(violations, Warning{Attribute: attribute, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch {
+	case req.ProtocolVersionMajor == 1 && req.ProtocolVersionMinor <= 1:
+	case req.ProtocolVersionMajor == 2 && req.ProtocolVersionMinor <= 2:
+	default:
+		violate("", "unknown ipp version %d.%d", req.ProtocolVersionMajor, req.ProtocolVersionMinor)
+	}
+	if req.Operation <= 0 {
+		violate("", "operation-id %d must be greater than zero", req.Operation)
+	}
+	if req.RequestId <= 0 {
+		violate("", "request-id %d must be greater than zero", req.RequestId)
+	}
+
+	if err := req.Validate(); err != nil {
+		violate("", "%v", err)
+	}
+
+	// the encoder sets a missing charset and language, so their presence is checked before the request is encoded
+	for _, name := range []string{AttributeCharset, AttributeNaturalLanguage} {
+		if _, ok := req.OperationAttributes[name]; !ok {
+			violate(name, "missing %s, it must be sent as operation attribute", name)
+		}
+	}
+
+	// Encode adds the missing charset and language to the operation attributes, it must not change the request of
+	// the caller
+	encoded := *req
+	encoded.OperationAttributes = maps.Clone(req.OperationAttributes)
+	data, err := encoded.Encode()
+	if err != nil {
+		violate("", "cannot encode the request: %v", err)
+		return violations
+	}
+
+	// the structure and the value tags are checked by the decoder
+	decoder := NewRequestDecoder(bytes.NewReader(data))
+	decoder.OnWarning = func(w Warning) {
+		violations = append(violations, w)
+	}
+	if _, err := decoder.Decode(nil); err != nil {
+		violate("", "cannot decode the encoded request: %v", err)
+		return violations
+	}
+
+	scanner, err := NewMessageScanner(bytes.NewReader(data))
+	if err != nil {
+		violate("", "cannot decode the encoded request: %v", err)
+		return violations
+	}
+	for _, attr := range scanner.Values() {
+		lintValue(attr.Name, attr, violate)
+	}
+
+	return violations
+}
+
+// lintValue checks the characters and ranges of a value, the members of collections are checked with their path as
+// name, e.g. media-col.media-size-name
+func lintValue(name string, attr Attribute, violate func(attribute, format string, args ...interface{})) {
+	switch attr.Tag {
+	case TagBeginCollection:
+		members, _ := attr.Value.(Attributes)
+		for _, member := range slices.Sorted(maps.Keys(members)) {
+			for _, value := range members[member] {
+				lintValue(name+"."+member, value, violate)
+			}
+		}
+		return
+	case TagEnum:
+		if value, _ := attr.Value.(int); value < 1 {
+			violate(name, "enum value %d must be greater than zero", value)
+		}
+		return
+	}
+
+	value, ok := attr.Value.(string)
+	if !ok {
+		return
+	}
+
+	switch attr.Tag {
+	case TagKeyword:
+		if !keywordPattern.MatchString(value) {
+			violate(name, "keyword %q contains characters other than a-z, 0-9, '-', '_' and '.'", value)
+		}
+	case TagCharset:
+		if !charsetPattern.MatchString(value) {
+			violate(name, "charset %q is not a lowercase charset name", value)
+		}
+	case TagLanguage:
+		if !languagePattern.MatchString(value) {
+			violate(name, "natural language %q is not a language tag", value)
+		}
+	case TagMimeType:
+		if mediaType, _, err := mime.ParseMediaType(value); err != nil || !strings.Contains(mediaType, "/") {
+			violate(name, "mime media type %q is not a type/subtype", value)
+		}
+	case TagUri:
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" {
+			violate(name, "uri %q is not an absolute uri", value)
+		}
+	case TagUriScheme:
+		if !uriSchemePattern.MatchString(value) {
+			violate(name, "uri scheme %q is invalid", value)
+		}
+	case TagText, TagName:
+		if !utf8.ValidString(value) {
+			violate(name, "value is not valid utf-8")
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package ipp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	valid := func(opts ...RequestOption) *Request {
+		opts = append([]RequestOption{
+			WithOperationAttributes(map[string]interface{}{
+				AttributeCharset:         Charset,
+				AttributeNaturalLanguage: CharsetLanguage,
+			}),
+			WithPrinterURI("ipp://printer.example.com/ipp/print"),
+			WithUser("alice"),
+		}, opts...)
+		return NewRequest(OperationPrintJob, 1, opts...)
+	}
+
+	tests := []struct {
+		name       string
+		req        *Request
+		violations []Warning
+	}{
+		{
+			name: "conforming request",
+			req: valid(WithJobAttributes(map[string]interface{}{
+				AttributeSides:  SidesOneSided,
+				AttributeCopies: 2,
+			})),
+		},
+		{
+			name: "request id",
+			req: func() *Request {
+				req := valid()
+				req.RequestId = 0
+				return req
+			}(),
+			violations: []Warning{{Message: "request-id 0 must be greater than zero"}},
+		},
+		{
+			name: "version",
+			req: func() *Request {
+				req := valid()
+				req.ProtocolVersionMajor = 3
+				return req
+			}(),
+			violations: []Warning{{Message: "unknown ipp version 3.0"}},
+		},
+		{
+			name: "missing charset",
+			req: func() *Request {
+				req := valid()
+				delete(req.OperationAttributes, AttributeCharset)
+				return req
+			}(),
+			violations: []Warning{{
+				Attribute: AttributeCharset,
+				Message:   "missing attributes-charset, it must be sent as operation attribute",
+			}},
+		},
+		{
+			name: "missing language",
+			req: func() *Request {
+				req := valid()
+				delete(req.OperationAttributes, AttributeNaturalLanguage)
+				return req
+			}(),
+			violations: []Warning{{
+				Attribute: AttributeNaturalLanguage,
+				Message:   "missing attributes-natural-language, it must be sent as operation attribute",
+			}},
+		},
+		{
+			name: "missing printer-uri",
+			req: func() *Request {
+				req := valid()
+				delete(req.OperationAttributes, AttributePrinterURI)
+				return req
+			}(),
+			violations: []Warning{{Message: "ipp request target missing: Print-Job requires printer-uri"}},
+		},
+		{
+			name: "keyword",
+			req:  valid(WithJobAttributes(map[string]interface{}{AttributeSides: "One Sided"})),
+			violations: []Warning{{
+				Attribute: AttributeSides,
+				Message:   `keyword "One Sided" contains characters other than a-z, 0-9, '-', '_' and '.'`,
+			}},
+		},
+		{
+			name: "value tag",
+			req: valid(WithJobAttributes(map[string]interface{}{
+				AttributeCopies: Attribute{Tag: TagKeyword, Value: "two"},
+			})),
+			violations: []Warning{{Attribute: AttributeCopies, Message: "value tag 0x44 instead of 0x21"}},
+		},
+		{
+			name: "value length",
+			req: valid(WithOperationAttributes(map[string]interface{}{
+				AttributeJobName: strings.Repeat("a", 256),
+			})),
+			violations: []Warning{{Attribute: AttributeJobName, Message: "value of 256 octets exceeds the limit of 255"}},
+		},
+		{
+			name: "charset and uri",
+			req: valid(WithPrinterURI("printer"), WithOperationAttributes(map[string]interface{}{
+				AttributeCharset: "UTF-8",
+			})),
+			violations: []Warning{
+				{Attribute: AttributeCharset, Message: `charset "UTF-8" is not a lowercase charset name`},
+				{Attribute: AttributePrinterURI, Message: `uri "printer" is not an absolute uri`},
+			},
+		},
+		{
+			name: "mime type",
+			req:  valid(WithOperationAttributes(map[string]interface{}{AttributeDocumentFormat: "pdf"})),
+			violations: []Warning{
+				{Attribute: AttributeDocumentFormat, Message: `mime media type "pdf" is not a type/subtype`},
+			},
+		},
+		{
+			name: "unknown attribute",
+			req:  valid(WithJobAttributes(map[string]interface{}{"x-unknown": 1})),
+			violations: []Warning{{
+				Message: "cannot encode the request: invalid ipp tag: cannot get tag of attribute x-unknown",
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.violations, Lint(test.req))
+		})
+	}
+}
+
+func TestLint_DoesNotModifyRequest(t *testing.T) {
+	req := &Request{ProtocolVersionMajor: 2, Operation: OperationGetPrinterAttributes, RequestId: 1,
+		OperationAttributes: map[string]interface{}{AttributePrinterURI: "ipp://printer.example.com/ipp/print"}}
+
+	assert.Len(t, Lint(req), 2)
+	assert.Len(t, req.OperationAttributes, 1)
+}
+
+// withCharset sets attributes-charset and attributes-natural-language, which the client adds when the request is sent
+func withCharset(req *Request) *Request {
+	req.OperationAttributes[AttributeCharset] = Charset
+	req.OperationAttributes[AttributeNaturalLanguage] = CharsetLanguage
+	return req
+}
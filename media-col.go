@@ -0,0 +1,181 @@
+package ipp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var MediaSizeNameError = errors.New("invalid pwg media size name")
+
+// MediaCol is the media-col collection of a job or printer. dimensions and margins are in hundredths of millimeters
+// like in the wire form
+type MediaCol struct {
+	// SizeName is the pwg self describing media size name, e.g. iso_a4_210x297mm
+	SizeName string
+	Width    int
+	Height   int
+	// Margins are only sent if set, zero margins request borderless printing
+	Margins *MediaMargins
+	Source  string
+	Type    string
+	Color   string
+}
+
+// MediaMargins are the margins of a media-col in hundredths of millimeters
+type MediaMargins struct {
+	Top    int
+	Bottom int
+	Left   int
+	Right  int
+}
+
+// ParseMediaSizeName returns the dimensions of a pwg self describing media size name, e.g. na_letter_8.5x11in, in
+// hundredths of millimeters
+func ParseMediaSizeName(name string) (int, int, bool) {
+	dimensions := name[strings.LastIndex(name, "_")+1:]
+
+	var unit float64
+	switch {
+	case strings.HasSuffix(dimensions, "mm"):
+		unit = 100
+	case strings.HasSuffix(dimensions, "in"):
+		unit = 2540
+	default:
+		return 0, 0, false
+	}
+
+	parts := strings.Split(dimensions[:len(dimensions)-2], "x")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+
+	width, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || width <= 0 {
+		return 0, 0, false
+	}
+
+	height, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil || height <= 0 {
+		return 0, 0, false
+	}
+
+	return int(math.Round(width * unit)), int(math.Round(height * unit)), true
+}
+
+// NewMediaCol creates a media-col for the pwg self describing media size name, e.g. iso_a4_210x297mm or
+// custom_index-card_4x6in. legacy and ppd names of the registry like a4 or Letter are accepted as well. the other
+// members are set with the With methods
+func NewMediaCol(sizeName string) (MediaCol, error) {
+	size, ok := LookupMediaSize(sizeName)
+	if !ok {
+		return MediaCol{}, fmt.Errorf("%w: %s", MediaSizeNameError, sizeName)
+	}
+
+	return MediaCol{SizeName: size.Name, Width: size.Width, Height: size.Height}, nil
+}
+
+// WithSource returns a copy of the media-col with the media-source, e.g. tray-1 or manual
+func (m MediaCol) WithSource(source string) MediaCol {
+	m.Source = source
+	return m
+}
+
+// WithType returns a copy of the media-col with the media-type, e.g. stationery or photographic-glossy
+func (m MediaCol) WithType(mediaType string) MediaCol {
+	m.Type = mediaType
+	return m
+}
+
+// WithColor returns a copy of the media-col with the media-color, e.g. white or blue
+func (m MediaCol) WithColor(color string) MediaCol {
+	m.Color = color
+	return m
+}
+
+// WithMargins returns a copy of the media-col with the margins in hundredths of millimeters
+func (m MediaCol) WithMargins(top, bottom, left, right int) MediaCol {
+	m.Margins = &MediaMargins{Top: top, Bottom: bottom, Left: left, Right: right}
+	return m
+}
+
+// Borderless returns a copy of the media-col with zero margins
+func (m MediaCol) Borderless() MediaCol {
+	return m.WithMargins(0, 0, 0, 0)
+}
+
+// Collection returns the wire form of the media-col, it can be used as value of a media-col job attribute. the
+// media-size-name member is only sent if the dimensions are unknown, since printers prefer the media-size
+func (m MediaCol) Collection() Attributes {
+	c := make(Attributes)
+
+	if m.Width > 0 && m.Height > 0 {
+		size := make(Attributes)
+		size.Set(AttributeXDimension, TagInteger, m.Width)
+		size.Set(AttributeYDimension, TagInteger, m.Height)
+		c.Set(AttributeMediaSize, TagBeginCollection, size)
+	} else if m.SizeName != "" {
+		c.Set(AttributeMediaSizeName, TagKeyword, m.SizeName)
+	}
+
+	if m.Margins != nil {
+		c.Set(AttributeMediaTopMargin, TagInteger, m.Margins.Top)
+		c.Set(AttributeMediaBottomMargin, TagInteger, m.Margins.Bottom)
+		c.Set(AttributeMediaLeftMargin, TagInteger, m.Margins.Left)
+		c.Set(AttributeMediaRightMargin, TagInteger, m.Margins.Right)
+	}
+
+	if m.Source != "" {
+		c.Set(AttributeMediaSource, TagKeyword, m.Source)
+	}
+	if m.Type != "" {
+		c.Set(AttributeMediaType, TagKeyword, m.Type)
+	}
+	if m.Color != "" {
+		c.Set(AttributeMediaColor, TagKeyword, m.Color)
+	}
+
+	return c
+}
+
+// Unmarshal populates the media-col from its decoded collection. if only the media-size-name is given, the
+// dimensions are derived from the name
+func (m *MediaCol) Unmarshal(c Attributes) error {
+	u := attributeUnmarshaler{attributes: c}
+
+	*m = MediaCol{
+		SizeName: u.string(AttributeMediaSizeName),
+		Source:   u.string(AttributeMediaSource),
+		Type:     u.string(AttributeMediaType),
+		Color:    u.string(AttributeMediaColor),
+	}
+
+	if size, ok := u.collection(AttributeMediaSize); ok {
+		s := attributeUnmarshaler{attributes: size}
+		m.Width = s.int(AttributeXDimension)
+		m.Height = s.int(AttributeYDimension)
+		if s.err != nil && u.err == nil {
+			u.err = s.err
+		}
+	} else if m.SizeName != "" {
+		m.Width, m.Height, _ = ParseMediaSizeName(m.SizeName)
+	}
+
+	margins := []string{AttributeMediaTopMargin, AttributeMediaBottomMargin, AttributeMediaLeftMargin,
+		AttributeMediaRightMargin}
+	for _, name := range margins {
+		if len(u.values(name)) > 0 {
+			m.Margins = &MediaMargins{
+				Top:    u.int(AttributeMediaTopMargin),
+				Bottom: u.int(AttributeMediaBottomMargin),
+				Left:   u.int(AttributeMediaLeftMargin),
+				Right:  u.int(AttributeMediaRightMargin),
+			}
+			break
+		}
+	}
+
+	return u.err
+}
@@ -0,0 +1,89 @@
+package ipp
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMediaSizeName(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		ok     bool
+	}{
+		{name: "iso_a4_210x297mm", width: 21000, height: 29700, ok: true},
+		{name: "na_letter_8.5x11in", width: 21590, height: 27940, ok: true},
+		{name: "custom_index-card_4x6in", width: 10160, height: 15240, ok: true},
+		{name: "oe_photo-l_3.5x5in", width: 8890, height: 12700, ok: true},
+		{name: "letter"},
+		{name: "iso_a4_210mm"},
+		{name: "iso_a4_0x297mm"},
+	}
+
+	for _, test := range tests {
+		width, height, ok := ParseMediaSizeName(test.name)
+		assert.Equal(t, test.ok, ok, test.name)
+		assert.Equal(t, test.width, width, test.name)
+		assert.Equal(t, test.height, height, test.name)
+	}
+}
+
+func TestMediaCol(t *testing.T) {
+	_, err := NewMediaCol("tabloid-extra")
+	assert.True(t, errors.Is(err, MediaSizeNameError))
+
+	media, err := NewMediaCol("A4")
+	assert.Nil(t, err)
+	assert.Equal(t, MediaCol{SizeName: "iso_a4_210x297mm", Width: 21000, Height: 29700}, media)
+
+	media, err = NewMediaCol("na_letter_8.5x11in")
+	assert.Nil(t, err)
+	media = media.WithSource("manual").WithType("photographic-glossy").Borderless()
+
+	req := NewRequest(OperationPrintJob, 1)
+	req.JobAttributes[AttributeMediaCol] = media.Collection()
+	data, err := req.Encode()
+	assert.Nil(t, err)
+
+	decoded, err := NewRequestDecoder(bytes.NewReader(data)).Decode(nil)
+	assert.Nil(t, err)
+
+	collection, ok := decoded.JobAttributes[AttributeMediaCol].(Attributes)
+	assert.True(t, ok)
+	assert.NotContains(t, collection, AttributeMediaSizeName)
+
+	var parsed MediaCol
+	assert.Nil(t, parsed.Unmarshal(collection))
+	assert.Equal(t, 21590, parsed.Width)
+	assert.Equal(t, 27940, parsed.Height)
+	assert.Equal(t, "manual", parsed.Source)
+	assert.Equal(t, "photographic-glossy", parsed.Type)
+	assert.Equal(t, &MediaMargins{}, parsed.Margins)
+
+	// the dimensions are derived from the media-size-name if the media-size is missing
+	collection = make(Attributes)
+	collection.Set(AttributeMediaSizeName, TagKeyword, "iso_a5_148x210mm")
+	assert.Nil(t, parsed.Unmarshal(collection))
+	assert.Equal(t, 14800, parsed.Width)
+	assert.Nil(t, parsed.Margins)
+}
+
+func TestPrinterDescription_MediaCol(t *testing.T) {
+	a4, _ := NewMediaCol("iso_a4_210x297mm")
+	letter, _ := NewMediaCol("na_letter_8.5x11in")
+
+	attributes := make(Attributes)
+	attributes.Set(AttributeMediaColDefault, TagBeginCollection, a4.WithSource("tray-1").Collection())
+	attributes.Set(AttributeMediaColReady, TagBeginCollection, a4.WithSource("tray-1").Collection(),
+		letter.WithSource("tray-2").Collection())
+
+	var d PrinterDescription
+	assert.Nil(t, d.Unmarshal(attributes))
+	assert.Equal(t, 21000, d.MediaColDefault.Width)
+	assert.Len(t, d.MediaColReady, 2)
+	assert.Equal(t, "tray-2", d.MediaColReady[1].Source)
+}
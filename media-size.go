@@ -0,0 +1,323 @@
+package ipp
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MediaSize is an entry of the pwg 5101.1 media size registry. the dimensions are in hundredths of millimeters of
+// the portrait orientation
+type MediaSize struct {
+	// Name is the pwg self describing media size name, e.g. iso_a4_210x297mm
+	Name string
+	// PPD is the name of the size in ppd files, empty if there is no common ppd name
+	PPD    string
+	Width  int
+	Height int
+}
+
+// mediaSizeTolerance is the tolerance used to match dimensions to the registry, like cups
+const mediaSizeTolerance = 176
+
+// the media sizes of the pwg 5101.1 registry, the dimensions are derived from the names
+var mediaSizeNames = [][2]string{
+	{"na_index-3x5_3x5in", "3x5"},
+	{"na_personal_3.625x6.5in", "EnvPersonal"},
+	{"na_monarch_3.875x7.5in", "EnvMonarch"},
+	{"na_number-9_3.875x8.875in", "Env9"},
+	{"na_index-4x6_4x6in", "4x6"},
+	{"na_number-10_4.125x9.5in", "Env10"},
+	{"na_a2_4.375x5.75in", "EnvA2"},
+	{"na_number-11_4.5x10.375in", "Env11"},
+	{"na_number-12_4.75x11in", "Env12"},
+	{"na_5x7_5x7in", "5x7"},
+	{"na_index-5x8_5x8in", "5x8"},
+	{"na_number-14_5x11.5in", "Env14"},
+	{"na_invoice_5.5x8.5in", "Statement"},
+	{"na_index-4x6-ext_6x8in", "6x8"},
+	{"na_6x9_6x9in", "6x9"},
+	{"na_c5_6.5x9.5in", "6.5x9.5"},
+	{"na_7x9_7x9in", "7x9"},
+	{"na_executive_7.25x10.5in", "Executive"},
+	{"na_govt-letter_8x10in", "8x10"},
+	{"na_govt-legal_8x13in", "8x13"},
+	{"na_quarto_8.5x10.83in", "Quarto"},
+	{"na_letter_8.5x11in", "Letter"},
+	{"na_fanfold-eur_8.5x12in", "FanFoldGerman"},
+	{"na_letter-plus_8.5x12.69in", "LetterPlus"},
+	{"na_foolscap_8.5x13in", "FanFoldGermanLegal"},
+	{"na_oficio_8.5x13.4in", "Oficio"},
+	{"na_legal_8.5x14in", "Legal"},
+	{"na_super-a_8.94x14in", "SuperA"},
+	{"na_9x11_9x11in", "9x11"},
+	{"na_arch-a_9x12in", "ARCHA"},
+	{"na_letter-extra_9.5x12in", "LetterExtra"},
+	{"na_legal-extra_9.5x15in", "LegalExtra"},
+	{"na_10x11_10x11in", "10x11"},
+	{"na_10x13_10x13in", "10x13"},
+	{"na_10x14_10x14in", "10x14"},
+	{"na_10x15_10x15in", "10x15"},
+	{"na_11x12_11x12in", "11x12"},
+	{"na_edp_11x14in", "11x14"},
+	{"na_fanfold-us_11x14.875in", "FanFoldUS"},
+	{"na_11x15_11x15in", "11x15"},
+	{"na_ledger_11x17in", "Tabloid"},
+	{"na_eur-edp_12x14in", ""},
+	{"na_arch-b_12x18in", "ARCHB"},
+	{"na_12x19_12x19in", "12x19"},
+	{"na_b-plus_12x19.17in", "SuperB"},
+	{"na_super-b_13x19in", ""},
+	{"na_c_17x22in", "AnsiC"},
+	{"na_arch-c_18x24in", "ARCHC"},
+	{"na_d_22x34in", "AnsiD"},
+	{"na_arch-d_24x36in", "ARCHD"},
+	{"asme_f_28x40in", ""},
+	{"na_wide-format_30x42in", ""},
+	{"na_e_34x44in", "AnsiE"},
+	{"na_arch-e_36x48in", "ARCHE"},
+	{"na_f_44x68in", ""},
+	{"roc_16k_7.75x10.75in", "roc16k"},
+	{"roc_8k_10.75x15.5in", "roc8k"},
+	{"oe_photo-l_3.5x5in", "3.5x5"},
+	{"oe_square-photo_4x4in", "4x4"},
+	{"oe_square-photo_5x5in", "5x5"},
+	{"iso_a10_26x37mm", "A10"},
+	{"iso_a9_37x52mm", "A9"},
+	{"iso_a8_52x74mm", "A8"},
+	{"iso_a7_74x105mm", "A7"},
+	{"iso_a6_105x148mm", "A6"},
+	{"iso_a5_148x210mm", "A5"},
+	{"iso_a5-extra_174x235mm", "A5Extra"},
+	{"iso_a4_210x297mm", "A4"},
+	{"iso_a4-tab_225x297mm", "A4Tab"},
+	{"iso_a4-extra_235.5x322.3mm", "A4Extra"},
+	{"iso_a3_297x420mm", "A3"},
+	{"iso_a4x3_297x630mm", "A4x3"},
+	{"iso_a4x4_297x841mm", "A4x4"},
+	{"iso_a4x5_297x1051mm", "A4x5"},
+	{"iso_a4x6_297x1261mm", "A4x6"},
+	{"iso_a4x7_297x1471mm", "A4x7"},
+	{"iso_a4x8_297x1682mm", "A4x8"},
+	{"iso_a4x9_297x1892mm", "A4x9"},
+	{"iso_a3-extra_322x445mm", "A3Extra"},
+	{"iso_a2_420x594mm", "A2"},
+	{"iso_a3x3_420x891mm", "A3x3"},
+	{"iso_a3x4_420x1189mm", "A3x4"},
+	{"iso_a3x5_420x1486mm", "A3x5"},
+	{"iso_a3x6_420x1783mm", "A3x6"},
+	{"iso_a3x7_420x2080mm", "A3x7"},
+	{"iso_a1_594x841mm", "A1"},
+	{"iso_a2x3_594x1261mm", "A2x3"},
+	{"iso_a2x4_594x1682mm", "A2x4"},
+	{"iso_a2x5_594x2102mm", "A2x5"},
+	{"iso_a0_841x1189mm", "A0"},
+	{"iso_a1x3_841x1783mm", "A1x3"},
+	{"iso_a1x4_841x2378mm", "A1x4"},
+	{"iso_2a0_1189x1682mm", "2A0"},
+	{"iso_a0x3_1189x2523mm", "A0x3"},
+	{"iso_b10_31x44mm", "ISOB10"},
+	{"iso_b9_44x62mm", "ISOB9"},
+	{"iso_b8_62x88mm", "ISOB8"},
+	{"iso_b7_88x125mm", "ISOB7"},
+	{"iso_b6_125x176mm", "ISOB6"},
+	{"iso_b6c4_125x324mm", "EnvISOB6C4"},
+	{"iso_b5_176x250mm", "ISOB5"},
+	{"iso_b5-extra_201x276mm", "ISOB5Extra"},
+	{"iso_b4_250x353mm", "ISOB4"},
+	{"iso_b3_353x500mm", "ISOB3"},
+	{"iso_b2_500x707mm", "ISOB2"},
+	{"iso_b1_707x1000mm", "ISOB1"},
+	{"iso_b0_1000x1414mm", "ISOB0"},
+	{"iso_c10_28x40mm", "EnvC10"},
+	{"iso_c9_40x57mm", "EnvC9"},
+	{"iso_c8_57x81mm", "EnvC8"},
+	{"iso_c7_81x114mm", "EnvC7"},
+	{"iso_c7c6_81x162mm", "EnvC76"},
+	{"iso_c6_114x162mm", "EnvC6"},
+	{"iso_c6c5_114x229mm", "EnvC65"},
+	{"iso_c5_162x229mm", "EnvC5"},
+	{"iso_c4_229x324mm", "EnvC4"},
+	{"iso_c3_324x458mm", "EnvC3"},
+	{"iso_c2_458x648mm", "EnvC2"},
+	{"iso_c1_648x917mm", "EnvC1"},
+	{"iso_c0_917x1297mm", "EnvC0"},
+	{"iso_dl_110x220mm", "EnvDL"},
+	{"iso_ra4_215x305mm", "RA4"},
+	{"iso_sra4_225x320mm", "SRA4"},
+	{"iso_ra3_305x430mm", "RA3"},
+	{"iso_sra3_320x450mm", "SRA3"},
+	{"iso_ra2_430x610mm", "RA2"},
+	{"iso_sra2_450x640mm", "SRA2"},
+	{"iso_ra1_610x860mm", "RA1"},
+	{"iso_sra1_640x900mm", "SRA1"},
+	{"iso_ra0_860x1220mm", "RA0"},
+	{"iso_sra0_900x1280mm", "SRA0"},
+	{"jis_b10_32x45mm", "B10"},
+	{"jis_b9_45x64mm", "B9"},
+	{"jis_b8_64x91mm", "B8"},
+	{"jis_b7_91x128mm", "B7"},
+	{"jis_b6_128x182mm", "B6"},
+	{"jis_b5_182x257mm", "B5"},
+	{"jis_b4_257x364mm", "B4"},
+	{"jis_b3_364x515mm", "B3"},
+	{"jis_b2_515x728mm", "B2"},
+	{"jis_b1_728x1030mm", "B1"},
+	{"jis_b0_1030x1456mm", "B0"},
+	{"jis_exec_216x330mm", "jis-exec"},
+	{"jpn_chou4_90x205mm", "EnvChou4"},
+	{"jpn_hagaki_100x148mm", "Postcard"},
+	{"jpn_you4_105x235mm", "EnvYou4"},
+	{"jpn_chou2_111.1x146mm", ""},
+	{"jpn_chou3_120x235mm", "EnvChou3"},
+	{"jpn_oufuku_148x200mm", "DoublePostcardRotated"},
+	{"jpn_kahu_240x322.1mm", ""},
+	{"jpn_kaku2_240x332mm", "EnvKaku2"},
+	{"jpn_kaku3_216x277mm", "EnvKaku3"},
+	{"prc_32k_97x151mm", "PRC32K"},
+	{"prc_1_102x165mm", "EnvPRC1"},
+	{"prc_2_102x176mm", "EnvPRC2"},
+	{"prc_4_110x208mm", "EnvPRC4"},
+	{"prc_5_110x220mm", "EnvPRC5"},
+	{"prc_8_120x309mm", "EnvPRC8"},
+	{"prc_6_120x320mm", "EnvPRC6"},
+	{"prc_3_125x176mm", "EnvPRC3"},
+	{"prc_16k_146x215mm", "PRC16K"},
+	{"prc_7_160x230mm", "EnvPRC7"},
+	{"prc_10_324x458mm", "EnvPRC10"},
+	{"om_small-photo_100x150mm", "om_small-photo"},
+	{"om_italian_110x230mm", "EnvItalian"},
+	{"om_postfix_114x229mm", ""},
+	{"om_large-photo_200x300mm", "om_large-photo"},
+	{"om_folio_210x330mm", "Folio"},
+	{"om_folio-sp_215x315mm", "FolioSP"},
+	{"om_invite_220x220mm", "EnvInvite"},
+	{"om_juuro-ku-kai_198x275mm", ""},
+	{"om_pa-kai_267x389mm", ""},
+	{"om_dai-pa-kai_275x395mm", ""},
+}
+
+// MediaSizes is the pwg 5101.1 media size registry
+var MediaSizes = func() []MediaSize {
+	sizes := make([]MediaSize, len(mediaSizeNames))
+	for i, names := range mediaSizeNames {
+		width, height, _ := ParseMediaSizeName(names[0])
+		sizes[i] = MediaSize{Name: names[0], PPD: names[1], Width: width, Height: height}
+	}
+
+	return sizes
+}()
+
+// LookupMediaSize returns the media size with the pwg name, the legacy short name like a4 or letter, or the ppd name
+// like Letter or EnvDL. ppd names are case sensitive, other names are not. self describing names which are not in the registry, e.g. custom sizes, are accepted as well
+func LookupMediaSize(name string) (MediaSize, bool) {
+	if name == "" {
+		return MediaSize{}, false
+	}
+
+	for _, size := range MediaSizes {
+		if size.Name == name || size.PPD == name {
+			return size, true
+		}
+	}
+
+	// the short names are ambiguous, e.g. b5 is iso and B5 jis, the first match wins
+	lower := strings.ToLower(name)
+	for _, size := range MediaSizes {
+		if shortMediaName(size.Name) == lower || (size.PPD != "" && strings.ToLower(size.PPD) == lower) {
+			return size, true
+		}
+	}
+
+	if width, height, ok := ParseMediaSizeName(name); ok && strings.Count(name, "_") >= 2 {
+		return MediaSize{Name: name, Width: width, Height: height}, true
+	}
+
+	return MediaSize{}, false
+}
+
+// MediaSizeForDimensions returns the registry media size matching the dimensions in hundredths of millimeters. the
+// dimensions may be given in landscape orientation
+func MediaSizeForDimensions(width, height int) (MediaSize, bool) {
+	size := ClosestMediaSize(width, height)
+	width, height = portrait(width, height)
+
+	if abs(size.Width-width) < mediaSizeTolerance && abs(size.Height-height) < mediaSizeTolerance {
+		return size, true
+	}
+
+	return MediaSize{}, false
+}
+
+// ClosestMediaSize returns the registry media size which is closest to the dimensions in hundredths of millimeters
+func ClosestMediaSize(width, height int) MediaSize {
+	width, height = portrait(width, height)
+
+	var closest MediaSize
+	distance := math.MaxInt32
+	for _, size := range MediaSizes {
+		if d := abs(size.Width-width) + abs(size.Height-height); d < distance {
+			closest = size
+			distance = d
+		}
+	}
+
+	return closest
+}
+
+// CustomMediaSizeName formats the self describing name of a custom size in hundredths of millimeters like cups,
+// e.g. custom_100x150mm_100x150mm. sizes in multiples of a quarter inch are formatted in inches
+func CustomMediaSizeName(width, height int) string {
+	return FormatMediaSizeName("custom", "", width, height)
+}
+
+// FormatMediaSizeName formats a pwg self describing media size name from the class, e.g. iso or custom, the size
+// name and the dimensions in hundredths of millimeters. if name is empty, the dimensions are used as name
+func FormatMediaSizeName(class, name string, width, height int) string {
+	var dimensions string
+	if width%635 == 0 && height%635 == 0 {
+		dimensions = formatDimension(width, 2540, 3) + "x" + formatDimension(height, 2540, 3) + "in"
+	} else {
+		dimensions = formatDimension(width, 100, 2) + "x" + formatDimension(height, 100, 2) + "mm"
+	}
+
+	if name == "" {
+		name = dimensions
+	}
+
+	return class + "_" + name + "_" + dimensions
+}
+
+// formatDimension formats a dimension in hundredths of millimeters in the unit without trailing zeros
+func formatDimension(value, unit, precision int) string {
+	v := float64(value) / float64(unit)
+	scale := math.Pow10(precision)
+
+	return strconv.FormatFloat(math.Round(v*scale)/scale, 'f', -1, 64)
+}
+
+// shortMediaName returns the size name part of a pwg self describing name, e.g. a4 for iso_a4_210x297mm
+func shortMediaName(name string) string {
+	parts := strings.Split(name, "_")
+	if len(parts) != 3 {
+		return ""
+	}
+
+	return parts[1]
+}
+
+func portrait(width, height int) (int, int) {
+	if width > height {
+		return height, width
+	}
+
+	return width, height
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+
+	return i
+}
@@ -0,0 +1,89 @@
+package ipp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediaSizes(t *testing.T) {
+	names := make(map[string]bool)
+	for _, size := range MediaSizes {
+		assert.False(t, names[size.Name], size.Name)
+		names[size.Name] = true
+
+		assert.True(t, size.Width > 0 && size.Height > 0, size.Name)
+		assert.True(t, size.Width <= size.Height, size.Name)
+	}
+}
+
+func TestLookupMediaSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   string
+		width  int
+		height int
+		ok     bool
+	}{
+		{name: "iso_a4_210x297mm", size: "iso_a4_210x297mm", width: 21000, height: 29700, ok: true},
+		{name: "a4", size: "iso_a4_210x297mm", width: 21000, height: 29700, ok: true},
+		{name: "Letter", size: "na_letter_8.5x11in", width: 21590, height: 27940, ok: true},
+		{name: "EnvDL", size: "iso_dl_110x220mm", width: 11000, height: 22000, ok: true},
+		{name: "b5", size: "iso_b5_176x250mm", width: 17600, height: 25000, ok: true},
+		{name: "B5", size: "jis_b5_182x257mm", width: 18200, height: 25700, ok: true},
+		{name: "custom_label_62x100mm", size: "custom_label_62x100mm", width: 6200, height: 10000, ok: true},
+		{name: "62x100mm"},
+		{name: "tabloid-extra"},
+		{name: ""},
+	}
+
+	for _, test := range tests {
+		size, ok := LookupMediaSize(test.name)
+		assert.Equal(t, test.ok, ok, test.name)
+		assert.Equal(t, test.size, size.Name, test.name)
+		assert.Equal(t, test.width, size.Width, test.name)
+		assert.Equal(t, test.height, size.Height, test.name)
+	}
+}
+
+func TestMediaSizeForDimensions(t *testing.T) {
+	size, ok := MediaSizeForDimensions(21000, 29700)
+	assert.True(t, ok)
+	assert.Equal(t, "iso_a4_210x297mm", size.Name)
+
+	// landscape and within the tolerance
+	size, ok = MediaSizeForDimensions(27900, 21600)
+	assert.True(t, ok)
+	assert.Equal(t, "na_letter_8.5x11in", size.Name)
+
+	_, ok = MediaSizeForDimensions(20000, 29700)
+	assert.False(t, ok)
+
+	assert.Equal(t, "iso_a4_210x297mm", ClosestMediaSize(21100, 29400).Name)
+	assert.Equal(t, "na_index-4x6_4x6in", ClosestMediaSize(10100, 15200).Name)
+}
+
+func TestCustomMediaSizeName(t *testing.T) {
+	tests := []struct {
+		width  int
+		height int
+		name   string
+	}{
+		{width: 10000, height: 15000, name: "custom_100x150mm_100x150mm"},
+		{width: 6200, height: 2950, name: "custom_62x29.5mm_62x29.5mm"},
+		{width: 10160, height: 15240, name: "custom_4x6in_4x6in"},
+		{width: 22225, height: 27940, name: "custom_8.75x11in_8.75x11in"},
+	}
+
+	for _, test := range tests {
+		name := CustomMediaSizeName(test.width, test.height)
+		assert.Equal(t, test.name, name)
+
+		width, height, ok := ParseMediaSizeName(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, test.width, width, name)
+		assert.Equal(t, test.height, height, name)
+	}
+
+	assert.Equal(t, "oe_photo-l_3.5x5in", FormatMediaSizeName("oe", "photo-l", 8890, 12700))
+}
@@ -0,0 +1,596 @@
+package ipp
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// tagNames are the names of the value tags as used by ipptool
+var tagNames = map[int8]string{
+	TagUnsupportedValue: "unsupported",
+	TagDefault:          "default",
+	TagUnknown:          "unknown",
+	TagNoValue:          "no-value",
+	TagNotSettable:      "not-settable",
+	TagDeleteAttr:       "delete-attribute",
+	TagAdminDefine:      "admin-define",
+	TagInteger:          "integer",
+	TagBoolean:          "boolean",
+	TagEnum:             "enum",
+	TagString:           "octetString",
+	TagDate:             "dateTime",
+	TagResolution:       "resolution",
+	TagRange:            "rangeOfInteger",
+	TagBeginCollection:  "collection",
+	TagTextLang:         "textWithLanguage",
+	TagNameLang:         "nameWithLanguage",
+	TagText:             "textWithoutLanguage",
+	TagName:             "nameWithoutLanguage",
+	TagKeyword:          "keyword",
+	TagUri:              "uri",
+	TagUriScheme:        "uriScheme",
+	TagCharset:          "charset",
+	TagLanguage:         "naturalLanguage",
+	TagMimeType:         "mimeMediaType",
+	TagMemberName:       "memberAttrName",
+}
+
+// groupNames are the names of the delimiter tags of the attribute groups
+var groupNames = map[int8]string{
+	TagOperation:         "operation",
+	TagJob:               "job",
+	TagPrinter:           "printer",
+	TagUnsupportedGroup:  "unsupported",
+	TagSubscription:      "subscription",
+	TagEventNotification: "event-notification",
+	TagResource:          "resource",
+	TagDocument:          "document",
+	TagSystem:            "system",
+}
+
+// TagSyntax returns the ipptool syntax name of a value tag, e.g. keyword or rangeOfInteger. unknown tags are
+// formatted in hex
+func TagSyntax(tag int8) string {
+	if name, ok := tagNames[tag]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("0x%02x", uint8(tag))
+}
+
+// ParseTagSyntax returns the value tag of an ipptool syntax name, text and name are accepted for the tags without
+// language
+func ParseTagSyntax(name string) (int8, bool) {
+	switch name {
+	case "text":
+		return TagText, true
+	case "name":
+		return TagName, true
+	}
+
+	for tag, n := range tagNames {
+		if n == name {
+			return tag, true
+		}
+	}
+
+	var tag uint8
+	if _, err := fmt.Sscanf(name, "0x%02x", &tag); err == nil {
+		return int8(tag), true
+	}
+
+	return 0, false
+}
+
+func isOutOfBandTag(tag int8) bool {
+	return tag >= TagUnsupportedValue && tag <= TagAdminDefine
+}
+
+// jsonMessage is the json representation of requests and responses
+type jsonMessage struct {
+	Version     string      `json:"version"`
+	Operation   string      `json:"operation,omitempty"`
+	OperationID *int16      `json:"operation-id,omitempty"`
+	Status      string      `json:"status,omitempty"`
+	StatusCode  *int16      `json:"status-code,omitempty"`
+	RequestID   int32       `json:"request-id"`
+	Groups      []jsonGroup `json:"groups"`
+}
+
+type jsonGroup struct {
+	Tag        string          `json:"tag"`
+	Attributes []jsonAttribute `json:"attributes"`
+}
+
+type jsonAttribute struct {
+	Name   string            `json:"name"`
+	Tag    string            `json:"tag"`
+	Values []json.RawMessage `json:"values"`
+}
+
+type jsonRange struct {
+	Lower int32 `json:"lower"`
+	Upper int32 `json:"upper"`
+}
+
+type jsonResolution struct {
+	Width  int32 `json:"width"`
+	Height int32 `json:"height"`
+	Units  int8  `json:"units"`
+}
+
+// MarshalJSON encodes the request with its groups, the attributes with their names, value tags and values. the
+// document data is not part of the json
+func (r Request) MarshalJSON() ([]byte, error) {
+	operation := r.Operation
+	msg := jsonMessage{
+		Version:     fmt.Sprintf("%d.%d", r.ProtocolVersionMajor, r.ProtocolVersionMinor),
+		Operation:   Operation(r.Operation).String(),
+		OperationID: &operation,
+		RequestID:   r.RequestId,
+	}
+
+	groups := []struct {
+		tag        int8
+		attributes map[string]interface{}
+	}{
+		{TagOperation, r.OperationAttributes},
+		{TagJob, r.JobAttributes},
+		{TagPrinter, r.PrinterAttributes},
+		{TagSubscription, r.SubscriptionAttributes},
+	}
+
+	for _, group := range groups {
+		if len(group.attributes) == 0 && group.tag != TagOperation {
+			continue
+		}
+
+		attributes := make(Attributes, len(group.attributes))
+		for name, value := range group.attributes {
+			tag, _ := r.AttributeTag(name)
+			attributes[name] = requestAttributes(name, tag, value)
+		}
+
+		g, err := marshalJSONGroup(group.tag, attributes)
+		if err != nil {
+			return nil, err
+		}
+		msg.Groups = append(msg.Groups, g)
+	}
+
+	return json.Marshal(msg)
+}
+
+// UnmarshalJSON decodes a request encoded by MarshalJSON. values with the tag of the AttributeTagMapping are stored as
+// plain values, other values as []Attribute to keep their tag
+func (r *Request) UnmarshalJSON(data []byte) error {
+	var msg jsonMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	req := NewRequest(0, msg.RequestID)
+	if err := parseJSONVersion(msg.Version, &req.ProtocolVersionMajor, &req.ProtocolVersionMinor); err != nil {
+		return err
+	}
+
+	switch {
+	case msg.OperationID != nil:
+		req.Operation = *msg.OperationID
+	case msg.Operation != "":
+		operation, err := ParseOperation(msg.Operation)
+		if err != nil {
+			return err
+		}
+		req.Operation = int16(operation)
+	}
+
+	for _, g := range msg.Groups {
+		tag, attributes, err := unmarshalJSONGroup(g)
+		if err != nil {
+			return err
+		}
+
+		var group map[string]interface{}
+		switch tag {
+		case TagOperation:
+			group = req.OperationAttributes
+		case TagJob:
+			group = req.JobAttributes
+		case TagPrinter:
+			group = req.PrinterAttributes
+		case TagSubscription:
+			group = req.SubscriptionAttributes
+		default:
+			return fmt.Errorf("%w: %s in request", UnexpectedGroupError, g.Tag)
+		}
+
+		for name, values := range attributes {
+			group[name] = requestValue(name, values)
+		}
+	}
+
+	*r = *req
+	return nil
+}
+
+// MarshalJSON encodes the response with its groups, the attributes with their names, value tags and values
+func (r Response) MarshalJSON() ([]byte, error) {
+	status := r.StatusCode
+	msg := jsonMessage{
+		Version:    fmt.Sprintf("%d.%d", r.ProtocolVersionMajor, r.ProtocolVersionMinor),
+		Status:     Status(r.StatusCode).String(),
+		StatusCode: &status,
+		RequestID:  r.RequestId,
+	}
+
+	add := func(tag int8, attributes Attributes) error {
+		g, err := marshalJSONGroup(tag, attributes)
+		if err != nil {
+			return err
+		}
+		msg.Groups = append(msg.Groups, g)
+		return nil
+	}
+
+	if err := add(TagOperation, r.OperationAttributes); err != nil {
+		return nil, err
+	}
+	if len(r.UnsupportedAttributes) > 0 {
+		if err := add(TagUnsupportedGroup, r.UnsupportedAttributes); err != nil {
+			return nil, err
+		}
+	}
+
+	groups := []struct {
+		tag        int8
+		attributes []Attributes
+	}{
+		{TagPrinter, r.PrinterAttributes},
+		{TagJob, r.JobAttributes},
+		{TagSubscription, r.SubscriptionAttributes},
+		{TagEventNotification, r.EventNotificationAttributes},
+	}
+	for _, group := range groups {
+		for _, attributes := range group.attributes {
+			if err := add(group.tag, attributes); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return json.Marshal(msg)
+}
+
+// UnmarshalJSON decodes a response encoded by MarshalJSON
+func (r *Response) UnmarshalJSON(data []byte) error {
+	var msg jsonMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	resp := &Response{RequestId: msg.RequestID, OperationAttributes: make(Attributes)}
+	if err := parseJSONVersion(msg.Version, &resp.ProtocolVersionMajor, &resp.ProtocolVersionMinor); err != nil {
+		return err
+	}
+
+	switch {
+	case msg.StatusCode != nil:
+		resp.StatusCode = *msg.StatusCode
+	case msg.Status != "":
+		status, err := ParseStatus(msg.Status)
+		if err != nil {
+			return err
+		}
+		resp.StatusCode = int16(status)
+	}
+
+	for _, g := range msg.Groups {
+		tag, attributes, err := unmarshalJSONGroup(g)
+		if err != nil {
+			return err
+		}
+
+		switch tag {
+		case TagOperation:
+			resp.OperationAttributes = attributes
+		case TagUnsupportedGroup:
+			resp.UnsupportedAttributes = attributes
+		case TagPrinter:
+			resp.PrinterAttributes = append(resp.PrinterAttributes, attributes)
+		case TagJob:
+			resp.JobAttributes = append(resp.JobAttributes, attributes)
+		case TagSubscription:
+			resp.SubscriptionAttributes = append(resp.SubscriptionAttributes, attributes)
+		case TagEventNotification:
+			resp.EventNotificationAttributes = append(resp.EventNotificationAttributes, attributes)
+		default:
+			return fmt.Errorf("%w: %s in response", UnexpectedGroupError, g.Tag)
+		}
+	}
+
+	*r = *resp
+	return nil
+}
+
+// jsonNotification is the json representation of event notifications
+type jsonNotification struct {
+	SubscriptionID int             `json:"subscription-id"`
+	SequenceNumber int             `json:"sequence-number"`
+	Event          string          `json:"event"`
+	Text           string          `json:"text,omitempty"`
+	PrinterURI     string          `json:"printer-uri,omitempty"`
+	JobID          int             `json:"job-id,omitempty"`
+	UserData       string          `json:"user-data,omitempty"`
+	Attributes     []jsonAttribute `json:"attributes"`
+}
+
+// MarshalJSON encodes the notification with its fields and all attributes of the event, e.g. to forward events to web
+// clients. the attributes are encoded like the attributes of a request
+func (n Notification) MarshalJSON() ([]byte, error) {
+	attributes, err := marshalJSONAttributes(n.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(jsonNotification{
+		SubscriptionID: n.SubscriptionID,
+		SequenceNumber: n.SequenceNumber,
+		Event:          n.Event,
+		Text:           n.Text,
+		PrinterURI:     n.PrinterURI,
+		JobID:          n.JobID,
+		UserData:       n.UserData,
+		Attributes:     attributes,
+	})
+}
+
+// UnmarshalJSON decodes a notification encoded by MarshalJSON
+func (n *Notification) UnmarshalJSON(data []byte) error {
+	var msg jsonNotification
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	attributes, err := unmarshalJSONAttributes(msg.Attributes)
+	if err != nil {
+		return err
+	}
+
+	*n = Notification{
+		SubscriptionID: msg.SubscriptionID,
+		SequenceNumber: msg.SequenceNumber,
+		Event:          msg.Event,
+		Text:           msg.Text,
+		PrinterURI:     msg.PrinterURI,
+		JobID:          msg.JobID,
+		UserData:       msg.UserData,
+		Attributes:     attributes,
+	}
+
+	return nil
+}
+
+func parseJSONVersion(version string, major, minor *int8) error {
+	if _, err := fmt.Sscanf(version, "%d.%d", major, minor); err != nil {
+		return fmt.Errorf("invalid version %q: %w", version, err)
+	}
+
+	return nil
+}
+
+// requestAttributes converts a value of a request group into attributes with the tag of the attribute, see
+// Request.AttributeTag
+func requestAttributes(name string, tag int8, value interface{}) []Attribute {
+	switch v := value.(type) {
+	case Attribute:
+		return []Attribute{v}
+	case []Attribute:
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+
+	// ranges and dates are slices themselves
+	_, isRange := value.([]int32)
+	_, isDate := value.([]int)
+	if rv.Kind() != reflect.Slice || (tag == TagRange && isRange) || (tag == TagDate && isDate) {
+		return []Attribute{{Tag: tag, Name: name, Value: value}}
+	}
+
+	values := make([]Attribute, rv.Len())
+	for i := range values {
+		values[i] = Attribute{Tag: tag, Name: name, Value: rv.Index(i).Interface()}
+	}
+
+	return values
+}
+
+// requestValue converts attributes into a value of a request group. values with the tag of the AttributeTagMapping
+// are stored as plain value or []interface{}, others as []Attribute to keep the tag
+func requestValue(name string, values []Attribute) interface{} {
+	if tag, ok := AttributeTagMapping[name]; !ok || tag != values[0].Tag {
+		return values
+	}
+
+	if len(values) == 1 {
+		return values[0].Value
+	}
+
+	set := make([]interface{}, len(values))
+	for i, value := range values {
+		set[i] = value.Value
+	}
+
+	return set
+}
+
+// sortedAttributeNames returns the names of the attributes, charset and natural language first like the encoder
+func sortedAttributeNames(attributes Attributes) []string {
+	first := map[string]int{AttributeCharset: 1, AttributeNaturalLanguage: 2, AttributePrinterURI: 3, AttributeJobID: 4}
+
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		pi, pj := first[names[i]], first[names[j]]
+		switch {
+		case pi > 0 && pj > 0:
+			return pi < pj
+		case pi > 0 || pj > 0:
+			return pi > 0
+		}
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+func marshalJSONGroup(tag int8, attributes Attributes) (jsonGroup, error) {
+	members, err := marshalJSONAttributes(attributes)
+	return jsonGroup{Tag: groupNames[tag], Attributes: members}, err
+}
+
+func marshalJSONAttributes(attributes Attributes) ([]jsonAttribute, error) {
+	result := make([]jsonAttribute, 0, len(attributes))
+	for _, name := range sortedAttributeNames(attributes) {
+		values := attributes[name]
+		if len(values) == 0 {
+			continue
+		}
+
+		tag := values[0].Tag
+		if tag == TagZero {
+			tag = AttributeTagMapping[name]
+		}
+
+		attr := jsonAttribute{Name: name, Tag: TagSyntax(tag), Values: make([]json.RawMessage, 0, len(values))}
+		for _, value := range values {
+			if isOutOfBandTag(tag) {
+				continue
+			}
+
+			raw, err := marshalJSONValue(tag, value.Value)
+			if err != nil {
+				return nil, fmt.Errorf("attribute %s: %w", name, err)
+			}
+			attr.Values = append(attr.Values, raw)
+		}
+
+		result = append(result, attr)
+	}
+
+	return result, nil
+}
+
+func marshalJSONValue(tag int8, value interface{}) (json.RawMessage, error) {
+	switch v := value.(type) {
+	case Resolution:
+		return json.Marshal(jsonResolution{Width: v.Width, Height: v.Height, Units: v.Depth})
+	case Attributes:
+		members, err := marshalJSONAttributes(v)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(members)
+	case []int32:
+		if tag == TagRange && len(v) == 2 {
+			return json.Marshal(jsonRange{Lower: v[0], Upper: v[1]})
+		}
+	case []int:
+		if t, ok := DecodeDateTime(v); ok && tag == TagDate {
+			return json.Marshal(t.Format(time.RFC3339Nano))
+		}
+	}
+
+	return json.Marshal(value)
+}
+
+func unmarshalJSONGroup(g jsonGroup) (int8, Attributes, error) {
+	var tag int8
+	for t, name := range groupNames {
+		if name == g.Tag {
+			tag = t
+		}
+	}
+	if tag == 0 {
+		return 0, nil, fmt.Errorf("%w: unknown group %q", UnexpectedGroupError, g.Tag)
+	}
+
+	attributes, err := unmarshalJSONAttributes(g.Attributes)
+	return tag, attributes, err
+}
+
+func unmarshalJSONAttributes(members []jsonAttribute) (Attributes, error) {
+	attributes := make(Attributes, len(members))
+	for _, member := range members {
+		tag, ok := ParseTagSyntax(member.Tag)
+		if !ok {
+			return nil, fmt.Errorf("%w: attribute %s has unknown tag %q", InvalidTagError, member.Name, member.Tag)
+		}
+
+		if isOutOfBandTag(tag) {
+			attributes[member.Name] = []Attribute{{Tag: tag, Name: member.Name, Value: ""}}
+			continue
+		}
+
+		values := make([]Attribute, len(member.Values))
+		for i, raw := range member.Values {
+			value, err := unmarshalJSONValue(tag, raw)
+			if err != nil {
+				return nil, fmt.Errorf("attribute %s: %w", member.Name, err)
+			}
+			values[i] = Attribute{Tag: tag, Name: member.Name, Value: value}
+		}
+		attributes[member.Name] = values
+	}
+
+	return attributes, nil
+}
+
+// unmarshalJSONValue decodes a value into the type the AttributeDecoder returns for the tag
+func unmarshalJSONValue(tag int8, raw json.RawMessage) (interface{}, error) {
+	switch tag {
+	case TagInteger, TagEnum:
+		var v int
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case TagBoolean:
+		var v bool
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case TagRange:
+		var v jsonRange
+		err := json.Unmarshal(raw, &v)
+		return []int32{v.Lower, v.Upper}, err
+	case TagResolution:
+		var v jsonResolution
+		err := json.Unmarshal(raw, &v)
+		return Resolution{Width: v.Width, Height: v.Height, Depth: v.Units}, err
+	case TagDate:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			// dates which are no valid DateAndTime values are kept as octets
+			var v []int
+			err := json.Unmarshal(raw, &v)
+			return v, err
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		return EncodeDateTime(t), err
+	case TagBeginCollection:
+		var members []jsonAttribute
+		if err := json.Unmarshal(raw, &members); err != nil {
+			return nil, err
+		}
+		return unmarshalJSONAttributes(members)
+	}
+
+	var v string
+	err := json.Unmarshal(raw, &v)
+	return v, err
+}
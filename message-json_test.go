@@ -0,0 +1,88 @@
+package ipp
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequest_JSON(t *testing.T) {
+	media, _ := NewMediaCol("iso_a4_210x297mm")
+
+	req := NewRequest(OperationPrintJob, 3)
+	req.OperationAttributes[AttributePrinterURI] = "ipp://localhost/printers/office"
+	req.OperationAttributes[AttributeRequestingUserName] = "alice"
+	req.JobAttributes[AttributeCopies] = 2
+	req.JobAttributes[AttributeFinishings] = []int{4, 20}
+	req.JobAttributes[AttributeMediaCol] = media.Collection()
+	req.JobAttributes["x-custom-option"] = []Attribute{{Tag: TagText, Value: "value"}}
+
+	data, err := json.Marshal(req)
+	assert.Nil(t, err)
+
+	var msg map[string]interface{}
+	assert.Nil(t, json.Unmarshal(data, &msg))
+	assert.Equal(t, "Print-Job", msg["operation"])
+	assert.Equal(t, "2.0", msg["version"])
+	groups := msg["groups"].([]interface{})
+	assert.Len(t, groups, 2)
+	first := groups[0].(map[string]interface{})["attributes"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, AttributePrinterURI, first["name"])
+	assert.Equal(t, "uri", first["tag"])
+
+	var decoded Request
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, OperationPrintJob, decoded.Operation)
+	assert.Equal(t, int32(3), decoded.RequestId)
+	assert.Equal(t, 2, decoded.JobAttributes[AttributeCopies])
+	assert.Equal(t, []interface{}{4, 20}, decoded.JobAttributes[AttributeFinishings])
+	assert.Equal(t, []Attribute{{Tag: TagText, Name: "x-custom-option", Value: "value"}}, decoded.JobAttributes["x-custom-option"])
+
+	var col MediaCol
+	assert.Nil(t, col.Unmarshal(decoded.JobAttributes[AttributeMediaCol].(Attributes)))
+	assert.Equal(t, media.Width, col.Width)
+	assert.Equal(t, media.Height, col.Height)
+
+	again, err := json.Marshal(&decoded)
+	assert.Nil(t, err)
+	assert.JSONEq(t, string(data), string(again))
+
+	_, err = decoded.Encode()
+	assert.Nil(t, err)
+}
+
+func TestResponse_JSON(t *testing.T) {
+	created := time.Date(2020, 5, 1, 12, 30, 0, 0, time.FixedZone("", -5*3600))
+
+	resp := NewResponse(StatusOkIgnoredOrSubstituted, 7)
+	resp.OperationAttributes[AttributeStatusMessage] = []Attribute{{Tag: TagText, Value: "ignored"}}
+	resp.UnsupportedAttributes = Attributes{AttributeSides: []Attribute{{Tag: TagKeyword, Value: "two-sided"}}}
+
+	printer := make(Attributes)
+	printer.Set(AttributePrinterName, TagName, "office")
+	printer.Set(AttributePrinterState, TagEnum, int(PrinterStateIdle))
+	printer.Set(AttributeColorSupported, TagBoolean, true)
+	printer.Set(AttributeCopiesSupported, TagRange, []int32{1, 99})
+	printer.Set(AttributePrinterResolutionDefault, TagResolution, Resolution{Width: 600, Height: 600, Depth: 3})
+	printer.Set(AttributeDateTimeAtCreation, TagDate, EncodeDateTime(created))
+	printer.Set(AttributePrinterLocation, TagNoValue, "")
+	resp.PrinterAttributes = append(resp.PrinterAttributes, printer)
+
+	data, err := resp.Encode()
+	assert.Nil(t, err)
+	wire, err := NewResponseDecoder(bytes.NewReader(data)).Decode(nil)
+	assert.Nil(t, err)
+
+	encoded, err := json.Marshal(wire)
+	assert.Nil(t, err)
+	assert.Contains(t, string(encoded), `"status":"successful-ok-ignored-or-substituted-attributes"`)
+	assert.Contains(t, string(encoded), `"values":["2020-05-01T12:30:00-05:00"]`)
+	assert.Contains(t, string(encoded), `{"lower":1,"upper":99}`)
+
+	var decoded Response
+	assert.Nil(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, wire, &decoded)
+}
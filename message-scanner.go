@@ -0,0 +1,220 @@
+package ipp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// MessageHeader is the fixed header of an ipp request or response
+type MessageHeader struct {
+	ProtocolVersionMajor int8
+	ProtocolVersionMinor int8
+	// Code is the operation id of a request or the status code of a response
+	Code      int16
+	RequestId int32
+}
+
+// MessageScanner reads the attributes of an ipp request or response one value at a time. unlike the request and
+// response decoders it does not build attribute maps, the iterators yield the attributes in wire order, so very
+// large responses like media-col-database dumps can be processed while they are read. collection values are
+// decoded as a whole.
+//
+// the iterators consume the underlying reader, a message can only be iterated once. breaking out of a loop stops
+// the scanner at the current position and a following loop continues from there. after the end of the attributes
+// the reader is positioned at the document data
+type MessageScanner struct {
+	reader  io.Reader
+	decoder *AttributeDecoder
+	header  MessageHeader
+	err     error
+
+	group    int8
+	groups   int
+	previous string
+	done     bool
+
+	// pending is a value read ahead by Attributes or Groups
+	pending *scannedValue
+}
+
+// scannedValue is a single attribute value and the group it belongs to
+type scannedValue struct {
+	group int8
+	// index counts the group delimiters, it differs for consecutive groups with the same tag
+	index int
+	attr  Attribute
+	// additional is set for the additional values of a 1setOf attribute, they have no name on the wire
+	additional bool
+}
+
+// NewMessageScanner reads the message header from r and returns a scanner for the attributes
+func NewMessageScanner(r io.Reader) (*MessageScanner, error) {
+	s := &MessageScanner{reader: r, decoder: NewAttributeDecoder(r), group: TagCupsInvalid}
+
+	if err := binary.Read(r, binary.BigEndian, &s.header); err != nil {
+		return nil, readError(err)
+	}
+
+	return s, nil
+}
+
+// Header returns the header of the message
+func (s *MessageScanner) Header() MessageHeader {
+	return s.header
+}
+
+// Err returns the first error which stopped the iteration, nil if the end of the attributes was reached
+func (s *MessageScanner) Err() error {
+	return s.err
+}
+
+// Data returns the reader positioned after the end of the attributes, it is only valid once the iteration is done
+func (s *MessageScanner) Data() io.Reader {
+	return s.reader
+}
+
+// next returns the next attribute value, false at the end of the attributes or on errors
+func (s *MessageScanner) next() (scannedValue, bool) {
+	if s.pending != nil {
+		v := *s.pending
+		s.pending = nil
+		return v, true
+	}
+
+	tag := make([]byte, 1)
+	for !s.done && s.err == nil {
+		if _, err := io.ReadFull(s.reader, tag); err != nil {
+			// a stream may end without the end tag
+			if err != io.EOF {
+				s.err = err
+			}
+			s.done = true
+			break
+		}
+
+		t := int8(tag[0])
+		if t == TagEnd {
+			s.done = true
+			break
+		}
+		if t < TagUnsupportedValue {
+			s.group = t
+			s.groups++
+			continue
+		}
+
+		if s.group == TagCupsInvalid {
+			s.err = fmt.Errorf("%w: attribute before the operation attributes", UnexpectedGroupError)
+			break
+		}
+
+		attr, err := s.decoder.decode(t)
+		if err != nil {
+			s.err = err
+			break
+		}
+
+		v := scannedValue{group: s.group, index: s.groups, attr: attr}
+		if attr.Name == "" {
+			v.attr.Name = s.previous
+			v.additional = true
+		}
+		s.previous = v.attr.Name
+
+		return v, true
+	}
+
+	return scannedValue{}, false
+}
+
+// Values returns an iterator over all attribute values and the tag of their group, e.g. TagPrinter. the additional
+// values of a 1setOf attribute are yielded with the name of the attribute
+func (s *MessageScanner) Values() iter.Seq2[int8, Attribute] {
+	return func(yield func(int8, Attribute) bool) {
+		for {
+			v, ok := s.next()
+			if !ok || !yield(v.group, v.attr) {
+				return
+			}
+		}
+	}
+}
+
+// Attributes returns an iterator over the attributes and the tag of their group, all values of an attribute are
+// yielded at once
+func (s *MessageScanner) Attributes() iter.Seq2[int8, []Attribute] {
+	return func(yield func(int8, []Attribute) bool) {
+		first, ok := s.next()
+		for ok {
+			group, values := first.group, []Attribute{first.attr}
+
+			for {
+				var v scannedValue
+				if v, ok = s.next(); !ok {
+					break
+				}
+				if !v.additional || v.index != first.index {
+					first = v
+					break
+				}
+				values = append(values, v.attr)
+			}
+
+			if !yield(group, values) {
+				if ok {
+					s.pending = &first
+				}
+				return
+			}
+		}
+	}
+}
+
+// Groups returns an iterator over the attribute groups, e.g. one printer group per printer of a CUPS-Get-Printers
+// response. the values of a group are yielded in wire order, empty groups are skipped
+func (s *MessageScanner) Groups() iter.Seq2[int8, []Attribute] {
+	return func(yield func(int8, []Attribute) bool) {
+		first, ok := s.next()
+		for ok {
+			group, values := first.group, []Attribute{first.attr}
+
+			for {
+				var v scannedValue
+				if v, ok = s.next(); !ok {
+					break
+				}
+				if v.index != first.index {
+					first = v
+					break
+				}
+				values = append(values, v.attr)
+			}
+
+			if !yield(group, values) {
+				if ok {
+					s.pending = &first
+				}
+				return
+			}
+		}
+	}
+}
+
+// AttributeGroups returns an iterator over the attribute groups like Groups, each group is yielded as attributes
+// like the groups of a decoded response
+func (s *MessageScanner) AttributeGroups() iter.Seq2[int8, Attributes] {
+	return func(yield func(int8, Attributes) bool) {
+		for group, values := range s.Groups() {
+			attributes := make(Attributes)
+			for _, v := range values {
+				attributes[v.Name] = append(attributes[v.Name], v)
+			}
+
+			if !yield(group, attributes) {
+				return
+			}
+		}
+	}
+}
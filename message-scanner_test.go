@@ -0,0 +1,128 @@
+package ipp
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func scannerTestResponse(t *testing.T) []byte {
+	resp := NewResponse(StatusOk, 7)
+	for _, name := range []string{"office", "lab"} {
+		printer := make(Attributes)
+		printer.Set(AttributePrinterName, TagName, name)
+		printer.Set(AttributeDocumentFormatSupported, TagMimeType, "application/pdf", "image/urf", "image/pwg-raster")
+		resp.PrinterAttributes = append(resp.PrinterAttributes, printer)
+	}
+
+	data, err := resp.Encode()
+	assert.Nil(t, err)
+
+	return append(data, []byte("document")...)
+}
+
+func TestMessageScanner_Values(t *testing.T) {
+	s, err := NewMessageScanner(bytes.NewReader(scannerTestResponse(t)))
+	assert.Nil(t, err)
+	assert.Equal(t, MessageHeader{ProtocolVersionMajor: ProtocolVersionMajor, ProtocolVersionMinor: ProtocolVersionMinor,
+		Code: StatusOk, RequestId: 7}, s.Header())
+
+	var formats []string
+	printerValues := 0
+	for group, attr := range s.Values() {
+		if group == TagPrinter {
+			printerValues++
+		}
+		if attr.Name == AttributeDocumentFormatSupported {
+			formats = append(formats, attr.Value.(string))
+		}
+	}
+	assert.Nil(t, s.Err())
+	assert.Equal(t, 8, printerValues)
+	assert.Equal(t, []string{"application/pdf", "image/urf", "image/pwg-raster",
+		"application/pdf", "image/urf", "image/pwg-raster"}, formats)
+
+	data, err := io.ReadAll(s.Data())
+	assert.Nil(t, err)
+	assert.Equal(t, "document", string(data))
+}
+
+func TestMessageScanner_Attributes(t *testing.T) {
+	s, err := NewMessageScanner(bytes.NewReader(scannerTestResponse(t)))
+	assert.Nil(t, err)
+
+	var names []string
+	for group, values := range s.Attributes() {
+		if group != TagPrinter {
+			continue
+		}
+		names = append(names, values[0].Name)
+		if values[0].Name == AttributeDocumentFormatSupported {
+			assert.Len(t, values, 3)
+		} else {
+			assert.Len(t, values, 1)
+		}
+	}
+	assert.Nil(t, s.Err())
+	assert.Len(t, names, 4)
+}
+
+func TestMessageScanner_Groups(t *testing.T) {
+	s, err := NewMessageScanner(bytes.NewReader(scannerTestResponse(t)))
+	assert.Nil(t, err)
+
+	var tags []int8
+	for group, values := range s.Groups() {
+		tags = append(tags, group)
+		if group == TagPrinter {
+			assert.Len(t, values, 4)
+		}
+		// stop after the first printer and continue with the next loop
+		if group == TagPrinter {
+			break
+		}
+	}
+	for group := range s.Groups() {
+		tags = append(tags, group)
+	}
+
+	assert.Nil(t, s.Err())
+	assert.Equal(t, []int8{TagOperation, TagPrinter, TagPrinter}, tags)
+}
+
+func TestMessageScanner_AttributeGroups(t *testing.T) {
+	s, err := NewMessageScanner(bytes.NewReader(scannerTestResponse(t)))
+	assert.Nil(t, err)
+
+	var names []interface{}
+	for group, attributes := range s.AttributeGroups() {
+		if group != TagPrinter {
+			continue
+		}
+		names = append(names, attributes[AttributePrinterName][0].Value)
+		assert.Len(t, attributes[AttributeDocumentFormatSupported], 3)
+	}
+
+	assert.Nil(t, s.Err())
+	assert.Equal(t, []interface{}{"office", "lab"}, names)
+
+	data, err := io.ReadAll(s.Data())
+	assert.Nil(t, err)
+	assert.Equal(t, "document", string(data))
+}
+
+func TestMessageScanner_ShortRead(t *testing.T) {
+	data := scannerTestResponse(t)
+
+	// the message ends within the name length of the first attribute
+	s, err := NewMessageScanner(bytes.NewReader(data[:11]))
+	assert.Nil(t, err)
+	for range s.Values() {
+	}
+	assert.NotNil(t, s.Err())
+
+	_, err = NewMessageScanner(bytes.NewReader(data[:4]))
+	assert.NotNil(t, err)
+}
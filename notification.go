@@ -0,0 +1,288 @@
+package ipp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+)
+
+// DefaultNotifyGetInterval is the interval of PollNotifications if the printer doesn't return notify-get-interval
+const DefaultNotifyGetInterval = 10 * time.Second
+
+// SubscriptionTemplate describes a subscription created with IPPClient.CreatePrinterSubscription or
+// IPPClient.CreateJobSubscription
+type SubscriptionTemplate struct {
+	// Events are the notify-events keywords, e.g. EventJobCompleted. the printer uses notify-events-default if empty
+	Events []string
+	// RecipientURI is the notify-recipient-uri the printer pushes the events to with Send-Notifications, e.g. a
+	// http or https url of a NotificationReceiver of the server package. the events are pulled with ippget if empty
+	RecipientURI string
+	// LeaseDuration is the notify-lease-duration in seconds of printer subscriptions, zero uses the default of the
+	// printer
+	LeaseDuration int
+	// TimeInterval is the notify-time-interval, the minimum number of seconds between job-progress events
+	TimeInterval int
+	// UserData is sent back as notify-user-data with each event, at most 63 octets
+	UserData string
+}
+
+// attributes returns the subscription template attributes of the template
+func (t SubscriptionTemplate) attributes() map[string]interface{} {
+	attributes := make(map[string]interface{})
+
+	if len(t.Events) > 0 {
+		attributes[AttributeNotifyEvents] = t.Events
+	}
+	if t.RecipientURI != "" {
+		attributes[AttributeNotifyRecipientURI] = t.RecipientURI
+	} else {
+		attributes[AttributeNotifyPullMethod] = PullMethodIppGet
+	}
+	if t.LeaseDuration > 0 {
+		attributes[AttributeNotifyLeaseDuration] = t.LeaseDuration
+	}
+	if t.TimeInterval > 0 {
+		attributes[AttributeNotifyTimeInterval] = t.TimeInterval
+	}
+	if t.UserData != "" {
+		attributes[AttributeNotifyUserData] = t.UserData
+	}
+
+	return attributes
+}
+
+// CreatePrinterSubscription subscribes to the events of the printer and returns the notify-subscription-id
+func (c *IPPClient) CreatePrinterSubscription(printer string, template SubscriptionTemplate) (int, error) {
+	req := NewRequest(OperationCreatePrinterSubscriptions, 1, WithPrinterURI(c.getPrinterUri(printer)))
+	req.SubscriptionAttributes = template.attributes()
+
+	return c.createSubscription(printer, req)
+}
+
+// CreateJobSubscription subscribes to the events of a job and returns the notify-subscription-id. the subscription
+// ends with the job
+func (c *IPPClient) CreateJobSubscription(printer string, jobID int, template SubscriptionTemplate) (int, error) {
+	req := NewRequest(OperationCreateJobSubscriptions, 1, WithPrinterURI(c.getPrinterUri(printer)),
+		WithOperationAttributes(map[string]interface{}{AttributeNotifyJobID: jobID}))
+	req.SubscriptionAttributes = template.attributes()
+
+	return c.createSubscription(printer, req)
+}
+
+func (c *IPPClient) createSubscription(printer string, req *Request) (int, error) {
+	resp, err := c.SendRequest(c.adapter.GetHttpUri("printers", printer), req, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(resp.SubscriptionAttributes) == 0 {
+		return 0, fmt.Errorf("server doesn't return a subscription")
+	}
+
+	u := attributeUnmarshaler{attributes: resp.SubscriptionAttributes[0]}
+	if status := u.int(AttributeNotifyStatusCode); status != 0 {
+		return 0, StatusError{Status: int16(status), Message: "subscription not created"}
+	}
+
+	id := u.int(AttributeNotifySubscriptionID)
+	if id == 0 {
+		return 0, fmt.Errorf("server doesn't return a notify-subscription-id")
+	}
+
+	return id, u.err
+}
+
+// CancelSubscription cancels a subscription of the printer
+func (c *IPPClient) CancelSubscription(printer string, subscriptionID int) error {
+	req := NewRequest(OperationCancelSubscription, 1, WithPrinterURI(c.getPrinterUri(printer)),
+		WithOperationAttributes(map[string]interface{}{AttributeNotifySubscriptionID: subscriptionID}))
+
+	_, err := c.SendRequest(c.adapter.GetHttpUri("printers", printer), req, nil)
+	return err
+}
+
+// NotificationPoll is the result of a Get-Notifications request
+type NotificationPoll struct {
+	Notifications []Notification
+	// Interval is the notify-get-interval, the time the printer asks the client to wait before the next request
+	Interval time.Duration
+	// Complete is set if the printer returned successful-ok-events-complete, the subscription has ended and no
+	// further events follow
+	Complete bool
+}
+
+// GetNotifications pulls the events of a subscription with ippget, starting at the notify-sequence-number sequence.
+// the printer returns immediately, notify-wait is not requested
+func (c *IPPClient) GetNotifications(printer string, subscriptionID, sequence int) (*NotificationPoll, error) {
+	req := NewRequest(OperationGetNotifications, 1, WithPrinterURI(c.getPrinterUri(printer)),
+		WithOperationAttributes(map[string]interface{}{
+			AttributeNotifySubscriptionIDs: subscriptionID,
+			AttributeNotifySequenceNumbers: sequence,
+			AttributeNotifyWait:            false,
+		}))
+
+	resp, err := c.SendRequest(c.adapter.GetHttpUri("printers", printer), req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	poll := &NotificationPoll{Complete: resp.StatusCode == StatusOkEventsComplete}
+	if interval, err := GetOne[int](resp.OperationAttributes, AttributeNotifyGetInterval); err == nil {
+		poll.Interval = time.Duration(interval) * time.Second
+	}
+
+	for _, attributes := range resp.EventNotificationAttributes {
+		var n Notification
+		if err := n.Unmarshal(attributes); err != nil {
+			return nil, err
+		}
+		poll.Notifications = append(poll.Notifications, n)
+	}
+
+	return poll, nil
+}
+
+// PollNotifications pulls the events of a subscription in the notify-get-interval of the printer and passes new
+// events to handle until the context is done or the subscription has ended. it returns nil for an ended subscription,
+// the error of the context or the error of a failed request, e.g. a StatusError with client-error-not-found for a
+// canceled subscription
+func (c *IPPClient) PollNotifications(ctx context.Context, printer string, subscriptionID int,
+	handle func(notifications []Notification)) error {
+	sequence := 1
+	for {
+		poll, err := c.GetNotifications(printer, subscriptionID, sequence)
+		if err != nil {
+			return err
+		}
+
+		for _, n := range poll.Notifications {
+			sequence = max(sequence, n.SequenceNumber+1)
+		}
+		if len(poll.Notifications) > 0 {
+			handle(poll.Notifications)
+		}
+		if poll.Complete {
+			return nil
+		}
+
+		interval := poll.Interval
+		if interval <= 0 {
+			interval = DefaultNotifyGetInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Notification is an event notification of a subscription as returned by Get-Notifications or pushed with
+// Send-Notifications
+type Notification struct {
+	SubscriptionID int
+	SequenceNumber int
+	// Event is the notify-subscribed-event keyword, e.g. job-completed
+	Event      string
+	Text       string
+	PrinterURI string
+	// JobID is the job of job events, zero for printer events
+	JobID    int
+	UserData string
+	// Attributes are all attributes of the notification, e.g. job-state or printer-state-reasons
+	Attributes Attributes
+}
+
+// Unmarshal populates the notification from an event notification attributes group
+func (n *Notification) Unmarshal(attributes Attributes) error {
+	u := attributeUnmarshaler{attributes: attributes}
+
+	*n = Notification{
+		SubscriptionID: u.int(AttributeNotifySubscriptionID),
+		SequenceNumber: u.int(AttributeNotifySequenceNumber),
+		Event:          u.string(AttributeNotifySubscribedEvent),
+		Text:           u.string(AttributeNotifyText),
+		PrinterURI:     u.string(AttributeNotifyPrinterURI),
+		JobID:          u.int(AttributeNotifyJobID),
+		UserData:       u.string(AttributeNotifyUserData),
+		Attributes:     attributes,
+	}
+	if n.JobID == 0 {
+		n.JobID = u.int(AttributeJobID)
+	}
+
+	return u.err
+}
+
+// EncodeSendNotifications encodes a Send-Notifications request of rfc 3995 which pushes the event notification
+// attributes groups to the notify-recipient-uri of a subscription
+func EncodeSendNotifications(requestID int32, notifications []Attributes) ([]byte, error) {
+	var b bytes.Buffer
+	enc := NewAttributeEncoder(&b)
+
+	if err := enc.encodeHeader(ProtocolVersionMajor, ProtocolVersionMinor, OperationSendNotifications,
+		requestID); err != nil {
+		return nil, err
+	}
+
+	if err := enc.encodeTag(TagOperation); err != nil {
+		return nil, err
+	}
+	if err := enc.Encode(AttributeCharset, Charset); err != nil {
+		return nil, err
+	}
+	if err := enc.Encode(AttributeNaturalLanguage, CharsetLanguage); err != nil {
+		return nil, err
+	}
+
+	for _, notification := range notifications {
+		if err := enc.encodeTag(TagEventNotification); err != nil {
+			return nil, err
+		}
+		for _, name := range slices.Sorted(maps.Keys(notification)) {
+			if err := enc.Encode(name, notification[name]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := enc.encodeTag(TagEnd); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// DecodeSendNotifications decodes a Send-Notifications request and returns its header and notifications
+func DecodeSendNotifications(r io.Reader) (MessageHeader, []Notification, error) {
+	scanner, err := NewMessageScanner(r)
+	if err != nil {
+		return MessageHeader{}, nil, err
+	}
+
+	header := scanner.Header()
+	if header.Code != OperationSendNotifications {
+		return header, nil, fmt.Errorf("%w: %s instead of Send-Notifications", OperationNotSupportedError,
+			Operation(header.Code))
+	}
+
+	var notifications []Notification
+	for tag, attributes := range scanner.AttributeGroups() {
+		if tag != TagEventNotification {
+			continue
+		}
+
+		var n Notification
+		if err := n.Unmarshal(attributes); err != nil {
+			return header, nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return header, notifications, scanner.Err()
+}
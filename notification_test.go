@@ -0,0 +1,142 @@
+package ipp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPPClient_CreatePrinterSubscription(t *testing.T) {
+	adapter := &testAdapter{}
+	adapter.respond = func(req *Request) *Response {
+		resp := NewResponse(StatusOk, req.RequestId)
+		if req.Operation == OperationCreatePrinterSubscriptions || req.Operation == OperationCreateJobSubscriptions {
+			sub := make(Attributes)
+			sub.Set(AttributeNotifySubscriptionID, TagInteger, 7)
+			if _, ok := req.SubscriptionAttributes[AttributeNotifyRecipientURI]; ok {
+				sub.Set(AttributeNotifyStatusCode, TagEnum, int(StatusErrorUriScheme))
+			}
+			resp.SubscriptionAttributes = append(resp.SubscriptionAttributes, sub)
+		}
+		return resp
+	}
+	client := NewIPPClientWithAdapter("user", adapter)
+
+	id, err := client.CreatePrinterSubscription("office", SubscriptionTemplate{
+		Events:        []string{EventJobCompleted},
+		LeaseDuration: 600,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 7, id)
+	sent := adapter.requests[0].SubscriptionAttributes
+	assert.Equal(t, PullMethodIppGet, sent[AttributeNotifyPullMethod])
+	assert.Equal(t, 600, sent[AttributeNotifyLeaseDuration])
+
+	_, err = client.CreateJobSubscription("office", 3, SubscriptionTemplate{RecipientURI: "http://client/events"})
+	assert.True(t, errors.Is(err, StatusError{Status: StatusErrorUriScheme}))
+	req := adapter.requests[1]
+	assert.Equal(t, 3, req.OperationAttributes[AttributeNotifyJobID])
+	assert.Equal(t, "http://client/events", req.SubscriptionAttributes[AttributeNotifyRecipientURI])
+	assert.NotContains(t, req.SubscriptionAttributes, AttributeNotifyPullMethod)
+
+	assert.Nil(t, client.CancelSubscription("office", 7))
+	assert.Equal(t, 7, adapter.requests[2].OperationAttributes[AttributeNotifySubscriptionID])
+}
+
+func TestSendNotifications(t *testing.T) {
+	event := make(Attributes)
+	event.Set(AttributeNotifySubscriptionID, TagInteger, 2)
+	event.Set(AttributeNotifySequenceNumber, TagInteger, 5)
+	event.Set(AttributeNotifySubscribedEvent, TagKeyword, EventJobCompleted)
+	event.Set(AttributeNotifyText, TagText, "job completed")
+	event.Set(AttributeJobID, TagInteger, 12)
+	event.Set(AttributeJobState, TagEnum, int(JobStateCompleted))
+
+	data, err := EncodeSendNotifications(9, []Attributes{event})
+	assert.Nil(t, err)
+
+	header, notifications, err := DecodeSendNotifications(bytes.NewReader(data))
+	assert.Nil(t, err)
+	assert.Equal(t, OperationSendNotifications, header.Code)
+	assert.Equal(t, int32(9), header.RequestId)
+	if assert.Len(t, notifications, 1) {
+		n := notifications[0]
+		assert.Equal(t, 2, n.SubscriptionID)
+		assert.Equal(t, 5, n.SequenceNumber)
+		assert.Equal(t, EventJobCompleted, n.Event)
+		assert.Equal(t, "job completed", n.Text)
+		assert.Equal(t, 12, n.JobID)
+		assert.Equal(t, int(JobStateCompleted), n.Attributes[AttributeJobState][0].Value)
+	}
+
+	data, err = NewRequest(OperationGetNotifications, 1).Encode()
+	assert.Nil(t, err)
+	_, _, err = DecodeSendNotifications(bytes.NewReader(data))
+	assert.True(t, errors.Is(err, OperationNotSupportedError))
+}
+
+func TestIPPClient_PollNotifications(t *testing.T) {
+	complete := false
+	adapter := &testAdapter{}
+	adapter.respond = func(req *Request) *Response {
+		resp := NewResponse(StatusOk, req.RequestId)
+		resp.OperationAttributes.Set(AttributeNotifyGetInterval, TagInteger, 30)
+		if complete {
+			resp.StatusCode = StatusOkEventsComplete
+		}
+		for _, sequence := range []int{4, 5} {
+			event := make(Attributes)
+			event.Set(AttributeNotifySubscriptionID, TagInteger, 2)
+			event.Set(AttributeNotifySequenceNumber, TagInteger, sequence)
+			event.Set(AttributeNotifySubscribedEvent, TagKeyword, EventPrinterStateChanged)
+			resp.EventNotificationAttributes = append(resp.EventNotificationAttributes, event)
+		}
+		return resp
+	}
+	client := NewIPPClientWithAdapter("user", adapter)
+
+	poll, err := client.GetNotifications("office", 2, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, 30*time.Second, poll.Interval)
+	assert.False(t, poll.Complete)
+	assert.Len(t, poll.Notifications, 2)
+	assert.Equal(t, 2, adapter.requests[0].OperationAttributes[AttributeNotifySubscriptionIDs])
+	assert.Equal(t, 4, adapter.requests[0].OperationAttributes[AttributeNotifySequenceNumbers])
+
+	// the poll waits for the interval of the printer until the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var received []Notification
+	err = client.PollNotifications(ctx, "office", 2, func(notifications []Notification) {
+		received = append(received, notifications...)
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Len(t, received, 2)
+
+	complete = true
+	received = nil
+	assert.Nil(t, client.PollNotifications(context.Background(), "office", 2, func(notifications []Notification) {
+		received = append(received, notifications...)
+	}))
+	assert.Len(t, received, 2)
+}
+
+func TestNotification_JSON(t *testing.T) {
+	n := Notification{SubscriptionID: 1, SequenceNumber: 2, Event: EventJobCompleted, JobID: 3,
+		Attributes: make(Attributes)}
+	n.Attributes.Set(AttributeJobState, TagEnum, int(JobStateCompleted))
+	n.Attributes.Set(AttributeJobStateReasons, TagKeyword, "job-completed-successfully")
+
+	data, err := json.Marshal(n)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"event":"job-completed"`)
+
+	var decoded Notification
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, n, decoded)
+}
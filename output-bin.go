@@ -0,0 +1,34 @@
+package ipp
+
+import "strconv"
+
+// OutputBinMailbox returns the output-bin keyword of the numbered mailbox of a printer, e.g. mailbox-1
+func OutputBinMailbox(n int) string {
+	return "mailbox-" + strconv.Itoa(n)
+}
+
+// OutputBinStacker returns the output-bin keyword of the numbered stacker of a finisher, e.g. stacker-1
+func OutputBinStacker(n int) string {
+	return "stacker-" + strconv.Itoa(n)
+}
+
+// OutputBinTray returns the output-bin keyword of the numbered output tray, e.g. tray-1
+func OutputBinTray(n int) string {
+	return "tray-" + strconv.Itoa(n)
+}
+
+// WithOutputBin sets the output-bin job attribute, the bin should be listed in output-bin-supported, see
+// PrinterDescription.SupportsOutputBin
+func WithOutputBin(bin string) RequestOption {
+	return func(r *Request) {
+		r.JobAttributes[AttributeOutputBin] = bin
+	}
+}
+
+// WithPageDelivery sets the page-delivery job attribute which selects the order and the side the sheets are stacked
+// in the output bin, e.g. PageDeliverySameOrderFaceDown
+func WithPageDelivery(delivery string) RequestOption {
+	return func(r *Request) {
+		r.JobAttributes[AttributePageDelivery] = delivery
+	}
+}
@@ -0,0 +1,44 @@
+package ipp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputBin(t *testing.T) {
+	assert.Equal(t, "mailbox-3", OutputBinMailbox(3))
+	assert.Equal(t, "stacker-1", OutputBinStacker(1))
+	assert.Equal(t, "tray-2", OutputBinTray(2))
+
+	resp := NewResponse(StatusOk, 1)
+	attributes := make(Attributes)
+	attributes.Set(AttributeOutputBinDefault, TagKeyword, OutputBinFaceDown)
+	attributes.Set(AttributeOutputBinSupported, TagKeyword, OutputBinFaceDown, OutputBinMailbox(1),
+		OutputBinMailbox(2), OutputBinStacker(1))
+	attributes.Set(AttributePageDeliveryDefault, TagKeyword, PageDeliverySameOrderFaceDown)
+	attributes.Set(AttributePageDeliverySupported, TagKeyword, PageDeliverySameOrderFaceDown,
+		PageDeliveryReverseOrderFaceUp)
+	resp.PrinterAttributes = append(resp.PrinterAttributes, attributes)
+
+	data, err := resp.Encode()
+	assert.Nil(t, err)
+	decoded, err := NewResponseDecoder(bytes.NewReader(data)).Decode(nil)
+	assert.Nil(t, err)
+
+	var d PrinterDescription
+	assert.Nil(t, d.Unmarshal(decoded.PrinterAttributes[0]))
+	assert.Equal(t, OutputBinFaceDown, d.OutputBinDefault)
+	assert.True(t, d.SupportsOutputBin(OutputBinMailbox(2)))
+	assert.False(t, d.SupportsOutputBin(OutputBinMyMailbox))
+	assert.Equal(t, PageDeliverySameOrderFaceDown, d.PageDeliveryDefault)
+	assert.True(t, d.SupportsPageDelivery(PageDeliveryReverseOrderFaceUp))
+	assert.False(t, d.SupportsPageDelivery(PageDeliverySameOrderFaceUp))
+
+	req := NewRequest(OperationPrintJob, 1, WithPrinterURI("ipp://localhost/printers/office"),
+		WithOutputBin(OutputBinStacker(1)), WithPageDelivery(PageDeliveryReverseOrderFaceUp))
+	assert.Equal(t, "stacker-1", req.JobAttributes[AttributeOutputBin])
+	assert.Equal(t, PageDeliveryReverseOrderFaceUp, req.JobAttributes[AttributePageDelivery])
+	assert.Empty(t, Lint(withCharset(req)))
+}
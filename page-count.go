@@ -0,0 +1,214 @@
+package ipp
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PageCountError is returned if the pages of a document can not be counted
+var PageCountError = errors.New("unable to count pages")
+
+// PageCounter counts the pages of a document, the reader is consumed
+type PageCounter func(r io.Reader) (int, error)
+
+var (
+	pageCountersMu sync.RWMutex
+	pageCounters   = map[string]PageCounter{
+		MimeTypePDF: CountPDFPages,
+	}
+)
+
+// RegisterPageCounter registers the page counter of a document format, e.g. the pwg sub-package registers the
+// counter of pwg raster and urf documents
+func RegisterPageCounter(format string, counter PageCounter) {
+	pageCountersMu.Lock()
+	defer pageCountersMu.Unlock()
+
+	pageCounters[strings.ToLower(format)] = counter
+}
+
+// CountPages counts the pages of a document with the page counter of the format, e.g. to set the job-impressions of
+// a job. pdf documents are supported by the package, import the pwg sub-package for pwg raster and urf documents.
+// the reader is consumed
+func CountPages(r io.Reader, format string) (int, error) {
+	pageCountersMu.RLock()
+	counter, ok := pageCounters[strings.ToLower(format)]
+	pageCountersMu.RUnlock()
+	if !ok {
+		return 0, fmt.Errorf("%w: no page counter for %s", PageCountError, format)
+	}
+
+	return counter(r)
+}
+
+var (
+	pdfObjectPattern   = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+	pdfRootPattern     = regexp.MustCompile(`/Root\s+(\d+)\s+\d+\s+R`)
+	pdfPagesRefPattern = regexp.MustCompile(`/Pages\s+(\d+)\s+\d+\s+R`)
+	pdfCountPattern    = regexp.MustCompile(`/Count\s+(\d+)`)
+	pdfLengthPattern   = regexp.MustCompile(`/Length\s+(\d+)(\s+\d+\s+R)?`)
+	pdfPagesPattern    = regexp.MustCompile(`/Type\s*/Pages\b`)
+	pdfPagePattern     = regexp.MustCompile(`/Type\s*/Page\b`)
+	pdfObjStmPattern   = regexp.MustCompile(`/Type\s*/ObjStm\b`)
+	pdfFirstPattern    = regexp.MustCompile(`/First\s+(\d+)`)
+)
+
+// CountPDFPages counts the pages of a pdf document. the document is read into memory, the page count is taken from
+// the page tree of the document catalog. objects in compressed object streams and incremental updates are
+// supported, the page tree of encrypted documents is only found if it is not in an object stream
+func CountPDFPages(r io.Reader) (int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	if i := bytes.Index(data, []byte("%PDF-")); i < 0 || i > 1024 {
+		return 0, fmt.Errorf("%w: missing pdf header", PageCountError)
+	}
+
+	objects := pdfObjects(data)
+
+	// the trailer of the last update references the catalog
+	if roots := pdfRootPattern.FindAllSubmatch(data, -1); len(roots) > 0 {
+		catalog := objects[atoiDefault(string(roots[len(roots)-1][1]), -1)]
+		if ref := pdfPagesRefPattern.FindSubmatch(catalog); ref != nil {
+			if count := pdfCountPattern.FindSubmatch(objects[atoiDefault(string(ref[1]), -1)]); count != nil {
+				return atoiDefault(string(count[1]), 0), nil
+			}
+		}
+	}
+
+	// without catalog, the largest page tree root or the page objects are counted
+	pages, roots := 0, 0
+	for _, body := range objects {
+		switch {
+		case pdfPagesPattern.Match(body) && !bytes.Contains(body, []byte("/Parent")):
+			if count := pdfCountPattern.FindSubmatch(body); count != nil {
+				roots = max(roots, atoiDefault(string(count[1]), 0))
+			}
+		case pdfPagePattern.Match(body):
+			pages++
+		}
+	}
+	if roots > 0 {
+		return roots, nil
+	}
+	if pages > 0 {
+		return pages, nil
+	}
+
+	return 0, fmt.Errorf("%w: no pages found", PageCountError)
+}
+
+// pdfObjects returns the dictionaries of the objects of a pdf document by object number. later definitions replace
+// earlier ones, objects of object streams are replaced by uncompressed objects
+func pdfObjects(data []byte) map[int][]byte {
+	objects := make(map[int][]byte)
+	var streams [][]byte
+
+	matches := pdfObjectPattern.FindAllSubmatchIndex(data, -1)
+	for i, match := range matches {
+		end := len(data)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		body := data[match[1]:end]
+
+		dictionary, stream := body, []byte(nil)
+		if s := bytes.Index(body, []byte("stream")); s >= 0 {
+			dictionary, stream = body[:s], pdfStreamData(body[:s], body[s+len("stream"):])
+		}
+		if e := bytes.Index(dictionary, []byte("endobj")); e >= 0 {
+			dictionary = dictionary[:e]
+		}
+
+		number := atoiDefault(string(data[match[2]:match[3]]), -1)
+		objects[number] = dictionary
+
+		if pdfObjStmPattern.Match(dictionary) && stream != nil {
+			streams = append(streams, dictionary, stream)
+		}
+	}
+
+	compressed := make(map[int][]byte)
+	for i := 0; i < len(streams); i += 2 {
+		for number, body := range pdfObjectStream(streams[i], streams[i+1]) {
+			compressed[number] = body
+		}
+	}
+	for number, body := range compressed {
+		if _, ok := objects[number]; !ok {
+			objects[number] = body
+		}
+	}
+
+	return objects
+}
+
+// pdfStreamData returns the data of a stream following the stream keyword
+func pdfStreamData(dictionary, data []byte) []byte {
+	if bytes.HasPrefix(data, []byte("\r\n")) {
+		data = data[2:]
+	} else if bytes.HasPrefix(data, []byte("\n")) {
+		data = data[1:]
+	}
+
+	if length := pdfLengthPattern.FindSubmatch(dictionary); length != nil && len(length[2]) == 0 {
+		if n := atoiDefault(string(length[1]), -1); n >= 0 && n <= len(data) {
+			return data[:n]
+		}
+	}
+
+	if e := bytes.Index(data, []byte("endstream")); e >= 0 {
+		return bytes.TrimRight(data[:e], "\r\n")
+	}
+
+	return nil
+}
+
+// pdfObjectStream returns the objects of an object stream, streams with other filters than FlateDecode are skipped
+func pdfObjectStream(dictionary, stream []byte) map[int][]byte {
+	if bytes.Contains(dictionary, []byte("/Filter")) {
+		if !bytes.Contains(dictionary, []byte("/FlateDecode")) || bytes.Contains(dictionary, []byte("/DecodeParms")) {
+			return nil
+		}
+		z, err := zlib.NewReader(bytes.NewReader(stream))
+		if err != nil {
+			return nil
+		}
+		// truncated streams still yield the decompressed objects
+		stream, _ = io.ReadAll(z)
+	}
+
+	first := pdfFirstPattern.FindSubmatch(dictionary)
+	if first == nil {
+		return nil
+	}
+	offset := atoiDefault(string(first[1]), -1)
+	if offset < 0 || offset > len(stream) {
+		return nil
+	}
+
+	header := strings.Fields(string(stream[:offset]))
+	objects := make(map[int][]byte)
+	for i := 0; i+1 < len(header); i += 2 {
+		number, err1 := strconv.Atoi(header[i])
+		start, err2 := strconv.Atoi(header[i+1])
+		end := len(stream) - offset
+		if i+3 < len(header) {
+			end, _ = strconv.Atoi(header[i+3])
+		}
+		if err1 != nil || err2 != nil || start < 0 || start > end || offset+end > len(stream) {
+			return objects
+		}
+		objects[number] = stream[offset+start : offset+end]
+	}
+
+	return objects
+}
@@ -0,0 +1,98 @@
+package ipp
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testPDF builds a pdf document of the objects numbered from 1 with a trailer referencing the catalog, empty objects
+// are left out
+func testPDF(root int, objects ...string) string {
+	var b strings.Builder
+	b.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	for i, object := range objects {
+		if object == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root %d 0 R >>\n%%%%EOF\n", len(objects)+1, root)
+
+	return b.String()
+}
+
+func TestCountPDFPages(t *testing.T) {
+	simple := testPDF(1,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R 5 0 R] /Count 3 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] >>",
+		"<< /Type /Page /Parent 2 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 6 0 R >>",
+		"<< /Length 10 >>\nstream\n0 0 obj\nab\nendstream",
+	)
+
+	// an incremental update adds a page
+	update := simple + "2 0 obj\n<< /Type /Pages /Kids [3 0 R 4 0 R 5 0 R 7 0 R] /Count 4 >>\nendobj\n" +
+		"7 0 obj\n<< /Type /Page /Parent 2 0 R >>\nendobj\ntrailer\n<< /Root 1 0 R /Prev 9 >>\n%%EOF\n"
+
+	// the page tree is in a compressed object stream
+	pagesObject, pageObject := "<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >> ", "<< /Type /Page /Parent 2 0 R >> "
+	objects := pagesObject + pageObject + pageObject
+	header := fmt.Sprintf("2 0 3 %d 4 %d ", len(pagesObject), len(pagesObject)+len(pageObject))
+	var compressed bytes.Buffer
+	z := zlib.NewWriter(&compressed)
+	z.Write([]byte(header + objects))
+	z.Close()
+	objStm := testPDF(1,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"",
+		"",
+		"",
+		fmt.Sprintf("<< /Type /ObjStm /N 3 /First %d /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream",
+			len(header), compressed.Len(), compressed.String()),
+	)
+
+	// without catalog the page objects are counted
+	noCatalog := "%PDF-1.4\n1 0 obj\n<< /Type /Page >>\nendobj\n2 0 obj\n<< /Type/Page >>\nendobj\n"
+
+	for _, tt := range []struct {
+		name string
+		pdf  string
+		want int
+	}{
+		{"simple", simple, 3},
+		{"incremental update", update, 4},
+		{"object stream", objStm, 2},
+		{"without catalog", noCatalog, 2},
+	} {
+		pages, err := CountPages(strings.NewReader(tt.pdf), MimeTypePDF)
+		assert.Nil(t, err, tt.name)
+		assert.Equal(t, tt.want, pages, tt.name)
+	}
+
+	_, err := CountPDFPages(strings.NewReader("%!PS-Adobe-3.0"))
+	assert.ErrorIs(t, err, PageCountError)
+	_, err = CountPDFPages(strings.NewReader("%PDF-1.4\n1 0 obj\n<< >>\nendobj\n"))
+	assert.ErrorIs(t, err, PageCountError)
+}
+
+func TestCountPages(t *testing.T) {
+	_, err := CountPages(strings.NewReader(""), "image/tiff")
+	assert.ErrorIs(t, err, PageCountError)
+
+	RegisterPageCounter("text/x-pages", func(r io.Reader) (int, error) {
+		b, err := io.ReadAll(r)
+		return bytes.Count(b, []byte("\f")) + 1, err
+	})
+	defer delete(pageCounters, "text/x-pages")
+
+	pages, err := CountPages(strings.NewReader("one\ftwo"), "text/X-Pages")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, pages)
+}
@@ -0,0 +1,206 @@
+package ppd
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// PWGMediaNames maps common adobe page size names to pwg 5101.1 media names
+var PWGMediaNames = map[string]string{
+	"Letter":    "na_letter_8.5x11in",
+	"Legal":     "na_legal_8.5x14in",
+	"Executive": "na_executive_7.25x10.5in",
+	"Tabloid":   "na_ledger_11x17in",
+	"Ledger":    "na_ledger_11x17in",
+	"Statement": "na_invoice_5.5x8.5in",
+	"A3":        "iso_a3_297x420mm",
+	"A4":        "iso_a4_210x297mm",
+	"A5":        "iso_a5_148x210mm",
+	"A6":        "iso_a6_105x148mm",
+	"B5":        "iso_b5_176x250mm",
+	"Env10":     "na_number-10_4.125x9.5in",
+	"EnvDL":     "iso_dl_110x220mm",
+	"EnvC5":     "iso_c5_162x229mm",
+	"4x6":       "na_index-4x6_4x6in",
+}
+
+// JobAttributes translates the given option selection, merged with the ppd defaults, into ipp job attributes.
+// options without an ipp equivalent are ignored
+func (p *PPD) JobAttributes(selected map[string]string) (map[string]interface{}, error) {
+	marked := p.Defaults()
+	for option, choice := range selected {
+		option = strings.TrimPrefix(option, "*")
+
+		o := p.Option(option)
+		if o == nil {
+			return nil, fmt.Errorf("ppd has no option %s", option)
+		}
+
+		if o.Choice(choice) == nil && !strings.HasPrefix(choice, "Custom.") {
+			return nil, fmt.Errorf("option %s has no choice %s", option, choice)
+		}
+
+		marked[option] = choice
+	}
+
+	attributes := make(map[string]interface{})
+
+	for _, o := range p.Options {
+		choice, ok := marked[o.Keyword]
+		if !ok {
+			continue
+		}
+
+		switch o.Keyword {
+		case "PageSize", "PageRegion":
+			if name, ok := PWGMediaNames[choice]; ok {
+				attributes[ipp.AttributeMedia] = name
+			} else if strings.HasPrefix(choice, "Custom.") {
+				// custom sizes are sent with their self describing name, invalid sizes are left out
+				if width, height, ok := parseCustomSize(strings.TrimPrefix(choice, "Custom.")); ok {
+					attributes[ipp.AttributeMedia] = ipp.CustomMediaSizeName(width, height)
+				}
+			} else {
+				attributes[ipp.AttributeMedia] = choice
+			}
+		case "Duplex", "EFDuplex", "KMDuplex":
+			switch choice {
+			case "None", "False", "Off":
+				attributes[ipp.AttributeSides] = "one-sided"
+			case "DuplexNoTumble", "LongEdge", "True", "On":
+				attributes[ipp.AttributeSides] = "two-sided-long-edge"
+			case "DuplexTumble", "ShortEdge":
+				attributes[ipp.AttributeSides] = "two-sided-short-edge"
+			}
+		case "ColorModel", "ColorMode", "cupsColorModel":
+			switch strings.ToLower(choice) {
+			case "gray", "grayscale", "black", "mono", "monochrome", "kgray":
+				attributes[ipp.AttributePrintColorMode] = "monochrome"
+			default:
+				attributes[ipp.AttributePrintColorMode] = "color"
+			}
+		case "Resolution", "cupsResolution":
+			if res, ok := parseResolution(choice); ok {
+				attributes[ipp.AttributePrinterResolution] = res
+			}
+		case "cupsPrintQuality":
+			switch choice {
+			case "Draft":
+				attributes[ipp.AttributePrintQuality] = 3
+			case "Normal":
+				attributes[ipp.AttributePrintQuality] = 4
+			case "High":
+				attributes[ipp.AttributePrintQuality] = 5
+			}
+		case "InputSlot":
+			attributes[ipp.AttributeMediaSource] = pwgKeyword(choice)
+		case "MediaType":
+			attributes[ipp.AttributeMediaType] = pwgKeyword(choice)
+		case "OutputBin":
+			attributes[ipp.AttributeOutputBin] = pwgKeyword(choice)
+		}
+	}
+
+	return attributes, nil
+}
+
+// customSizeUnits are the units of ppd custom page sizes in hundredths of millimeters, sizes without unit are in
+// points
+var customSizeUnits = []struct {
+	suffix string
+	factor float64
+}{
+	{suffix: "mm", factor: 100},
+	{suffix: "cm", factor: 1000},
+	{suffix: "in", factor: 2540},
+	{suffix: "ft", factor: 30480},
+	{suffix: "pt", factor: 2540.0 / 72},
+	{suffix: "m", factor: 100000},
+}
+
+// parseCustomSize parses the size of a ppd custom page size like 612x792 or 4x6in into hundredths of millimeters
+func parseCustomSize(s string) (int, int, bool) {
+	factor := 2540.0 / 72
+	for _, unit := range customSizeUnits {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSuffix(s, unit.suffix)
+			factor = unit.factor
+			break
+		}
+	}
+
+	parts := strings.SplitN(s, "x", 2)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+
+	width, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || width <= 0 {
+		return 0, 0, false
+	}
+	height, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil || height <= 0 {
+		return 0, 0, false
+	}
+
+	return int(math.Round(width * factor)), int(math.Round(height * factor)), true
+}
+
+// parseResolution parses ppd resolutions like 600dpi or 1200x600dpi
+func parseResolution(s string) (ipp.Resolution, bool) {
+	units := int8(3)
+	switch {
+	case strings.HasSuffix(s, "dpi"):
+		s = strings.TrimSuffix(s, "dpi")
+	case strings.HasSuffix(s, "dpcm"):
+		s = strings.TrimSuffix(s, "dpcm")
+		units = 4
+	default:
+		return ipp.Resolution{}, false
+	}
+
+	parts := strings.SplitN(s, "x", 2)
+
+	x, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return ipp.Resolution{}, false
+	}
+
+	y := x
+	if len(parts) == 2 {
+		if y, err = strconv.Atoi(parts[1]); err != nil {
+			return ipp.Resolution{}, false
+		}
+	}
+
+	return ipp.Resolution{Height: int32(x), Width: int32(y), Depth: units}, true
+}
+
+// pwgKeyword converts a ppd choice name like ManualFeed into a pwg keyword like manual-feed
+func pwgKeyword(s string) string {
+	var sb strings.Builder
+	lower := false
+
+	for _, r := range s {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			if lower {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r - 'A' + 'a')
+			lower = false
+		case r == '_' || r == ' ' || r == '.':
+			sb.WriteByte('-')
+			lower = false
+		default:
+			sb.WriteRune(r)
+			lower = true
+		}
+	}
+
+	return sb.String()
+}
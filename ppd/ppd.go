@@ -0,0 +1,366 @@
+// Package ppd parses PostScript Printer Description files, as returned by the cups CUPS-Get-PPD operation,
+// and translates selected ppd choices into ipp job attributes
+package ppd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ui types of an option
+const (
+	UIPickOne  = "PickOne"
+	UIPickMany = "PickMany"
+	UIBoolean  = "Boolean"
+)
+
+// Attribute defines a main keyword entry of a ppd file which is not part of an option
+type Attribute struct {
+	Name  string
+	Spec  string
+	Text  string
+	Value string
+}
+
+// Choice defines a single choice of an option
+type Choice struct {
+	Choice string
+	Text   string
+	Code   string
+}
+
+// Option defines an user interface option of a ppd file
+type Option struct {
+	Keyword string
+	Text    string
+	UI      string
+	Group   string
+	Default string
+	Choices []*Choice
+}
+
+// Choice returns the choice with the given name or nil if the option has no such choice
+func (o *Option) Choice(name string) *Choice {
+	for _, c := range o.Choices {
+		if c.Choice == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// Group defines a group of options
+type Group struct {
+	Name    string
+	Text    string
+	Options []*Option
+}
+
+// Constraint defines an ui constraint between two options. an empty choice means any choice except None, False or Off
+type Constraint struct {
+	Option1 string
+	Choice1 string
+	Option2 string
+	Choice2 string
+}
+
+// PPD defines a parsed ppd file
+type PPD struct {
+	FormatVersion string
+	Manufacturer  string
+	ModelName     string
+	NickName      string
+	ColorDevice   bool
+
+	Groups      []*Group
+	Options     []*Option
+	Constraints []Constraint
+	Attributes  map[string][]Attribute
+}
+
+// Option returns the option with the given keyword or nil if the ppd has no such option
+func (p *PPD) Option(keyword string) *Option {
+	keyword = strings.TrimPrefix(keyword, "*")
+
+	for _, o := range p.Options {
+		if o.Keyword == keyword {
+			return o
+		}
+	}
+
+	return nil
+}
+
+// Attribute returns the value of the first attribute with the given name and spec
+func (p *PPD) Attribute(name, spec string) (string, bool) {
+	for _, attr := range p.Attributes[name] {
+		if attr.Spec == spec {
+			return attr.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Defaults returns a map of option keywords and their default choices
+func (p *PPD) Defaults() map[string]string {
+	defaults := make(map[string]string, len(p.Options))
+
+	for _, o := range p.Options {
+		if o.Default != "" {
+			defaults[o.Keyword] = o.Default
+		}
+	}
+
+	return defaults
+}
+
+// Conflicts returns all constraints which are violated by the given option selection. options which are not selected are
+// checked with their default choice
+func (p *PPD) Conflicts(selected map[string]string) []Constraint {
+	marked := p.Defaults()
+	for option, choice := range selected {
+		marked[strings.TrimPrefix(option, "*")] = choice
+	}
+
+	conflicts := make([]Constraint, 0)
+
+	for _, c := range p.Constraints {
+		if isMarked(marked, c.Option1, c.Choice1) && isMarked(marked, c.Option2, c.Choice2) {
+			conflicts = append(conflicts, c)
+		}
+	}
+
+	return conflicts
+}
+
+func isMarked(marked map[string]string, option, choice string) bool {
+	value, ok := marked[option]
+	if !ok {
+		return false
+	}
+
+	if choice == "" {
+		switch value {
+		case "None", "False", "Off", "":
+			return false
+		}
+		return true
+	}
+
+	return value == choice
+}
+
+// Parse reads and parses a ppd file
+func Parse(r io.Reader) (*PPD, error) {
+	p := &PPD{
+		Groups:      make([]*Group, 0),
+		Options:     make([]*Option, 0),
+		Constraints: make([]Constraint, 0),
+		Attributes:  make(map[string][]Attribute),
+	}
+
+	options := make(map[string]*Option)
+	defaults := make(map[string]string)
+
+	var group *Group
+	var option *Option
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if lineNumber == 1 && !strings.HasPrefix(line, "*PPD-Adobe:") {
+			return nil, errors.New("missing *PPD-Adobe header")
+		}
+
+		if !strings.HasPrefix(line, "*") || strings.HasPrefix(line, "*%") || line == "*End" {
+			continue
+		}
+
+		name, spec, text, value := splitLine(line[1:])
+
+		// quoted values may span multiple lines
+		if strings.HasPrefix(value, "\"") && (len(value) == 1 || !strings.HasSuffix(value, "\"")) {
+			var sb strings.Builder
+			sb.WriteString(value)
+			closed := false
+			for scanner.Scan() {
+				lineNumber++
+				next := strings.TrimRight(scanner.Text(), "\r")
+				sb.WriteString("\n")
+				sb.WriteString(next)
+				if strings.HasSuffix(next, "\"") {
+					closed = true
+					break
+				}
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string for keyword %s", name)
+			}
+			value = sb.String()
+		}
+		value = unquote(value)
+
+		switch name {
+		case "OpenGroup":
+			groupName, groupText := splitTranslation(value)
+			group = &Group{Name: groupName, Text: groupText, Options: make([]*Option, 0)}
+			p.Groups = append(p.Groups, group)
+		case "CloseGroup":
+			group = nil
+		case "OpenUI", "JCLOpenUI":
+			keyword := strings.TrimPrefix(spec, "*")
+			option = &Option{
+				Keyword: keyword,
+				Text:    text,
+				UI:      value,
+				Choices: make([]*Choice, 0),
+			}
+			if option.Text == "" {
+				option.Text = keyword
+			}
+			if group != nil {
+				option.Group = group.Name
+				group.Options = append(group.Options, option)
+			}
+			options[keyword] = option
+			p.Options = append(p.Options, option)
+		case "CloseUI", "JCLCloseUI":
+			option = nil
+		case "UIConstraints", "NonUIConstraints":
+			if c, ok := parseConstraint(value); ok {
+				p.Constraints = append(p.Constraints, c)
+			}
+		case "FormatVersion":
+			p.FormatVersion = value
+		case "Manufacturer":
+			p.Manufacturer = value
+		case "ModelName":
+			p.ModelName = value
+		case "NickName":
+			p.NickName = value
+		case "ColorDevice":
+			p.ColorDevice = value == "True"
+		default:
+			if strings.HasPrefix(name, "Default") && spec == "" {
+				defaults[strings.TrimPrefix(name, "Default")] = value
+				continue
+			}
+
+			if o, ok := options[name]; ok && spec != "" {
+				o.Choices = append(o.Choices, &Choice{
+					Choice: spec,
+					Text:   translationOrSpec(text, spec),
+					Code:   value,
+				})
+				continue
+			}
+
+			p.Attributes[name] = append(p.Attributes[name], Attribute{
+				Name:  name,
+				Spec:  spec,
+				Text:  text,
+				Value: value,
+			})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if lineNumber == 0 {
+		return nil, errors.New("empty ppd file")
+	}
+
+	for keyword, def := range defaults {
+		if o, ok := options[keyword]; ok {
+			o.Default = def
+		}
+	}
+
+	return p, nil
+}
+
+// splitLine splits a line without the leading asterisk into the main keyword, the option keyword, its translation and the value
+func splitLine(line string) (name, spec, text, value string) {
+	keyPart := line
+	if i := strings.Index(line, ":"); i >= 0 {
+		keyPart = line[:i]
+		value = strings.TrimSpace(line[i+1:])
+	}
+
+	if i := strings.IndexAny(keyPart, " \t"); i >= 0 {
+		name = keyPart[:i]
+		spec, text = splitTranslation(strings.TrimSpace(keyPart[i+1:]))
+	} else {
+		name = keyPart
+	}
+
+	return
+}
+
+func splitTranslation(s string) (string, string) {
+	if i := strings.Index(s, "/"); i >= 0 {
+		return s[:i], s[i+1:]
+	}
+
+	return s, ""
+}
+
+func translationOrSpec(text, spec string) string {
+	if text == "" {
+		return spec
+	}
+
+	return text
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && strings.HasPrefix(s, "\"") && strings.HasSuffix(s, "\"") {
+		return s[1 : len(s)-1]
+	}
+
+	return s
+}
+
+func parseConstraint(value string) (Constraint, bool) {
+	fields := strings.Fields(value)
+	c := Constraint{}
+	options := 0
+
+	for _, field := range fields {
+		if strings.HasPrefix(field, "*") {
+			options++
+			switch options {
+			case 1:
+				c.Option1 = field[1:]
+			case 2:
+				c.Option2 = field[1:]
+			default:
+				return c, false
+			}
+			continue
+		}
+
+		switch options {
+		case 1:
+			c.Choice1 = field
+		case 2:
+			c.Choice2 = field
+		default:
+			return c, false
+		}
+	}
+
+	return c, options == 2
+}
@@ -0,0 +1,131 @@
+package ppd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPPD = `*PPD-Adobe: "4.3"
+*% test ppd
+*FormatVersion: "4.3"
+*Manufacturer: "Acme"
+*ModelName: "Acme Laser 1000"
+*NickName: "Acme Laser 1000, 1.0"
+*ColorDevice: True
+*cupsFilter: "application/vnd.cups-raster 0 rastertoacme"
+*OpenGroup: General/General
+*OpenUI *PageSize/Media Size: PickOne
+*OrderDependency: 10 AnySetup *PageSize
+*DefaultPageSize: A4
+*PageSize A4/A4: "<</PageSize[595 842]>>setpagedevice"
+*PageSize Letter/US Letter: "<</PageSize[612 792]>>
+setpagedevice"
+*End
+*CloseUI: *PageSize
+*OpenUI *Duplex/2-Sided Printing: PickOne
+*DefaultDuplex: None
+*Duplex None/Off: "<</Duplex false>>setpagedevice"
+*Duplex DuplexNoTumble/Long Edge: "<</Duplex true/Tumble false>>setpagedevice"
+*Duplex DuplexTumble/Short Edge: "<</Duplex true/Tumble true>>setpagedevice"
+*CloseUI: *Duplex
+*OpenUI *InputSlot/Media Source: PickOne
+*DefaultInputSlot: Auto
+*InputSlot Auto/Automatic: ""
+*InputSlot ManualFeed/Manual Feed: ""
+*CloseUI: *InputSlot
+*OpenUI *Resolution/Resolution: PickOne
+*DefaultResolution: 600dpi
+*Resolution 600dpi/600 DPI: ""
+*Resolution 1200x600dpi/1200x600 DPI: ""
+*CloseUI: *Resolution
+*CloseGroup: General
+*UIConstraints: *Duplex *InputSlot ManualFeed
+*UIConstraints: *InputSlot ManualFeed *Duplex
+`
+
+func TestParse(t *testing.T) {
+	p, err := Parse(strings.NewReader(testPPD))
+	assert.Nil(t, err)
+
+	assert.Equal(t, "Acme", p.Manufacturer)
+	assert.Equal(t, "Acme Laser 1000", p.ModelName)
+	assert.True(t, p.ColorDevice)
+	assert.Len(t, p.Groups, 1)
+	assert.Len(t, p.Options, 4)
+	assert.Len(t, p.Constraints, 2)
+
+	pageSize := p.Option("PageSize")
+	assert.NotNil(t, pageSize)
+	assert.Equal(t, "Media Size", pageSize.Text)
+	assert.Equal(t, UIPickOne, pageSize.UI)
+	assert.Equal(t, "General", pageSize.Group)
+	assert.Equal(t, "A4", pageSize.Default)
+	assert.Len(t, pageSize.Choices, 2)
+	assert.Equal(t, "US Letter", pageSize.Choice("Letter").Text)
+	assert.Equal(t, "<</PageSize[612 792]>>\nsetpagedevice", pageSize.Choice("Letter").Code)
+
+	filter, ok := p.Attribute("cupsFilter", "")
+	assert.True(t, ok)
+	assert.Equal(t, "application/vnd.cups-raster 0 rastertoacme", filter)
+}
+
+func TestParse_InvalidHeader(t *testing.T) {
+	_, err := Parse(strings.NewReader("*Manufacturer: \"Acme\"\n"))
+	assert.NotNil(t, err)
+}
+
+func TestPPD_Conflicts(t *testing.T) {
+	p, err := Parse(strings.NewReader(testPPD))
+	assert.Nil(t, err)
+
+	assert.Empty(t, p.Conflicts(map[string]string{"Duplex": "DuplexTumble"}))
+	assert.Empty(t, p.Conflicts(map[string]string{"InputSlot": "ManualFeed"}))
+	assert.Len(t, p.Conflicts(map[string]string{"Duplex": "DuplexTumble", "InputSlot": "ManualFeed"}), 2)
+}
+
+func TestPPD_JobAttributes(t *testing.T) {
+	p, err := Parse(strings.NewReader(testPPD))
+	assert.Nil(t, err)
+
+	attributes, err := p.JobAttributes(map[string]string{
+		"PageSize":   "Letter",
+		"Duplex":     "DuplexNoTumble",
+		"InputSlot":  "ManualFeed",
+		"Resolution": "1200x600dpi",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		ipp.AttributeMedia:             "na_letter_8.5x11in",
+		ipp.AttributeSides:             "two-sided-long-edge",
+		ipp.AttributeMediaSource:       "manual-feed",
+		ipp.AttributePrinterResolution: ipp.Resolution{Height: 1200, Width: 600, Depth: 3},
+	}, attributes)
+
+	_, err = p.JobAttributes(map[string]string{"PageSize": "A0"})
+	assert.NotNil(t, err)
+}
+
+func TestPPD_JobAttributes_CustomPageSize(t *testing.T) {
+	p, err := Parse(strings.NewReader(testPPD))
+	assert.Nil(t, err)
+
+	tests := []struct {
+		choice string
+		media  interface{}
+	}{
+		{choice: "Custom.612x792", media: "custom_8.5x11in_8.5x11in"},
+		{choice: "Custom.4x6in", media: "custom_4x6in_4x6in"},
+		{choice: "Custom.100x150mm", media: "custom_100x150mm_100x150mm"},
+		{choice: "Custom.10.5x14.8cm", media: "custom_105x148mm_105x148mm"},
+		{choice: "Custom.wide", media: nil},
+	}
+
+	for _, test := range tests {
+		attributes, err := p.JobAttributes(map[string]string{"PageSize": test.choice})
+		assert.Nil(t, err)
+		assert.Equal(t, test.media, attributes[ipp.AttributeMedia], test.choice)
+	}
+}
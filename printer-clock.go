@@ -0,0 +1,102 @@
+package ipp
+
+import "time"
+
+// PrinterClock relates the printer-up-time of a printer to the wall clock. it converts the integer time-at-creation,
+// time-at-processing and time-at-completed attributes, which are seconds of printer up time, into times
+type PrinterClock struct {
+	// UpTime is the printer-up-time or job-printer-up-time of the response
+	UpTime int
+	// CurrentTime is the wall clock time at UpTime, the printer-current-time of the response or the time the
+	// response was received
+	CurrentTime time.Time
+}
+
+// NewPrinterClock creates a clock from the printer-up-time and printer-current-time of the attributes. the
+// job-printer-up-time is used if printer-up-time is missing, e.g. in job attributes, and now is used if
+// printer-current-time is missing
+func NewPrinterClock(attributes Attributes, now time.Time) PrinterClock {
+	u := attributeUnmarshaler{attributes: attributes}
+
+	clock := PrinterClock{UpTime: u.int(AttributePrinterUpTime), CurrentTime: now}
+	if clock.UpTime <= 0 {
+		clock.UpTime = u.int(AttributeJobPrinterUpTime)
+	}
+	if t, ok := u.dateTime(AttributePrinterCurrentTime); ok {
+		clock.CurrentTime = t
+	}
+
+	return clock
+}
+
+// PrinterClock returns the clock of the response, the up time and current time are taken from the first printer or
+// job attributes group containing them
+func (r *Response) PrinterClock(now time.Time) PrinterClock {
+	clock := PrinterClock{CurrentTime: now}
+	currentTimeFound := false
+
+	groups := append(append([]Attributes{r.OperationAttributes}, r.PrinterAttributes...), r.JobAttributes...)
+	for _, attributes := range groups {
+		if clock.UpTime <= 0 {
+			clock.UpTime = NewPrinterClock(attributes, now).UpTime
+		}
+
+		u := attributeUnmarshaler{attributes: attributes}
+		if t, ok := u.dateTime(AttributePrinterCurrentTime); ok && !currentTimeFound {
+			clock.CurrentTime = t
+			currentTimeFound = true
+		}
+	}
+
+	return clock
+}
+
+// Time converts seconds of printer up time into a time, a zero time is returned if the up time or the clock are not
+// set
+func (c PrinterClock) Time(upTime int) time.Time {
+	if upTime <= 0 || c.UpTime <= 0 {
+		return time.Time{}
+	}
+
+	return c.CurrentTime.Add(-time.Duration(c.UpTime-upTime) * time.Second).Truncate(time.Second)
+}
+
+// PrinterUpTime converts a time into seconds of printer up time, the result is never less than 1
+func (c PrinterClock) PrinterUpTime(t time.Time) int {
+	upTime := c.UpTime - int(c.CurrentTime.Sub(t)/time.Second)
+	if upTime < 1 {
+		return 1
+	}
+
+	return upTime
+}
+
+// JobTime returns the value of a dateTime attribute, e.g. date-time-at-creation, or converts the integer attribute,
+// e.g. time-at-creation, if the printer does not return the dateTime attribute
+func (c PrinterClock) JobTime(attributes Attributes, dateTime, upTime string) time.Time {
+	u := attributeUnmarshaler{attributes: attributes}
+	if t, ok := u.dateTime(dateTime); ok {
+		return t
+	}
+
+	return c.Time(u.int(upTime))
+}
+
+// PendingDuration returns the time the job waited until processing started, zero if the times are unknown
+func (j *Job) PendingDuration() time.Duration {
+	if j.CreatedAt.IsZero() || j.ProcessingAt.IsZero() {
+		return 0
+	}
+
+	return j.ProcessingAt.Sub(j.CreatedAt)
+}
+
+// ProcessingDuration returns the time from the start of processing until the job was completed, canceled or
+// aborted, zero if the times are unknown
+func (j *Job) ProcessingDuration() time.Duration {
+	if j.ProcessingAt.IsZero() || j.CompletedAt.IsZero() {
+		return 0
+	}
+
+	return j.CompletedAt.Sub(j.ProcessingAt)
+}
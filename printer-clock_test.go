@@ -0,0 +1,56 @@
+package ipp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrinterClock(t *testing.T) {
+	now := time.Date(2021, 6, 16, 10, 0, 0, 0, time.UTC)
+	current := time.Date(2021, 6, 16, 9, 59, 0, 0, time.UTC)
+
+	printer := make(Attributes)
+	printer.Set(AttributePrinterUpTime, TagInteger, 1000)
+	printer.Set(AttributePrinterCurrentTime, TagDate, EncodeDateTime(current))
+
+	clock := NewPrinterClock(printer, now)
+	assert.Equal(t, 1000, clock.UpTime)
+	assert.True(t, current.Equal(clock.CurrentTime))
+	assert.True(t, current.Add(-100*time.Second).Equal(clock.Time(900)))
+	assert.True(t, clock.Time(0).IsZero())
+	assert.Equal(t, 900, clock.PrinterUpTime(current.Add(-100*time.Second)))
+	assert.Equal(t, 1, clock.PrinterUpTime(current.Add(-time.Hour)))
+
+	job := make(Attributes)
+	job.Set(AttributeJobPrinterUpTime, TagInteger, 500)
+	job.Set(AttributeTimeAtCreation, TagInteger, 440)
+	job.Set(AttributeTimeAtCompleted, TagInteger, 480)
+	job.Set(AttributeDateTimeAtCompleted, TagDate, EncodeDateTime(now))
+
+	clock = NewPrinterClock(job, now)
+	assert.Equal(t, 500, clock.UpTime)
+	assert.Equal(t, now.Add(-time.Minute), clock.JobTime(job, AttributeDateTimeAtCreation, AttributeTimeAtCreation))
+	assert.True(t, now.Equal(clock.JobTime(job, AttributeDateTimeAtCompleted, AttributeTimeAtCompleted)))
+
+	resp := NewResponse(StatusOk, 1)
+	resp.JobAttributes = append(resp.JobAttributes, job)
+	resp.PrinterAttributes = append(resp.PrinterAttributes, printer)
+	clock = resp.PrinterClock(now)
+	assert.Equal(t, 1000, clock.UpTime)
+	assert.True(t, current.Equal(clock.CurrentTime))
+
+	resp.PrinterAttributes = nil
+	assert.Equal(t, PrinterClock{UpTime: 500, CurrentTime: now}, resp.PrinterClock(now))
+}
+
+func TestJob_Durations(t *testing.T) {
+	created := time.Date(2021, 6, 16, 10, 0, 0, 0, time.UTC)
+	job := Job{CreatedAt: created, ProcessingAt: created.Add(time.Minute)}
+	assert.Equal(t, time.Minute, job.PendingDuration())
+	assert.Equal(t, time.Duration(0), job.ProcessingDuration())
+
+	job.CompletedAt = created.Add(3 * time.Minute)
+	assert.Equal(t, 2*time.Minute, job.ProcessingDuration())
+}
@@ -0,0 +1,205 @@
+package ipp
+
+// PrinterDescription contains the common printer description and status attributes of rfc 8011 and ipp everywhere
+// in typed form. missing attributes keep their zero value
+type PrinterDescription struct {
+	Name         string
+	Info         string
+	Location     string
+	MakeAndModel string
+	MoreInfo     string
+	UUID         string
+	URISupported []string
+	Icons        []string
+
+	State           int8
+	StateReasons    []string
+	StateMessage    string
+	IsAcceptingJobs bool
+	QueuedJobCount  int
+	UpTime          int
+
+	OperationsSupported      []int16
+	IPPVersionsSupported     []string
+	DocumentFormatDefault    string
+	DocumentFormatsSupported []string
+	// DocumentFormatDetailsSupported are the members of document-format-details the printer accepts
+	DocumentFormatDetailsSupported []string
+
+	MediaDefault   string
+	MediaSupported []string
+	MediaReady     []string
+	// MediaColDefault and MediaColReady are the media-col-default and media-col-ready collections
+	MediaColDefault MediaCol
+	MediaColReady   []MediaCol
+	// FinishingsSupported are the finishings-supported enum values
+	FinishingsSupported   []int
+	FinishingsColDatabase []FinishingsCol
+	SidesDefault          string
+	SidesSupported        []string
+	ColorSupported        bool
+	// OutputBinDefault and OutputBinSupported are output-bin keywords, e.g. face-down, mailbox-1 or stacker-2
+	OutputBinDefault      string
+	OutputBinSupported    []string
+	PageDeliveryDefault   string
+	PageDeliverySupported []string
+	// CopiesSupported is the maximum number of copies, the upper bound of copies-supported
+	CopiesSupported int
+	// JobCopiesSupported is the maximum number of job-copies, the upper bound of job-copies-supported
+	JobCopiesSupported int
+	// ProofPrintDefault is the proof-print-default collection, nil if the printer doesn't print proofs by default
+	ProofPrintDefault *ProofPrint
+	// ProofPrintSupported are the members of proof-print the printer accepts, e.g. media-col and proof-print-copies
+	ProofPrintSupported []string
+
+	PrintScalingDefault           string
+	PrintScalingSupported         []string
+	PrintRenderingIntentDefault   string
+	PrintRenderingIntentSupported []string
+	PrintContentOptimizeDefault   string
+	PrintContentOptimizeSupported []string
+
+	ResolutionDefault    Resolution
+	ResolutionsSupported []Resolution
+
+	// PwgRasterTypesSupported are the pwg-raster-document-type-supported keywords, e.g. srgb_8
+	PwgRasterTypesSupported       []string
+	PwgRasterResolutionsSupported []Resolution
+	PwgRasterSheetBack            string
+	// URFSupported are the apple raster capabilities of urf-supported, e.g. W8, SRGB24 or RS300-600
+	URFSupported []string
+
+	// Unsupported contains the unsupported attributes group of the response, e.g. requested attributes the printer
+	// does not know. it is not populated by Unmarshal
+	Unsupported Attributes
+}
+
+// Unmarshal populates the description from the printer attributes of a response, e.g. the result of
+// IPPClient.GetPrinterAttributes. an error wrapping AttributeTypeError is returned if an attribute has an unexpected
+// value type, the other fields are populated nevertheless
+func (d *PrinterDescription) Unmarshal(attributes Attributes) error {
+	u := attributeUnmarshaler{attributes: attributes}
+
+	d.Name = u.string(AttributePrinterName)
+	d.Info = u.string(AttributePrinterInfo)
+	d.Location = u.string(AttributePrinterLocation)
+	d.MakeAndModel = u.string(AttributePrinterMakeAndModel)
+	d.MoreInfo = u.string(AttributePrinterMoreInfo)
+	d.UUID = u.string(AttributePrinterUUID)
+	d.URISupported = u.strings(AttributePrinterUriSupported)
+	d.Icons = u.strings(AttributePrinterIcons)
+
+	d.State = int8(u.int(AttributePrinterState))
+	d.StateReasons = u.strings(AttributePrinterStateReasons)
+	d.StateMessage = u.string(AttributePrinterStateMessage)
+	d.IsAcceptingJobs = u.bool(AttributePrinterIsAcceptingJobs)
+	d.QueuedJobCount = u.int(AttributeQueuedJobCount)
+	d.UpTime = u.int(AttributePrinterUpTime)
+
+	d.OperationsSupported = nil
+	for _, op := range u.ints(AttributeOperationsSupported) {
+		d.OperationsSupported = append(d.OperationsSupported, int16(op))
+	}
+	d.IPPVersionsSupported = u.strings(AttributeIppVersionsSupported)
+	d.DocumentFormatDefault = u.string(AttributeDocumentFormatDefault)
+	d.DocumentFormatsSupported = u.strings(AttributeDocumentFormatSupported)
+	d.DocumentFormatDetailsSupported = u.strings(AttributeDocumentFormatDetailsSupported)
+
+	d.MediaDefault = u.string(AttributeMediaDefault)
+	d.MediaSupported = u.strings(AttributeMediaSupported)
+	d.MediaReady = u.strings(AttributeMediaReady)
+	d.MediaColDefault = MediaCol{}
+	if cols := u.mediaCols(AttributeMediaColDefault); len(cols) > 0 {
+		d.MediaColDefault = cols[0]
+	}
+	d.MediaColReady = u.mediaCols(AttributeMediaColReady)
+	d.FinishingsSupported = u.ints(AttributeFinishingsSupported)
+	d.FinishingsColDatabase = u.finishingsCols(AttributeFinishingsColDatabase)
+	d.SidesDefault = u.string(AttributeSidesDefault)
+	d.SidesSupported = u.strings(AttributeSidesSupported)
+	d.ColorSupported = u.bool(AttributeColorSupported)
+	d.OutputBinDefault = u.string(AttributeOutputBinDefault)
+	d.OutputBinSupported = u.strings(AttributeOutputBinSupported)
+	d.PageDeliveryDefault = u.string(AttributePageDeliveryDefault)
+	d.PageDeliverySupported = u.strings(AttributePageDeliverySupported)
+	d.CopiesSupported = u.rangeUpper(AttributeCopiesSupported)
+	d.JobCopiesSupported = u.rangeUpper(AttributeJobCopiesSupported)
+	d.ProofPrintDefault = u.proofPrint(AttributeProofPrintDefault)
+	d.ProofPrintSupported = u.strings(AttributeProofPrintSupported)
+
+	d.PrintScalingDefault = u.string(AttributePrintScalingDefault)
+	d.PrintScalingSupported = u.strings(AttributePrintScalingSupported)
+	d.PrintRenderingIntentDefault = u.string(AttributePrintRenderingIntentDefault)
+	d.PrintRenderingIntentSupported = u.strings(AttributePrintRenderingIntentSupported)
+	d.PrintContentOptimizeDefault = u.string(AttributePrintContentOptimizeDefault)
+	d.PrintContentOptimizeSupported = u.strings(AttributePrintContentOptimizeSupported)
+
+	d.ResolutionDefault = Resolution{}
+	if resolutions := u.resolutions(AttributePrinterResolutionDefault); len(resolutions) > 0 {
+		d.ResolutionDefault = resolutions[0]
+	}
+	d.ResolutionsSupported = u.resolutions(AttributePrinterResolutionSupported)
+
+	d.PwgRasterTypesSupported = u.strings(AttributePwgRasterDocumentTypeSupported)
+	d.PwgRasterResolutionsSupported = u.resolutions(AttributePwgRasterDocumentResolutionSupported)
+	d.PwgRasterSheetBack = u.string(AttributePwgRasterDocumentSheetBack)
+	d.URFSupported = u.strings(AttributeUrfSupported)
+
+	return u.err
+}
+
+// SupportsOperation reports whether the operation is listed in operations-supported
+func (d *PrinterDescription) SupportsOperation(operation int16) bool {
+	for _, op := range d.OperationsSupported {
+		if op == operation {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SupportsDocumentFormat reports whether the mime type is listed in document-format-supported
+func (d *PrinterDescription) SupportsDocumentFormat(format string) bool {
+	return containsString(d.DocumentFormatsSupported, format)
+}
+
+// SupportsPrintScaling reports whether the print-scaling keyword, e.g. PrintScalingFill, is listed in
+// print-scaling-supported
+func (d *PrinterDescription) SupportsPrintScaling(scaling string) bool {
+	return containsString(d.PrintScalingSupported, scaling)
+}
+
+// SupportsPrintRenderingIntent reports whether the print-rendering-intent keyword, e.g. PrintRenderingIntentPerceptual,
+// is listed in print-rendering-intent-supported
+func (d *PrinterDescription) SupportsPrintRenderingIntent(intent string) bool {
+	return containsString(d.PrintRenderingIntentSupported, intent)
+}
+
+// SupportsPrintContentOptimize reports whether the print-content-optimize keyword, e.g. PrintContentOptimizePhoto, is
+// listed in print-content-optimize-supported
+func (d *PrinterDescription) SupportsPrintContentOptimize(optimize string) bool {
+	return containsString(d.PrintContentOptimizeSupported, optimize)
+}
+
+// SupportsOutputBin reports whether the output-bin keyword, e.g. OutputBinFaceDown or OutputBinMailbox(1), is listed
+// in output-bin-supported
+func (d *PrinterDescription) SupportsOutputBin(bin string) bool {
+	return containsString(d.OutputBinSupported, bin)
+}
+
+// SupportsPageDelivery reports whether the page-delivery keyword, e.g. PageDeliverySameOrderFaceDown, is listed in
+// page-delivery-supported
+func (d *PrinterDescription) SupportsPageDelivery(delivery string) bool {
+	return containsString(d.PageDeliverySupported, delivery)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,93 @@
+package ipp
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrinterDescription_Unmarshal(t *testing.T) {
+	resp := NewResponse(StatusOk, 1)
+	attributes := make(Attributes)
+	attributes.Set(AttributePrinterName, TagName, "office")
+	attributes.Set(AttributePrinterMakeAndModel, TagText, "Office Laser")
+	attributes.Set(AttributePrinterUriSupported, TagUri, "ipp://localhost/ipp/print", "ipps://localhost/ipp/print")
+	attributes.Set(AttributePrinterState, TagEnum, int(PrinterStateProcessing))
+	attributes.Set(AttributePrinterStateReasons, TagKeyword, "media-low-warning", "toner-low-warning")
+	attributes.Set(AttributePrinterStateMessage, TagNoValue, "")
+	attributes.Set(AttributePrinterIsAcceptingJobs, TagBoolean, true)
+	attributes.Set(AttributeQueuedJobCount, TagInteger, 3)
+	attributes.Set(AttributeOperationsSupported, TagEnum, int(OperationPrintJob), int(OperationGetPrinterAttributes))
+	attributes.Set(AttributeDocumentFormatSupported, TagMimeType, "application/pdf", "image/pwg-raster")
+	attributes.Set(AttributeMediaDefault, TagKeyword, "iso_a4_210x297mm")
+	attributes.Set(AttributeMediaSupported, TagKeyword, "iso_a4_210x297mm", "na_letter_8.5x11in")
+	attributes.Set(AttributeSidesSupported, TagKeyword, "one-sided", "two-sided-long-edge")
+	attributes.Set(AttributeColorSupported, TagBoolean, true)
+	attributes.Set(AttributeCopiesSupported, TagRange, []int32{1, 99})
+	attributes.Set(AttributePrinterResolutionDefault, TagResolution, Resolution{Height: 600, Width: 600, Depth: 3})
+	attributes.Set(AttributePrinterResolutionSupported, TagResolution,
+		Resolution{Height: 300, Width: 300, Depth: 3}, Resolution{Height: 600, Width: 600, Depth: 3})
+	attributes.Set(AttributePwgRasterDocumentTypeSupported, TagKeyword, "sgray_8", "srgb_8")
+	attributes.Set(AttributePwgRasterDocumentSheetBack, TagKeyword, "rotated")
+	attributes.Set(AttributeUrfSupported, TagKeyword, "W8", "SRGB24", "RS300-600")
+	attributes.Set(AttributePrintScalingDefault, TagKeyword, PrintScalingAuto)
+	attributes.Set(AttributePrintScalingSupported, TagKeyword, PrintScalingAuto, PrintScalingFill, PrintScalingFit)
+	attributes.Set(AttributePrintRenderingIntentSupported, TagKeyword, PrintRenderingIntentAuto,
+		PrintRenderingIntentPerceptual)
+	attributes.Set(AttributePrintContentOptimizeDefault, TagKeyword, PrintContentOptimizeAuto)
+	attributes.Set(AttributePrintContentOptimizeSupported, TagKeyword, PrintContentOptimizeAuto,
+		PrintContentOptimizePhoto)
+	resp.PrinterAttributes = append(resp.PrinterAttributes, attributes)
+
+	// unmarshal the decoded attributes to cover the value types of the decoder
+	data, err := resp.Encode()
+	assert.Nil(t, err)
+	decoded, err := NewResponseDecoder(bytes.NewReader(data)).Decode(nil)
+	assert.Nil(t, err)
+
+	var d PrinterDescription
+	assert.Nil(t, d.Unmarshal(decoded.PrinterAttributes[0]))
+
+	assert.Equal(t, "office", d.Name)
+	assert.Equal(t, "Office Laser", d.MakeAndModel)
+	assert.Len(t, d.URISupported, 2)
+	assert.Equal(t, PrinterStateProcessing, d.State)
+	assert.Equal(t, []string{"media-low-warning", "toner-low-warning"}, d.StateReasons)
+	assert.Equal(t, "", d.StateMessage)
+	assert.True(t, d.IsAcceptingJobs)
+	assert.Equal(t, 3, d.QueuedJobCount)
+	assert.True(t, d.SupportsOperation(OperationPrintJob))
+	assert.False(t, d.SupportsOperation(OperationCancelJob))
+	assert.True(t, d.SupportsDocumentFormat("image/pwg-raster"))
+	assert.Equal(t, "iso_a4_210x297mm", d.MediaDefault)
+	assert.Len(t, d.MediaSupported, 2)
+	assert.Equal(t, []string{"one-sided", "two-sided-long-edge"}, d.SidesSupported)
+	assert.True(t, d.ColorSupported)
+	assert.Equal(t, 99, d.CopiesSupported)
+	assert.Equal(t, int32(600), d.ResolutionDefault.Width)
+	assert.Len(t, d.ResolutionsSupported, 2)
+	assert.Equal(t, []string{"sgray_8", "srgb_8"}, d.PwgRasterTypesSupported)
+	assert.Equal(t, "rotated", d.PwgRasterSheetBack)
+	assert.Equal(t, []string{"W8", "SRGB24", "RS300-600"}, d.URFSupported)
+	assert.Equal(t, PrintScalingAuto, d.PrintScalingDefault)
+	assert.True(t, d.SupportsPrintScaling(PrintScalingFill))
+	assert.False(t, d.SupportsPrintScaling(PrintScalingAutoFit))
+	assert.True(t, d.SupportsPrintRenderingIntent(PrintRenderingIntentPerceptual))
+	assert.False(t, d.SupportsPrintRenderingIntent(PrintRenderingIntentSaturation))
+	assert.Equal(t, PrintContentOptimizeAuto, d.PrintContentOptimizeDefault)
+	assert.True(t, d.SupportsPrintContentOptimize(PrintContentOptimizePhoto))
+}
+
+func TestPrinterDescription_UnmarshalTypeError(t *testing.T) {
+	attributes := make(Attributes)
+	attributes.Set(AttributePrinterName, TagName, "office")
+	attributes.Set(AttributePrinterState, TagKeyword, "idle")
+
+	var d PrinterDescription
+	err := d.Unmarshal(attributes)
+	assert.True(t, errors.Is(err, AttributeTypeError))
+	assert.Contains(t, err.Error(), AttributePrinterState)
+	assert.Equal(t, "office", d.Name)
+}
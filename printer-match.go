@@ -0,0 +1,134 @@
+package ipp
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// JobRequirements describes a job for MatchPrinters, zero fields are not required
+type JobRequirements struct {
+	// DocumentFormat must be listed in document-format-supported, printers with application/octet-stream detect the
+	// format themselves and match all formats
+	DocumentFormat string
+	// Media is the media size name, e.g. iso_a4_210x297mm, it must be listed in media-supported
+	Media  string
+	Color  bool
+	Duplex bool
+	// Finishings are the finishings enum values, e.g. FinishingsStaple, all of them must be supported
+	Finishings []int8
+	Copies     int
+}
+
+// PrinterMatch is a printer ranked by MatchPrinters
+type PrinterMatch struct {
+	Printer     string
+	Description *PrinterDescription
+	// Missing are the supported attributes of the requirements the printer doesn't meet, e.g. sides-supported, and
+	// printer-is-accepting-jobs if the printer rejects jobs
+	Missing []string
+	// Score ranks the printers meeting the same number of requirements, see MatchPrinters
+	Score int
+}
+
+// Matches reports whether the printer meets all requirements
+func (m PrinterMatch) Matches() bool {
+	return len(m.Missing) == 0
+}
+
+// MatchPrinters ranks the printers by their capabilities for the job, e.g. to route jobs to the best printer of a
+// fleet. the printers are keyed by name or uri, the descriptions should be requested with IPPClient.PrinterCapabilities.
+// printers meeting all requirements come first, ties are broken by the score: the media is loaded, the requested
+// media and sides are the defaults, the printer is idle rather than processing or stopped, few jobs are queued and
+// no error is reported. matching printers can be selected with PrinterMatch.Matches
+func MatchPrinters(requirements JobRequirements, printers map[string]*PrinterDescription) []PrinterMatch {
+	matches := make([]PrinterMatch, 0, len(printers))
+	for name, d := range printers {
+		if d == nil {
+			continue
+		}
+		matches = append(matches, PrinterMatch{
+			Printer:     name,
+			Description: d,
+			Missing:     requirements.missing(d),
+			Score:       requirements.score(d),
+		})
+	}
+
+	sort.Slice(matches, func(i, k int) bool {
+		a, b := matches[i], matches[k]
+		if len(a.Missing) != len(b.Missing) {
+			return len(a.Missing) < len(b.Missing)
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Printer < b.Printer
+	})
+
+	return matches
+}
+
+// missing returns the supported attributes of the requirements the printer doesn't meet
+func (r JobRequirements) missing(d *PrinterDescription) []string {
+	var missing []string
+
+	if !d.IsAcceptingJobs {
+		missing = append(missing, AttributePrinterIsAcceptingJobs)
+	}
+	if r.DocumentFormat != "" && !d.SupportsDocumentFormat(r.DocumentFormat) &&
+		!d.SupportsDocumentFormat(MimeTypeOctetStream) {
+		missing = append(missing, AttributeDocumentFormatSupported)
+	}
+	if r.Media != "" && !containsString(d.MediaSupported, r.Media) {
+		missing = append(missing, AttributeMediaSupported)
+	}
+	if r.Color && !d.ColorSupported {
+		missing = append(missing, AttributeColorSupported)
+	}
+	if r.Duplex && !slices.ContainsFunc(d.SidesSupported, func(sides string) bool {
+		return strings.HasPrefix(sides, "two-sided")
+	}) {
+		missing = append(missing, AttributeSidesSupported)
+	}
+	for _, finishings := range r.Finishings {
+		if !d.SupportsFinishings(finishings) {
+			missing = append(missing, AttributeFinishingsSupported)
+			break
+		}
+	}
+	if r.Copies > 1 && d.CopiesSupported < r.Copies {
+		missing = append(missing, AttributeCopiesSupported)
+	}
+
+	return missing
+}
+
+// score rates how well the printer suits the job apart from its capabilities
+func (r JobRequirements) score(d *PrinterDescription) int {
+	score := 0
+
+	if r.Media != "" && containsString(d.MediaReady, r.Media) {
+		score += 20
+	}
+	if r.Media != "" && d.MediaDefault == r.Media {
+		score += 5
+	}
+	if r.Duplex && strings.HasPrefix(d.SidesDefault, "two-sided") {
+		score += 5
+	}
+
+	switch d.State {
+	case PrinterStateIdle:
+		score += 20
+	case PrinterStateProcessing:
+		score += 10
+	}
+	score -= 2 * min(d.QueuedJobCount, 10)
+
+	if d.Reasons().HasError() {
+		score -= 30
+	}
+
+	return score
+}
@@ -0,0 +1,61 @@
+package ipp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPrinters(t *testing.T) {
+	office := &PrinterDescription{
+		State:                    PrinterStateIdle,
+		StateReasons:             []string{"none"},
+		IsAcceptingJobs:          true,
+		DocumentFormatsSupported: []string{MimeTypePDF, MimeTypePwgRaster},
+		MediaSupported:           []string{"iso_a4_210x297mm", "iso_a3_297x420mm"},
+		MediaReady:               []string{"iso_a4_210x297mm"},
+		SidesSupported:           []string{SidesOneSided, SidesTwoSidedLongEdge},
+		ColorSupported:           true,
+		FinishingsSupported:      []int{int(FinishingsNone), int(FinishingsStaple)},
+		CopiesSupported:          99,
+	}
+	busy := *office
+	busy.State = PrinterStateProcessing
+	busy.QueuedJobCount = 4
+	jammed := *office
+	jammed.StateReasons = []string{"media-jam-error"}
+	mono := &PrinterDescription{
+		State:                    PrinterStateIdle,
+		IsAcceptingJobs:          true,
+		DocumentFormatsSupported: []string{MimeTypeOctetStream},
+		MediaSupported:           []string{"iso_a4_210x297mm"},
+		SidesSupported:           []string{SidesOneSided},
+		CopiesSupported:          1,
+	}
+	stopped := *office
+	stopped.IsAcceptingJobs = false
+
+	requirements := JobRequirements{DocumentFormat: MimeTypePDF, Media: "iso_a4_210x297mm", Color: true,
+		Duplex: true, Finishings: []int8{FinishingsStaple}, Copies: 10}
+	matches := MatchPrinters(requirements, map[string]*PrinterDescription{
+		"office": office, "busy": &busy, "jammed": &jammed, "mono": mono, "stopped": &stopped, "offline": nil,
+	})
+
+	var names []string
+	for _, m := range matches {
+		names = append(names, m.Printer)
+	}
+	assert.Equal(t, []string{"office", "busy", "jammed", "stopped", "mono"}, names)
+	assert.True(t, matches[0].Matches())
+	assert.True(t, matches[2].Matches())
+	assert.Equal(t, []string{AttributePrinterIsAcceptingJobs}, matches[3].Missing)
+	assert.Equal(t, []string{AttributeColorSupported, AttributeSidesSupported, AttributeFinishingsSupported,
+		AttributeCopiesSupported}, matches[4].Missing)
+
+	// printers which detect the format match any format
+	matches = MatchPrinters(JobRequirements{DocumentFormat: "image/urf"}, map[string]*PrinterDescription{
+		"office": office, "mono": mono,
+	})
+	assert.Equal(t, "mono", matches[0].Printer)
+	assert.Equal(t, []string{AttributeDocumentFormatSupported}, matches[1].Missing)
+}
@@ -0,0 +1,303 @@
+package ipp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// NoPrinterAvailableError is returned by PrinterPool if no member of the pool is healthy or all members failed
+var NoPrinterAvailableError = errors.New("no printer of the pool is available")
+
+// DefaultPoolRetryAfter is the time an unhealthy pool member is skipped until it is tried again
+const DefaultPoolRetryAfter = 30 * time.Second
+
+// PoolMember is a printer of a PrinterPool, the printer is addressed like in the methods of the client
+type PoolMember struct {
+	Client  *IPPClient
+	Printer string
+	// Name identifies the member in the statistics, defaults to Printer
+	Name string
+}
+
+// PoolStats are the statistics of a pool member
+type PoolStats struct {
+	Name    string
+	Healthy bool
+	// Active is the number of submissions in progress
+	Active int
+	// Jobs is the number of submitted jobs
+	Jobs int
+	// Failures is the number of failed submissions and health checks
+	Failures int
+	// LastError is the error of the last failed submission or health check
+	LastError error
+	// LastCheck is the time of the last health check
+	LastCheck time.Time
+}
+
+// PoolJob is a job submitted by a PrinterPool together with the member which accepted it
+type PoolJob struct {
+	*Job
+	Member PoolMember
+}
+
+// poolMember holds the state of a member, it is guarded by the mutex of the pool
+type poolMember struct {
+	PoolMember
+	stats PoolStats
+	// unhealthyUntil is the time the member is skipped until
+	unhealthyUntil time.Time
+}
+
+// PrinterPool distributes jobs across equivalent printers. each job is submitted to the healthy member with the
+// fewest submissions in progress, members with the same load take turns. if the submission fails with a connection
+// or server error, the member is marked unhealthy and the job is submitted to the next member. client errors like an
+// unsupported document format are returned without retry because the other members would reject the job as well.
+// unhealthy members are skipped for RetryAfter or until a health check succeeds, see Check and Watch
+type PrinterPool struct {
+	// RetryAfter is the time an unhealthy member is skipped, defaults to DefaultPoolRetryAfter
+	RetryAfter time.Duration
+
+	mu      sync.Mutex
+	members []*poolMember
+	next    int
+	now     func() time.Time
+}
+
+// NewPrinterPool creates a pool of the members, all members are considered healthy until a submission or a health
+// check fails
+func NewPrinterPool(members ...PoolMember) *PrinterPool {
+	p := &PrinterPool{RetryAfter: DefaultPoolRetryAfter, now: time.Now}
+
+	for _, m := range members {
+		if m.Name == "" {
+			m.Name = m.Printer
+		}
+		p.members = append(p.members, &poolMember{PoolMember: m, stats: PoolStats{Name: m.Name, Healthy: true}})
+	}
+
+	return p
+}
+
+// PrintJob submits the document with Print-Job to a member of the pool, see SubmitDocuments
+func (p *PrinterPool) PrintJob(doc Document, jobAttributes map[string]interface{}) (*PoolJob, error) {
+	return p.SubmitDocuments([]Document{doc}, jobAttributes)
+}
+
+// SubmitDocuments submits the documents as one job to a member of the pool. the documents are read again for each
+// retry, documents which are not an io.Seeker are buffered in memory if the pool has more than one member. the
+// error of the last tried member is wrapped in NoPrinterAvailableError if all members failed
+func (p *PrinterPool) SubmitDocuments(docs []Document, jobAttributes map[string]interface{}) (*PoolJob, error) {
+	docs, err := p.rewindable(docs)
+	if err != nil {
+		return nil, err
+	}
+
+	tried := make(map[*poolMember]bool)
+	var lastErr error
+
+	for {
+		if len(tried) > 0 {
+			if err := rewind(docs); err != nil {
+				return nil, err
+			}
+		}
+
+		m := p.acquire(tried)
+		if m == nil {
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w: %w", NoPrinterAvailableError, lastErr)
+			}
+			return nil, NoPrinterAvailableError
+		}
+		tried[m] = true
+
+		var job *Job
+		if len(docs) == 1 {
+			job, err = m.Client.SubmitJob(docs[0], m.Printer, jobAttributes)
+		} else {
+			job, err = m.Client.SubmitDocuments(docs, m.Printer, jobAttributes)
+		}
+
+		switch {
+		case err == nil:
+			p.release(m, true, nil)
+			return &PoolJob{Job: job, Member: m.PoolMember}, nil
+		case IsClientError(err):
+			p.release(m, false, nil)
+			return nil, err
+		}
+
+		p.release(m, false, err)
+		lastErr = err
+	}
+}
+
+// rewindable makes the documents readable more than once if the pool has more than one member
+func (p *PrinterPool) rewindable(docs []Document) ([]Document, error) {
+	if len(p.members) < 2 {
+		return docs, nil
+	}
+
+	rewindable := make([]Document, len(docs))
+	for i, doc := range docs {
+		rewindable[i] = doc
+		if _, ok := doc.Document.(io.Seeker); ok || doc.Document == nil {
+			continue
+		}
+
+		data, err := io.ReadAll(doc.Document)
+		if err != nil {
+			return nil, err
+		}
+		rewindable[i].Document = bytes.NewReader(data)
+	}
+
+	return rewindable, nil
+}
+
+// rewind seeks the documents to their start
+func rewind(docs []Document) error {
+	for _, doc := range docs {
+		if seeker, ok := doc.Document.(io.Seeker); ok {
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// acquire selects the healthy member with the fewest active submissions which was not tried yet and counts the
+// submission as active
+func (p *PrinterPool) acquire(tried map[*poolMember]bool) *poolMember {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	var selected *poolMember
+
+	for i := range p.members {
+		m := p.members[(p.next+i)%len(p.members)]
+		if tried[m] || !m.stats.Healthy && now.Before(m.unhealthyUntil) {
+			continue
+		}
+		if selected == nil || m.stats.Active < selected.stats.Active {
+			selected = m
+		}
+	}
+
+	if selected != nil {
+		selected.stats.Active++
+		p.next = (p.next + 1) % len(p.members)
+	}
+
+	return selected
+}
+
+// release ends an active submission and updates the statistics of the member, the member is marked unhealthy if err
+// is set
+func (p *PrinterPool) release(m *poolMember, submitted bool, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	m.stats.Active--
+	if submitted {
+		m.stats.Jobs++
+		m.stats.Healthy = true
+	}
+	if err != nil {
+		p.markUnhealthy(m, err)
+	}
+}
+
+// markUnhealthy records the error of a member, the pool lock must be held
+func (p *PrinterPool) markUnhealthy(m *poolMember, err error) {
+	m.stats.Failures++
+	m.stats.LastError = err
+	m.stats.Healthy = false
+	m.unhealthyUntil = p.now().Add(p.RetryAfter)
+}
+
+// Check checks the health of all members with a Get-Printer-Attributes request. a member is healthy if it answers,
+// is not stopped and accepts jobs. the members are checked concurrently
+func (p *PrinterPool) Check() {
+	p.mu.Lock()
+	members := append([]*poolMember(nil), p.members...)
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, m := range members {
+		wg.Add(1)
+		go func(m *poolMember) {
+			defer wg.Done()
+
+			err := checkPoolMember(m.PoolMember)
+
+			p.mu.Lock()
+			defer p.mu.Unlock()
+
+			m.stats.LastCheck = p.now()
+			if err != nil {
+				p.markUnhealthy(m, err)
+			} else {
+				m.stats.Healthy = true
+			}
+		}(m)
+	}
+
+	wg.Wait()
+}
+
+// checkPoolMember requests the state of a member, an error is returned if the member can't print
+func checkPoolMember(m PoolMember) error {
+	attributes, err := m.Client.GetPrinterAttributes(m.Printer,
+		[]string{AttributePrinterState, AttributePrinterIsAcceptingJobs})
+	if err != nil {
+		return err
+	}
+
+	if state, err := GetOne[int8](attributes, AttributePrinterState); err == nil && state == PrinterStateStopped {
+		return errors.New("printer is stopped")
+	}
+	if accepting, err := GetOne[bool](attributes, AttributePrinterIsAcceptingJobs); err == nil && !accepting {
+		return errors.New("printer is not accepting jobs")
+	}
+
+	return nil
+}
+
+// Watch checks the health of the members in the given interval until the context is done
+func (p *PrinterPool) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		p.Check()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stats returns the statistics of the members in the order they were added
+func (p *PrinterPool) Stats() []PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make([]PoolStats, len(p.members))
+	for i, m := range p.members {
+		stats[i] = m.stats
+	}
+
+	return stats
+}
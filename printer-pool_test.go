@@ -0,0 +1,125 @@
+package ipp
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// poolAdapter creates a test adapter which answers job submissions with the status and reads the documents
+func poolAdapter(status *int16, documents *[]string) *testAdapter {
+	adapter := &testAdapter{}
+	adapter.respond = func(req *Request) *Response {
+		if req.File != nil {
+			data, _ := io.ReadAll(req.File)
+			*documents = append(*documents, string(data))
+		}
+
+		resp := NewResponse(*status, req.RequestId)
+		switch {
+		case *status != StatusOk:
+		case req.Operation == OperationGetPrinterAttributes:
+			printer := make(Attributes)
+			printer.Set(AttributePrinterState, TagEnum, int(PrinterStateIdle))
+			printer.Set(AttributePrinterIsAcceptingJobs, TagBoolean, *documents == nil)
+			resp.PrinterAttributes = append(resp.PrinterAttributes, printer)
+		default:
+			job := make(Attributes)
+			job.Set(AttributeJobID, TagInteger, len(*documents))
+			resp.JobAttributes = append(resp.JobAttributes, job)
+		}
+		return resp
+	}
+
+	return adapter
+}
+
+func TestPrinterPool(t *testing.T) {
+	statusA, statusB := StatusOk, StatusOk
+	var documentsA, documentsB []string
+
+	pool := NewPrinterPool(
+		PoolMember{Client: NewIPPClientWithAdapter("user", poolAdapter(&statusA, &documentsA)), Printer: "a"},
+		PoolMember{Client: NewIPPClientWithAdapter("user", poolAdapter(&statusB, &documentsB)), Printer: "b",
+			Name: "backup"},
+	)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pool.now = func() time.Time { return now }
+
+	// the members take turns
+	for i := 0; i < 4; i++ {
+		job, err := pool.PrintJob(Document{Document: strings.NewReader("doc"), Size: 3, Name: "doc"}, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a", "b"}[i%2], job.Member.Printer)
+	}
+
+	// a failing member is skipped and the document is sent again to the next member
+	statusA = StatusErrorServiceUnavailable
+	job, err := pool.PrintJob(Document{Document: io.MultiReader(strings.NewReader("retried")), Size: -1}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "b", job.Member.Printer)
+	assert.Equal(t, "retried", documentsB[len(documentsB)-1])
+
+	stats := pool.Stats()
+	assert.Equal(t, "backup", stats[1].Name)
+	assert.False(t, stats[0].Healthy)
+	assert.Equal(t, 1, stats[0].Failures)
+	assert.True(t, errors.Is(stats[0].LastError, ServerError))
+	assert.Equal(t, []int{2, 3}, []int{stats[0].Jobs, stats[1].Jobs})
+
+	_, err = pool.PrintJob(Document{Document: strings.NewReader("doc"), Size: 3}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, pool.Stats()[0].Failures)
+
+	// the unhealthy member is tried again after RetryAfter
+	statusA = StatusOk
+	now = now.Add(DefaultPoolRetryAfter)
+	for i := 0; i < 2; i++ {
+		_, err = pool.PrintJob(Document{Document: strings.NewReader("doc"), Size: 3}, nil)
+		assert.Nil(t, err)
+	}
+	assert.True(t, pool.Stats()[0].Healthy)
+	assert.Equal(t, 3, pool.Stats()[0].Jobs)
+
+	// client errors are not retried and don't affect the health
+	statusA, statusB = StatusErrorDocumentFormatNotSupported, StatusErrorDocumentFormatNotSupported
+	_, err = pool.PrintJob(Document{Document: strings.NewReader("doc"), Size: 3}, nil)
+	assert.True(t, IsClientError(err))
+	assert.False(t, errors.Is(err, NoPrinterAvailableError))
+	assert.Equal(t, []int{1, 0}, []int{pool.Stats()[0].Failures, pool.Stats()[1].Failures})
+
+	statusA, statusB = StatusErrorServiceUnavailable, StatusErrorNotAcceptingJobs
+	_, err = pool.PrintJob(Document{Document: strings.NewReader("doc"), Size: 3}, nil)
+	assert.True(t, errors.Is(err, NoPrinterAvailableError))
+	assert.True(t, errors.Is(err, ServerError))
+
+	_, err = pool.PrintJob(Document{Document: strings.NewReader("doc"), Size: 3}, nil)
+	assert.Equal(t, NoPrinterAvailableError, err)
+}
+
+func TestPrinterPool_Check(t *testing.T) {
+	statusA, statusB := StatusOk, StatusOk
+	var documentsA []string
+	documentsB := []string{}
+
+	pool := NewPrinterPool(
+		PoolMember{Client: NewIPPClientWithAdapter("user", poolAdapter(&statusA, &documentsA)), Printer: "a"},
+		PoolMember{Client: NewIPPClientWithAdapter("user", poolAdapter(&statusB, &documentsB)), Printer: "b"},
+	)
+	pool.Check()
+
+	stats := pool.Stats()
+	assert.True(t, stats[0].Healthy)
+	assert.False(t, stats[0].LastCheck.IsZero())
+	// the adapter of b reports printer-is-accepting-jobs false
+	assert.False(t, stats[1].Healthy)
+	assert.EqualError(t, stats[1].LastError, "printer is not accepting jobs")
+
+	job, err := pool.PrintJob(Document{Document: strings.NewReader("doc"), Size: 3}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "a", job.Member.Printer)
+}
@@ -0,0 +1,85 @@
+package ipp
+
+// ProofPrint is the proof-print collection of pwg 5100.11. a job with proof-print prints the proof copies first and is
+// held afterwards, the full run with the copies or job-copies of the job is printed after the job is released with
+// IPPClient.ReleaseJob and discarded with IPPClient.CancelJob
+type ProofPrint struct {
+	// Copies is the proof-print-copies, the number of proof copies. zero holds the job without printing a proof
+	Copies int
+	// Media and MediaCol select the media of the proof copies, e.g. plain paper for a run on glossy media. MediaCol
+	// takes precedence, the media of the job is used if both are empty
+	Media    string
+	MediaCol *MediaCol
+}
+
+// NewProofPrint creates a proof-print requesting a single proof copy on the media of the job
+func NewProofPrint() ProofPrint {
+	return ProofPrint{Copies: 1}
+}
+
+// Collection returns the wire form of the proof-print, it can be used as value of a proof-print job attribute
+func (p ProofPrint) Collection() Attributes {
+	c := make(Attributes)
+
+	c.Set(AttributeProofPrintCopies, TagInteger, p.Copies)
+	if p.MediaCol != nil {
+		c.Set(AttributeMediaCol, TagBeginCollection, p.MediaCol.Collection())
+	} else if p.Media != "" {
+		c.Set(AttributeMedia, TagKeyword, p.Media)
+	}
+
+	return c
+}
+
+// Unmarshal populates the proof-print from its decoded collection
+func (p *ProofPrint) Unmarshal(c Attributes) error {
+	u := attributeUnmarshaler{attributes: c}
+
+	*p = ProofPrint{
+		Copies: u.int(AttributeProofPrintCopies),
+		Media:  u.string(AttributeMedia),
+	}
+	if cols := u.mediaCols(AttributeMediaCol); len(cols) > 0 {
+		p.MediaCol = &cols[0]
+	}
+
+	return u.err
+}
+
+// proofPrint returns the first proof-print value of the attribute, nil if there is none
+func (u *attributeUnmarshaler) proofPrint(name string) *ProofPrint {
+	c, ok := u.collection(name)
+	if !ok {
+		return nil
+	}
+
+	p := new(ProofPrint)
+	if err := p.Unmarshal(c); err != nil && u.err == nil {
+		u.err = err
+	}
+
+	return p
+}
+
+// WithProofPrint adds the proof-print job attribute to a request, copies is the job-copies of the full run. copies
+// below one are not sent and the printer uses its job-copies-default
+func WithProofPrint(proof ProofPrint, copies int) RequestOption {
+	return func(r *Request) {
+		r.JobAttributes[AttributeProofPrint] = proof.Collection()
+		if copies > 0 {
+			r.JobAttributes[AttributeJobCopies] = copies
+		}
+	}
+}
+
+// SupportsProofPrint reports whether the printer accepts the proof-print job attribute, proof-print-supported lists
+// the members the printer accepts
+func (d *PrinterDescription) SupportsProofPrint() bool {
+	for _, member := range d.ProofPrintSupported {
+		if member == AttributeProofPrintCopies {
+			return true
+		}
+	}
+
+	return false
+}
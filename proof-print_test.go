@@ -0,0 +1,44 @@
+package ipp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProofPrint(t *testing.T) {
+	proof := ProofPrint{Copies: 1, MediaCol: &MediaCol{Width: 21000, Height: 29700, Type: "stationery"}}
+
+	resp := NewResponse(StatusOk, 1)
+	attributes := make(Attributes)
+	attributes.Set(AttributeProofPrintDefault, TagBeginCollection, proof.Collection())
+	attributes.Set(AttributeProofPrintSupported, TagKeyword, "media-col", AttributeProofPrintCopies)
+	attributes.Set(AttributeJobCopiesSupported, TagRange, []int32{1, 999})
+	resp.PrinterAttributes = append(resp.PrinterAttributes, attributes)
+
+	data, err := resp.Encode()
+	assert.Nil(t, err)
+	decoded, err := NewResponseDecoder(bytes.NewReader(data)).Decode(nil)
+	assert.Nil(t, err)
+
+	var d PrinterDescription
+	assert.Nil(t, d.Unmarshal(decoded.PrinterAttributes[0]))
+	assert.Equal(t, &proof, d.ProofPrintDefault)
+	assert.Equal(t, 999, d.JobCopiesSupported)
+	assert.True(t, d.SupportsProofPrint())
+	assert.False(t, (&PrinterDescription{}).SupportsProofPrint())
+
+	// the media-col takes precedence over the media
+	c := ProofPrint{Copies: 2, Media: "na_letter_8.5x11in", MediaCol: &MediaCol{Type: "plain"}}.Collection()
+	assert.Nil(t, c[AttributeMedia])
+	assert.Len(t, c[AttributeMediaCol], 1)
+
+	req := NewRequest(OperationPrintJob, 1, WithProofPrint(NewProofPrint(), 100))
+	assert.Equal(t, 100, req.JobAttributes[AttributeJobCopies])
+	assert.Equal(t, ProofPrint{Copies: 1}.Collection(), req.JobAttributes[AttributeProofPrint])
+	req = NewRequest(OperationPrintJob, 1, WithPrinterURI("ipp://localhost/printers/office"),
+		WithProofPrint(ProofPrint{Media: "iso_a4_210x297mm"}, 0))
+	assert.NotContains(t, req.JobAttributes, AttributeJobCopies)
+	assert.Empty(t, Lint(withCharset(req)))
+}
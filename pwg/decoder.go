@@ -0,0 +1,221 @@
+package pwg
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+
+	"github.com/phin1x/go-ipp"
+)
+
+func init() {
+	ipp.RegisterPageCounter(ipp.MimeTypePwgRaster, CountPages)
+	ipp.RegisterPageCounter(ipp.MimeTypeUrf, CountPages)
+}
+
+// CountPages counts the pages of a pwg raster or urf document, the pages are decompressed without keeping their
+// data. it is registered as the page counter of both formats with ipp.RegisterPageCounter
+func CountPages(r io.Reader) (int, error) {
+	d := NewDecoder(r)
+	d.discard = true
+
+	for pages := 0; ; pages++ {
+		if _, err := d.Next(); err == io.EOF {
+			return pages, nil
+		} else if err != nil {
+			return pages, err
+		}
+	}
+}
+
+// Page is a decoded pwg raster page
+type Page struct {
+	Header Header
+	// Data are the uncompressed lines of the page, Header.BytesPerLine bytes per line
+	Data []byte
+}
+
+// Decoder reads the pages of a pwg raster or urf document
+type Decoder struct {
+	// MaxPixels limits the width times height of the pages, pages exceeding it are rejected with FormatError before
+	// they are decompressed. zero means unlimited
+	MaxPixels int64
+
+	reader *bufio.Reader
+	format string
+	// discard drops the data of the pages, the pages are only counted
+	discard bool
+}
+
+// NewDecoder returns a decoder which reads a pwg raster or urf document from r, the format is detected from the
+// sync word
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{reader: bufio.NewReader(r)}
+}
+
+// Format returns the format of the document, ipp.MimeTypePwgRaster or ipp.MimeTypeUrf. it is empty until the first
+// page was read
+func (d *Decoder) Format() string {
+	return d.format
+}
+
+// Next reads the next page, io.EOF is returned at the end of the document
+func (d *Decoder) Next() (*Page, error) {
+	if d.format == "" {
+		if err := d.readSyncWord(); err != nil {
+			return nil, err
+		}
+	}
+
+	size := HeaderSize
+	if d.format == ipp.MimeTypeUrf {
+		size = URFHeaderSize
+	}
+
+	b := make([]byte, size)
+	if _, err := io.ReadFull(d.reader, b); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %w", FormatError, err)
+	}
+
+	page := &Page{}
+	var err error
+	if d.format == ipp.MimeTypeUrf {
+		err = page.Header.unmarshalURF(b)
+	} else {
+		err = page.Header.UnmarshalBinary(b)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	h := &page.Header
+	if h.BitsPerPixel == 0 || (h.BitsPerPixel >= 8 && h.BitsPerPixel%8 != 0) ||
+		h.BytesPerLine != (h.Width*h.BitsPerPixel+7)/8 {
+		return nil, fmt.Errorf("%w: %d bytes per line for %d pixels with %d bits", FormatError, h.BytesPerLine,
+			h.Width, h.BitsPerPixel)
+	}
+
+	if d.MaxPixels > 0 && int64(h.Width)*int64(h.Height) > d.MaxPixels {
+		return nil, fmt.Errorf("%w: page with %dx%d pixels exceeds the limit", FormatError, h.Width, h.Height)
+	}
+
+	if err := d.readLines(page); err != nil {
+		return nil, fmt.Errorf("%w: %w", FormatError, err)
+	}
+
+	return page, nil
+}
+
+// readSyncWord reads the sync word and detects the format, the page count of urf documents is skipped
+func (d *Decoder) readSyncWord() error {
+	sync, err := d.reader.Peek(len(URFSyncWord) + 4)
+	if err != nil && len(sync) < len(SyncWord) {
+		return fmt.Errorf("%w: %w", FormatError, err)
+	}
+
+	switch {
+	case string(sync[:len(SyncWord)]) == SyncWord:
+		d.format = ipp.MimeTypePwgRaster
+		_, err = d.reader.Discard(len(SyncWord))
+	case len(sync) == len(URFSyncWord)+4 && string(sync[:len(URFSyncWord)]) == URFSyncWord:
+		d.format = ipp.MimeTypeUrf
+		_, err = d.reader.Discard(len(sync))
+	default:
+		return fmt.Errorf("%w: unknown sync word %q", FormatError, sync[:len(SyncWord)])
+	}
+
+	return err
+}
+
+// readLines decompresses the lines of the page
+func (d *Decoder) readLines(page *Page) error {
+	bytesPerLine, height := int(page.Header.BytesPerLine), int(page.Header.Height)
+	size := max(int(page.Header.BitsPerPixel)/8, 1)
+	line := make([]byte, bytesPerLine)
+
+	for y := 0; y < height; {
+		repeat, err := d.reader.ReadByte()
+		if err != nil {
+			return err
+		}
+
+		for x := 0; x < bytesPerLine; {
+			control, err := d.reader.ReadByte()
+			if err != nil {
+				return err
+			}
+
+			if control < 128 {
+				count := (int(control) + 1) * size
+				if x+count > bytesPerLine {
+					return fmt.Errorf("run exceeds line at line %d", y)
+				}
+				if _, err := io.ReadFull(d.reader, line[x:x+size]); err != nil {
+					return err
+				}
+				for i := x + size; i < x+count; i += size {
+					copy(line[i:i+size], line[x:x+size])
+				}
+				x += count
+				continue
+			}
+
+			count := (257 - int(control)) * size
+			if x+count > bytesPerLine {
+				return fmt.Errorf("literal exceeds line at line %d", y)
+			}
+			if _, err := io.ReadFull(d.reader, line[x:x+count]); err != nil {
+				return err
+			}
+			x += count
+		}
+
+		for i := 0; i <= int(repeat) && y < height; i++ {
+			if !d.discard {
+				page.Data = append(page.Data, line...)
+			}
+			y++
+		}
+	}
+
+	return nil
+}
+
+// Image returns the page as image, sgray and black pages as *image.Gray and srgb pages as *image.RGBA. the
+// transforms of the header are not reverted
+func (p *Page) Image() (image.Image, error) {
+	width, height := int(p.Header.Width), int(p.Header.Height)
+	bytesPerLine := int(p.Header.BytesPerLine)
+
+	switch p.Header.Type() {
+	case TypeSGray8:
+		img := image.NewGray(image.Rect(0, 0, width, height))
+		copy(img.Pix, p.Data)
+		return img, nil
+	case TypeBlack1:
+		img := image.NewGray(image.Rect(0, 0, width, height))
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				black := p.Data[y*bytesPerLine+x/8]&(0x80>>(x%8)) != 0
+				if !black {
+					img.SetGray(x, y, color.Gray{Y: 0xff})
+				}
+			}
+		}
+		return img, nil
+	case TypeSRGB8:
+		img := image.NewRGBA(image.Rect(0, 0, width, height))
+		for i := 0; i < width*height; i++ {
+			copy(img.Pix[4*i:4*i+3], p.Data[3*i:3*i+3])
+			img.Pix[4*i+3] = 0xff
+		}
+		return img, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", UnsupportedTypeError, p.Header.Type())
+}
@@ -0,0 +1,348 @@
+package pwg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// the raster types of the encoder, named like the pwg-raster-document-type-supported keywords
+const (
+	TypeBlack1 = "black_1"
+	TypeSGray8 = "sgray_8"
+	TypeSRGB8  = "srgb_8"
+)
+
+// the pwg-raster-document-sheet-back values, they describe how a printer feeds the back side of duplex sheets
+const (
+	SheetBackNormal       = "normal"
+	SheetBackFlipped      = "flipped"
+	SheetBackRotated      = "rotated"
+	SheetBackManualTumble = "manual-tumble"
+)
+
+// DefaultResolution is used if the options have no resolution
+var DefaultResolution = ipp.Resolution{Height: 300, Width: 300, Depth: 3}
+
+// Options are the document options of an encoder. the values should be taken from the pwg-raster-document-*
+// attributes of the printer
+type Options struct {
+	// Format is the document format, ipp.MimeTypePwgRaster or ipp.MimeTypeUrf, pwg raster if empty
+	Format string
+	// Type is the raster type, e.g. TypeSRGB8, TypeSGray8 if empty
+	Type string
+	// Resolution is the resolution of the pages, DefaultResolution if empty. the height is the cross feed and the
+	// width the feed resolution like in printer-resolution
+	Resolution ipp.Resolution
+	// Sides is the ipp sides keyword, e.g. two-sided-long-edge, one-sided if empty
+	Sides string
+	// SheetBack is the pwg-raster-document-sheet-back of the printer, SheetBackNormal if empty. the back sides of
+	// duplex sheets are mirrored accordingly
+	SheetBack string
+	// Copies is the number of copies the printer produces, 1 if zero
+	Copies       int
+	MediaType    string
+	PrintQuality int
+	// PageSizeName is the pwg media size name of the pages, it is derived from the page dimensions if empty
+	PageSizeName string
+	// TotalPageCount is the number of pages of the document, zero if unknown. it is written to the file header of
+	// urf documents
+	TotalPageCount int
+}
+
+// Encoder writes images as pages of a pwg raster or urf document
+type Encoder struct {
+	writer  *bufio.Writer
+	options Options
+	pages   int
+}
+
+// NewEncoder returns an encoder which writes a document in the format of the options to w
+func NewEncoder(w io.Writer, options Options) *Encoder {
+	if options.Format == "" {
+		options.Format = ipp.MimeTypePwgRaster
+	}
+	if options.Type == "" {
+		options.Type = TypeSGray8
+	}
+	if options.Resolution == (ipp.Resolution{}) {
+		options.Resolution = DefaultResolution
+	}
+	if options.Sides == "" {
+		options.Sides = ipp.SidesOneSided
+	}
+	if options.SheetBack == "" {
+		options.SheetBack = SheetBackNormal
+	}
+	if options.Copies == 0 {
+		options.Copies = 1
+	}
+
+	return &Encoder{writer: bufio.NewWriter(w), options: options}
+}
+
+// Encode writes the pages of a document, the total page count is set to the number of pages
+func Encode(w io.Writer, pages []image.Image, options Options) error {
+	if options.TotalPageCount == 0 {
+		options.TotalPageCount = len(pages)
+	}
+
+	e := NewEncoder(w, options)
+	for _, page := range pages {
+		if err := e.Encode(page); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Encode writes the image as the next page, one pixel of the image is one pixel of the page. transparent pixels
+// are printed as white
+func (e *Encoder) Encode(img image.Image) error {
+	header, err := e.header(img.Bounds())
+	if err != nil {
+		return err
+	}
+
+	var b []byte
+	if e.options.Format == ipp.MimeTypeUrf {
+		if e.pages == 0 {
+			b = binary.BigEndian.AppendUint32([]byte(URFSyncWord), uint32(e.options.TotalPageCount))
+		}
+		b, err = header.appendURF(b)
+	} else {
+		if e.pages == 0 {
+			b = []byte(SyncWord)
+		}
+		var page []byte
+		page, err = header.MarshalBinary()
+		b = append(b, page...)
+	}
+	if err != nil {
+		return err
+	}
+	e.pages++
+
+	if _, err := e.writer.Write(b); err != nil {
+		return err
+	}
+
+	if err := e.writeLines(img, header); err != nil {
+		return err
+	}
+
+	return e.writer.Flush()
+}
+
+// header returns the page header of the next page with the bounds
+func (e *Encoder) header(bounds image.Rectangle) (*Header, error) {
+	if bounds.Empty() {
+		return nil, fmt.Errorf("%w: empty page", FormatError)
+	}
+
+	header := &Header{
+		MediaType:      e.options.MediaType,
+		NumCopies:      uint32(e.options.Copies),
+		Width:          uint32(bounds.Dx()),
+		Height:         uint32(bounds.Dy()),
+		TotalPageCount: uint32(e.options.TotalPageCount),
+		PrintQuality:   uint32(e.options.PrintQuality),
+		PageSizeName:   e.options.PageSizeName,
+		ImageBox:       [4]uint32{0, 0, uint32(bounds.Dx()), uint32(bounds.Dy())},
+	}
+
+	if e.options.Format != ipp.MimeTypePwgRaster && e.options.Format != ipp.MimeTypeUrf {
+		return nil, fmt.Errorf("%w: unknown format %s", UnsupportedTypeError, e.options.Format)
+	}
+
+	switch e.options.Type {
+	case TypeBlack1:
+		header.ColorSpace, header.BitsPerColor, header.NumColors = ColorSpaceBlack, 1, 1
+	case TypeSGray8:
+		header.ColorSpace, header.BitsPerColor, header.NumColors = ColorSpaceSGray, 8, 1
+	case TypeSRGB8:
+		header.ColorSpace, header.BitsPerColor, header.NumColors = ColorSpaceSRGB, 8, 3
+	default:
+		return nil, fmt.Errorf("%w: %s", UnsupportedTypeError, e.options.Type)
+	}
+	header.BitsPerPixel = header.BitsPerColor * header.NumColors
+	header.BytesPerLine = (header.Width*header.BitsPerPixel + 7) / 8
+
+	crossFeed, feed := dotsPerInch(e.options.Resolution.Height, e.options.Resolution.Depth),
+		dotsPerInch(e.options.Resolution.Width, e.options.Resolution.Depth)
+	if crossFeed <= 0 || feed <= 0 {
+		return nil, fmt.Errorf("%w: invalid resolution %dx%d", FormatError, crossFeed, feed)
+	}
+	header.HWResolution = [2]uint32{uint32(crossFeed), uint32(feed)}
+	header.PageSize = [2]uint32{
+		uint32((bounds.Dx()*72 + crossFeed/2) / crossFeed),
+		uint32((bounds.Dy()*72 + feed/2) / feed),
+	}
+
+	if header.PageSizeName == "" {
+		width, height := bounds.Dx()*2540/crossFeed, bounds.Dy()*2540/feed
+		if size, ok := ipp.MediaSizeForDimensions(width, height); ok {
+			header.PageSizeName = size.Name
+		} else {
+			header.PageSizeName = ipp.CustomMediaSizeName(width, height)
+		}
+	}
+
+	switch e.options.Sides {
+	case ipp.SidesOneSided:
+	case ipp.SidesTwoSidedLongEdge:
+		header.Duplex = true
+	case ipp.SidesTwoSidedShortEdge:
+		header.Duplex, header.Tumble = true, true
+	default:
+		return nil, fmt.Errorf("%w: unknown sides %s", FormatError, e.options.Sides)
+	}
+
+	header.CrossFeedTransform, header.FeedTransform = 1, 1
+	// the pages are counted from one, the even pages are the back sides
+	if header.Duplex && e.pages%2 == 1 {
+		switch e.options.SheetBack {
+		case SheetBackNormal:
+		case SheetBackFlipped:
+			if header.Tumble {
+				header.CrossFeedTransform = -1
+			} else {
+				header.FeedTransform = -1
+			}
+		case SheetBackRotated:
+			if !header.Tumble {
+				header.CrossFeedTransform, header.FeedTransform = -1, -1
+			}
+		case SheetBackManualTumble:
+			if header.Tumble {
+				header.CrossFeedTransform, header.FeedTransform = -1, -1
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown sheet back %s", FormatError, e.options.SheetBack)
+		}
+	}
+
+	return header, nil
+}
+
+// dotsPerInch converts a resolution in the unit of the resolution depth, 3 for dots per inch and 4 for dots per
+// centimeter, to dots per inch
+func dotsPerInch(value int32, unit int8) int {
+	if unit == 4 {
+		return int(value) * 254 / 100
+	}
+
+	return int(value)
+}
+
+// writeLines writes the compressed lines of the page, the image is mirrored by the transforms of the header
+func (e *Encoder) writeLines(img image.Image, header *Header) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	previous, line := make([]byte, header.BytesPerLine), make([]byte, header.BytesPerLine)
+	repeat := 0
+	var buf []byte
+
+	for y := 0; y < height; y++ {
+		sy := bounds.Min.Y + y
+		if header.FeedTransform < 0 {
+			sy = bounds.Max.Y - 1 - y
+		}
+
+		clear(line)
+		for x := 0; x < width; x++ {
+			sx := bounds.Min.X + x
+			if header.CrossFeedTransform < 0 {
+				sx = bounds.Max.X - 1 - x
+			}
+			putPixel(line, x, header, img.At(sx, sy).RGBA)
+		}
+
+		if y > 0 && repeat < 256 && bytes.Equal(line, previous) {
+			repeat++
+			continue
+		}
+		if y > 0 {
+			buf = compressLine(buf[:0], previous, repeat, int(header.BitsPerPixel))
+			if _, err := e.writer.Write(buf); err != nil {
+				return err
+			}
+		}
+
+		previous, line = line, previous
+		repeat = 1
+	}
+
+	buf = compressLine(buf[:0], previous, repeat, int(header.BitsPerPixel))
+	_, err := e.writer.Write(buf)
+	return err
+}
+
+// putPixel stores the color of a pixel in the line, the color is composed over white
+func putPixel(line []byte, x int, header *Header, rgba func() (r, g, b, a uint32)) {
+	r, g, b, a := rgba()
+	r, g, b = r+0xffff-a, g+0xffff-a, b+0xffff-a
+
+	switch header.ColorSpace {
+	case ColorSpaceSRGB:
+		line[3*x], line[3*x+1], line[3*x+2] = byte(r>>8), byte(g>>8), byte(b>>8)
+	case ColorSpaceSGray:
+		line[x] = byte(gray(r, g, b) >> 8)
+	case ColorSpaceBlack:
+		if gray(r, g, b) < 0x8000 {
+			line[x/8] |= 0x80 >> (x % 8)
+		}
+	}
+}
+
+// gray returns the luminance of 16 bit color components like color.GrayModel
+func gray(r, g, b uint32) uint32 {
+	return (19595*r + 38470*g + 7471*b + 1<<15) >> 16
+}
+
+// compressLine appends a line which is repeated count times in the pwg run length encoding. runs of pixels with
+// the same color are stored as a repeat count and the color, other pixels are stored as a literal count and the
+// colors. lines with less than 8 bits per pixel are compressed byte by byte
+func compressLine(buf, line []byte, count, bitsPerPixel int) []byte {
+	buf = append(buf, byte(count-1))
+
+	size := max(bitsPerPixel/8, 1)
+	pixels := len(line) / size
+	pixel := func(i int) []byte {
+		return line[i*size : (i+1)*size]
+	}
+
+	for i := 0; i < pixels; {
+		j := i + 1
+		for j < pixels && j-i < 128 && bytes.Equal(pixel(j), pixel(i)) {
+			j++
+		}
+		if j-i > 1 {
+			buf = append(buf, byte(j-i-1))
+			buf = append(buf, pixel(i)...)
+			i = j
+			continue
+		}
+
+		// the literal run ends before the next pixels with the same color
+		for j < pixels && j-i < 128 && (j+1 >= pixels || !bytes.Equal(pixel(j), pixel(j+1))) {
+			j++
+		}
+		if j-i == 1 {
+			buf = append(buf, 0)
+		} else {
+			buf = append(buf, byte(257-(j-i)))
+		}
+		buf = append(buf, line[i*size:j*size]...)
+		i = j
+	}
+
+	return buf
+}
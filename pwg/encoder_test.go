@@ -0,0 +1,204 @@
+package pwg
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"io"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+// testPage returns a page with a black left half, a gray right half and a red first line
+func testPage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.RGBA{A: 0xff}
+			switch {
+			case y == 0:
+				c = color.RGBA{R: 0xff, A: 0xff}
+			case x >= width/2:
+				c = color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	return img
+}
+
+func decodePages(t *testing.T, data []byte) []*Page {
+	d := NewDecoder(bytes.NewReader(data))
+
+	var pages []*Page
+	for {
+		page, err := d.Next()
+		if err == io.EOF {
+			return pages
+		}
+		if !assert.Nil(t, err) {
+			return pages
+		}
+		pages = append(pages, page)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	page := testPage(300, 20)
+
+	for _, tt := range []struct {
+		typ          string
+		bytesPerLine uint32
+		want         func(x, y int) color.Color
+	}{
+		{TypeSRGB8, 900, func(x, y int) color.Color { return page.At(x, y) }},
+		{TypeSGray8, 300, func(x, y int) color.Color { return color.GrayModel.Convert(page.At(x, y)) }},
+		{TypeBlack1, 38, func(x, y int) color.Color {
+			if x >= 150 && y > 0 {
+				return color.Gray{Y: 0xff}
+			}
+			return color.Gray{}
+		}},
+	} {
+		t.Run(tt.typ, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.Nil(t, Encode(&buf, []image.Image{page, page}, Options{Type: tt.typ}))
+			assert.Equal(t, SyncWord, buf.String()[:4])
+
+			pages := decodePages(t, buf.Bytes())
+			assert.Len(t, pages, 2)
+
+			header := pages[1].Header
+			assert.Equal(t, tt.typ, header.Type())
+			assert.Equal(t, [2]uint32{300, 300}, header.HWResolution)
+			assert.Equal(t, [2]uint32{72, 5}, header.PageSize)
+			assert.Equal(t, uint32(tt.bytesPerLine), header.BytesPerLine)
+			assert.Equal(t, uint32(2), header.TotalPageCount)
+			assert.Equal(t, uint32(1), header.NumCopies)
+			assert.False(t, header.Duplex)
+
+			img, err := pages[1].Image()
+			assert.Nil(t, err)
+			for _, p := range []image.Point{{0, 0}, {299, 0}, {0, 1}, {149, 19}, {150, 1}, {299, 19}} {
+				r, g, b, _ := img.At(p.X, p.Y).RGBA()
+				wr, wg, wb, _ := tt.want(p.X, p.Y).RGBA()
+				assert.Equal(t, [3]uint32{wr >> 8, wg >> 8, wb >> 8}, [3]uint32{r >> 8, g >> 8, b >> 8}, "%v", p)
+			}
+
+			// the repeated lines of a page are compressed
+			assert.Less(t, buf.Len(), 2*(HeaderSize+int(tt.bytesPerLine)*4))
+		})
+	}
+}
+
+func TestEncode_Transparent(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, Encode(&buf, []image.Image{image.NewNRGBA(image.Rect(0, 0, 10, 10))}, Options{}))
+
+	pages := decodePages(t, buf.Bytes())
+	assert.Equal(t, bytes.Repeat([]byte{0xff}, 100), pages[0].Data)
+}
+
+func TestEncode_PageSize(t *testing.T) {
+	var buf bytes.Buffer
+	a4 := image.Rect(0, 0, 2480, 3508)
+	assert.Nil(t, NewEncoder(&buf, Options{}).Encode(image.NewGray(a4)))
+	assert.Nil(t, NewEncoder(&buf, Options{Resolution: ipp.Resolution{Height: 100, Width: 100, Depth: 3}}).
+		Encode(image.NewGray(image.Rect(0, 0, 400, 500))))
+
+	var header Header
+	assert.Nil(t, header.UnmarshalBinary(buf.Bytes()[4:4+HeaderSize]))
+	assert.Equal(t, "iso_a4_210x297mm", header.PageSizeName)
+	assert.Equal(t, [2]uint32{595, 842}, header.PageSize)
+
+	pages := decodePages(t, buf.Bytes()[bytes.LastIndex(buf.Bytes(), []byte(SyncWord)):])
+	assert.Equal(t, "custom_4x5in_4x5in", pages[0].Header.PageSizeName)
+}
+
+func TestEncode_Duplex(t *testing.T) {
+	page := image.NewGray(image.Rect(0, 0, 4, 2))
+	page.Pix = []byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+	for _, tt := range []struct {
+		sides, sheetBack string
+		transform        [2]int32
+		back             []byte
+	}{
+		{ipp.SidesTwoSidedLongEdge, SheetBackNormal, [2]int32{1, 1}, []byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		{ipp.SidesTwoSidedLongEdge, SheetBackFlipped, [2]int32{1, -1}, []byte{5, 6, 7, 8, 1, 2, 3, 4}},
+		{ipp.SidesTwoSidedShortEdge, SheetBackFlipped, [2]int32{-1, 1}, []byte{4, 3, 2, 1, 8, 7, 6, 5}},
+		{ipp.SidesTwoSidedLongEdge, SheetBackRotated, [2]int32{-1, -1}, []byte{8, 7, 6, 5, 4, 3, 2, 1}},
+		{ipp.SidesTwoSidedShortEdge, SheetBackRotated, [2]int32{1, 1}, []byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		{ipp.SidesTwoSidedShortEdge, SheetBackManualTumble, [2]int32{-1, -1}, []byte{8, 7, 6, 5, 4, 3, 2, 1}},
+	} {
+		var buf bytes.Buffer
+		options := Options{Sides: tt.sides, SheetBack: tt.sheetBack}
+		assert.Nil(t, Encode(&buf, []image.Image{page, page, page}, options))
+
+		pages := decodePages(t, buf.Bytes())
+		assert.Len(t, pages, 3)
+		for i, p := range pages {
+			assert.True(t, p.Header.Duplex)
+			assert.Equal(t, tt.sides == ipp.SidesTwoSidedShortEdge, p.Header.Tumble)
+			if i == 1 {
+				assert.Equal(t, tt.transform, [2]int32{p.Header.CrossFeedTransform, p.Header.FeedTransform},
+					"%s %s", tt.sides, tt.sheetBack)
+				assert.Equal(t, tt.back, p.Data, "%s %s", tt.sides, tt.sheetBack)
+			} else {
+				assert.Equal(t, page.Pix, p.Data)
+			}
+		}
+	}
+}
+
+func TestEncode_Errors(t *testing.T) {
+	page := image.NewGray(image.Rect(0, 0, 1, 1))
+
+	assert.ErrorIs(t, NewEncoder(io.Discard, Options{Type: "cmyk_8"}).Encode(page), UnsupportedTypeError)
+	assert.ErrorIs(t, NewEncoder(io.Discard, Options{Sides: "three-sided"}).Encode(page), FormatError)
+	assert.ErrorIs(t, NewEncoder(io.Discard, Options{}).Encode(image.NewGray(image.Rect(0, 0, 0, 0))), FormatError)
+
+	_, err := NewDecoder(bytes.NewReader([]byte("RaS3"))).Next()
+	assert.ErrorIs(t, err, FormatError)
+}
+
+func TestCompressLine(t *testing.T) {
+	for _, tt := range []struct {
+		line  []byte
+		count int
+		bits  int
+		want  []byte
+	}{
+		{[]byte{1, 1, 1, 1}, 1, 8, []byte{0, 3, 1}},
+		{[]byte{1, 2, 3}, 2, 8, []byte{1, 254, 1, 2, 3}},
+		{[]byte{1, 2, 2, 2, 3}, 1, 8, []byte{0, 0, 1, 2, 2, 0, 3}},
+		{[]byte{1, 2, 3, 1, 2, 3, 4, 5, 6}, 256, 24, []byte{255, 1, 1, 2, 3, 0, 4, 5, 6}},
+		{bytes.Repeat([]byte{7}, 130), 1, 8, []byte{0, 127, 7, 1, 7}},
+	} {
+		got := compressLine(nil, tt.line, tt.count, tt.bits)
+		assert.Equal(t, tt.want, got, "%v", tt.line)
+	}
+}
+
+func TestCountPages(t *testing.T) {
+	page := testPage(50, 30)
+
+	for _, format := range []string{ipp.MimeTypePwgRaster, ipp.MimeTypeUrf} {
+		var buf bytes.Buffer
+		assert.Nil(t, Encode(&buf, []image.Image{page, page, page}, Options{Format: format}))
+
+		pages, err := ipp.CountPages(&buf, format)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, pages)
+	}
+
+	pages, err := CountPages(bytes.NewReader([]byte(SyncWord)))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, pages)
+
+	_, err = CountPages(bytes.NewReader([]byte("%PDF-1.7")))
+	assert.ErrorIs(t, err, FormatError)
+}
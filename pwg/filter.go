@@ -0,0 +1,36 @@
+package pwg
+
+import (
+	"image"
+	// the image formats decoded by the image filter
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// NewImageFilter returns a document filter which converts gif, jpeg or png images of the input format, e.g.
+// image/jpeg, into a single page raster document with the options. one pixel of the image is one pixel of the page,
+// the image is not scaled to the resolution
+func NewImageFilter(input string, options Options) ipp.DocumentFilter {
+	output := options.Format
+	if output == "" {
+		output = ipp.MimeTypePwgRaster
+	}
+
+	return ipp.NewDocumentFilter(input, output, func(r io.Reader) io.Reader {
+		pr, pw := io.Pipe()
+
+		go func() {
+			img, _, err := image.Decode(r)
+			if err == nil {
+				err = Encode(pw, []image.Image{img}, options)
+			}
+			pw.CloseWithError(err)
+		}()
+
+		return pr
+	})
+}
@@ -0,0 +1,30 @@
+package pwg
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageFilter(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, png.Encode(&buf, testPage(40, 10)))
+
+	filter := NewImageFilter("image/png", Options{Format: ipp.MimeTypeUrf, Type: TypeSRGB8})
+	assert.Equal(t, ipp.MimeTypeUrf, filter.OutputFormat())
+
+	data, err := io.ReadAll(filter.Transform(&buf))
+	assert.Nil(t, err)
+	pages := decodePages(t, data)
+	assert.Len(t, pages, 1)
+	assert.Equal(t, uint32(40), pages[0].Header.Width)
+
+	_, err = io.ReadAll(filter.Transform(strings.NewReader("no image")))
+	assert.ErrorIs(t, err, image.ErrFormat)
+}
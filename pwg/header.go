@@ -0,0 +1,275 @@
+// Package pwg encodes and decodes pwg raster documents as defined by pwg 5102.4 and apple raster (urf) documents.
+// pwg raster is the mandatory document format of ipp everywhere printers, urf the format of airprint printers. they
+// are accepted by driverless printers which do not accept pdf.
+//
+// a document starts with a sync word, every page is a header followed by the compressed lines of the page. both
+// formats use the same compression, urf has a shorter page header. PrinterOptions selects the format and raster
+// type supported by a printer
+package pwg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var (
+	// FormatError is returned for data which is not a valid pwg raster document
+	FormatError = errors.New("invalid pwg raster data")
+	// UnsupportedTypeError is returned for raster types and color spaces the package does not handle
+	UnsupportedTypeError = errors.New("unsupported pwg raster type")
+)
+
+// SyncWord starts every pwg raster document
+const SyncWord = "RaS2"
+
+// HeaderSize is the size of a page header in bytes
+const HeaderSize = 1796
+
+// the color spaces of the pwg raster header, named after the cups color spaces
+const (
+	ColorSpaceRGB      = 1
+	ColorSpaceBlack    = 3
+	ColorSpaceCMYK     = 6
+	ColorSpaceSGray    = 18
+	ColorSpaceSRGB     = 19
+	ColorSpaceAdobeRGB = 20
+)
+
+// the values of the cut media, jog and insert sheet fields
+const (
+	WhenNever = iota
+	WhenAfterDocument
+	WhenAfterJob
+	WhenAfterSet
+	WhenAfterPage
+)
+
+// Header is the page header of a pwg raster page. the reserved fields are always zero, the strings are limited to
+// 63 bytes
+type Header struct {
+	MediaColor           string
+	MediaType            string
+	PrintContentOptimize string
+	CutMedia             uint32
+	Duplex               bool
+	// HWResolution is the cross feed and feed resolution in dots per inch
+	HWResolution  [2]uint32
+	InsertSheet   uint32
+	Jog           uint32
+	LeadingEdge   uint32
+	MediaPosition uint32
+	// MediaWeightMetric is the media weight in grams per square meter
+	MediaWeightMetric uint32
+	NumCopies         uint32
+	Orientation       uint32
+	// PageSize is the width and height of the page in points
+	PageSize [2]uint32
+	// Tumble is set for duplex pages bound on the short edge
+	Tumble bool
+	// Width and Height are the dimensions of the page in pixels
+	Width        uint32
+	Height       uint32
+	BitsPerColor uint32
+	BitsPerPixel uint32
+	BytesPerLine uint32
+	// ColorOrder is always 0, the colors of a pixel are stored together
+	ColorOrder     uint32
+	ColorSpace     uint32
+	NumColors      uint32
+	TotalPageCount uint32
+	// CrossFeedTransform and FeedTransform are 1 or -1, -1 if the page image is mirrored in the direction
+	CrossFeedTransform int32
+	FeedTransform      int32
+	// ImageBox is the left, top, right and bottom edge of the printed area in pixels
+	ImageBox         [4]uint32
+	AlternatePrimary uint32
+	// PrintQuality is the ipp print-quality enum, 0 for the default
+	PrintQuality     uint32
+	VendorIdentifier uint32
+	VendorData       []byte
+	RenderingIntent  string
+	PageSizeName     string
+}
+
+// the offsets of the header fields
+const (
+	offsetMediaClass           = 0
+	offsetMediaColor           = 64
+	offsetMediaType            = 128
+	offsetPrintContentOptimize = 192
+	offsetCutMedia             = 268
+	offsetDuplex               = 272
+	offsetHWResolution         = 276
+	offsetInsertSheet          = 300
+	offsetJog                  = 304
+	offsetLeadingEdge          = 308
+	offsetMediaPosition        = 324
+	offsetMediaWeightMetric    = 328
+	offsetNumCopies            = 340
+	offsetOrientation          = 344
+	offsetPageSize             = 352
+	offsetTumble               = 368
+	offsetWidth                = 372
+	offsetHeight               = 376
+	offsetBitsPerColor         = 384
+	offsetBitsPerPixel         = 388
+	offsetBytesPerLine         = 392
+	offsetColorOrder           = 396
+	offsetColorSpace           = 400
+	offsetNumColors            = 420
+	offsetTotalPageCount       = 452
+	offsetCrossFeedTransform   = 456
+	offsetFeedTransform        = 460
+	offsetImageBox             = 464
+	offsetAlternatePrimary     = 480
+	offsetPrintQuality         = 484
+	offsetVendorIdentifier     = 508
+	offsetVendorLength         = 512
+	offsetVendorData           = 516
+	offsetRenderingIntent      = 1668
+	offsetPageSizeName         = 1732
+
+	stringSize     = 64
+	vendorDataSize = 1088
+)
+
+// MarshalBinary encodes the header into its HeaderSize bytes
+func (h *Header) MarshalBinary() ([]byte, error) {
+	if len(h.VendorData) > vendorDataSize {
+		return nil, fmt.Errorf("%w: vendor data exceeds %d bytes", FormatError, vendorDataSize)
+	}
+
+	b := make([]byte, HeaderSize)
+	putString := func(offset int, s string) {
+		if len(s) >= stringSize {
+			s = s[:stringSize-1]
+		}
+		copy(b[offset:], s)
+	}
+	putUint32 := func(offset int, values ...uint32) {
+		for i, v := range values {
+			binary.BigEndian.PutUint32(b[offset+4*i:], v)
+		}
+	}
+
+	putString(offsetMediaClass, "PwgRaster")
+	putString(offsetMediaColor, h.MediaColor)
+	putString(offsetMediaType, h.MediaType)
+	putString(offsetPrintContentOptimize, h.PrintContentOptimize)
+	putUint32(offsetCutMedia, h.CutMedia, boolValue(h.Duplex), h.HWResolution[0], h.HWResolution[1])
+	putUint32(offsetInsertSheet, h.InsertSheet, h.Jog, h.LeadingEdge)
+	putUint32(offsetMediaPosition, h.MediaPosition, h.MediaWeightMetric)
+	putUint32(offsetNumCopies, h.NumCopies, h.Orientation)
+	putUint32(offsetPageSize, h.PageSize[0], h.PageSize[1])
+	putUint32(offsetTumble, boolValue(h.Tumble), h.Width, h.Height)
+	putUint32(offsetBitsPerColor, h.BitsPerColor, h.BitsPerPixel, h.BytesPerLine, h.ColorOrder, h.ColorSpace)
+	putUint32(offsetNumColors, h.NumColors)
+	putUint32(offsetTotalPageCount, h.TotalPageCount, uint32(h.CrossFeedTransform), uint32(h.FeedTransform))
+	putUint32(offsetImageBox, h.ImageBox[:]...)
+	putUint32(offsetAlternatePrimary, h.AlternatePrimary, h.PrintQuality)
+	putUint32(offsetVendorIdentifier, h.VendorIdentifier, uint32(len(h.VendorData)))
+	copy(b[offsetVendorData:], h.VendorData)
+	putString(offsetRenderingIntent, h.RenderingIntent)
+	putString(offsetPageSizeName, h.PageSizeName)
+
+	return b, nil
+}
+
+// UnmarshalBinary decodes a header from its HeaderSize bytes
+func (h *Header) UnmarshalBinary(b []byte) error {
+	if len(b) != HeaderSize {
+		return fmt.Errorf("%w: header has %d bytes", FormatError, len(b))
+	}
+	if getString(b, offsetMediaClass) != "PwgRaster" {
+		return fmt.Errorf("%w: missing PwgRaster media class", FormatError)
+	}
+
+	getUint32 := func(offset int) uint32 {
+		return binary.BigEndian.Uint32(b[offset:])
+	}
+
+	vendorLength := getUint32(offsetVendorLength)
+	if vendorLength > vendorDataSize {
+		return fmt.Errorf("%w: vendor data length %d", FormatError, vendorLength)
+	}
+
+	*h = Header{
+		MediaColor:           getString(b, offsetMediaColor),
+		MediaType:            getString(b, offsetMediaType),
+		PrintContentOptimize: getString(b, offsetPrintContentOptimize),
+		CutMedia:             getUint32(offsetCutMedia),
+		Duplex:               getUint32(offsetDuplex) != 0,
+		HWResolution:         [2]uint32{getUint32(offsetHWResolution), getUint32(offsetHWResolution + 4)},
+		InsertSheet:          getUint32(offsetInsertSheet),
+		Jog:                  getUint32(offsetJog),
+		LeadingEdge:          getUint32(offsetLeadingEdge),
+		MediaPosition:        getUint32(offsetMediaPosition),
+		MediaWeightMetric:    getUint32(offsetMediaWeightMetric),
+		NumCopies:            getUint32(offsetNumCopies),
+		Orientation:          getUint32(offsetOrientation),
+		PageSize:             [2]uint32{getUint32(offsetPageSize), getUint32(offsetPageSize + 4)},
+		Tumble:               getUint32(offsetTumble) != 0,
+		Width:                getUint32(offsetWidth),
+		Height:               getUint32(offsetHeight),
+		BitsPerColor:         getUint32(offsetBitsPerColor),
+		BitsPerPixel:         getUint32(offsetBitsPerPixel),
+		BytesPerLine:         getUint32(offsetBytesPerLine),
+		ColorOrder:           getUint32(offsetColorOrder),
+		ColorSpace:           getUint32(offsetColorSpace),
+		NumColors:            getUint32(offsetNumColors),
+		TotalPageCount:       getUint32(offsetTotalPageCount),
+		CrossFeedTransform:   int32(getUint32(offsetCrossFeedTransform)),
+		FeedTransform:        int32(getUint32(offsetFeedTransform)),
+		AlternatePrimary:     getUint32(offsetAlternatePrimary),
+		PrintQuality:         getUint32(offsetPrintQuality),
+		VendorIdentifier:     getUint32(offsetVendorIdentifier),
+		RenderingIntent:      getString(b, offsetRenderingIntent),
+		PageSizeName:         getString(b, offsetPageSizeName),
+	}
+	for i := range h.ImageBox {
+		h.ImageBox[i] = getUint32(offsetImageBox + 4*i)
+	}
+	if vendorLength > 0 {
+		h.VendorData = append([]byte{}, b[offsetVendorData:offsetVendorData+int(vendorLength)]...)
+	}
+
+	return nil
+}
+
+// Type returns the pwg-raster-document-type-supported keyword of the color space and bits per color, e.g. srgb_8
+func (h *Header) Type() string {
+	name, ok := colorSpaceNames[h.ColorSpace]
+	if !ok {
+		return fmt.Sprintf("unknown-%d_%d", h.ColorSpace, h.BitsPerColor)
+	}
+
+	return fmt.Sprintf("%s_%d", name, h.BitsPerColor)
+}
+
+var colorSpaceNames = map[uint32]string{
+	ColorSpaceRGB:      "rgb",
+	ColorSpaceBlack:    "black",
+	ColorSpaceCMYK:     "cmyk",
+	ColorSpaceSGray:    "sgray",
+	ColorSpaceSRGB:     "srgb",
+	ColorSpaceAdobeRGB: "adobe-rgb",
+}
+
+func getString(b []byte, offset int) string {
+	s := b[offset : offset+stringSize]
+	if i := bytes.IndexByte(s, 0); i >= 0 {
+		s = s[:i]
+	}
+
+	return string(s)
+}
+
+func boolValue(b bool) uint32 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
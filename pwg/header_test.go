@@ -0,0 +1,49 @@
+package pwg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeader(t *testing.T) {
+	header := Header{
+		MediaType:          "stationery",
+		Duplex:             true,
+		HWResolution:       [2]uint32{600, 300},
+		NumCopies:          2,
+		PageSize:           [2]uint32{595, 842},
+		Tumble:             true,
+		Width:              4960,
+		Height:             3508,
+		BitsPerColor:       8,
+		BitsPerPixel:       24,
+		BytesPerLine:       14880,
+		ColorSpace:         ColorSpaceSRGB,
+		NumColors:          3,
+		TotalPageCount:     4,
+		CrossFeedTransform: -1,
+		FeedTransform:      1,
+		ImageBox:           [4]uint32{0, 0, 4960, 3508},
+		PrintQuality:       5,
+		VendorData:         []byte{1, 2, 3},
+		PageSizeName:       "iso_a4_210x297mm",
+	}
+
+	b, err := header.MarshalBinary()
+	assert.Nil(t, err)
+	assert.Len(t, b, HeaderSize)
+	assert.Equal(t, "PwgRaster\x00", string(b[:10]))
+
+	var decoded Header
+	assert.Nil(t, decoded.UnmarshalBinary(b))
+	assert.Equal(t, header, decoded)
+	assert.Equal(t, TypeSRGB8, decoded.Type())
+
+	b[0] = 'X'
+	assert.ErrorIs(t, decoded.UnmarshalBinary(b), FormatError)
+	assert.ErrorIs(t, decoded.UnmarshalBinary(b[:100]), FormatError)
+
+	_, err = (&Header{VendorData: make([]byte, vendorDataSize+1)}).MarshalBinary()
+	assert.ErrorIs(t, err, FormatError)
+}
@@ -0,0 +1,108 @@
+package pwg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// the urf-supported keywords of the duplex back side modes
+var urfSheetBacks = map[string]string{
+	"DM1": SheetBackNormal,
+	"DM2": SheetBackFlipped,
+	"DM3": SheetBackRotated,
+	"DM4": SheetBackManualTumble,
+}
+
+// PrinterOptions returns the options of a raster document for the printer description, e.g. of
+// IPPClient.GetPrinterDescription. pwg raster is preferred, urf is used for apple raster printers which do not
+// support pwg raster. pages are encoded in srgb if color is set and the printer supports it, in gray otherwise.
+// the default resolution of the printer is used if the format supports it. an error wrapping UnsupportedTypeError is
+// returned if the printer supports neither format
+func PrinterOptions(d *ipp.PrinterDescription, color bool) (Options, error) {
+	switch {
+	case d.SupportsDocumentFormat(ipp.MimeTypePwgRaster):
+		options := Options{Format: ipp.MimeTypePwgRaster, SheetBack: d.PwgRasterSheetBack}
+
+		types := d.PwgRasterTypesSupported
+		if len(types) == 0 {
+			types = []string{TypeSGray8}
+		}
+		options.Type = selectType(types, color)
+		if options.Type == "" {
+			return Options{}, fmt.Errorf("%w: printer supports %s", UnsupportedTypeError, strings.Join(types, ", "))
+		}
+
+		options.Resolution = selectResolution(d.PwgRasterResolutionsSupported, d.ResolutionDefault)
+		return options, nil
+	case d.SupportsDocumentFormat(ipp.MimeTypeUrf):
+		options := Options{Format: ipp.MimeTypeUrf}
+
+		var types []string
+		var resolutions []ipp.Resolution
+		for _, keyword := range d.URFSupported {
+			switch {
+			case keyword == "W8":
+				types = append(types, TypeSGray8)
+			case keyword == "SRGB24":
+				types = append(types, TypeSRGB8)
+			case urfSheetBacks[keyword] != "":
+				options.SheetBack = urfSheetBacks[keyword]
+			case strings.HasPrefix(keyword, "RS"):
+				for _, value := range strings.Split(keyword[2:], "-") {
+					if dpi, err := strconv.Atoi(value); err == nil && dpi > 0 {
+						resolutions = append(resolutions, ipp.Resolution{Height: int32(dpi), Width: int32(dpi), Depth: 3})
+					}
+				}
+			}
+		}
+		options.Type = selectType(types, color)
+		if options.Type == "" {
+			return Options{}, fmt.Errorf("%w: printer supports urf %s", UnsupportedTypeError,
+				strings.Join(d.URFSupported, ","))
+		}
+
+		options.Resolution = selectResolution(resolutions, d.ResolutionDefault)
+		return options, nil
+	}
+
+	return Options{}, fmt.Errorf("%w: printer supports neither %s nor %s", UnsupportedTypeError,
+		ipp.MimeTypePwgRaster, ipp.MimeTypeUrf)
+}
+
+// selectType returns the supported type which fits the color mode best, printers without gray types get srgb pages
+func selectType(supported []string, color bool) string {
+	preferred := []string{TypeSGray8, TypeBlack1, TypeSRGB8}
+	if color {
+		preferred = []string{TypeSRGB8, TypeSGray8, TypeBlack1}
+	}
+
+	for _, typ := range preferred {
+		for _, s := range supported {
+			if s == typ {
+				return typ
+			}
+		}
+	}
+
+	return ""
+}
+
+// selectResolution returns the default resolution if it is supported, the first supported resolution otherwise
+func selectResolution(supported []ipp.Resolution, def ipp.Resolution) ipp.Resolution {
+	for _, r := range supported {
+		if r == def {
+			return r
+		}
+	}
+	if len(supported) > 0 {
+		return supported[0]
+	}
+	if def != (ipp.Resolution{}) {
+		return def
+	}
+
+	return DefaultResolution
+}
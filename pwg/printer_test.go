@@ -0,0 +1,83 @@
+package pwg
+
+import (
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrinterOptions(t *testing.T) {
+	dpi := func(dpi int32) ipp.Resolution {
+		return ipp.Resolution{Height: dpi, Width: dpi, Depth: 3}
+	}
+
+	for _, tt := range []struct {
+		name        string
+		description ipp.PrinterDescription
+		color       bool
+		want        Options
+		err         error
+	}{
+		{
+			name: "pwg raster",
+			description: ipp.PrinterDescription{
+				DocumentFormatsSupported:      []string{ipp.MimeTypePDF, ipp.MimeTypeUrf, ipp.MimeTypePwgRaster},
+				ResolutionDefault:             dpi(600),
+				PwgRasterTypesSupported:       []string{TypeSGray8, TypeSRGB8},
+				PwgRasterResolutionsSupported: []ipp.Resolution{dpi(300), dpi(600)},
+				PwgRasterSheetBack:            SheetBackRotated,
+			},
+			color: true,
+			want: Options{Format: ipp.MimeTypePwgRaster, Type: TypeSRGB8, Resolution: dpi(600),
+				SheetBack: SheetBackRotated},
+		},
+		{
+			name: "pwg raster monochrome",
+			description: ipp.PrinterDescription{
+				DocumentFormatsSupported:      []string{ipp.MimeTypePwgRaster},
+				ResolutionDefault:             dpi(1200),
+				PwgRasterTypesSupported:       []string{TypeBlack1, TypeSRGB8},
+				PwgRasterResolutionsSupported: []ipp.Resolution{dpi(300), dpi(600)},
+			},
+			want: Options{Format: ipp.MimeTypePwgRaster, Type: TypeBlack1, Resolution: dpi(300)},
+		},
+		{
+			name: "urf",
+			description: ipp.PrinterDescription{
+				DocumentFormatsSupported: []string{ipp.MimeTypePDF, ipp.MimeTypeUrf},
+				URFSupported:             []string{"V1.4", "CP1", "W8", "SRGB24", "RS300-600", "DM3", "IS1"},
+			},
+			want: Options{Format: ipp.MimeTypeUrf, Type: TypeSGray8, Resolution: dpi(300), SheetBack: SheetBackRotated},
+		},
+		{
+			name: "urf color",
+			description: ipp.PrinterDescription{
+				DocumentFormatsSupported: []string{ipp.MimeTypeUrf},
+				ResolutionDefault:        dpi(600),
+				URFSupported:             []string{"SRGB24", "RS300-600"},
+			},
+			color: true,
+			want:  Options{Format: ipp.MimeTypeUrf, Type: TypeSRGB8, Resolution: dpi(600)},
+		},
+		{
+			name: "urf without types",
+			description: ipp.PrinterDescription{
+				DocumentFormatsSupported: []string{ipp.MimeTypeUrf},
+				URFSupported:             []string{"RS300"},
+			},
+			err: UnsupportedTypeError,
+		},
+		{
+			name:        "pdf only",
+			description: ipp.PrinterDescription{DocumentFormatsSupported: []string{ipp.MimeTypePDF}},
+			err:         UnsupportedTypeError,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := PrinterOptions(&tt.description, tt.color)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.want, options)
+		})
+	}
+}
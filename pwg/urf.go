@@ -0,0 +1,119 @@
+package pwg
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// URFSyncWord starts every urf document, it is followed by the page count
+const URFSyncWord = "UNIRAST\x00"
+
+// URFHeaderSize is the size of an urf page header in bytes
+const URFHeaderSize = 32
+
+// the color spaces of the urf page header
+var urfColorSpaces = map[uint32]byte{
+	ColorSpaceSGray:    0,
+	ColorSpaceSRGB:     1,
+	ColorSpaceAdobeRGB: 3,
+	ColorSpaceRGB:      5,
+	ColorSpaceCMYK:     6,
+}
+
+// the values of the duplex byte of the urf page header
+const (
+	urfSimplex        = 1
+	urfDuplexTumble   = 2
+	urfDuplexNoTumble = 3
+)
+
+// appendURF appends the urf page header of the header. urf pages have a single resolution and at least 8 bits per
+// pixel
+func (h *Header) appendURF(b []byte) ([]byte, error) {
+	colorSpace, ok := urfColorSpaces[h.ColorSpace]
+	if !ok || h.BitsPerPixel < 8 {
+		return nil, fmt.Errorf("%w: %s in urf", UnsupportedTypeError, h.Type())
+	}
+	if h.HWResolution[0] != h.HWResolution[1] {
+		return nil, fmt.Errorf("%w: urf requires equal resolutions, got %dx%d", FormatError, h.HWResolution[0],
+			h.HWResolution[1])
+	}
+
+	duplex := byte(urfSimplex)
+	switch {
+	case h.Duplex && h.Tumble:
+		duplex = urfDuplexTumble
+	case h.Duplex:
+		duplex = urfDuplexNoTumble
+	}
+
+	header := make([]byte, URFHeaderSize)
+	header[0] = byte(h.BitsPerPixel)
+	header[1] = colorSpace
+	header[2] = duplex
+	header[3] = byte(h.PrintQuality)
+	header[5] = byte(h.MediaPosition)
+	binary.BigEndian.PutUint32(header[12:], h.Width)
+	binary.BigEndian.PutUint32(header[16:], h.Height)
+	binary.BigEndian.PutUint32(header[20:], h.HWResolution[0])
+
+	return append(b, header...), nil
+}
+
+// unmarshalURF decodes an urf page header, the fields missing in urf are derived from the other fields
+func (h *Header) unmarshalURF(b []byte) error {
+	if len(b) != URFHeaderSize {
+		return fmt.Errorf("%w: urf header has %d bytes", FormatError, len(b))
+	}
+
+	*h = Header{
+		BitsPerPixel:       uint32(b[0]),
+		PrintQuality:       uint32(b[3]),
+		MediaPosition:      uint32(b[5]),
+		Width:              binary.BigEndian.Uint32(b[12:]),
+		Height:             binary.BigEndian.Uint32(b[16:]),
+		NumCopies:          1,
+		CrossFeedTransform: 1,
+		FeedTransform:      1,
+	}
+
+	if h.BitsPerPixel == 0 || h.BitsPerPixel%8 != 0 {
+		return fmt.Errorf("%w: urf page with %d bits per pixel", FormatError, h.BitsPerPixel)
+	}
+	found := false
+	for colorSpace, value := range urfColorSpaces {
+		if value == b[1] {
+			h.ColorSpace, found = colorSpace, true
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: urf color space %d", UnsupportedTypeError, b[1])
+	}
+	switch h.ColorSpace {
+	case ColorSpaceSGray:
+		h.NumColors = 1
+	case ColorSpaceCMYK:
+		h.NumColors = 4
+	default:
+		h.NumColors = 3
+	}
+	h.BitsPerColor = h.BitsPerPixel / h.NumColors
+	h.BytesPerLine = h.Width * h.BitsPerPixel / 8
+
+	switch b[2] {
+	case urfDuplexTumble:
+		h.Duplex, h.Tumble = true, true
+	case urfDuplexNoTumble:
+		h.Duplex = true
+	}
+
+	resolution := binary.BigEndian.Uint32(b[20:])
+	if resolution == 0 {
+		return fmt.Errorf("%w: urf resolution is zero", FormatError)
+	}
+	h.HWResolution = [2]uint32{resolution, resolution}
+	h.PageSize = [2]uint32{h.Width * 72 / resolution, h.Height * 72 / resolution}
+	h.ImageBox = [4]uint32{0, 0, h.Width, h.Height}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package pwg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode_URF(t *testing.T) {
+	page := testPage(300, 20)
+
+	var buf bytes.Buffer
+	options := Options{Format: ipp.MimeTypeUrf, Type: TypeSRGB8, Sides: ipp.SidesTwoSidedShortEdge, PrintQuality: 5}
+	assert.Nil(t, Encode(&buf, []image.Image{page, page}, options))
+
+	data := buf.Bytes()
+	assert.Equal(t, URFSyncWord, string(data[:8]))
+	assert.Equal(t, uint32(2), binary.BigEndian.Uint32(data[8:]))
+	assert.Equal(t, []byte{24, 1, 2, 5}, data[12:16])
+	assert.Equal(t, uint32(300), binary.BigEndian.Uint32(data[12+12:]))
+	assert.Equal(t, uint32(20), binary.BigEndian.Uint32(data[12+16:]))
+	assert.Equal(t, uint32(300), binary.BigEndian.Uint32(data[12+20:]))
+
+	d := NewDecoder(bytes.NewReader(data))
+	pages := decodePages(t, data)
+	assert.Len(t, pages, 2)
+
+	header := pages[1].Header
+	assert.Equal(t, TypeSRGB8, header.Type())
+	assert.True(t, header.Duplex)
+	assert.True(t, header.Tumble)
+	assert.Equal(t, uint32(900), header.BytesPerLine)
+	assert.Equal(t, uint32(5), header.PrintQuality)
+
+	img, err := pages[0].Image()
+	assert.Nil(t, err)
+	assert.Equal(t, page.At(0, 0), img.At(0, 0))
+	assert.Equal(t, page.At(299, 19), img.At(299, 19))
+
+	_, err = d.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, ipp.MimeTypeUrf, d.Format())
+}
+
+func TestEncode_URFErrors(t *testing.T) {
+	page := image.NewGray(image.Rect(0, 0, 8, 8))
+
+	var buf bytes.Buffer
+	err := NewEncoder(&buf, Options{Format: ipp.MimeTypeUrf, Type: TypeBlack1}).Encode(page)
+	assert.ErrorIs(t, err, UnsupportedTypeError)
+
+	resolution := ipp.Resolution{Height: 600, Width: 300, Depth: 3}
+	err = NewEncoder(&buf, Options{Format: ipp.MimeTypeUrf, Resolution: resolution}).Encode(page)
+	assert.ErrorIs(t, err, FormatError)
+	assert.Zero(t, buf.Len())
+
+	err = NewEncoder(&buf, Options{Format: "application/pdf"}).Encode(page)
+	assert.ErrorIs(t, err, UnsupportedTypeError)
+}
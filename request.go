@@ -2,8 +2,10 @@ package ipp
 
 import (
 	"bytes"
-	"encoding/binary"
+	"context"
+	"fmt"
 	"io"
+	"slices"
 )
 
 // Request defines a ipp request
@@ -14,51 +16,239 @@ type Request struct {
 	Operation int16
 	RequestId int32
 
-	OperationAttributes map[string]interface{}
-	JobAttributes       map[string]interface{}
-	PrinterAttributes   map[string]interface{}
+	OperationAttributes    map[string]interface{}
+	JobAttributes          map[string]interface{}
+	PrinterAttributes      map[string]interface{}
+	SubscriptionAttributes map[string]interface{}
 
 	File     io.Reader
 	FileSize int
+
+	// tags are the tags of decoded attributes without tag in the AttributeTagMapping, e.g. vendor attributes, so
+	// they are encoded with their tag again
+	tags map[string]int8
 }
 
-// NewRequest creates a new ipp request
-func NewRequest(op int16, reqID int32) *Request {
-	return &Request{
-		ProtocolVersionMajor: ProtocolVersionMajor,
-		ProtocolVersionMinor: ProtocolVersionMinor,
-		Operation:            op,
-		RequestId:            reqID,
-		OperationAttributes:  make(map[string]interface{}),
-		JobAttributes:        make(map[string]interface{}),
-		PrinterAttributes:    make(map[string]interface{}),
-		File:                 nil,
-		FileSize:             -1,
+// RequestOption sets attributes or the document of a request created by NewRequest
+type RequestOption func(*Request)
+
+// NewRequest creates a new ipp request, the options are applied in order, e.g.
+//
+//	NewRequest(OperationPrintJob, 1, WithPrinterURI(uri), WithUser("alice"), WithDocument(f, size, MimeTypePostscript))
+//
+// the target attributes are checked by Validate
+func NewRequest(op int16, reqID int32, opts ...RequestOption) *Request {
+	req := &Request{
+		ProtocolVersionMajor:   ProtocolVersionMajor,
+		ProtocolVersionMinor:   ProtocolVersionMinor,
+		Operation:              op,
+		RequestId:              reqID,
+		OperationAttributes:    make(map[string]interface{}),
+		JobAttributes:          make(map[string]interface{}),
+		PrinterAttributes:      make(map[string]interface{}),
+		SubscriptionAttributes: make(map[string]interface{}),
+		File:                   nil,
+		FileSize:               -1,
+	}
+
+	for _, opt := range opts {
+		opt(req)
 	}
+
+	return req
 }
 
-// Encode encodes the request to a byte slice
-func (r *Request) Encode() ([]byte, error) {
-	buf := new(bytes.Buffer)
-	enc := NewAttributeEncoder(buf)
+// WithPrinterURI sets the printer-uri operation attribute
+func WithPrinterURI(uri string) RequestOption {
+	return func(r *Request) {
+		r.OperationAttributes[AttributePrinterURI] = uri
+	}
+}
 
-	if err := binary.Write(buf, binary.BigEndian, r.ProtocolVersionMajor); err != nil {
-		return nil, err
+// WithJobURI sets the job-uri operation attribute
+func WithJobURI(uri string) RequestOption {
+	return func(r *Request) {
+		r.OperationAttributes[AttributeJobURI] = uri
 	}
+}
 
-	if err := binary.Write(buf, binary.BigEndian, r.ProtocolVersionMinor); err != nil {
-		return nil, err
+// WithJobID sets the job-id operation attribute, the job is addressed by the printer-uri and the job-id
+func WithJobID(id int) RequestOption {
+	return func(r *Request) {
+		r.OperationAttributes[AttributeJobID] = id
+	}
+}
+
+// WithUser sets the requesting-user-name operation attribute
+func WithUser(name string) RequestOption {
+	return func(r *Request) {
+		r.OperationAttributes[AttributeRequestingUserName] = name
 	}
+}
 
-	if err := binary.Write(buf, binary.BigEndian, r.Operation); err != nil {
-		return nil, err
+// WithRequestedAttributes sets the requested-attributes operation attribute
+func WithRequestedAttributes(names ...string) RequestOption {
+	return func(r *Request) {
+		r.OperationAttributes[AttributeRequestedAttributes] = names
+	}
+}
+
+// WithGroups adds group keywords like RequestedJobTemplate or RequestedMediaColDatabase to the requested-attributes
+// operation attribute, names set by WithRequestedAttributes are kept
+func WithGroups(groups ...string) RequestOption {
+	return func(r *Request) {
+		requested, _ := r.OperationAttributes[AttributeRequestedAttributes].([]string)
+		r.OperationAttributes[AttributeRequestedAttributes] = append(append([]string(nil), requested...), groups...)
+	}
+}
+
+// WithOperationAttributes adds the attributes to the operation attributes, existing attributes are overwritten
+func WithOperationAttributes(attributes map[string]interface{}) RequestOption {
+	return func(r *Request) {
+		for name, value := range attributes {
+			r.OperationAttributes[name] = value
+		}
+	}
+}
+
+// WithJobAttributes adds the attributes to the job attributes, existing attributes are overwritten
+func WithJobAttributes(attributes map[string]interface{}) RequestOption {
+	return func(r *Request) {
+		for name, value := range attributes {
+			r.JobAttributes[name] = value
+		}
+	}
+}
+
+// WithPrinterAttributes adds the attributes to the printer attributes, existing attributes are overwritten
+func WithPrinterAttributes(attributes map[string]interface{}) RequestOption {
+	return func(r *Request) {
+		for name, value := range attributes {
+			r.PrinterAttributes[name] = value
+		}
+	}
+}
+
+// WithDocumentFormat sets the document-format operation attribute, e.g. to request the capabilities of a printer for
+// one format with Get-Printer-Attributes
+func WithDocumentFormat(format string) RequestOption {
+	return func(r *Request) {
+		r.OperationAttributes[AttributeDocumentFormat] = format
+	}
+}
+
+// WithDocument sets the document data of the request, size is the length of the data or -1 if unknown. the
+// document-format operation attribute is set if format is not empty
+func WithDocument(document io.Reader, size int, format string) RequestOption {
+	return func(r *Request) {
+		r.File = document
+		r.FileSize = size
+		if format != "" {
+			r.OperationAttributes[AttributeDocumentFormat] = format
+		}
+	}
+}
+
+// jobTargetOperations address a job with job-uri or with printer-uri and job-id
+var jobTargetOperations = []int16{
+	OperationSendDocument,
+	OperationSendUri,
+	OperationCancelJob,
+	OperationGetJobAttributes,
+	OperationHoldJob,
+	OperationReleaseJob,
+	OperationRestartJob,
+	OperationSetJobAttributes,
+	OperationCloseJob,
+	OperationResubmitJob,
+	OperationCupsAuthenticateJob,
+	OperationCupsGetDocument,
+}
+
+// printerTargetOperations address a printer with printer-uri
+var printerTargetOperations = []int16{
+	OperationPrintJob,
+	OperationPrintUri,
+	OperationValidateJob,
+	OperationCreateJob,
+	OperationGetJobs,
+	OperationGetPrinterAttributes,
+	OperationPausePrinter,
+	OperationResumePrinter,
+	OperationPurgeJobs,
+	OperationSetPrinterAttributes,
+	OperationGetPrinterSupportedValues,
+	OperationCreatePrinterSubscriptions,
+	OperationCreateJobSubscriptions,
+	OperationGetSubscriptionAttributes,
+	OperationGetSubscriptions,
+	OperationRenewSubscription,
+	OperationCancelSubscription,
+	OperationGetNotifications,
+	OperationEnablePrinter,
+	OperationDisablePrinter,
+	OperationHoldNewJobs,
+	OperationReleaseHeldNewJobs,
+	OperationCancelJobs,
+	OperationCancelMyJobs,
+	OperationIdentifyPrinter,
+	OperationValidateDocument,
+	OperationGetUserPrinterAttributes,
+	OperationCupsAddModifyPrinter,
+	OperationCupsDeletePrinter,
+	OperationCupsAddModifyClass,
+	OperationCupsDeleteClass,
+	OperationCupsAcceptJobs,
+	OperationCupsRejectJobs,
+	OperationCupsSetDefault,
+}
+
+// Validate checks that the request contains the operation attributes which address the target of the operation, a
+// printer-uri for printer operations and a job-uri or printer-uri and job-id for job operations. NewRequest doesn't
+// check the request, the client validates it before it is sent. operations without target, e.g. CUPS-Get-Printers,
+// and unknown operations are not checked
+func (r *Request) Validate() error {
+	_, printerURI := r.OperationAttributes[AttributePrinterURI]
+	_, jobURI := r.OperationAttributes[AttributeJobURI]
+	_, jobID := r.OperationAttributes[AttributeJobID]
+
+	switch {
+	case slices.Contains(jobTargetOperations, r.Operation):
+		if !jobURI && !(printerURI && jobID) {
+			return fmt.Errorf("%w: %s requires job-uri or printer-uri and job-id", MissingTargetError,
+				Operation(r.Operation))
+		}
+	case slices.Contains(printerTargetOperations, r.Operation):
+		if !printerURI {
+			return fmt.Errorf("%w: %s requires printer-uri", MissingTargetError, Operation(r.Operation))
+		}
+	}
+
+	return nil
+}
+
+// AttributeTag returns the tag of an attribute of the request, the tag of the AttributeTagMapping or the tag an
+// attribute without mapping, e.g. a vendor attribute, was decoded with
+func (r *Request) AttributeTag(name string) (int8, bool) {
+	if tag, ok := AttributeTagMapping[name]; ok {
+		return tag, true
 	}
 
-	if err := binary.Write(buf, binary.BigEndian, r.RequestId); err != nil {
+	tag, ok := r.tags[name]
+	return tag, ok
+}
+
+// Encode encodes the request to a byte slice
+func (r *Request) Encode() ([]byte, error) {
+	buf := getEncodeBuffer()
+	defer putEncodeBuffer(buf)
+	enc := &buf.encoder
+
+	if err := enc.encodeHeader(r.ProtocolVersionMajor, r.ProtocolVersionMinor, r.Operation, r.RequestId); err != nil {
 		return nil, err
 	}
 
-	if err := binary.Write(buf, binary.BigEndian, TagOperation); err != nil {
+	if err := enc.encodeTag(TagOperation); err != nil {
 		return nil, err
 	}
 
@@ -79,32 +269,43 @@ func (r *Request) Encode() ([]byte, error) {
 	}
 
 	if len(r.JobAttributes) > 0 {
-		if err := binary.Write(buf, binary.BigEndian, TagJob); err != nil {
+		if err := enc.encodeTag(TagJob); err != nil {
 			return nil, err
 		}
 		for attr, value := range r.JobAttributes {
-			if err := enc.Encode(attr, value); err != nil {
+			if err := r.encodeAttribute(enc, attr, value); err != nil {
 				return nil, err
 			}
 		}
 	}
 
 	if len(r.PrinterAttributes) > 0 {
-		if err := binary.Write(buf, binary.BigEndian, TagPrinter); err != nil {
+		if err := enc.encodeTag(TagPrinter); err != nil {
 			return nil, err
 		}
 		for attr, value := range r.PrinterAttributes {
-			if err := enc.Encode(attr, value); err != nil {
+			if err := r.encodeAttribute(enc, attr, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(r.SubscriptionAttributes) > 0 {
+		if err := enc.encodeTag(TagSubscription); err != nil {
+			return nil, err
+		}
+		for attr, value := range r.SubscriptionAttributes {
+			if err := r.encodeAttribute(enc, attr, value); err != nil {
 				return nil, err
 			}
 		}
 	}
 
-	if err := binary.Write(buf, binary.BigEndian, TagEnd); err != nil {
+	if err := enc.encodeTag(TagEnd); err != nil {
 		return nil, err
 	}
 
-	return buf.Bytes(), nil
+	return bytes.Clone(buf.Bytes()), nil
 }
 
 func (r *Request) encodeOperationAttributes(enc *AttributeEncoder) error {
@@ -117,15 +318,18 @@ func (r *Request) encodeOperationAttributes(enc *AttributeEncoder) error {
 
 	for _, attr := range ordered {
 		if value, ok := r.OperationAttributes[attr]; ok {
-			delete(r.OperationAttributes, attr)
-			if err := enc.Encode(attr, value); err != nil {
+			if err := r.encodeAttribute(enc, attr, value); err != nil {
 				return err
 			}
 		}
 	}
 
+	// the request is left unchanged, so it can be encoded again, e.g. to retry it
 	for attr, value := range r.OperationAttributes {
-		if err := enc.Encode(attr, value); err != nil {
+		if slices.Contains(ordered, attr) {
+			continue
+		}
+		if err := r.encodeAttribute(enc, attr, value); err != nil {
 			return err
 		}
 	}
@@ -133,9 +337,31 @@ func (r *Request) encodeOperationAttributes(enc *AttributeEncoder) error {
 	return nil
 }
 
+// encodeAttribute encodes an attribute with the tag it was decoded with if it has no tag in the AttributeTagMapping
+func (r *Request) encodeAttribute(enc *AttributeEncoder, name string, value interface{}) error {
+	switch value.(type) {
+	case Attribute, []Attribute:
+		return enc.Encode(name, value)
+	}
+
+	if tag, ok := r.AttributeTag(name); ok {
+		return enc.EncodeWithTag(name, tag, value)
+	}
+
+	return enc.Encode(name, value)
+}
+
 // RequestDecoder reads and decodes a request from a stream
 type RequestDecoder struct {
 	reader io.Reader
+	// InternValues decodes repeated keyword like values of the request into the same string, see
+	// AttributeDecoder.InternValues
+	InternValues bool
+	// OnWarning receives the deviations from the specification the decoder tolerates, e.g. duplicate attributes or
+	// a missing end-of-attributes tag, see AttributeDecoder.OnWarning
+	OnWarning func(Warning)
+	// Profile selects whether deviations are reported to OnWarning or fail decoding, see DecodeProfile
+	Profile DecodeProfile
 }
 
 // NewRequestDecoder returns a new decoder that reads from r
@@ -145,23 +371,44 @@ func NewRequestDecoder(r io.Reader) *RequestDecoder {
 	}
 }
 
+// DecodeContext decodes a request like Decode, but stops with a ReadTimeoutError once the context is done. reads
+// from a stream with read deadlines like a net.Conn are interrupted, other readers are checked between reads
+func (d *RequestDecoder) DecodeContext(ctx context.Context, data io.Writer) (*Request, error) {
+	reader := d.reader
+	defer func() { d.reader = reader }()
+
+	var release func(error) error
+	d.reader, release = withContext(ctx, reader)
+	req, err := d.Decode(data)
+
+	return req, release(err)
+}
+
 // Decode decodes a ipp request into a request  struct. additional data will be written to an io.Writer if data is not nil
 func (d *RequestDecoder) Decode(data io.Writer) (*Request, error) {
 	req := new(Request)
 
-	if err := binary.Read(d.reader, binary.BigEndian, &req.ProtocolVersionMajor); err != nil {
+	attribDecoder := NewAttributeDecoder(d.reader)
+	if d.InternValues {
+		attribDecoder.InternValues()
+	}
+	attribDecoder.OnWarning(d.OnWarning)
+	attribDecoder.SetProfile(d.Profile)
+
+	var err error
+	if req.ProtocolVersionMajor, err = attribDecoder.readInt8(); err != nil {
 		return nil, err
 	}
 
-	if err := binary.Read(d.reader, binary.BigEndian, &req.ProtocolVersionMinor); err != nil {
+	if req.ProtocolVersionMinor, err = attribDecoder.readInt8(); err != nil {
 		return nil, err
 	}
 
-	if err := binary.Read(d.reader, binary.BigEndian, &req.Operation); err != nil {
+	if req.Operation, err = attribDecoder.readInt16(); err != nil {
 		return nil, err
 	}
 
-	if err := binary.Read(d.reader, binary.BigEndian, &req.RequestId); err != nil {
+	if req.RequestId, err = attribDecoder.readInt32(); err != nil {
 		return nil, err
 	}
 
@@ -169,13 +416,14 @@ func (d *RequestDecoder) Decode(data io.Writer) (*Request, error) {
 
 	tag := TagCupsInvalid
 	previousAttributeName := ""
-	tagSet := false
-
-	attribDecoder := NewAttributeDecoder(d.reader)
+	// the number of operation attributes and whether the group has a named attribute, for the conformance checks
+	operationAttributes := 0
+	named := false
+	endTag := false
 
 	// decode attribute buffer
 	for {
-		if _, err := d.reader.Read(startByteSlice); err != nil {
+		if _, err := io.ReadFull(d.reader, startByteSlice); err != nil {
 			// when we read from a stream, we may get an EOF if we want to read the end tag
 			// all data should be read and we can ignore the error
 			if err == io.EOF {
@@ -188,55 +436,87 @@ func (d *RequestDecoder) Decode(data io.Writer) (*Request, error) {
 
 		// check if attributes are completed
 		if startByte == TagEnd {
+			endTag = true
 			break
 		}
 
-		if startByte == TagOperation {
-			if req.OperationAttributes == nil {
-				req.OperationAttributes = make(map[string]interface{})
+		// a delimiter tag starts the next group, the group may be empty
+		if startByte < TagUnsupportedValue {
+			switch startByte {
+			case TagOperation:
+				if req.OperationAttributes == nil {
+					req.OperationAttributes = make(map[string]interface{})
+				}
+			case TagJob:
+				if req.JobAttributes == nil {
+					req.JobAttributes = make(map[string]interface{})
+				}
+			case TagPrinter:
+				if req.PrinterAttributes == nil {
+					req.PrinterAttributes = make(map[string]interface{})
+				}
+			case TagSubscription:
+				if req.SubscriptionAttributes == nil {
+					req.SubscriptionAttributes = make(map[string]interface{})
+				}
+			default:
+				return nil, fmt.Errorf("%w: group tag 0x%02x in request", UnexpectedGroupError, uint8(startByte))
 			}
 
-			tag = TagOperation
-			tagSet = true
-
-		}
-
-		if startByte == TagJob {
-			if req.JobAttributes == nil {
-				req.JobAttributes = make(map[string]interface{})
+			if tag == TagCupsInvalid && startByte != TagOperation && attribDecoder.checking() {
+				err := attribDecoder.messageDeviation("the first group is 0x%02x instead of the operation attributes",
+					uint8(startByte))
+				if err != nil {
+					return nil, err
+				}
 			}
-			tag = TagJob
-			tagSet = true
-		}
 
-		if startByte == TagPrinter {
-			if req.PrinterAttributes == nil {
-				req.PrinterAttributes = make(map[string]interface{})
-			}
-			tag = TagPrinter
-			tagSet = true
+			tag = startByte
+			named = false
+			continue
 		}
 
-		if tagSet {
-			if _, err := d.reader.Read(startByteSlice); err != nil {
-				return nil, err
-			}
-			startByte = int8(startByteSlice[0])
+		if tag == TagCupsInvalid {
+			return nil, fmt.Errorf("%w: attribute before the operation attributes", UnexpectedGroupError)
 		}
 
-		attrib, err := attribDecoder.Decode(startByte)
+		attrib, err := attribDecoder.decode(startByte)
 		if err != nil {
 			return nil, err
 		}
 
 		if attrib.Name != "" {
+			if attribDecoder.checking() {
+				if tag == TagOperation {
+					if err := attribDecoder.checkOperationAttribute(operationAttributes, attrib.Name); err != nil {
+						return nil, err
+					}
+					operationAttributes++
+				}
+				if _, ok := requestGroup(req, tag)[attrib.Name]; ok {
+					if err := attribDecoder.deviation("duplicate attribute, the last one is kept"); err != nil {
+						return nil, err
+					}
+				}
+			}
+			named = true
+			req.setDecodedTag(attrib.Name, attrib.Tag)
 			appendAttributeToRequest(req, tag, attrib.Name, attrib.Value)
 			previousAttributeName = attrib.Name
 		} else {
-			appendAttributeToRequest(req, tag, previousAttributeName, attrib.Value)
+			if !named && attribDecoder.checking() {
+				if err := attribDecoder.messageDeviation("value without attribute name at the start of a group"); err != nil {
+					return nil, err
+				}
+			}
+			appendValueToRequest(req, tag, previousAttributeName, attrib.Value)
 		}
+	}
 
-		tagSet = false
+	if attribDecoder.checking() {
+		if err := attribDecoder.checkMessage(operationAttributes, endTag); err != nil {
+			return nil, err
+		}
 	}
 
 	if data != nil {
@@ -248,6 +528,34 @@ func (d *RequestDecoder) Decode(data io.Writer) (*Request, error) {
 	return req, nil
 }
 
+// setDecodedTag keeps the tag of a decoded attribute without tag in the AttributeTagMapping, see AttributeTag
+func (r *Request) setDecodedTag(name string, tag int8) {
+	if _, ok := AttributeTagMapping[name]; ok {
+		return
+	}
+
+	if r.tags == nil {
+		r.tags = make(map[string]int8)
+	}
+	r.tags[name] = tag
+}
+
+// requestGroup returns the attributes of a group of the request
+func requestGroup(req *Request, tag int8) map[string]interface{} {
+	switch tag {
+	case TagOperation:
+		return req.OperationAttributes
+	case TagPrinter:
+		return req.PrinterAttributes
+	case TagJob:
+		return req.JobAttributes
+	case TagSubscription:
+		return req.SubscriptionAttributes
+	}
+
+	return nil
+}
+
 func appendAttributeToRequest(req *Request, tag int8, name string, value interface{}) {
 	switch tag {
 	case TagOperation:
@@ -256,5 +564,67 @@ func appendAttributeToRequest(req *Request, tag int8, name string, value interfa
 		req.PrinterAttributes[name] = value
 	case TagJob:
 		req.JobAttributes[name] = value
+	case TagSubscription:
+		req.SubscriptionAttributes[name] = value
 	}
 }
+
+// appendValueToRequest adds an additional value to an already decoded attribute, turning it into a slice
+func appendValueToRequest(req *Request, tag int8, name string, value interface{}) {
+	var attributes map[string]interface{}
+
+	switch tag {
+	case TagOperation:
+		attributes = req.OperationAttributes
+	case TagPrinter:
+		attributes = req.PrinterAttributes
+	case TagJob:
+		attributes = req.JobAttributes
+	case TagSubscription:
+		attributes = req.SubscriptionAttributes
+	default:
+		return
+	}
+
+	existing, ok := attributes[name]
+	if !ok {
+		attributes[name] = value
+		return
+	}
+
+	attributes[name] = appendValue(existing, value)
+}
+
+// appendValue appends a value to a single value or a slice of values. slices of the same type are kept typed
+func appendValue(existing, value interface{}) interface{} {
+	switch e := existing.(type) {
+	case string:
+		if v, ok := value.(string); ok {
+			return []string{e, v}
+		}
+	case []string:
+		if v, ok := value.(string); ok {
+			return append(e, v)
+		}
+	case int:
+		if v, ok := value.(int); ok {
+			return []int{e, v}
+		}
+	case []int:
+		if v, ok := value.(int); ok {
+			return append(e, v)
+		}
+	case bool:
+		if v, ok := value.(bool); ok {
+			return []bool{e, v}
+		}
+	case []bool:
+		if v, ok := value.(bool); ok {
+			return append(e, v)
+		}
+	case []interface{}:
+		return append(e, value)
+	}
+
+	return []interface{}{existing, value}
+}
@@ -2,7 +2,9 @@ package ipp
 
 import (
 	"bytes"
+	"errors"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -38,12 +40,84 @@ var requestTestCases = []struct {
 	},
 }
 
+func TestNewRequest_Options(t *testing.T) {
+	document := strings.NewReader("%!PS")
+	req := NewRequest(OperationPrintJob, 7,
+		WithPrinterURI("ipp://localhost/printers/office"),
+		WithUser("alice"),
+		WithOperationAttributes(map[string]interface{}{AttributeJobName: "report"}),
+		WithJobAttributes(map[string]interface{}{AttributeCopies: 2}),
+		WithJobAttributes(map[string]interface{}{AttributeSides: "two-sided-long-edge"}),
+		WithDocument(document, 4, MimeTypePostscript),
+	)
+
+	assert.Equal(t, OperationPrintJob, req.Operation)
+	assert.Equal(t, int32(7), req.RequestId)
+	assert.Equal(t, map[string]interface{}{
+		AttributePrinterURI:         "ipp://localhost/printers/office",
+		AttributeRequestingUserName: "alice",
+		AttributeJobName:            "report",
+		AttributeDocumentFormat:     MimeTypePostscript,
+	}, req.OperationAttributes)
+	assert.Equal(t, map[string]interface{}{AttributeCopies: 2, AttributeSides: "two-sided-long-edge"}, req.JobAttributes)
+	assert.Equal(t, document, req.File)
+	assert.Equal(t, 4, req.FileSize)
+
+	req = NewRequest(OperationGetJobAttributes, 1, WithPrinterURI("ipp://localhost/printers/office"), WithJobID(3),
+		WithRequestedAttributes(AttributeJobState), WithDocument(nil, -1, ""))
+	assert.Equal(t, 3, req.OperationAttributes[AttributeJobID])
+	assert.Equal(t, []string{AttributeJobState}, req.OperationAttributes[AttributeRequestedAttributes])
+	assert.NotContains(t, req.OperationAttributes, AttributeDocumentFormat)
+
+	req = NewRequest(OperationGetPrinterAttributes, 1, WithRequestedAttributes(AttributePrinterName),
+		WithGroups(RequestedJobTemplate, RequestedMediaColDatabase))
+	assert.Equal(t, []string{AttributePrinterName, RequestedJobTemplate, RequestedMediaColDatabase},
+		req.OperationAttributes[AttributeRequestedAttributes])
+	assert.Equal(t, -1, req.FileSize)
+}
+
 func TestRequest_Encode(t *testing.T) {
 	for _, c := range requestTestCases {
 		data, err := c.Request.Encode()
 		assert.Nil(t, err)
 		assert.Equal(t, c.Bytes, data, "encoded request is not correct")
 	}
+
+	// the request is not modified by encoding it, so it can be sent again
+	req := NewRequest(OperationGetJobAttributes, 1, WithPrinterURI("ipp://localhost/printers/office"), WithJobID(5))
+	first, err := req.Encode()
+	assert.Nil(t, err)
+	second, err := req.Encode()
+	assert.Nil(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 5, req.OperationAttributes[AttributeJobID])
+}
+
+func TestRequest_Validate(t *testing.T) {
+	printerURI := WithPrinterURI("ipp://localhost/printers/office")
+
+	tests := []struct {
+		req   *Request
+		valid bool
+	}{
+		{req: NewRequest(OperationPrintJob, 1, printerURI), valid: true},
+		{req: NewRequest(OperationPrintJob, 1, WithJobURI("ipp://localhost/jobs/1"))},
+		{req: NewRequest(OperationGetPrinterAttributes, 1)},
+		{req: NewRequest(OperationCancelJob, 1, WithJobURI("ipp://localhost/jobs/1")), valid: true},
+		{req: NewRequest(OperationCancelJob, 1, printerURI, WithJobID(1)), valid: true},
+		{req: NewRequest(OperationCancelJob, 1, printerURI)},
+		{req: NewRequest(OperationSendDocument, 1, WithJobID(1))},
+		{req: NewRequest(OperationCupsGetPrinters, 1), valid: true},
+	}
+
+	for _, test := range tests {
+		err := test.req.Validate()
+		if test.valid {
+			assert.Nil(t, err, Operation(test.req.Operation).String())
+		} else {
+			assert.True(t, errors.Is(err, MissingTargetError), Operation(test.req.Operation).String())
+		}
+	}
 }
 
 func TestRequestDecoder_Decode(t *testing.T) {
@@ -57,3 +131,54 @@ func TestRequestDecoder_Decode(t *testing.T) {
 		assert.Equal(t, &c.Request, request, "decoded request is not correct")
 	}
 }
+
+func TestRequestDecoder_DecodeSubscriptionAttributes(t *testing.T) {
+	req := NewRequest(OperationCreatePrinterSubscriptions, 1)
+	req.OperationAttributes[AttributePrinterURI] = "ipp://localhost/printers/test"
+	req.SubscriptionAttributes[AttributeNotifyEvents] = []string{EventPrinterStateChanged, EventJobCompleted}
+	req.SubscriptionAttributes[AttributeNotifyPullMethod] = PullMethodIppGet
+
+	data, err := req.Encode()
+	assert.Nil(t, err)
+
+	decoded, err := NewRequestDecoder(bytes.NewReader(data)).Decode(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{EventPrinterStateChanged, EventJobCompleted}, decoded.SubscriptionAttributes[AttributeNotifyEvents])
+	assert.Equal(t, PullMethodIppGet, decoded.SubscriptionAttributes[AttributeNotifyPullMethod])
+}
+
+func TestRequest_EncodeReusesBuffers(t *testing.T) {
+	first, err := NewRequest(OperationGetPrinterAttributes, 1, WithPrinterURI("ipp://printer/ipp/print")).Encode()
+	assert.Nil(t, err)
+	expected := bytes.Clone(first)
+
+	// the buffer of the first request is reused, the returned bytes must not change
+	_, err = NewRequest(OperationGetJobs, 2, WithPrinterURI("ipp://other/ipp/print")).Encode()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, first)
+
+	req, err := NewRequestDecoder(bytes.NewReader(first)).Decode(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, OperationGetPrinterAttributes, req.Operation)
+	assert.Equal(t, "ipp://printer/ipp/print", req.OperationAttributes[AttributePrinterURI])
+}
+
+func BenchmarkRequest_Encode(b *testing.B) {
+	req := NewRequest(OperationPrintJob, 1,
+		WithPrinterURI("ipp://printer/ipp/print"),
+		WithUser("alice"),
+		WithJobAttributes(map[string]interface{}{
+			AttributeJobName:   "report.pdf",
+			AttributeCopies:    2,
+			AttributeSides:     "two-sided-long-edge",
+			AttributeMedia:     "iso_a4_210x297mm",
+			AttributeJobSheets: "none",
+		}))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := req.Encode(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
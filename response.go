@@ -2,13 +2,32 @@ package ipp
 
 import (
 	"bytes"
-	"encoding/binary"
+	"context"
+	"fmt"
 	"io"
+	"slices"
 )
 
 // Attributes is a wrapper for a set of attributes
 type Attributes map[string][]Attribute
 
+// Set replaces the values of an attribute with the given values, all values are tagged with the given tag
+func (a Attributes) Set(name string, tag int8, values ...interface{}) {
+	attr := make([]Attribute, len(values))
+	for i, value := range values {
+		attr[i] = Attribute{Tag: tag, Name: name, Value: value}
+	}
+
+	a[name] = attr
+}
+
+// Add appends the given values to an attribute, all values are tagged with the given tag
+func (a Attributes) Add(name string, tag int8, values ...interface{}) {
+	for _, value := range values {
+		a[name] = append(a[name], Attribute{Tag: tag, Name: name, Value: value})
+	}
+}
+
 // Response defines a ipp response
 type Response struct {
 	ProtocolVersionMajor int8
@@ -17,21 +36,29 @@ type Response struct {
 	StatusCode int16
 	RequestId  int32
 
-	OperationAttributes Attributes
-	PrinterAttributes   []Attributes
-	JobAttributes       []Attributes
+	OperationAttributes         Attributes
+	UnsupportedAttributes       Attributes
+	PrinterAttributes           []Attributes
+	JobAttributes               []Attributes
+	SubscriptionAttributes      []Attributes
+	EventNotificationAttributes []Attributes
 }
 
-// CheckForErrors checks the status code and returns a error if it is not zero. it also returns the status message if provided by the server
+// CheckForErrors checks the status code and returns a error if it is not a successful status. successful-ok-ignored-or-substituted-attributes
+// and the other successful-ok codes are no errors, the ignored attributes are returned in the UnsupportedAttributes. it also returns the
+// status message if provided by the server
 func (r *Response) CheckForErrors() error {
-	if r.StatusCode != StatusOk {
-		err := IPPError{
+	if r.StatusCode < StatusOk || r.StatusCode >= StatusRedirectionOtherSite {
+		err := StatusError{
 			Status:  r.StatusCode,
 			Message: "no status message returned",
 		}
 
-		if len(r.OperationAttributes["status-message"]) > 0 {
-			err.Message = r.OperationAttributes["status-message"][0].Value.(string)
+		if len(r.OperationAttributes[AttributeStatusMessage]) > 0 {
+			err.Message, _ = r.OperationAttributes[AttributeStatusMessage][0].Value.(string)
+		}
+		if len(r.OperationAttributes[AttributeDetailedStatusMessage]) > 0 {
+			err.DetailedMessage, _ = r.OperationAttributes[AttributeDetailedStatusMessage][0].Value.(string)
 		}
 
 		return err
@@ -55,26 +82,15 @@ func NewResponse(statusCode int16, reqID int32) *Response {
 
 // Encode encodes the response to a byte slice
 func (r *Response) Encode() ([]byte, error) {
-	buf := new(bytes.Buffer)
-	enc := NewAttributeEncoder(buf)
-
-	if err := binary.Write(buf, binary.BigEndian, r.ProtocolVersionMajor); err != nil {
-		return nil, err
-	}
+	buf := getEncodeBuffer()
+	defer putEncodeBuffer(buf)
+	enc := &buf.encoder
 
-	if err := binary.Write(buf, binary.BigEndian, r.ProtocolVersionMinor); err != nil {
+	if err := enc.encodeHeader(r.ProtocolVersionMajor, r.ProtocolVersionMinor, r.StatusCode, r.RequestId); err != nil {
 		return nil, err
 	}
 
-	if err := binary.Write(buf, binary.BigEndian, r.StatusCode); err != nil {
-		return nil, err
-	}
-
-	if err := binary.Write(buf, binary.BigEndian, r.RequestId); err != nil {
-		return nil, err
-	}
-
-	if err := binary.Write(buf, binary.BigEndian, TagOperation); err != nil {
+	if err := enc.encodeTag(TagOperation); err != nil {
 		return nil, err
 	}
 
@@ -102,30 +118,27 @@ func (r *Response) Encode() ([]byte, error) {
 		return nil, err
 	}
 
+	if len(r.UnsupportedAttributes) > 0 {
+		if err := enc.encodeTag(TagUnsupportedGroup); err != nil {
+			return nil, err
+		}
+
+		for name, attr := range r.UnsupportedAttributes {
+			if err := encodeAttribute(enc, name, attr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	if len(r.PrinterAttributes) > 0 {
 		for _, printerAttr := range r.PrinterAttributes {
-			if err := binary.Write(buf, binary.BigEndian, TagPrinter); err != nil {
+			if err := enc.encodeTag(TagPrinter); err != nil {
 				return nil, err
 			}
 
 			for name, attr := range printerAttr {
-				if len(attr) == 0 {
-					continue
-				}
-
-				values := make([]interface{}, len(attr))
-				for i, v := range attr {
-					values[i] = v.Value
-				}
-
-				if len(values) == 1 {
-					if err := enc.Encode(name, values[0]); err != nil {
-						return nil, err
-					}
-				} else {
-					if err := enc.Encode(name, values); err != nil {
-						return nil, err
-					}
+				if err := encodeAttribute(enc, name, attr); err != nil {
+					return nil, err
 				}
 			}
 		}
@@ -133,38 +146,45 @@ func (r *Response) Encode() ([]byte, error) {
 
 	if len(r.JobAttributes) > 0 {
 		for _, jobAttr := range r.JobAttributes {
-			if err := binary.Write(buf, binary.BigEndian, TagJob); err != nil {
+			if err := enc.encodeTag(TagJob); err != nil {
 				return nil, err
 			}
 
 			for name, attr := range jobAttr {
-				if len(attr) == 0 {
-					continue
+				if err := encodeAttribute(enc, name, attr); err != nil {
+					return nil, err
 				}
+			}
+		}
+	}
 
-				values := make([]interface{}, len(attr))
-				for i, v := range attr {
-					values[i] = v.Value
-				}
+	groups := []struct {
+		tag        int8
+		attributes []Attributes
+	}{
+		{TagSubscription, r.SubscriptionAttributes},
+		{TagEventNotification, r.EventNotificationAttributes},
+	}
 
-				if len(values) == 1 {
-					if err := enc.Encode(name, values[0]); err != nil {
-						return nil, err
-					}
-				} else {
-					if err := enc.Encode(name, values); err != nil {
-						return nil, err
-					}
+	for _, group := range groups {
+		for _, attributes := range group.attributes {
+			if err := enc.encodeTag(group.tag); err != nil {
+				return nil, err
+			}
+
+			for name, attr := range attributes {
+				if err := encodeAttribute(enc, name, attr); err != nil {
+					return nil, err
 				}
 			}
 		}
 	}
 
-	if err := binary.Write(buf, binary.BigEndian, TagEnd); err != nil {
+	if err := enc.encodeTag(TagEnd); err != nil {
 		return nil, err
 	}
 
-	return buf.Bytes(), nil
+	return bytes.Clone(buf.Bytes()), nil
 }
 
 func (r *Response) encodeOperationAttributes(enc *AttributeEncoder) error {
@@ -177,15 +197,18 @@ func (r *Response) encodeOperationAttributes(enc *AttributeEncoder) error {
 
 	for _, name := range ordered {
 		if attr, ok := r.OperationAttributes[name]; ok {
-			delete(r.OperationAttributes, name)
-			if err := encodeOperationAttribute(enc, name, attr); err != nil {
+			if err := encodeAttribute(enc, name, attr); err != nil {
 				return err
 			}
 		}
 	}
 
+	// the response is left unchanged, so it can be encoded again, e.g. to compare it with a golden file
 	for name, attr := range r.OperationAttributes {
-		if err := encodeOperationAttribute(enc, name, attr); err != nil {
+		if slices.Contains(ordered, name) {
+			continue
+		}
+		if err := encodeAttribute(enc, name, attr); err != nil {
 			return err
 		}
 	}
@@ -193,26 +216,42 @@ func (r *Response) encodeOperationAttributes(enc *AttributeEncoder) error {
 	return nil
 }
 
-func encodeOperationAttribute(enc *AttributeEncoder, name string, attr []Attribute) error {
-	if len(attr) == 0 {
-		return nil
-	}
-
-	values := make([]interface{}, len(attr))
+// encodeAttribute encodes all values of an attribute with their tags. if the tag of a value is not set, the tag is
+// determined by the AttributeTagMapping map
+func encodeAttribute(enc *AttributeEncoder, name string, attr []Attribute) error {
 	for i, v := range attr {
-		values[i] = v.Value
-	}
+		tag := v.Tag
+		if tag == TagZero {
+			var ok bool
+			if tag, ok = AttributeTagMapping[name]; !ok {
+				return fmt.Errorf("%w: cannot get tag of attribute %s", InvalidTagError, name)
+			}
+		}
+
+		valueName := name
+		if i > 0 {
+			valueName = ""
+		}
 
-	if len(values) == 1 {
-		return enc.Encode(name, values[0])
+		if err := enc.EncodeWithTag(valueName, tag, v.Value); err != nil {
+			return err
+		}
 	}
 
-	return enc.Encode(name, values)
+	return nil
 }
 
 // ResponseDecoder reads and decodes a response from a stream
 type ResponseDecoder struct {
 	reader io.Reader
+	// InternValues decodes repeated keyword like values of the response into the same string, e.g. the
+	// job-state-reasons of a Get-Jobs response, see AttributeDecoder.InternValues
+	InternValues bool
+	// OnWarning receives the deviations from the specification the decoder tolerates, e.g. duplicate attributes or
+	// a missing end-of-attributes tag, see AttributeDecoder.OnWarning
+	OnWarning func(Warning)
+	// Profile selects whether deviations are reported to OnWarning or fail decoding, see DecodeProfile
+	Profile DecodeProfile
 }
 
 // NewResponseDecoder returns a new decoder that reads from r
@@ -224,6 +263,32 @@ func NewResponseDecoder(r io.Reader) *ResponseDecoder {
 
 // Decode decodes a ipp response into a response struct. additional data will be written to an io.Writer if data is not nil
 func (d *ResponseDecoder) Decode(data io.Writer) (*Response, error) {
+	return d.decode(data, nil)
+}
+
+// DecodeContext decodes a response like Decode, but stops with a ReadTimeoutError once the context is done. reads
+// from a stream with read deadlines like a net.Conn are interrupted, other readers are checked between reads
+func (d *ResponseDecoder) DecodeContext(ctx context.Context, data io.Writer) (*Response, error) {
+	reader := d.reader
+	defer func() { d.reader = reader }()
+
+	var release func(error) error
+	d.reader, release = withContext(ctx, reader)
+	resp, err := d.decode(data, nil)
+
+	return resp, release(err)
+}
+
+// DecodeFunc decodes a ipp response like Decode, but passes each printer, job, subscription and event notification
+// group to fn as soon as it is decoded instead of adding it to the response, so only one group is held in memory,
+// e.g. for a Get-Jobs response with thousands of jobs. the returned response holds the operation and unsupported
+// attributes. decoding stops with the error of fn
+func (d *ResponseDecoder) DecodeFunc(data io.Writer, fn func(tag int8, attributes Attributes) error) (*Response, error) {
+	return d.decode(data, fn)
+}
+
+// decode decodes the response, the object groups are passed to fn if it is not nil
+func (d *ResponseDecoder) decode(data io.Writer, fn func(tag int8, attributes Attributes) error) (*Response, error) {
 	/*
 	   1 byte: Protocol Major Version - b
 	   1 byte: Protocol Minor Version - b
@@ -239,19 +304,27 @@ func (d *ResponseDecoder) Decode(data io.Writer) (*Response, error) {
 	// wrap the reader so we have more functionality
 	// reader := bufio.NewReader(d.reader)
 
-	if err := binary.Read(d.reader, binary.BigEndian, &resp.ProtocolVersionMajor); err != nil {
+	attribDecoder := NewAttributeDecoder(d.reader)
+	if d.InternValues {
+		attribDecoder.InternValues()
+	}
+	attribDecoder.OnWarning(d.OnWarning)
+	attribDecoder.SetProfile(d.Profile)
+
+	var err error
+	if resp.ProtocolVersionMajor, err = attribDecoder.readInt8(); err != nil {
 		return nil, err
 	}
 
-	if err := binary.Read(d.reader, binary.BigEndian, &resp.ProtocolVersionMinor); err != nil {
+	if resp.ProtocolVersionMinor, err = attribDecoder.readInt8(); err != nil {
 		return nil, err
 	}
 
-	if err := binary.Read(d.reader, binary.BigEndian, &resp.StatusCode); err != nil {
+	if resp.StatusCode, err = attribDecoder.readInt16(); err != nil {
 		return nil, err
 	}
 
-	if err := binary.Read(d.reader, binary.BigEndian, &resp.RequestId); err != nil {
+	if resp.RequestId, err = attribDecoder.readInt32(); err != nil {
 		return nil, err
 	}
 
@@ -259,14 +332,15 @@ func (d *ResponseDecoder) Decode(data io.Writer) (*Response, error) {
 
 	tag := TagCupsInvalid
 	previousAttributeName := ""
+	// the number of operation attributes and whether the group has a named attribute, for the conformance checks
+	operationAttributes := 0
+	named := false
+	endTag := false
 	tempAttributes := make(Attributes)
-	tagSet := false
-
-	attribDecoder := NewAttributeDecoder(d.reader)
 
 	// decode attribute buffer
 	for {
-		if _, err := d.reader.Read(startByteSlice); err != nil {
+		if _, err := io.ReadFull(d.reader, startByteSlice); err != nil {
 			// when we read from a stream, we may get an EOF if we want to read the end tag
 			// all data should be read and we can ignore the error
 			if err == io.EOF {
@@ -279,63 +353,84 @@ func (d *ResponseDecoder) Decode(data io.Writer) (*Response, error) {
 
 		// check if attributes are completed
 		if startByte == TagEnd {
+			endTag = true
 			break
 		}
 
-		if startByte == TagOperation {
-			if len(tempAttributes) > 0 && tag != TagCupsInvalid {
-				appendAttributeToResponse(resp, tag, tempAttributes)
-				tempAttributes = make(Attributes)
+		// a delimiter tag starts the next group, the group may be empty
+		if startByte < TagUnsupportedValue {
+			switch startByte {
+			case TagOperation, TagUnsupportedGroup, TagJob, TagPrinter, TagSubscription, TagEventNotification:
+			default:
+				return nil, fmt.Errorf("%w: group tag 0x%02x in response", UnexpectedGroupError, uint8(startByte))
 			}
 
-			tag = TagOperation
-			tagSet = true
-		}
-
-		if startByte == TagJob {
-			if len(tempAttributes) > 0 && tag != TagCupsInvalid {
-				appendAttributeToResponse(resp, tag, tempAttributes)
+			if len(tempAttributes) > 0 {
+				if err := addGroupToResponse(resp, tag, tempAttributes, fn); err != nil {
+					return nil, err
+				}
 				tempAttributes = make(Attributes)
 			}
 
-			tag = TagJob
-			tagSet = true
-		}
-
-		if startByte == TagPrinter {
-			if len(tempAttributes) > 0 && tag != TagCupsInvalid {
-				appendAttributeToResponse(resp, tag, tempAttributes)
-				tempAttributes = make(Attributes)
+			if tag == TagCupsInvalid && startByte != TagOperation && attribDecoder.checking() {
+				err := attribDecoder.messageDeviation("the first group is 0x%02x instead of the operation attributes",
+					uint8(startByte))
+				if err != nil {
+					return nil, err
+				}
 			}
 
-			tag = TagPrinter
-			tagSet = true
+			tag = startByte
+			named = false
+			continue
 		}
 
-		if tagSet {
-			if _, err := d.reader.Read(startByteSlice); err != nil {
-				return nil, err
-			}
-			startByte = int8(startByteSlice[0])
+		if tag == TagCupsInvalid {
+			return nil, fmt.Errorf("%w: attribute before the operation attributes", UnexpectedGroupError)
 		}
 
-		attrib, err := attribDecoder.Decode(startByte)
+		attrib, err := attribDecoder.decode(startByte)
 		if err != nil {
 			return nil, err
 		}
 
 		if attrib.Name != "" {
-			tempAttributes[attrib.Name] = append(tempAttributes[attrib.Name], *attrib)
+			if attribDecoder.checking() {
+				if tag == TagOperation {
+					if err := attribDecoder.checkOperationAttribute(operationAttributes, attrib.Name); err != nil {
+						return nil, err
+					}
+					operationAttributes++
+				}
+				if _, ok := tempAttributes[attrib.Name]; ok {
+					if err := attribDecoder.deviation("duplicate attribute, the values are merged"); err != nil {
+						return nil, err
+					}
+				}
+			}
+			named = true
+			tempAttributes[attrib.Name] = append(tempAttributes[attrib.Name], attrib)
 			previousAttributeName = attrib.Name
 		} else {
-			tempAttributes[previousAttributeName] = append(tempAttributes[previousAttributeName], *attrib)
+			if !named && attribDecoder.checking() {
+				if err := attribDecoder.messageDeviation("value without attribute name at the start of a group"); err != nil {
+					return nil, err
+				}
+			}
+			tempAttributes[previousAttributeName] = append(tempAttributes[previousAttributeName], attrib)
 		}
+	}
 
-		tagSet = false
+	if attribDecoder.checking() {
+		if err := attribDecoder.checkMessage(operationAttributes, endTag); err != nil {
+			return nil, err
+		}
 	}
 
-	if len(tempAttributes) > 0 && tag != TagCupsInvalid {
-		appendAttributeToResponse(resp, tag, tempAttributes)
+	if len(tempAttributes) > 0 {
+		if err := addGroupToResponse(resp, tag, tempAttributes, fn); err != nil {
+			return nil, err
+		}
 	}
 
 	if data != nil {
@@ -347,13 +442,29 @@ func (d *ResponseDecoder) Decode(data io.Writer) (*Response, error) {
 	return resp, nil
 }
 
+// addGroupToResponse adds a decoded group to the response or passes an object group to fn if it is not nil
+func addGroupToResponse(resp *Response, tag int8, attr Attributes, fn func(tag int8, attributes Attributes) error) error {
+	if fn == nil || tag == TagOperation || tag == TagUnsupportedGroup {
+		appendAttributeToResponse(resp, tag, attr)
+		return nil
+	}
+
+	return fn(tag, attr)
+}
+
 func appendAttributeToResponse(resp *Response, tag int8, attr map[string][]Attribute) {
 	switch tag {
 	case TagOperation:
 		resp.OperationAttributes = attr
+	case TagUnsupportedGroup:
+		resp.UnsupportedAttributes = attr
 	case TagPrinter:
 		resp.PrinterAttributes = append(resp.PrinterAttributes, attr)
 	case TagJob:
 		resp.JobAttributes = append(resp.JobAttributes, attr)
+	case TagSubscription:
+		resp.SubscriptionAttributes = append(resp.SubscriptionAttributes, attr)
+	case TagEventNotification:
+		resp.EventNotificationAttributes = append(resp.EventNotificationAttributes, attr)
 	}
 }
@@ -2,6 +2,8 @@ package ipp
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -67,3 +69,158 @@ func TestResponseDecoder_Decode(t *testing.T) {
 		assert.Equal(t, &c.Response, response, "decoded response is not correct")
 	}
 }
+
+func TestResponseDecoder_DecodeNotifications(t *testing.T) {
+	resp := NewResponse(StatusOk, 1)
+	resp.SubscriptionAttributes = []Attributes{{}}
+	resp.SubscriptionAttributes[0].Set(AttributeNotifySubscriptionID, TagInteger, 3)
+	for i := 1; i <= 2; i++ {
+		event := make(Attributes)
+		event.Set(AttributeNotifySequenceNumber, TagInteger, i)
+		event.Set(AttributeNotifySubscribedEvent, TagKeyword, EventJobStateChanged)
+		resp.EventNotificationAttributes = append(resp.EventNotificationAttributes, event)
+	}
+
+	data, err := resp.Encode()
+	assert.Nil(t, err)
+
+	decoded, err := NewResponseDecoder(bytes.NewReader(data)).Decode(nil)
+	assert.Nil(t, err)
+	assert.Len(t, decoded.SubscriptionAttributes, 1)
+	assert.Equal(t, 3, decoded.SubscriptionAttributes[0][AttributeNotifySubscriptionID][0].Value)
+	assert.Len(t, decoded.EventNotificationAttributes, 2)
+	assert.Equal(t, 2, decoded.EventNotificationAttributes[1][AttributeNotifySequenceNumber][0].Value)
+}
+
+// benchmarkResponse returns a Get-Printer-Attributes response with the attributes of a typical printer
+func benchmarkResponse() *Response {
+	resp := NewResponse(StatusOk, 1)
+	attributes := make(Attributes)
+	attributes.Set(AttributePrinterName, TagName, "office")
+	attributes.Set(AttributePrinterMakeAndModel, TagText, "Office Laser 4000")
+	attributes.Set(AttributePrinterUriSupported, TagUri, "ipp://printer/ipp/print", "ipps://printer/ipp/print")
+	attributes.Set(AttributePrinterState, TagEnum, int(PrinterStateIdle))
+	attributes.Set(AttributePrinterStateReasons, TagKeyword, "none")
+	attributes.Set(AttributePrinterIsAcceptingJobs, TagBoolean, true)
+	attributes.Set(AttributeOperationsSupported, TagEnum, int(OperationPrintJob), int(OperationValidateJob),
+		int(OperationCreateJob), int(OperationSendDocument), int(OperationCancelJob), int(OperationGetJobAttributes),
+		int(OperationGetJobs), int(OperationGetPrinterAttributes))
+	attributes.Set(AttributeDocumentFormatSupported, TagMimeType, "application/pdf", "image/jpeg", "image/pwg-raster",
+		"image/urf", "application/octet-stream")
+	attributes.Set(AttributeMediaSupported, TagKeyword, "iso_a4_210x297mm", "iso_a5_148x210mm", "na_letter_8.5x11in",
+		"na_legal_8.5x14in", "iso_dl_110x220mm")
+	attributes.Set(AttributeCopiesSupported, TagRange, []int32{1, 999})
+	attributes.Set(AttributePrinterResolutionSupported, TagResolution,
+		Resolution{Height: 300, Width: 300, Depth: 3}, Resolution{Height: 600, Width: 600, Depth: 3})
+	attributes.Set(AttributeMediaColDefault, TagBeginCollection, Attributes{
+		"media-size": {{Tag: TagBeginCollection, Value: Attributes{
+			"x-dimension": {{Tag: TagInteger, Value: 21000}},
+			"y-dimension": {{Tag: TagInteger, Value: 29700}},
+		}}},
+		"media-type": {{Tag: TagKeyword, Value: "stationery"}},
+	})
+	resp.PrinterAttributes = append(resp.PrinterAttributes, attributes)
+
+	return resp
+}
+
+func BenchmarkResponse_Encode(b *testing.B) {
+	resp := benchmarkResponse()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := resp.Encode(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkResponseDecoder_Decode(b *testing.B) {
+	data, err := benchmarkResponse().Encode()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+
+	for i := 0; i < b.N; i++ {
+		if _, err := NewResponseDecoder(bytes.NewReader(data)).Decode(nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkResponseDecoder_DecodeJobs(b *testing.B) {
+	resp := NewResponse(StatusOk, 1)
+	for id := 1; id <= 100; id++ {
+		job := make(Attributes)
+		job.Set(AttributeJobID, TagInteger, id)
+		job.Set(AttributeJobName, TagName, "report.pdf")
+		job.Set(AttributeJobOriginatingUserName, TagName, "alice")
+		job.Set(AttributeJobState, TagEnum, int(JobStateCompleted))
+		job.Set(AttributeJobStateReasons, TagKeyword, "job-completed-successfully")
+		job.Set(AttributeDocumentFormat, TagMimeType, MimeTypePDF)
+		job.Set(AttributeMedia, TagKeyword, "iso_a4_210x297mm")
+		job.Set(AttributeSides, TagKeyword, "two-sided-long-edge")
+		resp.JobAttributes = append(resp.JobAttributes, job)
+	}
+
+	data, err := resp.Encode()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, intern := range []bool{false, true} {
+		b.Run(fmt.Sprintf("intern=%t", intern), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+
+			for i := 0; i < b.N; i++ {
+				decoder := NewResponseDecoder(bytes.NewReader(data))
+				decoder.InternValues = intern
+				if _, err := decoder.Decode(nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestResponseDecoder_DecodeFunc(t *testing.T) {
+	resp := NewResponse(StatusOk, 1)
+	resp.OperationAttributes.Set(AttributeStatusMessage, TagText, "ok")
+	for id := 1; id <= 3; id++ {
+		job := make(Attributes)
+		job.Set(AttributeJobID, TagInteger, id)
+		job.Set(AttributeJobStateReasons, TagKeyword, "job-completed-successfully", "job-printing")
+		resp.JobAttributes = append(resp.JobAttributes, job)
+	}
+	data, err := resp.Encode()
+	assert.Nil(t, err)
+	data = append(data, "document"...)
+
+	var ids []interface{}
+	var document bytes.Buffer
+	decoded, err := NewResponseDecoder(bytes.NewReader(data)).DecodeFunc(&document, func(tag int8, attributes Attributes) error {
+		assert.Equal(t, TagJob, tag)
+		assert.Len(t, attributes[AttributeJobStateReasons], 2)
+		ids = append(ids, attributes[AttributeJobID][0].Value)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{1, 2, 3}, ids)
+	// the groups passed to the function are not kept
+	assert.Empty(t, decoded.JobAttributes)
+	assert.Equal(t, "ok", decoded.OperationAttributes[AttributeStatusMessage][0].Value)
+	assert.Equal(t, "document", document.String())
+
+	// the error of the function stops decoding
+	stop := errors.New("stop")
+	ids = nil
+	_, err = NewResponseDecoder(bytes.NewReader(data)).DecodeFunc(nil, func(tag int8, attributes Attributes) error {
+		ids = append(ids, attributes[AttributeJobID][0].Value)
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, []interface{}{1}, ids)
+}
@@ -0,0 +1,223 @@
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// AccountingRecord describes a terminated job for chargeback and quota systems
+type AccountingRecord struct {
+	JobID   int
+	JobUUID string
+	JobName string
+	User    string
+	Printer string
+	// State is the final job state, completed, canceled or aborted
+	State string
+	// Impressions is the job-impressions-completed of the job, it is only counted by sinks which increment the
+	// impressions of the job like RasterSink and PageCountSink
+	Impressions int
+	Copies      int
+	Documents   int
+	// Bytes is the total size of the received document data
+	Bytes       int64
+	CreatedAt   time.Time
+	CompletedAt time.Time
+	// Duration is the time from the start of the processing until the job terminated
+	Duration time.Duration
+}
+
+// AccountingHook receives an accounting record for each terminated job. errors are not reported to clients, hooks
+// which must not lose records have to retry or buffer them
+type AccountingHook interface {
+	Record(record AccountingRecord) error
+}
+
+// AccountingHookFunc is a function which implements the AccountingHook interface
+type AccountingHookFunc func(record AccountingRecord) error
+
+// Record calls the function
+func (f AccountingHookFunc) Record(record AccountingRecord) error {
+	return f(record)
+}
+
+// AccountingHooks passes the records to multiple hooks, the first error is returned after all hooks are called
+type AccountingHooks []AccountingHook
+
+// Record passes the record to all hooks
+func (h AccountingHooks) Record(record AccountingRecord) error {
+	var first error
+	for _, hook := range h {
+		if err := hook.Record(record); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+// NewAccountingRecord creates the accounting record of a terminated job of the printer with the given name
+func NewAccountingRecord(job *Job, printer string) AccountingRecord {
+	record := AccountingRecord{
+		JobID:       job.ID,
+		JobUUID:     job.UUID,
+		JobName:     job.Name,
+		User:        job.OriginatingUser,
+		Printer:     printer,
+		State:       ipp.JobState(job.State).String(),
+		Impressions: job.Impressions,
+		Copies:      1,
+		Documents:   job.NumberOfDocuments,
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: job.CompletedAt,
+	}
+
+	if attr := job.Attributes[ipp.AttributeCopies]; len(attr) > 0 {
+		if copies, ok := attr[0].Value.(int); ok && copies > 0 {
+			record.Copies = copies
+		}
+	}
+
+	for _, doc := range job.Documents {
+		record.Bytes += doc.Size
+	}
+
+	if !job.ProcessingAt.IsZero() && job.CompletedAt.After(job.ProcessingAt) {
+		record.Duration = job.CompletedAt.Sub(job.ProcessingAt)
+	}
+
+	return record
+}
+
+// jsonAccountingRecord is the json lines representation of an accounting record
+type jsonAccountingRecord struct {
+	JobID       int       `json:"job_id"`
+	JobUUID     string    `json:"job_uuid"`
+	JobName     string    `json:"job_name"`
+	User        string    `json:"user"`
+	Printer     string    `json:"printer"`
+	State       string    `json:"state"`
+	Impressions int       `json:"impressions"`
+	Copies      int       `json:"copies"`
+	Documents   int       `json:"documents"`
+	Bytes       int64     `json:"bytes"`
+	CreatedAt   time.Time `json:"created_at"`
+	CompletedAt time.Time `json:"completed_at"`
+	Duration    float64   `json:"duration_seconds"`
+}
+
+// JSONLinesAccounting is an AccountingHook which writes each record as a json object on a separate line
+type JSONLinesAccounting struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONLinesAccounting creates a hook which writes json lines to w
+func NewJSONLinesAccounting(w io.Writer) *JSONLinesAccounting {
+	return &JSONLinesAccounting{enc: json.NewEncoder(w)}
+}
+
+// Record writes the record as json line
+func (a *JSONLinesAccounting) Record(record AccountingRecord) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.enc.Encode(jsonAccountingRecord{
+		JobID:       record.JobID,
+		JobUUID:     record.JobUUID,
+		JobName:     record.JobName,
+		User:        record.User,
+		Printer:     record.Printer,
+		State:       record.State,
+		Impressions: record.Impressions,
+		Copies:      record.Copies,
+		Documents:   record.Documents,
+		Bytes:       record.Bytes,
+		CreatedAt:   record.CreatedAt,
+		CompletedAt: record.CompletedAt,
+		Duration:    record.Duration.Seconds(),
+	})
+}
+
+// CSVAccountingHeader are the column names written by a CSVAccounting
+var CSVAccountingHeader = []string{
+	"job_id", "job_uuid", "job_name", "user", "printer", "state", "impressions", "copies", "documents", "bytes",
+	"created_at", "completed_at", "duration_seconds",
+}
+
+// CSVAccounting is an AccountingHook which writes each record as a csv row. the header row is written before the
+// first record unless SkipHeader is set, e.g. when appending to an existing file
+type CSVAccounting struct {
+	SkipHeader bool
+
+	mu     sync.Mutex
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSVAccounting creates a hook which writes csv rows to w
+func NewCSVAccounting(w io.Writer) *CSVAccounting {
+	return &CSVAccounting{w: csv.NewWriter(w)}
+}
+
+// Record writes the record as csv row
+func (a *CSVAccounting) Record(record AccountingRecord) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.header && !a.SkipHeader {
+		if err := a.w.Write(CSVAccountingHeader); err != nil {
+			return err
+		}
+	}
+	a.header = true
+
+	row := []string{
+		strconv.Itoa(record.JobID),
+		record.JobUUID,
+		record.JobName,
+		record.User,
+		record.Printer,
+		record.State,
+		strconv.Itoa(record.Impressions),
+		strconv.Itoa(record.Copies),
+		strconv.Itoa(record.Documents),
+		strconv.FormatInt(record.Bytes, 10),
+		record.CreatedAt.Format(time.RFC3339),
+		record.CompletedAt.Format(time.RFC3339),
+		strconv.FormatFloat(record.Duration.Seconds(), 'f', 3, 64),
+	}
+
+	if err := a.w.Write(row); err != nil {
+		return err
+	}
+
+	a.w.Flush()
+	return a.w.Error()
+}
+
+// finishJob publishes the job-completed event of a terminated job and passes its accounting record to the hook and
+// the quota. the expired documents of the archive are removed
+func (p *VirtualPrinter) finishJob(job *Job) {
+	p.publishJobEvent(ipp.EventJobCompleted, job)
+	p.purgeArchive()
+
+	if p.Accounting == nil && p.Quota == nil {
+		return
+	}
+
+	record := NewAccountingRecord(job, p.name)
+	if p.Accounting != nil {
+		// errors are handled by the hook, see AccountingHook
+		_ = p.Accounting.Record(record)
+	}
+	if p.Quota != nil {
+		_ = p.Quota.Record(record)
+	}
+}
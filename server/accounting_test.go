@@ -0,0 +1,121 @@
+package server
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVirtualPrinter_Accounting(t *testing.T) {
+	var records []AccountingRecord
+
+	printer := NewVirtualPrinter("office", nil)
+	printer.Accounting = AccountingHookFunc(func(record AccountingRecord) error {
+		records = append(records, record)
+		return nil
+	})
+
+	s := NewServer()
+	printer.Register(s, "/ipp/print")
+
+	req := newPrinterRequest(ipp.OperationPrintJob, "ipp://localhost/ipp/print")
+	req.OperationAttributes[ipp.AttributeRequestingUserName] = "alice"
+	req.OperationAttributes[ipp.AttributeJobName] = "report"
+	req.JobAttributes[ipp.AttributeCopies] = 2
+	resp := serveTestDocument(t, s, "/ipp/print", req, []byte("document data"))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+
+	assert.Len(t, records, 1)
+	record := records[0]
+	assert.Equal(t, "alice", record.User)
+	assert.Equal(t, "report", record.JobName)
+	assert.Equal(t, "office", record.Printer)
+	assert.Equal(t, "completed", record.State)
+	assert.Equal(t, 2, record.Copies)
+	assert.Equal(t, 1, record.Documents)
+	assert.Equal(t, int64(len("document data")), record.Bytes)
+	assert.False(t, record.CompletedAt.IsZero())
+
+	req = newPrinterRequest(ipp.OperationCreateJob, "ipp://localhost/ipp/print")
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Len(t, records, 1)
+
+	req = newPrinterRequest(ipp.OperationCancelJob, "ipp://localhost/ipp/print")
+	req.OperationAttributes[ipp.AttributeJobID] = resp.JobAttributes[0][ipp.AttributeJobID][0].Value
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+
+	assert.Len(t, records, 2)
+	assert.Equal(t, "canceled", records[1].State)
+	assert.Equal(t, 0, records[1].Documents)
+}
+
+func TestAccountingHooks(t *testing.T) {
+	var calls int
+	failing := errors.New("failing")
+
+	hooks := AccountingHooks{
+		AccountingHookFunc(func(record AccountingRecord) error {
+			calls++
+			return failing
+		}),
+		AccountingHookFunc(func(record AccountingRecord) error {
+			calls++
+			return nil
+		}),
+	}
+
+	assert.Equal(t, failing, hooks.Record(AccountingRecord{}))
+	assert.Equal(t, 2, calls)
+}
+
+func TestJSONLinesAccounting(t *testing.T) {
+	var buf bytes.Buffer
+	hook := NewJSONLinesAccounting(&buf)
+
+	completed := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	assert.Nil(t, hook.Record(AccountingRecord{JobID: 1, User: "alice", State: "completed", CompletedAt: completed,
+		Duration: 1500 * time.Millisecond}))
+	assert.Nil(t, hook.Record(AccountingRecord{JobID: 2, User: "bob", State: "aborted"}))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+
+	var line map[string]interface{}
+	assert.Nil(t, json.Unmarshal(lines[0], &line))
+	assert.Equal(t, 1.0, line["job_id"])
+	assert.Equal(t, "alice", line["user"])
+	assert.Equal(t, "2020-05-01T12:00:00Z", line["completed_at"])
+	assert.Equal(t, 1.5, line["duration_seconds"])
+}
+
+func TestCSVAccounting(t *testing.T) {
+	var buf bytes.Buffer
+	hook := NewCSVAccounting(&buf)
+
+	assert.Nil(t, hook.Record(AccountingRecord{JobID: 1, User: "alice", State: "completed", Copies: 2, Bytes: 1024}))
+	assert.Nil(t, hook.Record(AccountingRecord{JobID: 2, User: "bob, jr.", State: "canceled"}))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, CSVAccountingHeader, rows[0])
+	assert.Equal(t, []string{"1", "", "", "alice", "", "completed", "0", "2", "0", "1024"}, rows[1][:10])
+	assert.Equal(t, "bob, jr.", rows[2][3])
+
+	buf.Reset()
+	hook = NewCSVAccounting(&buf)
+	hook.SkipHeader = true
+	assert.Nil(t, hook.Record(AccountingRecord{JobID: 3}))
+
+	rows, err = csv.NewReader(&buf).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, rows, 1)
+}
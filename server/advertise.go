@@ -0,0 +1,100 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/dnssd"
+)
+
+// dns-sd subtypes used by driverless clients, _universal is browsed by airprint and _print by mopria and windows
+var PrinterServiceSubtypes = []string{"_universal", "_print"}
+
+// Service returns the dns-sd service which advertises the printer registered at path on the given port. if secure is
+// true the printer is advertised as _ipps._tcp
+func (p *VirtualPrinter) Service(path string, port int, secure bool) *dnssd.Service {
+	serviceType := dnssd.ServiceTypeIPP
+	if secure {
+		serviceType = dnssd.ServiceTypeIPPS
+	}
+
+	return &dnssd.Service{
+		Instance: p.name,
+		Type:     serviceType,
+		Subtypes: PrinterServiceSubtypes,
+		Port:     port,
+		Text:     p.TXTRecord(path, secure),
+	}
+}
+
+// Advertise registers the dns-sd service of the printer on the responder
+func (p *VirtualPrinter) Advertise(responder *dnssd.Responder, path string, port int, secure bool) (*dnssd.Service, error) {
+	service := p.Service(path, port, secure)
+	if err := responder.Register(service); err != nil {
+		return nil, err
+	}
+
+	return service, nil
+}
+
+// TXTRecord generates the ipp everywhere txt record of the printer registered at path from the printer attributes
+func (p *VirtualPrinter) TXTRecord(path string, secure bool) map[string]string {
+	makeAndModel := firstString(p.Attributes, ipp.AttributePrinterMakeAndModel)
+
+	txt := map[string]string{
+		"txtvers": "1",
+		"qtotal":  "1",
+		"rp":      strings.TrimPrefix(cleanPath(path), "/"),
+		"ty":      makeAndModel,
+		"product": "(" + makeAndModel + ")",
+		"note":    firstString(p.Attributes, ipp.AttributePrinterLocation),
+		"pdl":     strings.Join(p.DocumentFormats, ","),
+		"UUID":    strings.TrimPrefix(p.uuid, "urn:uuid:"),
+		"Color":   "F",
+		"Duplex":  "F",
+		"URF":     "none",
+	}
+
+	if secure {
+		txt["TLS"] = "1.2"
+	}
+
+	if moreInfo := firstString(p.Attributes, ipp.AttributePrinterMoreInfo); moreInfo != "" {
+		txt["adminurl"] = moreInfo
+	}
+
+	if attr := p.Attributes[ipp.AttributeColorSupported]; len(attr) > 0 && attr[0].Value == true {
+		txt["Color"] = "T"
+	}
+
+	for _, side := range attributeStrings(p.Attributes[ipp.AttributeSidesSupported]) {
+		if strings.HasPrefix(side, "two-sided") {
+			txt["Duplex"] = "T"
+		}
+	}
+
+	if urf := attributeStrings(p.Attributes[ipp.AttributeUrfSupported]); len(urf) > 0 {
+		txt["URF"] = strings.Join(urf, ",")
+	}
+
+	return txt
+}
+
+func firstString(attributes ipp.Attributes, name string) string {
+	if values := attributeStrings(attributes[name]); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+func attributeStrings(attr []ipp.Attribute) []string {
+	values := make([]string, 0, len(attr))
+	for _, a := range attr {
+		if s, ok := a.Value.(string); ok {
+			values = append(values, s)
+		}
+	}
+
+	return values
+}
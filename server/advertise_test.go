@@ -0,0 +1,38 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/dnssd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVirtualPrinter_TXTRecord(t *testing.T) {
+	printer := NewVirtualPrinter("Office", nil)
+	printer.Attributes.Set(ipp.AttributePrinterMakeAndModel, ipp.TagText, "Example Laser 100")
+	printer.Attributes.Set(ipp.AttributePrinterLocation, ipp.TagText, "2nd floor")
+	printer.Attributes.Set(ipp.AttributeColorSupported, ipp.TagBoolean, true)
+	printer.Attributes.Set(ipp.AttributeSidesSupported, ipp.TagKeyword, "one-sided", "two-sided-long-edge")
+	printer.Attributes.Set(ipp.AttributeUrfSupported, ipp.TagKeyword, "V1.4", "W8", "RS300")
+
+	txt := printer.TXTRecord("/ipp/print/", true)
+	assert.Equal(t, "ipp/print", txt["rp"])
+	assert.Equal(t, "Example Laser 100", txt["ty"])
+	assert.Equal(t, "(Example Laser 100)", txt["product"])
+	assert.Equal(t, "2nd floor", txt["note"])
+	assert.Equal(t, "application/pdf,image/pwg-raster,image/jpeg,application/octet-stream", txt["pdl"])
+	assert.Equal(t, "V1.4,W8,RS300", txt["URF"])
+	assert.Equal(t, "T", txt["Color"])
+	assert.Equal(t, "T", txt["Duplex"])
+	assert.Equal(t, "1.2", txt["TLS"])
+	assert.Equal(t, strings.TrimPrefix(printer.UUID(), "urn:uuid:"), txt["UUID"])
+
+	service := printer.Service("/ipp/print", 631, false)
+	assert.Equal(t, dnssd.ServiceTypeIPP, service.Type)
+	assert.Equal(t, []string{"_universal", "_print"}, service.Subtypes)
+	_, ok := service.Text["TLS"]
+	assert.False(t, ok)
+	assert.Equal(t, "Office._ipp._tcp.local.", service.InstanceName())
+}
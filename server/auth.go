@@ -0,0 +1,464 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+var (
+	InvalidCredentialsError = errors.New("invalid credentials")
+	AccessDeniedError       = errors.New("access denied")
+	ForbiddenError          = errors.New("forbidden")
+)
+
+// authentication schemes of a User
+const (
+	AuthSchemeBasic       = "basic"
+	AuthSchemeDigest      = "digest"
+	AuthSchemeBearer      = "bearer"
+	AuthSchemeCertificate = "certificate"
+)
+
+// User defines the authenticated user of a request
+type User struct {
+	Name   string
+	Groups []string
+	// Scheme is the authentication scheme used by the client, e.g. AuthSchemeBasic
+	Scheme string
+}
+
+// InGroup checks if the user is a member of one of the groups
+func (u *User) InGroup(groups ...string) bool {
+	for _, group := range groups {
+		if indexOf(u.Groups, group) >= 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Authenticator authenticates the http request of an ipp request. Authenticate returns nil without an error if the
+// request does not contain credentials for the scheme of the authenticator and InvalidCredentialsError if the
+// credentials are wrong. Challenge returns the WWW-Authenticate header value which is sent to clients which need to
+// authenticate, an empty challenge omits the header
+type Authenticator interface {
+	Authenticate(r *http.Request) (*User, error)
+	Challenge() string
+}
+
+// Authorizer decides if a request may be served. it is called after the request is routed, so the operation, the
+// target endpoint and the authenticated user are known. authorizers can be set on the server and on each endpoint.
+// returning AccessDeniedError responds with client-error-not-authenticated for anonymous requests and
+// client-error-not-authorized for authenticated users, ForbiddenError responds with client-error-forbidden. an
+// ipp.StatusError is sent as is
+type Authorizer interface {
+	Authorize(req *Request) error
+}
+
+// AuthorizerFunc is a function which implements the Authorizer interface
+type AuthorizerFunc func(req *Request) error
+
+// Authorize calls the function
+func (f AuthorizerFunc) Authorize(req *Request) error {
+	return f(req)
+}
+
+// Authorizers combines multiple authorizers, a request is authorized if none of the authorizers denies it
+type Authorizers []Authorizer
+
+// Authorize calls all authorizers and returns the first error
+func (a Authorizers) Authorize(req *Request) error {
+	for _, authorizer := range a {
+		if err := authorizer.Authorize(req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RequireAuthentication is an Authorizer which denies all anonymous requests
+var RequireAuthentication Authorizer = AuthorizerFunc(func(req *Request) error {
+	if req.User == nil {
+		return AccessDeniedError
+	}
+
+	return nil
+})
+
+// RequireGroup returns an Authorizer which allows the operations only for members of one of the groups. other
+// operations are not restricted
+func RequireGroup(operations []int16, groups ...string) Authorizer {
+	return AuthorizerFunc(func(req *Request) error {
+		if !containsOperation(operations, req.Operation) {
+			return nil
+		}
+
+		if req.User == nil || !req.User.InGroup(groups...) {
+			return AccessDeniedError
+		}
+
+		return nil
+	})
+}
+
+// Authenticators combines multiple authenticators, the user of the first authenticator which finds credentials is used
+type Authenticators []Authenticator
+
+// Authenticate tries all authenticators in order
+func (a Authenticators) Authenticate(r *http.Request) (*User, error) {
+	for _, authenticator := range a {
+		user, err := authenticator.Authenticate(r)
+		if err != nil || user != nil {
+			return user, err
+		}
+	}
+
+	return nil, nil
+}
+
+// Challenge joins the challenges of all authenticators
+func (a Authenticators) Challenge() string {
+	challenges := make([]string, 0, len(a))
+	for _, authenticator := range a {
+		if challenge := authenticator.Challenge(); challenge != "" {
+			challenges = append(challenges, challenge)
+		}
+	}
+
+	return strings.Join(challenges, ", ")
+}
+
+// BasicAuthenticator authenticates users with http basic authentication
+type BasicAuthenticator struct {
+	Realm string
+	// Verify checks the password of the user
+	Verify func(username, password string) bool
+	// Groups returns the groups of an authenticated user, optional
+	Groups func(username string) []string
+}
+
+// Authenticate verifies the basic credentials of the request
+func (a *BasicAuthenticator) Authenticate(r *http.Request) (*User, error) {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return nil, nil
+	}
+
+	if !a.Verify(username, password) {
+		return nil, InvalidCredentialsError
+	}
+
+	return newUser(username, AuthSchemeBasic, a.Groups), nil
+}
+
+// Challenge returns the basic challenge of the realm
+func (a *BasicAuthenticator) Challenge() string {
+	return fmt.Sprintf("Basic realm=%q", a.Realm)
+}
+
+// BearerAuthenticator authenticates users with bearer tokens, e.g. oauth access tokens
+type BearerAuthenticator struct {
+	Realm string
+	// Verify validates the token and returns the user it was issued to
+	Verify func(token string) (*User, error)
+}
+
+// Authenticate verifies the bearer token of the request
+func (a *BearerAuthenticator) Authenticate(r *http.Request) (*User, error) {
+	scheme, token := authorizationHeader(r)
+	if !strings.EqualFold(scheme, "bearer") {
+		return nil, nil
+	}
+
+	user, err := a.Verify(token)
+	if err != nil || user == nil {
+		return nil, InvalidCredentialsError
+	}
+
+	user.Scheme = AuthSchemeBearer
+	return user, nil
+}
+
+// Challenge returns the bearer challenge of the realm
+func (a *BearerAuthenticator) Challenge() string {
+	return fmt.Sprintf("Bearer realm=%q", a.Realm)
+}
+
+// CertificateAuthenticator authenticates users by the tls client certificate. the certificate chain must be verified
+// by the tls configuration of the http server, e.g. with tls.RequireAndVerifyClientCert
+type CertificateAuthenticator struct {
+	// Verify maps the certificate to a user, the common name of the subject is used as user name if nil
+	Verify func(cert *x509.Certificate) (*User, error)
+}
+
+// Authenticate returns the user of the peer certificate of the request
+func (a *CertificateAuthenticator) Authenticate(r *http.Request) (*User, error) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil, nil
+	}
+
+	cert := r.TLS.PeerCertificates[0]
+	if a.Verify == nil {
+		if cert.Subject.CommonName == "" {
+			return nil, InvalidCredentialsError
+		}
+		return &User{Name: cert.Subject.CommonName, Scheme: AuthSchemeCertificate}, nil
+	}
+
+	user, err := a.Verify(cert)
+	if err != nil || user == nil {
+		return nil, InvalidCredentialsError
+	}
+
+	user.Scheme = AuthSchemeCertificate
+	return user, nil
+}
+
+// Challenge returns an empty challenge, certificates are requested during the tls handshake
+func (a *CertificateAuthenticator) Challenge() string {
+	return ""
+}
+
+// digestNonceLifetime is the time a digest nonce is accepted after it was issued
+const digestNonceLifetime = 5 * time.Minute
+
+// DigestAuthenticator authenticates users with http digest authentication (rfc 7616) using md5 and qop auth
+type DigestAuthenticator struct {
+	Realm string
+	// Password returns the password of the user, false if the user does not exist
+	Password func(username string) (string, bool)
+	// Groups returns the groups of an authenticated user, optional
+	Groups func(username string) []string
+
+	once   sync.Once
+	secret []byte
+}
+
+// Authenticate verifies the digest response of the request
+func (a *DigestAuthenticator) Authenticate(r *http.Request) (*User, error) {
+	scheme, credentials := authorizationHeader(r)
+	if !strings.EqualFold(scheme, "digest") {
+		return nil, nil
+	}
+
+	params := parseAuthParams(credentials)
+	username := params["username"]
+
+	if params["realm"] != a.Realm || !a.validNonce(params["nonce"]) || params["uri"] != r.RequestURI {
+		return nil, InvalidCredentialsError
+	}
+
+	password, ok := a.Password(username)
+	if !ok {
+		return nil, InvalidCredentialsError
+	}
+
+	ha1 := md5Hex(username + ":" + a.Realm + ":" + password)
+	ha2 := md5Hex(r.Method + ":" + params["uri"])
+
+	var expected string
+	switch params["qop"] {
+	case "auth":
+		expected = md5Hex(strings.Join([]string{ha1, params["nonce"], params["nc"], params["cnonce"], "auth", ha2}, ":"))
+	case "":
+		expected = md5Hex(ha1 + ":" + params["nonce"] + ":" + ha2)
+	default:
+		return nil, InvalidCredentialsError
+	}
+
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(params["response"])) != 1 {
+		return nil, InvalidCredentialsError
+	}
+
+	return newUser(username, AuthSchemeDigest, a.Groups), nil
+}
+
+// Challenge returns a digest challenge with a new nonce
+func (a *DigestAuthenticator) Challenge() string {
+	return fmt.Sprintf("Digest realm=%q, qop=\"auth\", algorithm=MD5, nonce=%q", a.Realm, a.nonce(time.Now()))
+}
+
+// nonce generates a nonce containing the issue time signed with the secret of the authenticator
+func (a *DigestAuthenticator) nonce(issued time.Time) string {
+	payload := make([]byte, 8)
+	binary.BigEndian.PutUint64(payload, uint64(issued.Unix()))
+
+	return base64.RawURLEncoding.EncodeToString(append(payload, a.sign(payload)...))
+}
+
+func (a *DigestAuthenticator) validNonce(nonce string) bool {
+	data, err := base64.RawURLEncoding.DecodeString(nonce)
+	if err != nil || len(data) != 8+sha256.Size {
+		return false
+	}
+
+	if !hmac.Equal(data[8:], a.sign(data[:8])) {
+		return false
+	}
+
+	issued := time.Unix(int64(binary.BigEndian.Uint64(data[:8])), 0)
+	return time.Since(issued) < digestNonceLifetime
+}
+
+func (a *DigestAuthenticator) sign(payload []byte) []byte {
+	a.once.Do(func() {
+		a.secret = make([]byte, 32)
+		_, _ = rand.Read(a.secret)
+	})
+
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// UserName returns the name of the authenticated user or, for anonymous requests, the requesting-user-name
+// operation attribute
+func (r *Request) UserName() string {
+	if r.User != nil {
+		return r.User.Name
+	}
+
+	user, _ := r.OperationAttributes[ipp.AttributeRequestingUserName].(string)
+	return user
+}
+
+// authorize authenticates and authorizes the request, a non nil response is sent to the client instead of
+// calling the handler
+func (s *Server) authorize(req *Request) *ipp.Response {
+	if s.Authenticator != nil {
+		user, err := s.Authenticator.Authenticate(req.HTTPRequest)
+		if err != nil {
+			return Error(req, ipp.StatusErrorNotAuthenticated, err.Error())
+		}
+		req.User = user
+	}
+
+	authorizers := Authorizers{}
+	if s.Authorizer != nil {
+		authorizers = append(authorizers, s.Authorizer)
+	}
+	if req.Endpoint != nil {
+		if authorizer := req.Endpoint.getAuthorizer(); authorizer != nil {
+			authorizers = append(authorizers, authorizer)
+		}
+	}
+
+	err := authorizers.Authorize(req)
+	if err == nil {
+		return nil
+	}
+
+	var ippErr ipp.StatusError
+	switch {
+	case errors.As(err, &ippErr):
+		return Error(req, ippErr.Status, ippErr.Message)
+	case errors.Is(err, ForbiddenError):
+		return Error(req, ipp.StatusErrorForbidden, err.Error())
+	case errors.Is(err, AccessDeniedError) && req.User == nil:
+		return Error(req, ipp.StatusErrorNotAuthenticated, "authentication required")
+	case errors.Is(err, AccessDeniedError):
+		return Error(req, ipp.StatusErrorNotAuthorized, err.Error())
+	}
+
+	return Error(req, ipp.StatusErrorInternal, err.Error())
+}
+
+func newUser(name, scheme string, groups func(username string) []string) *User {
+	user := &User{Name: name, Scheme: scheme}
+	if groups != nil {
+		user.Groups = groups(name)
+	}
+
+	return user
+}
+
+// authorizationHeader splits the authorization header into the scheme and the credentials
+func authorizationHeader(r *http.Request) (string, string) {
+	header := strings.TrimSpace(r.Header.Get("Authorization"))
+	if i := strings.IndexByte(header, ' '); i > 0 {
+		return header[:i], strings.TrimSpace(header[i+1:])
+	}
+
+	return header, ""
+}
+
+// parseAuthParams parses the comma separated key=value parameters of an authorization header, values may be quoted
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+
+	for len(s) > 0 {
+		s = strings.TrimLeft(s, " ,")
+
+		i := strings.IndexByte(s, '=')
+		if i < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:i]))
+		s = s[i+1:]
+
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			var b strings.Builder
+			j := 1
+			for ; j < len(s) && s[j] != '"'; j++ {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+				}
+				b.WriteByte(s[j])
+			}
+			value = b.String()
+			s = s[minInt(j+1, len(s)):]
+		} else {
+			j := strings.IndexByte(s, ',')
+			if j < 0 {
+				j = len(s)
+			}
+			value = strings.TrimSpace(s[:j])
+			s = s[j:]
+		}
+
+		params[key] = value
+	}
+
+	return params
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func containsOperation(operations []int16, operation int16) bool {
+	for _, op := range operations {
+		if op == operation {
+			return true
+		}
+	}
+
+	return false
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveAuthRequest(t *testing.T, handler http.Handler, path string, req *ipp.Request, header http.Header) (*httptest.ResponseRecorder, *ipp.Response) {
+	payload, err := req.Encode()
+	assert.Nil(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	r.Header.Set("Content-Type", ipp.ContentTypeIPP)
+	for name, values := range header {
+		r.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+
+	resp, err := ipp.NewResponseDecoder(bytes.NewReader(rec.Body.Bytes())).Decode(nil)
+	assert.Nil(t, err)
+
+	return rec, resp
+}
+
+func basicAuthHeader(username, password string) http.Header {
+	r, _ := http.NewRequest(http.MethodPost, "/", nil)
+	r.SetBasicAuth(username, password)
+	return r.Header
+}
+
+func TestServer_Authentication(t *testing.T) {
+	s := NewServer()
+	s.Authenticator = &BasicAuthenticator{
+		Realm: "printers",
+		Verify: func(username, password string) bool {
+			return password == "secret"
+		},
+		Groups: func(username string) []string {
+			if username == "admin" {
+				return []string{"operators"}
+			}
+			return nil
+		},
+	}
+	s.Authorizer = Authorizers{
+		RequireAuthentication,
+		RequireGroup([]int16{ipp.OperationPausePrinter}, "operators"),
+	}
+
+	var user *User
+	handler := func(req *Request) (*ipp.Response, error) {
+		user = req.User
+		return nil, nil
+	}
+	s.HandleFunc(ipp.OperationGetPrinterAttributes, handler)
+	s.HandleFunc(ipp.OperationPausePrinter, handler)
+
+	request := func(op int16) *ipp.Request {
+		return newPrinterRequest(op, "ipp://localhost/")
+	}
+
+	rec, resp := serveAuthRequest(t, s, "/", request(ipp.OperationGetPrinterAttributes), nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, `Basic realm="printers"`, rec.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, ipp.StatusErrorNotAuthenticated, resp.StatusCode)
+
+	rec, resp = serveAuthRequest(t, s, "/", request(ipp.OperationGetPrinterAttributes), basicAuthHeader("alice", "wrong"))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, ipp.StatusErrorNotAuthenticated, resp.StatusCode)
+
+	rec, resp = serveAuthRequest(t, s, "/", request(ipp.OperationGetPrinterAttributes), basicAuthHeader("alice", "secret"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, &User{Name: "alice", Scheme: AuthSchemeBasic}, user)
+
+	rec, resp = serveAuthRequest(t, s, "/", request(ipp.OperationPausePrinter), basicAuthHeader("alice", "secret"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ipp.StatusErrorNotAuthorized, resp.StatusCode)
+
+	_, resp = serveAuthRequest(t, s, "/", request(ipp.OperationPausePrinter), basicAuthHeader("admin", "secret"))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, "admin", user.Name)
+
+	s.Authorizer = AuthorizerFunc(func(req *Request) error {
+		return ForbiddenError
+	})
+	_, resp = serveAuthRequest(t, s, "/", request(ipp.OperationPausePrinter), basicAuthHeader("admin", "secret"))
+	assert.Equal(t, ipp.StatusErrorForbidden, resp.StatusCode)
+}
+
+func TestBearerAuthenticator(t *testing.T) {
+	a := &BearerAuthenticator{
+		Realm: "printers",
+		Verify: func(token string) (*User, error) {
+			if token != "token" {
+				return nil, fmt.Errorf("unknown token")
+			}
+			return &User{Name: "alice"}, nil
+		},
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	user, err := a.Authenticate(r)
+	assert.Nil(t, err)
+	assert.Nil(t, user)
+
+	r.Header.Set("Authorization", "Bearer token")
+	user, err = a.Authenticate(r)
+	assert.Nil(t, err)
+	assert.Equal(t, &User{Name: "alice", Scheme: AuthSchemeBearer}, user)
+
+	r.Header.Set("Authorization", "Bearer other")
+	_, err = a.Authenticate(r)
+	assert.Equal(t, InvalidCredentialsError, err)
+}
+
+func TestDigestAuthenticator(t *testing.T) {
+	a := &DigestAuthenticator{
+		Realm: "printers",
+		Password: func(username string) (string, bool) {
+			return "secret", username == "alice"
+		},
+	}
+
+	params := parseAuthParams(a.Challenge()[len("Digest "):])
+	assert.Equal(t, "printers", params["realm"])
+	assert.Equal(t, "auth", params["qop"])
+	nonce := params["nonce"]
+
+	authorization := func(username, password, nonce string) string {
+		ha1 := md5Hex(username + ":printers:" + password)
+		ha2 := md5Hex("POST:/ipp/print")
+		response := md5Hex(ha1 + ":" + nonce + ":00000001:abcdef:auth:" + ha2)
+		return fmt.Sprintf(`Digest username="%s", realm="printers", nonce="%s", uri="/ipp/print", qop=auth, nc=00000001, cnonce="abcdef", response="%s"`,
+			username, nonce, response)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/ipp/print", nil)
+	r.Header.Set("Authorization", authorization("alice", "secret", nonce))
+	user, err := a.Authenticate(r)
+	assert.Nil(t, err)
+	assert.Equal(t, &User{Name: "alice", Scheme: AuthSchemeDigest}, user)
+
+	r.Header.Set("Authorization", authorization("alice", "wrong", nonce))
+	_, err = a.Authenticate(r)
+	assert.Equal(t, InvalidCredentialsError, err)
+
+	r.Header.Set("Authorization", authorization("bob", "secret", nonce))
+	_, err = a.Authenticate(r)
+	assert.Equal(t, InvalidCredentialsError, err)
+
+	r.Header.Set("Authorization", authorization("alice", "secret", "forged"))
+	_, err = a.Authenticate(r)
+	assert.Equal(t, InvalidCredentialsError, err)
+}
+
+func TestParseAuthParams(t *testing.T) {
+	params := parseAuthParams(`username="al\"ice", qop=auth, nc=00000001,realm="a, b"`)
+	assert.Equal(t, map[string]string{
+		"username": `al"ice`,
+		"qop":      "auth",
+		"nc":       "00000001",
+		"realm":    "a, b",
+	}, params)
+}
+
+func TestVirtualPrinter_OwnerPolicy(t *testing.T) {
+	printer := NewVirtualPrinter("test", nil)
+	s := NewServer()
+	s.Authenticator = &BasicAuthenticator{
+		Verify: func(username, password string) bool {
+			return true
+		},
+		Groups: func(username string) []string {
+			if username == "admin" {
+				return []string{"operators"}
+			}
+			return nil
+		},
+	}
+	printer.Register(s, "/ipp/print").SetAuthorizer(printer.OwnerPolicy("operators"))
+
+	printerURI := "ipp://localhost/ipp/print"
+
+	newJob := func() int {
+		_, resp := serveAuthRequest(t, s, "/ipp/print", newPrinterRequest(ipp.OperationCreateJob, printerURI), basicAuthHeader("alice", ""))
+		assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+		return resp.JobAttributes[0][ipp.AttributeJobID][0].Value.(int)
+	}
+
+	cancel := func(jobID int, header http.Header) int16 {
+		req := newPrinterRequest(ipp.OperationCancelJob, printerURI)
+		req.OperationAttributes[ipp.AttributeJobID] = jobID
+		_, resp := serveAuthRequest(t, s, "/ipp/print", req, header)
+		return resp.StatusCode
+	}
+
+	jobID := newJob()
+	job, _ := printer.Job(jobID)
+	assert.Equal(t, "alice", job.OriginatingUser)
+
+	assert.Equal(t, ipp.StatusErrorNotAuthenticated, cancel(jobID, nil))
+	assert.Equal(t, ipp.StatusErrorNotAuthorized, cancel(jobID, basicAuthHeader("bob", "")))
+	assert.Equal(t, ipp.StatusOk, cancel(jobID, basicAuthHeader("alice", "")))
+
+	jobID = newJob()
+	assert.Equal(t, ipp.StatusOk, cancel(jobID, basicAuthHeader("admin", "")))
+	assert.Equal(t, ipp.StatusErrorNotFound, cancel(42, basicAuthHeader("bob", "")))
+}
@@ -0,0 +1,427 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// job-sheets keywords
+const (
+	JobSheetsNone     = "none"
+	JobSheetsStandard = "standard"
+)
+
+// JobSheetsSupported are the job-sheets values supported by a VirtualPrinter with a Banner
+var JobSheetsSupported = []string{JobSheetsNone, JobSheetsStandard}
+
+// default banner page settings
+const (
+	DefaultBannerMedia      = "iso_a4_210x297mm"
+	DefaultBannerResolution = 300
+)
+
+// Banner generates the banner pages of a VirtualPrinter. jobs with the job-sheets value standard get a banner page
+// with the job name, user, job id, printer and submission time, which is passed to the sink as document number zero
+// before the first document of the job
+type Banner struct {
+	// Format is the document format of the banner pages, application/pdf or image/pwg-raster, defaults to pdf
+	Format string
+	// Media is the pwg self describing media size name of the page, defaults to iso_a4_210x297mm
+	Media string
+	// Resolution is the resolution of raster banner pages in dpi, defaults to 300
+	Resolution int
+	// Default is the job-sheets value for jobs without job-sheets attribute, defaults to none
+	Default string
+}
+
+// format returns the document format of the banner pages
+func (b *Banner) format() string {
+	if b.Format == "" {
+		return "application/pdf"
+	}
+
+	return b.Format
+}
+
+// jobSheets returns the job-sheets value of the job or the default of the banner
+func (b *Banner) jobSheets(job *Job) string {
+	if attr := job.Attributes[ipp.AttributeJobSheets]; len(attr) > 0 {
+		if sheets, ok := attr[0].Value.(string); ok {
+			return sheets
+		}
+	}
+
+	if b.Default == "" {
+		return JobSheetsNone
+	}
+
+	return b.Default
+}
+
+// Render generates the banner page of the job printed on the printer with the given name
+func (b *Banner) Render(job *Job, printer string) ([]byte, error) {
+	media := b.Media
+	if media == "" {
+		media = DefaultBannerMedia
+	}
+
+	width, height, ok := mediaSize(media)
+	if !ok {
+		return nil, fmt.Errorf("invalid media size name %s", media)
+	}
+
+	title := job.Name
+	lines := []string{
+		"User: " + job.OriginatingUser,
+		"Job: " + strconv.Itoa(job.ID),
+		"Printer: " + printer,
+		"Submitted: " + job.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+
+	switch b.format() {
+	case "application/pdf":
+		return bannerPDF(title, lines, width, height), nil
+	case "image/pwg-raster":
+		resolution := b.Resolution
+		if resolution <= 0 {
+			resolution = DefaultBannerResolution
+		}
+		return bannerRaster(title, lines, media, width, height, resolution), nil
+	}
+
+	return nil, fmt.Errorf("unsupported banner format %s", b.format())
+}
+
+// mediaSize returns the width and height in points of a pwg self describing media size name like
+// iso_a4_210x297mm or na_letter_8.5x11in
+func mediaSize(name string) (float64, float64, bool) {
+	width, height, ok := ipp.ParseMediaSizeName(name)
+	if !ok {
+		return 0, 0, false
+	}
+
+	// the dimensions are in hundredths of millimeters
+	return float64(width) * 72 / 2540, float64(height) * 72 / 2540, true
+}
+
+// bannerPDF generates a single page pdf with the title and the lines in the upper left corner
+func bannerPDF(title string, lines []string, width, height float64) []byte {
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "BT /F1 28 Tf 72 %.2f Td (%s) Tj ET\n", height-108, pdfString(title))
+	for i, line := range lines {
+		fmt.Fprintf(&content, "BT /F2 16 Tf 72 %.2f Td (%s) Tj ET\n", height-156-float64(i)*24, pdfString(line))
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>", width, height),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return pdf.Bytes()
+}
+
+// pdfString escapes a text for a pdf string literal, characters outside of latin-1 are replaced
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0xff:
+			b.WriteByte('?')
+		case r >= 0x80:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// pwg raster constants
+const (
+	pwgHeaderSize     = 1796
+	pwgColorSpaceGray = 18
+)
+
+// bannerRaster generates a single page 8 bit gray pwg raster document with the title and the lines in the upper
+// left corner, rendered with the built-in bitmap font
+func bannerRaster(title string, lines []string, media string, width, height float64, resolution int) []byte {
+	pixelsWide := int(width / 72 * float64(resolution))
+	pixelsHigh := int(height / 72 * float64(resolution))
+
+	// the font is scaled so the title is about 8mm and the other lines 5mm high
+	margin := resolution
+	texts := []rasterText{{text: title, top: margin, scale: maxInt(resolution/22, 1)}}
+	top := margin + texts[0].height() + resolution/4
+	for _, line := range lines {
+		text := rasterText{text: line, top: top, scale: maxInt(resolution/35, 1)}
+		texts = append(texts, text)
+		top += text.height() + text.scale*3
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("RaS2")
+	buf.Write(pwgHeader(media, width, height, pixelsWide, pixelsHigh, resolution))
+
+	row := make([]byte, pixelsWide)
+	var previous []byte
+	repeat := 0
+
+	flush := func() {
+		if previous == nil {
+			return
+		}
+		buf.WriteByte(byte(repeat - 1))
+		packBits(&buf, previous)
+	}
+
+	for y := 0; y < pixelsHigh; y++ {
+		for x := range row {
+			row[x] = 0xff
+		}
+		for _, text := range texts {
+			text.draw(row, y, margin)
+		}
+
+		if previous != nil && repeat < 256 && bytes.Equal(row, previous) {
+			repeat++
+			continue
+		}
+
+		flush()
+		previous = append(previous[:0], row...)
+		repeat = 1
+	}
+	flush()
+
+	return buf.Bytes()
+}
+
+// pwgHeader encodes the page header of a single page 8 bit gray pwg raster document
+func pwgHeader(media string, width, height float64, pixelsWide, pixelsHigh, resolution int) []byte {
+	header := make([]byte, pwgHeaderSize)
+	copy(header[0:], "PwgRaster")
+
+	put := func(offset int, value int) {
+		binary.BigEndian.PutUint32(header[offset:], uint32(value))
+	}
+
+	put(276, resolution)
+	put(280, resolution)
+	put(340, 1)
+	put(352, int(width+0.5))
+	put(356, int(height+0.5))
+	put(372, pixelsWide)
+	put(376, pixelsHigh)
+	put(384, 8)
+	put(388, 8)
+	put(392, pixelsWide)
+	put(400, pwgColorSpaceGray)
+	put(420, 1)
+	put(452, 1)
+	put(456, 1)
+	put(460, 1)
+	copy(header[1732:1795], media)
+
+	return header
+}
+
+// packBits compresses a line of 8 bit pixels with the pwg raster run length encoding
+func packBits(buf *bytes.Buffer, line []byte) {
+	for i := 0; i < len(line); {
+		run := 1
+		for i+run < len(line) && run < 128 && line[i+run] == line[i] {
+			run++
+		}
+
+		// single pixels can not be encoded as literal
+		if run > 1 || i+1 == len(line) || (i+2 < len(line) && line[i+1] == line[i+2]) {
+			buf.WriteByte(byte(run - 1))
+			buf.WriteByte(line[i])
+			i += run
+			continue
+		}
+
+		// literal pixels up to the next repetition
+		literal := 2
+		for i+literal < len(line) && literal < 128 {
+			if i+literal+1 < len(line) && line[i+literal] == line[i+literal+1] {
+				break
+			}
+			literal++
+		}
+
+		buf.WriteByte(byte(257 - literal))
+		buf.Write(line[i : i+literal])
+		i += literal
+	}
+}
+
+// rasterText is a line of text rendered into a raster image
+type rasterText struct {
+	text  string
+	top   int
+	scale int
+}
+
+func (t rasterText) height() int {
+	return len(bannerFont['A']) * t.scale
+}
+
+// draw renders the pixels of the text in the image row y, starting at column left
+func (t rasterText) draw(row []byte, y, left int) {
+	glyphRow := (y - t.top) / t.scale
+	if y < t.top || glyphRow >= len(bannerFont['A']) {
+		return
+	}
+
+	x := left
+	for _, r := range strings.ToUpper(t.text) {
+		glyph, ok := bannerFont[r]
+		if !ok {
+			glyph = bannerFont['?']
+		}
+
+		for column := 0; column < 5; column++ {
+			if glyph[glyphRow]&(0x10>>uint(column)) == 0 {
+				continue
+			}
+
+			for i := 0; i < t.scale; i++ {
+				if px := x + column*t.scale + i; px < len(row)-left {
+					row[px] = 0
+				}
+			}
+		}
+
+		x += 6 * t.scale
+		if x >= len(row)-left {
+			return
+		}
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+// bannerFont is a 5x7 bitmap font for the upper case letters, digits and common punctuation of raster banners
+var bannerFont = map[rune][7]byte{
+	' ':  {0, 0, 0, 0, 0, 0, 0},
+	'A':  {0b01110, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'B':  {0b11110, 0b10001, 0b10001, 0b11110, 0b10001, 0b10001, 0b11110},
+	'C':  {0b01110, 0b10001, 0b10000, 0b10000, 0b10000, 0b10001, 0b01110},
+	'D':  {0b11110, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b11110},
+	'E':  {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b11111},
+	'F':  {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b10000},
+	'G':  {0b01110, 0b10001, 0b10000, 0b10111, 0b10001, 0b10001, 0b01111},
+	'H':  {0b10001, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'I':  {0b01110, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'J':  {0b00111, 0b00010, 0b00010, 0b00010, 0b00010, 0b10010, 0b01100},
+	'K':  {0b10001, 0b10010, 0b10100, 0b11000, 0b10100, 0b10010, 0b10001},
+	'L':  {0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b11111},
+	'M':  {0b10001, 0b11011, 0b10101, 0b10101, 0b10001, 0b10001, 0b10001},
+	'N':  {0b10001, 0b10001, 0b11001, 0b10101, 0b10011, 0b10001, 0b10001},
+	'O':  {0b01110, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'P':  {0b11110, 0b10001, 0b10001, 0b11110, 0b10000, 0b10000, 0b10000},
+	'Q':  {0b01110, 0b10001, 0b10001, 0b10001, 0b10101, 0b10010, 0b01101},
+	'R':  {0b11110, 0b10001, 0b10001, 0b11110, 0b10100, 0b10010, 0b10001},
+	'S':  {0b01111, 0b10000, 0b10000, 0b01110, 0b00001, 0b00001, 0b11110},
+	'T':  {0b11111, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100},
+	'U':  {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'V':  {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01010, 0b00100},
+	'W':  {0b10001, 0b10001, 0b10001, 0b10101, 0b10101, 0b10101, 0b01010},
+	'X':  {0b10001, 0b10001, 0b01010, 0b00100, 0b01010, 0b10001, 0b10001},
+	'Y':  {0b10001, 0b10001, 0b10001, 0b01010, 0b00100, 0b00100, 0b00100},
+	'Z':  {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b10000, 0b11111},
+	'0':  {0b01110, 0b10001, 0b10011, 0b10101, 0b11001, 0b10001, 0b01110},
+	'1':  {0b00100, 0b01100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'2':  {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b01000, 0b11111},
+	'3':  {0b11111, 0b00010, 0b00100, 0b00010, 0b00001, 0b10001, 0b01110},
+	'4':  {0b00010, 0b00110, 0b01010, 0b10010, 0b11111, 0b00010, 0b00010},
+	'5':  {0b11111, 0b10000, 0b11110, 0b00001, 0b00001, 0b10001, 0b01110},
+	'6':  {0b00110, 0b01000, 0b10000, 0b11110, 0b10001, 0b10001, 0b01110},
+	'7':  {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b01000, 0b01000},
+	'8':  {0b01110, 0b10001, 0b10001, 0b01110, 0b10001, 0b10001, 0b01110},
+	'9':  {0b01110, 0b10001, 0b10001, 0b01111, 0b00001, 0b00010, 0b01100},
+	':':  {0b00000, 0b01100, 0b01100, 0b00000, 0b01100, 0b01100, 0b00000},
+	'-':  {0b00000, 0b00000, 0b00000, 0b11111, 0b00000, 0b00000, 0b00000},
+	'.':  {0b00000, 0b00000, 0b00000, 0b00000, 0b00000, 0b01100, 0b01100},
+	',':  {0b00000, 0b00000, 0b00000, 0b00000, 0b01100, 0b00100, 0b01000},
+	'/':  {0b00000, 0b00001, 0b00010, 0b00100, 0b01000, 0b10000, 0b00000},
+	'_':  {0b00000, 0b00000, 0b00000, 0b00000, 0b00000, 0b00000, 0b11111},
+	'@':  {0b01110, 0b10001, 0b00001, 0b01101, 0b10101, 0b10101, 0b01110},
+	'(':  {0b00010, 0b00100, 0b01000, 0b01000, 0b01000, 0b00100, 0b00010},
+	')':  {0b01000, 0b00100, 0b00010, 0b00010, 0b00010, 0b00100, 0b01000},
+	'\'': {0b01100, 0b00100, 0b01000, 0b00000, 0b00000, 0b00000, 0b00000},
+	'+':  {0b00000, 0b00100, 0b00100, 0b11111, 0b00100, 0b00100, 0b00000},
+	'=':  {0b00000, 0b00000, 0b11111, 0b00000, 0b11111, 0b00000, 0b00000},
+	'#':  {0b01010, 0b01010, 0b11111, 0b01010, 0b11111, 0b01010, 0b01010},
+	'!':  {0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b00000, 0b00100},
+	'?':  {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b00000, 0b00100},
+}
+
+// writeBanner passes the banner page to the sink if the job requests a job sheet
+func (p *VirtualPrinter) writeBanner(job *Job) error {
+	if p.Banner == nil || p.Sink == nil || p.Banner.jobSheets(job) == JobSheetsNone {
+		return nil
+	}
+
+	data, err := p.Banner.Render(job, p.name)
+	if err != nil {
+		return err
+	}
+
+	doc := Document{Number: 0, Name: "banner", Format: p.Banner.format(), Size: int64(len(data))}
+	return p.Sink.WriteDocument(job, doc, bytes.NewReader(data))
+}
+
+// validateJobSheets checks the job-sheets job template attribute of a job creation request
+func validateJobSheets(req *Request) error {
+	sheets, ok := req.JobAttributes[ipp.AttributeJobSheets]
+	if !ok {
+		return nil
+	}
+
+	if value, _ := sheets.(string); indexOf(JobSheetsSupported, value) < 0 {
+		return ipp.StatusError{
+			Status:  ipp.StatusErrorAttributesOrValues,
+			Message: fmt.Sprintf("job-sheets %v is not supported", sheets),
+		}
+	}
+
+	return nil
+}
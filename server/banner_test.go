@@ -0,0 +1,172 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+// decodeRasterLines decodes the compressed lines of a single page 8 bit pwg raster document
+func decodeRasterLines(t *testing.T, data []byte, width int) [][]byte {
+	var lines [][]byte
+	r := bytes.NewReader(data)
+
+	for r.Len() > 0 {
+		repeat, _ := r.ReadByte()
+
+		line := make([]byte, 0, width)
+		for len(line) < width {
+			control, err := r.ReadByte()
+			assert.Nil(t, err)
+
+			if control < 128 {
+				value, _ := r.ReadByte()
+				for i := 0; i <= int(control); i++ {
+					line = append(line, value)
+				}
+				continue
+			}
+
+			literal := make([]byte, 257-int(control))
+			_, err = io.ReadFull(r, literal)
+			assert.Nil(t, err)
+			line = append(line, literal...)
+		}
+
+		assert.Len(t, line, width)
+		for i := 0; i <= int(repeat); i++ {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+func TestMediaSize(t *testing.T) {
+	width, height, ok := mediaSize("iso_a4_210x297mm")
+	assert.True(t, ok)
+	assert.InDelta(t, 595.3, width, 0.1)
+	assert.InDelta(t, 841.9, height, 0.1)
+
+	width, height, ok = mediaSize("na_letter_8.5x11in")
+	assert.True(t, ok)
+	assert.Equal(t, 612.0, width)
+	assert.Equal(t, 792.0, height)
+
+	_, _, ok = mediaSize("letter")
+	assert.False(t, ok)
+}
+
+func TestBanner_PDF(t *testing.T) {
+	job := &Job{ID: 7, Name: "report (final)", OriginatingUser: "alice", CreatedAt: time.Now()}
+
+	data, err := (&Banner{}).Render(job, "office")
+	assert.Nil(t, err)
+
+	pdf := string(data)
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	assert.Contains(t, pdf, `(report \(final\))`)
+	assert.Contains(t, pdf, "(User: alice)")
+	assert.Contains(t, pdf, "/MediaBox [0 0 595.28 841.89]")
+
+	// the cross reference table must point to the objects
+	i := strings.LastIndex(pdf, "startxref\n")
+	xref, err := strconv.Atoi(strings.Fields(pdf[i+len("startxref\n"):])[0])
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(pdf[xref:], "xref\n"))
+
+	entries := strings.Split(pdf[xref:], "\n")[3:9]
+	for n, entry := range entries {
+		offset, err := strconv.Atoi(entry[:10])
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(pdf[offset:], strconv.Itoa(n+1)+" 0 obj"))
+	}
+}
+
+func TestBanner_Raster(t *testing.T) {
+	job := &Job{ID: 7, Name: "report", OriginatingUser: "alice", CreatedAt: time.Now()}
+	banner := &Banner{Format: "image/pwg-raster", Media: "na_letter_8.5x11in", Resolution: 100}
+
+	data, err := banner.Render(job, "office")
+	assert.Nil(t, err)
+	assert.Equal(t, "RaS2", string(data[:4]))
+
+	header := data[4 : 4+pwgHeaderSize]
+	assert.Equal(t, "PwgRaster", string(bytes.TrimRight(header[:64], "\x00")))
+	assert.Equal(t, "na_letter_8.5x11in", string(bytes.TrimRight(header[1732:], "\x00")))
+	assert.Equal(t, uint32(100), binary.BigEndian.Uint32(header[276:]))
+	assert.Equal(t, uint32(612), binary.BigEndian.Uint32(header[352:]))
+
+	width := int(binary.BigEndian.Uint32(header[372:]))
+	height := int(binary.BigEndian.Uint32(header[376:]))
+	assert.Equal(t, 850, width)
+	assert.Equal(t, 1100, height)
+
+	lines := decodeRasterLines(t, data[4+pwgHeaderSize:], width)
+	assert.Len(t, lines, height)
+
+	black := 0
+	for _, line := range lines {
+		black += bytes.Count(line, []byte{0})
+	}
+	assert.True(t, black > 0)
+	assert.NotContains(t, lines[0], byte(0))
+}
+
+func TestPackBits(t *testing.T) {
+	var buf bytes.Buffer
+	line := []byte{1, 1, 1, 2, 3, 4, 4, 5}
+	packBits(&buf, line)
+
+	assert.Equal(t, []byte{2, 1, 255, 2, 3, 1, 4, 0, 5}, buf.Bytes())
+	assert.Equal(t, [][]byte{line}, decodeRasterLines(t, append([]byte{0}, buf.Bytes()...), len(line)))
+}
+
+func TestVirtualPrinter_Banner(t *testing.T) {
+	var docs []Document
+
+	printer := NewVirtualPrinter("office", nil)
+	printer.Banner = &Banner{}
+	printer.Sink = DocumentSinkFunc(func(job *Job, doc Document, data io.Reader) error {
+		docs = append(docs, doc)
+		_, err := io.Copy(ioutil.Discard, data)
+		return err
+	})
+
+	s := NewServer()
+	printer.Register(s, "/ipp/print")
+	printerURI := "ipp://localhost/ipp/print"
+
+	req := newPrinterRequest(ipp.OperationPrintJob, printerURI)
+	req.JobAttributes[ipp.AttributeJobSheets] = JobSheetsStandard
+	resp := serveTestDocument(t, s, "/ipp/print", req, []byte("data"))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+
+	assert.Len(t, docs, 2)
+	assert.Equal(t, 0, docs[0].Number)
+	assert.Equal(t, "application/pdf", docs[0].Format)
+	assert.Equal(t, 1, docs[1].Number)
+
+	docs = nil
+	resp = serveTestDocument(t, s, "/ipp/print", newPrinterRequest(ipp.OperationPrintJob, printerURI), []byte("data"))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Len(t, docs, 1)
+
+	req = newPrinterRequest(ipp.OperationPrintJob, printerURI)
+	req.JobAttributes[ipp.AttributeJobSheets] = "classified"
+	resp = serveTestDocument(t, s, "/ipp/print", req, []byte("data"))
+	assert.Equal(t, ipp.StatusErrorAttributesOrValues, resp.StatusCode)
+
+	attributes := printer.printerAttributes(&Request{Request: ipp.NewRequest(ipp.OperationGetPrinterAttributes, 1)})
+	assert.Equal(t, JobSheetsNone, attributes[ipp.AttributeJobSheetsDefault][0].Value)
+	assert.Len(t, attributes[ipp.AttributeJobSheetsSupported], 2)
+}
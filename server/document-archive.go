@@ -0,0 +1,290 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+var DocumentNotRetainedError = errors.New("document is not retained")
+
+// DocumentArchive retains the document data of the jobs of a VirtualPrinter in a directory, so users can retrieve
+// the documents with CUPS-Get-Document and reprint terminated jobs with Resubmit-Job. the documents are named like
+// the files of a DirectorySink
+type DocumentArchive struct {
+	// MaxAge is the time the documents of terminated jobs are retained after their completion, zero retains them
+	// until they are removed
+	MaxAge time.Duration
+
+	files *DirectorySink
+}
+
+// NewDocumentArchive creates an archive in the directory, the directory is created if it does not exist
+func NewDocumentArchive(directory string) (*DocumentArchive, error) {
+	files, err := NewDirectorySink(directory)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create archive directory: %w", err)
+	}
+
+	return &DocumentArchive{files: files}, nil
+}
+
+// Directory returns the directory of the archive
+func (a *DocumentArchive) Directory() string {
+	return a.files.Directory
+}
+
+// Open opens the retained data of a document of the job, DocumentNotRetainedError is returned if the document was
+// not archived or is expired
+func (a *DocumentArchive) Open(job *Job, doc Document) (*os.File, error) {
+	if !doc.Retained || a.Expired(job, time.Now()) {
+		return nil, DocumentNotRetainedError
+	}
+
+	file, err := os.Open(a.path(job, doc))
+	if os.IsNotExist(err) {
+		return nil, DocumentNotRetainedError
+	}
+
+	return file, err
+}
+
+// Expired checks if the retention time of the documents of a terminated job has passed
+func (a *DocumentArchive) Expired(job *Job, now time.Time) bool {
+	return a.MaxAge > 0 && job.IsTerminated() && now.Sub(job.CompletedAt) > a.MaxAge
+}
+
+// Remove deletes the retained documents of a job
+func (a *DocumentArchive) Remove(job *Job) error {
+	for _, doc := range job.Documents {
+		if !doc.Retained {
+			continue
+		}
+		if err := os.Remove(a.path(job, doc)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to remove archived document: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (a *DocumentArchive) path(job *Job, doc Document) string {
+	return filepath.Join(a.files.Directory, a.files.FileName(job, doc))
+}
+
+// create opens a temporary file for the data of a document, the file is moved into the archive by commit
+func (a *DocumentArchive) create(job *Job, doc Document) (*archiveFile, error) {
+	mode := a.files.FileMode
+	if mode == 0 {
+		mode = 0600
+	}
+
+	path := a.path(job, doc)
+	file, err := os.OpenFile(path+".tmp", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create archived document: %w", err)
+	}
+
+	return &archiveFile{File: file, path: path}, nil
+}
+
+// archiveFile is a document which is written to the archive
+type archiveFile struct {
+	*os.File
+	path string
+	err  error
+}
+
+// Write writes to the file, errors are kept for commit, so a failing archive doesn't fail the printing of the
+// document
+func (f *archiveFile) Write(b []byte) (int, error) {
+	if f.err == nil {
+		_, f.err = f.File.Write(b)
+	}
+
+	return len(b), nil
+}
+
+// commit moves the completely written document into the archive
+func (f *archiveFile) commit() error {
+	if f.err != nil {
+		f.abort()
+		return f.err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), f.path)
+}
+
+// abort removes the incompletely written document
+func (f *archiveFile) abort() {
+	f.Close()
+	os.Remove(f.Name())
+}
+
+// purgeArchive removes the documents of the terminated jobs whose retention time has passed
+func (p *VirtualPrinter) purgeArchive() {
+	if p.Archive == nil || p.Archive.MaxAge <= 0 {
+		return
+	}
+
+	jobs, err := p.Jobs.List()
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	for _, job := range jobs {
+		if !p.Archive.Expired(job, now) || !hasRetainedDocuments(job) {
+			continue
+		}
+		if err := p.Archive.Remove(job); err != nil {
+			continue
+		}
+
+		_, _ = p.Jobs.Update(job.ID, func(job *Job) error {
+			for i := range job.Documents {
+				job.Documents[i].Retained = false
+			}
+			return nil
+		})
+	}
+}
+
+func hasRetainedDocuments(job *Job) bool {
+	for _, doc := range job.Documents {
+		if doc.Retained {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cupsGetDocument responds with the retained data of a document of a job, the document is selected by the
+// document-number operation attribute
+func (p *VirtualPrinter) cupsGetDocument(req *Request) (*ipp.Response, error) {
+	if p.Archive == nil {
+		return Error(req, ipp.StatusErrorOperationNotSupported, "documents are not retained"), nil
+	}
+
+	job, resp := p.lookupJob(req)
+	if resp != nil {
+		return resp, nil
+	}
+
+	number, ok := req.OperationAttributes[ipp.AttributeDocumentNumber].(int)
+	if !ok {
+		return Error(req, ipp.StatusErrorBadRequest, "missing document-number"), nil
+	}
+	if number < 1 || number > len(job.Documents) {
+		return Error(req, ipp.StatusErrorNotFound, fmt.Sprintf("document %d of job %d does not exist", number,
+			job.ID)), nil
+	}
+
+	doc := job.Documents[number-1]
+	file, err := p.Archive.Open(job, doc)
+	if errors.Is(err, DocumentNotRetainedError) {
+		return Error(req, ipp.StatusErrorNotFound, fmt.Sprintf("document %d of job %d is not retained", number,
+			job.ID)), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	req.ResponseData = file
+
+	format := doc.Format
+	if format == "" {
+		format = ipp.MimeTypeOctetStream
+	}
+
+	b := NewResponseBuilder(req).
+		OperationAttribute(ipp.AttributeDocumentFormat, ipp.TagMimeType, format).
+		OperationAttribute(ipp.AttributeDocumentNumber, ipp.TagInteger, doc.Number)
+	if doc.Name != "" {
+		b.OperationAttribute(ipp.AttributeDocumentName, ipp.TagName, doc.Name)
+	}
+
+	return b.Build(), nil
+}
+
+// resubmitJob creates a new job with the retained documents of a terminated job. the job template attributes of the
+// request replace the ones of the original job
+func (p *VirtualPrinter) resubmitJob(req *Request) (*ipp.Response, error) {
+	if p.Archive == nil {
+		return Error(req, ipp.StatusErrorOperationNotSupported, "documents are not retained"), nil
+	}
+
+	original, resp := p.lookupJob(req)
+	if resp != nil {
+		return resp, nil
+	}
+
+	if !original.IsTerminated() || len(original.Documents) == 0 {
+		return Error(req, ipp.StatusErrorNotPossible, fmt.Sprintf("job %d can not be resubmitted", original.ID)), nil
+	}
+
+	// all documents are opened before the job is created, so a job is only created if it can be printed completely
+	files := make([]*os.File, 0, len(original.Documents))
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+	for _, doc := range original.Documents {
+		file, err := p.Archive.Open(original, doc)
+		if errors.Is(err, DocumentNotRetainedError) {
+			return Error(req, ipp.StatusErrorNotPossible, fmt.Sprintf("documents of job %d are not retained",
+				original.ID)), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	jobReq := *req.Request
+	jobReq.OperationAttributes = make(map[string]interface{}, len(req.OperationAttributes)+1)
+	for name, value := range req.OperationAttributes {
+		jobReq.OperationAttributes[name] = value
+	}
+	jobReq.OperationAttributes[ipp.AttributeJobName] = original.Name
+	resubmit := *req
+	resubmit.Request = &jobReq
+
+	job, subscription, err := p.newJob(&resubmit, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.Jobs.Update(job.ID, func(job *Job) error {
+		for name, attr := range original.Attributes {
+			if _, ok := job.Attributes[name]; !ok {
+				job.Attributes[name] = attr
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	for i, doc := range original.Documents {
+		docReq := jobReq
+		docReq.OperationAttributes = map[string]interface{}{ipp.AttributeDocumentName: doc.Name}
+		docReq.File = files[i]
+		resubmit.Request = &docReq
+
+		if err := p.receiveDocument(&resubmit, job.ID, doc.Format, i == len(original.Documents)-1); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.jobResponse(req, job.ID, subscription), nil
+}
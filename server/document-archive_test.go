@@ -0,0 +1,124 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVirtualPrinter_Archive(t *testing.T) {
+	var printed []string
+	printer := NewVirtualPrinter("test", func(job *Job, format string, document io.Reader) error {
+		data, err := io.ReadAll(document)
+		printed = append(printed, string(data))
+		return err
+	})
+	archive, err := NewDocumentArchive(t.TempDir())
+	if !assert.Nil(t, err) {
+		return
+	}
+	printer.Archive = archive
+	s := NewServer()
+	printer.Register(s, "/ipp/print")
+
+	printerURI := "ipp://localhost/ipp/print"
+
+	resp := serveTestRequest(t, s, "/ipp/print", newPrinterRequest(ipp.OperationGetPrinterAttributes, printerURI))
+	var operations []int
+	for _, attr := range resp.PrinterAttributes[0][ipp.AttributeOperationsSupported] {
+		operations = append(operations, attr.Value.(int))
+	}
+	assert.Contains(t, operations, int(ipp.OperationResubmitJob))
+	assert.Contains(t, operations, int(ipp.OperationCupsGetDocument))
+
+	req := newPrinterRequest(ipp.OperationPrintJob, printerURI)
+	req.OperationAttributes[ipp.AttributeJobName] = "report"
+	req.OperationAttributes[ipp.AttributeDocumentFormat] = "application/pdf"
+	req.JobAttributes[ipp.AttributeCopies] = 2
+	resp = serveTestDocument(t, s, "/ipp/print", req, []byte("%PDF-1.7 report"))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	jobID := resp.JobAttributes[0][ipp.AttributeJobID][0].Value.(int)
+
+	req = newPrinterRequest(ipp.OperationCupsGetDocument, printerURI)
+	req.OperationAttributes[ipp.AttributeJobID] = jobID
+	req.OperationAttributes[ipp.AttributeDocumentNumber] = 1
+	resp, data := serveGetDocument(t, s, req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, "application/pdf", resp.OperationAttributes[ipp.AttributeDocumentFormat][0].Value)
+	assert.Equal(t, "report", resp.OperationAttributes[ipp.AttributeDocumentName][0].Value)
+	assert.Equal(t, "%PDF-1.7 report", data)
+
+	req.OperationAttributes[ipp.AttributeDocumentNumber] = 2
+	resp, data = serveGetDocument(t, s, req)
+	assert.Equal(t, ipp.StatusErrorNotFound, resp.StatusCode)
+	assert.Empty(t, data)
+
+	// the resubmitted job keeps the attributes of the original unless they are replaced
+	req = newPrinterRequest(ipp.OperationResubmitJob, printerURI)
+	req.OperationAttributes[ipp.AttributeJobID] = jobID
+	req.JobAttributes[ipp.AttributeJobPriority] = 80
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	reprintID := resp.JobAttributes[0][ipp.AttributeJobID][0].Value.(int)
+	assert.NotEqual(t, jobID, reprintID)
+	assert.Equal(t, int(ipp.JobStateCompleted), resp.JobAttributes[0][ipp.AttributeJobState][0].Value)
+	assert.Equal(t, []string{"%PDF-1.7 report", "%PDF-1.7 report"}, printed)
+
+	reprint, _ := printer.Job(reprintID)
+	assert.Equal(t, "report", reprint.Name)
+	assert.Equal(t, 2, reprint.Attributes[ipp.AttributeCopies][0].Value)
+	assert.Equal(t, 80, reprint.Attributes[ipp.AttributeJobPriority][0].Value)
+	if assert.Len(t, reprint.Documents, 1) {
+		assert.True(t, reprint.Documents[0].Retained)
+		assert.Equal(t, "application/pdf", reprint.Documents[0].Format)
+	}
+
+	resp = serveTestRequest(t, s, "/ipp/print", newPrinterRequest(ipp.OperationCreateJob, printerURI))
+	req = newPrinterRequest(ipp.OperationResubmitJob, printerURI)
+	req.OperationAttributes[ipp.AttributeJobID] = resp.JobAttributes[0][ipp.AttributeJobID][0].Value.(int)
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusErrorNotPossible, resp.StatusCode)
+
+	// expired documents are neither returned nor resubmitted and removed with the next terminated job
+	archive.MaxAge = time.Nanosecond
+	req = newPrinterRequest(ipp.OperationCupsGetDocument, printerURI)
+	req.OperationAttributes[ipp.AttributeJobID] = jobID
+	req.OperationAttributes[ipp.AttributeDocumentNumber] = 1
+	resp, _ = serveGetDocument(t, s, req)
+	assert.Equal(t, ipp.StatusErrorNotFound, resp.StatusCode)
+
+	req = newPrinterRequest(ipp.OperationResubmitJob, printerURI)
+	req.OperationAttributes[ipp.AttributeJobID] = jobID
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusErrorNotPossible, resp.StatusCode)
+
+	printer.purgeArchive()
+	job, _ := printer.Job(jobID)
+	assert.False(t, job.Documents[0].Retained)
+	files, err := os.ReadDir(archive.Directory())
+	assert.Nil(t, err)
+	assert.Empty(t, files)
+}
+
+func serveGetDocument(t *testing.T, handler http.Handler, req *ipp.Request) (*ipp.Response, string) {
+	payload, err := req.Encode()
+	assert.Nil(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, "/ipp/print", bytes.NewReader(payload))
+	r.Header.Set("Content-Type", ipp.ContentTypeIPP)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+
+	var data bytes.Buffer
+	resp, err := ipp.NewResponseDecoder(rec.Body).Decode(&data)
+	assert.Nil(t, err)
+
+	return resp, data.String()
+}
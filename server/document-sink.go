@@ -0,0 +1,119 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DocumentSink receives the data of printed documents. the data is streamed from the request, so implementations
+// should consume the reader instead of buffering whole documents. returning an error aborts the job. sinks may
+// increment the Impressions of the passed job, the count is added to the stored job
+type DocumentSink interface {
+	WriteDocument(job *Job, doc Document, data io.Reader) error
+}
+
+// DocumentHandler is a function which implements the DocumentSink interface
+type DocumentHandler func(job *Job, format string, document io.Reader) error
+
+// WriteDocument calls the handler with the format of the document
+func (h DocumentHandler) WriteDocument(job *Job, doc Document, data io.Reader) error {
+	return h(job, doc.Format, data)
+}
+
+// DocumentSinkFunc is a function which implements the DocumentSink interface with the full document metadata
+type DocumentSinkFunc func(job *Job, doc Document, data io.Reader) error
+
+// WriteDocument calls the function
+func (f DocumentSinkFunc) WriteDocument(job *Job, doc Document, data io.Reader) error {
+	return f(job, doc, data)
+}
+
+// DiscardSink is a DocumentSink which drops all document data
+var DiscardSink DocumentSink = DocumentSinkFunc(func(job *Job, doc Document, data io.Reader) error {
+	_, err := io.Copy(ioutil.Discard, data)
+	return err
+})
+
+// DirectorySink is a DocumentSink which saves each document as a file in a directory
+type DirectorySink struct {
+	Directory string
+	// FileMode is the permission of the created files, defaults to 0600
+	FileMode os.FileMode
+}
+
+// NewDirectorySink creates a sink which writes into the directory, the directory is created if it does not exist
+func NewDirectorySink(directory string) (*DirectorySink, error) {
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, err
+	}
+
+	return &DirectorySink{Directory: directory, FileMode: 0600}, nil
+}
+
+// WriteDocument streams the document into a file named after the job id, the document number and the format. the
+// file is created with a temporary name and renamed after all data is written, so incomplete documents are never
+// visible
+func (s *DirectorySink) WriteDocument(job *Job, doc Document, data io.Reader) error {
+	path := filepath.Join(s.Directory, s.FileName(job, doc))
+
+	mode := s.FileMode
+	if mode == 0 {
+		mode = 0600
+	}
+
+	file, err := os.OpenFile(path+".tmp", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+// FileName returns the name of the file of a document, e.g. j00001-d01.pdf
+func (s *DirectorySink) FileName(job *Job, doc Document) string {
+	return fmt.Sprintf("j%05d-d%02d%s", job.ID, doc.Number, extension(doc.Format))
+}
+
+// extension returns the file extension of a document format
+func extension(format string) string {
+	switch format {
+	case "application/pdf":
+		return ".pdf"
+	case "image/pwg-raster":
+		return ".pwg"
+	case "image/urf":
+		return ".urf"
+	case "image/jpeg":
+		return ".jpg"
+	case "application/postscript":
+		return ".ps"
+	case "", "application/octet-stream":
+		return ".prn"
+	}
+
+	if extensions, err := mime.ExtensionsByType(format); err == nil && len(extensions) > 0 {
+		return extensions[0]
+	}
+
+	if i := strings.LastIndexByte(format, '/'); i >= 0 {
+		return "." + format[i+1:]
+	}
+
+	return ".prn"
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestDirectorySink(t *testing.T) {
+	sink, err := NewDirectorySink(filepath.Join(t.TempDir(), "spool"))
+	assert.Nil(t, err)
+
+	job := &Job{ID: 3}
+	doc := Document{Number: 1, Format: "application/pdf"}
+
+	assert.Nil(t, sink.WriteDocument(job, doc, strings.NewReader("%PDF-1.7")))
+
+	data, err := ioutil.ReadFile(filepath.Join(sink.Directory, "j00003-d01.pdf"))
+	assert.Nil(t, err)
+	assert.Equal(t, "%PDF-1.7", string(data))
+
+	// incomplete documents are removed
+	doc.Number = 2
+	assert.NotNil(t, sink.WriteDocument(job, doc, failingReader{}))
+	files, _ := ioutil.ReadDir(sink.Directory)
+	assert.Len(t, files, 1)
+}
+
+func TestExtension(t *testing.T) {
+	assert.Equal(t, ".pwg", extension("image/pwg-raster"))
+	assert.Equal(t, ".prn", extension(ipp.MimeTypeOctetStream))
+	assert.Equal(t, ".prn", extension(""))
+}
+
+func TestVirtualPrinter_Sink(t *testing.T) {
+	var docs []Document
+	var received bytes.Buffer
+
+	printer := NewVirtualPrinter("test", nil)
+	printer.Sink = DocumentSinkFunc(func(job *Job, doc Document, data io.Reader) error {
+		docs = append(docs, doc)
+		_, err := io.Copy(&received, data)
+		return err
+	})
+
+	s := NewServer()
+	printer.Register(s, "/printers/test")
+
+	client, closeServer := newTestClient(t, s)
+	defer closeServer()
+
+	doc := []byte("%PDF-1.7 test")
+	jobID, err := client.PrintJob(ipp.Document{
+		Document: bytes.NewReader(doc),
+		Size:     len(doc),
+		Name:     "report.pdf",
+		MimeType: "application/pdf",
+	}, "test", map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Equal(t, []Document{{Number: 1, Name: "report.pdf", Format: "application/pdf"}}, docs)
+	assert.Equal(t, doc, received.Bytes())
+
+	job, _ := printer.Job(jobID)
+	assert.Equal(t, int64(len(doc)), job.Documents[0].Size)
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// Endpoint defines a printer endpoint of a server, addressed by its http path, with its own set of operation handlers
+type Endpoint struct {
+	path string
+
+	mu         sync.RWMutex
+	handlers   map[int16]HandlerFunc
+	resources  map[string]http.Handler
+	authorizer Authorizer
+	shutdown   []ShutdownFunc
+	stop       []func()
+}
+
+// Path returns the http path of the endpoint
+func (e *Endpoint) Path() string {
+	return e.path
+}
+
+// HandleFunc registers the handler for the given operation on this endpoint, an already registered handler will be replaced
+func (e *Endpoint) HandleFunc(operation int16, handler HandlerFunc) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.handlers[operation] = handler
+}
+
+// Operations returns the ids of all operations with a registered handler on this endpoint
+func (e *Endpoint) Operations() []int16 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	operations := make([]int16, 0, len(e.handlers))
+	for op := range e.handlers {
+		operations = append(operations, op)
+	}
+
+	return operations
+}
+
+// HandleResource registers a http handler for GET and HEAD requests below the sub path with the given name, e.g.
+// the name icons serves /ipp/print/icons/... of the endpoint /ipp/print. resources are served without
+// authentication, so they must not expose confidential data
+func (e *Endpoint) HandleResource(name string, handler http.Handler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.resources == nil {
+		e.resources = make(map[string]http.Handler)
+	}
+	e.resources[strings.Trim(name, "/")] = handler
+}
+
+func (e *Endpoint) resource(name string) (http.Handler, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	handler, ok := e.resources[name]
+	return handler, ok
+}
+
+// SetAuthorizer sets an authorizer which is called for requests to this endpoint after the authorizer of the server
+func (e *Endpoint) SetAuthorizer(authorizer Authorizer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.authorizer = authorizer
+}
+
+func (e *Endpoint) getAuthorizer() Authorizer {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.authorizer
+}
+
+func (e *Endpoint) handler(operation int16) (HandlerFunc, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	handler, ok := e.handlers[operation]
+	return handler, ok
+}
+
+// Endpoint returns the printer endpoint for the given http path, e.g. /ipp/print or /printers/office.
+// the endpoint is created if it does not exist yet
+func (s *Server) Endpoint(path string) *Endpoint {
+	path = cleanPath(path)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.endpoints[path]; ok {
+		return e
+	}
+
+	e := &Endpoint{
+		path:     path,
+		handlers: make(map[int16]HandlerFunc),
+	}
+	s.endpoints[path] = e
+
+	return e
+}
+
+// RemoveEndpoint removes the printer endpoint for the given http path
+func (s *Server) RemoveEndpoint(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.endpoints, cleanPath(path))
+}
+
+// Endpoints returns all registered printer endpoints
+func (s *Server) Endpoints() []*Endpoint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	endpoints := make([]*Endpoint, 0, len(s.endpoints))
+	for _, e := range s.endpoints {
+		endpoints = append(endpoints, e)
+	}
+
+	return endpoints
+}
+
+// lookupEndpoint returns the endpoint with the longest path matching the given path. sub paths of an endpoint,
+// e.g. /ipp/print/42 for a job, are resolved to the endpoint
+func (s *Server) lookupEndpoint(path string) *Endpoint {
+	path = cleanPath(path)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for {
+		if e, ok := s.endpoints[path]; ok {
+			return e
+		}
+
+		i := strings.LastIndex(path, "/")
+		if i <= 0 {
+			return nil
+		}
+		path = path[:i]
+	}
+}
+
+// lookupResource returns the resource handler of the endpoint matching the http path
+func (s *Server) lookupResource(path string) (http.Handler, bool) {
+	path = cleanPath(path)
+
+	e := s.lookupEndpoint(path)
+	if e == nil {
+		return nil, false
+	}
+
+	name := strings.TrimPrefix(strings.TrimPrefix(path, e.Path()), "/")
+	if i := strings.Index(name, "/"); i >= 0 {
+		name = name[:i]
+	}
+
+	return e.resource(name)
+}
+
+// resolveEndpoint determines the target endpoint of a request by the http path or, as fallback, the path of the
+// printer-uri or job-uri operation attribute. a printer-uri in the urn:uuid form addresses the queue with the uuid
+func (s *Server) resolveEndpoint(req *Request) *Endpoint {
+	if e := s.lookupEndpoint(req.HTTPRequest.URL.Path); e != nil {
+		return e
+	}
+
+	for _, name := range []string{ipp.AttributePrinterURI, ipp.AttributeJobURI} {
+		value, ok := req.OperationAttributes[name].(string)
+		if !ok {
+			continue
+		}
+
+		if strings.HasPrefix(strings.ToLower(value), "urn:uuid:") {
+			if e := s.queueByUUID(value); e != nil {
+				return e
+			}
+			continue
+		}
+
+		if u, err := url.Parse(value); err == nil {
+			if e := s.lookupEndpoint(u.Path); e != nil {
+				return e
+			}
+		}
+	}
+
+	return nil
+}
+
+func cleanPath(path string) string {
+	path = "/" + strings.Trim(path, "/")
+	return path
+}
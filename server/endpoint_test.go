@@ -0,0 +1,71 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveTestRequest(t *testing.T, handler http.Handler, path string, req *ipp.Request) *ipp.Response {
+	payload, err := req.Encode()
+	assert.Nil(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	r.Header.Set("Content-Type", ipp.ContentTypeIPP)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	resp, err := ipp.NewResponseDecoder(rec.Body).Decode(nil)
+	assert.Nil(t, err)
+
+	return resp
+}
+
+func newPrinterRequest(op int16, printerURI string) *ipp.Request {
+	req := ipp.NewRequest(op, 1)
+	req.OperationAttributes[ipp.AttributePrinterURI] = printerURI
+	return req
+}
+
+func TestServer_Endpoints(t *testing.T) {
+	var served string
+
+	s := NewServer()
+	s.HandleFunc(ipp.OperationCupsGetPrinters, func(req *Request) (*ipp.Response, error) {
+		served = "server"
+		return nil, nil
+	})
+	for _, path := range []string{"/ipp/print", "/printers/office"} {
+		s.Endpoint(path).HandleFunc(ipp.OperationGetPrinterAttributes, func(req *Request) (*ipp.Response, error) {
+			served = req.Endpoint.Path()
+			return nil, nil
+		})
+	}
+
+	resp := serveTestRequest(t, s, "/ipp/print", newPrinterRequest(ipp.OperationGetPrinterAttributes, "ipp://localhost/ipp/print"))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, "/ipp/print", served)
+
+	resp = serveTestRequest(t, s, "/printers/office/", newPrinterRequest(ipp.OperationGetPrinterAttributes, "ipp://localhost/printers/office"))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, "/printers/office", served)
+
+	resp = serveTestRequest(t, s, "/", newPrinterRequest(ipp.OperationGetPrinterAttributes, "ipp://localhost/printers/office"))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, "/printers/office", served)
+
+	resp = serveTestRequest(t, s, "/printers/office/jobs/3", ipp.NewRequest(ipp.OperationCupsGetPrinters, 1))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, "server", served)
+
+	resp = serveTestRequest(t, s, "/printers/lab", newPrinterRequest(ipp.OperationGetPrinterAttributes, "ipp://localhost/printers/lab"))
+	assert.Equal(t, ipp.StatusErrorNotFound, resp.StatusCode)
+
+	resp = serveTestRequest(t, s, "/ipp/print", newPrinterRequest(ipp.OperationPrintJob, "ipp://localhost/ipp/print"))
+	assert.Equal(t, ipp.StatusErrorOperationNotSupported, resp.StatusCode)
+}
@@ -0,0 +1,230 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// defaultStreamBuffer is the number of events queued for a client of an EventStream
+const defaultStreamBuffer = 64
+
+// EventStream is a http handler which streams event notifications as server-sent events with json payloads, so web
+// dashboards can follow printer and job events with an EventSource without speaking ipp. the events are fed with
+// Publish, e.g. from a NotificationReceiver created with Receiver for push subscriptions or from
+// ipp.IPPClient.PollNotifications for ippget subscriptions. each event has the notify-subscribed-event as type, the
+// subscription id and sequence number as id and the json of ipp.Notification as data. clients can filter the
+// events with the query parameters event, job-id and subscription-id, e.g. /events?event=job-completed&job-id=5
+type EventStream struct {
+	// Heartbeat is the interval of the comments which keep idle connections open through proxies, zero disables them
+	Heartbeat time.Duration
+	// Buffer is the number of events queued for a slow client, further events are dropped for it. defaults to 64
+	Buffer int
+
+	mu      sync.Mutex
+	clients map[*streamClient]struct{}
+	done    chan struct{}
+	closed  bool
+}
+
+// streamClient is a connected client of an EventStream
+type streamClient struct {
+	events         []string
+	jobID          int
+	subscriptionID int
+	frames         chan []byte
+}
+
+// NewEventStream creates an event stream with a heartbeat every 30 seconds
+func NewEventStream() *EventStream {
+	return &EventStream{Heartbeat: 30 * time.Second}
+}
+
+// Receiver returns a NotificationReceiver which publishes the pushed notifications to the stream
+func (s *EventStream) Receiver() *NotificationReceiver {
+	return NewNotificationReceiver(func(notifications []ipp.Notification) {
+		s.Publish(notifications...)
+	})
+}
+
+// Publish sends the notifications to the connected clients whose filters match
+func (s *EventStream) Publish(notifications ...ipp.Notification) {
+	for _, n := range notifications {
+		frame, err := eventFrame(n)
+		if err != nil {
+			continue
+		}
+
+		s.mu.Lock()
+		for c := range s.clients {
+			if !c.matches(n) {
+				continue
+			}
+			select {
+			case c.frames <- frame:
+			default:
+				// the client doesn't keep up, it can resynchronize with the ids of the events
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Clients returns the number of connected clients
+func (s *EventStream) Clients() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.clients)
+}
+
+// Close ends the streams of the connected clients and rejects new clients, e.g. in a function registered with
+// Server.RegisterOnShutdown, since http.Server.Shutdown doesn't end open streams
+func (s *EventStream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.doneChan())
+	}
+}
+
+// doneChan returns the channel which is closed by Close, the caller must hold the lock
+func (s *EventStream) doneChan() chan struct{} {
+	if s.done == nil {
+		s.done = make(chan struct{})
+	}
+
+	return s.done
+}
+
+// ServeHTTP streams the events to the client until it disconnects or the stream is closed
+func (s *EventStream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "only GET requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	c, err := newStreamClient(r, s.Buffer)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		http.Error(w, "event stream is closed", http.StatusServiceUnavailable)
+		return
+	}
+	if s.clients == nil {
+		s.clients = make(map[*streamClient]struct{})
+	}
+	s.clients[c] = struct{}{}
+	done := s.doneChan()
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, c)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	var heartbeat <-chan time.Time
+	if s.Heartbeat > 0 {
+		ticker := time.NewTicker(s.Heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-done:
+			return
+		case <-heartbeat:
+			if _, err := w.Write([]byte(": heartbeat\n\n")); err != nil {
+				return
+			}
+		case frame := <-c.frames:
+			if _, err := w.Write(frame); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// newStreamClient creates a client with the filters of the query parameters of the request
+func newStreamClient(r *http.Request, buffer int) (*streamClient, error) {
+	if buffer <= 0 {
+		buffer = defaultStreamBuffer
+	}
+
+	query := r.URL.Query()
+	c := &streamClient{events: query["event"], frames: make(chan []byte, buffer)}
+
+	for name, target := range map[string]*int{"job-id": &c.jobID, "subscription-id": &c.subscriptionID} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid %s %q", name, value)
+		}
+		*target = id
+	}
+
+	return c, nil
+}
+
+// matches checks the notification against the filters of the client
+func (c *streamClient) matches(n ipp.Notification) bool {
+	if len(c.events) > 0 && !slices.Contains(c.events, n.Event) {
+		return false
+	}
+	if c.jobID != 0 && n.JobID != c.jobID {
+		return false
+	}
+
+	return c.subscriptionID == 0 || n.SubscriptionID == c.subscriptionID
+}
+
+// eventFrame formats the notification as server-sent event. events whose name would break the frame are sent
+// without type, they are received as message events
+func eventFrame(n ipp.Notification) ([]byte, error) {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return nil, err
+	}
+
+	frame := fmt.Appendf(nil, "id: %d.%d\n", n.SubscriptionID, n.SequenceNumber)
+	if n.Event != "" && !strings.ContainsAny(n.Event, "\r\n") {
+		frame = fmt.Appendf(frame, "event: %s\n", n.Event)
+	}
+
+	return fmt.Appendf(frame, "data: %s\n\n", data), nil
+}
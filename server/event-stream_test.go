@@ -0,0 +1,95 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventStream(t *testing.T) {
+	stream := NewEventStream()
+	srv := httptest.NewServer(stream)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "?event=job-completed&job-id=5")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, 1, stream.Clients())
+
+	completed := ipp.Notification{SubscriptionID: 2, SequenceNumber: 3, Event: ipp.EventJobCompleted, JobID: 5,
+		Attributes: ipp.Attributes{}}
+	completed.Attributes.Set(ipp.AttributeJobState, ipp.TagEnum, int(ipp.JobStateCompleted))
+	stream.Publish(
+		ipp.Notification{SubscriptionID: 2, SequenceNumber: 1, Event: ipp.EventJobCreated, JobID: 5},
+		ipp.Notification{SubscriptionID: 2, SequenceNumber: 2, Event: ipp.EventJobCompleted, JobID: 4},
+		completed,
+	)
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		if !assert.Nil(t, err) {
+			return
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	assert.Equal(t, "id: 2.3", lines[0])
+	assert.Equal(t, "event: job-completed", lines[1])
+
+	var received ipp.Notification
+	assert.Nil(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &received))
+	assert.Equal(t, 5, received.JobID)
+	assert.Equal(t, int(ipp.JobStateCompleted), received.Attributes[ipp.AttributeJobState][0].Value)
+
+	stream.Close()
+	_, err = reader.ReadString('\n')
+	assert.Nil(t, err)
+	_, err = reader.ReadString('\n')
+	assert.NotNil(t, err)
+
+	resp, err = http.Get(srv.URL)
+	if assert.Nil(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	}
+
+	resp, err = http.Get(srv.URL + "?job-id=x")
+	if assert.Nil(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	}
+}
+
+func TestEventStream_Receiver(t *testing.T) {
+	stream := NewEventStream()
+	rec := httptest.NewRecorder()
+	c, err := newStreamClient(httptest.NewRequest(http.MethodGet, "/?subscription-id=1", nil), 0)
+	assert.Nil(t, err)
+	stream.clients = map[*streamClient]struct{}{c: {}}
+
+	event := make(ipp.Attributes)
+	event.Set(ipp.AttributeNotifySubscriptionID, ipp.TagInteger, 1)
+	event.Set(ipp.AttributeNotifySequenceNumber, ipp.TagInteger, 1)
+	event.Set(ipp.AttributeNotifySubscribedEvent, ipp.TagKeyword, ipp.EventPrinterStopped)
+	payload, err := ipp.EncodeSendNotifications(1, []ipp.Attributes{event})
+	assert.Nil(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(payload)))
+	r.Header.Set("Content-Type", ipp.ContentTypeIPP)
+	stream.Receiver().ServeHTTP(rec, r)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	if assert.Len(t, c.frames, 1) {
+		assert.True(t, strings.HasPrefix(string(<-c.frames), "id: 1.1\nevent: printer-stopped\n"))
+	}
+}
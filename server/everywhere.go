@@ -0,0 +1,296 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// Capabilities describes the basic features of a printer from which the ipp everywhere attributes are generated
+type Capabilities struct {
+	MakeAndModel string
+	Location     string
+	Info         string
+	Organization string
+
+	// DocumentFormats are the supported document formats, image/pwg-raster is always added
+	DocumentFormats []string
+	// Media are the supported pwg media size names, the first size is the default and the ready media. legacy and
+	// ppd names like a4 or Letter are converted to the pwg names of the registry
+	Media []string
+	// MediaSources are the supported media-source keywords
+	MediaSources []string
+	// MediaTypes are the supported media-type keywords
+	MediaTypes []string
+	// OutputBins are the supported output-bin keywords
+	OutputBins []string
+	// Resolutions are the supported resolutions, the first resolution is the default
+	Resolutions []ipp.Resolution
+	// MaxCopies is the maximum of the copies attribute
+	MaxCopies int
+
+	Color      bool
+	Duplex     bool
+	Borderless bool
+}
+
+// default capabilities used for empty fields
+var (
+	DefaultMedia      = []string{"iso_a4_210x297mm", "na_letter_8.5x11in"}
+	DefaultResolution = ipp.Resolution{Height: 300, Width: 300, Depth: 3}
+)
+
+// media margin of printers which can not print borderless in hundredths of millimeters
+const defaultMediaMargin = 423
+
+// EverywhereAttributes generates the printer description attributes required by ipp everywhere from the capabilities.
+// dynamic attributes like printer-state, printer-uuid or printer-uri-supported are not part of the set, they are
+// added by the VirtualPrinter
+func EverywhereAttributes(c Capabilities) ipp.Attributes {
+	media := lookupMediaSizes(c.Media)
+	if len(media) == 0 {
+		media = lookupMediaSizes(DefaultMedia)
+	}
+
+	resolutions := c.Resolutions
+	if len(resolutions) == 0 {
+		resolutions = []ipp.Resolution{DefaultResolution}
+	}
+
+	maxCopies := c.MaxCopies
+	if maxCopies < 1 {
+		maxCopies = 999
+	}
+
+	formats := c.DocumentFormats
+	if indexOf(formats, "image/pwg-raster") < 0 {
+		formats = append(append([]string{}, formats...), "image/pwg-raster")
+	}
+
+	a := make(ipp.Attributes)
+
+	a.Set(ipp.AttributePrinterMakeAndModel, ipp.TagText, c.MakeAndModel)
+	a.Set(ipp.AttributePrinterLocation, ipp.TagText, c.Location)
+	a.Set(ipp.AttributePrinterInfo, ipp.TagText, c.Info)
+	a.Set(ipp.AttributePrinterOrganization, ipp.TagText, c.Organization)
+	a.Set(ipp.AttributePrinterOrganizationalUnit, ipp.TagText, "")
+	a.Set(ipp.AttributePrinterGeoLocation, ipp.TagUnknown, "")
+	a.Set(ipp.AttributePrinterDeviceID, ipp.TagText, deviceID(c.MakeAndModel, formats))
+	a.Set(ipp.AttributePrinterKind, ipp.TagKeyword, "document")
+	a.Set(ipp.AttributeIppFeaturesSupported, ipp.TagKeyword, "ipp-everywhere")
+	a.Set(ipp.AttributeIppVersionsSupported, ipp.TagKeyword, "1.1", "2.0")
+	a.Set(ipp.AttributeCharsetConfigured, ipp.TagCharset, ipp.Charset)
+	a.Set(ipp.AttributeCharsetSupported, ipp.TagCharset, ipp.Charset, "us-ascii")
+	a.Set(ipp.AttributeNaturalLanguageConfigured, ipp.TagLanguage, "en")
+	a.Set(ipp.AttributeGeneratedNaturalLanguageSupported, ipp.TagLanguage, "en")
+	a.Set(ipp.AttributeCompressionSupported, ipp.TagKeyword, "none")
+	a.Set(ipp.AttributePdlOverrideSupported, ipp.TagKeyword, "attempted")
+	a.Set(ipp.AttributeIdentifyActionsDefault, ipp.TagKeyword, "sound")
+	a.Set(ipp.AttributeIdentifyActionsSupported, ipp.TagKeyword, "display", "sound")
+	a.Set(ipp.AttributeMultipleOperationTimeOut, ipp.TagInteger, 60)
+	a.Set(ipp.AttributeMultipleDocumentJobsSupported, ipp.TagBoolean, false)
+	a.Set(ipp.AttributeJobIdsSupported, ipp.TagBoolean, true)
+	a.Set(ipp.AttributeWhichJobsSupported, ipp.TagKeyword, ipp.JobStateFilterCompleted, ipp.JobStateFilterNotCompleted)
+	a.Set(ipp.AttributePrinterGetAttributesSupported, ipp.TagKeyword, ipp.AttributeDocumentFormat)
+	a.Set(ipp.AttributeJobCreationAttributesSupported, ipp.TagKeyword, toValues([]string{
+		ipp.AttributeCopies, ipp.AttributeFinishings, ipp.AttributeMedia, ipp.AttributeMediaSource, ipp.AttributeMediaType,
+		ipp.AttributeOrientationRequested, ipp.AttributeOutputBin, ipp.AttributePageRanges, ipp.AttributePrintColorMode,
+		ipp.AttributePrintQuality, ipp.AttributePrinterResolution, ipp.AttributeSides, ipp.AttributePrintScaling,
+		ipp.AttributePrintContentOptimize, ipp.AttributePrintRenderingIntent,
+	})...)
+
+	a.Set(ipp.AttributeDocumentFormatDefault, ipp.TagMimeType, formats[0])
+	a.Set(ipp.AttributeDocumentFormatSupported, ipp.TagMimeType, toValues(formats)...)
+
+	a.Set(ipp.AttributeCopiesDefault, ipp.TagInteger, 1)
+	a.Set(ipp.AttributeCopiesSupported, ipp.TagRange, []int32{1, int32(maxCopies)})
+	a.Set(ipp.AttributeFinishingsDefault, ipp.TagEnum, int(ipp.FinishingsNone))
+	a.Set(ipp.AttributeFinishingsSupported, ipp.TagEnum, int(ipp.FinishingsNone))
+	a.Set(ipp.AttributePageRangesSupported, ipp.TagBoolean, true)
+
+	a.Set(ipp.AttributeOrientationRequestedDefault, ipp.TagEnum, int(ipp.OrientationPortrait))
+	a.Set(ipp.AttributeOrientationRequestedSupported, ipp.TagEnum, int(ipp.OrientationPortrait),
+		int(ipp.OrientationLandscape), int(ipp.OrientationReverseLandscape), int(ipp.OrientationReversePortrait))
+
+	a.Set(ipp.AttributePrintQualityDefault, ipp.TagEnum, int(ipp.PrintQualityNormal))
+	a.Set(ipp.AttributePrintQualitySupported, ipp.TagEnum, int(ipp.PrintQualityDraft), int(ipp.PrintQualityNormal),
+		int(ipp.PrintQualityHigh))
+
+	a.Set(ipp.AttributePrintScalingDefault, ipp.TagKeyword, ipp.PrintScalingAuto)
+	a.Set(ipp.AttributePrintScalingSupported, ipp.TagKeyword, ipp.PrintScalingAuto, ipp.PrintScalingAutoFit,
+		ipp.PrintScalingFill, ipp.PrintScalingFit, ipp.PrintScalingNone)
+	a.Set(ipp.AttributePrintContentOptimizeDefault, ipp.TagKeyword, ipp.PrintContentOptimizeAuto)
+	a.Set(ipp.AttributePrintContentOptimizeSupported, ipp.TagKeyword, ipp.PrintContentOptimizeAuto,
+		ipp.PrintContentOptimizeGraphics, ipp.PrintContentOptimizePhoto, ipp.PrintContentOptimizeText,
+		ipp.PrintContentOptimizeTextAndGraphics)
+	a.Set(ipp.AttributePrintRenderingIntentDefault, ipp.TagKeyword, ipp.PrintRenderingIntentAuto)
+	a.Set(ipp.AttributePrintRenderingIntentSupported, ipp.TagKeyword, ipp.PrintRenderingIntentAuto,
+		ipp.PrintRenderingIntentPerceptual, ipp.PrintRenderingIntentRelative)
+
+	names := make([]string, len(media))
+	for i, size := range media {
+		names[i] = size.Name
+	}
+	a.Set(ipp.AttributeMediaDefault, ipp.TagKeyword, names[0])
+	a.Set(ipp.AttributeMediaReady, ipp.TagKeyword, names[0])
+	a.Set(ipp.AttributeMediaSupported, ipp.TagKeyword, toValues(names)...)
+
+	margins := []interface{}{defaultMediaMargin}
+	if c.Borderless {
+		margins = []interface{}{0, defaultMediaMargin}
+	}
+
+	sizes := make([]interface{}, len(media))
+	database := make([]interface{}, 0, 2*len(media))
+	for i, size := range media {
+		col := ipp.MediaCol{SizeName: size.Name, Width: size.Width, Height: size.Height}
+		sizes[i] = col.Collection()[ipp.AttributeMediaSize][0].Value
+
+		col = col.WithMargins(defaultMediaMargin, defaultMediaMargin, defaultMediaMargin, defaultMediaMargin)
+		database = append(database, col.Collection())
+		if c.Borderless {
+			database = append(database, col.Borderless().Collection())
+		}
+	}
+	a.Set(ipp.AttributeMediaSizeSupported, ipp.TagBeginCollection, sizes...)
+	a.Set(ipp.AttributeMediaColDatabase, ipp.TagBeginCollection, database...)
+	a.Set(ipp.AttributeMediaColDefault, ipp.TagBeginCollection, database[0])
+	a.Set(ipp.AttributeMediaColReady, ipp.TagBeginCollection, database[0])
+	a.Set(ipp.AttributeMediaColSupported, ipp.TagKeyword, ipp.AttributeMediaSize, ipp.AttributeMediaTopMargin,
+		ipp.AttributeMediaBottomMargin, ipp.AttributeMediaLeftMargin, ipp.AttributeMediaRightMargin,
+		ipp.AttributeMediaSource, ipp.AttributeMediaType)
+	a.Set(ipp.AttributeMediaBottomMarginSupported, ipp.TagInteger, margins...)
+	a.Set(ipp.AttributeMediaLeftMarginSupported, ipp.TagInteger, margins...)
+	a.Set(ipp.AttributeMediaRightMarginSupported, ipp.TagInteger, margins...)
+	a.Set(ipp.AttributeMediaTopMarginSupported, ipp.TagInteger, margins...)
+
+	sources := c.MediaSources
+	if len(sources) == 0 {
+		sources = []string{"auto"}
+	}
+	a.Set(ipp.AttributeMediaSourceSupported, ipp.TagKeyword, toValues(sources)...)
+
+	types := c.MediaTypes
+	if len(types) == 0 {
+		types = []string{"stationery"}
+	}
+	a.Set(ipp.AttributeMediaTypeSupported, ipp.TagKeyword, toValues(types)...)
+
+	bins := c.OutputBins
+	if len(bins) == 0 {
+		bins = []string{"face-down"}
+	}
+	a.Set(ipp.AttributeOutputBinDefault, ipp.TagKeyword, bins[0])
+	a.Set(ipp.AttributeOutputBinSupported, ipp.TagKeyword, toValues(bins)...)
+
+	colorModes := []string{"monochrome"}
+	rasterTypes := []string{"sgray_8"}
+	if c.Color {
+		colorModes = []string{"auto", "monochrome", "color"}
+		rasterTypes = append(rasterTypes, "srgb_8")
+	}
+	a.Set(ipp.AttributeColorSupported, ipp.TagBoolean, c.Color)
+	a.Set(ipp.AttributePrintColorModeDefault, ipp.TagKeyword, colorModes[0])
+	a.Set(ipp.AttributePrintColorModeSupported, ipp.TagKeyword, toValues(colorModes)...)
+
+	sides := []string{"one-sided"}
+	if c.Duplex {
+		sides = append(sides, "two-sided-long-edge", "two-sided-short-edge")
+	}
+	a.Set(ipp.AttributeSidesDefault, ipp.TagKeyword, "one-sided")
+	a.Set(ipp.AttributeSidesSupported, ipp.TagKeyword, toValues(sides)...)
+
+	resolutionValues := make([]interface{}, len(resolutions))
+	for i, resolution := range resolutions {
+		resolutionValues[i] = resolution
+	}
+	a.Set(ipp.AttributePrinterResolutionDefault, ipp.TagResolution, resolutions[0])
+	a.Set(ipp.AttributePrinterResolutionSupported, ipp.TagResolution, resolutionValues...)
+	a.Set(ipp.AttributePwgRasterDocumentResolutionSupported, ipp.TagResolution, resolutionValues...)
+	a.Set(ipp.AttributePwgRasterDocumentTypeSupported, ipp.TagKeyword, toValues(rasterTypes)...)
+	a.Set(ipp.AttributePwgRasterDocumentSheetBack, ipp.TagKeyword, "normal")
+	a.Set(ipp.AttributeUrfSupported, ipp.TagKeyword, toValues(urfSupported(c, resolutions))...)
+
+	return a
+}
+
+// SetCapabilities replaces the printer description attributes and document formats of the printer with the ones
+// generated from the capabilities. the name of the printer is kept
+func (p *VirtualPrinter) SetCapabilities(c Capabilities) {
+	attributes := EverywhereAttributes(c)
+	attributes.Set(ipp.AttributePrinterName, ipp.TagName, p.name)
+	if c.Info == "" {
+		attributes.Set(ipp.AttributePrinterInfo, ipp.TagText, p.name)
+	}
+
+	p.Attributes = attributes
+	p.DocumentFormats = attributeStrings(attributes[ipp.AttributeDocumentFormatSupported])
+}
+
+// urfSupported returns the urf-supported keywords matching the capabilities
+func urfSupported(c Capabilities, resolutions []ipp.Resolution) []string {
+	urf := []string{"V1.4", "CP1", "W8"}
+	if c.Color {
+		urf = append(urf, "SRGB24")
+	}
+	if c.Duplex {
+		urf = append(urf, "DM1")
+	}
+
+	dpi := make([]string, 0, len(resolutions))
+	for _, resolution := range resolutions {
+		dpi = append(dpi, fmt.Sprint(resolution.Width))
+	}
+	urf = append(urf, "RS"+strings.Join(dpi, "-"), "IS1")
+
+	return urf
+}
+
+// deviceID returns the ieee 1284 device id of the printer
+func deviceID(makeAndModel string, formats []string) string {
+	manufacturer, model := makeAndModel, makeAndModel
+	if i := strings.IndexByte(makeAndModel, ' '); i > 0 {
+		manufacturer, model = makeAndModel[:i], makeAndModel[i+1:]
+	}
+
+	commands := make([]string, 0, len(formats))
+	for _, format := range formats {
+		switch format {
+		case "application/pdf":
+			commands = append(commands, "PDF")
+		case "image/pwg-raster":
+			commands = append(commands, "PWGRaster")
+		case "image/urf":
+			commands = append(commands, "URF")
+		case "image/jpeg":
+			commands = append(commands, "JPEG")
+		case ipp.MimeTypePostscript:
+			commands = append(commands, "POSTSCRIPT")
+		}
+	}
+
+	return fmt.Sprintf("MFG:%s;MDL:%s;CMD:%s;", manufacturer, model, strings.Join(commands, ","))
+}
+
+func toValues(values []string) []interface{} {
+	v := make([]interface{}, len(values))
+	for i, value := range values {
+		v[i] = value
+	}
+
+	return v
+}
+
+// lookupMediaSizes looks up the media sizes in the pwg registry, names which are not in the registry and are not self
+// describing are skipped
+func lookupMediaSizes(names []string) []ipp.MediaSize {
+	sizes := make([]ipp.MediaSize, 0, len(names))
+	for _, name := range names {
+		if size, ok := ipp.LookupMediaSize(name); ok {
+			sizes = append(sizes, size)
+		}
+	}
+
+	return sizes
+}
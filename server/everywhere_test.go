@@ -0,0 +1,65 @@
+package server
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEverywhereAttributes(t *testing.T) {
+	attributes := EverywhereAttributes(Capabilities{
+		MakeAndModel:    "Example Laser 100",
+		DocumentFormats: []string{"application/pdf"},
+		Media:           []string{"na_letter_8.5x11in", "A4", "unknown"},
+		Resolutions:     []ipp.Resolution{{Width: 600, Height: 600, Depth: 3}, {Width: 300, Height: 300, Depth: 3}},
+		Color:           true,
+		Duplex:          true,
+		Borderless:      true,
+	})
+
+	assert.Equal(t, "application/pdf", attributes[ipp.AttributeDocumentFormatDefault][0].Value)
+	assert.Equal(t, []string{"application/pdf", "image/pwg-raster"}, attributeStrings(attributes[ipp.AttributeDocumentFormatSupported]))
+	assert.Equal(t, "na_letter_8.5x11in", attributes[ipp.AttributeMediaDefault][0].Value)
+	assert.Equal(t, []string{"na_letter_8.5x11in", "iso_a4_210x297mm"}, attributeStrings(attributes[ipp.AttributeMediaSupported]))
+	assert.Len(t, attributes[ipp.AttributeMediaSizeSupported], 2)
+	assert.Len(t, attributes[ipp.AttributeMediaColDatabase], 4)
+	assert.Len(t, attributes[ipp.AttributeMediaTopMarginSupported], 2)
+	assert.Equal(t, []string{"one-sided", "two-sided-long-edge", "two-sided-short-edge"}, attributeStrings(attributes[ipp.AttributeSidesSupported]))
+	assert.Equal(t, []string{"sgray_8", "srgb_8"}, attributeStrings(attributes[ipp.AttributePwgRasterDocumentTypeSupported]))
+	assert.Equal(t, []string{"V1.4", "CP1", "W8", "SRGB24", "DM1", "RS600-300", "IS1"}, attributeStrings(attributes[ipp.AttributeUrfSupported]))
+	assert.Equal(t, "MFG:Example;MDL:Laser 100;CMD:PDF,PWGRaster;", attributes[ipp.AttributePrinterDeviceID][0].Value)
+	assert.Equal(t, ipp.Resolution{Width: 600, Height: 600, Depth: 3}, attributes[ipp.AttributePrinterResolutionDefault][0].Value)
+
+	// the whole set must be encodable
+	resp := ipp.NewResponse(ipp.StatusOk, 1)
+	resp.PrinterAttributes = append(resp.PrinterAttributes, attributes)
+	b, err := resp.Encode()
+	assert.Nil(t, err)
+
+	decoded, err := ipp.NewResponseDecoder(bytes.NewReader(b)).Decode(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{1, 999}, decoded.PrinterAttributes[0][ipp.AttributeCopiesSupported][0].Value)
+	assert.Equal(t, "", decoded.PrinterAttributes[0][ipp.AttributePrinterGeoLocation][0].Value)
+	assert.Equal(t, ipp.TagUnknown, decoded.PrinterAttributes[0][ipp.AttributePrinterGeoLocation][0].Tag)
+
+	var description ipp.PrinterDescription
+	assert.Nil(t, description.Unmarshal(decoded.PrinterAttributes[0]))
+	assert.Equal(t, 21590, description.MediaColDefault.Width)
+	assert.Equal(t, &ipp.MediaMargins{Top: 423, Bottom: 423, Left: 423, Right: 423}, description.MediaColDefault.Margins)
+	assert.True(t, description.SupportsPrintScaling(ipp.PrintScalingFill))
+	assert.True(t, description.SupportsPrintContentOptimize(ipp.PrintContentOptimizePhoto))
+	assert.True(t, description.SupportsPrintRenderingIntent(ipp.PrintRenderingIntentPerceptual))
+}
+
+func TestVirtualPrinter_SetCapabilities(t *testing.T) {
+	printer := NewVirtualPrinter("office", nil)
+	printer.SetCapabilities(Capabilities{MakeAndModel: "Example Inkjet"})
+
+	assert.Equal(t, []string{"image/pwg-raster"}, printer.DocumentFormats)
+	assert.Equal(t, "office", printer.Attributes[ipp.AttributePrinterName][0].Value)
+	assert.Equal(t, "office", printer.Attributes[ipp.AttributePrinterInfo][0].Value)
+	assert.Equal(t, "monochrome", printer.Attributes[ipp.AttributePrintColorModeDefault][0].Value)
+	assert.Equal(t, "F", printer.TXTRecord("/ipp/print", false)["Color"])
+}
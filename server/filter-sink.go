@@ -0,0 +1,43 @@
+package server
+
+import (
+	"io"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// FilterSink is a DocumentSink which normalizes received documents with document filters, e.g. a pdf job is
+// converted into pwg raster for a RasterSink. documents in one of the Formats are passed unchanged
+type FilterSink struct {
+	// Filters converts the documents
+	Filters *ipp.FilterRegistry
+	// Formats are the formats the Sink handles, the first format reachable with the shortest chain of filters is used
+	Formats []string
+	// Sink receives the converted documents, the format of the passed document is the converted format
+	Sink DocumentSink
+}
+
+// DocumentFormats returns the formats the sink accepts, the Formats and the formats the filters convert into them.
+// they can be used as the DocumentFormats of a VirtualPrinter
+func (s *FilterSink) DocumentFormats() []string {
+	return s.Filters.InputFormats(s.Formats...)
+}
+
+// WriteDocument converts the document and passes it to the Sink. documents which can not be converted abort the
+// job with an error wrapping ipp.NoFilterError
+func (s *FilterSink) WriteDocument(job *Job, doc Document, data io.Reader) error {
+	converted, format, err := s.Filters.Transform(data, doc.Format, s.Formats...)
+	if err != nil {
+		return err
+	}
+
+	doc.Format = format
+	err = s.Sink.WriteDocument(job, doc, converted)
+
+	// the filters of a chain only terminate once their output is consumed
+	if _, copyErr := io.Copy(io.Discard, converted); err == nil {
+		err = copyErr
+	}
+
+	return err
+}
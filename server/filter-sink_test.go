@@ -0,0 +1,36 @@
+package server
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterSink(t *testing.T) {
+	var formats, documents []string
+	sink := &FilterSink{
+		Filters: ipp.NewFilterRegistry(ipp.NewDocumentFilter("text/plain", ipp.MimeTypePDF, func(r io.Reader) io.Reader {
+			return io.MultiReader(strings.NewReader("%PDF "), r)
+		})),
+		Formats: []string{ipp.MimeTypePDF},
+		Sink: DocumentSinkFunc(func(job *Job, doc Document, data io.Reader) error {
+			b, err := io.ReadAll(data)
+			formats = append(formats, doc.Format)
+			documents = append(documents, string(b))
+			return err
+		}),
+	}
+
+	assert.Equal(t, []string{ipp.MimeTypePDF, "text/plain"}, sink.DocumentFormats())
+
+	assert.Nil(t, sink.WriteDocument(&Job{}, Document{Format: "text/plain"}, strings.NewReader("hello")))
+	assert.Nil(t, sink.WriteDocument(&Job{}, Document{Format: ipp.MimeTypePDF}, strings.NewReader("%PDF")))
+	assert.Equal(t, []string{ipp.MimeTypePDF, ipp.MimeTypePDF}, formats)
+	assert.Equal(t, []string{"%PDF hello", "%PDF"}, documents)
+
+	err := sink.WriteDocument(&Job{}, Document{Format: "image/jpeg"}, strings.NewReader(""))
+	assert.ErrorIs(t, err, ipp.NoFilterError)
+}
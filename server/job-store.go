@@ -0,0 +1,341 @@
+package server
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/phin1x/go-ipp"
+)
+
+var JobNotFoundError = errors.New("job does not exist")
+
+// JobStore stores the jobs of a printer. implementations must be safe for concurrent use and must return copies
+// of the stored jobs, so modifications are only persisted through Update
+type JobStore interface {
+	// Create stores a new job and assigns its id
+	Create(job *Job) error
+	// Get returns the job with the given id or JobNotFoundError
+	Get(id int) (*Job, error)
+	// List returns all jobs ordered by id
+	List() ([]*Job, error)
+	// Update calls fn with the stored job and persists the modifications if fn returns no error
+	Update(id int, fn func(job *Job) error) (*Job, error)
+	// AddDocument attaches the metadata of a received document to a job
+	AddDocument(id int, doc Document) error
+	// Delete removes a job
+	Delete(id int) error
+}
+
+// Document defines the metadata of a document of a job
+type Document struct {
+	Number int
+	Name   string
+	Format string
+	// Size is the number of received bytes, it is zero while the document is passed to a DocumentSink
+	Size int64
+	// Retained is set if the document data is kept in the DocumentArchive of the printer
+	Retained bool
+}
+
+// MemoryJobStore implements a JobStore which keeps all jobs in memory
+type MemoryJobStore struct {
+	mu        sync.Mutex
+	jobs      map[int]*Job
+	lastJobID int
+}
+
+// NewMemoryJobStore creates a new empty in memory job store
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{
+		jobs: make(map[int]*Job),
+	}
+}
+
+// Create stores a new job and assigns its id
+func (s *MemoryJobStore) Create(job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastJobID++
+	job.ID = s.lastJobID
+	s.jobs[job.ID] = job.Copy()
+
+	return nil
+}
+
+// Get returns the job with the given id
+func (s *MemoryJobStore) Get(id int) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, JobNotFoundError
+	}
+
+	return job.Copy(), nil
+}
+
+// List returns all jobs ordered by id
+func (s *MemoryJobStore) List() ([]*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job.Copy())
+	}
+
+	sortJobs(jobs)
+
+	return jobs, nil
+}
+
+// Update calls fn with the stored job and keeps the modifications if fn returns no error
+func (s *MemoryJobStore) Update(id int, fn func(job *Job) error) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, JobNotFoundError
+	}
+
+	updated := job.Copy()
+	if err := fn(updated); err != nil {
+		return nil, err
+	}
+	updated.ID = id
+	s.jobs[id] = updated
+
+	return updated.Copy(), nil
+}
+
+// AddDocument attaches the metadata of a received document to a job
+func (s *MemoryJobStore) AddDocument(id int, doc Document) error {
+	_, err := s.Update(id, func(job *Job) error {
+		job.addDocument(doc)
+		return nil
+	})
+
+	return err
+}
+
+// Delete removes a job
+func (s *MemoryJobStore) Delete(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[id]; !ok {
+		return JobNotFoundError
+	}
+	delete(s.jobs, id)
+
+	return nil
+}
+
+// SpoolJobStore implements a JobStore which persists every job as a file in a spool directory,
+// so the jobs survive restarts of the server
+type SpoolJobStore struct {
+	directory string
+	memory    *MemoryJobStore
+
+	mu sync.Mutex
+}
+
+// the values of job attributes are stored as interfaces, collections like media-col are ipp.Attributes
+func init() {
+	gob.Register(ipp.Resolution{})
+	gob.Register(ipp.Attributes{})
+	gob.Register([]ipp.Attribute{})
+}
+
+// NewSpoolJobStore creates a job store for the given spool directory and loads all jobs already stored in it
+func NewSpoolJobStore(directory string) (*SpoolJobStore, error) {
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create spool directory: %w", err)
+	}
+
+	s := &SpoolJobStore{
+		directory: directory,
+		memory:    NewMemoryJobStore(),
+	}
+
+	files, err := ioutil.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read spool directory: %w", err)
+	}
+
+	for _, fi := range files {
+		if fi.IsDir() || !strings.HasPrefix(fi.Name(), "j") || !strings.HasSuffix(fi.Name(), ".job") {
+			continue
+		}
+
+		if _, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(fi.Name(), "j"), ".job")); err != nil {
+			continue
+		}
+
+		job, err := s.load(filepath.Join(directory, fi.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		s.memory.jobs[job.ID] = job
+		if job.ID > s.memory.lastJobID {
+			s.memory.lastJobID = job.ID
+		}
+	}
+
+	return s, nil
+}
+
+// Directory returns the spool directory of the store
+func (s *SpoolJobStore) Directory() string {
+	return s.directory
+}
+
+// Create stores a new job and assigns its id. the job is only kept if its file is written
+func (s *SpoolJobStore) Create(job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+
+	previous := job.ID
+	job.ID = s.memory.lastJobID + 1
+	if err := s.save(job); err != nil {
+		job.ID = previous
+		return err
+	}
+
+	s.memory.lastJobID = job.ID
+	s.memory.jobs[job.ID] = job.Copy()
+
+	return nil
+}
+
+// Get returns the job with the given id
+func (s *SpoolJobStore) Get(id int) (*Job, error) {
+	return s.memory.Get(id)
+}
+
+// List returns all jobs ordered by id
+func (s *SpoolJobStore) List() ([]*Job, error) {
+	return s.memory.List()
+}
+
+// Update calls fn with the stored job and persists the modifications if fn returns no error. the stored job is only
+// changed if its file is written
+func (s *SpoolJobStore) Update(id int, fn func(job *Job) error) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.memory.mu.Lock()
+	defer s.memory.mu.Unlock()
+
+	job, ok := s.memory.jobs[id]
+	if !ok {
+		return nil, JobNotFoundError
+	}
+
+	updated := job.Copy()
+	if err := fn(updated); err != nil {
+		return nil, err
+	}
+	updated.ID = id
+
+	if err := s.save(updated); err != nil {
+		return nil, err
+	}
+	s.memory.jobs[id] = updated
+
+	return updated.Copy(), nil
+}
+
+// AddDocument attaches the metadata of a received document to a job
+func (s *SpoolJobStore) AddDocument(id int, doc Document) error {
+	_, err := s.Update(id, func(job *Job) error {
+		job.addDocument(doc)
+		return nil
+	})
+
+	return err
+}
+
+// Delete removes a job and its spool file
+func (s *SpoolJobStore) Delete(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.memory.Delete(id); err != nil {
+		return err
+	}
+
+	if err := os.Remove(s.jobFile(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to remove job file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SpoolJobStore) jobFile(id int) string {
+	return filepath.Join(s.directory, fmt.Sprintf("j%05d.job", id))
+}
+
+// save writes the job to a temporary file which replaces the job file afterwards
+func (s *SpoolJobStore) save(job *Job) error {
+	path := s.jobFile(job.ID)
+
+	f, err := ioutil.TempFile(s.directory, ".tmp-job-")
+	if err != nil {
+		return fmt.Errorf("unable to create job file: %w", err)
+	}
+
+	if err := gob.NewEncoder(f).Encode(job); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("unable to encode job %d: %w", job.ID, err)
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("unable to write job file: %w", err)
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("unable to write job file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SpoolJobStore) load(path string) (*Job, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open job file: %w", err)
+	}
+	defer f.Close()
+
+	job := new(Job)
+	if err := gob.NewDecoder(f).Decode(job); err != nil {
+		return nil, fmt.Errorf("unable to decode job file %s: %w", path, err)
+	}
+
+	return job, nil
+}
+
+func sortJobs(jobs []*Job) {
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].ID < jobs[j].ID
+	})
+}
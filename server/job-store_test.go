@@ -0,0 +1,132 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func testJobStore(t *testing.T, store JobStore) {
+	for i := 0; i < 2; i++ {
+		job := &Job{Name: "test", OriginatingUser: "alice", State: ipp.JobStatePending}
+		assert.Nil(t, store.Create(job))
+		assert.Equal(t, i+1, job.ID)
+	}
+
+	job, err := store.Update(1, func(job *Job) error {
+		job.SetState(ipp.JobStateCompleted, "job-completed-successfully")
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int8(ipp.JobStateCompleted), job.State)
+
+	assert.Nil(t, store.AddDocument(2, Document{Name: "doc", Format: "application/pdf", Size: 2048}))
+
+	job, err = store.Get(2)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, job.NumberOfDocuments)
+	assert.Equal(t, 2, job.KOctets)
+	assert.Equal(t, []Document{{Number: 1, Name: "doc", Format: "application/pdf", Size: 2048}}, job.Documents)
+
+	// returned jobs must not alias the stored jobs
+	job.Name = "changed"
+	job, _ = store.Get(2)
+	assert.Equal(t, "test", job.Name)
+
+	jobs, err := store.List()
+	assert.Nil(t, err)
+	assert.Len(t, jobs, 2)
+	assert.Equal(t, 1, jobs[0].ID)
+
+	assert.Nil(t, store.Delete(1))
+	_, err = store.Get(1)
+	assert.Equal(t, JobNotFoundError, err)
+	assert.Equal(t, JobNotFoundError, store.Delete(1))
+	_, err = store.Update(1, func(job *Job) error { return nil })
+	assert.Equal(t, JobNotFoundError, err)
+}
+
+func TestMemoryJobStore(t *testing.T) {
+	testJobStore(t, NewMemoryJobStore())
+}
+
+func TestSpoolJobStore(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewSpoolJobStore(dir)
+	assert.Nil(t, err)
+	testJobStore(t, store)
+
+	reloaded, err := NewSpoolJobStore(dir)
+	assert.Nil(t, err)
+
+	job, err := reloaded.Get(2)
+	assert.Nil(t, err)
+	assert.Equal(t, "alice", job.OriginatingUser)
+	assert.Len(t, job.Documents, 1)
+
+	job = &Job{Name: "next"}
+	assert.Nil(t, reloaded.Create(job))
+	assert.Equal(t, 3, job.ID)
+}
+
+func TestSpoolJobStore_Collections(t *testing.T) {
+	dir := t.TempDir()
+
+	size := make(ipp.Attributes)
+	size.Set(ipp.AttributeXDimension, ipp.TagInteger, 21000)
+	size.Set(ipp.AttributeYDimension, ipp.TagInteger, 29700)
+	mediaCol := make(ipp.Attributes)
+	mediaCol.Set(ipp.AttributeMediaSize, ipp.TagBeginCollection, size)
+	mediaCol.Set(ipp.AttributeMediaType, ipp.TagKeyword, "stationery")
+
+	job := &Job{Name: "collection", Attributes: make(ipp.Attributes)}
+	job.Attributes.Set(ipp.AttributeMediaCol, ipp.TagBeginCollection, mediaCol)
+
+	store, err := NewSpoolJobStore(dir)
+	assert.Nil(t, err)
+	assert.Nil(t, store.Create(job))
+
+	reloaded, err := NewSpoolJobStore(dir)
+	assert.Nil(t, err)
+	job, err = reloaded.Get(job.ID)
+	if assert.Nil(t, err) {
+		assert.Equal(t, mediaCol, job.Attributes[ipp.AttributeMediaCol][0].Value)
+	}
+}
+
+func TestSpoolJobStore_SaveError(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "spool")
+
+	store, err := NewSpoolJobStore(dir)
+	assert.Nil(t, err)
+	job := &Job{Name: "saved", State: ipp.JobStatePending}
+	assert.Nil(t, store.Create(job))
+
+	// without the spool directory the jobs can't be written, the jobs in memory must stay unchanged
+	assert.Nil(t, os.RemoveAll(dir))
+
+	_, err = store.Update(job.ID, func(job *Job) error {
+		job.SetState(ipp.JobStateCompleted)
+		return nil
+	})
+	assert.NotNil(t, err)
+	stored, err := store.Get(job.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, int8(ipp.JobStatePending), stored.State)
+
+	failed := &Job{Name: "failed"}
+	assert.NotNil(t, store.Create(failed))
+	assert.Equal(t, 0, failed.ID)
+	jobs, err := store.List()
+	assert.Nil(t, err)
+	assert.Len(t, jobs, 1)
+
+	// the id of the failed job is used for the next job
+	assert.Nil(t, os.MkdirAll(dir, 0700))
+	assert.Nil(t, store.Create(failed))
+	assert.Equal(t, 2, failed.ID)
+}
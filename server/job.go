@@ -0,0 +1,90 @@
+package server
+
+import (
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// Job defines a job received by a printer of the server
+type Job struct {
+	ID                int
+	UUID              string
+	Name              string
+	OriginatingUser   string
+	State             int8
+	StateReasons      []string
+	StateMessage      string
+	DocumentFormat    string
+	NumberOfDocuments int
+	KOctets           int
+	Impressions       int
+
+	// Attributes contains the job template attributes requested by the client
+	Attributes ipp.Attributes
+	Documents  []Document
+
+	CreatedAt    time.Time
+	ProcessingAt time.Time
+	CompletedAt  time.Time
+}
+
+// IsTerminated checks if the job is in one of the terminating states canceled, aborted or completed
+func (j *Job) IsTerminated() bool {
+	return j.State == ipp.JobStateCanceled || j.State == ipp.JobStateAborted || j.State == ipp.JobStateCompleted
+}
+
+// SetState changes the state of the job and records the time of the transition
+func (j *Job) SetState(state int8, reasons ...string) {
+	j.State = state
+
+	if len(reasons) == 0 {
+		reasons = []string{"none"}
+	}
+	j.StateReasons = reasons
+
+	switch state {
+	case ipp.JobStateProcessing:
+		if j.ProcessingAt.IsZero() {
+			j.ProcessingAt = time.Now()
+		}
+	case ipp.JobStateCanceled, ipp.JobStateAborted, ipp.JobStateCompleted:
+		if j.ProcessingAt.IsZero() {
+			j.ProcessingAt = time.Now()
+		}
+		j.CompletedAt = time.Now()
+	}
+}
+
+// Copy returns a deep copy of the job
+func (j *Job) Copy() *Job {
+	c := *j
+	c.StateReasons = append([]string(nil), j.StateReasons...)
+	c.Documents = append([]Document(nil), j.Documents...)
+
+	c.Attributes = make(ipp.Attributes, len(j.Attributes))
+	for name, attr := range j.Attributes {
+		c.Attributes[name] = append([]ipp.Attribute(nil), attr...)
+	}
+
+	return &c
+}
+
+// addDocument appends the document metadata and updates the document counters of the job
+func (j *Job) addDocument(doc Document) {
+	doc.Number = len(j.Documents) + 1
+	j.Documents = append(j.Documents, doc)
+	j.NumberOfDocuments = len(j.Documents)
+	j.DocumentFormat = doc.Format
+
+	var size int64
+	for _, d := range j.Documents {
+		size += d.Size
+	}
+	j.KOctets = kiloOctets(size)
+}
+
+// kiloOctets rounds the number of bytes up to kilo octets
+func kiloOctets(bytes int64) int {
+	return int((bytes + 1023) / 1024)
+}
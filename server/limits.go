@@ -0,0 +1,204 @@
+package server
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+var RequestTooLargeError = errors.New("request exceeds the maximum request size")
+
+// maxRateLimitClients is the number of clients tracked by the rate limiter before idle clients are removed
+const maxRateLimitClients = 1024
+
+// Limits defines the resource limits of a Server, a zero value disables the limit. requests exceeding a size limit are
+// answered with client-error-request-entity-too-large, requests exceeding a load limit with server-error-busy
+type Limits struct {
+	// MaxRequestSize is the maximum size of the encoded ipp request without the document data in bytes
+	MaxRequestSize int64
+	// MaxDocumentSize is the maximum size of the document data of a request in bytes
+	MaxDocumentSize int64
+	// MaxConcurrentRequests is the maximum number of requests served at the same time
+	MaxConcurrentRequests int
+	// RequestsPerSecond is the sustained request rate allowed per client ip address, Burst is the number of requests
+	// a client can send at once, it defaults to one second of requests
+	RequestsPerSecond float64
+	Burst             int
+}
+
+// limitReader returns an error after n bytes have been read
+type limitReader struct {
+	reader io.Reader
+	n      int64
+	err    error
+}
+
+func (r *limitReader) Read(b []byte) (int, error) {
+	if r.n <= 0 {
+		// check if the data ends exactly at the limit
+		var probe [1]byte
+		if n, err := r.reader.Read(probe[:]); n == 0 && err != nil {
+			return 0, err
+		}
+		return 0, r.err
+	}
+
+	if int64(len(b)) > r.n {
+		b = b[:r.n]
+	}
+
+	n, err := r.reader.Read(b)
+	r.n -= int64(n)
+	return n, err
+}
+
+// requestReader limits the ipp request of the body to the maximum request size
+func (s *Server) requestReader(body io.Reader) io.Reader {
+	if s.Limits.MaxRequestSize <= 0 {
+		return body
+	}
+
+	return &limitReader{reader: body, n: s.Limits.MaxRequestSize, err: RequestTooLargeError}
+}
+
+// documentReader limits the document data of the body to the maximum document size
+func (s *Server) documentReader(body io.Reader) io.Reader {
+	if s.Limits.MaxDocumentSize <= 0 {
+		return body
+	}
+
+	return &limitReader{
+		reader: body,
+		n:      s.Limits.MaxDocumentSize,
+		err: ipp.StatusError{
+			Status:  ipp.StatusErrorRequestEntity,
+			Message: fmt.Sprintf("document exceeds the maximum size of %d bytes", s.Limits.MaxDocumentSize),
+		},
+	}
+}
+
+// tooLargeResponse answers a request exceeding the maximum request size, the request id is read from the header
+// of the request because the request could not be decoded
+func tooLargeResponse(header []byte) *ipp.Response {
+	return headerErrorResponse(header, ipp.StatusErrorRequestEntity, RequestTooLargeError.Error())
+}
+
+// headerErrorResponse returns an error response for a request which couldn't be decoded, the request id and version
+// are taken from the header of the request
+func headerErrorResponse(header []byte, status int16, message string) *ipp.Response {
+	var requestID int32
+	if len(header) >= 8 {
+		requestID = int32(binary.BigEndian.Uint32(header[4:8]))
+	}
+
+	req := &Request{Request: ipp.NewRequest(0, requestID)}
+	resp := Error(req, status, message)
+	if len(header) >= 2 && isVersionSupported(int8(header[0]), int8(header[1])) {
+		resp.ProtocolVersionMajor, resp.ProtocolVersionMinor = int8(header[0]), int8(header[1])
+	}
+
+	return resp
+}
+
+// acquire reserves a request slot and checks the rate limit of the client. if the request may be served, the
+// returned function releases the slot, otherwise a server-error-busy response is returned
+func (s *Server) acquire(req *Request) (func(), *ipp.Response) {
+	release := func() {}
+	if max := s.Limits.MaxConcurrentRequests; max > 0 {
+		if atomic.AddInt32(&s.active, 1) > int32(max) {
+			atomic.AddInt32(&s.active, -1)
+			return nil, Error(req, ipp.StatusErrorBusy, "too many concurrent requests")
+		}
+		release = func() {
+			atomic.AddInt32(&s.active, -1)
+		}
+	}
+
+	if s.Limits.RequestsPerSecond > 0 && !s.limiter.allow(clientAddress(req.HTTPRequest), s.Limits, time.Now()) {
+		release()
+		return nil, Error(req, ipp.StatusErrorBusy, "request rate limit exceeded")
+	}
+
+	return release, nil
+}
+
+// rateLimiter implements a token bucket per client
+type rateLimiter struct {
+	mu      sync.Mutex
+	clients map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (l *rateLimiter) allow(client string, limits Limits, now time.Time) bool {
+	burst := float64(limits.Burst)
+	if burst <= 0 {
+		burst = limits.RequestsPerSecond
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.clients == nil {
+		l.clients = make(map[string]*tokenBucket)
+	}
+
+	bucket, ok := l.clients[client]
+	if !ok {
+		if len(l.clients) >= maxRateLimitClients {
+			l.removeIdle(limits.RequestsPerSecond, burst, now)
+		}
+		bucket = &tokenBucket{tokens: burst, last: now}
+		l.clients[client] = bucket
+	}
+
+	bucket.tokens += now.Sub(bucket.last).Seconds() * limits.RequestsPerSecond
+	if bucket.tokens > burst {
+		bucket.tokens = burst
+	}
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+
+	bucket.tokens--
+	return true
+}
+
+// removeIdle removes the buckets of clients which are refilled completely, the lock must be held
+func (l *rateLimiter) removeIdle(rate, burst float64, now time.Time) {
+	for client, bucket := range l.clients {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*rate >= burst {
+			delete(l.clients, client)
+		}
+	}
+}
+
+// clientAddress returns the ip address of the client of a http request
+func clientAddress(r *http.Request) string {
+	if r == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveTestDocument(t *testing.T, handler http.Handler, path string, req *ipp.Request, document []byte) *ipp.Response {
+	payload, err := req.Encode()
+	assert.Nil(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(append(payload, document...)))
+	r.Header.Set("Content-Type", ipp.ContentTypeIPP)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	resp, err := ipp.NewResponseDecoder(rec.Body).Decode(nil)
+	assert.Nil(t, err)
+
+	return resp
+}
+
+func TestServer_Limits(t *testing.T) {
+	printer := NewVirtualPrinter("test", nil)
+	printer.MaxActiveJobs = 1
+
+	s := NewServer()
+	s.Limits.MaxRequestSize = 512
+	s.Limits.MaxDocumentSize = 16
+	printer.Register(s, "/ipp/print")
+
+	printerURI := "ipp://localhost/ipp/print"
+
+	req := newPrinterRequest(ipp.OperationGetPrinterAttributes, printerURI)
+	req.RequestId = 42
+	req.OperationAttributes[ipp.AttributeRequestingUserName] = strings.Repeat("a", 600)
+	resp := serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusErrorRequestEntity, resp.StatusCode)
+	assert.Equal(t, int32(42), resp.RequestId)
+
+	req = newPrinterRequest(ipp.OperationPrintJob, printerURI)
+	resp = serveTestDocument(t, s, "/ipp/print", req, bytes.Repeat([]byte("x"), 16))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+
+	req = newPrinterRequest(ipp.OperationPrintJob, printerURI)
+	resp = serveTestDocument(t, s, "/ipp/print", req, bytes.Repeat([]byte("x"), 17))
+	assert.Equal(t, ipp.StatusErrorRequestEntity, resp.StatusCode)
+
+	job, _ := printer.Job(2)
+	assert.Equal(t, ipp.JobStateAborted, job.State)
+
+	resp = serveTestRequest(t, s, "/ipp/print", newPrinterRequest(ipp.OperationCreateJob, printerURI))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+
+	resp = serveTestRequest(t, s, "/ipp/print", newPrinterRequest(ipp.OperationCreateJob, printerURI))
+	assert.Equal(t, ipp.StatusErrorBusy, resp.StatusCode)
+}
+
+func TestServer_MaxConcurrentRequests(t *testing.T) {
+	s := NewServer()
+	s.Limits.MaxConcurrentRequests = 1
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	s.HandleFunc(ipp.OperationGetPrinterAttributes, func(req *Request) (*ipp.Response, error) {
+		close(started)
+		<-unblock
+		return nil, nil
+	})
+	s.HandleFunc(ipp.OperationGetJobs, func(req *Request) (*ipp.Response, error) {
+		return nil, nil
+	})
+
+	done := make(chan *ipp.Response)
+	go func() {
+		done <- serveTestRequest(t, s, "/", newPrinterRequest(ipp.OperationGetPrinterAttributes, "ipp://localhost/"))
+	}()
+	<-started
+
+	resp := serveTestRequest(t, s, "/", newPrinterRequest(ipp.OperationGetJobs, "ipp://localhost/"))
+	assert.Equal(t, ipp.StatusErrorBusy, resp.StatusCode)
+
+	close(unblock)
+	assert.Equal(t, ipp.StatusOk, (<-done).StatusCode)
+
+	resp = serveTestRequest(t, s, "/", newPrinterRequest(ipp.OperationGetJobs, "ipp://localhost/"))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+}
+
+func TestRateLimiter(t *testing.T) {
+	var l rateLimiter
+	limits := Limits{RequestsPerSecond: 2, Burst: 3}
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, l.allow("10.0.0.1", limits, now))
+	}
+	assert.False(t, l.allow("10.0.0.1", limits, now))
+	assert.True(t, l.allow("10.0.0.2", limits, now))
+
+	assert.True(t, l.allow("10.0.0.1", limits, now.Add(500*time.Millisecond)))
+	assert.False(t, l.allow("10.0.0.1", limits, now.Add(500*time.Millisecond)))
+}
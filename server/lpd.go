@@ -0,0 +1,550 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+var LPDBridgeClosedError = errors.New("lpd bridge is closed")
+
+// DefaultLPDAddress is the listen address of the line printer daemon protocol
+const DefaultLPDAddress = ":515"
+
+// lpd daemon commands (rfc 1179)
+const (
+	lpdPrintWaitingJobs = 0x01
+	lpdReceiveJob       = 0x02
+	lpdQueueStateShort  = 0x03
+	lpdQueueStateLong   = 0x04
+	lpdRemoveJobs       = 0x05
+)
+
+// lpd receive job subcommands
+const (
+	lpdAbortJob        = 0x01
+	lpdControlFile     = 0x02
+	lpdDataFile        = 0x03
+	lpdAcknowledge     = 0x00
+	lpdNegativeAnswer  = 0x01
+	lpdMaxCommandBytes = 1024
+)
+
+// lpdConnectionTimeout closes connections of clients which do not send data
+const lpdConnectionTimeout = 5 * time.Minute
+
+// LPDBridge accepts jobs submitted with the line printer daemon protocol (rfc 1179) and injects them as ipp jobs
+// into the printers of a Server, so legacy unix clients can print to printers served with this package. the
+// requests pass through the server like ipp requests, including validation, authorization and limits
+type LPDBridge struct {
+	Server *Server
+	// SpoolDirectory stores the data files until the job is complete, defaults to the temp directory
+	SpoolDirectory string
+	// MaxFileSize limits the size of control and data files, zero means unlimited
+	MaxFileSize int64
+
+	mu        sync.Mutex
+	queues    map[string]string
+	listeners []net.Listener
+	closed    bool
+}
+
+// NewLPDBridge creates a lpd bridge which submits the jobs to the printers of the server
+func NewLPDBridge(s *Server) *LPDBridge {
+	return &LPDBridge{
+		Server: s,
+		queues: make(map[string]string),
+	}
+}
+
+// AddQueue makes the printer endpoint with the http path available as lpd queue with the given name
+func (b *LPDBridge) AddQueue(name, path string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.queues[name] = cleanPath(path)
+}
+
+func (b *LPDBridge) queue(name string) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	path, ok := b.queues[name]
+	return path, ok
+}
+
+// ListenAndServe listens on the tcp address, DefaultLPDAddress if empty, and serves lpd connections
+func (b *LPDBridge) ListenAndServe(address string) error {
+	if address == "" {
+		address = DefaultLPDAddress
+	}
+
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	return b.Serve(l)
+}
+
+// Serve accepts lpd connections on the listener until the bridge is closed
+func (b *LPDBridge) Serve(l net.Listener) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return LPDBridgeClosedError
+	}
+	b.listeners = append(b.listeners, l)
+	b.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			b.mu.Lock()
+			closed := b.closed
+			b.mu.Unlock()
+
+			if closed {
+				return LPDBridgeClosedError
+			}
+			return err
+		}
+
+		go b.serveConn(conn)
+	}
+}
+
+// Close stops all listeners of the bridge
+func (b *LPDBridge) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for _, l := range b.listeners {
+		l.Close()
+	}
+	b.listeners = nil
+
+	return nil
+}
+
+func (b *LPDBridge) serveConn(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(lpdConnectionTimeout))
+
+	r := bufio.NewReader(conn)
+	command, args, err := readLPDCommand(r)
+	if err != nil {
+		return
+	}
+
+	queue := ""
+	if len(args) > 0 {
+		queue = args[0]
+	}
+
+	path, ok := b.queue(queue)
+
+	switch command {
+	case lpdPrintWaitingJobs:
+		// jobs are printed as soon as they are received
+	case lpdReceiveJob:
+		if !ok {
+			_, _ = conn.Write([]byte{lpdNegativeAnswer})
+			return
+		}
+		_, _ = conn.Write([]byte{lpdAcknowledge})
+		b.receiveJob(conn, r, path)
+	case lpdQueueStateShort, lpdQueueStateLong:
+		if !ok {
+			fmt.Fprintf(conn, "%s: unknown printer\n", queue)
+			return
+		}
+		b.queueState(conn, conn.RemoteAddr(), path, command == lpdQueueStateLong)
+	case lpdRemoveJobs:
+		if ok && len(args) > 1 {
+			b.removeJobs(conn.RemoteAddr(), path, args[1], args[2:])
+		}
+	}
+}
+
+// lpdJob collects the files of a job received with the receive job command
+type lpdJob struct {
+	control map[byte][]string
+	files   map[string]*os.File
+}
+
+// receiveJob reads the subcommands of a receive job command, the job is submitted when the control file and all
+// data files referenced by it are received
+func (b *LPDBridge) receiveJob(conn net.Conn, r *bufio.Reader, path string) {
+	job := &lpdJob{files: make(map[string]*os.File)}
+	defer job.remove()
+
+	for {
+		command, args, err := readLPDCommand(r)
+		if err != nil {
+			return
+		}
+
+		switch command {
+		case lpdAbortJob:
+			job.remove()
+			job = &lpdJob{files: make(map[string]*os.File)}
+			continue
+		case lpdControlFile, lpdDataFile:
+		default:
+			_, _ = conn.Write([]byte{lpdNegativeAnswer})
+			return
+		}
+
+		if len(args) < 2 {
+			_, _ = conn.Write([]byte{lpdNegativeAnswer})
+			return
+		}
+
+		size, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || size < 0 || (b.MaxFileSize > 0 && size > b.MaxFileSize) {
+			_, _ = conn.Write([]byte{lpdNegativeAnswer})
+			return
+		}
+		_, _ = conn.Write([]byte{lpdAcknowledge})
+
+		if command == lpdControlFile {
+			err = job.readControlFile(r, size)
+		} else {
+			err = job.readDataFile(r, args[1], size, b.SpoolDirectory)
+		}
+		if err != nil {
+			return
+		}
+
+		// every file is terminated with a zero byte
+		if terminator, err := r.ReadByte(); err != nil || terminator != 0 {
+			return
+		}
+
+		if job.complete() {
+			if err := b.submit(conn.RemoteAddr(), path, job); err != nil {
+				_, _ = conn.Write([]byte{lpdNegativeAnswer})
+				return
+			}
+			job.remove()
+			job = &lpdJob{files: make(map[string]*os.File)}
+		}
+
+		_, _ = conn.Write([]byte{lpdAcknowledge})
+	}
+}
+
+func (j *lpdJob) readControlFile(r io.Reader, size int64) error {
+	data, err := ioutil.ReadAll(io.LimitReader(r, size))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return io.ErrUnexpectedEOF
+	}
+
+	j.control = make(map[byte][]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		j.control[line[0]] = append(j.control[line[0]], line[1:])
+	}
+
+	return nil
+}
+
+func (j *lpdJob) readDataFile(r io.Reader, name string, size int64, directory string) error {
+	file, err := ioutil.TempFile(directory, "lpd-")
+	if err != nil {
+		return err
+	}
+
+	if old, ok := j.files[name]; ok {
+		old.Close()
+		os.Remove(old.Name())
+	}
+	j.files[name] = file
+
+	n, err := io.Copy(file, io.LimitReader(r, size))
+	if err != nil {
+		return err
+	}
+	if n != size {
+		return io.ErrUnexpectedEOF
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	return err
+}
+
+// documents returns the data file names of the control file in print order with their document format and number
+// of copies, a file printed multiple times is listed once
+func (j *lpdJob) documents() ([]string, map[string]string, int) {
+	var names []string
+	formats := make(map[string]string)
+	copies := make(map[string]int)
+
+	for _, command := range []byte("cdfglnoprtv") {
+		for _, name := range j.control[command] {
+			if _, ok := formats[name]; !ok {
+				names = append(names, name)
+				formats[name] = lpdDocumentFormat(command)
+			}
+			copies[name]++
+		}
+	}
+
+	maxCopies := 1
+	for _, n := range copies {
+		if n > maxCopies {
+			maxCopies = n
+		}
+	}
+
+	return names, formats, maxCopies
+}
+
+// complete checks if the control file and all referenced data files are received
+func (j *lpdJob) complete() bool {
+	if j.control == nil {
+		return false
+	}
+
+	names, _, _ := j.documents()
+	for _, name := range names {
+		if _, ok := j.files[name]; !ok {
+			return false
+		}
+	}
+
+	return len(names) > 0
+}
+
+func (j *lpdJob) value(command byte) string {
+	if values := j.control[command]; len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+func (j *lpdJob) remove() {
+	for name, file := range j.files {
+		file.Close()
+		os.Remove(file.Name())
+		delete(j.files, name)
+	}
+}
+
+// lpdDocumentFormat maps the print command of a control file line to a document format, the printer must support
+// text/plain to accept formatted text files
+func lpdDocumentFormat(command byte) string {
+	switch command {
+	case 'f', 'p':
+		return "text/plain"
+	case 'o':
+		return ipp.MimeTypePostscript
+	}
+
+	// l prints the data unchanged, the printer detects the format
+	return ipp.MimeTypeOctetStream
+}
+
+// submit injects the job into the printer with Print-Job or, for multiple documents, with Create-Job and
+// Send-Document requests
+func (b *LPDBridge) submit(client net.Addr, path string, job *lpdJob) error {
+	names, formats, copies := job.documents()
+
+	name := job.value('J')
+	if name == "" {
+		name = job.value('N')
+	}
+
+	user := job.value('P')
+
+	newRequest := func(op int16) *ipp.Request {
+		req := ipp.NewRequest(op, 1)
+		req.OperationAttributes[ipp.AttributePrinterURI] = "ipp://localhost" + path
+		req.OperationAttributes[ipp.AttributeRequestingUserName] = user
+		return req
+	}
+
+	addDocument := func(req *ipp.Request, i int) {
+		file := job.files[names[i]]
+		req.OperationAttributes[ipp.AttributeDocumentFormat] = formats[names[i]]
+		if documentName := job.control['N']; i < len(documentName) {
+			req.OperationAttributes[ipp.AttributeDocumentName] = documentName[i]
+		}
+		req.File = file
+	}
+
+	if len(names) == 1 {
+		req := newRequest(ipp.OperationPrintJob)
+		req.OperationAttributes[ipp.AttributeJobName] = name
+		req.JobAttributes[ipp.AttributeCopies] = copies
+		addDocument(req, 0)
+
+		_, err := b.serve(client, path, req)
+		return err
+	}
+
+	req := newRequest(ipp.OperationCreateJob)
+	req.OperationAttributes[ipp.AttributeJobName] = name
+	req.JobAttributes[ipp.AttributeCopies] = copies
+
+	resp, err := b.serve(client, path, req)
+	if err != nil {
+		return err
+	}
+
+	if len(resp.JobAttributes) == 0 || len(resp.JobAttributes[0][ipp.AttributeJobID]) == 0 {
+		return errors.New("missing job-id in create-job response")
+	}
+	jobID := resp.JobAttributes[0][ipp.AttributeJobID][0].Value
+
+	for i := range names {
+		req := newRequest(ipp.OperationSendDocument)
+		req.OperationAttributes[ipp.AttributeJobID] = jobID
+		req.OperationAttributes[ipp.AttributeLastDocument] = i == len(names)-1
+		addDocument(req, i)
+
+		if _, err := b.serve(client, path, req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// queueState writes the jobs of the printer in a human readable format
+func (b *LPDBridge) queueState(w io.Writer, client net.Addr, path string, long bool) {
+	req := ipp.NewRequest(ipp.OperationGetJobs, 1)
+	req.OperationAttributes[ipp.AttributePrinterURI] = "ipp://localhost" + path
+	req.OperationAttributes[ipp.AttributeRequestedAttributes] = []string{
+		ipp.AttributeJobID, ipp.AttributeJobName, ipp.AttributeJobOriginatingUserName, ipp.AttributeJobState,
+		ipp.AttributeJobKilobyteOctets,
+	}
+
+	resp, err := b.serve(client, path, req)
+	if err != nil {
+		fmt.Fprintf(w, "%s\n", err)
+		return
+	}
+
+	if len(resp.JobAttributes) == 0 {
+		fmt.Fprintf(w, "no entries\n")
+		return
+	}
+
+	fmt.Fprintf(w, "Rank   Owner      Job  Files                                 Total Size\n")
+	for i, attributes := range resp.JobAttributes {
+		rank := "active"
+		if i > 0 {
+			rank = strconv.Itoa(i + 1)
+		}
+
+		id, _ := attributeValue(attributes, ipp.AttributeJobID).(int)
+		owner, _ := attributeValue(attributes, ipp.AttributeJobOriginatingUserName).(string)
+		name, _ := attributeValue(attributes, ipp.AttributeJobName).(string)
+		size, _ := attributeValue(attributes, ipp.AttributeJobKilobyteOctets).(int)
+
+		if long {
+			fmt.Fprintf(w, "\n%s: %s [job %d]\n\t%-37s %d bytes\n", owner, rank, id, name, size*1024)
+		} else {
+			fmt.Fprintf(w, "%-6s %-10s %-4d %-37s %d bytes\n", rank, owner, id, name, size*1024)
+		}
+	}
+}
+
+// removeJobs cancels the jobs with the given ids on behalf of the agent
+func (b *LPDBridge) removeJobs(client net.Addr, path string, agent string, jobs []string) {
+	for _, job := range jobs {
+		id, err := strconv.Atoi(job)
+		if err != nil {
+			continue
+		}
+
+		req := ipp.NewRequest(ipp.OperationCancelJob, 1)
+		req.OperationAttributes[ipp.AttributePrinterURI] = "ipp://localhost" + path
+		req.OperationAttributes[ipp.AttributeRequestingUserName] = agent
+		req.OperationAttributes[ipp.AttributeJobID] = id
+
+		_, _ = b.serve(client, path, req)
+	}
+}
+
+// serve passes the request to the server as if it was received over http from the lpd client
+func (b *LPDBridge) serve(client net.Addr, path string, req *ipp.Request) (*ipp.Response, error) {
+	httpReq, err := http.NewRequest(http.MethodPost, "http://localhost"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if client != nil {
+		httpReq.RemoteAddr = client.String()
+	}
+
+	// the request is encoded and decoded again, so handlers see the attributes like in requests received over http
+	payload, err := req.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, err := ipp.NewRequestDecoder(bytes.NewReader(payload)).Decode(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded.File = req.File
+	if decoded.File == nil {
+		decoded.File = strings.NewReader("")
+	}
+	decoded.FileSize = -1
+
+	resp := b.Server.serve(&Request{Request: decoded, HTTPRequest: httpReq})
+	if err := resp.CheckForErrors(); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// readLPDCommand reads a command line consisting of the command byte and space separated arguments
+func readLPDCommand(r *bufio.Reader) (byte, []string, error) {
+	line, err := r.ReadSlice('\n')
+	if err != nil {
+		if err == bufio.ErrBufferFull {
+			return 0, nil, errors.New("lpd command too long")
+		}
+		return 0, nil, err
+	}
+
+	if len(line) < 2 || len(line) > lpdMaxCommandBytes {
+		return 0, nil, errors.New("invalid lpd command")
+	}
+
+	return line[0], strings.Fields(string(line[1 : len(line)-1])), nil
+}
+
+func attributeValue(attributes ipp.Attributes, name string) interface{} {
+	if attr := attributes[name]; len(attr) > 0 {
+		return attr[0].Value
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLPDBridge(t *testing.T) (*LPDBridge, string, *VirtualPrinter, *bytes.Buffer, func()) {
+	var mu sync.Mutex
+	var received bytes.Buffer
+
+	printer := NewVirtualPrinter("test", nil)
+	printer.Sink = DocumentSinkFunc(func(job *Job, doc Document, data io.Reader) error {
+		mu.Lock()
+		defer mu.Unlock()
+		_, err := io.Copy(&received, data)
+		return err
+	})
+
+	s := NewServer()
+	printer.Register(s, "/printers/test")
+
+	bridge := NewLPDBridge(s)
+	bridge.SpoolDirectory = t.TempDir()
+	bridge.AddQueue("lp", "/printers/test")
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	go bridge.Serve(l)
+
+	return bridge, l.Addr().String(), printer, &received, func() {
+		bridge.Close()
+	}
+}
+
+func dialLPD(t *testing.T, address string) net.Conn {
+	conn, err := net.Dial("tcp", address)
+	assert.Nil(t, err)
+	return conn
+}
+
+func sendLPDFile(t *testing.T, conn net.Conn, r *bufio.Reader, command byte, name string, data string) byte {
+	fmt.Fprintf(conn, "%c%d %s\n", command, len(data), name)
+	ack, err := r.ReadByte()
+	assert.Nil(t, err)
+	assert.Equal(t, byte(0), ack)
+
+	conn.Write(append([]byte(data), 0))
+	ack, err = r.ReadByte()
+	assert.Nil(t, err)
+	return ack
+}
+
+func TestLPDBridge_ReceiveJob(t *testing.T) {
+	bridge, address, printer, received, closeBridge := newTestLPDBridge(t)
+	defer closeBridge()
+
+	conn := dialLPD(t, address)
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	fmt.Fprintf(conn, "\x02lp\n")
+	ack, _ := r.ReadByte()
+	assert.Equal(t, byte(0), ack)
+
+	// bsd lpr sends the data file before the control file
+	assert.Equal(t, byte(0), sendLPDFile(t, conn, r, lpdDataFile, "dfA001host", "hello world"))
+	control := "Hhost\nPalice\nJreport\nldfA001host\nldfA001host\nNreport.txt\n"
+	assert.Equal(t, byte(0), sendLPDFile(t, conn, r, lpdControlFile, "cfA001host", control))
+
+	job, ok := printer.Job(1)
+	assert.True(t, ok)
+	assert.Equal(t, "report", job.Name)
+	assert.Equal(t, "alice", job.OriginatingUser)
+	assert.Equal(t, ipp.MimeTypeOctetStream, job.DocumentFormat)
+	assert.Equal(t, 2, job.Attributes[ipp.AttributeCopies][0].Value)
+	assert.Equal(t, "hello world", received.String())
+
+	files, _ := ioutil.ReadDir(bridge.SpoolDirectory)
+	assert.Len(t, files, 0)
+}
+
+func TestLPDBridge_MultipleDocuments(t *testing.T) {
+	_, address, printer, received, closeBridge := newTestLPDBridge(t)
+	defer closeBridge()
+
+	printer.DocumentFormats = append(printer.DocumentFormats, "text/plain")
+
+	conn := dialLPD(t, address)
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	fmt.Fprintf(conn, "\x02lp\n")
+	r.ReadByte()
+
+	control := "Hhost\nPbob\nJnotes\nfdfA002host\nfdfB002host\n"
+	assert.Equal(t, byte(0), sendLPDFile(t, conn, r, lpdControlFile, "cfA002host", control))
+	assert.Equal(t, byte(0), sendLPDFile(t, conn, r, lpdDataFile, "dfA002host", "first "))
+	assert.Equal(t, byte(0), sendLPDFile(t, conn, r, lpdDataFile, "dfB002host", "second"))
+
+	job, ok := printer.Job(1)
+	assert.True(t, ok)
+	assert.Equal(t, 2, job.NumberOfDocuments)
+	assert.Equal(t, "text/plain", job.DocumentFormat)
+	assert.Equal(t, "first second", received.String())
+}
+
+func TestLPDBridge_UnknownQueue(t *testing.T) {
+	_, address, _, _, closeBridge := newTestLPDBridge(t)
+	defer closeBridge()
+
+	conn := dialLPD(t, address)
+	defer conn.Close()
+
+	fmt.Fprintf(conn, "\x02unknown\n")
+	ack, _ := bufio.NewReader(conn).ReadByte()
+	assert.Equal(t, byte(1), ack)
+}
+
+func TestLPDBridge_QueueStateAndRemove(t *testing.T) {
+	bridge, address, printer, _, closeBridge := newTestLPDBridge(t)
+	defer closeBridge()
+
+	printer.Sink = DocumentSinkFunc(func(job *Job, doc Document, data io.Reader) error {
+		return nil
+	})
+
+	resp := serveTestRequest(t, bridge.Server, "/printers/test", func() *ipp.Request {
+		req := newPrinterRequest(ipp.OperationCreateJob, "ipp://localhost/printers/test")
+		req.OperationAttributes[ipp.AttributeRequestingUserName] = "carol"
+		req.OperationAttributes[ipp.AttributeJobName] = "slides"
+		return req
+	}())
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+
+	conn := dialLPD(t, address)
+	fmt.Fprintf(conn, "\x03lp\n")
+	state, err := ioutil.ReadAll(conn)
+	conn.Close()
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(string(state), "carol"))
+	assert.True(t, strings.Contains(string(state), "slides"))
+
+	conn = dialLPD(t, address)
+	fmt.Fprintf(conn, "\x05lp carol 1\n")
+	ioutil.ReadAll(conn)
+	conn.Close()
+
+	job, _ := printer.Job(1)
+	assert.Equal(t, ipp.JobStateCanceled, job.State)
+}
+
+func TestReadLPDCommand(t *testing.T) {
+	command, args, err := readLPDCommand(bufio.NewReader(strings.NewReader("\x05lp root 12 13\n")))
+	assert.Nil(t, err)
+	assert.Equal(t, byte(lpdRemoveJobs), command)
+	assert.Equal(t, []string{"lp", "root", "12", "13"}, args)
+
+	_, _, err = readLPDCommand(bufio.NewReader(strings.NewReader("\n")))
+	assert.NotNil(t, err)
+}
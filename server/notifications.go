@@ -0,0 +1,369 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// notifyGetInterval is the interval in seconds in which clients should poll with Get-Notifications
+const notifyGetInterval = 10
+
+func (p *VirtualPrinter) createPrinterSubscriptions(req *Request) (*ipp.Response, error) {
+	return p.createSubscription(req, 0)
+}
+
+// createJobSubscriptions subscribes to the events of the job named by notify-job-id, see rfc 3995
+func (p *VirtualPrinter) createJobSubscriptions(req *Request) (*ipp.Response, error) {
+	id, ok := req.OperationAttributes[ipp.AttributeNotifyJobID].(int)
+	if !ok {
+		return Error(req, ipp.StatusErrorBadRequest, "missing notify-job-id"), nil
+	}
+
+	job, ok := p.Job(id)
+	if !ok {
+		return Error(req, ipp.StatusErrorNotFound, fmt.Sprintf("job %d does not exist", id)), nil
+	}
+
+	if job.IsTerminated() {
+		return Error(req, ipp.StatusErrorNotPossible, fmt.Sprintf("job %d is already terminated", job.ID)), nil
+	}
+
+	return p.createSubscription(req, job.ID)
+}
+
+// createSubscription creates the subscription of the subscription template group of the request
+func (p *VirtualPrinter) createSubscription(req *Request, jobID int) (*ipp.Response, error) {
+	if len(req.SubscriptionAttributes) == 0 {
+		return Error(req, ipp.StatusErrorBadRequest, "missing subscription template attributes"), nil
+	}
+
+	attributes, err := p.subscribe(req, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	b := NewResponseBuilder(req).SubscriptionAttributes(attributes)
+	if _, failed := attributes[ipp.AttributeNotifyStatusCode]; failed {
+		b.Status(ipp.StatusErrorIgnoredAllSubscriptions)
+	}
+
+	return b.Build(), nil
+}
+
+// subscribe creates a subscription from the subscription template attributes of the request and returns the
+// subscription attributes group of the response. if the subscription can not be created, the group contains the
+// notify-status-code
+func (p *VirtualPrinter) subscribe(req *Request, jobID int) (ipp.Attributes, error) {
+	template := req.SubscriptionAttributes
+	attributes := make(ipp.Attributes)
+
+	failed := func(status int16) (ipp.Attributes, error) {
+		attributes.Set(ipp.AttributeNotifyStatusCode, ipp.TagEnum, int(status))
+		return attributes, nil
+	}
+
+	recipient, push := template[ipp.AttributeNotifyRecipientURI].(string)
+	method, pull := template[ipp.AttributeNotifyPullMethod].(string)
+	switch {
+	case push && pull:
+		return failed(ipp.StatusErrorBadRequest)
+	case push && (p.Pusher == nil || !pushScheme(recipient)):
+		return failed(ipp.StatusErrorUriScheme)
+	case pull && method != ipp.PullMethodIppGet:
+		return failed(ipp.StatusErrorAttributesOrValues)
+	}
+
+	events := stringValues(template[ipp.AttributeNotifyEvents])
+	if len(unsupportedEvents(events)) > 0 {
+		return failed(ipp.StatusErrorAttributesOrValues)
+	}
+
+	owner := req.UserName()
+	if owner == "" {
+		owner = "anonymous"
+	}
+
+	leaseDuration, ok := template[ipp.AttributeNotifyLeaseDuration].(int)
+	if !ok {
+		leaseDuration = -1
+	}
+
+	sub := &Subscription{
+		Owner:           owner,
+		Events:          events,
+		JobID:           jobID,
+		PullMethod:      ipp.PullMethodIppGet,
+		RecipientURI:    recipient,
+		LeaseDuration:   leaseDuration,
+		PrinterURI:      p.printerURI(req),
+		Charset:         ipp.Charset,
+		NaturalLanguage: ipp.CharsetLanguage,
+	}
+	sub.TimeInterval, _ = template[ipp.AttributeNotifyTimeInterval].(int)
+	sub.UserData, _ = template[ipp.AttributeNotifyUserData].(string)
+
+	if push {
+		sub.PullMethod = ""
+	}
+
+	if charset, ok := template[ipp.AttributeNotifyCharset].(string); ok {
+		sub.Charset = charset
+	}
+
+	if language, ok := req.OperationAttributes[ipp.AttributeNaturalLanguage].(string); ok {
+		sub.NaturalLanguage = language
+	}
+	if language, ok := template[ipp.AttributeNotifyNaturalLanguage].(string); ok {
+		sub.NaturalLanguage = language
+	}
+
+	if err := p.Subscriptions.Create(sub); err != nil {
+		if err == TooManySubscriptionsError {
+			return failed(ipp.StatusErrorTooManySubscriptions)
+		}
+		return nil, err
+	}
+
+	attributes.Set(ipp.AttributeNotifySubscriptionID, ipp.TagInteger, sub.ID)
+	if jobID == 0 {
+		attributes.Set(ipp.AttributeNotifyLeaseDuration, ipp.TagInteger, sub.LeaseDuration)
+	}
+
+	return attributes, nil
+}
+
+func (p *VirtualPrinter) getSubscriptionAttributes(req *Request) (*ipp.Response, error) {
+	id, _ := req.OperationAttributes[ipp.AttributeNotifySubscriptionID].(int)
+
+	sub, err := p.Subscriptions.Get(id)
+	if err != nil {
+		return subscriptionError(req, id, err)
+	}
+
+	return NewResponseBuilder(req).SubscriptionAttributes(p.subscriptionAttributes(sub)).Build(), nil
+}
+
+func (p *VirtualPrinter) getSubscriptions(req *Request) (*ipp.Response, error) {
+	jobID, _ := req.OperationAttributes[ipp.AttributeNotifyJobID].(int)
+	mySubscriptions, _ := req.OperationAttributes[ipp.AttributeMySubscriptions].(bool)
+	user := req.UserName()
+	limit, _ := req.OperationAttributes[ipp.AttributeLimit].(int)
+
+	b := NewResponseBuilder(req)
+
+	count := 0
+	for _, sub := range p.Subscriptions.List() {
+		if sub.JobID != jobID {
+			continue
+		}
+
+		if mySubscriptions && sub.Owner != user {
+			continue
+		}
+
+		if limit > 0 && count >= limit {
+			break
+		}
+
+		b.SubscriptionAttributes(p.subscriptionAttributes(sub))
+		count++
+	}
+
+	return b.Build(), nil
+}
+
+func (p *VirtualPrinter) renewSubscription(req *Request) (*ipp.Response, error) {
+	id, _ := req.OperationAttributes[ipp.AttributeNotifySubscriptionID].(int)
+
+	leaseDuration, ok := req.OperationAttributes[ipp.AttributeNotifyLeaseDuration].(int)
+	if !ok {
+		leaseDuration = -1
+	}
+
+	sub, err := p.Subscriptions.Renew(id, leaseDuration)
+	if err != nil {
+		return subscriptionError(req, id, err)
+	}
+
+	return NewResponseBuilder(req).
+		OperationAttribute(ipp.AttributeNotifyLeaseDuration, ipp.TagInteger, sub.LeaseDuration).
+		Build(), nil
+}
+
+func (p *VirtualPrinter) cancelSubscription(req *Request) (*ipp.Response, error) {
+	id, _ := req.OperationAttributes[ipp.AttributeNotifySubscriptionID].(int)
+
+	if err := p.Subscriptions.Cancel(id); err != nil {
+		return subscriptionError(req, id, err)
+	}
+	if p.Pusher != nil {
+		p.Pusher.Cancel(id)
+	}
+
+	return OK(req), nil
+}
+
+func (p *VirtualPrinter) getNotifications(req *Request) (*ipp.Response, error) {
+	ids := intValues(req.OperationAttributes[ipp.AttributeNotifySubscriptionIDs])
+	if len(ids) == 0 {
+		return Error(req, ipp.StatusErrorBadRequest, "missing notify-subscription-ids"), nil
+	}
+	sequences := intValues(req.OperationAttributes[ipp.AttributeNotifySequenceNumbers])
+
+	b := NewResponseBuilder(req).
+		OperationAttribute(ipp.AttributeNotifyGetInterval, ipp.TagInteger, notifyGetInterval).
+		OperationAttribute(ipp.AttributePrinterUpTime, ipp.TagInteger, p.upTime(time.Now()))
+
+	for i, id := range ids {
+		first := 1
+		if i < len(sequences) {
+			first = sequences[i]
+		}
+
+		sub, err := p.Subscriptions.Get(id)
+		if err != nil {
+			return subscriptionError(req, id, err)
+		}
+
+		events, err := p.Subscriptions.Events(id, first)
+		if err != nil {
+			return subscriptionError(req, id, err)
+		}
+
+		for _, event := range events {
+			b.EventNotification(p.eventAttributes(sub, event))
+		}
+	}
+
+	return b.Build(), nil
+}
+
+// publishJobEvent publishes an event with the current state of the job
+func (p *VirtualPrinter) publishJobEvent(name string, job *Job) {
+	attributes := make(ipp.Attributes)
+	attributes.Set(ipp.AttributeJobID, ipp.TagInteger, job.ID)
+	attributes.Set(ipp.AttributeJobName, ipp.TagName, job.Name)
+	attributes.Set(ipp.AttributeJobState, ipp.TagEnum, int(job.State))
+	attributes.Set(ipp.AttributeJobStateReasons, ipp.TagKeyword, toValues(job.StateReasons)...)
+	attributes.Set(ipp.AttributeJobImpressionsCompleted, ipp.TagInteger, job.Impressions)
+
+	text := "job state changed"
+	switch name {
+	case ipp.EventJobCreated:
+		text = "job created"
+	case ipp.EventJobCompleted:
+		text = "job completed"
+	}
+
+	p.Subscriptions.Publish(Event{Name: name, Text: text, JobID: job.ID, Attributes: attributes})
+}
+
+// publishPrinterEvent publishes a printer-state-changed or printer-stopped event with the printer status
+func (p *VirtualPrinter) publishPrinterEvent(status PrinterStatus) {
+	attributes := make(ipp.Attributes)
+	attributes.Set(ipp.AttributePrinterState, ipp.TagEnum, int(status.State))
+	attributes.Set(ipp.AttributePrinterStateReasons, ipp.TagKeyword, toValues(status.Reasons)...)
+	attributes.Set(ipp.AttributePrinterIsAcceptingJobs, ipp.TagBoolean, status.AcceptingJobs)
+
+	event := Event{Name: ipp.EventPrinterStateChanged, Text: "printer state changed", Attributes: attributes}
+	if status.State == ipp.PrinterStateStopped {
+		event.Name = ipp.EventPrinterStopped
+		event.Text = "printer stopped"
+	}
+
+	p.Subscriptions.Publish(event)
+}
+
+func (p *VirtualPrinter) subscriptionAttributes(sub *Subscription) ipp.Attributes {
+	attributes := make(ipp.Attributes)
+	attributes.Set(ipp.AttributeNotifySubscriptionID, ipp.TagInteger, sub.ID)
+	attributes.Set(ipp.AttributeNotifyEvents, ipp.TagKeyword, toValues(sub.Events)...)
+	if sub.RecipientURI != "" {
+		attributes.Set(ipp.AttributeNotifyRecipientURI, ipp.TagUri, sub.RecipientURI)
+	} else {
+		attributes.Set(ipp.AttributeNotifyPullMethod, ipp.TagKeyword, sub.PullMethod)
+	}
+	attributes.Set(ipp.AttributeNotifySubscriberUserName, ipp.TagName, sub.Owner)
+	attributes.Set(ipp.AttributeNotifyPrinterURI, ipp.TagUri, sub.PrinterURI)
+	attributes.Set(ipp.AttributeNotifyCharset, ipp.TagCharset, sub.Charset)
+	attributes.Set(ipp.AttributeNotifyNaturalLanguage, ipp.TagLanguage, sub.NaturalLanguage)
+	attributes.Set(ipp.AttributeNotifyTimeInterval, ipp.TagInteger, sub.TimeInterval)
+
+	if sub.JobID != 0 {
+		attributes.Set(ipp.AttributeNotifyJobID, ipp.TagInteger, sub.JobID)
+	} else {
+		remaining := 0
+		if !sub.ExpiresAt.IsZero() {
+			remaining = int(sub.ExpiresAt.Sub(time.Now()).Seconds())
+		}
+		attributes.Set(ipp.AttributeNotifyLeaseDuration, ipp.TagInteger, remaining)
+	}
+
+	if sub.UserData != "" {
+		attributes.Set(ipp.AttributeNotifyUserData, ipp.TagString, sub.UserData)
+	}
+
+	return attributes
+}
+
+func (p *VirtualPrinter) eventAttributes(sub *Subscription, event Event) ipp.Attributes {
+	attributes := make(ipp.Attributes, len(event.Attributes)+8)
+	for name, attr := range event.Attributes {
+		attributes[name] = attr
+	}
+
+	attributes.Set(ipp.AttributeNotifySubscriptionID, ipp.TagInteger, event.SubscriptionID)
+	attributes.Set(ipp.AttributeNotifySequenceNumber, ipp.TagInteger, event.SequenceNumber)
+	attributes.Set(ipp.AttributeNotifySubscribedEvent, ipp.TagKeyword, event.Name)
+	attributes.Set(ipp.AttributeNotifyText, ipp.TagText, event.Text)
+	attributes.Set(ipp.AttributeNotifyCharset, ipp.TagCharset, sub.Charset)
+	attributes.Set(ipp.AttributeNotifyNaturalLanguage, ipp.TagLanguage, sub.NaturalLanguage)
+	attributes.Set(ipp.AttributeNotifyPrinterURI, ipp.TagUri, sub.PrinterURI)
+	attributes.Set(ipp.AttributePrinterUpTime, ipp.TagInteger, p.upTime(event.Time))
+
+	if sub.UserData != "" {
+		attributes.Set(ipp.AttributeNotifyUserData, ipp.TagString, sub.UserData)
+	}
+
+	return attributes
+}
+
+// pushEvent delivers the event to the recipient of a push subscription
+func (p *VirtualPrinter) pushEvent(sub *Subscription, event Event) {
+	if sub.RecipientURI == "" || p.Pusher == nil {
+		return
+	}
+
+	p.Pusher.push(pushedEvent{sub: sub, notification: p.eventAttributes(sub, event), subscriptions: p.Subscriptions})
+}
+
+// subscriptionError converts an error of the SubscriptionManager into a response
+func subscriptionError(req *Request, id int, err error) (*ipp.Response, error) {
+	if err == SubscriptionNotFoundError {
+		return Error(req, ipp.StatusErrorNotFound, fmt.Sprintf("subscription %d does not exist", id)), nil
+	}
+
+	return nil, err
+}
+
+// intValues returns the values of a decoded integer attribute
+func intValues(value interface{}) []int {
+	switch v := value.(type) {
+	case int:
+		return []int{v}
+	case []int:
+		return v
+	case []interface{}:
+		values := make([]int, 0, len(v))
+		for _, i := range v {
+			if n, ok := i.(int); ok {
+				values = append(values, n)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
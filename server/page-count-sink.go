@@ -0,0 +1,52 @@
+package server
+
+import (
+	"io"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// PageCountSink is a DocumentSink which counts the pages of the documents with ipp.CountPages while they are passed
+// to its Sink. the pages are added to the impressions of the job, so accounting records and
+// job-impressions-completed report them. documents of formats without page counter are not counted, the count of
+// sinks which count impressions themselves like RasterSink is kept
+type PageCountSink struct {
+	// Sink receives the documents, they are discarded if nil
+	Sink DocumentSink
+}
+
+// WriteDocument passes the document to the Sink and counts its pages
+func (s *PageCountSink) WriteDocument(job *Job, doc Document, data io.Reader) error {
+	pr, pw := io.Pipe()
+	counted := make(chan int, 1)
+	go func() {
+		pages, err := ipp.CountPages(pr, doc.Format)
+		if err != nil {
+			pages = 0
+		}
+		// the counter may stop early, the remaining data is consumed so the sink is not blocked
+		_, _ = io.Copy(io.Discard, pr)
+		counted <- pages
+	}()
+
+	sink := s.Sink
+	if sink == nil {
+		sink = DiscardSink
+	}
+
+	impressions := job.Impressions
+	err := sink.WriteDocument(job, doc, io.TeeReader(data, pw))
+
+	// the data the sink did not read is still counted
+	if _, copyErr := io.Copy(pw, data); err == nil {
+		err = copyErr
+	}
+	pw.Close()
+
+	pages := <-counted
+	if job.Impressions == impressions {
+		job.Impressions += pages
+	}
+
+	return err
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/pwg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageCountSink(t *testing.T) {
+	pdf := "%PDF-1.4\n1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n" +
+		"2 0 obj\n<< /Type /Pages /Count 2 >>\nendobj\ntrailer\n<< /Root 1 0 R >>\n"
+
+	var received []string
+	sink := &PageCountSink{Sink: DocumentSinkFunc(func(job *Job, doc Document, data io.Reader) error {
+		// the sink reads only a part of the document
+		b := make([]byte, 8)
+		_, err := io.ReadFull(data, b)
+		received = append(received, string(b))
+		return err
+	})}
+
+	job := &Job{}
+	assert.Nil(t, sink.WriteDocument(job, Document{Format: ipp.MimeTypePDF}, strings.NewReader(pdf)))
+	assert.Equal(t, 2, job.Impressions)
+	assert.Equal(t, []string{"%PDF-1.4"}, received)
+
+	assert.Nil(t, sink.WriteDocument(job, Document{Format: "text/plain"}, strings.NewReader("some text")))
+	assert.Equal(t, 2, job.Impressions)
+
+	sink.Sink = DocumentSinkFunc(func(job *Job, doc Document, data io.Reader) error {
+		return errors.New("offline")
+	})
+	assert.EqualError(t, sink.WriteDocument(job, Document{Format: ipp.MimeTypePDF}, strings.NewReader(pdf)), "offline")
+	assert.Equal(t, 4, job.Impressions)
+
+	// the pages counted by a raster sink are not counted twice
+	var raster bytes.Buffer
+	assert.Nil(t, pwg.Encode(&raster, []image.Image{image.NewGray(image.Rect(0, 0, 8, 8))}, pwg.Options{}))
+	sink.Sink = &RasterSink{}
+	assert.Nil(t, sink.WriteDocument(job, Document{Format: ipp.MimeTypePwgRaster}, &raster))
+	assert.Equal(t, 5, job.Impressions)
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// the icon sizes required by ipp everywhere and airprint clients
+const (
+	IconSizeSmall  = 48
+	IconSizeMedium = 128
+	IconSizeLarge  = 512
+)
+
+// printerIcons holds the png icons of a printer by size
+type printerIcons struct {
+	mu     sync.RWMutex
+	bySize map[int][]byte
+}
+
+// SetIcon adds a square png icon to the printer, an icon of the same size is replaced. the icons are served below the
+// path of the printer endpoint and advertised with printer-icons ordered by size, clients expect at least the sizes
+// IconSizeSmall, IconSizeMedium and IconSizeLarge
+func (p *VirtualPrinter) SetIcon(data []byte) error {
+	config, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid png icon: %w", err)
+	}
+
+	if config.Width != config.Height {
+		return fmt.Errorf("icon must be square, got %dx%d", config.Width, config.Height)
+	}
+
+	p.icons.mu.Lock()
+	defer p.icons.mu.Unlock()
+
+	if p.icons.bySize == nil {
+		p.icons.bySize = make(map[int][]byte)
+	}
+	p.icons.bySize[config.Width] = data
+
+	return nil
+}
+
+// RemoveIcon removes the icon with the given size
+func (p *VirtualPrinter) RemoveIcon(size int) {
+	p.icons.mu.Lock()
+	defer p.icons.mu.Unlock()
+
+	delete(p.icons.bySize, size)
+}
+
+// iconSizes returns the sizes of the icons in ascending order
+func (p *VirtualPrinter) iconSizes() []int {
+	p.icons.mu.RLock()
+	defer p.icons.mu.RUnlock()
+
+	sizes := make([]int, 0, len(p.icons.bySize))
+	for size := range p.icons.bySize {
+		sizes = append(sizes, size)
+	}
+	sort.Ints(sizes)
+
+	return sizes
+}
+
+// setIconAttributes adds the printer-icons uris to the printer attributes
+func (p *VirtualPrinter) setIconAttributes(req *Request, attributes ipp.Attributes) {
+	sizes := p.iconSizes()
+	if len(sizes) == 0 {
+		return
+	}
+
+	uris := make([]interface{}, len(sizes))
+	for i, size := range sizes {
+		uris[i] = requestResourceURI(req, "icons/"+strconv.Itoa(size)+".png")
+	}
+
+	attributes.Set(ipp.AttributePrinterIcons, ipp.TagUri, uris...)
+}
+
+// serveIcon serves the icons of the printer
+func (p *VirtualPrinter) serveIcon(w http.ResponseWriter, r *http.Request) {
+	name := path.Base(r.URL.Path)
+
+	size, err := strconv.Atoi(strings.TrimSuffix(name, ".png"))
+	if err != nil || !strings.HasSuffix(name, ".png") {
+		http.NotFound(w, r)
+		return
+	}
+
+	p.icons.mu.RLock()
+	data, ok := p.icons.bySize[size]
+	p.icons.mu.RUnlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(data))
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+// testIcon encodes an empty png image
+func testIcon(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	assert.Nil(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))))
+	return buf.Bytes()
+}
+
+func TestVirtualPrinter_Icons(t *testing.T) {
+	printer := NewVirtualPrinter("office", nil)
+	assert.Nil(t, printer.SetIcon(testIcon(t, IconSizeLarge, IconSizeLarge)))
+	assert.Nil(t, printer.SetIcon(testIcon(t, IconSizeSmall, IconSizeSmall)))
+	assert.Nil(t, printer.SetIcon(testIcon(t, IconSizeMedium, IconSizeMedium)))
+	assert.NotNil(t, printer.SetIcon(testIcon(t, 48, 64)))
+	assert.NotNil(t, printer.SetIcon([]byte("GIF89a")))
+
+	s := NewServer()
+	printer.Register(s, "/ipp/print")
+
+	req := newPrinterRequest(ipp.OperationGetPrinterAttributes, "ipp://localhost/ipp/print")
+	req.OperationAttributes[ipp.AttributeRequestedAttributes] = []string{ipp.AttributePrinterIcons}
+	resp := serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+
+	icons := resp.PrinterAttributes[0][ipp.AttributePrinterIcons]
+	assert.Len(t, icons, 3)
+	assert.Equal(t, "http://example.com/ipp/print/icons/48.png", icons[0].Value)
+	assert.Equal(t, "http://example.com/ipp/print/icons/512.png", icons[2].Value)
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ipp/print/icons/128.png", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+
+	config, err := png.DecodeConfig(rec.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, IconSizeMedium, config.Width)
+
+	printer.RemoveIcon(IconSizeMedium)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ipp/print/icons/128.png", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,257 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// common printer-state-reasons keywords
+const (
+	PrinterStateReasonNone            = "none"
+	PrinterStateReasonPaused          = "paused"
+	PrinterStateReasonMediaEmpty      = "media-empty"
+	PrinterStateReasonMediaJam        = "media-jam"
+	PrinterStateReasonMediaNeeded     = "media-needed"
+	PrinterStateReasonTonerLow        = "toner-low"
+	PrinterStateReasonTonerEmpty      = "toner-empty"
+	PrinterStateReasonDoorOpen        = "door-open"
+	PrinterStateReasonOffline         = "offline-report"
+	PrinterStateReasonMovingToPaused  = "moving-to-paused"
+	PrinterStateReasonShutdown        = "shutdown"
+	PrinterStateReasonIdentifyPrinter = "identify-printer-requested"
+)
+
+// PrinterStatus is a snapshot of a PrinterState
+type PrinterStatus struct {
+	State         int8
+	Reasons       []string
+	Message       string
+	AcceptingJobs bool
+	ChangedAt     time.Time
+}
+
+// PrinterState manages the printer-state, printer-state-reasons and printer-state-message of a printer. all methods
+// are safe for concurrent use
+type PrinterState struct {
+	mu            sync.Mutex
+	state         int8
+	reasons       []string
+	message       string
+	acceptingJobs bool
+	changedAt     time.Time
+	active        int
+	listeners     []func(status PrinterStatus)
+}
+
+// NewPrinterState creates a idle printer state which accepts jobs
+func NewPrinterState() *PrinterState {
+	return &PrinterState{
+		state:         ipp.PrinterStateIdle,
+		acceptingJobs: true,
+		changedAt:     time.Now(),
+	}
+}
+
+// OnChange registers a function which is called with the new status after each change of the printer state
+func (s *PrinterState) OnChange(fn func(status PrinterStatus)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.listeners = append(s.listeners, fn)
+}
+
+// Status returns a snapshot of the current printer state
+func (s *PrinterState) Status() PrinterStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.status()
+}
+
+// State returns the current printer-state
+func (s *PrinterState) State() int8 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state
+}
+
+// HasReason checks if the reason is part of the printer-state-reasons
+func (s *PrinterState) HasReason(reason string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return indexOf(s.reasons, reason) >= 0
+}
+
+// IsAcceptingJobs returns the value of printer-is-accepting-jobs
+func (s *PrinterState) IsAcceptingJobs() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.acceptingJobs
+}
+
+// StartProcessing marks the start of the processing of a job. the printer changes to processing unless it is stopped
+func (s *PrinterState) StartProcessing() {
+	s.update(func() bool {
+		s.active++
+		return s.transition()
+	})
+}
+
+// FinishProcessing marks the end of the processing of a job. the printer changes to idle if no other job is processed
+func (s *PrinterState) FinishProcessing() {
+	s.update(func() bool {
+		if s.active > 0 {
+			s.active--
+		}
+		return s.transition()
+	})
+}
+
+// Pause stops the printer and adds the paused reason
+func (s *PrinterState) Pause() {
+	s.update(func() bool {
+		changed := s.addReason(PrinterStateReasonPaused)
+		return s.transition() || changed
+	})
+}
+
+// Resume removes the paused reason and restarts the printer
+func (s *PrinterState) Resume() {
+	s.update(func() bool {
+		changed := s.removeReason(PrinterStateReasonPaused)
+		return s.transition() || changed
+	})
+}
+
+// AddReason adds a reason to the printer-state-reasons
+func (s *PrinterState) AddReason(reason string) {
+	s.update(func() bool {
+		return s.addReason(reason)
+	})
+}
+
+// RemoveReason removes a reason from the printer-state-reasons
+func (s *PrinterState) RemoveReason(reason string) {
+	s.update(func() bool {
+		return s.removeReason(reason)
+	})
+}
+
+// SetMessage sets the printer-state-message
+func (s *PrinterState) SetMessage(message string) {
+	s.update(func() bool {
+		changed := s.message != message
+		s.message = message
+		return changed
+	})
+}
+
+// SetAcceptingJobs sets the printer-is-accepting-jobs attribute
+func (s *PrinterState) SetAcceptingJobs(accepting bool) {
+	s.update(func() bool {
+		changed := s.acceptingJobs != accepting
+		s.acceptingJobs = accepting
+		return changed
+	})
+}
+
+// Attributes returns the printer-state, printer-state-reasons, printer-state-message and printer-is-accepting-jobs
+// attributes of the current state
+func (s *PrinterState) Attributes() ipp.Attributes {
+	status := s.Status()
+
+	reasons := make([]interface{}, len(status.Reasons))
+	for i, reason := range status.Reasons {
+		reasons[i] = reason
+	}
+
+	attributes := make(ipp.Attributes)
+	attributes.Set(ipp.AttributePrinterState, ipp.TagEnum, int(status.State))
+	attributes.Set(ipp.AttributePrinterStateReasons, ipp.TagKeyword, reasons...)
+	attributes.Set(ipp.AttributePrinterStateMessage, ipp.TagText, status.Message)
+	attributes.Set(ipp.AttributePrinterIsAcceptingJobs, ipp.TagBoolean, status.AcceptingJobs)
+
+	return attributes
+}
+
+// update calls fn with the lock held and notifies the listeners if fn reports a change
+func (s *PrinterState) update(fn func() bool) {
+	s.mu.Lock()
+	if !fn() {
+		s.mu.Unlock()
+		return
+	}
+
+	s.changedAt = time.Now()
+	status := s.status()
+	listeners := append([]func(status PrinterStatus){}, s.listeners...)
+	s.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(status)
+	}
+}
+
+// transition derives the printer-state from the paused reason and the number of processed jobs
+func (s *PrinterState) transition() bool {
+	state := ipp.PrinterStateIdle
+	switch {
+	case indexOf(s.reasons, PrinterStateReasonPaused) >= 0:
+		state = ipp.PrinterStateStopped
+	case s.active > 0:
+		state = ipp.PrinterStateProcessing
+	}
+
+	changed := s.state != state
+	s.state = state
+	return changed
+}
+
+func (s *PrinterState) addReason(reason string) bool {
+	if reason == PrinterStateReasonNone || indexOf(s.reasons, reason) >= 0 {
+		return false
+	}
+
+	s.reasons = append(s.reasons, reason)
+	return true
+}
+
+func (s *PrinterState) removeReason(reason string) bool {
+	i := indexOf(s.reasons, reason)
+	if i < 0 {
+		return false
+	}
+
+	s.reasons = append(s.reasons[:i:i], s.reasons[i+1:]...)
+	return true
+}
+
+func (s *PrinterState) status() PrinterStatus {
+	reasons := []string{PrinterStateReasonNone}
+	if len(s.reasons) > 0 {
+		reasons = append([]string{}, s.reasons...)
+	}
+
+	return PrinterStatus{
+		State:         s.state,
+		Reasons:       reasons,
+		Message:       s.message,
+		AcceptingJobs: s.acceptingJobs,
+		ChangedAt:     s.changedAt,
+	}
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrinterState(t *testing.T) {
+	state := NewPrinterState()
+
+	var changes []PrinterStatus
+	state.OnChange(func(status PrinterStatus) {
+		changes = append(changes, status)
+	})
+
+	assert.Equal(t, ipp.PrinterStateIdle, state.State())
+	assert.Equal(t, []string{PrinterStateReasonNone}, state.Status().Reasons)
+
+	state.StartProcessing()
+	state.StartProcessing()
+	assert.Equal(t, ipp.PrinterStateProcessing, state.State())
+	state.FinishProcessing()
+	assert.Equal(t, ipp.PrinterStateProcessing, state.State())
+
+	state.Pause()
+	assert.Equal(t, ipp.PrinterStateStopped, state.State())
+	assert.True(t, state.HasReason(PrinterStateReasonPaused))
+
+	state.FinishProcessing()
+	assert.Equal(t, ipp.PrinterStateStopped, state.State())
+
+	state.Resume()
+	assert.Equal(t, ipp.PrinterStateIdle, state.State())
+	assert.False(t, state.HasReason(PrinterStateReasonPaused))
+
+	state.AddReason(PrinterStateReasonMediaEmpty)
+	state.AddReason(PrinterStateReasonMediaEmpty)
+	state.AddReason(PrinterStateReasonTonerLow)
+	state.RemoveReason(PrinterStateReasonMediaEmpty)
+	assert.Equal(t, []string{PrinterStateReasonTonerLow}, state.Status().Reasons)
+
+	// reasons and state changes are reported once per change
+	assert.Len(t, changes, 6)
+	assert.Equal(t, ipp.PrinterStateIdle, changes[len(changes)-1].State)
+}
+
+func TestPrinterState_Attributes(t *testing.T) {
+	state := NewPrinterState()
+	state.SetMessage("out of paper")
+	state.AddReason(PrinterStateReasonMediaEmpty)
+	state.SetAcceptingJobs(false)
+
+	attributes := state.Attributes()
+	assert.Equal(t, int(ipp.PrinterStateIdle), attributes[ipp.AttributePrinterState][0].Value)
+	assert.Equal(t, PrinterStateReasonMediaEmpty, attributes[ipp.AttributePrinterStateReasons][0].Value)
+	assert.Equal(t, "out of paper", attributes[ipp.AttributePrinterStateMessage][0].Value)
+	assert.Equal(t, false, attributes[ipp.AttributePrinterIsAcceptingJobs][0].Value)
+}
@@ -0,0 +1,289 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// ContentTypeStrings is the content type of the strings files served for printer-strings-uri
+const ContentTypeStrings = "text/strings"
+
+var StringsSyntaxError = errors.New("invalid strings file")
+
+// Strings contains the localized texts of attributes in the apple .strings format used by printer-strings-uri.
+// keys are attribute names, e.g. "media-source", or attribute values in the form "<attribute>.<value>", e.g.
+// "media-source.tray-3" or "print-quality.5"
+type Strings map[string]string
+
+// ParseStrings reads a strings file, comments are ignored
+func ParseStrings(r io.Reader) (Strings, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := stringsParser{data: data}
+	s := make(Strings)
+
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return s, nil
+		}
+
+		key, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect('='); err != nil {
+			return nil, err
+		}
+		value, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(';'); err != nil {
+			return nil, err
+		}
+
+		s[key] = value
+	}
+}
+
+// Encode writes the strings in the .strings format ordered by key
+func (s Strings) Encode(w io.Writer) error {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	bw := bufio.NewWriter(w)
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(bw, "%s = %s;\n", quoteString(key), quoteString(s[key])); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// quoteString quotes and escapes a string of a strings file
+func quoteString(s string) string {
+	var buf strings.Builder
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+
+	return buf.String()
+}
+
+// stringsParser is a scanner for strings files
+type stringsParser struct {
+	data []byte
+	pos  int
+}
+
+func (p *stringsParser) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("%w: %s at offset %d", StringsSyntaxError, fmt.Sprintf(format, a...), p.pos)
+}
+
+// skipSpace skips white space and comments
+func (p *stringsParser) skipSpace() {
+	for p.pos < len(p.data) {
+		rest := p.data[p.pos:]
+		switch {
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\r' || rest[0] == '\n':
+			p.pos++
+		case bytes.HasPrefix(rest, []byte("//")):
+			if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+				p.pos += i + 1
+			} else {
+				p.pos = len(p.data)
+			}
+		case bytes.HasPrefix(rest, []byte("/*")):
+			if i := bytes.Index(rest[2:], []byte("*/")); i >= 0 {
+				p.pos += i + 4
+			} else {
+				p.pos = len(p.data)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *stringsParser) expect(c byte) error {
+	p.skipSpace()
+	if p.pos >= len(p.data) || p.data[p.pos] != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+
+	return nil
+}
+
+func (p *stringsParser) quoted() (string, error) {
+	if err := p.expect('"'); err != nil {
+		return "", err
+	}
+
+	var buf strings.Builder
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		p.pos++
+
+		switch c {
+		case '"':
+			return buf.String(), nil
+		case '\\':
+			if p.pos >= len(p.data) {
+				return "", p.errorf("unterminated escape sequence")
+			}
+			escaped := p.data[p.pos]
+			p.pos++
+
+			switch escaped {
+			case 'n':
+				buf.WriteByte('\n')
+			case 'r':
+				buf.WriteByte('\r')
+			case 't':
+				buf.WriteByte('\t')
+			default:
+				buf.WriteByte(escaped)
+			}
+		default:
+			buf.WriteByte(c)
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+// printerStrings holds the strings of a printer by language
+type printerStrings struct {
+	mu         sync.RWMutex
+	byLanguage map[string]Strings
+}
+
+// SetStrings sets the localized strings of the printer for the natural language, e.g. en or de-ch. the strings are
+// served below the path of the printer endpoint and advertised with printer-strings-uri. nil removes the language
+func (p *VirtualPrinter) SetStrings(language string, s Strings) {
+	p.strings.mu.Lock()
+	defer p.strings.mu.Unlock()
+
+	language = strings.ToLower(language)
+	if s == nil {
+		delete(p.strings.byLanguage, language)
+		return
+	}
+
+	if p.strings.byLanguage == nil {
+		p.strings.byLanguage = make(map[string]Strings)
+	}
+	p.strings.byLanguage[language] = s
+}
+
+// stringsLanguages returns the languages with strings ordered by name
+func (p *VirtualPrinter) stringsLanguages() []string {
+	p.strings.mu.RLock()
+	defer p.strings.mu.RUnlock()
+
+	languages := make([]string, 0, len(p.strings.byLanguage))
+	for language := range p.strings.byLanguage {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	return languages
+}
+
+// lookupStrings returns the strings of the language, for a language with a region, e.g. de-ch, the strings of the
+// language without region are used if there are no strings for the region
+func (p *VirtualPrinter) lookupStrings(language string) (string, Strings, bool) {
+	p.strings.mu.RLock()
+	defer p.strings.mu.RUnlock()
+
+	language = strings.ToLower(language)
+	if s, ok := p.strings.byLanguage[language]; ok {
+		return language, s, true
+	}
+
+	if i := strings.IndexByte(language, '-'); i > 0 {
+		if s, ok := p.strings.byLanguage[language[:i]]; ok {
+			return language[:i], s, true
+		}
+	}
+
+	return "", nil, false
+}
+
+// setStringsAttributes adds printer-strings-languages-supported and the printer-strings-uri of the natural language
+// of the request to the printer attributes
+func (p *VirtualPrinter) setStringsAttributes(req *Request, attributes ipp.Attributes) {
+	languages := p.stringsLanguages()
+	if len(languages) == 0 {
+		return
+	}
+
+	attributes.Set(ipp.AttributePrinterStringsLanguagesSupported, ipp.TagLanguage, toValues(languages)...)
+
+	requested, _ := req.OperationAttributes[ipp.AttributeNaturalLanguage].(string)
+	if language, _, ok := p.lookupStrings(requested); ok {
+		attributes.Set(ipp.AttributePrinterStringsURI, ipp.TagUri, requestResourceURI(req, "strings/"+language+".strings"))
+	} else {
+		attributes.Set(ipp.AttributePrinterStringsURI, ipp.TagNoValue, "")
+	}
+}
+
+// serveStrings serves the strings files of the printer
+func (p *VirtualPrinter) serveStrings(w http.ResponseWriter, r *http.Request) {
+	name := path.Base(r.URL.Path)
+	if !strings.HasSuffix(name, ".strings") {
+		http.NotFound(w, r)
+		return
+	}
+
+	language, s, ok := p.lookupStrings(strings.TrimSuffix(name, ".strings"))
+	if !ok || language+".strings" != strings.ToLower(name) {
+		http.NotFound(w, r)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := s.Encode(&buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentTypeStrings+"; charset=utf-8")
+	w.Header().Set("Content-Language", language)
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(buf.Bytes()))
+}
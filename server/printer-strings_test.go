@@ -0,0 +1,92 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStrings(t *testing.T) {
+	data := `/* media sources */
+"media-source" = "Paper Source";
+// the large capacity tray
+"media-source.tray-3"="Tray 3 \"LCT\"";
+"print-quality.5" = "Best\nQuality";
+`
+
+	s, err := ParseStrings(strings.NewReader(data))
+	assert.Nil(t, err)
+	assert.Equal(t, Strings{
+		"media-source":        "Paper Source",
+		"media-source.tray-3": `Tray 3 "LCT"`,
+		"print-quality.5":     "Best\nQuality",
+	}, s)
+
+	var buf bytes.Buffer
+	assert.Nil(t, s.Encode(&buf))
+	assert.Equal(t, `"media-source" = "Paper Source";`+"\n", strings.SplitAfter(buf.String(), "\n")[0])
+
+	parsed, err := ParseStrings(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, s, parsed)
+
+	for _, invalid := range []string{`"key" "value";`, `"key" = "value"`, `"key = "value";`, `key = "value";`} {
+		_, err := ParseStrings(strings.NewReader(invalid))
+		assert.True(t, errors.Is(err, StringsSyntaxError), invalid)
+	}
+}
+
+func TestVirtualPrinter_Strings(t *testing.T) {
+	printer := NewVirtualPrinter("office", nil)
+	printer.SetStrings("en", Strings{"media-source.tray-3": "Tray 3"})
+	printer.SetStrings("de", Strings{"media-source.tray-3": "Fach 3"})
+
+	s := NewServer()
+	printer.Register(s, "/ipp/print")
+
+	tests := []struct {
+		language string
+		uri      string
+	}{
+		{language: "en", uri: "http://example.com/ipp/print/strings/en.strings"},
+		{language: "de-CH", uri: "http://example.com/ipp/print/strings/de.strings"},
+		{language: "fr"},
+	}
+
+	for _, test := range tests {
+		req := newPrinterRequest(ipp.OperationGetPrinterAttributes, "ipp://localhost/ipp/print")
+		req.OperationAttributes[ipp.AttributeNaturalLanguage] = test.language
+		req.OperationAttributes[ipp.AttributeRequestedAttributes] = []string{ipp.AttributePrinterStringsURI,
+			ipp.AttributePrinterStringsLanguagesSupported}
+		resp := serveTestRequest(t, s, "/ipp/print", req)
+		assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+
+		attributes := resp.PrinterAttributes[0]
+		assert.Len(t, attributes[ipp.AttributePrinterStringsLanguagesSupported], 2)
+		if test.uri == "" {
+			assert.Equal(t, ipp.TagNoValue, attributes[ipp.AttributePrinterStringsURI][0].Tag)
+		} else {
+			assert.Equal(t, test.uri, attributes[ipp.AttributePrinterStringsURI][0].Value)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ipp/print/strings/de.strings", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/strings; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `"media-source.tray-3" = "Fach 3";`+"\n", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ipp/print/strings/fr.strings", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/ipp/print/strings/de.strings", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
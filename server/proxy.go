@@ -0,0 +1,426 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// ProxyRecord describes an operation forwarded by a ProxyHandler
+type ProxyRecord struct {
+	Time       time.Time
+	RemoteAddr string
+	// User is the requesting-user-name of the request
+	User      string
+	Operation int16
+	// PrinterURI and JobURI are the uris sent by the client
+	PrinterURI string
+	JobURI     string
+	JobID      int
+	// Status is the status code of the upstream response, zero if the upstream could not be reached
+	Status   int16
+	Duration time.Duration
+	Err      error
+}
+
+// String formats the record as a log line, e.g.
+// 10.0.0.7:51234 alice Print-Job ipp://proxy/printers/office -> successful-ok (120ms)
+func (r ProxyRecord) String() string {
+	user := r.User
+	if user == "" {
+		user = "-"
+	}
+
+	uri := r.PrinterURI
+	if r.JobURI != "" {
+		uri = r.JobURI
+	} else if r.JobID != 0 {
+		uri += fmt.Sprintf(" job %d", r.JobID)
+	}
+
+	result := ipp.Status(r.Status).String()
+	if r.Err != nil {
+		result = r.Err.Error()
+	}
+
+	return fmt.Sprintf("%s %s %s %s -> %s (%s)", r.RemoteAddr, user, ipp.Operation(r.Operation), uri, result,
+		r.Duration.Round(time.Millisecond))
+}
+
+// NewProxyLog returns an audit function for ProxyHandler which writes each record as a line to w
+func NewProxyLog(w io.Writer) func(record ProxyRecord) {
+	var mu sync.Mutex
+
+	return func(record ProxyRecord) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		fmt.Fprintf(w, "%s %s\n", record.Time.Format(time.RFC3339), record)
+	}
+}
+
+// ProxyHandler is a http.Handler which forwards all ipp requests to an upstream printer or server, unlike
+// RelayPrinter the requests are passed on as they are, including unknown operations and vendor attributes. the uris
+// of the proxy are rewritten to the ones of the upstream in requests, e.g. printer-uri, job-uri or
+// notify-recipient-uri, and back in responses. other http requests, e.g. for printer icons, are passed to the
+// upstream as well
+type ProxyHandler struct {
+	// Client sends the requests to the upstream, defaults to http.DefaultClient
+	Client *http.Client
+	// Username and Password are sent with basic authentication to the upstream if set, otherwise the authorization
+	// of the client is passed on
+	Username string
+	Password string
+	// Audit is called after each forwarded ipp request, see NewProxyLog
+	Audit func(record ProxyRecord)
+
+	upstream proxyTarget
+	reverse  *httputil.ReverseProxy
+}
+
+// NewProxyHandler creates a proxy for the upstream ipp, ipps, http or https uri. the http paths and the uris of the
+// proxy are appended to the upstream uri, e.g. ipp://10.0.0.5:631 proxies all queues of a cups server and
+// ipp://printer/ipp maps ipp://proxy/print to ipp://printer/ipp/print
+func NewProxyHandler(upstream string) (*ProxyHandler, error) {
+	u, err := url.Parse(upstream)
+	if err != nil {
+		return nil, err
+	}
+
+	var secure bool
+	switch u.Scheme {
+	case "ipp", "http":
+	case "ipps", "https":
+		secure = true
+	default:
+		return nil, fmt.Errorf("unsupported uri scheme %s", u.Scheme)
+	}
+
+	p := &ProxyHandler{upstream: newProxyTarget(secure, u.Host, u.Path)}
+
+	target, err := url.Parse(p.upstream.uri("", true))
+	if err != nil {
+		return nil, err
+	}
+	p.reverse = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(target)
+		},
+		Transport: proxyTransport{p},
+	}
+
+	return p, nil
+}
+
+// proxyTransport sends the non-ipp requests of the reverse proxy with the client of the proxy
+type proxyTransport struct {
+	p *ProxyHandler
+}
+
+func (t proxyTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	client := t.p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	if client.Transport == nil {
+		return http.DefaultTransport.RoundTrip(r)
+	}
+
+	return client.Transport.RoundTrip(r)
+}
+
+// ServeHTTP forwards the request to the upstream. ipp requests are decoded to rewrite the uris, the document data
+// and the http status of the upstream are passed through
+func (p *ProxyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if r.Method != http.MethodPost || mediaType != ipp.ContentTypeIPP {
+		p.reverse.ServeHTTP(w, r)
+		return
+	}
+
+	start := time.Now()
+	body := bufio.NewReader(r.Body)
+
+	req, err := ipp.NewRequestDecoder(body).Decode(nil)
+	if err != nil {
+		http.Error(w, "unable to decode ipp request", http.StatusBadRequest)
+		return
+	}
+
+	client := newProxyTarget(r.TLS != nil, r.Host, "")
+
+	record := ProxyRecord{Time: start, RemoteAddr: r.RemoteAddr, Operation: req.Operation}
+	record.User, _ = req.OperationAttributes[ipp.AttributeRequestingUserName].(string)
+	record.PrinterURI, _ = req.OperationAttributes[ipp.AttributePrinterURI].(string)
+	record.JobURI, _ = req.OperationAttributes[ipp.AttributeJobURI].(string)
+	record.JobID, _ = req.OperationAttributes[ipp.AttributeJobID].(int)
+	if record.PrinterURI == "" && record.JobURI == "" {
+		record.PrinterURI = client.uri(r.URL.Path, false)
+	}
+
+	rewriteRequestURIs(req, client, p.upstream)
+	req.File = body
+
+	httpResp, err := p.send(r, req)
+	if err != nil {
+		record.Err = err
+		p.audit(record, start)
+
+		resp := Error(&Request{Request: req, HTTPRequest: r}, ipp.StatusErrorServiceUnavailable, err.Error())
+		writeResponse(w, resp)
+		return
+	}
+	defer httpResp.Body.Close()
+
+	// http errors like an authentication challenge are passed through
+	if httpResp.StatusCode != http.StatusOK {
+		record.Err = fmt.Errorf("upstream responded with http status %d", httpResp.StatusCode)
+		p.audit(record, start)
+
+		copyHeader(w.Header(), httpResp.Header)
+		w.WriteHeader(httpResp.StatusCode)
+		_, _ = io.Copy(w, httpResp.Body)
+		return
+	}
+
+	respBody := bufio.NewReader(httpResp.Body)
+	resp, err := ipp.NewResponseDecoder(respBody).Decode(nil)
+	if err != nil {
+		record.Err = err
+		p.audit(record, start)
+
+		resp := Error(&Request{Request: req, HTTPRequest: r}, ipp.StatusErrorServiceUnavailable, err.Error())
+		writeResponse(w, resp)
+		return
+	}
+
+	record.Status = resp.StatusCode
+	p.audit(record, start)
+
+	rewriteResponseURIs(resp, p.upstream, client)
+
+	payload, err := resp.Encode()
+	if err != nil {
+		http.Error(w, "unable to encode ipp response", http.StatusInternalServerError)
+		return
+	}
+
+	// the data after the response, e.g. of Get-Document, is streamed
+	w.Header().Set("Content-Type", ipp.ContentTypeIPP)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(payload)
+	_, _ = io.Copy(w, respBody)
+}
+
+// send posts the request with its document data to the upstream
+func (p *ProxyHandler) send(r *http.Request, req *ipp.Request) (*http.Response, error) {
+	payload, err := req.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(r.Context(), http.MethodPost, p.upstream.uri(r.URL.Path, true),
+		io.MultiReader(bytes.NewReader(payload), req.File))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", ipp.ContentTypeIPP)
+
+	if p.Username != "" {
+		httpReq.SetBasicAuth(p.Username, p.Password)
+	} else if authorization := r.Header.Get("Authorization"); authorization != "" {
+		httpReq.Header.Set("Authorization", authorization)
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return client.Do(httpReq)
+}
+
+// audit calls the audit function with the duration of the request
+func (p *ProxyHandler) audit(record ProxyRecord, start time.Time) {
+	if p.Audit == nil {
+		return
+	}
+
+	record.Duration = time.Since(start)
+	p.Audit(record)
+}
+
+// writeResponse writes an encoded ipp response
+func writeResponse(w http.ResponseWriter, resp *ipp.Response) {
+	payload, err := resp.Encode()
+	if err != nil {
+		http.Error(w, "unable to encode ipp response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ipp.ContentTypeIPP)
+	w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(payload)
+}
+
+// copyHeader copies the http header of the upstream response
+func copyHeader(dst, src http.Header) {
+	for name, values := range src {
+		for _, value := range values {
+			dst.Add(name, value)
+		}
+	}
+}
+
+// proxyTarget is one side of a proxy, the uris of a target share its host, port and path prefix
+type proxyTarget struct {
+	secure bool
+	// host is written into uris, address is the host with port to compare uris
+	host    string
+	address string
+	path    string
+}
+
+// newProxyTarget creates a target, the port defaults to 631
+func newProxyTarget(secure bool, host, path string) proxyTarget {
+	return proxyTarget{
+		secure:  secure,
+		host:    host,
+		address: uriAddress(host, "631"),
+		path:    strings.TrimSuffix(path, "/"),
+	}
+}
+
+// uriAddress returns the lower case host with port of an uri host
+func uriAddress(host, defaultPort string) string {
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(strings.Trim(host, "[]"), defaultPort)
+	}
+
+	return strings.ToLower(host)
+}
+
+// uri returns the ipp or http uri of the target for a path below the path prefix
+func (t proxyTarget) uri(path string, http bool) string {
+	scheme := "ipp"
+	if http {
+		scheme = "http"
+	}
+	if t.secure {
+		scheme += "s"
+	}
+
+	return scheme + "://" + t.host + t.path + path
+}
+
+// rewrite maps an uri of the target to the other target, uris of other hosts are returned unchanged
+func (t proxyTarget) rewrite(uri string, to proxyTarget) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+
+	var http bool
+	defaultPort := "631"
+	switch u.Scheme {
+	case "ipp", "ipps":
+	case "http":
+		http, defaultPort = true, "80"
+	case "https":
+		http, defaultPort = true, "443"
+	default:
+		return uri
+	}
+
+	if uriAddress(u.Host, defaultPort) != t.address {
+		return uri
+	}
+
+	path := u.EscapedPath()
+	if path != t.path && !strings.HasPrefix(path, t.path+"/") {
+		return uri
+	}
+
+	rewritten := to.uri(strings.TrimPrefix(path, t.path), http)
+	if u.RawQuery != "" {
+		rewritten += "?" + u.RawQuery
+	}
+
+	return rewritten
+}
+
+// rewriteRequestURIs rewrites the uri values of all request groups from one target to the other
+func rewriteRequestURIs(req *ipp.Request, from, to proxyTarget) {
+	for _, attributes := range []map[string]interface{}{
+		req.OperationAttributes,
+		req.JobAttributes,
+		req.PrinterAttributes,
+		req.SubscriptionAttributes,
+	} {
+		for name, value := range attributes {
+			tag, _ := req.AttributeTag(name)
+			attributes[name] = rewriteRequestValue(tag == ipp.TagUri, value, from, to)
+		}
+	}
+}
+
+// rewriteRequestValue rewrites a decoded request value if the attribute has the uri syntax, vendor attributes are
+// rewritten by the tag they were decoded with
+func rewriteRequestValue(isURI bool, value interface{}, from, to proxyTarget) interface{} {
+	switch v := value.(type) {
+	case string:
+		if isURI {
+			return from.rewrite(v, to)
+		}
+	case []string:
+		if isURI {
+			rewritten := make([]string, len(v))
+			for i, uri := range v {
+				rewritten[i] = from.rewrite(uri, to)
+			}
+			return rewritten
+		}
+	}
+
+	return value
+}
+
+// rewriteResponseURIs rewrites the uri values of all response groups from one target to the other
+func rewriteResponseURIs(resp *ipp.Response, from, to proxyTarget) {
+	groups := []ipp.Attributes{resp.OperationAttributes, resp.UnsupportedAttributes}
+	groups = append(groups, resp.PrinterAttributes...)
+	groups = append(groups, resp.JobAttributes...)
+	groups = append(groups, resp.SubscriptionAttributes...)
+	groups = append(groups, resp.EventNotificationAttributes...)
+
+	for _, attributes := range groups {
+		for _, values := range attributes {
+			for i := range values {
+				values[i] = rewriteAttribute(values[i], from, to)
+			}
+		}
+	}
+}
+
+// rewriteAttribute rewrites the value of an attribute with the uri tag
+func rewriteAttribute(attr ipp.Attribute, from, to proxyTarget) ipp.Attribute {
+	if uri, ok := attr.Value.(string); ok && attr.Tag == ipp.TagUri {
+		attr.Value = from.rewrite(uri, to)
+	}
+
+	return attr
+}
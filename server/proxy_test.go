@@ -0,0 +1,134 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxyHandler(t *testing.T) {
+	var received bytes.Buffer
+	printer := NewVirtualPrinter("upstream", func(job *Job, format string, document io.Reader) error {
+		_, err := io.Copy(&received, document)
+		return err
+	})
+	printer.SetStrings("en", Strings{"media-source.tray-3": "Tray 3"})
+	upstream := NewServer()
+	printer.Register(upstream, "/ipp/print")
+
+	// a vendor operation which echoes its operation attributes
+	var vendorRequest *ipp.Request
+	upstream.HandleFunc(0x4001, func(req *Request) (*ipp.Response, error) {
+		vendorRequest = req.Request
+		return NewResponseBuilder(req).
+			OperationAttribute(ipp.AttributeNotifyRecipientURI, ipp.TagUri, req.OperationAttributes[ipp.AttributeNotifyRecipientURI]).
+			Build(), nil
+	})
+
+	ts := httptest.NewServer(upstream)
+	defer ts.Close()
+	upstreamURI := "ipp://" + ts.Listener.Addr().String()
+
+	proxy, err := NewProxyHandler(upstreamURI)
+	assert.Nil(t, err)
+	var log bytes.Buffer
+	var records []ProxyRecord
+	audit := NewProxyLog(&log)
+	proxy.Audit = func(record ProxyRecord) {
+		records = append(records, record)
+		audit(record)
+	}
+
+	// httptest requests are sent to example.com
+	proxyURI := "ipp://example.com/ipp/print"
+
+	req := newPrinterRequest(ipp.OperationPrintJob, proxyURI)
+	req.OperationAttributes[ipp.AttributeRequestingUserName] = "alice"
+	resp := serveTestDocument(t, proxy, "/ipp/print", req, []byte("%PDF-1.7 proxy"))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, proxyURI+"/1", resp.JobAttributes[0][ipp.AttributeJobURI][0].Value)
+	assert.Equal(t, "%PDF-1.7 proxy", received.String())
+
+	req = newPrinterRequest(ipp.OperationGetJobAttributes, proxyURI)
+	req.OperationAttributes[ipp.AttributeJobURI] = proxyURI + "/1"
+	resp = serveTestRequest(t, proxy, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, proxyURI, resp.JobAttributes[0][ipp.AttributeJobPrinterURI][0].Value)
+
+	resp = serveTestRequest(t, proxy, "/ipp/print", newPrinterRequest(ipp.OperationGetPrinterAttributes, proxyURI))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, "upstream", resp.PrinterAttributes[0][ipp.AttributePrinterName][0].Value)
+	assert.Equal(t, proxyURI, resp.PrinterAttributes[0][ipp.AttributePrinterUriSupported][0].Value)
+
+	// unknown operations and vendor attributes are passed on, uris of other hosts are kept
+	req = newPrinterRequest(0x4001, proxyURI)
+	req.OperationAttributes[ipp.AttributeNotifyRecipientURI] = "ipp://example.com/notify"
+	req.OperationAttributes["com-example-callback"] = ipp.Attribute{
+		Tag: ipp.TagUri, Name: "com-example-callback", Value: "http://client.example.org/callback",
+	}
+	resp = serveTestRequest(t, proxy, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, upstreamURI+"/ipp/print", vendorRequest.OperationAttributes[ipp.AttributePrinterURI])
+	assert.Equal(t, upstreamURI+"/notify", vendorRequest.OperationAttributes[ipp.AttributeNotifyRecipientURI])
+	assert.Equal(t, "http://client.example.org/callback", vendorRequest.OperationAttributes["com-example-callback"])
+	tag, _ := vendorRequest.AttributeTag("com-example-callback")
+	assert.Equal(t, ipp.TagUri, tag)
+	assert.Equal(t, "ipp://example.com/notify", resp.OperationAttributes[ipp.AttributeNotifyRecipientURI][0].Value)
+
+	assert.Len(t, records, 4)
+	assert.Equal(t, "alice", records[0].User)
+	assert.Equal(t, ipp.OperationPrintJob, records[0].Operation)
+	assert.Equal(t, proxyURI, records[0].PrinterURI)
+	assert.Equal(t, ipp.StatusOk, records[0].Status)
+	assert.Equal(t, proxyURI+"/1", records[1].JobURI)
+	assert.Contains(t, log.String(), "alice Print-Job "+proxyURI+" -> successful-ok")
+
+	// other http requests are passed on
+	r := httptest.NewRequest(http.MethodGet, "/ipp/print/strings/en.strings", nil)
+	rec := httptest.NewRecorder()
+	proxy.ServeHTTP(rec, r)
+	direct, err := http.Get(ts.URL + "/ipp/print/strings/en.strings")
+	assert.Nil(t, err)
+	directBody, _ := io.ReadAll(direct.Body)
+	direct.Body.Close()
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, direct.StatusCode, rec.Code)
+	assert.Equal(t, string(directBody), rec.Body.String())
+
+	ts.Close()
+	resp = serveTestRequest(t, proxy, "/ipp/print", newPrinterRequest(ipp.OperationGetPrinterAttributes, proxyURI))
+	assert.Equal(t, ipp.StatusErrorServiceUnavailable, resp.StatusCode)
+	assert.NotNil(t, records[len(records)-1].Err)
+
+	_, err = NewProxyHandler("lpd://printer/queue")
+	assert.NotNil(t, err)
+}
+
+func TestProxyTarget_Rewrite(t *testing.T) {
+	proxy := newProxyTarget(false, "proxy:8631", "")
+	upstream := newProxyTarget(true, "Printer", "/ipp")
+
+	testCases := []struct {
+		URI      string
+		Expected string
+	}{
+		{"ipp://proxy:8631/print", "ipps://Printer/ipp/print"},
+		{"http://proxy:8631/print/icons", "https://Printer/ipp/print/icons"},
+		{"ipp://proxy:8631/print/1?x=y", "ipps://Printer/ipp/print/1?x=y"},
+		{"ipp://proxy/print", "ipp://proxy/print"},
+		{"mailto:alice@proxy", "mailto:alice@proxy"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.Expected, proxy.rewrite(tc.URI, upstream), tc.URI)
+	}
+
+	assert.Equal(t, "ipp://proxy:8631/print", upstream.rewrite("ipps://printer:631/ipp/print", proxy))
+	assert.Equal(t, "ipps://printer:631/other", upstream.rewrite("ipps://printer:631/other", proxy))
+	assert.Equal(t, "ipp://proxy:8631", upstream.rewrite("ipps://printer/ipp", proxy))
+}
@@ -0,0 +1,242 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// PushSchemes are the notify-recipient-uri schemes delivered by a NotificationPusher
+var PushSchemes = []string{"http", "https"}
+
+// defaultPushQueue is the number of events queued per subscription of a NotificationPusher
+const defaultPushQueue = 64
+
+// defaultPushClient sends the requests of a NotificationPusher, recipients which don't respond must not block the
+// delivery of the events of their subscription forever
+var defaultPushClient = &http.Client{Timeout: 30 * time.Second}
+
+// NotificationPusher delivers the events of subscriptions with a notify-recipient-uri with Send-Notifications
+// requests of rfc 3995 to the recipient. the events of a subscription are delivered in order in the background,
+// failed deliveries are dropped, clients can fetch missed events with Get-Notifications. the delivery stops when the
+// subscription is cancelled or expired
+type NotificationPusher struct {
+	// Client sends the requests to the recipients, defaults to a client with a timeout of 30 seconds
+	Client *http.Client
+	// MaxQueue is the number of events queued for a subscription whose recipient doesn't keep up, the oldest events
+	// are dropped. defaults to 64
+	MaxQueue int
+	// OnError is called if an event can not be delivered
+	OnError func(sub *Subscription, err error)
+
+	mu        sync.Mutex
+	pending   map[int][]pushedEvent
+	delivered sync.WaitGroup
+}
+
+type pushedEvent struct {
+	sub          *Subscription
+	notification ipp.Attributes
+	// subscriptions is the manager of the subscription, the event is dropped if the subscription is gone
+	subscriptions *SubscriptionManager
+}
+
+// NewNotificationPusher creates a pusher with the default client
+func NewNotificationPusher() *NotificationPusher {
+	return &NotificationPusher{}
+}
+
+// Push queues the event notification attributes for the recipient of the subscription. the event is dropped if the
+// lease of the subscription expired before it is delivered
+func (p *NotificationPusher) Push(sub *Subscription, notification ipp.Attributes) {
+	p.push(pushedEvent{sub: sub, notification: notification})
+}
+
+// Cancel drops the queued events of the subscription, an event which is already sent is not interrupted
+func (p *NotificationPusher) Cancel(id int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, running := p.pending[id]; running {
+		p.pending[id] = nil
+	}
+}
+
+func (p *NotificationPusher) push(event pushedEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.pending == nil {
+		p.pending = make(map[int][]pushedEvent)
+	}
+
+	id := event.sub.ID
+	queue, running := p.pending[id]
+	if limit := p.maxQueue(); len(queue) >= limit {
+		queue = queue[len(queue)-limit+1:]
+	}
+	p.pending[id] = append(queue, event)
+	if !running {
+		p.delivered.Add(1)
+		go p.deliver(id)
+	}
+}
+
+// Wait blocks until the queued events are delivered, e.g. before a test checks the received notifications
+func (p *NotificationPusher) Wait() {
+	p.delivered.Wait()
+}
+
+// deliver sends the queued events of the subscription until the queue is empty
+func (p *NotificationPusher) deliver(id int) {
+	defer p.delivered.Done()
+
+	for {
+		p.mu.Lock()
+		queue := p.pending[id]
+		if len(queue) == 0 {
+			delete(p.pending, id)
+			p.mu.Unlock()
+			return
+		}
+		event := queue[0]
+		p.pending[id] = queue[1:]
+		p.mu.Unlock()
+
+		if !event.active(time.Now()) {
+			p.Cancel(id)
+			continue
+		}
+
+		if err := p.send(event); err != nil && p.OnError != nil {
+			p.OnError(event.sub, err)
+		}
+	}
+}
+
+func (p *NotificationPusher) maxQueue() int {
+	if p.MaxQueue <= 0 {
+		return defaultPushQueue
+	}
+
+	return p.MaxQueue
+}
+
+// active checks if the subscription of the event is neither cancelled nor expired
+func (e pushedEvent) active(now time.Time) bool {
+	if e.subscriptions != nil {
+		_, err := e.subscriptions.Get(e.sub.ID)
+		return err == nil
+	}
+
+	return e.sub.ExpiresAt.IsZero() || now.Before(e.sub.ExpiresAt)
+}
+
+func (p *NotificationPusher) send(event pushedEvent) error {
+	body, err := ipp.EncodeSendNotifications(int32(event.sub.ID), []ipp.Attributes{event.notification})
+	if err != nil {
+		return err
+	}
+
+	client := p.Client
+	if client == nil {
+		client = defaultPushClient
+	}
+
+	resp, err := client.Post(event.sub.RecipientURI, ipp.ContentTypeIPP, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("recipient %s returned http status %d", event.sub.RecipientURI, resp.StatusCode)
+	}
+
+	decoded, err := ipp.NewResponseDecoder(resp.Body).Decode(nil)
+	if err != nil {
+		return err
+	}
+
+	return decoded.CheckForErrors()
+}
+
+// pushScheme checks the scheme of a notify-recipient-uri
+func pushScheme(recipient string) bool {
+	u, err := url.Parse(recipient)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	for _, scheme := range PushSchemes {
+		if u.Scheme == scheme {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NotificationReceiver is a http handler which receives the event notifications printers push with
+// Send-Notifications to the notify-recipient-uri of a subscription, e.g. one created with
+// ipp.IPPClient.CreatePrinterSubscription
+type NotificationReceiver struct {
+	// Handle is called with the notifications of each request
+	Handle func(notifications []ipp.Notification)
+}
+
+// NewNotificationReceiver creates a receiver which passes the notifications to handle
+func NewNotificationReceiver(handle func(notifications []ipp.Notification)) *NotificationReceiver {
+	return &NotificationReceiver{Handle: handle}
+}
+
+// ServeHTTP decodes a Send-Notifications request and responds with successful-ok, requests with other operations
+// are rejected with server-error-operation-not-supported
+func (n *NotificationReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != ipp.ContentTypeIPP {
+		http.Error(w, "content type must be "+ipp.ContentTypeIPP, http.StatusBadRequest)
+		return
+	}
+
+	header, notifications, err := ipp.DecodeSendNotifications(r.Body)
+	// the header is empty if the body is not an ipp message
+	if header == (ipp.MessageHeader{}) && err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := ipp.NewResponse(ipp.StatusOk, header.RequestId)
+	resp.OperationAttributes.Set(ipp.AttributeCharset, ipp.TagCharset, ipp.Charset)
+	resp.OperationAttributes.Set(ipp.AttributeNaturalLanguage, ipp.TagLanguage, ipp.CharsetLanguage)
+	switch {
+	case header.Code != ipp.OperationSendNotifications:
+		resp.StatusCode = ipp.StatusErrorOperationNotSupported
+	case err != nil:
+		resp.StatusCode = ipp.StatusErrorBadRequest
+		resp.OperationAttributes.Set(ipp.AttributeStatusMessage, ipp.TagText, err.Error())
+	case n.Handle != nil:
+		n.Handle(notifications)
+	}
+
+	data, err := resp.Encode()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ipp.ContentTypeIPP)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationPusher(t *testing.T) {
+	var mu sync.Mutex
+	var received []ipp.Notification
+	recipient := httptest.NewServer(NewNotificationReceiver(func(notifications []ipp.Notification) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, notifications...)
+	}))
+	defer recipient.Close()
+
+	printer := NewVirtualPrinter("test", nil)
+	s := NewServer()
+	printer.Register(s, "/ipp/print")
+
+	printerURI := "ipp://localhost/ipp/print"
+
+	// push subscriptions are rejected without a pusher
+	req := newPrinterRequest(ipp.OperationCreatePrinterSubscriptions, printerURI)
+	req.SubscriptionAttributes = map[string]interface{}{ipp.AttributeNotifyRecipientURI: recipient.URL}
+	resp := serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusErrorIgnoredAllSubscriptions, resp.StatusCode)
+
+	printer.Pusher = NewNotificationPusher()
+	var pushErr error
+	printer.Pusher.OnError = func(sub *Subscription, err error) {
+		pushErr = err
+	}
+
+	resp = serveTestRequest(t, s, "/ipp/print", newPrinterRequest(ipp.OperationGetPrinterAttributes, printerURI))
+	assert.Equal(t, "http", resp.PrinterAttributes[0][ipp.AttributeNotifySchemesSupported][0].Value)
+
+	req = newPrinterRequest(ipp.OperationCreatePrinterSubscriptions, printerURI)
+	req.SubscriptionAttributes = map[string]interface{}{ipp.AttributeNotifyRecipientURI: "mailto:test@localhost"}
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusErrorIgnoredAllSubscriptions, resp.StatusCode)
+
+	req = newPrinterRequest(ipp.OperationCreatePrinterSubscriptions, printerURI)
+	req.SubscriptionAttributes = map[string]interface{}{
+		ipp.AttributeNotifyRecipientURI: recipient.URL,
+		ipp.AttributeNotifyEvents:       []string{ipp.EventJobCreated},
+		ipp.AttributeNotifyUserData:     "token",
+	}
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	subscriptionID := resp.SubscriptionAttributes[0][ipp.AttributeNotifySubscriptionID][0].Value.(int)
+
+	req = newPrinterRequest(ipp.OperationGetSubscriptionAttributes, printerURI)
+	req.OperationAttributes[ipp.AttributeNotifySubscriptionID] = subscriptionID
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, recipient.URL, resp.SubscriptionAttributes[0][ipp.AttributeNotifyRecipientURI][0].Value)
+	assert.NotContains(t, resp.SubscriptionAttributes[0], ipp.AttributeNotifyPullMethod)
+
+	resp = serveTestRequest(t, s, "/ipp/print", newPrinterRequest(ipp.OperationCreateJob, printerURI))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	jobID := resp.JobAttributes[0][ipp.AttributeJobID][0].Value.(int)
+
+	printer.Pusher.Wait()
+	assert.Nil(t, pushErr)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if assert.Len(t, received, 1) {
+		assert.Equal(t, subscriptionID, received[0].SubscriptionID)
+		assert.Equal(t, ipp.EventJobCreated, received[0].Event)
+		assert.Equal(t, jobID, received[0].JobID)
+		assert.Equal(t, "token", received[0].UserData)
+	}
+}
+
+// blockingRecipient receives notifications, the first request blocks until release is closed. started is closed
+// when the first request arrived
+func blockingRecipient(t *testing.T, release chan struct{}) (recipient *httptest.Server, started chan struct{},
+	received func() []int) {
+	var mu sync.Mutex
+	var sequences []int
+	receiver := NewNotificationReceiver(func(notifications []ipp.Notification) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, n := range notifications {
+			sequences = append(sequences, n.SequenceNumber)
+		}
+	})
+
+	started = make(chan struct{})
+	var once sync.Once
+	recipient = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			close(started)
+			<-release
+		})
+		receiver.ServeHTTP(w, r)
+	}))
+	t.Cleanup(recipient.Close)
+
+	return recipient, started, func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int(nil), sequences...)
+	}
+}
+
+func sequenceEvent(sequence int) ipp.Attributes {
+	event := make(ipp.Attributes)
+	event.Set(ipp.AttributeNotifySubscribedEvent, ipp.TagKeyword, ipp.EventJobCompleted)
+	event.Set(ipp.AttributeNotifySequenceNumber, ipp.TagInteger, sequence)
+	return event
+}
+
+func TestNotificationPusher_MaxQueue(t *testing.T) {
+	release := make(chan struct{})
+	recipient, started, received := blockingRecipient(t, release)
+
+	pusher := NewNotificationPusher()
+	pusher.MaxQueue = 2
+	sub := &Subscription{ID: 1, RecipientURI: recipient.URL}
+	pusher.Push(sub, sequenceEvent(1))
+	<-started
+	for sequence := 2; sequence <= 5; sequence++ {
+		pusher.Push(sub, sequenceEvent(sequence))
+	}
+
+	close(release)
+	pusher.Wait()
+
+	// the oldest queued events are dropped
+	assert.Equal(t, []int{1, 4, 5}, received())
+}
+
+func TestNotificationPusher_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	recipient, started, received := blockingRecipient(t, release)
+
+	subscriptions := NewSubscriptionManager()
+	sub := &Subscription{Events: []string{ipp.EventJobCompleted}, RecipientURI: recipient.URL}
+	assert.Nil(t, subscriptions.Create(sub))
+
+	pusher := NewNotificationPusher()
+	pusher.push(pushedEvent{sub: sub, notification: sequenceEvent(1), subscriptions: subscriptions})
+	<-started
+	pusher.push(pushedEvent{sub: sub, notification: sequenceEvent(2), subscriptions: subscriptions})
+	pusher.push(pushedEvent{sub: sub, notification: sequenceEvent(3), subscriptions: subscriptions})
+
+	assert.Nil(t, subscriptions.Cancel(sub.ID))
+	close(release)
+	pusher.Wait()
+
+	// the event which was sent before the cancellation is delivered
+	assert.Equal(t, []int{1}, received())
+}
+
+func TestNotificationPusher_Expired(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	recipient, _, received := blockingRecipient(t, release)
+
+	pusher := NewNotificationPusher()
+	pusher.Push(&Subscription{ID: 1, RecipientURI: recipient.URL, ExpiresAt: time.Now().Add(-time.Second)},
+		sequenceEvent(1))
+	pusher.Wait()
+
+	assert.Empty(t, received())
+}
+
+func TestNotificationReceiver(t *testing.T) {
+	called := false
+	receiver := NewNotificationReceiver(func(notifications []ipp.Notification) {
+		called = true
+	})
+
+	resp := serveTestRequest(t, receiver, "/", ipp.NewRequest(ipp.OperationGetNotifications, 1))
+	assert.Equal(t, ipp.StatusErrorOperationNotSupported, resp.StatusCode)
+
+	resp = serveTestRequest(t, receiver, "/", ipp.NewRequest(ipp.OperationSendNotifications, 1))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.True(t, called)
+}
@@ -0,0 +1,267 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/dnssd"
+)
+
+var (
+	QueueExistsError   = errors.New("queue already exists")
+	QueueNotFoundError = errors.New("queue does not exist")
+)
+
+// ServiceRegistry registers dns-sd services, it is implemented by dnssd.Responder
+type ServiceRegistry interface {
+	Register(service *dnssd.Service) error
+	Unregister(service *dnssd.Service) error
+}
+
+// QueueConfig defines a printer queue hosted by a Server. every queue is served by its own VirtualPrinter, so
+// capabilities, document sinks, scheduling and authorization are independent of the other queues
+type QueueConfig struct {
+	// Name is the printer-name of the queue and the instance name of its dns-sd service
+	Name string
+	// Path is the http path of the queue, defaults to /printers/<name>
+	Path string
+	// UUID is the printer-uuid of the queue, a random uuid is generated if empty. a stable uuid should be configured,
+	// so clients recognize the queue after a restart of the server
+	UUID string
+	// Capabilities generates the ipp everywhere printer description attributes of the queue
+	Capabilities *Capabilities
+	// Icons are the png icons of the queue, see VirtualPrinter.SetIcon
+	Icons [][]byte
+	// Attributes are added to the printer description attributes after the capabilities
+	Attributes ipp.Attributes
+	// Sink receives the documents of the queue, the data is discarded if nil
+	Sink DocumentSink
+	// Scheduler enables job queueing for the queue, see VirtualPrinter.Scheduler
+	Scheduler Scheduler
+	// Banner generates banner pages for the jobs of the queue, see VirtualPrinter.Banner
+	Banner *Banner
+	// Accounting receives the accounting records of the jobs of the queue
+	Accounting AccountingHook
+	// Quota limits the usage of the users of the queue
+	Quota *Quota
+	// Authorizer is called for the requests of the queue after the authorizer of the server
+	Authorizer Authorizer
+	// DisableAdvertising excludes the queue from the dns-sd advertisements of the server
+	DisableAdvertising bool
+}
+
+// Queue is a printer queue hosted by a Server
+type Queue struct {
+	Printer  *VirtualPrinter
+	Endpoint *Endpoint
+
+	advertise bool
+	service   *dnssd.Service
+}
+
+// Name returns the name of the queue
+func (q *Queue) Name() string {
+	return q.Printer.Name()
+}
+
+// queues holds the queues of a server and the dns-sd registry they are advertised on
+type queues struct {
+	byName   map[string]*Queue
+	registry ServiceRegistry
+	port     int
+	secure   bool
+}
+
+// AddQueue creates a VirtualPrinter from the configuration and registers it at the path of the queue. the name, path
+// and uuid of the queue must be unique. if the queues of the server are advertised, the queue is advertised as well
+func (s *Server) AddQueue(config QueueConfig) (*Queue, error) {
+	if config.Name == "" {
+		return nil, errors.New("queue requires a name")
+	}
+
+	path := config.Path
+	if path == "" {
+		path = "/printers/" + config.Name
+	}
+	path = cleanPath(path)
+
+	printer := NewVirtualPrinter(config.Name, nil)
+	if config.UUID != "" {
+		printer.SetUUID(config.UUID)
+	}
+	if config.Capabilities != nil {
+		printer.SetCapabilities(*config.Capabilities)
+	}
+	for _, icon := range config.Icons {
+		if err := printer.SetIcon(icon); err != nil {
+			return nil, err
+		}
+	}
+	for name, attr := range config.Attributes {
+		printer.Attributes[name] = attr
+	}
+	printer.Sink = config.Sink
+	printer.Scheduler = config.Scheduler
+	printer.Banner = config.Banner
+	printer.Accounting = config.Accounting
+	printer.Quota = config.Quota
+
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	if s.queues.byName == nil {
+		s.queues.byName = make(map[string]*Queue)
+	}
+
+	for _, q := range s.queues.byName {
+		switch {
+		case strings.EqualFold(q.Name(), config.Name):
+			return nil, fmt.Errorf("%w: name %s", QueueExistsError, config.Name)
+		case q.Endpoint.Path() == path:
+			return nil, fmt.Errorf("%w: path %s", QueueExistsError, path)
+		case q.Printer.UUID() == printer.UUID():
+			return nil, fmt.Errorf("%w: uuid %s", QueueExistsError, printer.UUID())
+		}
+	}
+
+	s.mu.RLock()
+	_, exists := s.endpoints[path]
+	s.mu.RUnlock()
+	if exists {
+		return nil, fmt.Errorf("%w: path %s", QueueExistsError, path)
+	}
+
+	q := &Queue{
+		Printer:   printer,
+		Endpoint:  printer.Register(s, path),
+		advertise: !config.DisableAdvertising,
+	}
+	if config.Authorizer != nil {
+		q.Endpoint.SetAuthorizer(config.Authorizer)
+	}
+
+	if s.queues.registry != nil && q.advertise {
+		if err := s.registerQueue(q); err != nil {
+			s.RemoveEndpoint(path)
+			return nil, err
+		}
+	}
+
+	s.queues.byName[strings.ToLower(config.Name)] = q
+
+	return q, nil
+}
+
+// RemoveQueue removes the queue and its endpoint and stops advertising it
+func (s *Server) RemoveQueue(name string) error {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	q, ok := s.queues.byName[strings.ToLower(name)]
+	if !ok {
+		return QueueNotFoundError
+	}
+
+	delete(s.queues.byName, strings.ToLower(name))
+	s.RemoveEndpoint(q.Endpoint.Path())
+
+	if q.service != nil && s.queues.registry != nil {
+		return s.queues.registry.Unregister(q.service)
+	}
+
+	return nil
+}
+
+// Queue returns the queue with the given name
+func (s *Server) Queue(name string) (*Queue, bool) {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	q, ok := s.queues.byName[strings.ToLower(name)]
+	return q, ok
+}
+
+// Queues returns all queues of the server ordered by name
+func (s *Server) Queues() []*Queue {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	queues := make([]*Queue, 0, len(s.queues.byName))
+	for _, q := range s.queues.byName {
+		queues = append(queues, q)
+	}
+
+	sort.Slice(queues, func(i, j int) bool {
+		return queues[i].Name() < queues[j].Name()
+	})
+
+	return queues
+}
+
+// AdvertiseQueues registers the dns-sd services of all queues, and of queues added later, on the registry. the
+// queues are advertised on the given port, as _ipps._tcp services if secure is true
+func (s *Server) AdvertiseQueues(registry ServiceRegistry, port int, secure bool) error {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	s.queues.registry = registry
+	s.queues.port = port
+	s.queues.secure = secure
+
+	for _, q := range s.queues.byName {
+		if !q.advertise {
+			continue
+		}
+
+		if err := s.registerQueue(q); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// registerQueue advertises the queue on the registry of the server, the queue lock must be held
+func (s *Server) registerQueue(q *Queue) error {
+	service := q.Printer.Service(q.Endpoint.Path(), s.queues.port, s.queues.secure)
+	if err := s.queues.registry.Register(service); err != nil {
+		return err
+	}
+	q.service = service
+
+	return nil
+}
+
+// queueByUUID returns the endpoint of the queue with the printer-uuid
+func (s *Server) queueByUUID(uuid string) *Endpoint {
+	uuid = normalizeUUID(uuid)
+
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	for _, q := range s.queues.byName {
+		if q.Printer.UUID() == uuid {
+			return q.Endpoint
+		}
+	}
+
+	return nil
+}
+
+// SetUUID sets the printer-uuid of the printer, the uuid is accepted with or without the urn:uuid prefix
+func (p *VirtualPrinter) SetUUID(uuid string) {
+	p.uuid = normalizeUUID(uuid)
+}
+
+// normalizeUUID converts a uuid into the lower case urn:uuid form
+func normalizeUUID(uuid string) string {
+	uuid = strings.ToLower(uuid)
+	if !strings.HasPrefix(uuid, "urn:uuid:") {
+		uuid = "urn:uuid:" + uuid
+	}
+
+	return uuid
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/dnssd"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRegistry struct {
+	services map[string]*dnssd.Service
+}
+
+func (r *testRegistry) Register(service *dnssd.Service) error {
+	r.services[service.Instance] = service
+	return nil
+}
+
+func (r *testRegistry) Unregister(service *dnssd.Service) error {
+	delete(r.services, service.Instance)
+	return nil
+}
+
+func TestServer_Queues(t *testing.T) {
+	received := make(map[string]int)
+	sink := func(queue string) DocumentSink {
+		return DocumentSinkFunc(func(job *Job, doc Document, data io.Reader) error {
+			received[queue]++
+			_, err := io.Copy(ioutil.Discard, data)
+			return err
+		})
+	}
+
+	s := NewServer()
+
+	office, err := s.AddQueue(QueueConfig{
+		Name:         "office",
+		UUID:         "B2D1A9F4-3C7E-4E0B-9A51-0F6A3E8C2D11",
+		Capabilities: &Capabilities{MakeAndModel: "Office Laser", Duplex: true},
+		Sink:         sink("office"),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "/printers/office", office.Endpoint.Path())
+	assert.Equal(t, "urn:uuid:b2d1a9f4-3c7e-4e0b-9a51-0f6a3e8c2d11", office.Printer.UUID())
+
+	_, err = s.AddQueue(QueueConfig{
+		Name:       "labels",
+		Path:       "/ipp/labels",
+		Attributes: ipp.Attributes{ipp.AttributePrinterLocation: {{Tag: ipp.TagText, Value: "warehouse"}}},
+		Sink:       sink("labels"),
+		Authorizer: RequireAuthentication,
+	})
+	assert.Nil(t, err)
+
+	_, err = s.AddQueue(QueueConfig{Name: "Office", Path: "/other"})
+	assert.True(t, errors.Is(err, QueueExistsError))
+	_, err = s.AddQueue(QueueConfig{Name: "other", Path: "/printers/office"})
+	assert.True(t, errors.Is(err, QueueExistsError))
+	_, err = s.AddQueue(QueueConfig{Name: "other", UUID: "urn:uuid:b2d1a9f4-3c7e-4e0b-9a51-0f6a3e8c2d11"})
+	assert.True(t, errors.Is(err, QueueExistsError))
+
+	queues := s.Queues()
+	assert.Len(t, queues, 2)
+	assert.Equal(t, "labels", queues[0].Name())
+
+	// the queue is addressed by its uuid if the http path does not match a queue
+	req := newPrinterRequest(ipp.OperationPrintJob, "urn:uuid:b2d1a9f4-3c7e-4e0b-9a51-0f6a3e8c2d11")
+	resp := serveTestDocument(t, s, "/", req, []byte("data"))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, 1, received["office"])
+
+	req = newPrinterRequest(ipp.OperationPrintJob, "ipp://localhost/ipp/labels")
+	resp = serveTestDocument(t, s, "/ipp/labels", req, []byte("data"))
+	assert.Equal(t, ipp.StatusErrorNotAuthenticated, resp.StatusCode)
+	assert.Equal(t, 0, received["labels"])
+
+	req = newPrinterRequest(ipp.OperationGetPrinterAttributes, "ipp://localhost/ipp/labels")
+	req.OperationAttributes[ipp.AttributeRequestedAttributes] = []string{ipp.AttributePrinterLocation}
+	resp = serveTestRequest(t, s, "/ipp/labels", req)
+	assert.Equal(t, ipp.StatusErrorNotAuthenticated, resp.StatusCode)
+
+	req = newPrinterRequest(ipp.OperationGetPrinterAttributes, "ipp://localhost/printers/office")
+	req.OperationAttributes[ipp.AttributeRequestedAttributes] = []string{ipp.AttributePrinterMakeAndModel}
+	resp = serveTestRequest(t, s, "/printers/office", req)
+	assert.Equal(t, "Office Laser", resp.PrinterAttributes[0][ipp.AttributePrinterMakeAndModel][0].Value)
+}
+
+func TestServer_AdvertiseQueues(t *testing.T) {
+	registry := &testRegistry{services: make(map[string]*dnssd.Service)}
+
+	s := NewServer()
+	_, err := s.AddQueue(QueueConfig{Name: "office"})
+	assert.Nil(t, err)
+	_, err = s.AddQueue(QueueConfig{Name: "hidden", DisableAdvertising: true})
+	assert.Nil(t, err)
+
+	assert.Nil(t, s.AdvertiseQueues(registry, 631, true))
+	assert.Len(t, registry.services, 1)
+	assert.Equal(t, dnssd.ServiceTypeIPPS, registry.services["office"].Type)
+	assert.Equal(t, "printers/office", registry.services["office"].Text["rp"])
+
+	_, err = s.AddQueue(QueueConfig{Name: "labels"})
+	assert.Nil(t, err)
+	assert.Contains(t, registry.services, "labels")
+
+	assert.Nil(t, s.RemoveQueue("labels"))
+	assert.NotContains(t, registry.services, "labels")
+	assert.Equal(t, QueueNotFoundError, s.RemoveQueue("labels"))
+
+	_, ok := s.Queue("labels")
+	assert.False(t, ok)
+	assert.Len(t, s.Endpoints(), 2)
+}
@@ -0,0 +1,230 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+var QuotaExceededError = errors.New("quota exceeded")
+
+// QuotaLimit limits the usage of a user within the quota period, zero values are unlimited
+type QuotaLimit struct {
+	// Pages is the maximum number of impressions, including copies
+	Pages int
+	// Bytes is the maximum size of the document data
+	Bytes int64
+}
+
+// QuotaUsage is the usage of a user on a printer
+type QuotaUsage struct {
+	Pages int
+	Bytes int64
+}
+
+// QuotaStore persists the usage of the users, it must be safe for concurrent use. a store can be shared by multiple
+// printers, the usage is kept separately for each printer
+type QuotaStore interface {
+	// Usage returns the usage of the user on the printer since the given time
+	Usage(printer, user string, since time.Time) (QuotaUsage, error)
+	// Add adds the usage of a terminated job of the user on the printer
+	Add(printer, user string, usage QuotaUsage, at time.Time) error
+}
+
+// quotaEntry is the usage of a single job
+type quotaEntry struct {
+	at    time.Time
+	usage QuotaUsage
+}
+
+// MemoryQuotaStore is a QuotaStore which keeps the usage in memory. the usage is lost on restart, entries older
+// than the since time of a Usage call are discarded
+type MemoryQuotaStore struct {
+	mu      sync.Mutex
+	entries map[string][]quotaEntry
+}
+
+// NewMemoryQuotaStore creates an empty in-memory quota store
+func NewMemoryQuotaStore() *MemoryQuotaStore {
+	return &MemoryQuotaStore{entries: make(map[string][]quotaEntry)}
+}
+
+// Usage sums the usage of the user on the printer since the given time
+func (s *MemoryQuotaStore) Usage(printer, user string, since time.Time) (QuotaUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := quotaKey(printer, user)
+	entries := s.entries[key]
+
+	i := 0
+	for i < len(entries) && entries[i].at.Before(since) {
+		i++
+	}
+	entries = entries[i:]
+
+	if len(entries) == 0 {
+		delete(s.entries, key)
+	} else {
+		s.entries[key] = entries
+	}
+
+	var usage QuotaUsage
+	for _, entry := range entries {
+		usage.Pages += entry.usage.Pages
+		usage.Bytes += entry.usage.Bytes
+	}
+
+	return usage, nil
+}
+
+// Add stores the usage of the user on the printer
+func (s *MemoryQuotaStore) Add(printer, user string, usage QuotaUsage, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := quotaKey(printer, user)
+	entries := s.entries[key]
+
+	// keep the entries ordered by time, so expired entries can be discarded from the front
+	i := len(entries)
+	for i > 0 && entries[i-1].at.After(at) {
+		i--
+	}
+	entries = append(entries, quotaEntry{})
+	copy(entries[i+1:], entries[i:])
+	entries[i] = quotaEntry{at: at, usage: usage}
+
+	s.entries[key] = entries
+	return nil
+}
+
+// quotaKey returns the store key of the user on the printer, user names are case insensitive
+func quotaKey(printer, user string) string {
+	return printer + "\x00" + strings.ToLower(user)
+}
+
+// Quota enforces usage limits per user and printer. the usage is counted from the accounting records of the
+// terminated jobs within a sliding window of the quota period, like the job-quota-period of cups. pages are only
+// counted if the sink or a custom handler updates the job-impressions-completed of the jobs
+type Quota struct {
+	// Period is the length of the sliding window, the usage is never reset if zero
+	Period time.Duration
+	// Default is the limit of users without an entry in Users
+	Default QuotaLimit
+	// Users are the limits of individual users
+	Users map[string]QuotaLimit
+	// Store persists the usage
+	Store QuotaStore
+}
+
+// NewQuota creates a quota with the default limit for all users and an in-memory store
+func NewQuota(period time.Duration, limit QuotaLimit) *Quota {
+	return &Quota{
+		Period:  period,
+		Default: limit,
+		Users:   make(map[string]QuotaLimit),
+		Store:   NewMemoryQuotaStore(),
+	}
+}
+
+// Limit returns the limit of the user
+func (q *Quota) Limit(user string) QuotaLimit {
+	if limit, ok := q.Users[user]; ok {
+		return limit
+	}
+
+	return q.Default
+}
+
+// Usage returns the usage of the user on the printer within the current period
+func (q *Quota) Usage(printer, user string, now time.Time) (QuotaUsage, error) {
+	var since time.Time
+	if q.Period > 0 {
+		since = now.Add(-q.Period)
+	}
+
+	return q.Store.Usage(printer, user, since)
+}
+
+// Check returns QuotaExceededError if the user reached a limit on the printer, pages is the number of impressions
+// the new job is expected to print
+func (q *Quota) Check(printer, user string, pages int, now time.Time) error {
+	limit := q.Limit(user)
+	if limit.Pages <= 0 && limit.Bytes <= 0 {
+		return nil
+	}
+
+	usage, err := q.Usage(printer, user, now)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case limit.Pages > 0 && usage.Pages >= limit.Pages:
+		return QuotaExceededError
+	case limit.Pages > 0 && usage.Pages+pages > limit.Pages:
+		return fmt.Errorf("%w, %d of %d pages remaining", QuotaExceededError, limit.Pages-usage.Pages, limit.Pages)
+	case limit.Bytes > 0 && usage.Bytes >= limit.Bytes:
+		return QuotaExceededError
+	}
+
+	return nil
+}
+
+// Record adds the usage of the job to the store, it implements AccountingHook
+func (q *Quota) Record(record AccountingRecord) error {
+	usage := QuotaUsage{
+		Pages: record.Impressions * record.Copies,
+		Bytes: record.Bytes,
+	}
+	if usage.Pages == 0 && usage.Bytes == 0 {
+		return nil
+	}
+
+	at := record.CompletedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	return q.Store.Add(record.Printer, record.User, usage, at)
+}
+
+// validateQuota checks the quota of the user, the job-impressions of the request are counted as pages of the new
+// job. an exceeded quota is reported as client-error-not-authorized
+func (p *VirtualPrinter) validateQuota(req *Request, user string) error {
+	pages, _ := req.JobAttributes[ipp.AttributeJobImpressions].(int)
+	if copies, ok := req.JobAttributes[ipp.AttributeCopies].(int); ok && copies > 1 {
+		pages *= copies
+	}
+
+	err := p.Quota.Check(p.name, user, pages, time.Now())
+	if errors.Is(err, QuotaExceededError) {
+		return ipp.StatusError{Status: ipp.StatusErrorNotAuthorized, Message: err.Error()}
+	}
+
+	return err
+}
+
+// setQuotaAttributes adds the limits and the usage of the requesting user to the printer attributes
+func (p *VirtualPrinter) setQuotaAttributes(req *Request, attributes ipp.Attributes) {
+	user := req.UserName()
+	limit := p.Quota.Limit(user)
+
+	attributes.Set(ipp.AttributeJobQuotaPeriod, ipp.TagInteger, int(p.Quota.Period/time.Second))
+	attributes.Set(ipp.AttributeJobPageLimit, ipp.TagInteger, limit.Pages)
+	attributes.Set(ipp.AttributeJobKLimit, ipp.TagInteger, kiloOctets(limit.Bytes))
+
+	if user == "" {
+		return
+	}
+
+	if usage, err := p.Quota.Usage(p.name, user, time.Now()); err == nil {
+		attributes.Set(ipp.AttributeJobPagesUsed, ipp.TagInteger, usage.Pages)
+		attributes.Set(ipp.AttributeJobKOctetsUsed, ipp.TagInteger, kiloOctets(usage.Bytes))
+	}
+}
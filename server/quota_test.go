@@ -0,0 +1,104 @@
+package server
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryQuotaStore(t *testing.T) {
+	store := NewMemoryQuotaStore()
+	now := time.Now()
+
+	assert.Nil(t, store.Add("office", "alice", QuotaUsage{Pages: 2, Bytes: 100}, now.Add(-2*time.Hour)))
+	assert.Nil(t, store.Add("office", "Alice", QuotaUsage{Pages: 3, Bytes: 200}, now))
+	assert.Nil(t, store.Add("office", "alice", QuotaUsage{Pages: 1}, now.Add(-time.Minute)))
+	assert.Nil(t, store.Add("labels", "alice", QuotaUsage{Pages: 10}, now))
+
+	usage, err := store.Usage("office", "alice", time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, QuotaUsage{Pages: 6, Bytes: 300}, usage)
+
+	usage, err = store.Usage("office", "alice", now.Add(-time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, QuotaUsage{Pages: 4, Bytes: 200}, usage)
+
+	// the expired entry is discarded
+	usage, err = store.Usage("office", "alice", time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, QuotaUsage{Pages: 4, Bytes: 200}, usage)
+
+	usage, err = store.Usage("office", "bob", time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, QuotaUsage{}, usage)
+}
+
+func TestQuota_Check(t *testing.T) {
+	quota := NewQuota(24*time.Hour, QuotaLimit{Pages: 10})
+	quota.Users["bob"] = QuotaLimit{}
+	now := time.Now()
+
+	assert.Nil(t, quota.Record(AccountingRecord{Printer: "office", User: "alice", Impressions: 4, Copies: 2,
+		CompletedAt: now.Add(-time.Hour)}))
+	assert.Nil(t, quota.Record(AccountingRecord{Printer: "office", User: "bob", Impressions: 20, Copies: 1,
+		CompletedAt: now}))
+
+	tests := []struct {
+		user  string
+		pages int
+		now   time.Time
+		err   bool
+	}{
+		{user: "alice", pages: 0, now: now},
+		{user: "alice", pages: 2, now: now},
+		{user: "alice", pages: 3, now: now, err: true},
+		{user: "bob", pages: 100, now: now},
+		{user: "carol", pages: 11, now: now, err: true},
+	}
+
+	for _, test := range tests {
+		err := quota.Check("office", test.user, test.pages, test.now)
+		assert.Equal(t, test.err, errors.Is(err, QuotaExceededError), test.user, test.pages)
+	}
+
+	assert.Nil(t, quota.Record(AccountingRecord{Printer: "office", User: "alice", Impressions: 2, Copies: 1,
+		CompletedAt: now}))
+	assert.True(t, errors.Is(quota.Check("office", "alice", 0, now), QuotaExceededError))
+
+	// the usage expires after the period
+	assert.Nil(t, quota.Check("office", "alice", 10, now.Add(25*time.Hour)))
+}
+
+func TestVirtualPrinter_Quota(t *testing.T) {
+	printer := NewVirtualPrinter("office", nil)
+	printer.Quota = NewQuota(time.Hour, QuotaLimit{Bytes: 1500})
+
+	s := NewServer()
+	printer.Register(s, "/ipp/print")
+
+	print := func(user string) int16 {
+		req := newPrinterRequest(ipp.OperationPrintJob, "ipp://localhost/ipp/print")
+		req.OperationAttributes[ipp.AttributeRequestingUserName] = user
+		return serveTestDocument(t, s, "/ipp/print", req, make([]byte, 1024)).StatusCode
+	}
+
+	assert.Equal(t, ipp.StatusOk, print("alice"))
+	assert.Equal(t, ipp.StatusOk, print("alice"))
+	assert.Equal(t, ipp.StatusErrorNotAuthorized, print("alice"))
+	assert.Equal(t, ipp.StatusOk, print("bob"))
+
+	req := newPrinterRequest(ipp.OperationGetPrinterAttributes, "ipp://localhost/ipp/print")
+	req.OperationAttributes[ipp.AttributeRequestingUserName] = "alice"
+	req.OperationAttributes[ipp.AttributeRequestedAttributes] = []string{ipp.AttributeJobQuotaPeriod,
+		ipp.AttributeJobKLimit, ipp.AttributeJobKOctetsUsed}
+	resp := serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+
+	attributes := resp.PrinterAttributes[0]
+	assert.Equal(t, 3600, attributes[ipp.AttributeJobQuotaPeriod][0].Value)
+	assert.Equal(t, 2, attributes[ipp.AttributeJobKLimit][0].Value)
+	assert.Equal(t, 2, attributes[ipp.AttributeJobKOctetsUsed][0].Value)
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"image"
+	"io"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/pwg"
+)
+
+// DefaultMaxRasterPixels limits the pages decoded by a RasterSink, it fits a 13x19 inch page at 1200 dpi
+const DefaultMaxRasterPixels = 13 * 19 * 1200 * 1200
+
+// RasterSink is a DocumentSink which decodes pwg raster and urf documents into pages, e.g. for print to pdf or
+// print capture services. every decoded page is counted as an impression of the job. the back sides of duplex
+// sheets are passed as received, printers advertising a sheet back other than normal get mirrored back sides
+type RasterSink struct {
+	// Page is called with each page of a raster document, the number counts the pages of the document from one. if
+	// nil, the pages are only counted
+	Page func(job *Job, doc Document, number int, page image.Image) error
+	// Other receives the documents of other formats, they are discarded if nil
+	Other DocumentSink
+	// MaxPixels limits the width times height of a page, DefaultMaxRasterPixels if zero. larger pages abort the job
+	MaxPixels int64
+}
+
+// WriteDocument decodes raster documents and passes other documents to the Other sink
+func (s *RasterSink) WriteDocument(job *Job, doc Document, data io.Reader) error {
+	if doc.Format != ipp.MimeTypePwgRaster && doc.Format != ipp.MimeTypeUrf {
+		if s.Other != nil {
+			return s.Other.WriteDocument(job, doc, data)
+		}
+		return DiscardSink.WriteDocument(job, doc, data)
+	}
+
+	decoder := pwg.NewDecoder(data)
+	decoder.MaxPixels = s.MaxPixels
+	if decoder.MaxPixels == 0 {
+		decoder.MaxPixels = DefaultMaxRasterPixels
+	}
+
+	for number := 1; ; number++ {
+		page, err := decoder.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		job.Impressions++
+		if s.Page == nil {
+			continue
+		}
+
+		img, err := page.Image()
+		if err != nil {
+			return err
+		}
+		if err := s.Page(job, doc, number, img); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"io"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/pwg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRasterSink(t *testing.T) {
+	var pages []image.Image
+	var other []string
+	var records []AccountingRecord
+
+	printer := NewVirtualPrinter("raster", nil)
+	printer.DocumentFormats = []string{ipp.MimeTypePwgRaster, ipp.MimeTypeUrf, ipp.MimeTypePDF}
+	printer.Sink = &RasterSink{
+		Page: func(job *Job, doc Document, number int, page image.Image) error {
+			pages = append(pages, page)
+			return nil
+		},
+		Other: DocumentSinkFunc(func(job *Job, doc Document, data io.Reader) error {
+			other = append(other, doc.Format)
+			return nil
+		}),
+	}
+	printer.Accounting = AccountingHookFunc(func(record AccountingRecord) error {
+		records = append(records, record)
+		return nil
+	})
+
+	s := NewServer()
+	printer.Register(s, "/printers/raster")
+	client, closeServer := newTestClient(t, s)
+	defer closeServer()
+
+	page := image.NewGray(image.Rect(0, 0, 16, 8))
+	page.SetGray(3, 2, color.Gray{Y: 0x80})
+
+	print := func(format string, pageCount int) (int, error) {
+		pageImages := make([]image.Image, pageCount)
+		for i := range pageImages {
+			pageImages[i] = page
+		}
+
+		var buf bytes.Buffer
+		assert.Nil(t, pwg.Encode(&buf, pageImages, pwg.Options{Format: format}))
+		return client.PrintJob(ipp.Document{Document: &buf, Size: buf.Len(), Name: "pages", MimeType: format},
+			"raster", map[string]interface{}{})
+	}
+
+	jobID, err := print(ipp.MimeTypePwgRaster, 3)
+	assert.Nil(t, err)
+	job, _ := printer.Job(jobID)
+	assert.Equal(t, ipp.JobStateCompleted, job.State)
+	assert.Equal(t, 3, job.Impressions)
+	assert.Len(t, pages, 3)
+	assert.Equal(t, page, pages[0])
+
+	jobID, err = print(ipp.MimeTypeUrf, 2)
+	assert.Nil(t, err)
+	job, _ = printer.Job(jobID)
+	assert.Equal(t, 2, job.Impressions)
+	assert.Len(t, pages, 5)
+
+	pdf := []byte("%PDF-1.7")
+	jobID, err = client.PrintJob(ipp.Document{Document: bytes.NewReader(pdf), Size: len(pdf), MimeType: ipp.MimeTypePDF},
+		"raster", map[string]interface{}{})
+	assert.Nil(t, err)
+	job, _ = printer.Job(jobID)
+	assert.Equal(t, 0, job.Impressions)
+	assert.Equal(t, []string{ipp.MimeTypePDF}, other)
+
+	broken := []byte(pwg.SyncWord + "broken")
+	jobID, err = client.PrintJob(ipp.Document{Document: bytes.NewReader(broken), Size: len(broken),
+		MimeType: ipp.MimeTypePwgRaster}, "raster", map[string]interface{}{})
+	assert.Nil(t, err)
+	job, _ = printer.Job(jobID)
+	assert.Equal(t, ipp.JobStateAborted, job.State)
+
+	assert.Len(t, records, 4)
+	assert.Equal(t, []int{3, 2, 0, 0}, []int{records[0].Impressions, records[1].Impressions, records[2].Impressions,
+		records[3].Impressions})
+}
+
+func TestRasterSink_MaxPixels(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, pwg.Encode(&buf, []image.Image{image.NewGray(image.Rect(0, 0, 100, 100))}, pwg.Options{}))
+
+	job := &Job{}
+	sink := &RasterSink{MaxPixels: 5000}
+	err := sink.WriteDocument(job, Document{Format: ipp.MimeTypePwgRaster}, &buf)
+	assert.ErrorIs(t, err, pwg.FormatError)
+	assert.Equal(t, 0, job.Impressions)
+}
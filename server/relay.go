@@ -0,0 +1,220 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// RelayOperations are the operations forwarded by a RelayPrinter
+var RelayOperations = []int16{
+	ipp.OperationPrintJob,
+	ipp.OperationValidateJob,
+	ipp.OperationCreateJob,
+	ipp.OperationSendDocument,
+	ipp.OperationCancelJob,
+	ipp.OperationGetJobAttributes,
+	ipp.OperationGetJobs,
+	ipp.OperationGetPrinterAttributes,
+	ipp.OperationHoldJob,
+	ipp.OperationReleaseJob,
+	ipp.OperationRestartJob,
+	ipp.OperationIdentifyPrinter,
+}
+
+// RelayPrinter serves a printer which forwards all requests to a backend ipp printer, e.g. to add authentication,
+// accounting or tls in front of a legacy device. the printer-uri and job-uri attributes are rewritten in requests and
+// responses, so clients only see the uri of the relay
+type RelayPrinter struct {
+	// Backend is the printer uri of the backend printer, e.g. ipp://192.168.1.10/ipp/print
+	Backend string
+	// Client sends the requests to the backend, defaults to http.DefaultClient
+	Client *http.Client
+	// Username and Password are sent with basic authentication to the backend if set
+	Username string
+	Password string
+	// Attributes replace the printer attributes of the backend, e.g. printer-name or printer-location
+	Attributes ipp.Attributes
+	// OnForward is called with each forwarded request and the response of the backend, e.g. for accounting
+	OnForward func(req *Request, resp *ipp.Response)
+}
+
+// NewRelayPrinter creates a relay printer which forwards requests to the backend printer uri
+func NewRelayPrinter(backend string) *RelayPrinter {
+	return &RelayPrinter{
+		Backend:    backend,
+		Attributes: make(ipp.Attributes),
+	}
+}
+
+// Register registers the relay printer on the endpoint of the server with the given http path
+func (r *RelayPrinter) Register(s *Server, path string) *Endpoint {
+	e := s.Endpoint(path)
+
+	for _, op := range RelayOperations {
+		e.HandleFunc(op, r.forward)
+	}
+
+	return e
+}
+
+// forward sends the request to the backend and rewrites the response for the client
+func (r *RelayPrinter) forward(req *Request) (*ipp.Response, error) {
+	backendReq := r.backendRequest(req)
+
+	resp, err := r.send(backendReq)
+	if err != nil {
+		return nil, err
+	}
+
+	r.rewriteResponse(req, resp)
+
+	if r.OnForward != nil {
+		r.OnForward(req, resp)
+	}
+
+	return resp, nil
+}
+
+// backendRequest copies the request and replaces the uris of the relay with the ones of the backend
+func (r *RelayPrinter) backendRequest(req *Request) *ipp.Request {
+	backendReq := ipp.NewRequest(req.Operation, req.RequestId)
+	backendReq.ProtocolVersionMajor = req.ProtocolVersionMajor
+	backendReq.ProtocolVersionMinor = req.ProtocolVersionMinor
+	backendReq.JobAttributes = req.JobAttributes
+	backendReq.PrinterAttributes = req.PrinterAttributes
+	backendReq.SubscriptionAttributes = req.SubscriptionAttributes
+	backendReq.File = req.File
+
+	for name, value := range req.OperationAttributes {
+		backendReq.OperationAttributes[name] = value
+	}
+
+	if id, ok := jobID(req); ok {
+		delete(backendReq.OperationAttributes, ipp.AttributeJobURI)
+		backendReq.OperationAttributes[ipp.AttributeJobID] = id
+	}
+
+	backendReq.OperationAttributes[ipp.AttributePrinterURI] = r.Backend
+
+	// the backend records the user authenticated by the relay as job owner
+	if user := req.UserName(); user != "" {
+		backendReq.OperationAttributes[ipp.AttributeRequestingUserName] = user
+	}
+
+	return backendReq
+}
+
+// send posts the request with its document data to the backend. errors are returned as
+// server-error-service-unavailable
+func (r *RelayPrinter) send(req *ipp.Request) (*ipp.Response, error) {
+	payload, err := req.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader = bytes.NewReader(payload)
+	if req.File != nil {
+		body = io.MultiReader(body, req.File)
+	}
+
+	backendURL, err := httpURL(r.Backend)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, backendURL, body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", ipp.ContentTypeIPP)
+
+	if r.Username != "" {
+		httpReq.SetBasicAuth(r.Username, r.Password)
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, ipp.StatusError{Status: ipp.StatusErrorServiceUnavailable, Message: err.Error()}
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, ipp.StatusError{
+			Status:  ipp.StatusErrorServiceUnavailable,
+			Message: fmt.Sprintf("backend printer responded with http status %d", httpResp.StatusCode),
+		}
+	}
+
+	resp, err := ipp.NewResponseDecoder(httpResp.Body).Decode(nil)
+	if err != nil {
+		return nil, ipp.StatusError{Status: ipp.StatusErrorServiceUnavailable, Message: err.Error()}
+	}
+
+	return resp, nil
+}
+
+// rewriteResponse replaces the uris of the backend in the response with the ones of the relay
+func (r *RelayPrinter) rewriteResponse(req *Request, resp *ipp.Response) {
+	printerURI := requestPrinterURI(req)
+
+	for _, attributes := range resp.PrinterAttributes {
+		security := "none"
+		if strings.HasPrefix(printerURI, "ipps:") {
+			security = "tls"
+		}
+
+		attributes.Set(ipp.AttributePrinterUriSupported, ipp.TagUri, printerURI)
+		attributes.Set(ipp.AttributeUriSecuritySupported, ipp.TagKeyword, security)
+
+		for name, attr := range r.Attributes {
+			attributes[name] = attr
+		}
+	}
+
+	for _, attributes := range resp.JobAttributes {
+		if _, ok := attributes[ipp.AttributeJobPrinterURI]; ok {
+			attributes.Set(ipp.AttributeJobPrinterURI, ipp.TagUri, printerURI)
+		}
+
+		if _, ok := attributes[ipp.AttributeJobURI]; !ok {
+			continue
+		}
+
+		if id, ok := attributes[ipp.AttributeJobID]; ok && len(id) > 0 {
+			attributes.Set(ipp.AttributeJobURI, ipp.TagUri, fmt.Sprintf("%s/%v", strings.TrimSuffix(printerURI, "/"), id[0].Value))
+		}
+	}
+}
+
+// httpURL converts an ipp or ipps uri into the http url of the printer
+func httpURL(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+
+	switch u.Scheme {
+	case "ipp", "ipps":
+		// ipp uses port 631 instead of the default http ports
+		if u.Port() == "" {
+			u.Host += ":631"
+		}
+		u.Scheme = strings.Replace(u.Scheme, "ipp", "http", 1)
+	case "http", "https":
+	default:
+		return "", fmt.Errorf("unsupported uri scheme %s", u.Scheme)
+	}
+
+	return u.String(), nil
+}
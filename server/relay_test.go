@@ -0,0 +1,93 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelayPrinter(t *testing.T) {
+	var received bytes.Buffer
+	backend := NewVirtualPrinter("backend", func(job *Job, format string, document io.Reader) error {
+		_, err := io.Copy(&received, document)
+		return err
+	})
+	backendServer := NewServer()
+	backend.Register(backendServer, "/ipp/print")
+
+	ts := httptest.NewServer(backendServer)
+	defer ts.Close()
+
+	var forwarded []int16
+	relay := NewRelayPrinter("ipp://" + ts.Listener.Addr().String() + "/ipp/print")
+	relay.Attributes.Set(ipp.AttributePrinterLocation, ipp.TagText, "front desk")
+	relay.OnForward = func(req *Request, resp *ipp.Response) {
+		forwarded = append(forwarded, req.Operation)
+	}
+
+	s := NewServer()
+	relay.Register(s, "/printers/relay")
+
+	printerURI := "ipp://localhost/printers/relay"
+	// httptest requests are sent to example.com
+	relayURI := "ipp://example.com/printers/relay"
+
+	req := newPrinterRequest(ipp.OperationPrintJob, printerURI)
+	req.OperationAttributes[ipp.AttributeRequestingUserName] = "alice"
+	resp := serveTestDocument(t, s, "/printers/relay", req, []byte("%PDF-1.7 relay"))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, relayURI+"/1", resp.JobAttributes[0][ipp.AttributeJobURI][0].Value)
+	assert.Equal(t, "%PDF-1.7 relay", received.String())
+
+	job, ok := backend.Job(1)
+	assert.True(t, ok)
+	assert.Equal(t, "alice", job.OriginatingUser)
+
+	req = newPrinterRequest(ipp.OperationGetJobAttributes, printerURI)
+	req.OperationAttributes[ipp.AttributeJobURI] = printerURI + "/1"
+	resp = serveTestRequest(t, s, "/printers/relay", req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, relayURI+"/1", resp.JobAttributes[0][ipp.AttributeJobURI][0].Value)
+	assert.Equal(t, relayURI, resp.JobAttributes[0][ipp.AttributeJobPrinterURI][0].Value)
+
+	resp = serveTestRequest(t, s, "/printers/relay", newPrinterRequest(ipp.OperationGetPrinterAttributes, printerURI))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, "backend", resp.PrinterAttributes[0][ipp.AttributePrinterName][0].Value)
+	assert.Equal(t, "front desk", resp.PrinterAttributes[0][ipp.AttributePrinterLocation][0].Value)
+	assert.Equal(t, relayURI, resp.PrinterAttributes[0][ipp.AttributePrinterUriSupported][0].Value)
+
+	req = newPrinterRequest(ipp.OperationCancelJob, printerURI)
+	req.OperationAttributes[ipp.AttributeJobID] = 42
+	resp = serveTestRequest(t, s, "/printers/relay", req)
+	assert.Equal(t, ipp.StatusErrorNotFound, resp.StatusCode)
+
+	assert.Equal(t, []int16{ipp.OperationPrintJob, ipp.OperationGetJobAttributes, ipp.OperationGetPrinterAttributes, ipp.OperationCancelJob}, forwarded)
+
+	ts.Close()
+	resp = serveTestRequest(t, s, "/printers/relay", newPrinterRequest(ipp.OperationGetPrinterAttributes, printerURI))
+	assert.Equal(t, ipp.StatusErrorServiceUnavailable, resp.StatusCode)
+}
+
+func TestHttpURL(t *testing.T) {
+	testCases := []struct {
+		URI string
+		URL string
+	}{
+		{"ipp://printer/ipp/print", "http://printer:631/ipp/print"},
+		{"ipps://printer:8443/ipp/print", "https://printer:8443/ipp/print"},
+		{"http://printer/ipp", "http://printer/ipp"},
+	}
+
+	for _, c := range testCases {
+		u, err := httpURL(c.URI)
+		assert.Nil(t, err)
+		assert.Equal(t, c.URL, u)
+	}
+
+	_, err := httpURL("lpd://printer/queue")
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// group keywords of the requested-attributes operation attribute
+const (
+	RequestedAll                = ipp.RequestedAll
+	RequestedPrinterDescription = ipp.RequestedPrinterDescription
+	RequestedJobTemplate        = ipp.RequestedJobTemplate
+	RequestedJobDescription     = ipp.RequestedJobDescription
+)
+
+// JobTemplateAttributes are the job template attributes, the printer attributes describing them are named with a
+// -default, -supported or -ready suffix
+var JobTemplateAttributes = []string{
+	ipp.AttributeCopies,
+	ipp.AttributeFinishings,
+	ipp.AttributeFinishingsCol,
+	ipp.AttributeHoldJobUntil,
+	ipp.AttributeJobPriority,
+	ipp.AttributeJobSheets,
+	ipp.AttributeMedia,
+	ipp.AttributeMediaCol,
+	ipp.AttributeMediaSource,
+	ipp.AttributeMediaType,
+	ipp.AttributeMultipleDocumentHandling,
+	ipp.AttributeNumberUp,
+	ipp.AttributeOrientationRequested,
+	ipp.AttributeOutputBin,
+	ipp.AttributePageRanges,
+	ipp.AttributePrintColorMode,
+	ipp.AttributePrintQuality,
+	ipp.AttributePrinterResolution,
+	ipp.AttributeSides,
+}
+
+// RequestedAttributes returns the names and group keywords of the requested-attributes operation attribute of the
+// request, or the defaults if the request does not contain the attribute
+func RequestedAttributes(req *Request, defaults ...string) []string {
+	requested := stringValues(req.OperationAttributes[ipp.AttributeRequestedAttributes])
+	if len(requested) == 0 {
+		return defaults
+	}
+
+	return requested
+}
+
+// FilterPrinterAttributes returns the printer attributes matching the requested names and the group keywords all,
+// printer-description and job-template. the large media-col-database is only returned if requested by name
+func FilterPrinterAttributes(attributes ipp.Attributes, requested []string) ipp.Attributes {
+	return filterAttributes(attributes, requested, func(name string) string {
+		if name == ipp.AttributeMediaColDatabase {
+			return ""
+		}
+
+		for _, suffix := range []string{"-default", "-supported", "-ready"} {
+			if strings.HasSuffix(name, suffix) && isJobTemplateAttribute(strings.TrimSuffix(name, suffix)) {
+				return RequestedJobTemplate
+			}
+		}
+
+		return RequestedPrinterDescription
+	})
+}
+
+// FilterJobAttributes returns the job attributes matching the requested names and the group keywords all,
+// job-template and job-description
+func FilterJobAttributes(attributes ipp.Attributes, requested []string) ipp.Attributes {
+	return filterAttributes(attributes, requested, func(name string) string {
+		if isJobTemplateAttribute(name) {
+			return RequestedJobTemplate
+		}
+
+		return RequestedJobDescription
+	})
+}
+
+// filterAttributes returns the attributes matching the requested names, group keywords are expanded with the group
+// function which returns the group keyword of an attribute name, or an empty string for attributes which must be
+// requested by name
+func filterAttributes(attributes ipp.Attributes, requested []string, group func(name string) string) ipp.Attributes {
+	all := indexOf(requested, RequestedAll) >= 0
+
+	filtered := make(ipp.Attributes, len(requested))
+	for name, attr := range attributes {
+		if indexOf(requested, name) >= 0 {
+			filtered[name] = attr
+			continue
+		}
+
+		if g := group(name); g != "" && (all || indexOf(requested, g) >= 0) {
+			filtered[name] = attr
+		}
+	}
+
+	return filtered
+}
+
+func isJobTemplateAttribute(name string) bool {
+	return indexOf(JobTemplateAttributes, name) >= 0
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func attributeNames(attributes ipp.Attributes) []string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func TestFilterPrinterAttributes(t *testing.T) {
+	attributes := make(ipp.Attributes)
+	attributes.Set(ipp.AttributePrinterName, ipp.TagName, "test")
+	attributes.Set(ipp.AttributePrinterState, ipp.TagEnum, int(ipp.PrinterStateIdle))
+	attributes.Set(ipp.AttributeCopiesDefault, ipp.TagInteger, 1)
+	attributes.Set(ipp.AttributeMediaReady, ipp.TagKeyword, "iso_a4_210x297mm")
+	attributes.Set(ipp.AttributeSidesSupported, ipp.TagKeyword, "one-sided")
+	attributes.Set(ipp.AttributeDocumentFormatDefault, ipp.TagMimeType, "application/pdf")
+	all := attributeNames(attributes)
+	media, _ := ipp.NewMediaCol("iso_a4_210x297mm")
+	attributes.Set(ipp.AttributeMediaColDatabase, ipp.TagBeginCollection, media.Collection())
+
+	testCases := []struct {
+		Requested []string
+		Names     []string
+	}{
+		{[]string{RequestedAll}, all},
+		{[]string{RequestedAll, ipp.RequestedMediaColDatabase}, attributeNames(attributes)},
+		{[]string{ipp.RequestedMediaColDatabase}, []string{ipp.AttributeMediaColDatabase}},
+		{[]string{RequestedJobTemplate}, []string{ipp.AttributeCopiesDefault, ipp.AttributeMediaReady, ipp.AttributeSidesSupported}},
+		{[]string{RequestedPrinterDescription}, []string{ipp.AttributeDocumentFormatDefault, ipp.AttributePrinterName, ipp.AttributePrinterState}},
+		{[]string{ipp.AttributePrinterState, ipp.AttributeCopiesDefault, "unknown"}, []string{ipp.AttributeCopiesDefault, ipp.AttributePrinterState}},
+		{[]string{ipp.AttributePrinterName, RequestedJobTemplate}, []string{ipp.AttributeCopiesDefault, ipp.AttributeMediaReady, ipp.AttributePrinterName, ipp.AttributeSidesSupported}},
+		{[]string{RequestedJobDescription}, []string{}},
+	}
+
+	for _, c := range testCases {
+		assert.Equal(t, c.Names, attributeNames(FilterPrinterAttributes(attributes, c.Requested)), "%v", c.Requested)
+	}
+}
+
+func TestFilterJobAttributes(t *testing.T) {
+	attributes := make(ipp.Attributes)
+	attributes.Set(ipp.AttributeJobID, ipp.TagInteger, 1)
+	attributes.Set(ipp.AttributeJobState, ipp.TagEnum, int(ipp.JobStatePending))
+	attributes.Set(ipp.AttributeCopies, ipp.TagInteger, 2)
+	attributes.Set(ipp.AttributeSides, ipp.TagKeyword, "one-sided")
+
+	testCases := []struct {
+		Requested []string
+		Names     []string
+	}{
+		{[]string{RequestedAll}, attributeNames(attributes)},
+		{[]string{RequestedJobTemplate}, []string{ipp.AttributeCopies, ipp.AttributeSides}},
+		{[]string{RequestedJobDescription}, []string{ipp.AttributeJobID, ipp.AttributeJobState}},
+		{[]string{ipp.AttributeJobID, ipp.AttributeSides}, []string{ipp.AttributeJobID, ipp.AttributeSides}},
+	}
+
+	for _, c := range testCases {
+		assert.Equal(t, c.Names, attributeNames(FilterJobAttributes(attributes, c.Requested)), "%v", c.Requested)
+	}
+}
+
+func TestVirtualPrinter_RequestedAttributes(t *testing.T) {
+	printer := NewVirtualPrinter("test", nil)
+	s := NewServer()
+	printer.Register(s, "/ipp/print")
+
+	printerURI := "ipp://localhost/ipp/print"
+
+	req := newPrinterRequest(ipp.OperationGetPrinterAttributes, printerURI)
+	req.OperationAttributes[ipp.AttributeRequestedAttributes] = []string{ipp.AttributePrinterState, ipp.AttributePrinterName}
+	resp := serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, []string{ipp.AttributePrinterName, ipp.AttributePrinterState}, attributeNames(resp.PrinterAttributes[0]))
+
+	resp = serveTestRequest(t, s, "/ipp/print", newPrinterRequest(ipp.OperationCreateJob, printerURI))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+
+	resp = serveTestRequest(t, s, "/ipp/print", newPrinterRequest(ipp.OperationGetJobs, printerURI))
+	assert.Equal(t, []string{ipp.AttributeJobID, ipp.AttributeJobURI}, attributeNames(resp.JobAttributes[0]))
+
+	req = newPrinterRequest(ipp.OperationGetJobAttributes, printerURI)
+	req.OperationAttributes[ipp.AttributeJobID] = 1
+	req.OperationAttributes[ipp.AttributeRequestedAttributes] = ipp.AttributeJobState
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, []string{ipp.AttributeJobState}, attributeNames(resp.JobAttributes[0]))
+}
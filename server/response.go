@@ -0,0 +1,140 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// ResponseBuilder constructs the response to a request. the request id and version of the request are echoed
+type ResponseBuilder struct {
+	resp *ipp.Response
+}
+
+// NewResponseBuilder creates a builder for a successful-ok response to the given request
+func NewResponseBuilder(req *Request) *ResponseBuilder {
+	resp := ipp.NewResponse(ipp.StatusOk, req.RequestId)
+	resp.ProtocolVersionMajor = req.ProtocolVersionMajor
+	resp.ProtocolVersionMinor = req.ProtocolVersionMinor
+
+	if language, ok := req.OperationAttributes[ipp.AttributeNaturalLanguage].(string); ok {
+		resp.OperationAttributes.Set(ipp.AttributeNaturalLanguage, ipp.TagLanguage, language)
+	}
+
+	return &ResponseBuilder{resp: resp}
+}
+
+// Status sets the status code of the response
+func (b *ResponseBuilder) Status(status int16) *ResponseBuilder {
+	b.resp.StatusCode = status
+	return b
+}
+
+// StatusMessage sets the status-message operation attribute
+func (b *ResponseBuilder) StatusMessage(format string, args ...interface{}) *ResponseBuilder {
+	b.resp.OperationAttributes.Set(ipp.AttributeStatusMessage, ipp.TagText, fmt.Sprintf(format, args...))
+	return b
+}
+
+// DetailedStatusMessage sets the detailed-status-message operation attribute
+func (b *ResponseBuilder) DetailedStatusMessage(format string, args ...interface{}) *ResponseBuilder {
+	b.resp.OperationAttributes.Set(ipp.AttributeDetailedStatusMessage, ipp.TagText, fmt.Sprintf(format, args...))
+	return b
+}
+
+// OperationAttribute adds an operation attribute
+func (b *ResponseBuilder) OperationAttribute(name string, tag int8, values ...interface{}) *ResponseBuilder {
+	b.resp.OperationAttributes.Set(name, tag, values...)
+	return b
+}
+
+// Unsupported adds an attribute to the unsupported attributes group. if the status is still successful-ok, it is changed
+// to successful-ok-ignored-or-substituted-attributes
+func (b *ResponseBuilder) Unsupported(name string, tag int8, values ...interface{}) *ResponseBuilder {
+	if b.resp.UnsupportedAttributes == nil {
+		b.resp.UnsupportedAttributes = make(ipp.Attributes)
+	}
+
+	b.resp.UnsupportedAttributes.Set(name, tag, values...)
+
+	if b.resp.StatusCode == ipp.StatusOk {
+		b.resp.StatusCode = ipp.StatusOkIgnoredOrSubstituted
+	}
+
+	return b
+}
+
+// PrinterAttributes adds a printer attributes group
+func (b *ResponseBuilder) PrinterAttributes(attributes ipp.Attributes) *ResponseBuilder {
+	b.resp.PrinterAttributes = append(b.resp.PrinterAttributes, attributes)
+	return b
+}
+
+// JobAttributes adds a job attributes group
+func (b *ResponseBuilder) JobAttributes(attributes ipp.Attributes) *ResponseBuilder {
+	b.resp.JobAttributes = append(b.resp.JobAttributes, attributes)
+	return b
+}
+
+// SubscriptionAttributes adds a subscription attributes group
+func (b *ResponseBuilder) SubscriptionAttributes(attributes ipp.Attributes) *ResponseBuilder {
+	b.resp.SubscriptionAttributes = append(b.resp.SubscriptionAttributes, attributes)
+	return b
+}
+
+// EventNotification adds an event notification attributes group
+func (b *ResponseBuilder) EventNotification(attributes ipp.Attributes) *ResponseBuilder {
+	b.resp.EventNotificationAttributes = append(b.resp.EventNotificationAttributes, attributes)
+	return b
+}
+
+// Job adds a job attributes group with the job-id, job-uri, job-state and job-state-reasons attributes,
+// as returned by the job creation operations
+func (b *ResponseBuilder) Job(id int, uri string, state int8, reasons ...string) *ResponseBuilder {
+	if len(reasons) == 0 {
+		reasons = []string{"none"}
+	}
+
+	attributes := make(ipp.Attributes)
+	attributes.Set(ipp.AttributeJobID, ipp.TagInteger, id)
+	attributes.Set(ipp.AttributeJobURI, ipp.TagUri, uri)
+	attributes.Set(ipp.AttributeJobState, ipp.TagEnum, int(state))
+
+	values := make([]interface{}, len(reasons))
+	for i, reason := range reasons {
+		values[i] = reason
+	}
+	attributes.Set(ipp.AttributeJobStateReasons, ipp.TagKeyword, values...)
+
+	return b.JobAttributes(attributes)
+}
+
+// Build returns the constructed response
+func (b *ResponseBuilder) Build() *ipp.Response {
+	return b.resp
+}
+
+// OK returns a successful-ok response to the request
+func OK(req *Request) *ipp.Response {
+	return NewResponseBuilder(req).Build()
+}
+
+// Error returns a response with the given error status and status message
+func Error(req *Request, status int16, message string) *ipp.Response {
+	b := NewResponseBuilder(req).Status(status)
+	if message != "" {
+		b.StatusMessage("%s", message)
+	}
+
+	return b.Build()
+}
+
+// DocumentFormatNotSupported returns a client-error-document-format-not-supported response with the requested
+// document-format in the unsupported attributes group
+func DocumentFormatNotSupported(req *Request, format string) *ipp.Response {
+	return NewResponseBuilder(req).
+		Status(ipp.StatusErrorDocumentFormatNotSupported).
+		StatusMessage("document format %s is not supported", format).
+		Unsupported(ipp.AttributeDocumentFormat, ipp.TagMimeType, format).
+		Build()
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseBuilder(t *testing.T) {
+	ippReq := ipp.NewRequest(ipp.OperationPrintJob, 77)
+	ippReq.ProtocolVersionMajor = 1
+	ippReq.ProtocolVersionMinor = 1
+	ippReq.OperationAttributes[ipp.AttributeNaturalLanguage] = "de-DE"
+	req := &Request{Request: ippReq}
+
+	resp := NewResponseBuilder(req).
+		StatusMessage("job %d created", 5).
+		Unsupported(ipp.AttributeSides, ipp.TagKeyword, "two-sided-long-edge").
+		Job(5, "ipp://localhost/ipp/print/5", ipp.JobStatePending).
+		Build()
+
+	assert.Equal(t, int32(77), resp.RequestId)
+	assert.Equal(t, int8(1), resp.ProtocolVersionMajor)
+	assert.Equal(t, int8(1), resp.ProtocolVersionMinor)
+	assert.Equal(t, ipp.StatusOkIgnoredOrSubstituted, resp.StatusCode)
+	assert.Equal(t, "de-DE", resp.OperationAttributes[ipp.AttributeNaturalLanguage][0].Value)
+	assert.Equal(t, "job 5 created", resp.OperationAttributes[ipp.AttributeStatusMessage][0].Value)
+	assert.Equal(t, "two-sided-long-edge", resp.UnsupportedAttributes[ipp.AttributeSides][0].Value)
+	assert.Equal(t, 5, resp.JobAttributes[0][ipp.AttributeJobID][0].Value)
+	assert.Equal(t, "none", resp.JobAttributes[0][ipp.AttributeJobStateReasons][0].Value)
+
+	resp = DocumentFormatNotSupported(req, "image/tiff")
+	assert.Equal(t, ipp.StatusErrorDocumentFormatNotSupported, resp.StatusCode)
+	assert.Equal(t, "image/tiff", resp.UnsupportedAttributes[ipp.AttributeDocumentFormat][0].Value)
+
+	payload, err := resp.Encode()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, payload)
+}
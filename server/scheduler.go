@@ -0,0 +1,479 @@
+package server
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// job-hold-until keywords
+const (
+	HoldUntilNoHold      = "no-hold"
+	HoldUntilIndefinite  = "indefinite"
+	HoldUntilDayTime     = "day-time"
+	HoldUntilEvening     = "evening"
+	HoldUntilNight       = "night"
+	HoldUntilSecondShift = "second-shift"
+	HoldUntilThirdShift  = "third-shift"
+	HoldUntilWeekend     = "weekend"
+)
+
+// HoldUntilSupported are the job-hold-until keywords supported by a VirtualPrinter with a Scheduler
+var HoldUntilSupported = []string{
+	HoldUntilNoHold,
+	HoldUntilIndefinite,
+	HoldUntilDayTime,
+	HoldUntilEvening,
+	HoldUntilNight,
+	HoldUntilSecondShift,
+	HoldUntilThirdShift,
+	HoldUntilWeekend,
+}
+
+// job-state-reasons of queued jobs
+const (
+	jobReasonQueued             = "job-queued"
+	jobReasonHoldUntilSpecified = "job-hold-until-specified"
+	jobReasonIncoming           = "job-incoming"
+)
+
+// Scheduler selects the job a VirtualPrinter processes next. the queued jobs are ordered by job id, held jobs are
+// only passed after their hold time is reached. implementations may keep state between calls, e.g. to share the
+// printer fairly between users, and must be safe for concurrent use by multiple printers if they are shared
+type Scheduler interface {
+	// Next returns the job to process next or nil to keep the queued jobs waiting
+	Next(queued []*Job) *Job
+}
+
+// SchedulerFunc is a function which implements the Scheduler interface
+type SchedulerFunc func(queued []*Job) *Job
+
+// Next calls the function
+func (f SchedulerFunc) Next(queued []*Job) *Job {
+	return f(queued)
+}
+
+// FIFOScheduler processes the jobs in the order of their submission
+var FIFOScheduler Scheduler = SchedulerFunc(func(queued []*Job) *Job {
+	if len(queued) == 0 {
+		return nil
+	}
+
+	return queued[0]
+})
+
+// PriorityScheduler processes the job with the highest job-priority first, jobs with the same priority are processed
+// in the order of their submission
+var PriorityScheduler Scheduler = SchedulerFunc(func(queued []*Job) *Job {
+	var next *Job
+	for _, job := range queued {
+		if next == nil || JobPriority(job) > JobPriority(next) {
+			next = job
+		}
+	}
+
+	return next
+})
+
+// JobPriority returns the job-priority of a job, jobs without priority have the default priority 50
+func JobPriority(job *Job) int {
+	if attr := job.Attributes[ipp.AttributeJobPriority]; len(attr) > 0 {
+		if priority, ok := attr[0].Value.(int); ok {
+			return priority
+		}
+	}
+
+	return ipp.DefaultJobPriority
+}
+
+// JobHoldUntil returns the job-hold-until keyword of a job, no-hold if the job has none
+func JobHoldUntil(job *Job) string {
+	if attr := job.Attributes[ipp.AttributeHoldJobUntil]; len(attr) > 0 {
+		if keyword, ok := attr[0].Value.(string); ok {
+			return keyword
+		}
+	}
+
+	return HoldUntilNoHold
+}
+
+// ReleaseTime returns the earliest time at or after now a job with the job-hold-until keyword may be processed, in
+// the location of now. the time is zero for jobs held indefinitely, false is returned for unsupported keywords
+func ReleaseTime(holdUntil string, now time.Time) (time.Time, bool) {
+	switch holdUntil {
+	case HoldUntilNoHold:
+		return now, true
+	case HoldUntilIndefinite:
+		return time.Time{}, true
+	case HoldUntilDayTime:
+		return nextPeriod(now, 6, 18), true
+	case HoldUntilEvening:
+		return nextPeriod(now, 18, 24), true
+	case HoldUntilNight:
+		return nextPeriod(now, 18, 6), true
+	case HoldUntilSecondShift:
+		return nextPeriod(now, 16, 24), true
+	case HoldUntilThirdShift:
+		return nextPeriod(now, 0, 8), true
+	case HoldUntilWeekend:
+		days := int(time.Saturday - now.Weekday())
+		if now.Weekday() == time.Sunday || days == 0 {
+			return now, true
+		}
+		return time.Date(now.Year(), now.Month(), now.Day()+days, 0, 0, 0, 0, now.Location()), true
+	}
+
+	return time.Time{}, false
+}
+
+// nextPeriod returns now if it is within the daily period between the start and end hour, otherwise the next start
+// of the period. periods with an end before the start span midnight
+func nextPeriod(now time.Time, start, end int) time.Time {
+	hour := now.Hour()
+
+	inPeriod := hour >= start && hour < end
+	if end <= start {
+		inPeriod = hour >= start || hour < end
+	}
+
+	if inPeriod {
+		return now
+	}
+
+	day := now.Day()
+	if hour >= start {
+		day++
+	}
+
+	return time.Date(now.Year(), now.Month(), day, start, 0, 0, 0, now.Location())
+}
+
+// jobQueue holds the scheduling state of a VirtualPrinter
+type jobQueue struct {
+	mu       sync.Mutex
+	busy     bool
+	draining bool
+	timer    *time.Timer
+	spool    *DirectorySink
+	spoolMu  sync.Mutex
+}
+
+// spool returns the sink which stores the documents of queued jobs
+func (p *VirtualPrinter) spool() (*DirectorySink, error) {
+	p.queue.spoolMu.Lock()
+	defer p.queue.spoolMu.Unlock()
+
+	if p.queue.spool != nil {
+		return p.queue.spool, nil
+	}
+
+	directory := p.SpoolDirectory
+	if directory == "" {
+		directory = filepath.Join(os.TempDir(), "ipp-spool", strings.TrimPrefix(p.uuid, "urn:uuid:"))
+	}
+
+	spool, err := NewDirectorySink(directory)
+	if err != nil {
+		return nil, err
+	}
+	p.queue.spool = spool
+
+	return spool, nil
+}
+
+// removeSpool deletes the spooled documents of a job
+func (p *VirtualPrinter) removeSpool(job *Job) {
+	spool, err := p.spool()
+	if err != nil {
+		return
+	}
+
+	for _, doc := range job.Documents {
+		os.Remove(filepath.Join(spool.Directory, spool.FileName(job, doc)))
+	}
+}
+
+// validateScheduling checks the job-hold-until and job-priority job template attributes of a job creation request
+func validateScheduling(req *Request) error {
+	if holdUntil, ok := req.JobAttributes[ipp.AttributeHoldJobUntil]; ok {
+		keyword, _ := holdUntil.(string)
+		if _, ok := ReleaseTime(keyword, time.Now()); !ok {
+			return ipp.StatusError{
+				Status:  ipp.StatusErrorAttributesOrValues,
+				Message: fmt.Sprintf("job-hold-until %v is not supported", holdUntil),
+			}
+		}
+	}
+
+	if priority, ok := req.JobAttributes[ipp.AttributeJobPriority]; ok {
+		if value, _ := priority.(int); value < 1 || value > 100 {
+			return ipp.StatusError{
+				Status:  ipp.StatusErrorAttributesOrValues,
+				Message: fmt.Sprintf("job-priority %v is out of range 1-100", priority),
+			}
+		}
+	}
+
+	return nil
+}
+
+// queueJob marks a completely received job as queued, or as held if its job-hold-until is in the future
+func queueJob(job *Job, now time.Time) {
+	release, _ := ReleaseTime(JobHoldUntil(job), now)
+	if release.IsZero() || release.After(now) {
+		job.SetState(ipp.JobStateHeld, jobReasonHoldUntilSpecified)
+		return
+	}
+
+	job.SetState(ipp.JobStatePending, jobReasonQueued)
+}
+
+// schedule releases held jobs whose hold time is reached and starts processing the job selected by the scheduler,
+// unless a job is already processed or the printer is stopped. a timer is set for the next release of a held job
+func (p *VirtualPrinter) schedule() {
+	if p.Scheduler == nil {
+		return
+	}
+
+	p.queue.mu.Lock()
+	defer p.queue.mu.Unlock()
+
+	if p.queue.busy || p.queue.draining {
+		return
+	}
+
+	jobs, err := p.Jobs.List()
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	var wakeup time.Time
+	queued := make([]*Job, 0, len(jobs))
+
+	for _, job := range jobs {
+		switch {
+		case job.State == ipp.JobStateHeld:
+			release, _ := ReleaseTime(JobHoldUntil(job), now)
+			if release.IsZero() {
+				continue
+			}
+
+			if release.After(now) {
+				if wakeup.IsZero() || release.Before(wakeup) {
+					wakeup = release
+				}
+				continue
+			}
+
+			job, err = p.Jobs.Update(job.ID, func(job *Job) error {
+				if job.State != ipp.JobStateHeld {
+					return fmt.Errorf("job %d is not held", job.ID)
+				}
+				job.SetState(ipp.JobStatePending, jobReasonQueued)
+				return nil
+			})
+			if err != nil {
+				continue
+			}
+			p.publishJobEvent(ipp.EventJobStateChanged, job)
+
+			queued = append(queued, job)
+		case job.State == ipp.JobStatePending && indexOf(job.StateReasons, jobReasonQueued) >= 0:
+			queued = append(queued, job)
+		}
+	}
+
+	if p.queue.timer != nil {
+		p.queue.timer.Stop()
+		p.queue.timer = nil
+	}
+	if !wakeup.IsZero() {
+		p.queue.timer = time.AfterFunc(wakeup.Sub(now), p.schedule)
+	}
+
+	if len(queued) == 0 || p.State.State() == ipp.PrinterStateStopped {
+		return
+	}
+
+	next := p.Scheduler.Next(queued)
+	if next == nil {
+		return
+	}
+
+	p.queue.busy = true
+	go p.process(next.ID)
+}
+
+// process passes the spooled documents of a queued job to the sink and schedules the next job afterwards
+func (p *VirtualPrinter) process(id int) {
+	defer func() {
+		p.queue.mu.Lock()
+		p.queue.busy = false
+		p.queue.mu.Unlock()
+
+		p.schedule()
+	}()
+
+	job, err := p.Jobs.Update(id, func(job *Job) error {
+		if job.State != ipp.JobStatePending {
+			return fmt.Errorf("job %d is not queued", job.ID)
+		}
+		job.SetState(ipp.JobStateProcessing, "job-printing")
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	defer p.removeSpool(job)
+
+	p.publishJobEvent(ipp.EventJobStateChanged, job)
+
+	p.State.StartProcessing()
+	defer p.State.FinishProcessing()
+
+	impressions := job.Impressions
+	processErr := p.printSpooled(job)
+	impressions = job.Impressions - impressions
+
+	canceled := false
+	job, err = p.Jobs.Update(id, func(job *Job) error {
+		job.Impressions += impressions
+		switch {
+		case job.State == ipp.JobStateCanceled:
+			// the job was finished by cancel-job
+			canceled = true
+		case processErr != nil:
+			job.StateMessage = processErr.Error()
+			job.SetState(ipp.JobStateAborted, "aborted-by-system")
+		default:
+			job.SetState(ipp.JobStateCompleted, "job-completed-successfully")
+		}
+		return nil
+	})
+	if err != nil || canceled {
+		return
+	}
+
+	p.finishJob(job)
+}
+
+// printSpooled writes the spooled documents of the job to the sink, it stops if the job is canceled
+func (p *VirtualPrinter) printSpooled(job *Job) error {
+	spool, err := p.spool()
+	if err != nil {
+		return err
+	}
+
+	if err := p.writeBanner(job); err != nil {
+		return err
+	}
+
+	for _, doc := range job.Documents {
+		if current, ok := p.Job(job.ID); !ok || current.State == ipp.JobStateCanceled {
+			return nil
+		}
+
+		file, err := os.Open(filepath.Join(spool.Directory, spool.FileName(job, doc)))
+		if err != nil {
+			return err
+		}
+
+		if p.Sink != nil {
+			err = p.Sink.WriteDocument(job, doc, file)
+		} else {
+			_, err = file.WriteTo(ioutil.Discard)
+		}
+		file.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *VirtualPrinter) holdJob(req *Request) (*ipp.Response, error) {
+	if p.Scheduler == nil {
+		return Error(req, ipp.StatusErrorOperationNotSupported, "job scheduling is not enabled"), nil
+	}
+
+	job, resp := p.lookupJob(req)
+	if resp != nil {
+		return resp, nil
+	}
+
+	holdUntil, _ := req.OperationAttributes[ipp.AttributeHoldJobUntil].(string)
+	if holdUntil == "" {
+		holdUntil = HoldUntilIndefinite
+	}
+
+	if _, ok := ReleaseTime(holdUntil, time.Now()); !ok {
+		return NewResponseBuilder(req).
+			Status(ipp.StatusErrorAttributesOrValues).
+			Unsupported(ipp.AttributeHoldJobUntil, ipp.TagKeyword, holdUntil).
+			Build(), nil
+	}
+
+	job, err := p.Jobs.Update(job.ID, func(job *Job) error {
+		if job.State != ipp.JobStatePending && job.State != ipp.JobStateHeld {
+			return ipp.StatusError{
+				Status:  ipp.StatusErrorNotPossible,
+				Message: fmt.Sprintf("job %d is not pending", job.ID),
+			}
+		}
+
+		job.Attributes.Set(ipp.AttributeHoldJobUntil, ipp.TagKeyword, holdUntil)
+		if indexOf(job.StateReasons, jobReasonIncoming) < 0 {
+			queueJob(job, time.Now())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.publishJobEvent(ipp.EventJobStateChanged, job)
+	p.schedule()
+
+	return OK(req), nil
+}
+
+func (p *VirtualPrinter) releaseJob(req *Request) (*ipp.Response, error) {
+	if p.Scheduler == nil {
+		return Error(req, ipp.StatusErrorOperationNotSupported, "job scheduling is not enabled"), nil
+	}
+
+	job, resp := p.lookupJob(req)
+	if resp != nil {
+		return resp, nil
+	}
+
+	job, err := p.Jobs.Update(job.ID, func(job *Job) error {
+		if job.State != ipp.JobStateHeld {
+			return ipp.StatusError{
+				Status:  ipp.StatusErrorNotPossible,
+				Message: fmt.Sprintf("job %d is not held", job.ID),
+			}
+		}
+
+		job.Attributes.Set(ipp.AttributeHoldJobUntil, ipp.TagKeyword, HoldUntilNoHold)
+		job.SetState(ipp.JobStatePending, jobReasonQueued)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.publishJobEvent(ipp.EventJobStateChanged, job)
+	p.schedule()
+
+	return OK(req), nil
+}
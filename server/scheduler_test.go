@@ -0,0 +1,170 @@
+package server
+
+import (
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseTime(t *testing.T) {
+	// 2021-06-16 is a wednesday
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2021, 6, day, hour, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		holdUntil string
+		now       time.Time
+		expected  time.Time
+	}{
+		{HoldUntilNoHold, at(16, 10, 0), at(16, 10, 0)},
+		{HoldUntilIndefinite, at(16, 10, 0), time.Time{}},
+		{HoldUntilDayTime, at(16, 10, 0), at(16, 10, 0)},
+		{HoldUntilDayTime, at(16, 19, 0), at(17, 6, 0)},
+		{HoldUntilDayTime, at(16, 5, 59), at(16, 6, 0)},
+		{HoldUntilEvening, at(16, 10, 0), at(16, 18, 0)},
+		{HoldUntilEvening, at(16, 23, 30), at(16, 23, 30)},
+		{HoldUntilNight, at(16, 10, 0), at(16, 18, 0)},
+		{HoldUntilNight, at(16, 2, 0), at(16, 2, 0)},
+		{HoldUntilNight, at(16, 22, 0), at(16, 22, 0)},
+		{HoldUntilSecondShift, at(16, 12, 0), at(16, 16, 0)},
+		{HoldUntilThirdShift, at(16, 9, 0), at(17, 0, 0)},
+		{HoldUntilThirdShift, at(16, 7, 0), at(16, 7, 0)},
+		{HoldUntilWeekend, at(16, 10, 0), at(19, 0, 0)},
+		{HoldUntilWeekend, at(20, 10, 0), at(20, 10, 0)},
+	}
+
+	for _, test := range tests {
+		release, ok := ReleaseTime(test.holdUntil, test.now)
+		assert.True(t, ok, test.holdUntil)
+		assert.Equal(t, test.expected, release, "%s at %s", test.holdUntil, test.now)
+	}
+
+	_, ok := ReleaseTime("lunch", at(16, 10, 0))
+	assert.False(t, ok)
+}
+
+func TestPriorityScheduler(t *testing.T) {
+	job := func(id int, priority int) *Job {
+		j := &Job{ID: id, Attributes: make(ipp.Attributes)}
+		if priority > 0 {
+			j.Attributes.Set(ipp.AttributeJobPriority, ipp.TagInteger, priority)
+		}
+		return j
+	}
+
+	assert.Nil(t, PriorityScheduler.Next(nil))
+	assert.Equal(t, 2, PriorityScheduler.Next([]*Job{job(1, 0), job(2, 80), job(3, 80)}).ID)
+	assert.Equal(t, 1, PriorityScheduler.Next([]*Job{job(1, 0), job(2, 20)}).ID)
+	assert.Equal(t, 1, FIFOScheduler.Next([]*Job{job(1, 0), job(2, 80)}).ID)
+}
+
+func TestVirtualPrinter_Scheduler(t *testing.T) {
+	var mu sync.Mutex
+	var printed []int
+
+	printer := NewVirtualPrinter("test", nil)
+	printer.Scheduler = PriorityScheduler
+	printer.SpoolDirectory = t.TempDir()
+	printer.Sink = DocumentSinkFunc(func(job *Job, doc Document, data io.Reader) error {
+		mu.Lock()
+		defer mu.Unlock()
+		printed = append(printed, job.ID)
+		_, err := io.Copy(ioutil.Discard, data)
+		return err
+	})
+
+	s := NewServer()
+	printer.Register(s, "/ipp/print")
+	printerURI := "ipp://localhost/ipp/print"
+
+	printJob := func(attributes map[string]interface{}) *ipp.Response {
+		req := newPrinterRequest(ipp.OperationPrintJob, printerURI)
+		for name, value := range attributes {
+			req.JobAttributes[name] = value
+		}
+		return serveTestDocument(t, s, "/ipp/print", req, []byte("data"))
+	}
+
+	printer.State.Pause()
+
+	assert.Equal(t, ipp.StatusOk, printJob(map[string]interface{}{ipp.AttributeJobPriority: 10}).StatusCode)
+	assert.Equal(t, ipp.StatusOk, printJob(map[string]interface{}{ipp.AttributeJobPriority: 90}).StatusCode)
+	assert.Equal(t, ipp.StatusOk, printJob(map[string]interface{}{ipp.AttributeHoldJobUntil: HoldUntilIndefinite}).StatusCode)
+	assert.Equal(t, ipp.StatusErrorAttributesOrValues, printJob(map[string]interface{}{ipp.AttributeHoldJobUntil: "lunch"}).StatusCode)
+
+	job, _ := printer.Job(1)
+	assert.Equal(t, ipp.JobStatePending, job.State)
+	assert.Equal(t, []string{"job-queued"}, job.StateReasons)
+	job, _ = printer.Job(3)
+	assert.Equal(t, ipp.JobStateHeld, job.State)
+
+	printer.State.Resume()
+
+	completed := func(id int) func() bool {
+		return func() bool {
+			job, _ := printer.Job(id)
+			return job.State == ipp.JobStateCompleted
+		}
+	}
+
+	assert.Eventually(t, completed(1), time.Second, 10*time.Millisecond)
+	assert.Eventually(t, completed(2), time.Second, 10*time.Millisecond)
+
+	job, _ = printer.Job(3)
+	assert.Equal(t, ipp.JobStateHeld, job.State)
+
+	req := newPrinterRequest(ipp.OperationReleaseJob, printerURI)
+	req.OperationAttributes[ipp.AttributeJobID] = 3
+	assert.Equal(t, ipp.StatusOk, serveTestRequest(t, s, "/ipp/print", req).StatusCode)
+	assert.Eventually(t, completed(3), time.Second, 10*time.Millisecond)
+
+	req = newPrinterRequest(ipp.OperationReleaseJob, printerURI)
+	req.OperationAttributes[ipp.AttributeJobID] = 3
+	assert.Equal(t, ipp.StatusErrorNotPossible, serveTestRequest(t, s, "/ipp/print", req).StatusCode)
+
+	mu.Lock()
+	assert.Equal(t, []int{2, 1, 3}, printed)
+	mu.Unlock()
+
+	files, _ := ioutil.ReadDir(printer.SpoolDirectory)
+	assert.Len(t, files, 0)
+}
+
+func TestVirtualPrinter_HoldJob(t *testing.T) {
+	printer := NewVirtualPrinter("test", nil)
+	printer.Scheduler = FIFOScheduler
+	printer.SpoolDirectory = t.TempDir()
+
+	s := NewServer()
+	printer.Register(s, "/ipp/print")
+	printerURI := "ipp://localhost/ipp/print"
+
+	resp := serveTestRequest(t, s, "/ipp/print", newPrinterRequest(ipp.OperationCreateJob, printerURI))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+
+	req := newPrinterRequest(ipp.OperationHoldJob, printerURI)
+	req.OperationAttributes[ipp.AttributeJobID] = 1
+	assert.Equal(t, ipp.StatusOk, serveTestRequest(t, s, "/ipp/print", req).StatusCode)
+
+	req = newPrinterRequest(ipp.OperationSendDocument, printerURI)
+	req.OperationAttributes[ipp.AttributeJobID] = 1
+	req.OperationAttributes[ipp.AttributeLastDocument] = true
+	assert.Equal(t, ipp.StatusOk, serveTestDocument(t, s, "/ipp/print", req, []byte("data")).StatusCode)
+
+	job, _ := printer.Job(1)
+	assert.Equal(t, ipp.JobStateHeld, job.State)
+	assert.Equal(t, HoldUntilIndefinite, JobHoldUntil(job))
+
+	req = newPrinterRequest(ipp.OperationCancelJob, printerURI)
+	req.OperationAttributes[ipp.AttributeJobID] = 1
+	assert.Equal(t, ipp.StatusOk, serveTestRequest(t, s, "/ipp/print", req).StatusCode)
+
+	files, _ := ioutil.ReadDir(printer.SpoolDirectory)
+	assert.Len(t, files, 0)
+}
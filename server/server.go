@@ -0,0 +1,338 @@
+// Package server implements the server side of the internet printing protocol. it provides a http.Handler which decodes
+// incoming ipp requests, dispatches them to registered operation handlers and encodes their responses
+package server
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// Request defines a decoded ipp request together with the http request it was received on.
+// the document data of the request, if any, can be read from the embedded requests File field
+type Request struct {
+	*ipp.Request
+	HTTPRequest *http.Request
+	// Endpoint is the printer endpoint the request is addressed to, nil if the request targets the server itself
+	Endpoint *Endpoint
+	// User is the user authenticated by the Authenticator of the server, nil for anonymous requests
+	User *User
+	// ResponseData is set by handlers to send document data after a successful response, e.g. for CUPS-Get-Document.
+	// it is closed after the response was written if it implements io.Closer
+	ResponseData io.Reader
+}
+
+// HandlerFunc handles a single ipp operation. if an ipp.StatusError is returned, its status code and messages are sent
+// to the client, other errors are reported as server-error-internal-error
+type HandlerFunc func(req *Request) (*ipp.Response, error)
+
+// Server implements a http.Handler which serves ipp requests. requests are routed to the printer endpoint
+// matching the http path, operations without an endpoint specific handler fall back to the handlers of the server
+type Server struct {
+	// DisableValidation turns off the automatic validation of incoming requests
+	DisableValidation bool
+	// Authenticator authenticates the user of each request, all requests are anonymous if nil
+	Authenticator Authenticator
+	// Authorizer is called for each routed request before the operation handler, all requests are allowed if nil
+	Authorizer Authorizer
+	// Limits restricts the size of requests and the load of the server
+	Limits Limits
+	// DecodeProfile selects how deviations of requests from rfc 8011 are handled. with ipp.ProfileStrict such
+	// requests are rejected with client-error-bad-request, e.g. to test clients. the default tolerates them
+	DecodeProfile ipp.DecodeProfile
+	// Logger logs every request with its operation, status and duration. successful requests are logged at debug
+	// level, client errors at info, server errors, undecodable requests and tolerated deviations from the
+	// specification at warn and failed handlers at error level. nothing is logged if nil
+	Logger *slog.Logger
+
+	mu        sync.RWMutex
+	handlers  map[int16]HandlerFunc
+	endpoints map[string]*Endpoint
+
+	active   int32
+	inflight int32
+	limiter  rateLimiter
+
+	onShutdown []ShutdownFunc
+
+	queueMu sync.Mutex
+	queues  queues
+}
+
+// NewServer creates a new ipp server without any registered operation handlers
+func NewServer() *Server {
+	return &Server{
+		handlers:  make(map[int16]HandlerFunc),
+		endpoints: make(map[string]*Endpoint),
+	}
+}
+
+// HandleFunc registers the handler for the given operation for all paths, an already registered handler will be replaced
+func (s *Server) HandleFunc(operation int16, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[operation] = handler
+}
+
+// Operations returns the ids of all operations with a registered handler
+func (s *Server) Operations() []int16 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	operations := make([]int16, 0, len(s.handlers))
+	for op := range s.handlers {
+		operations = append(operations, op)
+	}
+
+	return operations
+}
+
+// ServeHTTP decodes the ipp request, calls the operation handler and writes the encoded ipp response. GET and HEAD
+// requests are passed to the resource handlers of the endpoints, see Endpoint.HandleResource
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		if handler, ok := s.lookupResource(r.URL.Path); ok {
+			handler.ServeHTTP(w, r)
+			return
+		}
+	}
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != ipp.ContentTypeIPP {
+		http.Error(w, "content type must be "+ipp.ContentTypeIPP, http.StatusBadRequest)
+		return
+	}
+
+	start := time.Now()
+	body := bufio.NewReader(r.Body)
+	header, _ := body.Peek(8)
+
+	decoder := ipp.NewRequestDecoder(s.requestReader(body))
+	decoder.Profile = s.DecodeProfile
+	if s.Logger != nil {
+		decoder.OnWarning = func(warning ipp.Warning) {
+			s.log(r, slog.LevelWarn, "ipp request deviates from the specification",
+				slog.String("path", r.URL.Path), slog.String("warning", warning.String()))
+		}
+	}
+
+	var resp *ipp.Response
+	var data io.Reader
+	req, err := decoder.Decode(nil)
+	switch {
+	case errors.Is(err, RequestTooLargeError):
+		s.log(r, slog.LevelWarn, "ipp request too large", slog.String("path", r.URL.Path))
+		resp = tooLargeResponse(header)
+	case errors.Is(err, ipp.ConformanceError):
+		s.log(r, slog.LevelInfo, "ipp request doesn't conform to rfc 8011", slog.String("path", r.URL.Path),
+			slog.Any("error", err))
+		resp = headerErrorResponse(header, ipp.StatusErrorBadRequest, err.Error())
+	case err != nil:
+		s.log(r, slog.LevelWarn, "unable to decode ipp request", slog.String("path", r.URL.Path), slog.Any("error", err))
+		http.Error(w, "unable to decode ipp request", http.StatusBadRequest)
+		return
+	default:
+		// the remaining body contains the document data
+		req.File = s.documentReader(body)
+		req.FileSize = -1
+
+		serverReq := &Request{Request: req, HTTPRequest: r}
+		resp = s.serve(serverReq)
+		s.logRequest(serverReq, resp, time.Since(start))
+		data = serverReq.ResponseData
+	}
+
+	if closer, ok := data.(io.Closer); ok {
+		defer closer.Close()
+	}
+	if resp.CheckForErrors() != nil {
+		data = nil
+	}
+
+	payload, err := resp.Encode()
+	if err != nil {
+		http.Error(w, "unable to encode ipp response", http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusOK
+	// clients need an http challenge to send credentials
+	if resp.StatusCode == ipp.StatusErrorNotAuthenticated && s.Authenticator != nil {
+		if challenge := s.Authenticator.Challenge(); challenge != "" {
+			w.Header().Set("WWW-Authenticate", challenge)
+			status = http.StatusUnauthorized
+		}
+	}
+
+	w.Header().Set("Content-Type", ipp.ContentTypeIPP)
+	if data == nil {
+		w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+	}
+	w.WriteHeader(status)
+	if _, err := w.Write(payload); err == nil && data != nil {
+		_, _ = io.Copy(w, data)
+	}
+}
+
+// serve serves the request if the load limits of the server are not exceeded
+func (s *Server) serve(req *Request) *ipp.Response {
+	atomic.AddInt32(&s.inflight, 1)
+	defer atomic.AddInt32(&s.inflight, -1)
+
+	release, busy := s.acquire(req)
+	if busy != nil {
+		return busy
+	}
+	defer release()
+
+	return s.serveIPP(req)
+}
+
+func (s *Server) serveIPP(req *Request) *ipp.Response {
+	if !isVersionSupported(req.ProtocolVersionMajor, req.ProtocolVersionMinor) {
+		resp := Error(req, ipp.StatusErrorVersionNotSupported, "unsupported ipp version")
+		resp.ProtocolVersionMajor = ipp.ProtocolVersionMajor
+		resp.ProtocolVersionMinor = ipp.ProtocolVersionMinor
+		return resp
+	}
+
+	if !s.DisableValidation {
+		if verr := validateRequest(req); verr != nil {
+			resp := Error(req, verr.status, verr.message)
+			resp.UnsupportedAttributes = verr.unsupported
+			return resp
+		}
+	}
+
+	handler, status := s.route(req)
+	if status != ipp.StatusOk {
+		return Error(req, status, ippStatusMessages[status])
+	}
+
+	if resp := s.authorize(req); resp != nil {
+		return resp
+	}
+
+	resp, err := handler(req)
+	if err != nil {
+		var statusErr ipp.StatusError
+		if errors.As(err, &statusErr) {
+			resp := Error(req, statusErr.Status, statusErr.Message)
+			if statusErr.DetailedMessage != "" {
+				resp.OperationAttributes.Set(ipp.AttributeDetailedStatusMessage, ipp.TagText, statusErr.DetailedMessage)
+			}
+			return resp
+		}
+
+		s.log(req.HTTPRequest, slog.LevelError, "ipp operation handler failed",
+			slog.String("operation", ipp.Operation(req.Operation).String()), slog.Any("error", err))
+		return Error(req, ipp.StatusErrorInternal, err.Error())
+	}
+
+	if resp == nil {
+		resp = OK(req)
+	}
+
+	resp.RequestId = req.RequestId
+	resp.ProtocolVersionMajor = req.ProtocolVersionMajor
+	resp.ProtocolVersionMinor = req.ProtocolVersionMinor
+
+	return resp
+}
+
+// log logs a record with the logger of the server, if any
+func (s *Server) log(r *http.Request, level slog.Level, msg string, attrs ...slog.Attr) {
+	if s.Logger == nil {
+		return
+	}
+
+	attrs = append(attrs, slog.String("remote", r.RemoteAddr))
+	s.Logger.LogAttrs(r.Context(), level, msg, attrs...)
+}
+
+// logRequest logs a served request at the level of its status
+func (s *Server) logRequest(req *Request, resp *ipp.Response, duration time.Duration) {
+	if s.Logger == nil {
+		return
+	}
+
+	level := slog.LevelDebug
+	switch {
+	case resp.StatusCode >= ipp.StatusErrorInternal:
+		level = slog.LevelWarn
+	case resp.StatusCode >= ipp.StatusErrorBadRequest:
+		level = slog.LevelInfo
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", ipp.Operation(req.Operation).String()),
+		slog.String("path", req.HTTPRequest.URL.Path),
+		slog.Int("request-id", int(req.RequestId)),
+		slog.String("status", ipp.Status(resp.StatusCode).String()),
+		slog.Duration("duration", duration),
+	}
+	if req.User != nil {
+		attrs = append(attrs, slog.String("user", req.User.Name))
+	}
+
+	s.log(req.HTTPRequest, level, "ipp request", attrs...)
+}
+
+// route looks up the handler of a request. if the request does not target a known endpoint and the operation is not
+// handled by the server, client-error-not-found is returned. unknown operations result in server-error-operation-not-supported
+func (s *Server) route(req *Request) (HandlerFunc, int16) {
+	req.Endpoint = s.resolveEndpoint(req)
+
+	if req.Endpoint != nil {
+		if handler, ok := req.Endpoint.handler(req.Operation); ok {
+			return handler, ipp.StatusOk
+		}
+	}
+
+	s.mu.RLock()
+	handler, ok := s.handlers[req.Operation]
+	hasEndpoints := len(s.endpoints) > 0
+	s.mu.RUnlock()
+
+	if ok {
+		return handler, ipp.StatusOk
+	}
+
+	if req.Endpoint == nil && hasEndpoints {
+		return nil, ipp.StatusErrorNotFound
+	}
+
+	return nil, ipp.StatusErrorOperationNotSupported
+}
+
+var ippStatusMessages = map[int16]string{
+	ipp.StatusErrorNotFound:              "the printer does not exist",
+	ipp.StatusErrorOperationNotSupported: "operation not supported",
+}
+
+// isVersionSupported checks if the ipp version is one of 1.0, 1.1, 2.0, 2.1 or 2.2
+func isVersionSupported(major, minor int8) bool {
+	switch major {
+	case 1:
+		return minor == 0 || minor == 1
+	case 2:
+		return minor >= 0 && minor <= 2
+	}
+
+	return false
+}
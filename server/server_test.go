@@ -0,0 +1,236 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient(t *testing.T, handler http.Handler) (*ipp.IPPClient, func()) {
+	ts := httptest.NewServer(handler)
+
+	host, port, err := net.SplitHostPort(ts.Listener.Addr().String())
+	assert.Nil(t, err)
+	portNumber, err := strconv.Atoi(port)
+	assert.Nil(t, err)
+
+	return ipp.NewIPPClient(host, portNumber, "user", "", false), ts.Close
+}
+
+func TestServer_ServeHTTP(t *testing.T) {
+	s := NewServer()
+	s.HandleFunc(ipp.OperationGetPrinterAttributes, func(req *Request) (*ipp.Response, error) {
+		resp := ipp.NewResponse(ipp.StatusOk, req.RequestId)
+		resp.PrinterAttributes = append(resp.PrinterAttributes, ipp.Attributes{
+			ipp.AttributePrinterName:  []ipp.Attribute{{Tag: ipp.TagName, Value: "test-printer"}},
+			ipp.AttributePrinterState: []ipp.Attribute{{Tag: ipp.TagEnum, Value: int(ipp.PrinterStateIdle)}},
+			ipp.AttributePrinterStateReasons: []ipp.Attribute{
+				{Tag: ipp.TagKeyword, Value: "media-low-warning"},
+				{Tag: ipp.TagKeyword, Value: "toner-low-warning"},
+			},
+		})
+		return resp, nil
+	})
+	s.HandleFunc(ipp.OperationPausePrinter, func(req *Request) (*ipp.Response, error) {
+		return nil, ipp.StatusError{Status: ipp.StatusErrorNotAuthorized, Message: "not allowed",
+			DetailedMessage: "pausing requires operator rights"}
+	})
+	s.HandleFunc(ipp.OperationResumePrinter, func(req *Request) (*ipp.Response, error) {
+		return nil, errors.New("broken")
+	})
+
+	client, closeServer := newTestClient(t, s)
+	defer closeServer()
+
+	attributes, err := client.GetPrinterAttributes("test", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "test-printer", attributes[ipp.AttributePrinterName][0].Value)
+	assert.Equal(t, int(ipp.PrinterStateIdle), attributes[ipp.AttributePrinterState][0].Value)
+	assert.Len(t, attributes[ipp.AttributePrinterStateReasons], 2)
+
+	err = client.PausePrinter("test")
+	var ippErr ipp.IPPError
+	assert.True(t, errors.As(err, &ippErr))
+	assert.Equal(t, ipp.StatusErrorNotAuthorized, ippErr.Status)
+	assert.Equal(t, "not allowed", ippErr.Message)
+	assert.Equal(t, "pausing requires operator rights", ippErr.DetailedMessage)
+	assert.True(t, ipp.IsClientError(err))
+
+	err = client.ResumePrinter("test")
+	assert.True(t, errors.As(err, &ippErr))
+	assert.Equal(t, ipp.StatusErrorInternal, ippErr.Status)
+
+	_, err = client.GetJobAttributes(1, nil)
+	assert.True(t, errors.As(err, &ippErr))
+	assert.Equal(t, ipp.StatusErrorOperationNotSupported, ippErr.Status)
+}
+
+func TestServer_ServeHTTP_Document(t *testing.T) {
+	var received []byte
+
+	s := NewServer()
+	s.HandleFunc(ipp.OperationPrintJob, func(req *Request) (*ipp.Response, error) {
+		data, err := ioutil.ReadAll(req.File)
+		if err != nil {
+			return nil, err
+		}
+		received = data
+
+		resp := ipp.NewResponse(ipp.StatusOk, req.RequestId)
+		resp.JobAttributes = append(resp.JobAttributes, ipp.Attributes{
+			ipp.AttributeJobID: []ipp.Attribute{{Tag: ipp.TagInteger, Value: 7}},
+		})
+		return resp, nil
+	})
+
+	client, closeServer := newTestClient(t, s)
+	defer closeServer()
+
+	doc := []byte("%PDF-1.4 test document")
+	jobID, err := client.PrintJob(ipp.Document{
+		Document: bytes.NewReader(doc),
+		Size:     len(doc),
+		Name:     "test",
+		MimeType: "application/pdf",
+	}, "test", map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Equal(t, 7, jobID)
+	assert.Equal(t, doc, received)
+}
+
+func TestServer_ServeHTTP_BadRequests(t *testing.T) {
+	s := NewServer()
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte{2, 0}))
+	r.Header.Set("Content-Type", "text/plain")
+	s.ServeHTTP(rec, r)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	payload, err := ipp.NewRequest(ipp.OperationGetJobs, 5).Encode()
+	assert.Nil(t, err)
+	payload[0] = 3
+
+	rec = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+	r.Header.Set("Content-Type", ipp.ContentTypeIPP)
+	s.ServeHTTP(rec, r)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	resp, err := ipp.NewResponseDecoder(rec.Body).Decode(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, ipp.StatusErrorVersionNotSupported, resp.StatusCode)
+	assert.Equal(t, int32(5), resp.RequestId)
+}
+
+func TestServer_IgnoredAttributes(t *testing.T) {
+	s := NewServer()
+	s.HandleFunc(ipp.OperationPrintJob, func(req *Request) (*ipp.Response, error) {
+		return NewResponseBuilder(req).
+			Job(3, "ipp://localhost/jobs/3", ipp.JobStatePending).
+			Unsupported(ipp.AttributeSides, ipp.TagKeyword, req.JobAttributes[ipp.AttributeSides]).
+			Build(), nil
+	})
+
+	client, closeServer := newTestClient(t, s)
+	defer closeServer()
+
+	job, err := client.SubmitJob(ipp.Document{Document: bytes.NewReader([]byte("data")), Size: 4, Name: "doc",
+		MimeType: ipp.MimeTypeOctetStream}, "test", map[string]interface{}{ipp.AttributeSides: "two-sided-long-edge"})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, job.ID)
+	assert.Equal(t, "two-sided-long-edge", job.Unsupported[ipp.AttributeSides][0].Value)
+}
+
+func TestServer_Logger(t *testing.T) {
+	var log bytes.Buffer
+	s := NewServer()
+	s.DisableValidation = true
+	s.Logger = slog.New(slog.NewTextHandler(&log, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	s.HandleFunc(ipp.OperationGetPrinterAttributes, func(req *Request) (*ipp.Response, error) {
+		return nil, nil
+	})
+	s.HandleFunc(ipp.OperationCancelJob, func(req *Request) (*ipp.Response, error) {
+		return nil, ipp.StatusError{Status: ipp.StatusErrorNotFound, Message: "no such job"}
+	})
+	s.HandleFunc(ipp.OperationGetJobs, func(req *Request) (*ipp.Response, error) {
+		return nil, errors.New("job store unavailable")
+	})
+
+	serveTestRequest(t, s, "/", ipp.NewRequest(ipp.OperationGetPrinterAttributes, 7))
+	serveTestRequest(t, s, "/", ipp.NewRequest(ipp.OperationCancelJob, 8))
+	serveTestRequest(t, s, "/", ipp.NewRequest(ipp.OperationGetJobs, 9))
+
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte{2, 0, 0}))
+	r.Header.Set("Content-Type", ipp.ContentTypeIPP)
+	s.ServeHTTP(rec, r)
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	assert.Len(t, lines, 5)
+	assert.Contains(t, lines[0], "level=DEBUG msg=\"ipp request\" operation=Get-Printer-Attributes path=/ request-id=7 status=successful-ok")
+	assert.Contains(t, lines[0], "remote=192.0.2.1:1234")
+	assert.Contains(t, lines[1], "level=INFO msg=\"ipp request\" operation=Cancel-Job")
+	assert.Contains(t, lines[1], "status=client-error-not-found")
+	assert.Contains(t, lines[2], "level=ERROR msg=\"ipp operation handler failed\" operation=Get-Jobs error=\"job store unavailable\"")
+	assert.Contains(t, lines[3], "level=WARN msg=\"ipp request\" operation=Get-Jobs")
+	assert.Contains(t, lines[4], "level=WARN msg=\"unable to decode ipp request\"")
+
+	// tolerated deviations are logged as warnings
+	log.Reset()
+	payload, err := ipp.NewRequest(ipp.OperationGetPrinterAttributes, 10).Encode()
+	assert.Nil(t, err)
+	rec = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload[:len(payload)-1]))
+	r.Header.Set("Content-Type", ipp.ContentTypeIPP)
+	s.ServeHTTP(rec, r)
+	assert.Contains(t, log.String(), "level=WARN msg=\"ipp request deviates from the specification\" path=/ "+
+		"warning=\"message without end-of-attributes tag\"")
+}
+
+func TestServer_DecodeProfile(t *testing.T) {
+	s := NewServer()
+	s.DisableValidation = true
+	s.HandleFunc(ipp.OperationGetPrinterAttributes, func(req *Request) (*ipp.Response, error) {
+		return nil, nil
+	})
+
+	payload, err := ipp.NewRequest(ipp.OperationGetPrinterAttributes, 3).Encode()
+	assert.Nil(t, err)
+	// the end-of-attributes tag is missing
+	payload = payload[:len(payload)-1]
+
+	serve := func() *ipp.Response {
+		rec := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+		r.Header.Set("Content-Type", ipp.ContentTypeIPP)
+		s.ServeHTTP(rec, r)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		resp, err := ipp.NewResponseDecoder(rec.Body).Decode(nil)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	assert.Equal(t, ipp.StatusOk, serve().StatusCode)
+
+	s.DecodeProfile = ipp.ProfileStrict
+	resp := serve()
+	assert.Equal(t, ipp.StatusErrorBadRequest, resp.StatusCode)
+	assert.Equal(t, int32(3), resp.RequestId)
+	assert.Contains(t, resp.OperationAttributes[ipp.AttributeStatusMessage][0].Value, "end-of-attributes")
+}
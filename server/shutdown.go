@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// shutdownPollInterval is the interval in which Shutdown checks for in-flight requests and jobs
+const shutdownPollInterval = 10 * time.Millisecond
+
+// ShutdownFunc is called by Server.Shutdown, it should return when its work is done or the context expired
+type ShutdownFunc func(ctx context.Context) error
+
+// OnShutdown registers a function which is called by Server.Shutdown to drain the endpoint, e.g. to wait for the
+// jobs of a printer
+func (e *Endpoint) OnShutdown(fn ShutdownFunc) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.shutdown = append(e.shutdown, fn)
+}
+
+func (e *Endpoint) shutdownFuncs() []ShutdownFunc {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return append([]ShutdownFunc(nil), e.shutdown...)
+}
+
+// onStop registers a function which is called by Server.Shutdown before any endpoint is drained, e.g. to reject
+// new jobs
+func (e *Endpoint) onStop(fn func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stop = append(e.stop, fn)
+}
+
+func (e *Endpoint) stopFuncs() []func() {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return append(([]func())(nil), e.stop...)
+}
+
+// RegisterOnShutdown registers a function which is called by Shutdown after all endpoints are drained, e.g. to
+// persist the state of the server. the jobs in a SpoolJobStore are written on every change and need no hook
+func (s *Server) RegisterOnShutdown(fn ShutdownFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onShutdown = append(s.onShutdown, fn)
+}
+
+// Shutdown gracefully drains the server. the dns-sd records of the queues are unregistered and all printers stop
+// accepting jobs at once, like CUPS-Reject-Jobs, while other requests are still served. the endpoints are drained
+// concurrently, Shutdown waits for in-flight requests and processing jobs until the context expires and runs the
+// functions registered with RegisterOnShutdown afterwards. the http server serving the Server should be shut down
+// after Shutdown returned. if the context expires, its error is returned, otherwise the first error of a shutdown
+// function
+func (s *Server) Shutdown(ctx context.Context) error {
+	var (
+		mu    sync.Mutex
+		first error
+	)
+	keep := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+
+	keep(s.unregisterQueues())
+
+	s.mu.RLock()
+	hooks := append([]ShutdownFunc(nil), s.onShutdown...)
+	s.mu.RUnlock()
+
+	endpoints := s.Endpoints()
+	for _, e := range endpoints {
+		for _, fn := range e.stopFuncs() {
+			fn()
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, e := range endpoints {
+		for _, fn := range e.shutdownFuncs() {
+			wg.Add(1)
+			go func(fn ShutdownFunc) {
+				defer wg.Done()
+				keep(fn(ctx))
+			}(fn)
+		}
+	}
+	wg.Wait()
+
+	keep(waitUntil(ctx, func() bool {
+		return atomic.LoadInt32(&s.inflight) == 0
+	}))
+
+	for _, fn := range hooks {
+		keep(fn(ctx))
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return first
+}
+
+// unregisterQueues removes the dns-sd records of all queues, queues added afterwards are not advertised
+func (s *Server) unregisterQueues() error {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	if s.queues.registry == nil {
+		return nil
+	}
+
+	var first error
+	for _, q := range s.queues.byName {
+		if q.service == nil {
+			continue
+		}
+
+		if err := s.queues.registry.Unregister(q.service); err != nil && first == nil {
+			first = err
+		}
+		q.service = nil
+	}
+	s.queues.registry = nil
+
+	return first
+}
+
+// waitUntil polls the condition until it is true or the context expires
+func waitUntil(ctx context.Context, done func() bool) error {
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+
+	for !done() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
+
+// stopAccepting rejects new jobs and stops starting queued jobs. queued jobs stay in the job store, with a
+// SpoolJobStore and a SpoolDirectory they are processed after a restart
+func (p *VirtualPrinter) stopAccepting() {
+	p.State.SetAcceptingJobs(false)
+
+	p.queue.mu.Lock()
+	defer p.queue.mu.Unlock()
+
+	p.queue.draining = true
+	if p.queue.timer != nil {
+		p.queue.timer.Stop()
+		p.queue.timer = nil
+	}
+}
+
+// drain waits until the processing job of the printer is finished
+func (p *VirtualPrinter) drain(ctx context.Context) error {
+	return waitUntil(ctx, func() bool {
+		p.queue.mu.Lock()
+		defer p.queue.mu.Unlock()
+
+		return !p.queue.busy && p.State.State() != ipp.PrinterStateProcessing
+	})
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/phin1x/go-ipp/dnssd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Shutdown(t *testing.T) {
+	started := make(chan int, 2)
+	release := make(chan struct{})
+
+	registry := &testRegistry{services: make(map[string]*dnssd.Service)}
+	s := NewServer()
+
+	q, err := s.AddQueue(QueueConfig{
+		Name:      "office",
+		Scheduler: FIFOScheduler,
+		Sink: DocumentSinkFunc(func(job *Job, doc Document, data io.Reader) error {
+			started <- job.ID
+			<-release
+			_, err := io.Copy(ioutil.Discard, data)
+			return err
+		}),
+	})
+	assert.Nil(t, err)
+	q.Printer.SpoolDirectory = t.TempDir()
+	assert.Nil(t, s.AdvertiseQueues(registry, 631, false))
+
+	var persisted bool
+	s.RegisterOnShutdown(func(ctx context.Context) error {
+		persisted = true
+		return nil
+	})
+
+	printerURI := "ipp://localhost/printers/office"
+	resp := serveTestDocument(t, s, "/printers/office", newPrinterRequest(ipp.OperationPrintJob, printerURI), []byte("1"))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	resp = serveTestDocument(t, s, "/printers/office", newPrinterRequest(ipp.OperationPrintJob, printerURI), []byte("2"))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, 1, <-started)
+
+	done := make(chan error)
+	go func() {
+		done <- s.Shutdown(context.Background())
+	}()
+
+	assert.Eventually(t, func() bool {
+		return !q.Printer.State.IsAcceptingJobs()
+	}, time.Second, time.Millisecond)
+	assert.Empty(t, registry.services)
+
+	resp = serveTestDocument(t, s, "/printers/office", newPrinterRequest(ipp.OperationPrintJob, printerURI), []byte("3"))
+	assert.Equal(t, ipp.StatusErrorNotAcceptingJobs, resp.StatusCode)
+
+	select {
+	case <-done:
+		t.Fatal("shutdown returned before the processing job finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	assert.Nil(t, <-done)
+	assert.True(t, persisted)
+
+	// the queued job is not started during the shutdown
+	job, ok := q.Printer.Job(2)
+	assert.True(t, ok)
+	assert.Equal(t, ipp.JobStatePending, job.State)
+	assert.Len(t, started, 0)
+}
+
+func TestServer_Shutdown_Deadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	printer := NewVirtualPrinter("office", nil)
+	printer.Scheduler = FIFOScheduler
+	printer.SpoolDirectory = t.TempDir()
+	printer.Sink = DocumentSinkFunc(func(job *Job, doc Document, data io.Reader) error {
+		<-release
+		return nil
+	})
+
+	s := NewServer()
+	printer.Register(s, "/ipp/print")
+
+	resp := serveTestDocument(t, s, "/ipp/print", newPrinterRequest(ipp.OperationPrintJob, "ipp://localhost/ipp/print"), []byte("1"))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, s.Shutdown(ctx))
+}
+
+func TestServer_Shutdown_Printers(t *testing.T) {
+	started := make(chan string, 2)
+	release := make(chan struct{})
+
+	s := NewServer()
+	printers := make(map[string]*VirtualPrinter)
+	for _, name := range []string{"office", "lab"} {
+		name := name
+		printer := NewVirtualPrinter(name, nil)
+		printer.Scheduler = FIFOScheduler
+		printer.Sink = DocumentSinkFunc(func(job *Job, doc Document, data io.Reader) error {
+			started <- name
+			<-release
+			return nil
+		})
+		printer.Register(s, "/printers/"+name)
+		printers[name] = printer
+	}
+
+	for name := range printers {
+		resp := serveTestDocument(t, s, "/printers/"+name,
+			newPrinterRequest(ipp.OperationPrintJob, "ipp://localhost/printers/"+name), []byte("1"))
+		assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	}
+	<-started
+	<-started
+
+	done := make(chan error)
+	go func() {
+		done <- s.Shutdown(context.Background())
+	}()
+
+	// all printers reject jobs while the processing jobs are drained
+	assert.Eventually(t, func() bool {
+		return !printers["office"].State.IsAcceptingJobs() && !printers["lab"].State.IsAcceptingJobs()
+	}, time.Second, time.Millisecond)
+
+	close(release)
+	assert.Nil(t, <-done)
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultSocketPort is the port of the raw socket protocol, also known as appsocket or jetdirect
+const DefaultSocketPort = "9100"
+
+// default timeouts of a SocketSink
+const (
+	DefaultSocketDialTimeout  = 30 * time.Second
+	DefaultSocketIdleTimeout  = 5 * time.Minute
+	DefaultSocketDrainTimeout = 10 * time.Second
+)
+
+// SocketSink is a DocumentSink which streams documents to a printer with a raw socket interface on port 9100, so
+// a VirtualPrinter can serve as ipp front-end of a network printer without ipp support. the document data is sent
+// unchanged, so the document formats of the printer should be restricted to the languages the device understands.
+// documents are sent one at a time because most devices accept only a single connection
+type SocketSink struct {
+	// Address is the host and port of the printer, the port defaults to 9100
+	Address string
+	// DialTimeout limits the time to connect to the printer
+	DialTimeout time.Duration
+	// IdleTimeout aborts a document if the printer does not accept data for the given time
+	IdleTimeout time.Duration
+	// DrainTimeout limits the time to wait for the printer to close the connection after all data was sent, devices
+	// which keep the connection open are treated as done after it
+	DrainTimeout time.Duration
+
+	mu sync.Mutex
+}
+
+// NewSocketSink creates a sink which sends documents to the printer at the address
+func NewSocketSink(address string) *SocketSink {
+	return &SocketSink{
+		Address:      address,
+		DialTimeout:  DefaultSocketDialTimeout,
+		IdleTimeout:  DefaultSocketIdleTimeout,
+		DrainTimeout: DefaultSocketDrainTimeout,
+	}
+}
+
+// WriteDocument connects to the printer and streams the document. after all data is written, the sending side of
+// the connection is closed and the sink waits until the printer closes the connection, so the document is only
+// reported as printed after the device received it completely. a printer which doesn't close the connection within
+// the drain timeout is assumed to have received the document
+func (s *SocketSink) WriteDocument(job *Job, doc Document, data io.Reader) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	address := s.Address
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, DefaultSocketPort)
+	}
+
+	timeout := s.DialTimeout
+	if timeout <= 0 {
+		timeout = DefaultSocketDialTimeout
+	}
+
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := io.Copy(&deadlineWriter{conn: conn, timeout: s.idleTimeout()}, data); err != nil {
+		return err
+	}
+
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		if err := tcp.CloseWrite(); err != nil {
+			return err
+		}
+	}
+
+	// devices may send status information back, it is discarded
+	drain := s.DrainTimeout
+	if drain <= 0 {
+		drain = DefaultSocketDrainTimeout
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(drain))
+	if _, err := io.Copy(io.Discard, conn); err != nil && !errors.Is(err, os.ErrDeadlineExceeded) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *SocketSink) idleTimeout() time.Duration {
+	if s.IdleTimeout <= 0 {
+		return DefaultSocketIdleTimeout
+	}
+
+	return s.IdleTimeout
+}
+
+// deadlineWriter extends the write deadline of the connection before each write
+type deadlineWriter struct {
+	conn    net.Conn
+	timeout time.Duration
+}
+
+func (w *deadlineWriter) Write(b []byte) (int, error) {
+	if err := w.conn.SetWriteDeadline(time.Now().Add(w.timeout)); err != nil {
+		return 0, err
+	}
+
+	return w.conn.Write(b)
+}
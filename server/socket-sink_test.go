@@ -0,0 +1,85 @@
+package server
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSocketSink(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer l.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		data, _ := ioutil.ReadAll(conn)
+		_, _ = conn.Write([]byte("@PJL INFO STATUS\r\n"))
+		conn.Close()
+		received <- data
+	}()
+
+	printer := NewVirtualPrinter("test", nil)
+	printer.DocumentFormats = []string{"application/vnd.hp-pcl"}
+	printer.Sink = NewSocketSink(l.Addr().String())
+
+	s := NewServer()
+	printer.Register(s, "/ipp/print")
+
+	doc := bytes.Repeat([]byte("PCL"), 10000)
+	req := newPrinterRequest(ipp.OperationPrintJob, "ipp://localhost/ipp/print")
+	resp := serveTestDocument(t, s, "/ipp/print", req, doc)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, doc, <-received)
+
+	job, _ := printer.Job(1)
+	assert.Equal(t, ipp.JobStateCompleted, job.State)
+}
+
+func TestSocketSink_Unreachable(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	address := l.Addr().String()
+	l.Close()
+
+	err = NewSocketSink(address).WriteDocument(&Job{ID: 1}, Document{Number: 1}, strings.NewReader("data"))
+	assert.Error(t, err)
+}
+
+func TestSocketSink_ConnectionKeptOpen(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer l.Close()
+
+	received := make(chan []byte, 1)
+	release := make(chan struct{})
+	defer close(release)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		data, _ := ioutil.ReadAll(conn)
+		received <- data
+		// the device keeps the connection open after the end of the data
+		<-release
+	}()
+
+	sink := NewSocketSink(l.Addr().String())
+	sink.DrainTimeout = 50 * time.Millisecond
+
+	err = sink.WriteDocument(&Job{ID: 1}, Document{Number: 1}, strings.NewReader("data"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("data"), <-received)
+}
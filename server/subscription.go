@@ -0,0 +1,357 @@
+package server
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+var (
+	SubscriptionNotFoundError = errors.New("subscription does not exist")
+	TooManySubscriptionsError = errors.New("too many subscriptions")
+)
+
+// default limits of a SubscriptionManager
+const (
+	DefaultLeaseDuration = 86400
+	MaxLeaseDuration     = 67108863
+	DefaultMaxEvents     = 100
+
+	// jobSubscriptionRetention is the time job subscriptions are kept after the job-completed event
+	jobSubscriptionRetention = 60 * time.Second
+)
+
+// Event defines a notification event generated by a printer or job
+type Event struct {
+	// Name is the notify-subscribed-event keyword of the event
+	Name string
+	Text string
+	// JobID is the id of the job the event belongs to, zero for printer events
+	JobID int
+	// Attributes contains the job or printer attributes describing the state after the event
+	Attributes ipp.Attributes
+	Time       time.Time
+
+	// SubscriptionID and SequenceNumber are assigned when the event is queued for a subscription
+	SubscriptionID int
+	SequenceNumber int
+}
+
+// Subscription defines a subscription for notification events
+type Subscription struct {
+	ID    int
+	Owner string
+	// Events are the subscribed event keywords
+	Events []string
+	// JobID is the id of the subscribed job, zero for printer subscriptions
+	JobID int
+	// PullMethod is ippget for subscriptions whose events are pulled with Get-Notifications, it is empty for push
+	// subscriptions
+	PullMethod string
+	// RecipientURI is the notify-recipient-uri the events of push subscriptions are delivered to
+	RecipientURI string
+	// LeaseDuration is the lease duration in seconds, zero means the subscription never expires and a negative
+	// duration is replaced with the default lease duration on creation
+	LeaseDuration int
+	ExpiresAt     time.Time
+	TimeInterval  int
+	UserData      string
+	PrinterURI    string
+
+	Charset         string
+	NaturalLanguage string
+
+	sequence int
+	events   []Event
+}
+
+// Copy returns a copy of the subscription without queued events
+func (s *Subscription) Copy() *Subscription {
+	c := *s
+	c.Events = append([]string{}, s.Events...)
+	c.events = nil
+	return &c
+}
+
+// Matches checks if the subscription receives the event. job-state-changed includes job-created and job-completed,
+// printer-state-changed includes printer-stopped
+func (s *Subscription) Matches(event Event) bool {
+	if s.JobID != 0 && s.JobID != event.JobID {
+		return false
+	}
+
+	for _, name := range s.Events {
+		switch {
+		case name == event.Name:
+			return true
+		case name == ipp.EventJobStateChanged && (event.Name == ipp.EventJobCreated || event.Name == ipp.EventJobCompleted):
+			return true
+		case name == ipp.EventPrinterStateChanged && event.Name == ipp.EventPrinterStopped:
+			return true
+		}
+	}
+
+	return false
+}
+
+// SubscriptionManager stores subscriptions and queues the events for pull delivery with ippget. all methods are safe
+// for concurrent use
+type SubscriptionManager struct {
+	// MaxSubscriptions limits the number of subscriptions, zero means unlimited
+	MaxSubscriptions int
+	// MaxEvents is the number of events kept per subscription
+	MaxEvents int
+	// DefaultLeaseDuration is the lease duration in seconds used if a request does not contain notify-lease-duration
+	DefaultLeaseDuration int
+
+	mu        sync.Mutex
+	subs      map[int]*Subscription
+	lastID    int
+	listeners []func(sub *Subscription, event Event)
+	now       func() time.Time
+}
+
+// NewSubscriptionManager creates an empty subscription manager with the default limits
+func NewSubscriptionManager() *SubscriptionManager {
+	return &SubscriptionManager{
+		MaxEvents:            DefaultMaxEvents,
+		DefaultLeaseDuration: DefaultLeaseDuration,
+		subs:                 make(map[int]*Subscription),
+		now:                  time.Now,
+	}
+}
+
+// OnEvent registers a function which is called for each event queued for a subscription, e.g. to push notifications
+func (m *SubscriptionManager) OnEvent(fn func(sub *Subscription, event Event)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.listeners = append(m.listeners, fn)
+}
+
+// Create stores a new subscription, assigns its id and sets the lease expiration
+func (m *SubscriptionManager) Create(sub *Subscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expire()
+
+	if m.MaxSubscriptions > 0 && len(m.subs) >= m.MaxSubscriptions {
+		return TooManySubscriptionsError
+	}
+
+	if len(sub.Events) == 0 {
+		sub.Events = []string{ipp.EventJobCompleted}
+	}
+
+	m.lastID++
+	sub.ID = m.lastID
+	m.setLease(sub, sub.LeaseDuration)
+	m.subs[sub.ID] = sub.Copy()
+
+	return nil
+}
+
+// Get returns a copy of the subscription with the given id or SubscriptionNotFoundError
+func (m *SubscriptionManager) Get(id int) (*Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expire()
+
+	sub, ok := m.subs[id]
+	if !ok {
+		return nil, SubscriptionNotFoundError
+	}
+
+	return sub.Copy(), nil
+}
+
+// List returns copies of all subscriptions ordered by id
+func (m *SubscriptionManager) List() []*Subscription {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expire()
+
+	subs := make([]*Subscription, 0, len(m.subs))
+	for _, sub := range m.subs {
+		subs = append(subs, sub.Copy())
+	}
+
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].ID < subs[j].ID
+	})
+
+	return subs
+}
+
+// Renew extends the lease of a printer subscription, a lease duration of zero never expires
+func (m *SubscriptionManager) Renew(id int, leaseDuration int) (*Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expire()
+
+	sub, ok := m.subs[id]
+	if !ok {
+		return nil, SubscriptionNotFoundError
+	}
+
+	if sub.JobID != 0 {
+		return nil, ipp.StatusError{Status: ipp.StatusErrorNotPossible, Message: "job subscriptions can not be renewed"}
+	}
+
+	m.setLease(sub, leaseDuration)
+
+	return sub.Copy(), nil
+}
+
+// Cancel removes a subscription
+func (m *SubscriptionManager) Cancel(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.subs[id]; !ok {
+		return SubscriptionNotFoundError
+	}
+
+	delete(m.subs, id)
+	return nil
+}
+
+// Publish queues the event for all matching subscriptions. job subscriptions expire shortly after the
+// job-completed event
+func (m *SubscriptionManager) Publish(event Event) {
+	m.mu.Lock()
+
+	m.expire()
+
+	if event.Time.IsZero() {
+		event.Time = m.now()
+	}
+
+	type delivery struct {
+		sub   *Subscription
+		event Event
+	}
+	var deliveries []delivery
+
+	for _, sub := range m.subs {
+		if !sub.Matches(event) {
+			continue
+		}
+
+		sub.sequence++
+		queued := event
+		queued.SubscriptionID = sub.ID
+		queued.SequenceNumber = sub.sequence
+
+		sub.events = append(sub.events, queued)
+		if max := m.maxEvents(); len(sub.events) > max {
+			sub.events = sub.events[len(sub.events)-max:]
+		}
+
+		if sub.JobID != 0 && event.Name == ipp.EventJobCompleted {
+			sub.ExpiresAt = event.Time.Add(jobSubscriptionRetention)
+		}
+
+		deliveries = append(deliveries, delivery{sub.Copy(), queued})
+	}
+
+	listeners := append([]func(sub *Subscription, event Event){}, m.listeners...)
+	m.mu.Unlock()
+
+	for _, d := range deliveries {
+		for _, listener := range listeners {
+			listener(d.sub, d.event)
+		}
+	}
+}
+
+// Events returns the queued events of a subscription with a sequence number of at least firstSequence
+func (m *SubscriptionManager) Events(id int, firstSequence int) ([]Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expire()
+
+	sub, ok := m.subs[id]
+	if !ok {
+		return nil, SubscriptionNotFoundError
+	}
+
+	events := make([]Event, 0, len(sub.events))
+	for _, event := range sub.events {
+		if event.SequenceNumber >= firstSequence {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// setLease sets the lease duration and expiration of a subscription, job subscriptions have no lease
+func (m *SubscriptionManager) setLease(sub *Subscription, leaseDuration int) {
+	if sub.JobID != 0 {
+		sub.LeaseDuration = 0
+		return
+	}
+
+	if leaseDuration < 0 {
+		leaseDuration = m.DefaultLeaseDuration
+	}
+	if leaseDuration > MaxLeaseDuration {
+		leaseDuration = MaxLeaseDuration
+	}
+
+	sub.LeaseDuration = leaseDuration
+	sub.ExpiresAt = time.Time{}
+	if leaseDuration > 0 {
+		sub.ExpiresAt = m.now().Add(time.Duration(leaseDuration) * time.Second)
+	}
+}
+
+// expire removes all subscriptions with an expired lease, the lock must be held
+func (m *SubscriptionManager) expire() {
+	now := m.now()
+	for id, sub := range m.subs {
+		if !sub.ExpiresAt.IsZero() && !now.Before(sub.ExpiresAt) {
+			delete(m.subs, id)
+		}
+	}
+}
+
+func (m *SubscriptionManager) maxEvents() int {
+	if m.MaxEvents <= 0 {
+		return DefaultMaxEvents
+	}
+
+	return m.MaxEvents
+}
+
+// supportedEvents are the events generated by the VirtualPrinter
+var supportedEvents = []string{
+	ipp.EventNone,
+	ipp.EventJobCreated,
+	ipp.EventJobCompleted,
+	ipp.EventJobStateChanged,
+	ipp.EventPrinterStateChanged,
+	ipp.EventPrinterStopped,
+}
+
+// unsupportedEvents returns the requested events which are not generated by the printer
+func unsupportedEvents(events []string) []string {
+	var unsupported []string
+	for _, event := range events {
+		if indexOf(supportedEvents, strings.ToLower(event)) < 0 {
+			unsupported = append(unsupported, event)
+		}
+	}
+
+	return unsupported
+}
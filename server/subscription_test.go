@@ -0,0 +1,87 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscription_Matches(t *testing.T) {
+	testCases := []struct {
+		Subscription Subscription
+		Event        Event
+		Matches      bool
+	}{
+		{Subscription{Events: []string{ipp.EventJobCompleted}}, Event{Name: ipp.EventJobCompleted, JobID: 1}, true},
+		{Subscription{Events: []string{ipp.EventJobCompleted}}, Event{Name: ipp.EventJobCreated, JobID: 1}, false},
+		{Subscription{Events: []string{ipp.EventJobStateChanged}}, Event{Name: ipp.EventJobCreated, JobID: 1}, true},
+		{Subscription{Events: []string{ipp.EventPrinterStateChanged}}, Event{Name: ipp.EventPrinterStopped}, true},
+		{Subscription{Events: []string{ipp.EventJobCompleted}, JobID: 2}, Event{Name: ipp.EventJobCompleted, JobID: 1}, false},
+		{Subscription{Events: []string{ipp.EventNone}}, Event{Name: ipp.EventJobCompleted, JobID: 1}, false},
+	}
+
+	for _, c := range testCases {
+		assert.Equal(t, c.Matches, c.Subscription.Matches(c.Event), "%v %v", c.Subscription.Events, c.Event.Name)
+	}
+}
+
+func TestSubscriptionManager(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	m := NewSubscriptionManager()
+	m.MaxEvents = 2
+	m.now = func() time.Time { return now }
+
+	printerSub := &Subscription{Events: []string{ipp.EventJobStateChanged}, LeaseDuration: 60}
+	assert.Nil(t, m.Create(printerSub))
+	assert.Equal(t, 1, printerSub.ID)
+	assert.Equal(t, now.Add(time.Minute), printerSub.ExpiresAt)
+
+	jobSub := &Subscription{JobID: 1, LeaseDuration: -1}
+	assert.Nil(t, m.Create(jobSub))
+	assert.Equal(t, []string{ipp.EventJobCompleted}, jobSub.Events)
+	assert.Equal(t, 0, jobSub.LeaseDuration)
+
+	var delivered []Event
+	m.OnEvent(func(sub *Subscription, event Event) {
+		delivered = append(delivered, event)
+	})
+
+	m.Publish(Event{Name: ipp.EventJobCreated, JobID: 1})
+	m.Publish(Event{Name: ipp.EventJobStateChanged, JobID: 1})
+	m.Publish(Event{Name: ipp.EventJobCompleted, JobID: 1})
+	assert.Len(t, delivered, 4)
+
+	events, err := m.Events(printerSub.ID, 1)
+	assert.Nil(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, 2, events[0].SequenceNumber)
+		assert.Equal(t, ipp.EventJobCompleted, events[1].Name)
+	}
+
+	events, err = m.Events(jobSub.ID, 1)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+
+	_, err = m.Renew(jobSub.ID, 60)
+	assert.Error(t, err)
+
+	renewed, err := m.Renew(printerSub.ID, 0)
+	assert.Nil(t, err)
+	assert.True(t, renewed.ExpiresAt.IsZero())
+
+	now = now.Add(2 * time.Minute)
+	assert.Len(t, m.List(), 1)
+
+	_, err = m.Get(jobSub.ID)
+	assert.Equal(t, SubscriptionNotFoundError, err)
+
+	assert.Nil(t, m.Cancel(printerSub.ID))
+	assert.Equal(t, SubscriptionNotFoundError, m.Cancel(printerSub.ID))
+
+	m.MaxSubscriptions = 1
+	assert.Nil(t, m.Create(&Subscription{}))
+	assert.Equal(t, TooManySubscriptionsError, m.Create(&Subscription{}))
+}
@@ -0,0 +1,391 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var UpgradeNotSupportedError = errors.New("connection does not support upgrades")
+
+// validity of generated self-signed certificates
+const selfSignedValidity = 10 * 365 * 24 * time.Hour
+
+// maxUpgradeBodySize limits the body of a request which is answered after a tls upgrade, the body must be buffered
+// because the connection is taken over before the request is served
+const maxUpgradeBodySize = 16 << 20
+
+// TLSConfig returns a tls configuration for serving ipps with the given certificates
+func TLSConfig(certificates ...tls.Certificate) *tls.Config {
+	return &tls.Config{
+		Certificates: certificates,
+		MinVersion:   tls.VersionTLS12,
+	}
+}
+
+// SelfSignedCertificate generates a self-signed certificate for the printer with the given uuid. the uuid is added as
+// uri subject alternative name and determines the serial number, so clients can pin the certificate to the printer.
+// hosts are added as dns names or ip addresses, the first host is used as common name
+func SelfSignedCertificate(uuid string, hosts ...string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	template, err := certificateTemplate(uuid, hosts)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: template}, nil
+}
+
+// LoadOrCreateCertificate loads the certificate of the printer with the given uuid from the directory. if it does not
+// exist, a self-signed certificate is generated and saved, so the certificate stays the same across restarts
+func LoadOrCreateCertificate(directory, uuid string, hosts ...string) (tls.Certificate, error) {
+	name := strings.TrimPrefix(uuid, "urn:uuid:")
+	certFile := filepath.Join(directory, name+".crt")
+	keyFile := filepath.Join(directory, name+".key")
+
+	if cert, err := tls.LoadX509KeyPair(certFile, keyFile); err == nil {
+		return cert, nil
+	} else if !os.IsNotExist(err) {
+		return tls.Certificate{}, err
+	}
+
+	cert, err := SelfSignedCertificate(uuid, hosts...)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return tls.Certificate{}, err
+	}
+
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}), 0600); err != nil {
+		return tls.Certificate{}, err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	if err := ioutil.WriteFile(certFile, certPEM, 0644); err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return cert, nil
+}
+
+// Certificate loads or creates the self-signed certificate of the printer in the directory, see LoadOrCreateCertificate
+func (p *VirtualPrinter) Certificate(directory string, hosts ...string) (tls.Certificate, error) {
+	return LoadOrCreateCertificate(directory, p.uuid, hosts...)
+}
+
+func certificateTemplate(uuid string, hosts []string) (*x509.Certificate, error) {
+	if !strings.HasPrefix(uuid, "urn:uuid:") {
+		uuid = "urn:uuid:" + uuid
+	}
+
+	uri, err := url.Parse(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256([]byte(uuid))
+	serial := new(big.Int).SetBytes(sum[:16])
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: strings.TrimPrefix(uuid, "urn:uuid:")},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		URIs:                  []*url.URL{uri},
+	}
+
+	if len(hosts) > 0 {
+		template.Subject.CommonName = hosts[0]
+	}
+
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	return template, nil
+}
+
+// UpgradeHandler implements the http upgrade to tls (rfc 2817) required by rfc 8010 for ipp clients which connect to
+// an unencrypted port like 631. requests with an "Upgrade: TLS/1.x" header are answered with 101 Switching Protocols,
+// after the tls handshake the original request is answered and the connection is served encrypted. clients usually
+// request the upgrade with "OPTIONS *", which is answered by http.Server itself unless DisableGeneralOptionsHandler is
+// set, so the handler should be served with HTTPServer or ListenAndServe
+type UpgradeHandler struct {
+	Handler http.Handler
+	Config  *tls.Config
+	// RequireTLS rejects unencrypted requests without an upgrade header with 426 Upgrade Required
+	RequireTLS bool
+}
+
+// NewUpgradeHandler wraps the handler, e.g. a Server, with tls upgrade support
+func NewUpgradeHandler(handler http.Handler, config *tls.Config) *UpgradeHandler {
+	return &UpgradeHandler{Handler: handler, Config: config}
+}
+
+// HTTPServer returns a http server for the address which passes "OPTIONS *" requests to the handler
+func (h *UpgradeHandler) HTTPServer(address string) *http.Server {
+	return &http.Server{
+		Addr:                         address,
+		Handler:                      h,
+		DisableGeneralOptionsHandler: true,
+	}
+}
+
+// ListenAndServe listens on the tcp address and serves unencrypted connections which can be upgraded to tls
+func (h *UpgradeHandler) ListenAndServe(address string) error {
+	return h.HTTPServer(address).ListenAndServe()
+}
+
+// ServeHTTP upgrades the connection if requested and passes all other requests to the handler
+func (h *UpgradeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.TLS != nil {
+		h.Handler.ServeHTTP(w, r)
+		return
+	}
+
+	if isTLSUpgrade(r) {
+		if err := h.upgrade(w, r); err == UpgradeNotSupportedError {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if h.RequireTLS {
+		w.Header().Set("Upgrade", "TLS/1.2, HTTP/1.1")
+		w.Header().Set("Connection", "Upgrade")
+		http.Error(w, "tls required", http.StatusUpgradeRequired)
+		return
+	}
+
+	h.Handler.ServeHTTP(w, r)
+}
+
+// upgrade switches the connection to tls, answers the request and serves the following requests of the connection
+func (h *UpgradeHandler) upgrade(w http.ResponseWriter, r *http.Request) error {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return UpgradeNotSupportedError
+	}
+
+	// the body can not be read from the request after the connection is hijacked
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxUpgradeBodySize))
+	if err != nil {
+		return err
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return err
+	}
+
+	_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: TLS/1.2, HTTP/1.1\r\nConnection: Upgrade\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return err
+	}
+
+	tlsConn := tls.Server(&bufferedConn{Conn: conn, reader: rw.Reader}, h.Config)
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return err
+	}
+
+	state := tlsConn.ConnectionState()
+	r.TLS = &state
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	r.Header.Del("Upgrade")
+	r.Header.Del("Connection")
+
+	resp := newBufferedResponse()
+	if r.Method == http.MethodOptions {
+		resp.Header().Set("Allow", http.MethodPost)
+		resp.WriteHeader(http.StatusOK)
+	} else {
+		h.Handler.ServeHTTP(resp, r)
+	}
+
+	if err := resp.writeTo(tlsConn, r); err != nil {
+		tlsConn.Close()
+		return err
+	}
+
+	l := newConnListener(tlsConn)
+	server := &http.Server{
+		Handler: h.Handler,
+		ConnState: func(conn net.Conn, state http.ConnState) {
+			if state == http.StateClosed || state == http.StateHijacked {
+				l.Close()
+			}
+		},
+	}
+
+	return server.Serve(l)
+}
+
+// isTLSUpgrade checks if the request asks for an upgrade to tls
+func isTLSUpgrade(r *http.Request) bool {
+	if !headerContainsToken(r.Header, "Connection", "upgrade") {
+		return false
+	}
+
+	for _, value := range r.Header["Upgrade"] {
+		for _, protocol := range strings.Split(value, ",") {
+			if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(protocol)), "TLS/") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func headerContainsToken(header http.Header, name, token string) bool {
+	for _, value := range header[http.CanonicalHeaderKey(name)] {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// bufferedConn reads the data already buffered by the http server before reading from the connection
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// connListener is a net.Listener which accepts a single connection, Accept blocks after the connection is returned
+// until the listener is closed
+type connListener struct {
+	conn   net.Conn
+	once   sync.Once
+	closed chan struct{}
+}
+
+func newConnListener(conn net.Conn) *connListener {
+	return &connListener{conn: conn, closed: make(chan struct{})}
+}
+
+func (l *connListener) Accept() (net.Conn, error) {
+	if conn := l.conn; conn != nil {
+		l.conn = nil
+		return conn, nil
+	}
+
+	<-l.closed
+	return nil, io.EOF
+}
+
+func (l *connListener) Close() error {
+	l.once.Do(func() {
+		close(l.closed)
+	})
+	return nil
+}
+
+func (l *connListener) Addr() net.Addr {
+	return &net.TCPAddr{}
+}
+
+// bufferedResponse collects the response of a handler which is written after a tls upgrade
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: make(http.Header)}
+}
+
+func (r *bufferedResponse) Header() http.Header {
+	return r.header
+}
+
+func (r *bufferedResponse) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+
+	return r.body.Write(b)
+}
+
+func (r *bufferedResponse) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *bufferedResponse) writeTo(w io.Writer, req *http.Request) error {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+
+	r.header.Set("Content-Length", strconv.Itoa(r.body.Len()))
+
+	resp := &http.Response{
+		Status:        strconv.Itoa(r.status) + " " + http.StatusText(r.status),
+		StatusCode:    r.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.header,
+		Body:          ioutil.NopCloser(&r.body),
+		ContentLength: int64(r.body.Len()),
+		Request:       req,
+	}
+
+	return resp.Write(w)
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfSignedCertificate(t *testing.T) {
+	cert, err := SelfSignedCertificate("urn:uuid:3f6ca2c4-0b5e-4b7e-8f5a-1b2c3d4e5f60", "printer.local", "127.0.0.1")
+	assert.Nil(t, err)
+
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	assert.Nil(t, err)
+	assert.Equal(t, "printer.local", parsed.Subject.CommonName)
+	assert.Equal(t, []string{"printer.local"}, parsed.DNSNames)
+	assert.Len(t, parsed.IPAddresses, 1)
+	assert.Equal(t, "urn:uuid:3f6ca2c4-0b5e-4b7e-8f5a-1b2c3d4e5f60", parsed.URIs[0].String())
+
+	other, err := SelfSignedCertificate("3f6ca2c4-0b5e-4b7e-8f5a-1b2c3d4e5f60")
+	assert.Nil(t, err)
+	assert.Equal(t, parsed.SerialNumber, other.Leaf.SerialNumber)
+}
+
+func TestLoadOrCreateCertificate(t *testing.T) {
+	dir := t.TempDir()
+	printer := NewVirtualPrinter("test", nil)
+
+	cert, err := printer.Certificate(dir, "localhost")
+	assert.Nil(t, err)
+
+	loaded, err := printer.Certificate(dir, "localhost")
+	assert.Nil(t, err)
+	assert.Equal(t, cert.Certificate, loaded.Certificate)
+
+	other, err := NewVirtualPrinter("other", nil).Certificate(dir, "localhost")
+	assert.Nil(t, err)
+	assert.NotEqual(t, cert.Certificate, other.Certificate)
+}
+
+func TestUpgradeHandler(t *testing.T) {
+	cert, err := SelfSignedCertificate(newUUID(), "localhost")
+	assert.Nil(t, err)
+
+	printer := NewVirtualPrinter("test", nil)
+	s := NewServer()
+	printer.Register(s, "/ipp/print")
+
+	ts := httptest.NewUnstartedServer(nil)
+	ts.Config = NewUpgradeHandler(s, TLSConfig(cert)).HTTPServer("")
+	ts.Start()
+	defer ts.Close()
+
+	conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("OPTIONS * HTTP/1.1\r\nHost: localhost\r\nConnection: Upgrade\r\nUpgrade: TLS/1.2,TLS/1.1,TLS/1.0\r\n\r\n"))
+	assert.Nil(t, err)
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+	tlsConn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true})
+	assert.Nil(t, tlsConn.Handshake())
+
+	tlsReader := bufio.NewReader(tlsConn)
+	resp, err = http.ReadResponse(tlsReader, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	payload, err := newPrinterRequest(ipp.OperationGetPrinterAttributes, "ipps://localhost/ipp/print").Encode()
+	assert.Nil(t, err)
+
+	r, err := http.NewRequest(http.MethodPost, "https://localhost/ipp/print", bytes.NewReader(payload))
+	assert.Nil(t, err)
+	r.Header.Set("Content-Type", ipp.ContentTypeIPP)
+	assert.Nil(t, r.Write(tlsConn))
+
+	resp, err = http.ReadResponse(tlsReader, r)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	ippResp, err := ipp.NewResponseDecoder(resp.Body).Decode(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "ipps://localhost/ipp/print", ippResp.PrinterAttributes[0][ipp.AttributePrinterUriSupported][0].Value)
+	assert.Equal(t, "tls", ippResp.PrinterAttributes[0][ipp.AttributeUriSecuritySupported][0].Value)
+}
+
+func TestUpgradeHandler_RequireTLS(t *testing.T) {
+	h := NewUpgradeHandler(NewServer(), TLSConfig())
+	h.RequireTLS = true
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/ipp/print", nil))
+	assert.Equal(t, http.StatusUpgradeRequired, rec.Code)
+	assert.Equal(t, "TLS/1.2, HTTP/1.1", rec.Header().Get("Upgrade"))
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// SupportedCharsets are the values of attributes-charset accepted by the server
+var SupportedCharsets = []string{"utf-8", "us-ascii"}
+
+var (
+	printerTarget      = []string{ipp.AttributePrinterURI}
+	jobTarget          = []string{ipp.AttributePrinterURI, ipp.AttributeJobID}
+	subscriptionTarget = []string{ipp.AttributePrinterURI, ipp.AttributeNotifySubscriptionID}
+	// Create-Job-Subscriptions is sent to the printer and names the job with notify-job-id, see rfc 3995
+	jobSubscriptionTarget = []string{ipp.AttributePrinterURI, ipp.AttributeNotifyJobID}
+)
+
+// RequiredOperationAttributes maps operations to the operation attributes a request must contain, besides
+// attributes-charset and attributes-natural-language. for job operations a job-uri can be used instead of printer-uri and job-id
+var RequiredOperationAttributes = map[int16][]string{
+	ipp.OperationPrintJob:                   printerTarget,
+	ipp.OperationPrintUri:                   {ipp.AttributePrinterURI, ipp.AttributeDocumentURI},
+	ipp.OperationValidateJob:                printerTarget,
+	ipp.OperationCreateJob:                  printerTarget,
+	ipp.OperationSendDocument:               append(jobTarget, ipp.AttributeLastDocument),
+	ipp.OperationSendUri:                    append(jobTarget, ipp.AttributeLastDocument, ipp.AttributeDocumentURI),
+	ipp.OperationCancelJob:                  jobTarget,
+	ipp.OperationGetJobAttributes:           jobTarget,
+	ipp.OperationGetJobs:                    printerTarget,
+	ipp.OperationGetPrinterAttributes:       printerTarget,
+	ipp.OperationHoldJob:                    jobTarget,
+	ipp.OperationReleaseJob:                 jobTarget,
+	ipp.OperationRestartJob:                 jobTarget,
+	ipp.OperationPausePrinter:               printerTarget,
+	ipp.OperationResumePrinter:              printerTarget,
+	ipp.OperationPurgeJobs:                  printerTarget,
+	ipp.OperationSetPrinterAttributes:       printerTarget,
+	ipp.OperationSetJobAttributes:           jobTarget,
+	ipp.OperationCreatePrinterSubscriptions: printerTarget,
+	ipp.OperationCreateJobSubscriptions:     jobSubscriptionTarget,
+	ipp.OperationGetSubscriptionAttributes:  subscriptionTarget,
+	ipp.OperationGetSubscriptions:           printerTarget,
+	ipp.OperationRenewSubscription:          subscriptionTarget,
+	ipp.OperationCancelSubscription:         subscriptionTarget,
+	ipp.OperationGetNotifications:           printerTarget,
+	ipp.OperationCancelJobs:                 printerTarget,
+	ipp.OperationCancelMyJobs:               printerTarget,
+	ipp.OperationCloseJob:                   jobTarget,
+	ipp.OperationIdentifyPrinter:            printerTarget,
+	ipp.OperationResubmitJob:                jobTarget,
+}
+
+// validationError defines a failed request validation with the status code and unsupported attributes to respond with
+type validationError struct {
+	status      int16
+	message     string
+	unsupported ipp.Attributes
+}
+
+// validateRequest checks the request id, the presence and values of attributes-charset and attributes-natural-language
+// and the required operation attributes
+func validateRequest(req *Request) *validationError {
+	if req.RequestId <= 0 {
+		return &validationError{status: ipp.StatusErrorBadRequest, message: "request-id must be greater than zero"}
+	}
+
+	charset, ok := req.OperationAttributes[ipp.AttributeCharset]
+	if !ok {
+		return &validationError{status: ipp.StatusErrorBadRequest, message: "missing attributes-charset"}
+	}
+
+	charsetValue, ok := charset.(string)
+	if !ok {
+		return &validationError{
+			status:      ipp.StatusErrorBadRequest,
+			message:     "attributes-charset must have exactly one value",
+			unsupported: unsupportedAttribute(ipp.AttributeCharset, ipp.TagCharset, charset),
+		}
+	}
+
+	if !isCharsetSupported(charsetValue) {
+		return &validationError{
+			status:      ipp.StatusErrorCharset,
+			message:     fmt.Sprintf("charset %s is not supported", charsetValue),
+			unsupported: unsupportedAttribute(ipp.AttributeCharset, ipp.TagCharset, charsetValue),
+		}
+	}
+
+	language, ok := req.OperationAttributes[ipp.AttributeNaturalLanguage]
+	if !ok {
+		return &validationError{status: ipp.StatusErrorBadRequest, message: "missing attributes-natural-language"}
+	}
+
+	if _, ok := language.(string); !ok {
+		return &validationError{
+			status:      ipp.StatusErrorBadRequest,
+			message:     "attributes-natural-language must have exactly one value",
+			unsupported: unsupportedAttribute(ipp.AttributeNaturalLanguage, ipp.TagLanguage, language),
+		}
+	}
+
+	required, ok := RequiredOperationAttributes[req.Operation]
+	if !ok {
+		return nil
+	}
+
+	// a job-uri replaces printer-uri and job-id only for operations targeting a job
+	_, hasJobURI := req.OperationAttributes[ipp.AttributeJobURI]
+	hasJobURI = hasJobURI && slices.Contains(required, ipp.AttributeJobID)
+
+	for _, name := range required {
+		if _, ok := req.OperationAttributes[name]; ok {
+			continue
+		}
+
+		if hasJobURI && (name == ipp.AttributePrinterURI || name == ipp.AttributeJobID) {
+			continue
+		}
+
+		return &validationError{status: ipp.StatusErrorBadRequest, message: fmt.Sprintf("missing required attribute %s", name)}
+	}
+
+	return nil
+}
+
+func isCharsetSupported(charset string) bool {
+	for _, supported := range SupportedCharsets {
+		if strings.EqualFold(supported, charset) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func unsupportedAttribute(name string, tag int8, value interface{}) ipp.Attributes {
+	return ipp.Attributes{
+		name: []ipp.Attribute{{Tag: tag, Name: name, Value: value}},
+	}
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Validation(t *testing.T) {
+	s := NewServer()
+	s.HandleFunc(ipp.OperationGetJobAttributes, func(req *Request) (*ipp.Response, error) {
+		return nil, nil
+	})
+
+	req := ipp.NewRequest(ipp.OperationGetJobAttributes, 1)
+	req.OperationAttributes[ipp.AttributeJobURI] = "ipp://localhost/jobs/1"
+	resp := serveTestRequest(t, s, "/", req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+
+	req = newPrinterRequest(ipp.OperationGetJobAttributes, "ipp://localhost/")
+	resp = serveTestRequest(t, s, "/", req)
+	assert.Equal(t, ipp.StatusErrorBadRequest, resp.StatusCode)
+	assert.Equal(t, "missing required attribute job-id", resp.OperationAttributes[ipp.AttributeStatusMessage][0].Value)
+
+	req = ipp.NewRequest(ipp.OperationGetJobAttributes, 0)
+	req.OperationAttributes[ipp.AttributeJobURI] = "ipp://localhost/jobs/1"
+	resp = serveTestRequest(t, s, "/", req)
+	assert.Equal(t, ipp.StatusErrorBadRequest, resp.StatusCode)
+
+	req = ipp.NewRequest(ipp.OperationGetJobAttributes, 1)
+	req.OperationAttributes[ipp.AttributeJobURI] = "ipp://localhost/jobs/1"
+	req.OperationAttributes[ipp.AttributeCharset] = "iso-8859-15"
+	resp = serveTestRequest(t, s, "/", req)
+	assert.Equal(t, ipp.StatusErrorCharset, resp.StatusCode)
+	assert.Equal(t, "iso-8859-15", resp.UnsupportedAttributes[ipp.AttributeCharset][0].Value)
+
+	// Create-Job-Subscriptions names the job with notify-job-id instead of job-id
+	s.HandleFunc(ipp.OperationCreateJobSubscriptions, func(req *Request) (*ipp.Response, error) {
+		return nil, nil
+	})
+	req = newPrinterRequest(ipp.OperationCreateJobSubscriptions, "ipp://localhost/")
+	req.OperationAttributes[ipp.AttributeNotifyJobID] = 1
+	resp = serveTestRequest(t, s, "/", req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+
+	req = newPrinterRequest(ipp.OperationCreateJobSubscriptions, "ipp://localhost/")
+	req.OperationAttributes[ipp.AttributeJobID] = 1
+	resp = serveTestRequest(t, s, "/", req)
+	assert.Equal(t, ipp.StatusErrorBadRequest, resp.StatusCode)
+	assert.Equal(t, "missing required attribute notify-job-id",
+		resp.OperationAttributes[ipp.AttributeStatusMessage][0].Value)
+
+	s.DisableValidation = true
+	req = ipp.NewRequest(ipp.OperationGetJobAttributes, 1)
+	resp = serveTestRequest(t, s, "/", req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+}
@@ -0,0 +1,1007 @@
+package server
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phin1x/go-ipp"
+)
+
+// VirtualPrinter implements an ipp everywhere printer which passes received documents to a DocumentSink
+type VirtualPrinter struct {
+	// Attributes contains the static printer description attributes, dynamic attributes like printer-state are added on request
+	Attributes ipp.Attributes
+	// DocumentFormats are the accepted document formats, the first format is the default
+	DocumentFormats []string
+	// Sink receives the document data of all jobs, if nil the data is discarded
+	Sink DocumentSink
+	// OnIdentify is called for Identify-Printer operations
+	OnIdentify func(actions []string, message string)
+	// Jobs stores the jobs of the printer
+	Jobs JobStore
+	// State manages the printer-state and printer-state-reasons of the printer
+	State *PrinterState
+	// Subscriptions stores the subscriptions and queues the notification events of the printer
+	Subscriptions *SubscriptionManager
+	// Pusher delivers the events of subscriptions with a notify-recipient-uri, if nil the events can only be pulled
+	// with Get-Notifications. it is not set by default, since the printer sends requests to the uris of the clients
+	Pusher *NotificationPusher
+	// MaxActiveJobs limits the number of pending and processing jobs, new jobs are rejected with server-error-busy.
+	// zero means unlimited
+	MaxActiveJobs int
+	// Scheduler enables job-priority and job-hold-until by queueing completely received jobs and passing them to the
+	// Sink in the order it selects. if nil, documents are passed to the Sink while they are received
+	Scheduler Scheduler
+	// Banner generates banner pages for jobs requesting a job sheet with the job-sheets attribute, if nil job-sheets
+	// is not supported
+	Banner *Banner
+	// Accounting receives an accounting record for each terminated job
+	Accounting AccountingHook
+	// Quota limits the usage of the users, jobs of users who exceeded their quota are rejected
+	Quota *Quota
+	// SpoolDirectory stores the documents of queued jobs, defaults to a directory of the printer in the temp directory
+	SpoolDirectory string
+	// Archive retains the documents of the jobs for CUPS-Get-Document and Resubmit-Job, documents are not retained
+	// if nil
+	Archive *DocumentArchive
+
+	queue     jobQueue
+	strings   printerStrings
+	icons     printerIcons
+	name      string
+	uuid      string
+	startTime time.Time
+}
+
+// VirtualPrinterOperations are the operations supported by a VirtualPrinter
+var VirtualPrinterOperations = []int16{
+	ipp.OperationPrintJob,
+	ipp.OperationValidateJob,
+	ipp.OperationCreateJob,
+	ipp.OperationSendDocument,
+	ipp.OperationCancelJob,
+	ipp.OperationGetJobAttributes,
+	ipp.OperationGetJobs,
+	ipp.OperationGetPrinterAttributes,
+	ipp.OperationIdentifyPrinter,
+	ipp.OperationCreatePrinterSubscriptions,
+	ipp.OperationCreateJobSubscriptions,
+	ipp.OperationGetSubscriptionAttributes,
+	ipp.OperationGetSubscriptions,
+	ipp.OperationRenewSubscription,
+	ipp.OperationCancelSubscription,
+	ipp.OperationGetNotifications,
+}
+
+// NewVirtualPrinter creates a new virtual printer with a basic set of printer description attributes. documents are
+// passed to the handler, use the Sink field to receive the full document metadata
+func NewVirtualPrinter(name string, handler DocumentHandler) *VirtualPrinter {
+	p := &VirtualPrinter{
+		Attributes:      make(ipp.Attributes),
+		DocumentFormats: []string{"application/pdf", "image/pwg-raster", "image/jpeg", ipp.MimeTypeOctetStream},
+		Jobs:            NewMemoryJobStore(),
+		State:           NewPrinterState(),
+		Subscriptions:   NewSubscriptionManager(),
+		name:            name,
+		uuid:            newUUID(),
+		startTime:       time.Now(),
+	}
+
+	if handler != nil {
+		p.Sink = handler
+	}
+
+	p.State.OnChange(p.publishPrinterEvent)
+	p.Subscriptions.OnEvent(p.pushEvent)
+	p.State.OnChange(func(status PrinterStatus) {
+		p.schedule()
+	})
+
+	p.Attributes.Set(ipp.AttributePrinterName, ipp.TagName, name)
+	p.Attributes.Set(ipp.AttributePrinterInfo, ipp.TagText, name)
+	p.Attributes.Set(ipp.AttributePrinterMakeAndModel, ipp.TagText, "Virtual Printer")
+	p.Attributes.Set(ipp.AttributePrinterLocation, ipp.TagText, "")
+	p.Attributes.Set(ipp.AttributeCharsetConfigured, ipp.TagCharset, ipp.Charset)
+	p.Attributes.Set(ipp.AttributeCharsetSupported, ipp.TagCharset, ipp.Charset, "us-ascii")
+	p.Attributes.Set(ipp.AttributeNaturalLanguageConfigured, ipp.TagLanguage, "en")
+	p.Attributes.Set(ipp.AttributeGeneratedNaturalLanguageSupported, ipp.TagLanguage, "en")
+	p.Attributes.Set(ipp.AttributeIppVersionsSupported, ipp.TagKeyword, "1.1", "2.0")
+	p.Attributes.Set(ipp.AttributeCompressionSupported, ipp.TagKeyword, "none")
+	p.Attributes.Set(ipp.AttributePdlOverrideSupported, ipp.TagKeyword, "attempted")
+	p.Attributes.Set(ipp.AttributeIdentifyActionsSupported, ipp.TagKeyword, "display", "sound")
+
+	return p
+}
+
+// Name returns the name of the printer
+func (p *VirtualPrinter) Name() string {
+	return p.name
+}
+
+// UUID returns the uuid of the printer in the urn:uuid form
+func (p *VirtualPrinter) UUID() string {
+	return p.uuid
+}
+
+// Register registers the operation handlers of the printer on the endpoint with the given path
+func (p *VirtualPrinter) Register(s *Server, path string) *Endpoint {
+	e := s.Endpoint(path)
+
+	e.HandleFunc(ipp.OperationPrintJob, p.printJob)
+	e.HandleFunc(ipp.OperationValidateJob, p.validateJob)
+	e.HandleFunc(ipp.OperationCreateJob, p.createJob)
+	e.HandleFunc(ipp.OperationSendDocument, p.sendDocument)
+	e.HandleFunc(ipp.OperationCancelJob, p.cancelJob)
+	e.HandleFunc(ipp.OperationGetJobAttributes, p.getJobAttributes)
+	e.HandleFunc(ipp.OperationGetJobs, p.getJobs)
+	e.HandleFunc(ipp.OperationGetPrinterAttributes, p.getPrinterAttributes)
+	e.HandleFunc(ipp.OperationIdentifyPrinter, p.identifyPrinter)
+	e.HandleFunc(ipp.OperationHoldJob, p.holdJob)
+	e.HandleFunc(ipp.OperationReleaseJob, p.releaseJob)
+	e.HandleFunc(ipp.OperationResubmitJob, p.resubmitJob)
+	e.HandleFunc(ipp.OperationCupsGetDocument, p.cupsGetDocument)
+	e.HandleFunc(ipp.OperationCreatePrinterSubscriptions, p.createPrinterSubscriptions)
+	e.HandleFunc(ipp.OperationCreateJobSubscriptions, p.createJobSubscriptions)
+	e.HandleFunc(ipp.OperationGetSubscriptionAttributes, p.getSubscriptionAttributes)
+	e.HandleFunc(ipp.OperationGetSubscriptions, p.getSubscriptions)
+	e.HandleFunc(ipp.OperationRenewSubscription, p.renewSubscription)
+	e.HandleFunc(ipp.OperationCancelSubscription, p.cancelSubscription)
+	e.HandleFunc(ipp.OperationGetNotifications, p.getNotifications)
+	e.HandleResource("strings", http.HandlerFunc(p.serveStrings))
+	e.HandleResource("icons", http.HandlerFunc(p.serveIcon))
+	e.onStop(p.stopAccepting)
+	e.OnShutdown(p.drain)
+
+	return e
+}
+
+// Job returns a copy of the job with the given id
+func (p *VirtualPrinter) Job(id int) (*Job, bool) {
+	job, err := p.Jobs.Get(id)
+	if err != nil {
+		return nil, false
+	}
+
+	return job, true
+}
+
+func (p *VirtualPrinter) printJob(req *Request) (*ipp.Response, error) {
+	format, ok := p.documentFormat(req)
+	if !ok {
+		return DocumentFormatNotSupported(req, format), nil
+	}
+
+	job, subscription, err := p.newJob(req, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.receiveDocument(req, job.ID, format, true); err != nil {
+		return nil, err
+	}
+
+	return p.jobResponse(req, job.ID, subscription), nil
+}
+
+func (p *VirtualPrinter) validateJob(req *Request) (*ipp.Response, error) {
+	format, ok := p.documentFormat(req)
+	if !ok {
+		return DocumentFormatNotSupported(req, format), nil
+	}
+
+	return OK(req), nil
+}
+
+func (p *VirtualPrinter) createJob(req *Request) (*ipp.Response, error) {
+	job, subscription, err := p.newJob(req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return p.jobResponse(req, job.ID, subscription), nil
+}
+
+func (p *VirtualPrinter) sendDocument(req *Request) (*ipp.Response, error) {
+	job, resp := p.lookupJob(req)
+	if resp != nil {
+		return resp, nil
+	}
+
+	if job.IsTerminated() || job.State == ipp.JobStateProcessing {
+		return Error(req, ipp.StatusErrorNotPossible, "job does not accept further documents"), nil
+	}
+
+	format, ok := p.documentFormat(req)
+	if !ok {
+		return DocumentFormatNotSupported(req, format), nil
+	}
+
+	lastDocument, _ := req.OperationAttributes[ipp.AttributeLastDocument].(bool)
+
+	if err := p.receiveDocument(req, job.ID, format, lastDocument); err != nil {
+		return nil, err
+	}
+
+	return p.jobResponse(req, job.ID, nil), nil
+}
+
+func (p *VirtualPrinter) cancelJob(req *Request) (*ipp.Response, error) {
+	job, resp := p.lookupJob(req)
+	if resp != nil {
+		return resp, nil
+	}
+
+	job, err := p.Jobs.Update(job.ID, func(job *Job) error {
+		if job.IsTerminated() {
+			return ipp.StatusError{
+				Status:  ipp.StatusErrorNotPossible,
+				Message: fmt.Sprintf("job %d is already terminated", job.ID),
+			}
+		}
+
+		job.SetState(ipp.JobStateCanceled, "job-canceled-by-user")
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Scheduler != nil && job.State != ipp.JobStateProcessing {
+		p.removeSpool(job)
+	}
+
+	p.finishJob(job)
+
+	return OK(req), nil
+}
+
+func (p *VirtualPrinter) getJobAttributes(req *Request) (*ipp.Response, error) {
+	job, resp := p.lookupJob(req)
+	if resp != nil {
+		return resp, nil
+	}
+
+	requested := RequestedAttributes(req, RequestedAll)
+
+	return NewResponseBuilder(req).JobAttributes(FilterJobAttributes(p.jobAttributes(req, job), requested)).Build(), nil
+}
+
+func (p *VirtualPrinter) getJobs(req *Request) (*ipp.Response, error) {
+	whichJobs, _ := req.OperationAttributes[ipp.AttributeWhichJobs].(string)
+	if whichJobs == "" {
+		whichJobs = ipp.JobStateFilterNotCompleted
+	}
+
+	if whichJobs != ipp.JobStateFilterNotCompleted && whichJobs != ipp.JobStateFilterCompleted && whichJobs != ipp.JobStateFilterAll {
+		return NewResponseBuilder(req).
+			Status(ipp.StatusErrorAttributesOrValues).
+			Unsupported(ipp.AttributeWhichJobs, ipp.TagKeyword, whichJobs).
+			Build(), nil
+	}
+
+	myJobs, _ := req.OperationAttributes[ipp.AttributeMyJobs].(bool)
+	user := req.UserName()
+	limit, _ := req.OperationAttributes[ipp.AttributeLimit].(int)
+
+	stored, err := p.Jobs.List()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*Job, 0, len(stored))
+	for _, job := range stored {
+		if whichJobs == ipp.JobStateFilterNotCompleted && job.IsTerminated() {
+			continue
+		}
+
+		if whichJobs == ipp.JobStateFilterCompleted && !job.IsTerminated() {
+			continue
+		}
+
+		if myJobs && job.OriginatingUser != user {
+			continue
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	if limit > 0 && len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+
+	requested := RequestedAttributes(req, ipp.AttributeJobURI, ipp.AttributeJobID)
+
+	b := NewResponseBuilder(req)
+	for _, job := range jobs {
+		b.JobAttributes(FilterJobAttributes(p.jobAttributes(req, job), requested))
+	}
+
+	return b.Build(), nil
+}
+
+func (p *VirtualPrinter) getPrinterAttributes(req *Request) (*ipp.Response, error) {
+	requested := RequestedAttributes(req, RequestedAll)
+
+	return NewResponseBuilder(req).PrinterAttributes(FilterPrinterAttributes(p.printerAttributes(req), requested)).Build(), nil
+}
+
+func (p *VirtualPrinter) identifyPrinter(req *Request) (*ipp.Response, error) {
+	if p.OnIdentify != nil {
+		actions := stringValues(req.OperationAttributes[ipp.AttributeIdentifyActions])
+		if len(actions) == 0 {
+			actions = []string{"sound"}
+		}
+		message, _ := req.OperationAttributes[ipp.AttributeMessage].(string)
+
+		p.OnIdentify(actions, message)
+	}
+
+	return OK(req), nil
+}
+
+// documentFormat returns the requested document format and whether it is supported
+func (p *VirtualPrinter) documentFormat(req *Request) (string, bool) {
+	format, _ := req.OperationAttributes[ipp.AttributeDocumentFormat].(string)
+	if format == "" {
+		if len(p.DocumentFormats) > 0 {
+			return p.DocumentFormats[0], true
+		}
+		return ipp.MimeTypeOctetStream, true
+	}
+
+	for _, supported := range p.DocumentFormats {
+		if strings.EqualFold(supported, format) {
+			return format, true
+		}
+	}
+
+	return format, false
+}
+
+// newJob creates and stores a job for a job creation request. if the request contains subscription template
+// attributes, a job subscription is created and its attributes group is returned
+func (p *VirtualPrinter) newJob(req *Request, format string) (*Job, ipp.Attributes, error) {
+	if !p.State.IsAcceptingJobs() {
+		return nil, nil, ipp.StatusError{Status: ipp.StatusErrorNotAcceptingJobs, Message: "printer is not accepting jobs"}
+	}
+
+	if p.MaxActiveJobs > 0 {
+		active, err := p.activeJobs()
+		if err != nil {
+			return nil, nil, err
+		}
+		if active >= p.MaxActiveJobs {
+			return nil, nil, ipp.StatusError{Status: ipp.StatusErrorBusy, Message: "too many active jobs"}
+		}
+	}
+
+	if p.Scheduler != nil {
+		if err := validateScheduling(req); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if p.Banner != nil {
+		if err := validateJobSheets(req); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	name, _ := req.OperationAttributes[ipp.AttributeJobName].(string)
+	user := req.UserName()
+
+	if name == "" {
+		name = "Untitled"
+	}
+
+	if user == "" {
+		user = "anonymous"
+	}
+
+	if p.Quota != nil {
+		if err := p.validateQuota(req, user); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	job := &Job{
+		UUID:            newUUID(),
+		Name:            name,
+		OriginatingUser: user,
+		DocumentFormat:  format,
+		Attributes:      toAttributes(req, req.JobAttributes),
+		CreatedAt:       time.Now(),
+	}
+	job.SetState(ipp.JobStatePending, "job-incoming")
+
+	if err := p.Jobs.Create(job); err != nil {
+		return nil, nil, err
+	}
+
+	var subscription ipp.Attributes
+	if len(req.SubscriptionAttributes) > 0 {
+		var err error
+		if subscription, err = p.subscribe(req, job.ID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	p.publishJobEvent(ipp.EventJobCreated, job)
+
+	return job, subscription, nil
+}
+
+// receiveDocument streams the document data of the request to the sink and updates the job. with a scheduler, the
+// data is spooled and the job is queued after the last document
+func (p *VirtualPrinter) receiveDocument(req *Request, jobID int, format string, lastDocument bool) error {
+	if p.Scheduler != nil {
+		return p.spoolDocument(req, jobID, format, lastDocument)
+	}
+
+	job, err := p.Jobs.Update(jobID, func(job *Job) error {
+		job.SetState(ipp.JobStateProcessing, "job-printing")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	p.publishJobEvent(ipp.EventJobStateChanged, job)
+
+	p.State.StartProcessing()
+	defer p.State.FinishProcessing()
+
+	// sinks count impressions on the passed job, the count is added to the stored job
+	impressions := job.Impressions
+
+	// the banner page is printed before the first document, the document is discarded if the banner failed
+	var bannerErr error
+	sink := p.Sink
+	if len(job.Documents) == 0 {
+		if bannerErr = p.writeBanner(job); bannerErr != nil {
+			sink = nil
+		}
+	}
+
+	doc, handlerErr := p.writeDocument(req, job, format, sink)
+	if bannerErr != nil {
+		handlerErr = bannerErr
+	}
+	if err := p.Jobs.AddDocument(jobID, doc); err != nil {
+		return err
+	}
+
+	impressions = job.Impressions - impressions
+	canceled := false
+	job, err = p.Jobs.Update(jobID, func(job *Job) error {
+		job.Impressions += impressions
+		switch {
+		case job.State == ipp.JobStateCanceled:
+			// the job was finished by cancel-job
+			canceled = true
+		case handlerErr != nil:
+			job.StateMessage = handlerErr.Error()
+			job.SetState(ipp.JobStateAborted, "aborted-by-system")
+		case lastDocument:
+			job.SetState(ipp.JobStateCompleted, "job-completed-successfully")
+		default:
+			job.SetState(ipp.JobStatePending, "job-incoming")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if job.IsTerminated() {
+		if !canceled {
+			p.finishJob(job)
+		}
+	} else {
+		p.publishJobEvent(ipp.EventJobStateChanged, job)
+	}
+
+	return statusError(handlerErr)
+}
+
+// spoolDocument stores the document data of the request in the spool and queues the job after the last document
+func (p *VirtualPrinter) spoolDocument(req *Request, jobID int, format string, lastDocument bool) error {
+	job, err := p.Jobs.Get(jobID)
+	if err != nil {
+		return err
+	}
+
+	spool, err := p.spool()
+	if err != nil {
+		return err
+	}
+
+	doc, spoolErr := p.writeDocument(req, job, format, spool)
+	if err := p.Jobs.AddDocument(jobID, doc); err != nil {
+		return err
+	}
+
+	canceled := false
+	job, err = p.Jobs.Update(jobID, func(job *Job) error {
+		switch {
+		case job.State == ipp.JobStateCanceled:
+			// the job was finished by cancel-job
+			canceled = true
+		case spoolErr != nil:
+			job.StateMessage = spoolErr.Error()
+			job.SetState(ipp.JobStateAborted, "aborted-by-system")
+		case lastDocument:
+			queueJob(job, time.Now())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if job.IsTerminated() {
+		p.removeSpool(job)
+		if !canceled {
+			p.finishJob(job)
+		}
+	} else {
+		p.publishJobEvent(ipp.EventJobStateChanged, job)
+	}
+
+	p.schedule()
+
+	return statusError(spoolErr)
+}
+
+// writeDocument passes the document data of the request to the sink and returns the document metadata with the
+// number of received bytes. with an archive, the data is retained while it is passed to the sink
+func (p *VirtualPrinter) writeDocument(req *Request, job *Job, format string, sink DocumentSink) (Document, error) {
+	counter := &countingReader{reader: req.File}
+	if req.File == nil {
+		counter.reader = strings.NewReader("")
+	}
+
+	name, _ := req.OperationAttributes[ipp.AttributeDocumentName].(string)
+	if name == "" {
+		name, _ = req.OperationAttributes[ipp.AttributeJobName].(string)
+	}
+	doc := Document{Number: len(job.Documents) + 1, Name: name, Format: format}
+
+	// documents which can not be archived are printed without being retained
+	var archived *archiveFile
+	if p.Archive != nil {
+		if file, err := p.Archive.create(job, doc); err == nil {
+			archived = file
+			counter.reader = io.TeeReader(counter.reader, archived)
+		}
+	}
+
+	var err error
+	if sink != nil {
+		err = sink.WriteDocument(job, doc, counter)
+	}
+
+	// consume data the sink did not read
+	if _, copyErr := io.Copy(ioutil.Discard, counter); err == nil {
+		err = copyErr
+	}
+
+	if archived != nil {
+		if err == nil {
+			doc.Retained = archived.commit() == nil
+		} else {
+			archived.abort()
+		}
+	}
+
+	doc.Size = counter.n
+	return doc, err
+}
+
+// statusError returns errors with an ipp status, e.g. from the document size limit of the server, so they are
+// reported to the client
+func statusError(err error) error {
+	var ippErr ipp.StatusError
+	if errors.As(err, &ippErr) {
+		return ippErr
+	}
+
+	return nil
+}
+
+// activeJobs returns the number of jobs which are not terminated
+func (p *VirtualPrinter) activeJobs() (int, error) {
+	jobs, err := p.Jobs.List()
+	if err != nil {
+		return 0, err
+	}
+
+	active := 0
+	for _, job := range jobs {
+		if !job.IsTerminated() {
+			active++
+		}
+	}
+
+	return active, nil
+}
+
+// ownerOperations are the operations which modify a job or subscription of a user
+var ownerOperations = []int16{
+	ipp.OperationSendDocument,
+	ipp.OperationCancelJob,
+	ipp.OperationCreateJobSubscriptions,
+	ipp.OperationRenewSubscription,
+	ipp.OperationCancelSubscription,
+	ipp.OperationResubmitJob,
+	ipp.OperationCupsGetDocument,
+}
+
+// OwnerPolicy returns an Authorizer which allows operations modifying a job or subscription only for its owner and
+// members of the operator groups, e.g. to restrict Cancel-Job. the owner is the authenticated user or, for anonymous
+// requests, the requesting-user-name. set the policy on the endpoint of the printer with Endpoint.SetAuthorizer
+func (p *VirtualPrinter) OwnerPolicy(operators ...string) Authorizer {
+	return AuthorizerFunc(func(req *Request) error {
+		if !containsOperation(ownerOperations, req.Operation) {
+			return nil
+		}
+
+		if req.User != nil && req.User.InGroup(operators...) {
+			return nil
+		}
+
+		var owner string
+		switch req.Operation {
+		case ipp.OperationRenewSubscription, ipp.OperationCancelSubscription:
+			id, _ := req.OperationAttributes[ipp.AttributeNotifySubscriptionID].(int)
+			sub, err := p.Subscriptions.Get(id)
+			if err != nil {
+				// the handler responds with client-error-not-found
+				return nil
+			}
+			owner = sub.Owner
+		default:
+			id, ok := jobID(req)
+			if !ok {
+				return nil
+			}
+			job, ok := p.Job(id)
+			if !ok {
+				return nil
+			}
+			owner = job.OriginatingUser
+		}
+
+		if user := req.UserName(); user == "" || user != owner {
+			return AccessDeniedError
+		}
+
+		return nil
+	})
+}
+
+// lookupJob returns a copy of the job addressed by the job-id or job-uri operation attribute,
+// or a client-error-not-found response
+func (p *VirtualPrinter) lookupJob(req *Request) (*Job, *ipp.Response) {
+	id, ok := jobID(req)
+	if !ok {
+		return nil, Error(req, ipp.StatusErrorBadRequest, "missing job-id or job-uri")
+	}
+
+	job, ok := p.Job(id)
+	if !ok {
+		return nil, Error(req, ipp.StatusErrorNotFound, fmt.Sprintf("job %d does not exist", id))
+	}
+
+	return job, nil
+}
+
+func (p *VirtualPrinter) jobResponse(req *Request, id int, subscription ipp.Attributes) *ipp.Response {
+	job, _ := p.Job(id)
+
+	b := NewResponseBuilder(req).
+		Job(job.ID, p.jobURI(req, job.ID), job.State, job.StateReasons...)
+	if subscription != nil {
+		b.SubscriptionAttributes(subscription)
+	}
+
+	return b.Build()
+}
+
+// printerURI returns the uri of the printer as seen by the client of the request
+func (p *VirtualPrinter) printerURI(req *Request) string {
+	return requestPrinterURI(req)
+}
+
+// requestPrinterURI returns the uri of the endpoint of the request as seen by the client
+func requestPrinterURI(req *Request) string {
+	scheme := "ipp"
+	if req.HTTPRequest != nil && req.HTTPRequest.TLS != nil {
+		scheme = "ipps"
+	}
+
+	host := "localhost"
+	if req.HTTPRequest != nil && req.HTTPRequest.Host != "" {
+		host = req.HTTPRequest.Host
+	}
+
+	path := "/"
+	if req.Endpoint != nil {
+		path = req.Endpoint.Path()
+	}
+
+	return fmt.Sprintf("%s://%s%s", scheme, host, path)
+}
+
+// requestResourceURI returns the http uri of a resource below the endpoint of the request as seen by the client
+func requestResourceURI(req *Request, name string) string {
+	scheme := "http"
+	if req.HTTPRequest != nil && req.HTTPRequest.TLS != nil {
+		scheme = "https"
+	}
+
+	host := "localhost"
+	if req.HTTPRequest != nil && req.HTTPRequest.Host != "" {
+		host = req.HTTPRequest.Host
+	}
+
+	path := ""
+	if req.Endpoint != nil {
+		path = strings.TrimSuffix(req.Endpoint.Path(), "/")
+	}
+
+	return fmt.Sprintf("%s://%s%s/%s", scheme, host, path, name)
+}
+
+func (p *VirtualPrinter) jobURI(req *Request, id int) string {
+	return fmt.Sprintf("%s/%d", strings.TrimSuffix(p.printerURI(req), "/"), id)
+}
+
+// upTime converts a point in time into the printer up time in seconds
+func (p *VirtualPrinter) upTime(t time.Time) int {
+	return int(t.Sub(p.startTime)/time.Second) + 1
+}
+
+func (p *VirtualPrinter) printerAttributes(req *Request) ipp.Attributes {
+	attributes := make(ipp.Attributes, len(p.Attributes)+16)
+	for name, attr := range p.Attributes {
+		attributes[name] = attr
+	}
+
+	printerURI := p.printerURI(req)
+	security := "none"
+	if strings.HasPrefix(printerURI, "ipps:") {
+		security = "tls"
+	}
+
+	queued, _ := p.activeJobs()
+
+	operations := make([]interface{}, 0, len(VirtualPrinterOperations)+4)
+	for _, op := range VirtualPrinterOperations {
+		operations = append(operations, int(op))
+	}
+	if p.Scheduler != nil {
+		operations = append(operations, int(ipp.OperationHoldJob), int(ipp.OperationReleaseJob))
+	}
+	if p.Archive != nil {
+		operations = append(operations, int(ipp.OperationResubmitJob), int(ipp.OperationCupsGetDocument))
+	}
+
+	formats := make([]interface{}, len(p.DocumentFormats))
+	for i, format := range p.DocumentFormats {
+		formats[i] = format
+	}
+
+	attributes.Set(ipp.AttributePrinterUriSupported, ipp.TagUri, printerURI)
+	attributes.Set(ipp.AttributeUriSecuritySupported, ipp.TagKeyword, security)
+	attributes.Set(ipp.AttributeUriAuthenticationSupported, ipp.TagKeyword, "none")
+	attributes.Set(ipp.AttributePrinterUUID, ipp.TagUri, p.uuid)
+	for name, attr := range p.State.Attributes() {
+		attributes[name] = attr
+	}
+	now := time.Now()
+	attributes.Set(ipp.AttributePrinterUpTime, ipp.TagInteger, p.upTime(now))
+	attributes.Set(ipp.AttributePrinterCurrentTime, ipp.TagDate, ipp.EncodeDateTime(now))
+	attributes.Set(ipp.AttributeQueuedJobCount, ipp.TagInteger, queued)
+	attributes.Set(ipp.AttributeOperationsSupported, ipp.TagEnum, operations...)
+	attributes.Set(ipp.AttributeDocumentFormatSupported, ipp.TagMimeType, formats...)
+	if len(p.DocumentFormats) > 0 {
+		attributes.Set(ipp.AttributeDocumentFormatDefault, ipp.TagMimeType, p.DocumentFormats[0])
+	}
+
+	if p.Banner != nil {
+		sheets := p.Banner.Default
+		if sheets == "" {
+			sheets = JobSheetsNone
+		}
+		attributes.Set(ipp.AttributeJobSheetsDefault, ipp.TagKeyword, sheets)
+		attributes.Set(ipp.AttributeJobSheetsSupported, ipp.TagKeyword, toValues(JobSheetsSupported)...)
+	}
+
+	if p.Quota != nil {
+		p.setQuotaAttributes(req, attributes)
+	}
+
+	p.setStringsAttributes(req, attributes)
+	p.setIconAttributes(req, attributes)
+
+	if p.Scheduler != nil {
+		attributes.Set(ipp.AttributeJobHoldUntilDefault, ipp.TagKeyword, HoldUntilNoHold)
+		attributes.Set(ipp.AttributeJobHoldUntilSupported, ipp.TagKeyword, toValues(HoldUntilSupported)...)
+		attributes.Set(ipp.AttributeJobPriorityDefault, ipp.TagInteger, ipp.DefaultJobPriority)
+		attributes.Set(ipp.AttributeJobPrioritySupported, ipp.TagInteger, 100)
+	}
+
+	attributes.Set(ipp.AttributeNotifyEventsDefault, ipp.TagKeyword, ipp.EventJobCompleted)
+	attributes.Set(ipp.AttributeNotifyEventsSupported, ipp.TagKeyword, toValues(supportedEvents)...)
+	attributes.Set(ipp.AttributeNotifyPullMethodSupported, ipp.TagKeyword, ipp.PullMethodIppGet)
+	if p.Pusher != nil {
+		attributes.Set(ipp.AttributeNotifySchemesSupported, ipp.TagUriScheme, toValues(PushSchemes)...)
+	}
+	attributes.Set(ipp.AttributeNotifyLeaseDurationDefault, ipp.TagInteger, p.Subscriptions.DefaultLeaseDuration)
+	attributes.Set(ipp.AttributeNotifyLeaseDurationSupported, ipp.TagRange, []int32{0, MaxLeaseDuration})
+	attributes.Set(ipp.AttributeNotifyMaxEventsSupported, ipp.TagInteger, p.Subscriptions.maxEvents())
+
+	return attributes
+}
+
+func (p *VirtualPrinter) jobAttributes(req *Request, job *Job) ipp.Attributes {
+	attributes := make(ipp.Attributes, len(job.Attributes)+16)
+	for name, attr := range job.Attributes {
+		attributes[name] = attr
+	}
+
+	reasons := make([]interface{}, len(job.StateReasons))
+	for i, reason := range job.StateReasons {
+		reasons[i] = reason
+	}
+
+	attributes.Set(ipp.AttributeJobID, ipp.TagInteger, job.ID)
+	attributes.Set(ipp.AttributeJobURI, ipp.TagUri, p.jobURI(req, job.ID))
+	attributes.Set(ipp.AttributeJobUUID, ipp.TagUri, job.UUID)
+	attributes.Set(ipp.AttributeJobPrinterURI, ipp.TagUri, p.printerURI(req))
+	attributes.Set(ipp.AttributeJobName, ipp.TagName, job.Name)
+	attributes.Set(ipp.AttributeJobOriginatingUserName, ipp.TagName, job.OriginatingUser)
+	attributes.Set(ipp.AttributeJobState, ipp.TagEnum, int(job.State))
+	attributes.Set(ipp.AttributeJobStateReasons, ipp.TagKeyword, reasons...)
+	attributes.Set(ipp.AttributeJobKilobyteOctets, ipp.TagInteger, job.KOctets)
+	attributes.Set(ipp.AttributeNumberOfDocuments, ipp.TagInteger, job.NumberOfDocuments)
+	attributes.Set(ipp.AttributeJobImpressionsCompleted, ipp.TagInteger, job.Impressions)
+	attributes.Set(ipp.AttributeJobPrinterUpTime, ipp.TagInteger, p.upTime(time.Now()))
+	attributes.Set(ipp.AttributeTimeAtCreation, ipp.TagInteger, p.upTime(job.CreatedAt))
+	p.setTimeAttribute(attributes, ipp.AttributeTimeAtProcessing, job.ProcessingAt)
+	p.setTimeAttribute(attributes, ipp.AttributeTimeAtCompleted, job.CompletedAt)
+
+	if job.StateMessage != "" {
+		attributes.Set(ipp.AttributeJobStateMessage, ipp.TagText, job.StateMessage)
+	}
+
+	if job.DocumentFormat != "" {
+		attributes.Set(ipp.AttributeDocumentFormat, ipp.TagMimeType, job.DocumentFormat)
+	}
+
+	return attributes
+}
+
+// setTimeAttribute sets an up time attribute, times not reached yet are encoded as no-value
+func (p *VirtualPrinter) setTimeAttribute(attributes ipp.Attributes, name string, t time.Time) {
+	if t.IsZero() {
+		attributes.Set(name, ipp.TagNoValue, "")
+		return
+	}
+
+	attributes.Set(name, ipp.TagInteger, p.upTime(t))
+}
+
+// jobID returns the job id of the job-id or job-uri operation attribute
+func jobID(req *Request) (int, bool) {
+	if id, ok := req.OperationAttributes[ipp.AttributeJobID].(int); ok {
+		return id, true
+	}
+
+	uri, ok := req.OperationAttributes[ipp.AttributeJobURI].(string)
+	if !ok {
+		return 0, false
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return 0, false
+	}
+
+	id, err := strconv.Atoi(u.Path[strings.LastIndex(u.Path, "/")+1:])
+	if err != nil {
+		return 0, false
+	}
+
+	return id, true
+}
+
+// toAttributes converts attributes of a decoded request into tagged attributes. vendor attributes keep the tag they
+// were decoded with, attributes without tag are skipped
+func toAttributes(req *Request, values map[string]interface{}) ipp.Attributes {
+	attributes := make(ipp.Attributes, len(values))
+
+	for name, value := range values {
+		tag, ok := req.AttributeTag(name)
+		if !ok {
+			continue
+		}
+
+		switch v := value.(type) {
+		case []string:
+			for _, s := range v {
+				attributes.Add(name, tag, s)
+			}
+		case []int:
+			if tag == ipp.TagDate {
+				attributes.Add(name, tag, v)
+				continue
+			}
+			for _, i := range v {
+				attributes.Add(name, tag, i)
+			}
+		case []bool:
+			for _, b := range v {
+				attributes.Add(name, tag, b)
+			}
+		case []interface{}:
+			attributes.Add(name, tag, v...)
+		default:
+			attributes.Add(name, tag, v)
+		}
+	}
+
+	return attributes
+}
+
+// stringValues returns the values of a decoded keyword or string attribute
+func stringValues(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, s := range v {
+			if str, ok := s.(string); ok {
+				values = append(values, str)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
+
+// countingReader counts the bytes read from the underlying reader
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (r *countingReader) Read(b []byte) (int, error) {
+	n, err := r.reader.Read(b)
+	r.n += int64(n)
+	return n, err
+}
+
+// newUUID generates a random version 4 uuid in the urn:uuid form
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,231 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVirtualPrinter(t *testing.T) {
+	var received bytes.Buffer
+
+	printer := NewVirtualPrinter("test", func(job *Job, format string, document io.Reader) error {
+		if format == "image/jpeg" {
+			return errors.New("broken jpeg")
+		}
+		_, err := io.Copy(&received, document)
+		return err
+	})
+
+	s := NewServer()
+	printer.Register(s, "/printers/test")
+
+	client, closeServer := newTestClient(t, s)
+	defer closeServer()
+
+	attributes, err := client.GetPrinterAttributes("test", []string{RequestedAll})
+	assert.Nil(t, err)
+	assert.Equal(t, "test", attributes[ipp.AttributePrinterName][0].Value)
+	assert.Equal(t, int(ipp.PrinterStateIdle), attributes[ipp.AttributePrinterState][0].Value)
+	assert.Equal(t, printer.UUID(), attributes[ipp.AttributePrinterUUID][0].Value)
+	assert.Len(t, attributes[ipp.AttributeOperationsSupported], len(VirtualPrinterOperations))
+
+	doc := []byte("%PDF-1.7 test")
+	jobID, err := client.PrintJob(ipp.Document{
+		Document: bytes.NewReader(doc),
+		Size:     len(doc),
+		Name:     "report.pdf",
+		MimeType: "application/pdf",
+	}, "test", map[string]interface{}{ipp.AttributeSides: "two-sided-long-edge"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, jobID)
+	assert.Equal(t, doc, received.Bytes())
+
+	job, ok := printer.Job(jobID)
+	assert.True(t, ok)
+	assert.Equal(t, ipp.JobStateCompleted, job.State)
+	assert.Equal(t, "user", job.OriginatingUser)
+	assert.Equal(t, "two-sided-long-edge", job.Attributes[ipp.AttributeSides][0].Value)
+
+	_, err = client.PrintJob(ipp.Document{
+		Document: bytes.NewReader(doc),
+		Size:     len(doc),
+		Name:     "photo.tiff",
+		MimeType: "image/tiff",
+	}, "test", map[string]interface{}{})
+	var ippErr ipp.IPPError
+	assert.True(t, errors.As(err, &ippErr))
+	assert.Equal(t, ipp.StatusErrorDocumentFormatNotSupported, ippErr.Status)
+
+	jobID, err = client.PrintJob(ipp.Document{
+		Document: bytes.NewReader(doc),
+		Size:     len(doc),
+		Name:     "photo.jpg",
+		MimeType: "image/jpeg",
+	}, "test", map[string]interface{}{})
+	assert.Nil(t, err)
+	job, _ = printer.Job(jobID)
+	assert.Equal(t, ipp.JobStateAborted, job.State)
+	assert.Equal(t, "broken jpeg", job.StateMessage)
+}
+
+func TestVirtualPrinter_CreateJob(t *testing.T) {
+	printer := NewVirtualPrinter("test", nil)
+	s := NewServer()
+	printer.Register(s, "/ipp/print")
+
+	printerURI := "ipp://localhost/ipp/print"
+
+	req := newPrinterRequest(ipp.OperationCreateJob, printerURI)
+	req.JobAttributes["epson-media-thickness"] = ipp.Attribute{Tag: ipp.TagInteger, Value: 3}
+	resp := serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	jobID := resp.JobAttributes[0][ipp.AttributeJobID][0].Value.(int)
+	assert.Equal(t, int(ipp.JobStatePending), resp.JobAttributes[0][ipp.AttributeJobState][0].Value)
+
+	// vendor attributes are kept with the tag they were sent with
+	job, _ := printer.Job(jobID)
+	assert.Equal(t, []ipp.Attribute{{Tag: ipp.TagInteger, Name: "epson-media-thickness", Value: 3}},
+		job.Attributes["epson-media-thickness"])
+
+	req = newPrinterRequest(ipp.OperationGetJobs, printerURI)
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Len(t, resp.JobAttributes, 1)
+
+	req = newPrinterRequest(ipp.OperationSendDocument, printerURI)
+	req.OperationAttributes[ipp.AttributeJobID] = jobID
+	req.OperationAttributes[ipp.AttributeLastDocument] = true
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	assert.Equal(t, int(ipp.JobStateCompleted), resp.JobAttributes[0][ipp.AttributeJobState][0].Value)
+
+	req = newPrinterRequest(ipp.OperationCancelJob, printerURI)
+	req.OperationAttributes[ipp.AttributeJobID] = jobID
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusErrorNotPossible, resp.StatusCode)
+
+	req = newPrinterRequest(ipp.OperationGetJobs, printerURI)
+	req.OperationAttributes[ipp.AttributeWhichJobs] = ipp.JobStateFilterCompleted
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Len(t, resp.JobAttributes, 1)
+
+	req = ipp.NewRequest(ipp.OperationGetJobAttributes, 1)
+	req.OperationAttributes[ipp.AttributeJobURI] = printerURI + "/42"
+	resp = serveTestRequest(t, s, "/", req)
+	assert.Equal(t, ipp.StatusErrorNotFound, resp.StatusCode)
+}
+
+func TestVirtualPrinter_State(t *testing.T) {
+	printer := NewVirtualPrinter("test", nil)
+	s := NewServer()
+	printer.Register(s, "/printers/test")
+
+	client, closeServer := newTestClient(t, s)
+	defer closeServer()
+
+	printer.State.Pause()
+	printer.State.AddReason(PrinterStateReasonTonerLow)
+	printer.State.SetAcceptingJobs(false)
+
+	attributes, err := client.GetPrinterAttributes("test", []string{RequestedAll})
+	assert.Nil(t, err)
+	assert.Equal(t, int(ipp.PrinterStateStopped), attributes[ipp.AttributePrinterState][0].Value)
+	assert.Equal(t, PrinterStateReasonPaused, attributes[ipp.AttributePrinterStateReasons][0].Value)
+	assert.Equal(t, PrinterStateReasonTonerLow, attributes[ipp.AttributePrinterStateReasons][1].Value)
+	assert.Equal(t, false, attributes[ipp.AttributePrinterIsAcceptingJobs][0].Value)
+
+	resp := serveTestRequest(t, s, "/printers/test", newPrinterRequest(ipp.OperationCreateJob, "ipp://localhost/printers/test"))
+	assert.Equal(t, ipp.StatusErrorNotAcceptingJobs, resp.StatusCode)
+}
+
+func TestVirtualPrinter_CreateJobSubscription(t *testing.T) {
+	printer := NewVirtualPrinter("test", nil)
+	s := NewServer()
+	printer.Register(s, "/printers/test")
+
+	client, closeServer := newTestClient(t, s)
+	defer closeServer()
+
+	resp := serveTestRequest(t, s, "/printers/test", newPrinterRequest(ipp.OperationCreateJob,
+		"ipp://localhost/printers/test"))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	jobID := resp.JobAttributes[0][ipp.AttributeJobID][0].Value.(int)
+
+	subscriptionID, err := client.CreateJobSubscription("test", jobID,
+		ipp.SubscriptionTemplate{Events: []string{ipp.EventJobCompleted}})
+	assert.Nil(t, err)
+	assert.Greater(t, subscriptionID, 0)
+
+	sub, err := printer.Subscriptions.Get(subscriptionID)
+	assert.Nil(t, err)
+	assert.Equal(t, jobID, sub.JobID)
+
+	_, err = client.CreateJobSubscription("test", jobID+1,
+		ipp.SubscriptionTemplate{Events: []string{ipp.EventJobCompleted}})
+	assert.True(t, ipp.IsNotFound(err))
+}
+
+func TestVirtualPrinter_Notifications(t *testing.T) {
+	printer := NewVirtualPrinter("test", nil)
+	s := NewServer()
+	printer.Register(s, "/ipp/print")
+
+	printerURI := "ipp://localhost/ipp/print"
+
+	req := newPrinterRequest(ipp.OperationCreatePrinterSubscriptions, printerURI)
+	req.SubscriptionAttributes = map[string]interface{}{
+		ipp.AttributeNotifyPullMethod:    ipp.PullMethodIppGet,
+		ipp.AttributeNotifyEvents:        []string{ipp.EventJobStateChanged, ipp.EventPrinterStateChanged},
+		ipp.AttributeNotifyLeaseDuration: 600,
+	}
+	resp := serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	subscriptionID := resp.SubscriptionAttributes[0][ipp.AttributeNotifySubscriptionID][0].Value.(int)
+	assert.Equal(t, 600, resp.SubscriptionAttributes[0][ipp.AttributeNotifyLeaseDuration][0].Value)
+
+	req = newPrinterRequest(ipp.OperationCreatePrinterSubscriptions, printerURI)
+	req.SubscriptionAttributes = map[string]interface{}{
+		ipp.AttributeNotifyRecipientURI: "mailto:test@localhost",
+	}
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusErrorIgnoredAllSubscriptions, resp.StatusCode)
+
+	resp = serveTestRequest(t, s, "/ipp/print", newPrinterRequest(ipp.OperationCreateJob, printerURI))
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	jobID := resp.JobAttributes[0][ipp.AttributeJobID][0].Value.(int)
+
+	printer.State.Pause()
+
+	req = newPrinterRequest(ipp.OperationGetNotifications, printerURI)
+	req.OperationAttributes[ipp.AttributeNotifySubscriptionIDs] = subscriptionID
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+	if assert.Len(t, resp.EventNotificationAttributes, 2) {
+		created := resp.EventNotificationAttributes[0]
+		assert.Equal(t, ipp.EventJobCreated, created[ipp.AttributeNotifySubscribedEvent][0].Value)
+		assert.Equal(t, 1, created[ipp.AttributeNotifySequenceNumber][0].Value)
+		assert.Equal(t, jobID, created[ipp.AttributeJobID][0].Value)
+
+		stopped := resp.EventNotificationAttributes[1]
+		assert.Equal(t, ipp.EventPrinterStopped, stopped[ipp.AttributeNotifySubscribedEvent][0].Value)
+		assert.Equal(t, int(ipp.PrinterStateStopped), stopped[ipp.AttributePrinterState][0].Value)
+	}
+
+	req = newPrinterRequest(ipp.OperationGetSubscriptions, printerURI)
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Len(t, resp.SubscriptionAttributes, 1)
+
+	req = newPrinterRequest(ipp.OperationCancelSubscription, printerURI)
+	req.OperationAttributes[ipp.AttributeNotifySubscriptionID] = subscriptionID
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusOk, resp.StatusCode)
+
+	req = newPrinterRequest(ipp.OperationGetSubscriptionAttributes, printerURI)
+	req.OperationAttributes[ipp.AttributeNotifySubscriptionID] = subscriptionID
+	resp = serveTestRequest(t, s, "/ipp/print", req)
+	assert.Equal(t, ipp.StatusErrorNotFound, resp.StatusCode)
+}
@@ -0,0 +1,419 @@
+package snmp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// asn.1 and snmp value types
+const (
+	TypeInteger     byte = 0x02
+	TypeOctetString byte = 0x04
+	TypeNull        byte = 0x05
+	TypeOID         byte = 0x06
+	TypeIPAddress   byte = 0x40
+	TypeCounter32   byte = 0x41
+	TypeGauge32     byte = 0x42
+	TypeTimeTicks   byte = 0x43
+	TypeCounter64   byte = 0x46
+	// TypeNoSuchObject, TypeNoSuchInstance and TypeEndOfMibView are the exceptions of snmp v2c responses
+	TypeNoSuchObject   byte = 0x80
+	TypeNoSuchInstance byte = 0x81
+	TypeEndOfMibView   byte = 0x82
+
+	typeSequence  byte = 0x30
+	pduGetRequest byte = 0xa0
+	pduGetNext    byte = 0xa1
+	pduResponse   byte = 0xa2
+	snmpVersion2c      = 1
+)
+
+var (
+	// ShortMessageError is returned if a message ends before all fields are read
+	ShortMessageError = errors.New("snmp message is too short")
+	// InvalidOIDError is returned if an object identifier can not be parsed or encoded
+	InvalidOIDError = errors.New("invalid snmp object identifier")
+)
+
+// OID is an object identifier like 1.3.6.1.2.1.43.11.1.1.9.1.1
+type OID []int
+
+// ParseOID parses the dotted form of an object identifier, a leading dot is allowed
+func ParseOID(s string) (OID, error) {
+	parts := strings.Split(strings.TrimPrefix(s, "."), ".")
+	oid := make(OID, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: %s", InvalidOIDError, s)
+		}
+		oid[i] = n
+	}
+
+	if len(oid) < 2 {
+		return nil, fmt.Errorf("%w: %s", InvalidOIDError, s)
+	}
+
+	return oid, nil
+}
+
+// String returns the dotted form of the object identifier
+func (o OID) String() string {
+	parts := make([]string, len(o))
+	for i, n := range o {
+		parts[i] = strconv.Itoa(n)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// HasPrefix reports whether the object identifier is within the subtree of prefix
+func (o OID) HasPrefix(prefix OID) bool {
+	if len(o) < len(prefix) {
+		return false
+	}
+
+	for i := range prefix {
+		if o[i] != prefix[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Compare returns -1, 0 or 1 if the object identifier is lexicographically less than, equal to or greater than other
+func (o OID) Compare(other OID) int {
+	for i := 0; i < len(o) && i < len(other); i++ {
+		if o[i] != other[i] {
+			if o[i] < other[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case len(o) < len(other):
+		return -1
+	case len(o) > len(other):
+		return 1
+	}
+
+	return 0
+}
+
+// Variable is a variable binding of a snmp message. integers, counters, gauges and time ticks are int64 values,
+// octet strings and ip addresses are []byte values, object identifiers are OID values and all other values are nil
+type Variable struct {
+	OID   OID
+	Type  byte
+	Value interface{}
+}
+
+// Int returns the value of an integer, counter, gauge or time ticks variable, zero for other types
+func (v Variable) Int() int {
+	i, _ := v.Value.(int64)
+	return int(i)
+}
+
+// String returns the value of an octet string variable, an empty string for other types
+func (v Variable) String() string {
+	b, _ := v.Value.([]byte)
+	return string(b)
+}
+
+// Exists reports whether the agent returned a value, false for the exceptions like noSuchObject
+func (v Variable) Exists() bool {
+	return v.Type != TypeNoSuchObject && v.Type != TypeNoSuchInstance && v.Type != TypeEndOfMibView
+}
+
+// message is a snmp v2c message with a single pdu
+type message struct {
+	community   string
+	pduType     byte
+	requestID   int32
+	errorStatus int
+	errorIndex  int
+	variables   []Variable
+}
+
+func (m *message) marshal() ([]byte, error) {
+	var bindings []byte
+	for _, v := range m.variables {
+		oid, err := encodeOID(v.OID)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := encodeValue(v)
+		if err != nil {
+			return nil, err
+		}
+
+		bindings = appendTLV(bindings, typeSequence, appendTLV(appendTLV(nil, TypeOID, oid), v.Type, value))
+	}
+
+	var pdu []byte
+	pdu = appendTLV(pdu, TypeInteger, encodeInteger(int64(m.requestID)))
+	pdu = appendTLV(pdu, TypeInteger, encodeInteger(int64(m.errorStatus)))
+	pdu = appendTLV(pdu, TypeInteger, encodeInteger(int64(m.errorIndex)))
+	pdu = appendTLV(pdu, typeSequence, bindings)
+
+	var msg []byte
+	msg = appendTLV(msg, TypeInteger, encodeInteger(snmpVersion2c))
+	msg = appendTLV(msg, TypeOctetString, []byte(m.community))
+	msg = appendTLV(msg, m.pduType, pdu)
+
+	return appendTLV(nil, typeSequence, msg), nil
+}
+
+func unmarshalMessage(b []byte) (*message, error) {
+	tag, content, _, err := readTLV(b)
+	if err != nil {
+		return nil, err
+	}
+	if tag != typeSequence {
+		return nil, fmt.Errorf("unexpected snmp message type 0x%02x", tag)
+	}
+
+	var fields [3][]byte
+	var tags [3]byte
+	for i := range fields {
+		if tags[i], fields[i], content, err = readTLV(content); err != nil {
+			return nil, err
+		}
+	}
+	if tags[0] != TypeInteger || tags[1] != TypeOctetString {
+		return nil, errors.New("invalid snmp message header")
+	}
+	if version := decodeInteger(fields[0]); version != snmpVersion2c {
+		return nil, fmt.Errorf("unsupported snmp version %d", version)
+	}
+
+	m := &message{community: string(fields[1]), pduType: tags[2]}
+
+	pdu := fields[2]
+	var ints [3]int64
+	for i := range ints {
+		var value []byte
+		if tag, value, pdu, err = readTLV(pdu); err != nil {
+			return nil, err
+		}
+		if tag != TypeInteger {
+			return nil, errors.New("invalid snmp pdu header")
+		}
+		ints[i] = decodeInteger(value)
+	}
+	m.requestID, m.errorStatus, m.errorIndex = int32(ints[0]), int(ints[1]), int(ints[2])
+
+	if tag, pdu, _, err = readTLV(pdu); err != nil {
+		return nil, err
+	}
+	if tag != typeSequence {
+		return nil, errors.New("invalid snmp variable bindings")
+	}
+
+	for len(pdu) > 0 {
+		var binding []byte
+		if _, binding, pdu, err = readTLV(pdu); err != nil {
+			return nil, err
+		}
+
+		oidTag, oid, rest, err := readTLV(binding)
+		if err != nil {
+			return nil, err
+		}
+		if oidTag != TypeOID {
+			return nil, errors.New("invalid snmp variable binding")
+		}
+		valueTag, value, _, err := readTLV(rest)
+		if err != nil {
+			return nil, err
+		}
+
+		v := Variable{Type: valueTag}
+		if v.OID, err = decodeOID(oid); err != nil {
+			return nil, err
+		}
+		if v.Value, err = decodeValue(valueTag, value); err != nil {
+			return nil, err
+		}
+		m.variables = append(m.variables, v)
+	}
+
+	return m, nil
+}
+
+func appendTLV(b []byte, tag byte, content []byte) []byte {
+	b = append(b, tag)
+
+	switch n := len(content); {
+	case n < 0x80:
+		b = append(b, byte(n))
+	case n <= 0xff:
+		b = append(b, 0x81, byte(n))
+	default:
+		b = append(b, 0x82, byte(n>>8), byte(n))
+	}
+
+	return append(b, content...)
+}
+
+// readTLV reads a tag, length and value and returns the value and the remaining bytes
+func readTLV(b []byte) (byte, []byte, []byte, error) {
+	if len(b) < 2 {
+		return 0, nil, nil, ShortMessageError
+	}
+
+	tag, length, off := b[0], int(b[1]), 2
+	if length&0x80 != 0 {
+		n := length & 0x7f
+		if n == 0 || n > 3 || len(b) < 2+n {
+			return 0, nil, nil, ShortMessageError
+		}
+		length = 0
+		for _, c := range b[2 : 2+n] {
+			length = length<<8 | int(c)
+		}
+		off += n
+	}
+
+	if len(b) < off+length {
+		return 0, nil, nil, ShortMessageError
+	}
+
+	return tag, b[off : off+length], b[off+length:], nil
+}
+
+// encodeInteger encodes the shortest two's complement form of the value
+func encodeInteger(v int64) []byte {
+	b := []byte{byte(v)}
+	for v > 0x7f || v < -0x80 {
+		v >>= 8
+		b = append([]byte{byte(v)}, b...)
+	}
+
+	return b
+}
+
+func decodeInteger(b []byte) int64 {
+	var v int64
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		v = -1
+	}
+	for _, c := range b {
+		v = v<<8 | int64(c)
+	}
+
+	return v
+}
+
+// decodeUnsigned decodes counters, gauges and time ticks which are never negative
+func decodeUnsigned(b []byte) int64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+
+	return int64(v)
+}
+
+func encodeOID(oid OID) ([]byte, error) {
+	if len(oid) < 2 || oid[0] > 2 || oid[1] >= 40 {
+		return nil, fmt.Errorf("%w: %s", InvalidOIDError, oid)
+	}
+
+	b := encodeBase128(nil, 40*oid[0]+oid[1])
+	for _, n := range oid[2:] {
+		if n < 0 {
+			return nil, fmt.Errorf("%w: %s", InvalidOIDError, oid)
+		}
+		b = encodeBase128(b, n)
+	}
+
+	return b, nil
+}
+
+func encodeBase128(b []byte, n int) []byte {
+	var digits []byte
+	for {
+		digits = append([]byte{byte(n & 0x7f)}, digits...)
+		if n >>= 7; n == 0 {
+			break
+		}
+	}
+
+	for i := 0; i < len(digits)-1; i++ {
+		digits[i] |= 0x80
+	}
+
+	return append(b, digits...)
+}
+
+func decodeOID(b []byte) (OID, error) {
+	if len(b) == 0 {
+		return nil, InvalidOIDError
+	}
+
+	var oid OID
+	n := 0
+	for i, c := range b {
+		n = n<<7 | int(c&0x7f)
+		if c&0x80 != 0 {
+			if i == len(b)-1 {
+				return nil, InvalidOIDError
+			}
+			continue
+		}
+
+		if oid == nil {
+			first := n / 40
+			if first > 2 {
+				first = 2
+			}
+			oid = OID{first, n - 40*first}
+		} else {
+			oid = append(oid, n)
+		}
+		n = 0
+	}
+
+	return oid, nil
+}
+
+func encodeValue(v Variable) ([]byte, error) {
+	switch value := v.Value.(type) {
+	case nil:
+		return nil, nil
+	case int64:
+		return encodeInteger(value), nil
+	case int:
+		return encodeInteger(int64(value)), nil
+	case []byte:
+		return value, nil
+	case string:
+		return []byte(value), nil
+	case OID:
+		return encodeOID(value)
+	}
+
+	return nil, fmt.Errorf("cannot encode snmp value of type %T", v.Value)
+}
+
+func decodeValue(tag byte, b []byte) (interface{}, error) {
+	switch tag {
+	case TypeInteger:
+		return decodeInteger(b), nil
+	case TypeCounter32, TypeGauge32, TypeTimeTicks, TypeCounter64:
+		return decodeUnsigned(b), nil
+	case TypeOctetString, TypeIPAddress:
+		return append([]byte{}, b...), nil
+	case TypeOID:
+		return decodeOID(b)
+	}
+
+	return nil, nil
+}
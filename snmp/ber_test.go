@@ -0,0 +1,65 @@
+package snmp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOID(t *testing.T) {
+	oid, err := ParseOID(".1.3.6.1.2.1.43.11.1.1.9.1.1")
+	assert.Nil(t, err)
+	assert.Equal(t, "1.3.6.1.2.1.43.11.1.1.9.1.1", oid.String())
+	assert.True(t, oid.HasPrefix(prtMarkerSuppliesEntry))
+	assert.False(t, oid.HasPrefix(prtAlertEntry))
+	assert.Equal(t, -1, prtMarkerSuppliesEntry.Compare(oid))
+	assert.Equal(t, 1, prtAlertEntry.Compare(oid))
+	assert.Equal(t, 0, oid.Compare(oid))
+
+	_, err = ParseOID("1.3.x")
+	assert.ErrorIs(t, err, InvalidOIDError)
+
+	encoded, err := encodeOID(OID{1, 3, 6, 1, 4, 1, 11, 2, 3, 9, 4, 2, 1, 1, 16384})
+	assert.Nil(t, err)
+	decoded, err := decodeOID(encoded)
+	assert.Nil(t, err)
+	assert.Equal(t, OID{1, 3, 6, 1, 4, 1, 11, 2, 3, 9, 4, 2, 1, 1, 16384}, decoded)
+}
+
+func TestInteger(t *testing.T) {
+	for _, v := range []int64{0, 1, 127, 128, 255, 256, -1, -128, -129, 1 << 31, -(1 << 31)} {
+		assert.Equal(t, v, decodeInteger(encodeInteger(v)), v)
+	}
+	assert.Equal(t, []byte{0x00, 0x80}, encodeInteger(128))
+	assert.Equal(t, int64(0xffffffff), decodeUnsigned([]byte{0xff, 0xff, 0xff, 0xff}))
+}
+
+func TestMessage(t *testing.T) {
+	long := make([]byte, 300)
+	m := &message{
+		community: "public",
+		pduType:   pduResponse,
+		requestID: 42,
+		variables: []Variable{
+			{OID: OID{1, 3, 6, 1, 2, 1, 1, 5, 0}, Type: TypeOctetString, Value: []byte("printer")},
+			{OID: OID{1, 3, 6, 1, 2, 1, 1, 3, 0}, Type: TypeTimeTicks, Value: int64(123456)},
+			{OID: OID{1, 3, 6, 1, 2, 1, 43, 11, 1, 1, 9, 1, 1}, Type: TypeInteger, Value: int64(-3)},
+			{OID: OID{1, 3, 6, 1, 2, 1, 1, 2, 0}, Type: TypeOID, Value: OID{1, 3, 6, 1, 4, 1, 11}},
+			{OID: OID{1, 3, 6, 1, 2, 1, 1, 1, 0}, Type: TypeOctetString, Value: long},
+			{OID: OID{1, 3, 6, 1, 2, 1, 1, 9, 0}, Type: TypeNoSuchObject},
+		},
+	}
+
+	b, err := m.marshal()
+	assert.Nil(t, err)
+
+	decoded, err := unmarshalMessage(b)
+	assert.Nil(t, err)
+	assert.Equal(t, m, decoded)
+	assert.False(t, decoded.variables[5].Exists())
+	assert.Equal(t, "printer", decoded.variables[0].String())
+	assert.Equal(t, -3, decoded.variables[2].Int())
+
+	_, err = unmarshalMessage(b[:len(b)-1])
+	assert.ErrorIs(t, err, ShortMessageError)
+}
@@ -0,0 +1,148 @@
+// Package snmp implements a minimal snmp v2c client for the printer mib of rfc 3805. it reads the marker supplies and
+// alerts of network printers into the Supply and Alert types of the ipp package, to supplement printers whose ipp
+// marker attributes are missing or stale.
+package snmp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// AgentError is returned if the agent answers with an error status
+	AgentError = errors.New("snmp agent returned an error")
+	// TimeoutError is returned if the agent does not answer within the timeout and the retries
+	TimeoutError = errors.New("snmp agent did not answer")
+)
+
+// DefaultPort is the udp port of snmp agents
+const DefaultPort = "161"
+
+// maxWalkRequests limits the number of requests of a walk
+const maxWalkRequests = 10000
+
+// Client queries a snmp v2c agent, e.g. the agent of a network printer
+type Client struct {
+	// Address is the host of the agent with an optional port, the port defaults to 161
+	Address string
+	// Community is the community string, usually public for read access
+	Community string
+	// Timeout is the time to wait for the answer of a single request
+	Timeout time.Duration
+	// Retries is the number of times a request is repeated after a timeout
+	Retries int
+
+	requestID atomic.Int32
+}
+
+// NewClient creates a client for the agent at address using the public community
+func NewClient(address string) *Client {
+	return &Client{
+		Address:   address,
+		Community: "public",
+		Timeout:   2 * time.Second,
+		Retries:   1,
+	}
+}
+
+// Get returns the variables of the object identifiers. variables unknown to the agent are returned with the type
+// TypeNoSuchObject or TypeNoSuchInstance
+func (c *Client) Get(oids ...OID) ([]Variable, error) {
+	return c.request(pduGetRequest, oids)
+}
+
+// Walk returns all variables within the subtree of root in lexicographic order
+func (c *Client) Walk(root OID) ([]Variable, error) {
+	var variables []Variable
+
+	oid := root
+	for i := 0; i < maxWalkRequests; i++ {
+		next, err := c.request(pduGetNext, []OID{oid})
+		if err != nil {
+			return nil, err
+		}
+		if len(next) != 1 {
+			return nil, fmt.Errorf("%w: expected 1 variable, got %d", AgentError, len(next))
+		}
+
+		v := next[0]
+		if !v.Exists() || !v.OID.HasPrefix(root) {
+			return variables, nil
+		}
+		// a broken agent may return the same or a lower object identifier
+		if v.OID.Compare(oid) <= 0 {
+			return nil, fmt.Errorf("%w: object identifier %s does not increase", AgentError, v.OID)
+		}
+
+		variables = append(variables, v)
+		oid = v.OID
+	}
+
+	return variables, nil
+}
+
+func (c *Client) request(pduType byte, oids []OID) ([]Variable, error) {
+	req := &message{community: c.Community, pduType: pduType, requestID: c.requestID.Add(1)}
+	for _, oid := range oids {
+		req.variables = append(req.variables, Variable{OID: oid, Type: TypeNull})
+	}
+
+	payload, err := req.marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	address := c.Address
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, DefaultPort)
+	}
+
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+
+	buf := make([]byte, 65535)
+	for attempt := 0; attempt <= c.Retries; attempt++ {
+		if _, err := conn.Write(payload); err != nil {
+			return nil, err
+		}
+		if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+			return nil, err
+		}
+
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					break
+				}
+				return nil, err
+			}
+
+			resp, err := unmarshalMessage(buf[:n])
+			// late answers of a previous attempt or request are skipped
+			if err != nil || resp.pduType != pduResponse || resp.requestID != req.requestID {
+				continue
+			}
+
+			if resp.errorStatus != 0 {
+				return nil, fmt.Errorf("%w: status %d at index %d", AgentError, resp.errorStatus, resp.errorIndex)
+			}
+
+			return resp.variables, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", TimeoutError, address)
+}
@@ -0,0 +1,121 @@
+package snmp
+
+import (
+	"net"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testAgent answers get and get-next requests from a fixed set of variables
+type testAgent struct {
+	conn      *net.UDPConn
+	variables []Variable
+	// drop is the number of requests which are not answered
+	drop atomic.Int32
+}
+
+func newTestAgent(t *testing.T, variables []Variable) *testAgent {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skipf("udp not available: %v", err)
+	}
+
+	sort.Slice(variables, func(i, j int) bool {
+		return variables[i].OID.Compare(variables[j].OID) < 0
+	})
+
+	a := &testAgent{conn: conn, variables: variables}
+	go a.serve()
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return a
+}
+
+func (a *testAgent) client() *Client {
+	c := NewClient(a.conn.LocalAddr().String())
+	c.Timeout = 100 * time.Millisecond
+	return c
+}
+
+func (a *testAgent) serve() {
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := a.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+
+		req, err := unmarshalMessage(buf[:n])
+		if err != nil {
+			continue
+		}
+		if a.drop.Add(-1) >= 0 {
+			continue
+		}
+		a.drop.Store(0)
+
+		resp := &message{community: req.community, pduType: pduResponse, requestID: req.requestID}
+		for _, v := range req.variables {
+			resp.variables = append(resp.variables, a.lookup(req.pduType, v.OID))
+		}
+
+		b, _ := resp.marshal()
+		_, _ = a.conn.WriteToUDP(b, addr)
+	}
+}
+
+func (a *testAgent) lookup(pduType byte, oid OID) Variable {
+	for _, v := range a.variables {
+		switch {
+		case pduType == pduGetRequest && v.OID.Compare(oid) == 0:
+			return v
+		case pduType == pduGetNext && v.OID.Compare(oid) > 0:
+			return v
+		}
+	}
+
+	if pduType == pduGetNext {
+		return Variable{OID: oid, Type: TypeEndOfMibView}
+	}
+
+	return Variable{OID: oid, Type: TypeNoSuchObject}
+}
+
+func TestClient(t *testing.T) {
+	sysName := OID{1, 3, 6, 1, 2, 1, 1, 5, 0}
+	agent := newTestAgent(t, []Variable{
+		{OID: sysName, Type: TypeOctetString, Value: []byte("printer")},
+		{OID: OID{1, 3, 6, 1, 2, 1, 43, 5, 1, 1, 1, 1}, Type: TypeInteger, Value: int64(1)},
+		{OID: OID{1, 3, 6, 1, 2, 1, 43, 5, 1, 1, 2, 1}, Type: TypeInteger, Value: int64(2)},
+		{OID: OID{1, 3, 6, 1, 2, 1, 43, 6, 1, 1, 1, 1}, Type: TypeInteger, Value: int64(3)},
+	})
+	c := agent.client()
+
+	variables, err := c.Get(sysName, OID{1, 3, 6, 1, 2, 1, 1, 6, 0})
+	assert.Nil(t, err)
+	assert.Len(t, variables, 2)
+	assert.Equal(t, "printer", variables[0].String())
+	assert.False(t, variables[1].Exists())
+
+	variables, err = c.Walk(OID{1, 3, 6, 1, 2, 1, 43, 5})
+	assert.Nil(t, err)
+	assert.Len(t, variables, 2)
+	assert.Equal(t, 2, variables[1].Int())
+
+	variables, err = c.Walk(OID{1, 3, 6, 1, 2, 1, 43, 6})
+	assert.Nil(t, err)
+	assert.Len(t, variables, 1)
+
+	// the first request is lost and repeated
+	agent.drop.Store(1)
+	_, err = c.Get(sysName)
+	assert.Nil(t, err)
+
+	agent.drop.Store(2)
+	_, err = c.Get(sysName)
+	assert.ErrorIs(t, err, TimeoutError)
+}
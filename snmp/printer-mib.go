@@ -0,0 +1,243 @@
+package snmp
+
+import (
+	"github.com/phin1x/go-ipp"
+)
+
+// tables of the printer mib, rfc 3805
+var (
+	prtMarkerSuppliesEntry = OID{1, 3, 6, 1, 2, 1, 43, 11, 1, 1}
+	prtMarkerColorantValue = OID{1, 3, 6, 1, 2, 1, 43, 12, 1, 1, 4}
+	prtAlertEntry          = OID{1, 3, 6, 1, 2, 1, 43, 18, 1, 1}
+)
+
+// columns of the prtMarkerSuppliesTable
+const (
+	suppliesColorantIndex = 3
+	suppliesClass         = 4
+	suppliesType          = 5
+	suppliesDescription   = 6
+	suppliesUnit          = 7
+	suppliesMaxCapacity   = 8
+	suppliesLevel         = 9
+)
+
+// columns of the prtAlertTable
+const (
+	alertSeverity    = 2
+	alertTraining    = 3
+	alertGroup       = 4
+	alertGroupIndex  = 5
+	alertLocation    = 6
+	alertCode        = 7
+	alertDescription = 8
+)
+
+var supplyClasses = map[int]string{
+	1: "other",
+	3: "supplyThatIsConsumed",
+	4: "receptacleThatIsFilled",
+}
+
+var supplyTypes = map[int]string{
+	1: "other", 2: "unknown", 3: "toner", 4: "wasteToner", 5: "ink", 6: "inkCartridge", 7: "inkRibbon",
+	8: "wasteInk", 9: "opc", 10: "developer", 11: "fuserOil", 12: "solidWax", 13: "ribbonWax", 14: "wasteWax",
+	15: "fuser", 16: "coronaWire", 17: "fuserOilWick", 18: "cleanerUnit", 19: "fuserCleaningPad",
+	20: "transferUnit", 21: "tonerCartridge", 22: "fuserOiler", 23: "water", 24: "wasteWater",
+	25: "glueWaterAdditive", 26: "wastePaper", 27: "bindingSupply", 28: "bandingSupply", 29: "stitchingWire",
+	30: "shrinkWrap", 31: "paperWrap", 32: "staples", 33: "inserts", 34: "covers",
+}
+
+var supplyUnits = map[int]string{
+	1: "other", 2: "unknown", 3: "tenThousandthsOfInches", 4: "micrometers", 7: "impressions", 8: "sheets",
+	11: "hours", 12: "thousandthsOfOunces", 13: "tenthsOfGrams", 14: "hundrethsOfFluidOunces",
+	15: "tenthsOfMilliliters", 16: "feet", 17: "meters", 18: "items", 19: "percent",
+}
+
+var alertSeverities = map[int]string{
+	1: "other",
+	3: "critical",
+	4: "warning",
+	5: "warningBinaryChangeEvent",
+}
+
+var alertTrainings = map[int]string{
+	1: "other", 2: "unknown", 3: "untrained", 4: "trained", 5: "fieldService", 6: "management",
+	7: "noInterventionRequired",
+}
+
+var alertGroups = map[int]string{
+	1: "other", 3: "hostResourcesMIBStorageTable", 4: "hostResourcesMIBDeviceTable", 5: "generalPrinter",
+	6: "cover", 7: "localization", 8: "input", 9: "output", 10: "marker", 11: "markerSupplies",
+	12: "markerColorant", 13: "mediaPath", 14: "channel", 15: "interpreter", 16: "consoleDisplayBuffer",
+	17: "consoleLights", 18: "alert", 30: "finDevice", 31: "finSupply", 32: "finSupplyMediaInput",
+	33: "finAttribute",
+}
+
+var alertCodes = map[int]string{
+	1: "other", 2: "unknown", 3: "coverOpen", 4: "coverClosed", 5: "interlockOpen", 6: "interlockClosed",
+	7: "configurationChange", 8: "jam", 9: "subunitMissing", 10: "subunitLifeAlmostOver", 11: "subunitLifeOver",
+	12: "subunitAlmostEmpty", 13: "subunitEmpty", 14: "subunitAlmostFull", 15: "subunitFull",
+	16: "subunitNearLimit", 17: "subunitAtLimit", 18: "subunitOpened", 19: "subunitClosed",
+	20: "subunitTurnedOn", 21: "subunitTurnedOff", 22: "subunitOffline", 23: "subunitPowerSaver",
+	24: "subunitWarmingUp", 25: "subunitAdded", 26: "subunitRemoved", 27: "subunitResourceAdded",
+	28: "subunitResourceRemoved", 29: "subunitRecoverableFailure", 30: "subunitUnrecoverableFailure",
+	501: "doorOpen", 502: "doorClosed", 503: "powerUp", 504: "powerDown", 505: "printerNMSReset",
+	506: "printerManualReset", 507: "printerReadyToPrint",
+	801: "inputMediaTrayMissing", 802: "inputMediaSizeChange", 803: "inputMediaWeightChange",
+	804: "inputMediaTypeChange", 805: "inputMediaColorChange", 806: "inputMediaFormPartsChange",
+	807: "inputMediaSupplyLow", 808: "inputMediaSupplyEmpty",
+	901: "outputMediaTrayMissing", 902: "outputMediaTrayAlmostFull", 903: "outputMediaTrayFull",
+	1001: "markerFuserUnderTemperature", 1002: "markerFuserOverTemperature",
+	1101: "markerTonerEmpty", 1102: "markerInkEmpty", 1103: "markerPrintRibbonEmpty",
+	1104: "markerTonerAlmostEmpty", 1105: "markerInkAlmostEmpty", 1106: "markerPrintRibbonAlmostEmpty",
+	1107: "markerWasteTonerReceptacleAlmostFull", 1108: "markerWasteInkReceptacleAlmostFull",
+	1109: "markerWasteTonerReceptacleFull", 1110: "markerWasteInkReceptacleFull",
+	1111: "markerOpcLifeAlmostOver", 1112: "markerOpcLifeOver", 1113: "markerDeveloperAlmostEmpty",
+	1114: "markerDeveloperEmpty", 1115: "markerTonerCartridgeMissing",
+	1301: "mediaPathMediaTrayMissing", 1302: "mediaPathMediaTrayAlmostFull", 1303: "mediaPathMediaTrayFull",
+}
+
+// keyword returns the keyword of an enum value, other for unknown values
+func keyword(keywords map[int]string, value int) string {
+	if k, ok := keywords[value]; ok {
+		return k
+	}
+
+	return "other"
+}
+
+// tableRow is a row of a mib table, the columns are indexed by their number
+type tableRow struct {
+	index   OID
+	columns map[int]Variable
+}
+
+// table groups the variables of a table walk by their row index, the rows are in the order of the walk
+func table(entry OID, variables []Variable) []tableRow {
+	var rows []tableRow
+	index := make(map[string]int)
+
+	for _, v := range variables {
+		if len(v.OID) < len(entry)+2 || !v.OID.HasPrefix(entry) {
+			continue
+		}
+
+		column, rowIndex := v.OID[len(entry)], v.OID[len(entry)+1:]
+		i, ok := index[rowIndex.String()]
+		if !ok {
+			i = len(rows)
+			index[rowIndex.String()] = i
+			rows = append(rows, tableRow{index: rowIndex, columns: make(map[int]Variable)})
+		}
+		rows[i].columns[column] = v
+	}
+
+	return rows
+}
+
+// Supplies reads the prtMarkerSuppliesTable and the colorant names of the prtMarkerColorantTable
+func (c *Client) Supplies() ([]ipp.Supply, error) {
+	variables, err := c.Walk(prtMarkerSuppliesEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	colorants, err := c.Walk(prtMarkerColorantValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSupplies(variables, colorants), nil
+}
+
+func parseSupplies(variables, colorants []Variable) []ipp.Supply {
+	// the colorant values are indexed by the device index and the colorant index
+	colorantNames := make(map[string]string)
+	for _, v := range colorants {
+		colorantNames[v.OID[len(prtMarkerColorantValue):].String()] = v.String()
+	}
+
+	var supplies []ipp.Supply
+	for _, row := range table(prtMarkerSuppliesEntry, variables) {
+		supply := ipp.Supply{
+			Index:       row.index[len(row.index)-1],
+			Class:       keyword(supplyClasses, row.columns[suppliesClass].Int()),
+			Type:        keyword(supplyTypes, row.columns[suppliesType].Int()),
+			Description: row.columns[suppliesDescription].String(),
+			Unit:        keyword(supplyUnits, row.columns[suppliesUnit].Int()),
+			MaxCapacity: ipp.SupplyUnknown,
+			Level:       ipp.SupplyUnknown,
+		}
+		if v, ok := row.columns[suppliesMaxCapacity]; ok {
+			supply.MaxCapacity = v.Int()
+		}
+		if v, ok := row.columns[suppliesLevel]; ok {
+			supply.Level = v.Int()
+		}
+		if colorant := row.columns[suppliesColorantIndex].Int(); colorant > 0 && len(row.index) == 2 {
+			supply.ColorantName = colorantNames[OID{row.index[0], colorant}.String()]
+		}
+
+		supplies = append(supplies, supply)
+	}
+
+	return supplies
+}
+
+// Alerts reads the prtAlertTable
+func (c *Client) Alerts() ([]ipp.Alert, error) {
+	variables, err := c.Walk(prtAlertEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseAlerts(variables), nil
+}
+
+func parseAlerts(variables []Variable) []ipp.Alert {
+	var alerts []ipp.Alert
+	for _, row := range table(prtAlertEntry, variables) {
+		alerts = append(alerts, ipp.Alert{
+			Index:       row.index[len(row.index)-1],
+			Severity:    keyword(alertSeverities, row.columns[alertSeverity].Int()),
+			Training:    keyword(alertTrainings, row.columns[alertTraining].Int()),
+			Group:       keyword(alertGroups, row.columns[alertGroup].Int()),
+			GroupIndex:  row.columns[alertGroupIndex].Int(),
+			Location:    row.columns[alertLocation].Int(),
+			Code:        keyword(alertCodes, row.columns[alertCode].Int()),
+			Description: row.columns[alertDescription].String(),
+		})
+	}
+
+	return alerts
+}
+
+// Supplement returns the supplies and alerts of the printer attributes, e.g. of a Get-Printer-Attributes response.
+// the printer mib is queried instead if the attributes contain no supplies or the levels of all supplies are
+// unknown, which some printers report when their ipp marker attributes are stale
+func (c *Client) Supplement(attributes ipp.Attributes) ([]ipp.Supply, []ipp.Alert, error) {
+	supplies := ipp.ParseSupplies(attributes)
+	alerts := ipp.ParseAlerts(attributes)
+
+	stale := true
+	for _, supply := range supplies {
+		stale = stale && supply.Level == ipp.SupplyUnknown
+	}
+	if !stale {
+		return supplies, alerts, nil
+	}
+
+	supplies, err := c.Supplies()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, ok := attributes[ipp.AttributePrinterAlert]; !ok {
+		if alerts, err = c.Alerts(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return supplies, alerts, nil
+}
@@ -0,0 +1,78 @@
+package snmp
+
+import (
+	"testing"
+
+	"github.com/phin1x/go-ipp"
+	"github.com/stretchr/testify/assert"
+)
+
+func column(entry OID, column int, index ...int) OID {
+	oid := append(append(OID{}, entry...), column)
+	return append(oid, index...)
+}
+
+func printerMIB() []Variable {
+	return []Variable{
+		{OID: column(prtMarkerSuppliesEntry, suppliesColorantIndex, 1, 1), Type: TypeInteger, Value: int64(1)},
+		{OID: column(prtMarkerSuppliesEntry, suppliesClass, 1, 1), Type: TypeInteger, Value: int64(3)},
+		{OID: column(prtMarkerSuppliesEntry, suppliesType, 1, 1), Type: TypeInteger, Value: int64(21)},
+		{OID: column(prtMarkerSuppliesEntry, suppliesDescription, 1, 1), Type: TypeOctetString,
+			Value: []byte("Black Cartridge")},
+		{OID: column(prtMarkerSuppliesEntry, suppliesUnit, 1, 1), Type: TypeInteger, Value: int64(7)},
+		{OID: column(prtMarkerSuppliesEntry, suppliesMaxCapacity, 1, 1), Type: TypeInteger, Value: int64(10000)},
+		{OID: column(prtMarkerSuppliesEntry, suppliesLevel, 1, 1), Type: TypeInteger, Value: int64(2500)},
+		{OID: column(prtMarkerSuppliesEntry, suppliesClass, 1, 2), Type: TypeInteger, Value: int64(4)},
+		{OID: column(prtMarkerSuppliesEntry, suppliesType, 1, 2), Type: TypeInteger, Value: int64(4)},
+		{OID: column(prtMarkerSuppliesEntry, suppliesUnit, 1, 2), Type: TypeInteger, Value: int64(19)},
+		{OID: column(prtMarkerSuppliesEntry, suppliesMaxCapacity, 1, 2), Type: TypeInteger, Value: int64(100)},
+		{OID: column(prtMarkerSuppliesEntry, suppliesLevel, 1, 2), Type: TypeInteger, Value: int64(-3)},
+		{OID: append(append(OID{}, prtMarkerColorantValue...), 1, 1), Type: TypeOctetString, Value: []byte("black")},
+		{OID: column(prtAlertEntry, alertSeverity, 1, 7), Type: TypeInteger, Value: int64(3)},
+		{OID: column(prtAlertEntry, alertTraining, 1, 7), Type: TypeInteger, Value: int64(3)},
+		{OID: column(prtAlertEntry, alertGroup, 1, 7), Type: TypeInteger, Value: int64(8)},
+		{OID: column(prtAlertEntry, alertGroupIndex, 1, 7), Type: TypeInteger, Value: int64(2)},
+		{OID: column(prtAlertEntry, alertCode, 1, 7), Type: TypeInteger, Value: int64(8)},
+		{OID: column(prtAlertEntry, alertDescription, 1, 7), Type: TypeOctetString, Value: []byte("Paper jam")},
+	}
+}
+
+func TestClient_Supplies(t *testing.T) {
+	c := newTestAgent(t, printerMIB()).client()
+
+	supplies, err := c.Supplies()
+	assert.Nil(t, err)
+	assert.Equal(t, []ipp.Supply{
+		{Index: 1, Type: "tonerCartridge", Class: "supplyThatIsConsumed", Description: "Black Cartridge",
+			Unit: "impressions", MaxCapacity: 10000, Level: 2500, ColorantName: "black"},
+		{Index: 2, Type: "wasteToner", Class: "receptacleThatIsFilled", Unit: "percent", MaxCapacity: 100,
+			Level: ipp.SupplySomeRemaining},
+	}, supplies)
+	assert.Equal(t, 25, supplies[0].Percent())
+
+	alerts, err := c.Alerts()
+	assert.Nil(t, err)
+	assert.Equal(t, []ipp.Alert{{Index: 7, Severity: "critical", Training: "untrained", Group: "input",
+		GroupIndex: 2, Code: "jam", Description: "Paper jam"}}, alerts)
+}
+
+func TestClient_Supplement(t *testing.T) {
+	c := newTestAgent(t, printerMIB()).client()
+
+	attributes := make(ipp.Attributes)
+	attributes.Set(ipp.AttributeMarkerNames, ipp.TagName, "Black")
+	attributes.Set(ipp.AttributeMarkerLevels, ipp.TagInteger, 80)
+
+	supplies, alerts, err := c.Supplement(attributes)
+	assert.Nil(t, err)
+	assert.Equal(t, "Black", supplies[0].Description)
+	assert.Empty(t, alerts)
+
+	// the marker levels are unknown, the printer mib is used
+	attributes.Set(ipp.AttributeMarkerLevels, ipp.TagInteger, ipp.SupplyUnknown)
+	supplies, alerts, err = c.Supplement(attributes)
+	assert.Nil(t, err)
+	assert.Len(t, supplies, 2)
+	assert.Equal(t, "Black Cartridge", supplies[0].Description)
+	assert.Len(t, alerts, 1)
+}
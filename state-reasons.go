@@ -0,0 +1,177 @@
+package ipp
+
+import "strings"
+
+// ReasonSeverity is the severity of a printer-state-reasons or job-state-reasons keyword
+type ReasonSeverity int
+
+// reason severities, ordered from the least to the most severe
+const (
+	SeverityReport ReasonSeverity = iota
+	SeverityWarning
+	SeverityError
+)
+
+var severitySuffixes = map[ReasonSeverity]string{
+	SeverityReport:  "-report",
+	SeverityWarning: "-warning",
+	SeverityError:   "-error",
+}
+
+// String returns the keyword suffix of the severity without the leading dash
+func (s ReasonSeverity) String() string {
+	if suffix, ok := severitySuffixes[s]; ok {
+		return suffix[1:]
+	}
+
+	return ""
+}
+
+// StateReason is a single printer-state-reasons or job-state-reasons value
+type StateReason struct {
+	// Value is the keyword as sent by the printer, e.g. media-empty-error
+	Value string
+	// Keyword is the value without the severity suffix, e.g. media-empty
+	Keyword  string
+	Severity ReasonSeverity
+}
+
+// NewStateReason creates a printer state reason from a keyword without suffix and its severity
+func NewStateReason(keyword string, severity ReasonSeverity) StateReason {
+	return StateReason{Value: keyword + severitySuffixes[severity], Keyword: keyword, Severity: severity}
+}
+
+// String returns the keyword as sent by the printer
+func (r StateReason) String() string {
+	return r.Value
+}
+
+// StateReasons is a list of state reasons, the none keyword is never part of the list
+type StateReasons []StateReason
+
+// ParsePrinterStateReasons parses printer-state-reasons values and splits the -report, -warning and -error
+// suffixes from the keywords. as required by rfc 8011, a keyword without suffix is an error
+func ParsePrinterStateReasons(values []string) StateReasons {
+	reasons := make(StateReasons, 0, len(values))
+	for _, value := range values {
+		if value == "" || value == "none" {
+			continue
+		}
+
+		reason := StateReason{Value: value, Keyword: value, Severity: SeverityError}
+		for severity, suffix := range severitySuffixes {
+			if strings.HasSuffix(value, suffix) {
+				reason.Keyword = strings.TrimSuffix(value, suffix)
+				reason.Severity = severity
+				break
+			}
+		}
+
+		reasons = append(reasons, reason)
+	}
+
+	return reasons
+}
+
+// ParseJobStateReasons parses job-state-reasons values. job keywords have no severity suffix, keywords ending with
+// -error, e.g. document-format-error, and errors-detected are errors, warnings-detected is a warning and all other
+// keywords are reports
+func ParseJobStateReasons(values []string) StateReasons {
+	reasons := make(StateReasons, 0, len(values))
+	for _, value := range values {
+		if value == "" || value == "none" {
+			continue
+		}
+
+		reason := StateReason{Value: value, Keyword: value, Severity: SeverityReport}
+		switch {
+		case strings.HasSuffix(value, "-error") || value == "errors-detected":
+			reason.Severity = SeverityError
+		case value == "warnings-detected":
+			reason.Severity = SeverityWarning
+		}
+
+		reasons = append(reasons, reason)
+	}
+
+	return reasons
+}
+
+// Has checks whether a reason with the keyword is part of the list, the keyword is compared without suffix
+func (r StateReasons) Has(keyword string) bool {
+	for _, reason := range r {
+		if reason.Keyword == keyword {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasError checks whether the list contains a reason of severity error
+func (r StateReasons) HasError() bool {
+	return r.MaxSeverity() == SeverityError
+}
+
+// HasWarning checks whether the list contains a reason of severity warning or error
+func (r StateReasons) HasWarning() bool {
+	return r.MaxSeverity() >= SeverityWarning
+}
+
+// MaxSeverity returns the highest severity of the reasons, SeverityReport if the list is empty
+func (r StateReasons) MaxSeverity() ReasonSeverity {
+	severity := SeverityReport
+	for _, reason := range r {
+		if reason.Severity > severity {
+			severity = reason.Severity
+		}
+	}
+
+	return severity
+}
+
+// Filter returns the reasons of the given severity
+func (r StateReasons) Filter(severity ReasonSeverity) StateReasons {
+	var filtered StateReasons
+	for _, reason := range r {
+		if reason.Severity == severity {
+			filtered = append(filtered, reason)
+		}
+	}
+
+	return filtered
+}
+
+// Keywords returns the keywords of the reasons without suffix
+func (r StateReasons) Keywords() []string {
+	keywords := make([]string, len(r))
+	for i, reason := range r {
+		keywords[i] = reason.Keyword
+	}
+
+	return keywords
+}
+
+// Values returns the reasons as sent by the printer, none if the list is empty
+func (r StateReasons) Values() []string {
+	if len(r) == 0 {
+		return []string{"none"}
+	}
+
+	values := make([]string, len(r))
+	for i, reason := range r {
+		values[i] = reason.Value
+	}
+
+	return values
+}
+
+// Reasons returns the parsed job-state-reasons of the job
+func (j *Job) Reasons() StateReasons {
+	return ParseJobStateReasons(j.StateReasons)
+}
+
+// Reasons returns the parsed printer-state-reasons of the printer
+func (d *PrinterDescription) Reasons() StateReasons {
+	return ParsePrinterStateReasons(d.StateReasons)
+}
@@ -0,0 +1,52 @@
+package ipp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePrinterStateReasons(t *testing.T) {
+	reasons := ParsePrinterStateReasons([]string{"media-empty-error", "toner-low-warning", "offline-report", "paused"})
+	assert.Equal(t, StateReasons{
+		{Value: "media-empty-error", Keyword: "media-empty", Severity: SeverityError},
+		{Value: "toner-low-warning", Keyword: "toner-low", Severity: SeverityWarning},
+		{Value: "offline-report", Keyword: "offline", Severity: SeverityReport},
+		{Value: "paused", Keyword: "paused", Severity: SeverityError},
+	}, reasons)
+
+	assert.True(t, reasons.Has("toner-low"))
+	assert.False(t, reasons.Has("toner-low-warning"))
+	assert.True(t, reasons.HasError())
+	assert.Equal(t, []string{"toner-low"}, reasons.Filter(SeverityWarning).Keywords())
+	assert.Equal(t, []string{"media-empty-error", "toner-low-warning", "offline-report", "paused"}, reasons.Values())
+
+	warnings := ParsePrinterStateReasons([]string{"toner-low-warning", "offline-report"})
+	assert.False(t, warnings.HasError())
+	assert.True(t, warnings.HasWarning())
+	assert.Equal(t, SeverityWarning, warnings.MaxSeverity())
+
+	none := ParsePrinterStateReasons([]string{"none"})
+	assert.Empty(t, none)
+	assert.False(t, none.HasWarning())
+	assert.Equal(t, []string{"none"}, none.Values())
+
+	assert.Equal(t, "media-jam-error", NewStateReason("media-jam", SeverityError).String())
+	assert.Equal(t, "warning", SeverityWarning.String())
+}
+
+func TestParseJobStateReasons(t *testing.T) {
+	reasons := ParseJobStateReasons([]string{"job-printing", "document-format-error", "warnings-detected"})
+	assert.Equal(t, StateReasons{
+		{Value: "job-printing", Keyword: "job-printing", Severity: SeverityReport},
+		{Value: "document-format-error", Keyword: "document-format-error", Severity: SeverityError},
+		{Value: "warnings-detected", Keyword: "warnings-detected", Severity: SeverityWarning},
+	}, reasons)
+	assert.True(t, reasons.HasError())
+
+	job := Job{StateReasons: []string{"job-completed-successfully"}}
+	assert.False(t, job.Reasons().HasWarning())
+
+	printer := PrinterDescription{StateReasons: []string{"media-needed-warning"}}
+	assert.True(t, printer.Reasons().Has("media-needed"))
+}
@@ -0,0 +1,261 @@
+package ipp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// supply levels and capacities with a special meaning, as defined by the printer mib
+const (
+	SupplyUnlimited     = -1
+	SupplyUnknown       = -2
+	SupplySomeRemaining = -3
+)
+
+// Supply is a marker supply of a printer, e.g. a toner cartridge or a waste toner box. it is parsed from the
+// printer-supply attribute of pwg 5100.13 or the marker-* attributes of cups, the snmp sub-package reads it from
+// the printer mib
+type Supply struct {
+	// Index identifies the supply within the printer, it starts at 1
+	Index int
+	// Type is the supply type of the printer mib, e.g. toner, ink or wasteToner
+	Type string
+	// Class is supplyThatIsConsumed or receptacleThatIsFilled
+	Class       string
+	Description string
+	// Unit is the unit of MaxCapacity and Level, e.g. percent, sheets or tenthsOfGrams
+	Unit string
+	// MaxCapacity and Level are in Unit or one of SupplyUnlimited, SupplyUnknown and SupplySomeRemaining
+	MaxCapacity int
+	Level       int
+	// ColorantName is the colorant of the printer mib, e.g. black or cyan
+	ColorantName string
+	// Color is the sRGB color of the marker-colors attribute, e.g. #00FFFF
+	Color string
+	// LowLevel is the level in percent at which the supply is reported as almost empty, zero if unknown
+	LowLevel int
+}
+
+// Percent returns the level in percent of the maximum capacity, -1 if the level is not known
+func (s Supply) Percent() int {
+	switch {
+	case s.Level < 0:
+		return -1
+	case s.Unit == "percent":
+		return s.Level
+	case s.MaxCapacity <= 0:
+		return -1
+	}
+
+	return s.Level * 100 / s.MaxCapacity
+}
+
+// Low reports whether a consumed supply is at or below its low level, 10 percent is used if the low level is unknown
+func (s Supply) Low() bool {
+	low := s.LowLevel
+	if low <= 0 {
+		low = 10
+	}
+
+	percent := s.Percent()
+	return s.Class != "receptacleThatIsFilled" && percent >= 0 && percent <= low
+}
+
+// String returns the printer-supply value of the supply, e.g.
+// index=1;class=supplyThatIsConsumed;type=toner;unit=percent;maxcapacity=100;level=45;colorantname=black;
+func (s Supply) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "index=%d;", s.Index)
+	if s.Class != "" {
+		fmt.Fprintf(&b, "class=%s;", s.Class)
+	}
+	if s.Type != "" {
+		fmt.Fprintf(&b, "type=%s;", s.Type)
+	}
+	if s.Unit != "" {
+		fmt.Fprintf(&b, "unit=%s;", s.Unit)
+	}
+	fmt.Fprintf(&b, "maxcapacity=%d;level=%d;", s.MaxCapacity, s.Level)
+	if s.ColorantName != "" {
+		fmt.Fprintf(&b, "colorantname=%s;", s.ColorantName)
+	}
+
+	return b.String()
+}
+
+// ParseSupplies returns the supplies of the printer attributes. printer-supply and printer-supply-description are
+// preferred, the cups marker-names, marker-types, marker-colors, marker-levels and marker-low-levels attributes are
+// used otherwise. nil is returned if the printer reports no supplies
+func ParseSupplies(attributes Attributes) []Supply {
+	u := attributeUnmarshaler{attributes: attributes}
+
+	if values := u.strings(AttributePrinterSupply); len(values) > 0 {
+		descriptions := u.strings(AttributePrinterSupplyDescription)
+		colors := u.strings(AttributeMarkerColors)
+
+		supplies := make([]Supply, len(values))
+		for i, value := range values {
+			keys := parseKeyValues(value)
+			supplies[i] = Supply{
+				Index:        atoiDefault(keys["index"], i+1),
+				Type:         keys["type"],
+				Class:        keys["class"],
+				Unit:         keys["unit"],
+				MaxCapacity:  atoiDefault(keys["maxcapacity"], SupplyUnknown),
+				Level:        atoiDefault(keys["level"], SupplyUnknown),
+				ColorantName: keys["colorantname"],
+			}
+			if i < len(descriptions) {
+				supplies[i].Description = descriptions[i]
+			}
+			if i < len(colors) {
+				supplies[i].Color = colors[i]
+			}
+		}
+
+		return supplies
+	}
+
+	names := u.strings(AttributeMarkerNames)
+	if len(names) == 0 {
+		return nil
+	}
+
+	types := u.strings(AttributeMarkerTypes)
+	colors := u.strings(AttributeMarkerColors)
+	levels := u.ints(AttributeMarkerLevels)
+	lowLevels := u.ints(AttributeMarkerLowLevels)
+
+	supplies := make([]Supply, len(names))
+	for i, name := range names {
+		supplies[i] = Supply{
+			Index:       i + 1,
+			Class:       "supplyThatIsConsumed",
+			Description: name,
+			Unit:        "percent",
+			MaxCapacity: 100,
+			Level:       SupplyUnknown,
+		}
+		if i < len(types) {
+			// the cups marker types use dashes, e.g. waste-toner, the printer mib uses camel case
+			supplies[i].Type = camelCase(types[i])
+			if strings.HasPrefix(types[i], "waste-") {
+				supplies[i].Class = "receptacleThatIsFilled"
+			}
+		}
+		if i < len(colors) {
+			supplies[i].Color = colors[i]
+		}
+		if i < len(levels) {
+			supplies[i].Level = levels[i]
+		}
+		if i < len(lowLevels) {
+			supplies[i].LowLevel = lowLevels[i]
+		}
+	}
+
+	return supplies
+}
+
+// Alert is a printer alert of the printer mib, e.g. a paper jam or an open cover. it is parsed from the
+// printer-alert attribute of pwg 5100.9, the snmp sub-package reads it from the printer mib
+type Alert struct {
+	Index int
+	// Severity is other, critical, warning or warningBinaryChangeEvent
+	Severity string
+	// Training is the training level required to handle the alert, e.g. untrained or fieldService
+	Training string
+	// Group is the subunit of the alert, e.g. input, marker or markerSupplies
+	Group      string
+	GroupIndex int
+	Location   int
+	// Code is the alert code, e.g. jam, coverOpen or markerTonerEmpty
+	Code        string
+	Description string
+}
+
+// String returns the printer-alert value of the alert, e.g. index=1;severity=critical;group=input;groupindex=1;code=jam;
+func (a Alert) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "index=%d;", a.Index)
+	if a.Severity != "" {
+		fmt.Fprintf(&b, "severity=%s;", a.Severity)
+	}
+	if a.Training != "" {
+		fmt.Fprintf(&b, "training=%s;", a.Training)
+	}
+	if a.Group != "" {
+		fmt.Fprintf(&b, "group=%s;groupindex=%d;", a.Group, a.GroupIndex)
+	}
+	if a.Location != 0 {
+		fmt.Fprintf(&b, "location=%d;", a.Location)
+	}
+	if a.Code != "" {
+		fmt.Fprintf(&b, "code=%s;", a.Code)
+	}
+
+	return b.String()
+}
+
+// ParseAlerts returns the alerts of the printer-alert and printer-alert-description attributes
+func ParseAlerts(attributes Attributes) []Alert {
+	u := attributeUnmarshaler{attributes: attributes}
+
+	values := u.strings(AttributePrinterAlert)
+	descriptions := u.strings(AttributePrinterAlertDescription)
+
+	var alerts []Alert
+	for i, value := range values {
+		keys := parseKeyValues(value)
+		alert := Alert{
+			Index:      atoiDefault(keys["index"], i+1),
+			Severity:   keys["severity"],
+			Training:   keys["training"],
+			Group:      keys["group"],
+			GroupIndex: atoiDefault(keys["groupindex"], 0),
+			Location:   atoiDefault(keys["location"], 0),
+			Code:       keys["code"],
+		}
+		if i < len(descriptions) {
+			alert.Description = descriptions[i]
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts
+}
+
+// parseKeyValues parses the key=value; pairs of a printer-supply or printer-alert value, the keys are in lower case
+func parseKeyValues(value string) map[string]string {
+	keys := make(map[string]string)
+	for _, pair := range strings.Split(value, ";") {
+		key, v, ok := strings.Cut(pair, "=")
+		if ok {
+			keys[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(v)
+		}
+	}
+
+	return keys
+}
+
+func atoiDefault(s string, def int) int {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+
+	return i
+}
+
+// camelCase converts a keyword with dashes like waste-toner into camel case like wasteToner
+func camelCase(keyword string) string {
+	parts := strings.Split(keyword, "-")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,57 @@
+package ipp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSupplies(t *testing.T) {
+	attributes := make(Attributes)
+	attributes.Set(AttributePrinterSupply, TagString,
+		"index=1;class=supplyThatIsConsumed;type=toner;unit=percent;maxcapacity=100;level=45;colorantname=black;",
+		"index=2;class=receptacleThatIsFilled;type=wasteToner;unit=sheets;maxcapacity=20000;level=19000;")
+	attributes.Set(AttributePrinterSupplyDescription, TagText, "Black Toner", "Waste Toner Box")
+
+	supplies := ParseSupplies(attributes)
+	assert.Equal(t, []Supply{
+		{Index: 1, Type: "toner", Class: "supplyThatIsConsumed", Description: "Black Toner", Unit: "percent",
+			MaxCapacity: 100, Level: 45, ColorantName: "black"},
+		{Index: 2, Type: "wasteToner", Class: "receptacleThatIsFilled", Description: "Waste Toner Box",
+			Unit: "sheets", MaxCapacity: 20000, Level: 19000},
+	}, supplies)
+	assert.Equal(t, 45, supplies[0].Percent())
+	assert.Equal(t, 95, supplies[1].Percent())
+	assert.False(t, supplies[1].Low())
+	assert.Equal(t, attributes[AttributePrinterSupply][0].Value, supplies[0].String())
+
+	cups := make(Attributes)
+	cups.Set(AttributeMarkerNames, TagName, "Cyan Ink", "Waste Ink")
+	cups.Set(AttributeMarkerTypes, TagKeyword, "ink-cartridge", "waste-ink")
+	cups.Set(AttributeMarkerColors, TagName, "#00FFFF", "none")
+	cups.Set(AttributeMarkerLevels, TagInteger, 5, 60)
+	cups.Set(AttributeMarkerLowLevels, TagInteger, 8, 0)
+
+	supplies = ParseSupplies(cups)
+	assert.Len(t, supplies, 2)
+	assert.Equal(t, "inkCartridge", supplies[0].Type)
+	assert.Equal(t, "#00FFFF", supplies[0].Color)
+	assert.True(t, supplies[0].Low())
+	assert.Equal(t, "receptacleThatIsFilled", supplies[1].Class)
+	assert.False(t, supplies[1].Low())
+
+	assert.Nil(t, ParseSupplies(make(Attributes)))
+	assert.Equal(t, -1, Supply{Level: SupplyUnknown, Unit: "percent"}.Percent())
+}
+
+func TestParseAlerts(t *testing.T) {
+	attributes := make(Attributes)
+	attributes.Set(AttributePrinterAlert, TagString,
+		"index=1;severity=critical;training=untrained;group=input;groupindex=2;code=jam;")
+	attributes.Set(AttributePrinterAlertDescription, TagText, "Paper jam in tray 2")
+
+	alerts := ParseAlerts(attributes)
+	assert.Equal(t, []Alert{{Index: 1, Severity: "critical", Training: "untrained", Group: "input", GroupIndex: 2,
+		Code: "jam", Description: "Paper jam in tray 2"}}, alerts)
+	assert.Equal(t, attributes[AttributePrinterAlert][0].Value, alerts[0].String())
+}
@@ -0,0 +1,128 @@
+package ipp
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CertificateChangedError is returned by a TOFUStore if a printer presents another certificate than on first contact
+var CertificateChangedError = errors.New("printer certificate changed")
+
+// TOFUStore trusts the certificate of a printer on first use. the certificate of the first contact is saved in a
+// directory, like the ssl directory of cups, and later connections fail if the printer presents a certificate with
+// another public key. a renewed certificate with the same key replaces the saved one
+type TOFUStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewTOFUStore returns a store which saves the certificates in dir, the directory is created if it doesn't exist
+func NewTOFUStore(dir string) (*TOFUStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	return &TOFUStore{dir: dir}, nil
+}
+
+// Verify checks the certificate of the printer at host against the saved one, the certificate is saved if the
+// printer is contacted for the first time
+func (s *TOFUStore) Verify(host string, cert *x509.Certificate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved, err := s.certificate(host)
+	if errors.Is(err, os.ErrNotExist) {
+		return s.save(host, cert)
+	}
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(saved.Raw, cert.Raw) {
+		return nil
+	}
+	if !bytes.Equal(saved.RawSubjectPublicKeyInfo, cert.RawSubjectPublicKeyInfo) {
+		return fmt.Errorf("%w: %s presented %s instead of %s, remove %s if the change is expected", CertificateChangedError,
+			host, CertificateFingerprint(cert), CertificateFingerprint(saved), s.path(host))
+	}
+
+	return s.save(host, cert)
+}
+
+// Certificate returns the saved certificate of the printer at host, the error matches os.ErrNotExist if the printer
+// wasn't contacted yet
+func (s *TOFUStore) Certificate(host string) (*x509.Certificate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.certificate(host)
+}
+
+// Forget removes the saved certificate of the printer at host, the next certificate of the printer is trusted again
+func (s *TOFUStore) Forget(host string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := os.Remove(s.path(host))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *TOFUStore) certificate(host string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(s.path(host))
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("%s contains no certificate", s.path(host))
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func (s *TOFUStore) save(host string, cert *x509.Certificate) error {
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+
+	// the certificate is replaced atomically, a concurrent reader never sees a partial file
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path(host))
+}
+
+// path returns the file of a host, characters which are not allowed in file names like the colons of ipv6
+// addresses are replaced
+func (s *TOFUStore) path(host string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(host))
+
+	return filepath.Join(s.dir, name+".crt")
+}
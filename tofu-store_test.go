@@ -0,0 +1,115 @@
+package ipp
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testCertificate creates a self-signed certificate for 127.0.0.1 with the key
+func testCertificate(t *testing.T, key crypto.Signer, serial int64) tls.Certificate {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "printer"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	assert.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}
+}
+
+func testKey(t *testing.T) crypto.Signer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	return key
+}
+
+func TestTOFUStore_Verify(t *testing.T) {
+	store, err := NewTOFUStore(t.TempDir())
+	assert.Nil(t, err)
+
+	key := testKey(t)
+	first := testCertificate(t, key, 1).Leaf
+	renewed := testCertificate(t, key, 2).Leaf
+	other := testCertificate(t, testKey(t), 3).Leaf
+
+	_, err = store.Certificate("printer.local")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	assert.Nil(t, store.Verify("printer.local", first))
+	assert.Nil(t, store.Verify("printer.local", first))
+	saved, err := store.Certificate("printer.local")
+	assert.Nil(t, err)
+	assert.Equal(t, first.Raw, saved.Raw)
+
+	// other printers have their own certificates
+	assert.Nil(t, store.Verify("fe80::1%eth0", other))
+
+	err = store.Verify("printer.local", other)
+	assert.ErrorIs(t, err, CertificateChangedError)
+	assert.Contains(t, err.Error(), CertificateFingerprint(other))
+
+	// a renewed certificate with the same key replaces the saved one
+	assert.Nil(t, store.Verify("PRINTER.local", renewed))
+	saved, _ = store.Certificate("printer.local")
+	assert.Equal(t, renewed.Raw, saved.Raw)
+
+	assert.Nil(t, store.Forget("printer.local"))
+	assert.Nil(t, store.Forget("printer.local"))
+	assert.Nil(t, store.Verify("printer.local", other))
+}
+
+func TestHttpAdapter_TOFU(t *testing.T) {
+	store, err := NewTOFUStore(t.TempDir())
+	assert.Nil(t, err)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, err := NewRequestDecoder(r.Body).Decode(nil)
+		assert.Nil(t, err)
+
+		payload, _ := NewResponse(StatusOk, req.RequestId).Encode()
+		w.Header().Set("Content-Type", ContentTypeIPP)
+		w.Write(payload)
+	})
+
+	send := func(cert tls.Certificate) error {
+		ts := httptest.NewUnstartedServer(handler)
+		ts.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+		ts.StartTLS()
+		defer ts.Close()
+
+		host, port, _ := strings.Cut(strings.TrimPrefix(ts.URL, "https://"), ":")
+		portNumber, _ := strconv.Atoi(port)
+		adapter := NewHttpAdapter(host, portNumber, "", "", true)
+		assert.Nil(t, adapter.SetTLSOptions(TLSOptions{TOFU: store}))
+
+		req := NewRequest(OperationGetPrinterAttributes, 1, WithPrinterURI("ipps://printer/ipp/print"))
+		_, err := adapter.SendRequest(adapter.GetHttpUri("ipp", "print"), req, nil)
+		return err
+	}
+
+	cert := testCertificate(t, testKey(t), 1)
+	assert.Nil(t, send(cert))
+	assert.Nil(t, send(cert))
+	assert.ErrorIs(t, send(testCertificate(t, testKey(t), 2)), CertificateChangedError)
+}
@@ -0,0 +1,64 @@
+package ipp
+
+import "fmt"
+
+// DecodeProfile selects how the decoders handle deviations from rfc 8011
+type DecodeProfile int
+
+const (
+	// ProfileLenient tolerates the deviations of real printers and reports them as Warning, it is the default
+	ProfileLenient DecodeProfile = iota
+	// ProfileStrict fails with a ConformanceError on the first deviation, e.g. to test servers or validate messages
+	ProfileStrict
+)
+
+// valueLimits are the maximum lengths of the string values in octets as defined by rfc 8011
+var valueLimits = map[int8]int{
+	TagText:       1023,
+	TagName:       255,
+	TagKeyword:    255,
+	TagUri:        1023,
+	TagUriScheme:  63,
+	TagCharset:    63,
+	TagLanguage:   63,
+	TagMimeType:   255,
+	TagMemberName: 255,
+	TagString:     1023,
+}
+
+// Warning is a deviation from the ipp specification which the decoders tolerate, e.g. a wrong value tag, a wrong
+// value length or a duplicate attribute. warnings are passed to the OnWarning callbacks of the decoders, so interop
+// issues of printers can be monitored without failing the request
+type Warning struct {
+	// Attribute is the name of the affected attribute, it is empty for deviations of the message itself
+	Attribute string
+	Message   string
+}
+
+func (w Warning) String() string {
+	if w.Attribute == "" {
+		return w.Message
+	}
+
+	return fmt.Sprintf("%s: %s", w.Attribute, w.Message)
+}
+
+// tagClass groups the value tags which printers exchange in practice, e.g. a name sent as keyword or the values of a
+// rangeOfInteger attribute sent as integers
+func tagClass(tag int8) int8 {
+	switch tag {
+	case TagText, TagName, TagTextLang, TagNameLang, TagKeyword, TagUri, TagUriScheme, TagCharset, TagLanguage,
+		TagMimeType, TagString, TagReservedString:
+		return TagText
+	case TagRange:
+		return TagInteger
+	}
+
+	return tag
+}
+
+// isOutOfBand reports whether the tag is an out-of-band value like unknown or no-value, it may replace the value of
+// any attribute
+func isOutOfBand(tag int8) bool {
+	return tag >= TagUnsupportedValue && tag < TagInteger
+}
@@ -0,0 +1,162 @@
+package ipp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testAttribute encodes an attribute with a raw value
+func testAttribute(tag int8, name string, value []byte) []byte {
+	b := []byte{byte(tag)}
+	b = binary.BigEndian.AppendUint16(b, uint16(len(name)))
+	b = append(b, name...)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(value)))
+	return append(b, value...)
+}
+
+func TestResponseDecoder_OnWarning(t *testing.T) {
+	data := []byte{2, 0, 0, 0, 0, 0, 0, 1, byte(TagOperation)}
+	data = append(data, testAttribute(TagCharset, AttributeCharset, []byte("utf-8"))...)
+	data = append(data, testAttribute(TagLanguage, AttributeNaturalLanguage, []byte("en"))...)
+	data = append(data, byte(TagPrinter))
+	// an enum sent as 2 byte integer
+	data = append(data, testAttribute(TagInteger, AttributePrinterState, []byte{0, 3})...)
+	data = append(data, testAttribute(TagName, AttributePrinterName, []byte("office"))...)
+	data = append(data, testAttribute(TagName, AttributePrinterName, []byte("lab"))...)
+	data = append(data, testAttribute(TagBoolean, AttributePrinterIsAcceptingJobs, []byte{0, 1})...)
+	data = append(data, testAttribute(TagInteger, AttributeQueuedJobCount, []byte{0xff})...)
+	// out-of-band values are not reported
+	data = append(data, testAttribute(TagNoValue, AttributePrinterLocation, nil)...)
+	// the end-of-attributes tag is missing
+
+	var warnings []string
+	decoder := NewResponseDecoder(bytes.NewReader(data))
+	decoder.OnWarning = func(w Warning) {
+		warnings = append(warnings, w.String())
+	}
+	resp, err := decoder.Decode(nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{
+		"printer-state: value tag 0x21 instead of 0x23",
+		"printer-state: integer value with length 2",
+		"printer-name: duplicate attribute, the values are merged",
+		"printer-is-accepting-jobs: boolean value with length 2",
+		"queued-job-count: integer value with length 1",
+		"message without end-of-attributes tag",
+	}, warnings)
+
+	printer := resp.PrinterAttributes[0]
+	assert.Equal(t, 3, printer[AttributePrinterState][0].Value)
+	assert.Len(t, printer[AttributePrinterName], 2)
+	assert.Equal(t, true, printer[AttributePrinterIsAcceptingJobs][0].Value)
+	assert.Equal(t, -1, printer[AttributeQueuedJobCount][0].Value)
+
+	// the same message decodes without a warning function
+	_, err = NewResponseDecoder(bytes.NewReader(data)).Decode(nil)
+	assert.Nil(t, err)
+
+	data = []byte{2, 0, 0, 0, 0, 0, 0, 1, byte(TagOperation)}
+	data = append(data, testAttribute(TagInteger, AttributeJobID, []byte{0, 0, 0, 0, 1})...)
+	_, err = NewResponseDecoder(bytes.NewReader(data)).Decode(nil)
+	assert.NotNil(t, err)
+}
+
+func TestRequestDecoder_OnWarning(t *testing.T) {
+	data := []byte{2, 0, 0, 0x0b, 0, 0, 0, 1, byte(TagOperation)}
+	data = append(data, testAttribute(TagCharset, AttributeCharset, []byte("utf-8"))...)
+	data = append(data, testAttribute(TagLanguage, AttributeNaturalLanguage, []byte("en"))...)
+	data = append(data, testAttribute(TagUri, AttributePrinterURI, []byte("ipp://printer/ipp/print"))...)
+	data = append(data, testAttribute(TagUri, AttributePrinterURI, []byte("ipp://printer/ipp/other"))...)
+	data = append(data, testAttribute(TagResolution, AttributePrinterResolution, make([]byte, 10))...)
+	data = append(data, byte(TagEnd))
+
+	var warnings []Warning
+	decoder := NewRequestDecoder(bytes.NewReader(data))
+	decoder.OnWarning = func(w Warning) {
+		warnings = append(warnings, w)
+	}
+	req, err := decoder.Decode(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "ipp://printer/ipp/other", req.OperationAttributes[AttributePrinterURI])
+	assert.Equal(t, []Warning{
+		{Attribute: AttributePrinterURI, Message: "duplicate attribute, the last one is kept"},
+		{Attribute: AttributePrinterResolution, Message: "resolution value with length 10"},
+	}, warnings)
+}
+
+func TestDecodeProfile(t *testing.T) {
+	header := []byte{2, 0, 0, 0, 0, 0, 0, 1}
+	charset := testAttribute(TagCharset, AttributeCharset, []byte("utf-8"))
+	language := testAttribute(TagLanguage, AttributeNaturalLanguage, []byte("en"))
+
+	message := func(parts ...[]byte) []byte {
+		return append(bytes.Clone(header), bytes.Join(parts, nil)...)
+	}
+	operation := []byte{byte(TagOperation)}
+	printer := []byte{byte(TagPrinter)}
+	end := []byte{byte(TagEnd)}
+
+	testCases := []struct {
+		Name    string
+		Message []byte
+		Warning string
+	}{
+		{"conforming", message(operation, charset, language, printer,
+			testAttribute(TagEnum, AttributePrinterState, []byte{0, 0, 0, 3}), end), ""},
+		{"charset missing", message(operation, language, end),
+			"the first operation attribute is attributes-natural-language instead of attributes-charset"},
+		{"language missing", message(operation, charset, end),
+			"the operation attributes lack attributes-charset or attributes-natural-language"},
+		{"printer group first", message(printer, testAttribute(TagName, AttributePrinterName, []byte("office")),
+			operation, charset, language, end), "the first group is 0x04 instead of the operation attributes"},
+		{"value without name", message(operation, charset, language, printer,
+			testAttribute(TagKeyword, "", []byte("none")), end), "value without attribute name at the start of a group"},
+		{"keyword too long", message(operation, charset, language, printer,
+			testAttribute(TagKeyword, AttributePrinterStateReasons, bytes.Repeat([]byte("x"), 256)), end),
+			"printer-state-reasons: value of 256 octets exceeds the limit of 255"},
+		{"wrong tag", message(operation, charset, language, printer,
+			testAttribute(TagKeyword, AttributePrinterState, []byte("idle")), end),
+			"printer-state: value tag 0x44 instead of 0x23"},
+	}
+
+	for _, tc := range testCases {
+		var warnings []string
+		lenient := NewResponseDecoder(bytes.NewReader(tc.Message))
+		lenient.OnWarning = func(w Warning) {
+			warnings = append(warnings, w.String())
+		}
+		_, err := lenient.Decode(nil)
+		assert.Nil(t, err, tc.Name)
+
+		strict := NewResponseDecoder(bytes.NewReader(tc.Message))
+		strict.Profile = ProfileStrict
+		_, strictErr := strict.Decode(nil)
+
+		if tc.Warning == "" {
+			assert.Empty(t, warnings, tc.Name)
+			assert.Nil(t, strictErr, tc.Name)
+			continue
+		}
+		assert.Equal(t, tc.Warning, warnings[0], tc.Name)
+		assert.ErrorIs(t, strictErr, ConformanceError, tc.Name)
+		assert.Contains(t, strictErr.Error(), tc.Warning, tc.Name)
+	}
+
+	// requests encoded by the package conform
+	data, err := NewRequest(OperationPrintJob, 1, WithPrinterURI("ipp://printer/ipp/print"), WithUser("alice"),
+		WithJobAttributes(map[string]interface{}{AttributeCopies: 2, AttributeSides: "two-sided-long-edge"})).Encode()
+	assert.Nil(t, err)
+	decoder := NewRequestDecoder(bytes.NewReader(data))
+	decoder.Profile = ProfileStrict
+	_, err = decoder.Decode(nil)
+	assert.Nil(t, err)
+
+	decoder = NewRequestDecoder(bytes.NewReader(data[:len(data)-1]))
+	decoder.Profile = ProfileStrict
+	_, err = decoder.Decode(nil)
+	assert.ErrorIs(t, err, ConformanceError)
+}